@@ -13,11 +13,52 @@ const (
 	BearerAuthScopes = "bearerAuth.Scopes"
 )
 
+// Defines values for EventType.
+const (
+	EventTypeProductAdded            EventType = "product.added"
+	EventTypeProductConditionChanged EventType = "product.condition_changed"
+	EventTypeProductDeleted          EventType = "product.deleted"
+	EventTypeProductStatusChanged    EventType = "product.status_changed"
+	EventTypeReceptionClosed         EventType = "reception.closed"
+	EventTypeReceptionOpened         EventType = "reception.opened"
+)
+
+// Defines values for ManifestItemType.
+const (
+	ManifestItemTypeОбувь       ManifestItemType = "обувь"
+	ManifestItemTypeОдежда      ManifestItemType = "одежда"
+	ManifestItemTypeЭлектроника ManifestItemType = "электроника"
+)
+
 // Defines values for PVZCity.
 const (
-	Казань         PVZCity = "Казань"
-	Москва         PVZCity = "Москва"
-	СанктПетербург PVZCity = "Санкт-Петербург"
+	PVZCityКазань         PVZCity = "Казань"
+	PVZCityМосква         PVZCity = "Москва"
+	PVZCityСанктПетербург PVZCity = "Санкт-Петербург"
+)
+
+// Defines values for PVZStatus.
+const (
+	PVZStatusActive    PVZStatus = "active"
+	PVZStatusClosed    PVZStatus = "closed"
+	PVZStatusSuspended PVZStatus = "suspended"
+)
+
+// Defines values for PVZUpdateStatus.
+const (
+	PVZUpdateStatusActive    PVZUpdateStatus = "active"
+	PVZUpdateStatusClosed    PVZUpdateStatus = "closed"
+	PVZUpdateStatusSuspended PVZUpdateStatus = "suspended"
+)
+
+// Defines values for ProductStatus.
+const (
+	Accepted   ProductStatus = "accepted"
+	InTransit  ProductStatus = "in_transit"
+	Issued     ProductStatus = "issued"
+	Returned   ProductStatus = "returned"
+	Stored     ProductStatus = "stored"
+	WrittenOff ProductStatus = "written_off"
 )
 
 // Defines values for ProductType.
@@ -27,18 +68,60 @@ const (
 	ProductTypeЭлектроника ProductType = "электроника"
 )
 
+// Defines values for ProductCondition.
+const (
+	Damaged   ProductCondition = "damaged"
+	Ok        ProductCondition = "ok"
+	WrongItem ProductCondition = "wrong_item"
+)
+
 // Defines values for ReceptionStatus.
 const (
 	Close      ReceptionStatus = "close"
 	InProgress ReceptionStatus = "in_progress"
 )
 
+// Defines values for ScheduleDayWeekday.
+const (
+	Friday    ScheduleDayWeekday = "friday"
+	Monday    ScheduleDayWeekday = "monday"
+	Saturday  ScheduleDayWeekday = "saturday"
+	Sunday    ScheduleDayWeekday = "sunday"
+	Thursday  ScheduleDayWeekday = "thursday"
+	Tuesday   ScheduleDayWeekday = "tuesday"
+	Wednesday ScheduleDayWeekday = "wednesday"
+)
+
 // Defines values for UserRole.
 const (
 	UserRoleEmployee  UserRole = "employee"
 	UserRoleModerator UserRole = "moderator"
 )
 
+// Defines values for WebhookDeliveryStatus.
+const (
+	Dead      WebhookDeliveryStatus = "dead"
+	Delivered WebhookDeliveryStatus = "delivered"
+	Pending   WebhookDeliveryStatus = "pending"
+)
+
+// Defines values for WebhookSubscriptionCity.
+const (
+	WebhookSubscriptionCityКазань         WebhookSubscriptionCity = "Казань"
+	WebhookSubscriptionCityМосква         WebhookSubscriptionCity = "Москва"
+	WebhookSubscriptionCityСанктПетербург WebhookSubscriptionCity = "Санкт-Петербург"
+)
+
+// Defines values for WebhookSubscriptionEventTypes.
+const (
+	WebhookSubscriptionEventTypesProductAdded            WebhookSubscriptionEventTypes = "product.added"
+	WebhookSubscriptionEventTypesProductConditionChanged WebhookSubscriptionEventTypes = "product.condition_changed"
+	WebhookSubscriptionEventTypesProductDeleted          WebhookSubscriptionEventTypes = "product.deleted"
+	WebhookSubscriptionEventTypesProductStatusChanged    WebhookSubscriptionEventTypes = "product.status_changed"
+	WebhookSubscriptionEventTypesReceptionClosed         WebhookSubscriptionEventTypes = "reception.closed"
+	WebhookSubscriptionEventTypesReceptionOpened         WebhookSubscriptionEventTypes = "reception.opened"
+)
+
 // Defines values for PostDummyLoginJSONBodyRole.
 const (
 	PostDummyLoginJSONBodyRoleEmployee  PostDummyLoginJSONBodyRole = "employee"
@@ -47,9 +130,9 @@ const (
 
 // Defines values for PostProductsJSONBodyType.
 const (
-	PostProductsJSONBodyTypeОбувь       PostProductsJSONBodyType = "обувь"
-	PostProductsJSONBodyTypeОдежда      PostProductsJSONBodyType = "одежда"
-	PostProductsJSONBodyTypeЭлектроника PostProductsJSONBodyType = "электроника"
+	Обувь       PostProductsJSONBodyType = "обувь"
+	Одежда      PostProductsJSONBodyType = "одежда"
+	Электроника PostProductsJSONBodyType = "электроника"
 )
 
 // Defines values for PostRegisterJSONBodyRole.
@@ -58,46 +141,339 @@ const (
 	Moderator PostRegisterJSONBodyRole = "moderator"
 )
 
+// DiscrepancyReport Расхождения между принятыми товарами и манифестом поставки
+type DiscrepancyReport struct {
+	// Duplicates Штрихкоды, принятые больше одного раза
+	Duplicates []struct {
+		Barcode string `json:"barcode"`
+		Count   int    `json:"count"`
+	} `json:"duplicates"`
+
+	// Expected Количество товаров в манифесте
+	Expected int `json:"expected"`
+
+	// Missing Товары из манифеста, которые не были приняты
+	Missing []ManifestItem `json:"missing"`
+
+	// Received Количество принятых товаров
+	Received int `json:"received"`
+
+	// Unexpected Штрихкоды принятых товаров, которых нет в манифесте
+	Unexpected []string `json:"unexpected"`
+
+	// Unlabeled Количество товаров, принятых без штрихкода
+	Unlabeled int `json:"unlabeled"`
+}
+
 // Error defines model for Error.
 type Error struct {
 	Message string `json:"message" validate:"required"`
 }
 
+// Event defines model for Event.
+type Event struct {
+	CreatedAt time.Time          `json:"createdAt"`
+	Id        openapi_types.UUID `json:"id"`
+
+	// Payload Приемка или товар, к которым относится событие
+	Payload     map[string]interface{} `json:"payload"`
+	PvzId       openapi_types.UUID     `json:"pvzId"`
+	ReceptionId openapi_types.UUID     `json:"receptionId"`
+	Type        EventType              `json:"type"`
+}
+
+// EventType defines model for Event.Type.
+type EventType string
+
+// ForceCloseReceptionRequest defines model for ForceCloseReceptionRequest.
+type ForceCloseReceptionRequest struct {
+	Reason *string `json:"reason,omitempty" validate:"omitempty,max=255"`
+}
+
+// GeoPoint Координаты в WGS 84
+type GeoPoint struct {
+	Latitude  float64 `json:"latitude" validate:"min=-90,max=90"`
+	Longitude float64 `json:"longitude" validate:"min=-180,max=180"`
+}
+
+// Manifest defines model for Manifest.
+type Manifest struct {
+	CreatedAt    time.Time           `json:"createdAt"`
+	Id           openapi_types.UUID  `json:"id"`
+	Items        []ManifestItem      `json:"items"`
+	PvzId        openapi_types.UUID  `json:"pvzId"`
+	ReceptionId  *openapi_types.UUID `json:"receptionId,omitempty"`
+	ReconciledAt *time.Time          `json:"reconciledAt,omitempty"`
+
+	// Report Расхождения между принятыми товарами и манифестом поставки
+	Report *DiscrepancyReport `json:"report,omitempty"`
+
+	// Status pending - ожидает поставки, receiving - поставка принимается,
+	// reconciled - приемка закрыта и сверена с манифестом
+	Status   string `json:"status"`
+	Supplier string `json:"supplier"`
+}
+
+// ManifestCreate defines model for ManifestCreate.
+type ManifestCreate struct {
+	Items    []ManifestItem     `json:"items" validate:"required,min=1,max=10000,unique=Barcode,dive"`
+	PvzId    openapi_types.UUID `json:"pvzId" validate:"required"`
+	Supplier string             `json:"supplier" validate:"required,max=255"`
+}
+
+// ManifestItem defines model for ManifestItem.
+type ManifestItem struct {
+	Barcode string           `json:"barcode" validate:"required,max=64"`
+	Type    ManifestItemType `json:"type" validate:"required"`
+}
+
+// ManifestItemType defines model for ManifestItem.Type.
+type ManifestItemType string
+
+// NearbyPVZ defines model for NearbyPVZ.
+type NearbyPVZ struct {
+	// Distance Расстояние до ПВЗ в метрах
+	Distance float64 `json:"distance"`
+	Pvz      PVZ     `json:"pvz"`
+}
+
 // PVZ defines model for PVZ.
 type PVZ struct {
-	City             PVZCity             `json:"city" validate:"required"`
-	Id               *openapi_types.UUID `json:"id,omitempty" validate:"omitempty"`
-	RegistrationDate *time.Time          `json:"registrationDate,omitempty" validate:"omitempty"`
+	// Address Почтовый адрес ПВЗ
+	Address *string `json:"address,omitempty" validate:"omitempty,max=255"`
+
+	// Capacity Вместимость ПВЗ в товарах, 0 - не ограничена
+	Capacity *int    `json:"capacity,omitempty" validate:"omitempty,min=0"`
+	City     PVZCity `json:"city" validate:"required"`
+
+	// DeletedAt Время вывода ПВЗ из эксплуатации
+	DeletedAt *time.Time          `json:"deletedAt,omitempty" validate:"omitempty"`
+	Id        *openapi_types.UUID `json:"id,omitempty" validate:"omitempty"`
+
+	// Location Координаты в WGS 84
+	Location         *GeoPoint  `json:"location,omitempty"`
+	RegistrationDate *time.Time `json:"registrationDate,omitempty" validate:"omitempty"`
+
+	// Status Приемки можно начинать только в ПВЗ со статусом active
+	Status *PVZStatus `json:"status,omitempty" validate:"omitempty,oneof=active suspended closed"`
 }
 
 // PVZCity defines model for PVZ.City.
 type PVZCity string
 
+// PVZOccupancy defines model for PVZOccupancy.
+type PVZOccupancy struct {
+	// Capacity Отсутствует, если вместимость ПВЗ не ограничена
+	Capacity *int `json:"capacity,omitempty"`
+
+	// Count Количество товаров в ПВЗ
+	Count int                `json:"count"`
+	PvzId openapi_types.UUID `json:"pvzId"`
+	Types *[]TypeOccupancy   `json:"types,omitempty"`
+}
+
+// PVZSchedule defines model for PVZSchedule.
+type PVZSchedule struct {
+	Exceptions *[]ScheduleException `json:"exceptions,omitempty" validate:"omitempty,dive"`
+	PvzId      *openapi_types.UUID  `json:"pvzId,omitempty" validate:"omitempty"`
+
+	// ReceptionsAllowedUntil До этого момента приемки можно начинать и вне графика работы
+	ReceptionsAllowedUntil *time.Time `json:"receptionsAllowedUntil,omitempty" validate:"omitempty"`
+
+	// Timezone Часовой пояс IANA, например Europe/Moscow
+	Timezone  string        `json:"timezone" validate:"required"`
+	UpdatedAt *time.Time    `json:"updatedAt,omitempty" validate:"omitempty"`
+	Weekly    []ScheduleDay `json:"weekly" validate:"dive"`
+}
+
+// PVZStatus Приемки можно начинать только в ПВЗ со статусом active
+type PVZStatus string
+
+// PVZUpdate defines model for PVZUpdate.
+type PVZUpdate struct {
+	// Address Почтовый адрес ПВЗ
+	Address *string `json:"address,omitempty" validate:"omitempty,max=255"`
+
+	// Capacity Вместимость ПВЗ в товарах, 0 - не ограничена
+	Capacity *int `json:"capacity,omitempty" validate:"omitempty,min=0"`
+
+	// City Одно из значений PVZ.city
+	City *string `json:"city,omitempty" validate:"omitempty,oneof=Москва Санкт-Петербург Казань"`
+
+	// ClearLocation Удалить координаты ПВЗ; нельзя передавать вместе с location
+	ClearLocation *bool `json:"clearLocation,omitempty"`
+
+	// Location Координаты в WGS 84
+	Location *GeoPoint        `json:"location,omitempty"`
+	Status   *PVZUpdateStatus `json:"status,omitempty" validate:"omitempty,oneof=active suspended closed"`
+}
+
+// PVZUpdateStatus defines model for PVZUpdate.Status.
+type PVZUpdateStatus string
+
 // Product defines model for Product.
 type Product struct {
-	DateTime    *time.Time          `json:"dateTime,omitempty" validate:"omitempty"`
+	Barcode *string `json:"barcode,omitempty" validate:"omitempty,max=64"`
+
+	// Condition ok - без замечаний, damaged - поврежден, wrong_item - привезен не тот товар
+	Condition     *ProductCondition `json:"condition,omitempty"`
+	ConditionNote *string           `json:"conditionNote,omitempty"`
+	DateTime      *time.Time        `json:"dateTime,omitempty" validate:"omitempty"`
+
+	// HasPhoto К отчету о состоянии приложено фото, см. GET /products/{productId}/photo
+	HasPhoto    *bool               `json:"hasPhoto,omitempty"`
 	Id          *openapi_types.UUID `json:"id,omitempty" validate:"omitempty"`
 	ReceptionId openapi_types.UUID  `json:"receptionId" validate:"required,uuid"`
-	Type        ProductType         `json:"type" validate:"required"`
+
+	// Status accepted - принят в открытой приемке, stored - на хранении после закрытия приемки,
+	// issued - выдан покупателю, returned - возвращен покупателем, written_off - списан,
+	// in_transit - перемещается в другой ПВЗ
+	Status *ProductStatus `json:"status,omitempty" validate:"omitempty"`
+	Type   ProductType    `json:"type" validate:"required"`
 }
 
+// ProductStatus accepted - принят в открытой приемке, stored - на хранении после закрытия приемки,
+// issued - выдан покупателю, returned - возвращен покупателем, written_off - списан,
+// in_transit - перемещается в другой ПВЗ
+type ProductStatus string
+
 // ProductType defines model for Product.Type.
 type ProductType string
 
+// ProductCondition ok - без замечаний, damaged - поврежден, wrong_item - привезен не тот товар
+type ProductCondition string
+
+// ProductConditionRequest defines model for ProductConditionRequest.
+type ProductConditionRequest struct {
+	// Condition ok - без замечаний, damaged - поврежден, wrong_item - привезен не тот товар
+	Condition ProductCondition `json:"condition"`
+	Note      *string          `json:"note,omitempty" validate:"omitempty,max=1000"`
+}
+
+// ProductStatusChange defines model for ProductStatusChange.
+type ProductStatusChange struct {
+	ChangedAt time.Time `json:"changedAt"`
+	Comment   *string   `json:"comment,omitempty"`
+
+	// From Отсутствует у записи о приемке товара
+	From      *string            `json:"from,omitempty"`
+	ProductId openapi_types.UUID `json:"productId"`
+	PvzId     openapi_types.UUID `json:"pvzId"`
+
+	// ReceptionId Приемка, в которой находился товар при изменении статуса
+	ReceptionId openapi_types.UUID `json:"receptionId"`
+	To          string             `json:"to"`
+}
+
+// ProductStatusRequest defines model for ProductStatusRequest.
+type ProductStatusRequest struct {
+	Comment *string `json:"comment,omitempty" validate:"omitempty,max=255"`
+}
+
 // Reception defines model for Reception.
 type Reception struct {
-	DateTime time.Time           `json:"dateTime" validate:"required,datetime"`
-	Id       *openapi_types.UUID `json:"id,omitempty" validate:"omitempty"`
-	PvzId    openapi_types.UUID  `json:"pvzId" validate:"required,uuid"`
-	Status   ReceptionStatus     `json:"status" validate:"required,oneof=in_progress close"`
+	DateTime time.Time `json:"dateTime" validate:"required,datetime"`
+
+	// Discrepancies Расхождения между принятыми товарами и манифестом поставки
+	Discrepancies *DiscrepancyReport  `json:"discrepancies,omitempty"`
+	Id            *openapi_types.UUID `json:"id,omitempty" validate:"omitempty"`
+
+	// ManifestId Манифест поставки, с которым начата приемка
+	ManifestId *openapi_types.UUID `json:"manifestId,omitempty"`
+	PvzId      openapi_types.UUID  `json:"pvzId" validate:"required,uuid"`
+	Status     ReceptionStatus     `json:"status" validate:"required,oneof=in_progress close"`
 }
 
 // ReceptionStatus defines model for Reception.Status.
 type ReceptionStatus string
 
+// ReceptionAuditEntry defines model for ReceptionAuditEntry.
+type ReceptionAuditEntry struct {
+	// Action auto_closed - закрыта планировщиком, flagged_stale - помечена как зависшая, force_closed - закрыта модератором
+	Action string `json:"action"`
+
+	// Actor Email модератора или system для планировщика
+	Actor       string             `json:"actor"`
+	CreatedAt   time.Time          `json:"createdAt"`
+	PvzId       openapi_types.UUID `json:"pvzId"`
+	Reason      *string            `json:"reason,omitempty"`
+	ReceptionId openapi_types.UUID `json:"receptionId"`
+}
+
+// ScheduleDay Рабочие интервалы дня недели. Дни, которых нет в графике, выходные
+type ScheduleDay struct {
+	Intervals []ScheduleInterval `json:"intervals" validate:"dive"`
+	Weekday   ScheduleDayWeekday `json:"weekday" validate:"required,oneof=monday tuesday wednesday thursday friday saturday sunday"`
+}
+
+// ScheduleDayWeekday defines model for ScheduleDay.Weekday.
+type ScheduleDayWeekday string
+
+// ScheduleException Особый график на дату, например праздник. Дата без интервалов - выходной
+type ScheduleException struct {
+	Date      openapi_types.Date  `json:"date" validate:"required"`
+	Intervals *[]ScheduleInterval `json:"intervals,omitempty" validate:"omitempty,dive"`
+	Note      *string             `json:"note,omitempty" validate:"omitempty,max=255"`
+}
+
+// ScheduleInterval Рабочий интервал в местном времени ПВЗ
+type ScheduleInterval struct {
+	Closes string `json:"closes" validate:"required"`
+	Opens  string `json:"opens" validate:"required"`
+}
+
 // Token defines model for Token.
 type Token = string
 
+// Transfer defines model for Transfer.
+type Transfer struct {
+	CreatedAt        time.Time            `json:"createdAt"`
+	DestinationPvzId openapi_types.UUID   `json:"destinationPvzId"`
+	DispatchedAt     *time.Time           `json:"dispatchedAt,omitempty"`
+	Id               openapi_types.UUID   `json:"id"`
+	ProductIds       []openapi_types.UUID `json:"productIds"`
+	ReceivedAt       *time.Time           `json:"receivedAt,omitempty"`
+
+	// ReceptionId Приемка ПВЗ-получателя, в которой приняты товары
+	ReceptionId *openapi_types.UUID `json:"receptionId,omitempty"`
+	SourcePvzId openapi_types.UUID  `json:"sourcePvzId"`
+
+	// Status outbound - собирается в ПВЗ-отправителе, in_transit - отправлено,
+	// received - принято в ПВЗ-получателе, cancelled - отменено
+	Status string `json:"status"`
+}
+
+// TransferCreate defines model for TransferCreate.
+type TransferCreate struct {
+	DestinationPvzId openapi_types.UUID `json:"destinationPvzId" validate:"required"`
+
+	// ProductIds Товары на хранении в ПВЗ-отправителе
+	ProductIds  []openapi_types.UUID `json:"productIds" validate:"required,min=1,max=1000,unique"`
+	SourcePvzId openapi_types.UUID   `json:"sourcePvzId" validate:"required"`
+}
+
+// TypeCapacities defines model for TypeCapacities.
+type TypeCapacities struct {
+	// Capacities Типы, которых нет в списке, ограничены только общей вместимостью ПВЗ
+	Capacities []TypeCapacity `json:"capacities" validate:"unique=Type,dive"`
+}
+
+// TypeCapacity Ограничение количества товаров одного типа в ПВЗ
+type TypeCapacity struct {
+	Capacity int `json:"capacity" validate:"required,min=1"`
+
+	// Type Тип товара (электроника, одежда, обувь)
+	Type string `json:"type" validate:"required,oneof=электроника одежда обувь"`
+}
+
+// TypeOccupancy defines model for TypeOccupancy.
+type TypeOccupancy struct {
+	// Capacity Отсутствует, если тип не ограничен отдельно
+	Capacity *int   `json:"capacity,omitempty"`
+	Count    int    `json:"count"`
+	Type     string `json:"type"`
+}
+
 // User defines model for User.
 type User struct {
 	Email openapi_types.Email `json:"email" validate:"required,email"`
@@ -108,6 +484,47 @@ type User struct {
 // UserRole defines model for User.Role.
 type UserRole string
 
+// WebhookDelivery defines model for WebhookDelivery.
+type WebhookDelivery struct {
+	Attempts       int                   `json:"attempts"`
+	CreatedAt      time.Time             `json:"createdAt"`
+	EventId        openapi_types.UUID    `json:"eventId"`
+	EventType      string                `json:"eventType"`
+	Id             openapi_types.UUID    `json:"id"`
+	LastError      *string               `json:"lastError,omitempty"`
+	LastStatusCode *int                  `json:"lastStatusCode,omitempty"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	SubscriptionId openapi_types.UUID    `json:"subscriptionId"`
+}
+
+// WebhookDeliveryStatus defines model for WebhookDelivery.Status.
+type WebhookDeliveryStatus string
+
+// WebhookSubscription defines model for WebhookSubscription.
+type WebhookSubscription struct {
+	City       *WebhookSubscriptionCity         `json:"city,omitempty" validate:"omitempty"`
+	CreatedAt  *time.Time                       `json:"createdAt,omitempty" validate:"omitempty"`
+	EventTypes *[]WebhookSubscriptionEventTypes `json:"eventTypes,omitempty" validate:"omitempty,dive,oneof=reception.opened reception.closed product.added product.deleted product.status_changed product.condition_changed"`
+	Id         *openapi_types.UUID              `json:"id,omitempty" validate:"omitempty"`
+	PvzId      *openapi_types.UUID              `json:"pvzId,omitempty" validate:"omitempty"`
+
+	// Secret Ключ подписи HMAC-SHA256. Возвращается только при создании
+	Secret *string `json:"secret,omitempty" validate:"omitempty,min=16"`
+	Url    string  `json:"url" validate:"required,url"`
+}
+
+// WebhookSubscriptionCity defines model for WebhookSubscription.City.
+type WebhookSubscriptionCity string
+
+// WebhookSubscriptionEventTypes defines model for WebhookSubscription.EventTypes.
+type WebhookSubscriptionEventTypes string
+
+// IdempotencyKey defines model for IdempotencyKey.
+type IdempotencyKey = string
+
+// IfMatch defines model for IfMatch.
+type IfMatch = string
+
 // PostDummyLoginJSONBody defines parameters for PostDummyLogin.
 type PostDummyLoginJSONBody struct {
 	Role PostDummyLoginJSONBodyRole `json:"role" validate:"required,oneof=employee moderator"`
@@ -122,15 +539,71 @@ type PostLoginJSONBody struct {
 	Password string              `json:"password" validate:"required"`
 }
 
+// PostManifestsParams defines parameters for PostManifests.
+type PostManifestsParams struct {
+	// IdempotencyKey Ключ идемпотентности. Повторный запрос с тем же ключом и телом возвращает сохраненный ответ
+	// с заголовком `Idempotent-Replayed: true`. Тот же ключ с другим телом - 422, пока исходный
+	// запрос выполняется - 409. Ответы 5xx не сохраняются. Если исходный запрос не завершился
+	// за `lease` из секции `idempotency`, повтор с тем же телом выполняется заново. Ключи запросов
+	// без авторизации привязаны к адресу клиента.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
 // PostProductsJSONBody defines parameters for PostProducts.
 type PostProductsJSONBody struct {
-	PvzId openapi_types.UUID       `json:"pvzId" validate:"required,uuid"`
-	Type  PostProductsJSONBodyType `json:"type" validate:"required"`
+	// Barcode Штрихкод товара для сверки с манифестом поставки
+	Barcode *string                  `json:"barcode,omitempty" validate:"omitempty,max=64"`
+	PvzId   openapi_types.UUID       `json:"pvzId" validate:"required,uuid"`
+	Type    PostProductsJSONBodyType `json:"type" validate:"required"`
+}
+
+// PostProductsParams defines parameters for PostProducts.
+type PostProductsParams struct {
+	// IdempotencyKey Ключ идемпотентности. Повторный запрос с тем же ключом и телом возвращает сохраненный ответ
+	// с заголовком `Idempotent-Replayed: true`. Тот же ключ с другим телом - 422, пока исходный
+	// запрос выполняется - 409. Ответы 5xx не сохраняются. Если исходный запрос не завершился
+	// за `lease` из секции `idempotency`, повтор с тем же телом выполняется заново. Ключи запросов
+	// без авторизации привязаны к адресу клиента.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+
+	// IfMatch ETag приемки или ПВЗ, полученный из предыдущего ответа. Если ресурс с тех пор изменился,
+	// запрос отклоняется с 412. Без заголовка или со значением `*` проверка не выполняется.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
 }
 
 // PostProductsJSONBodyType defines parameters for PostProducts.
 type PostProductsJSONBodyType string
 
+// PostProductsProductIdIssueParams defines parameters for PostProductsProductIdIssue.
+type PostProductsProductIdIssueParams struct {
+	// IdempotencyKey Ключ идемпотентности. Повторный запрос с тем же ключом и телом возвращает сохраненный ответ
+	// с заголовком `Idempotent-Replayed: true`. Тот же ключ с другим телом - 422, пока исходный
+	// запрос выполняется - 409. Ответы 5xx не сохраняются. Если исходный запрос не завершился
+	// за `lease` из секции `idempotency`, повтор с тем же телом выполняется заново. Ключи запросов
+	// без авторизации привязаны к адресу клиента.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PostProductsProductIdReturnParams defines parameters for PostProductsProductIdReturn.
+type PostProductsProductIdReturnParams struct {
+	// IdempotencyKey Ключ идемпотентности. Повторный запрос с тем же ключом и телом возвращает сохраненный ответ
+	// с заголовком `Idempotent-Replayed: true`. Тот же ключ с другим телом - 422, пока исходный
+	// запрос выполняется - 409. Ответы 5xx не сохраняются. Если исходный запрос не завершился
+	// за `lease` из секции `idempotency`, повтор с тем же телом выполняется заново. Ключи запросов
+	// без авторизации привязаны к адресу клиента.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PostProductsProductIdWriteOffParams defines parameters for PostProductsProductIdWriteOff.
+type PostProductsProductIdWriteOffParams struct {
+	// IdempotencyKey Ключ идемпотентности. Повторный запрос с тем же ключом и телом возвращает сохраненный ответ
+	// с заголовком `Idempotent-Replayed: true`. Тот же ключ с другим телом - 422, пока исходный
+	// запрос выполняется - 409. Ответы 5xx не сохраняются. Если исходный запрос не завершился
+	// за `lease` из секции `idempotency`, повтор с тем же телом выполняется заново. Ключи запросов
+	// без авторизации привязаны к адресу клиента.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
 // GetPvzParams defines parameters for GetPvz.
 type GetPvzParams struct {
 	// StartDate Начальная дата диапазона
@@ -146,9 +619,130 @@ type GetPvzParams struct {
 	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
 }
 
+// PostPvzParams defines parameters for PostPvz.
+type PostPvzParams struct {
+	// IdempotencyKey Ключ идемпотентности. Повторный запрос с тем же ключом и телом возвращает сохраненный ответ
+	// с заголовком `Idempotent-Replayed: true`. Тот же ключ с другим телом - 422, пока исходный
+	// запрос выполняется - 409. Ответы 5xx не сохраняются. Если исходный запрос не завершился
+	// за `lease` из секции `idempotency`, повтор с тем же телом выполняется заново. Ключи запросов
+	// без авторизации привязаны к адресу клиента.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// GetPvzNearbyParams defines parameters for GetPvzNearby.
+type GetPvzNearbyParams struct {
+	// Lat Широта точки
+	Lat float64 `form:"lat" json:"lat"`
+
+	// Lon Долгота точки
+	Lon float64 `form:"lon" json:"lon"`
+
+	// Radius Радиус поиска в метрах
+	Radius float64 `form:"radius" json:"radius"`
+
+	// Limit Максимальное количество ПВЗ в ответе
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// DeletePvzPvzIdParams defines parameters for DeletePvzPvzId.
+type DeletePvzPvzIdParams struct {
+	// IfMatch ETag приемки или ПВЗ, полученный из предыдущего ответа. Если ресурс с тех пор изменился,
+	// запрос отклоняется с 412. Без заголовка или со значением `*` проверка не выполняется.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// PatchPvzPvzIdParams defines parameters for PatchPvzPvzId.
+type PatchPvzPvzIdParams struct {
+	// IfMatch ETag приемки или ПВЗ, полученный из предыдущего ответа. Если ресурс с тех пор изменился,
+	// запрос отклоняется с 412. Без заголовка или со значением `*` проверка не выполняется.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// PostPvzPvzIdAllowReceptionJSONBody defines parameters for PostPvzPvzIdAllowReception.
+type PostPvzPvzIdAllowReceptionJSONBody struct {
+	Until time.Time `json:"until" validate:"required"`
+}
+
+// GetPvzPvzIdArchiveParams defines parameters for GetPvzPvzIdArchive.
+type GetPvzPvzIdArchiveParams struct {
+	// StartDate Начальная дата диапазона
+	StartDate *time.Time `form:"startDate,omitempty" json:"startDate,omitempty"`
+
+	// EndDate Конечная дата диапазона
+	EndDate *time.Time `form:"endDate,omitempty" json:"endDate,omitempty"`
+
+	// Page Номер страницы
+	Page *int `form:"page,omitempty" json:"page,omitempty"`
+
+	// Limit Количество элементов на странице
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// PostPvzPvzIdCloseLastReceptionParams defines parameters for PostPvzPvzIdCloseLastReception.
+type PostPvzPvzIdCloseLastReceptionParams struct {
+	// IdempotencyKey Ключ идемпотентности. Повторный запрос с тем же ключом и телом возвращает сохраненный ответ
+	// с заголовком `Idempotent-Replayed: true`. Тот же ключ с другим телом - 422, пока исходный
+	// запрос выполняется - 409. Ответы 5xx не сохраняются. Если исходный запрос не завершился
+	// за `lease` из секции `idempotency`, повтор с тем же телом выполняется заново. Ключи запросов
+	// без авторизации привязаны к адресу клиента.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+
+	// IfMatch ETag приемки или ПВЗ, полученный из предыдущего ответа. Если ресурс с тех пор изменился,
+	// запрос отклоняется с 412. Без заголовка или со значением `*` проверка не выполняется.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// PostPvzPvzIdDeleteLastProductParams defines parameters for PostPvzPvzIdDeleteLastProduct.
+type PostPvzPvzIdDeleteLastProductParams struct {
+	// IdempotencyKey Ключ идемпотентности. Повторный запрос с тем же ключом и телом возвращает сохраненный ответ
+	// с заголовком `Idempotent-Replayed: true`. Тот же ключ с другим телом - 422, пока исходный
+	// запрос выполняется - 409. Ответы 5xx не сохраняются. Если исходный запрос не завершился
+	// за `lease` из секции `idempotency`, повтор с тем же телом выполняется заново. Ключи запросов
+	// без авторизации привязаны к адресу клиента.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+
+	// IfMatch ETag приемки или ПВЗ, полученный из предыдущего ответа. Если ресурс с тех пор изменился,
+	// запрос отклоняется с 412. Без заголовка или со значением `*` проверка не выполняется.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// PostPvzPvzIdForceCloseReceptionParams defines parameters for PostPvzPvzIdForceCloseReception.
+type PostPvzPvzIdForceCloseReceptionParams struct {
+	// IdempotencyKey Ключ идемпотентности. Повторный запрос с тем же ключом и телом возвращает сохраненный ответ
+	// с заголовком `Idempotent-Replayed: true`. Тот же ключ с другим телом - 422, пока исходный
+	// запрос выполняется - 409. Ответы 5xx не сохраняются. Если исходный запрос не завершился
+	// за `lease` из секции `idempotency`, повтор с тем же телом выполняется заново. Ключи запросов
+	// без авторизации привязаны к адресу клиента.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// GetPvzPvzIdManifestsParams defines parameters for GetPvzPvzIdManifests.
+type GetPvzPvzIdManifestsParams struct {
+	// Status Фильтр по статусу - pending, receiving или reconciled
+	Status *string `form:"status,omitempty" json:"status,omitempty"`
+}
+
+// GetPvzPvzIdTransfersParams defines parameters for GetPvzPvzIdTransfers.
+type GetPvzPvzIdTransfersParams struct {
+	// Status Фильтр по статусу - outbound, in_transit, received или cancelled
+	Status *string `form:"status,omitempty" json:"status,omitempty"`
+}
+
 // PostReceptionsJSONBody defines parameters for PostReceptions.
 type PostReceptionsJSONBody struct {
-	PvzId openapi_types.UUID `json:"pvzId" validate:"required,uuid"`
+	// ManifestId Ожидающий манифест поставки этого ПВЗ для сверки при закрытии приемки
+	ManifestId *openapi_types.UUID `json:"manifestId,omitempty"`
+	PvzId      openapi_types.UUID  `json:"pvzId" validate:"required,uuid"`
+}
+
+// PostReceptionsParams defines parameters for PostReceptions.
+type PostReceptionsParams struct {
+	// IdempotencyKey Ключ идемпотентности. Повторный запрос с тем же ключом и телом возвращает сохраненный ответ
+	// с заголовком `Idempotent-Replayed: true`. Тот же ключ с другим телом - 422, пока исходный
+	// запрос выполняется - 409. Ответы 5xx не сохраняются. Если исходный запрос не завершился
+	// за `lease` из секции `idempotency`, повтор с тем же телом выполняется заново. Ключи запросов
+	// без авторизации привязаны к адресу клиента.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
 }
 
 // PostRegisterJSONBody defines parameters for PostRegister.
@@ -158,23 +752,129 @@ type PostRegisterJSONBody struct {
 	Role     PostRegisterJSONBodyRole `json:"role" validate:"required,oneof=employee moderator"`
 }
 
+// PostRegisterParams defines parameters for PostRegister.
+type PostRegisterParams struct {
+	// IdempotencyKey Ключ идемпотентности. Повторный запрос с тем же ключом и телом возвращает сохраненный ответ
+	// с заголовком `Idempotent-Replayed: true`. Тот же ключ с другим телом - 422, пока исходный
+	// запрос выполняется - 409. Ответы 5xx не сохраняются. Если исходный запрос не завершился
+	// за `lease` из секции `idempotency`, повтор с тем же телом выполняется заново. Ключи запросов
+	// без авторизации привязаны к адресу клиента.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
 // PostRegisterJSONBodyRole defines parameters for PostRegister.
 type PostRegisterJSONBodyRole string
 
+// PostTransfersParams defines parameters for PostTransfers.
+type PostTransfersParams struct {
+	// IdempotencyKey Ключ идемпотентности. Повторный запрос с тем же ключом и телом возвращает сохраненный ответ
+	// с заголовком `Idempotent-Replayed: true`. Тот же ключ с другим телом - 422, пока исходный
+	// запрос выполняется - 409. Ответы 5xx не сохраняются. Если исходный запрос не завершился
+	// за `lease` из секции `idempotency`, повтор с тем же телом выполняется заново. Ключи запросов
+	// без авторизации привязаны к адресу клиента.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PostTransfersTransferIdCancelParams defines parameters for PostTransfersTransferIdCancel.
+type PostTransfersTransferIdCancelParams struct {
+	// IdempotencyKey Ключ идемпотентности. Повторный запрос с тем же ключом и телом возвращает сохраненный ответ
+	// с заголовком `Idempotent-Replayed: true`. Тот же ключ с другим телом - 422, пока исходный
+	// запрос выполняется - 409. Ответы 5xx не сохраняются. Если исходный запрос не завершился
+	// за `lease` из секции `idempotency`, повтор с тем же телом выполняется заново. Ключи запросов
+	// без авторизации привязаны к адресу клиента.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PostTransfersTransferIdDispatchParams defines parameters for PostTransfersTransferIdDispatch.
+type PostTransfersTransferIdDispatchParams struct {
+	// IdempotencyKey Ключ идемпотентности. Повторный запрос с тем же ключом и телом возвращает сохраненный ответ
+	// с заголовком `Idempotent-Replayed: true`. Тот же ключ с другим телом - 422, пока исходный
+	// запрос выполняется - 409. Ответы 5xx не сохраняются. Если исходный запрос не завершился
+	// за `lease` из секции `idempotency`, повтор с тем же телом выполняется заново. Ключи запросов
+	// без авторизации привязаны к адресу клиента.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PostTransfersTransferIdReceiveParams defines parameters for PostTransfersTransferIdReceive.
+type PostTransfersTransferIdReceiveParams struct {
+	// IdempotencyKey Ключ идемпотентности. Повторный запрос с тем же ключом и телом возвращает сохраненный ответ
+	// с заголовком `Idempotent-Replayed: true`. Тот же ключ с другим телом - 422, пока исходный
+	// запрос выполняется - 409. Ответы 5xx не сохраняются. Если исходный запрос не завершился
+	// за `lease` из секции `idempotency`, повтор с тем же телом выполняется заново. Ключи запросов
+	// без авторизации привязаны к адресу клиента.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PostWebhooksParams defines parameters for PostWebhooks.
+type PostWebhooksParams struct {
+	// IdempotencyKey Ключ идемпотентности. Повторный запрос с тем же ключом и телом возвращает сохраненный ответ
+	// с заголовком `Idempotent-Replayed: true`. Тот же ключ с другим телом - 422, пока исходный
+	// запрос выполняется - 409. Ответы 5xx не сохраняются. Если исходный запрос не завершился
+	// за `lease` из секции `idempotency`, повтор с тем же телом выполняется заново. Ключи запросов
+	// без авторизации привязаны к адресу клиента.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PostWebhooksDeliveriesDeliveryIdRedeliverParams defines parameters for PostWebhooksDeliveriesDeliveryIdRedeliver.
+type PostWebhooksDeliveriesDeliveryIdRedeliverParams struct {
+	// IdempotencyKey Ключ идемпотентности. Повторный запрос с тем же ключом и телом возвращает сохраненный ответ
+	// с заголовком `Idempotent-Replayed: true`. Тот же ключ с другим телом - 422, пока исходный
+	// запрос выполняется - 409. Ответы 5xx не сохраняются. Если исходный запрос не завершился
+	// за `lease` из секции `idempotency`, повтор с тем же телом выполняется заново. Ключи запросов
+	// без авторизации привязаны к адресу клиента.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
 // PostDummyLoginJSONRequestBody defines body for PostDummyLogin for application/json ContentType.
 type PostDummyLoginJSONRequestBody PostDummyLoginJSONBody
 
 // PostLoginJSONRequestBody defines body for PostLogin for application/json ContentType.
 type PostLoginJSONRequestBody PostLoginJSONBody
 
+// PostManifestsJSONRequestBody defines body for PostManifests for application/json ContentType.
+type PostManifestsJSONRequestBody = ManifestCreate
+
 // PostProductsJSONRequestBody defines body for PostProducts for application/json ContentType.
 type PostProductsJSONRequestBody PostProductsJSONBody
 
+// PutProductsProductIdConditionJSONRequestBody defines body for PutProductsProductIdCondition for application/json ContentType.
+type PutProductsProductIdConditionJSONRequestBody = ProductConditionRequest
+
+// PostProductsProductIdIssueJSONRequestBody defines body for PostProductsProductIdIssue for application/json ContentType.
+type PostProductsProductIdIssueJSONRequestBody = ProductStatusRequest
+
+// PostProductsProductIdReturnJSONRequestBody defines body for PostProductsProductIdReturn for application/json ContentType.
+type PostProductsProductIdReturnJSONRequestBody = ProductStatusRequest
+
+// PostProductsProductIdWriteOffJSONRequestBody defines body for PostProductsProductIdWriteOff for application/json ContentType.
+type PostProductsProductIdWriteOffJSONRequestBody = ProductStatusRequest
+
 // PostPvzJSONRequestBody defines body for PostPvz for application/json ContentType.
 type PostPvzJSONRequestBody = PVZ
 
+// PatchPvzPvzIdJSONRequestBody defines body for PatchPvzPvzId for application/json ContentType.
+type PatchPvzPvzIdJSONRequestBody = PVZUpdate
+
+// PostPvzPvzIdAllowReceptionJSONRequestBody defines body for PostPvzPvzIdAllowReception for application/json ContentType.
+type PostPvzPvzIdAllowReceptionJSONRequestBody PostPvzPvzIdAllowReceptionJSONBody
+
+// PutPvzPvzIdCapacitiesJSONRequestBody defines body for PutPvzPvzIdCapacities for application/json ContentType.
+type PutPvzPvzIdCapacitiesJSONRequestBody = TypeCapacities
+
+// PostPvzPvzIdForceCloseReceptionJSONRequestBody defines body for PostPvzPvzIdForceCloseReception for application/json ContentType.
+type PostPvzPvzIdForceCloseReceptionJSONRequestBody = ForceCloseReceptionRequest
+
+// PutPvzPvzIdScheduleJSONRequestBody defines body for PutPvzPvzIdSchedule for application/json ContentType.
+type PutPvzPvzIdScheduleJSONRequestBody = PVZSchedule
+
 // PostReceptionsJSONRequestBody defines body for PostReceptions for application/json ContentType.
 type PostReceptionsJSONRequestBody PostReceptionsJSONBody
 
 // PostRegisterJSONRequestBody defines body for PostRegister for application/json ContentType.
 type PostRegisterJSONRequestBody PostRegisterJSONBody
+
+// PostTransfersJSONRequestBody defines body for PostTransfers for application/json ContentType.
+type PostTransfersJSONRequestBody = TransferCreate
+
+// PostWebhooksJSONRequestBody defines body for PostWebhooks for application/json ContentType.
+type PostWebhooksJSONRequestBody = WebhookSubscription
@@ -68,18 +68,75 @@ func (ReceptionStatus) EnumDescriptor() ([]byte, []int) {
 	return file_pvz_proto_rawDescGZIP(), []int{0}
 }
 
+type Location struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Latitude      float64                `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude     float64                `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Location) Reset() {
+	*x = Location{}
+	mi := &file_pvz_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Location) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Location) ProtoMessage() {}
+
+func (x *Location) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Location) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *Location) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
 type PVZ struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
 	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	RegistrationDate *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=registration_date,json=registrationDate,proto3" json:"registration_date,omitempty"`
 	City             string                 `protobuf:"bytes,3,opt,name=city,proto3" json:"city,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	Address          string                 `protobuf:"bytes,4,opt,name=address,proto3" json:"address,omitempty"`
+	// Unset when the PVZ has no coordinates.
+	Location *Location `protobuf:"bytes,5,opt,name=location,proto3" json:"location,omitempty"`
+	// Zero means the capacity is not limited.
+	Capacity      int32 `protobuf:"varint,7,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PVZ) Reset() {
 	*x = PVZ{}
-	mi := &file_pvz_proto_msgTypes[0]
+	mi := &file_pvz_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -91,7 +148,7 @@ func (x *PVZ) String() string {
 func (*PVZ) ProtoMessage() {}
 
 func (x *PVZ) ProtoReflect() protoreflect.Message {
-	mi := &file_pvz_proto_msgTypes[0]
+	mi := &file_pvz_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -104,7 +161,7 @@ func (x *PVZ) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PVZ.ProtoReflect.Descriptor instead.
 func (*PVZ) Descriptor() ([]byte, []int) {
-	return file_pvz_proto_rawDescGZIP(), []int{0}
+	return file_pvz_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *PVZ) GetId() string {
@@ -128,6 +185,275 @@ func (x *PVZ) GetCity() string {
 	return ""
 }
 
+func (x *PVZ) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *PVZ) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *PVZ) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+type Reception struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DateTime      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=date_time,json=dateTime,proto3" json:"date_time,omitempty"`
+	PvzId         string                 `protobuf:"bytes,3,opt,name=pvz_id,json=pvzId,proto3" json:"pvz_id,omitempty"`
+	Status        ReceptionStatus        `protobuf:"varint,4,opt,name=status,proto3,enum=pvz.v1.ReceptionStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reception) Reset() {
+	*x = Reception{}
+	mi := &file_pvz_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reception) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reception) ProtoMessage() {}
+
+func (x *Reception) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reception.ProtoReflect.Descriptor instead.
+func (*Reception) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Reception) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Reception) GetDateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DateTime
+	}
+	return nil
+}
+
+func (x *Reception) GetPvzId() string {
+	if x != nil {
+		return x.PvzId
+	}
+	return ""
+}
+
+func (x *Reception) GetStatus() ReceptionStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ReceptionStatus_RECEPTION_STATUS_IN_PROGRESS
+}
+
+type Product struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DateTime      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=date_time,json=dateTime,proto3" json:"date_time,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	ReceptionId   string                 `protobuf:"bytes,4,opt,name=reception_id,json=receptionId,proto3" json:"reception_id,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Product) Reset() {
+	*x = Product{}
+	mi := &file_pvz_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Product) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Product) ProtoMessage() {}
+
+func (x *Product) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Product.ProtoReflect.Descriptor instead.
+func (*Product) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Product) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Product) GetDateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DateTime
+	}
+	return nil
+}
+
+func (x *Product) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Product) GetReceptionId() string {
+	if x != nil {
+		return x.ReceptionId
+	}
+	return ""
+}
+
+func (x *Product) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type ReceptionInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reception     *Reception             `protobuf:"bytes,1,opt,name=reception,proto3" json:"reception,omitempty"`
+	Products      []*Product             `protobuf:"bytes,2,rep,name=products,proto3" json:"products,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReceptionInfo) Reset() {
+	*x = ReceptionInfo{}
+	mi := &file_pvz_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReceptionInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReceptionInfo) ProtoMessage() {}
+
+func (x *ReceptionInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReceptionInfo.ProtoReflect.Descriptor instead.
+func (*ReceptionInfo) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ReceptionInfo) GetReception() *Reception {
+	if x != nil {
+		return x.Reception
+	}
+	return nil
+}
+
+func (x *ReceptionInfo) GetProducts() []*Product {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+type PVZInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pvz           *PVZ                   `protobuf:"bytes,1,opt,name=pvz,proto3" json:"pvz,omitempty"`
+	Receptions    []*ReceptionInfo       `protobuf:"bytes,2,rep,name=receptions,proto3" json:"receptions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PVZInfo) Reset() {
+	*x = PVZInfo{}
+	mi := &file_pvz_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PVZInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PVZInfo) ProtoMessage() {}
+
+func (x *PVZInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PVZInfo.ProtoReflect.Descriptor instead.
+func (*PVZInfo) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PVZInfo) GetPvz() *PVZ {
+	if x != nil {
+		return x.Pvz
+	}
+	return nil
+}
+
+func (x *PVZInfo) GetReceptions() []*ReceptionInfo {
+	if x != nil {
+		return x.Receptions
+	}
+	return nil
+}
+
 type GetPVZListRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -136,7 +462,7 @@ type GetPVZListRequest struct {
 
 func (x *GetPVZListRequest) Reset() {
 	*x = GetPVZListRequest{}
-	mi := &file_pvz_proto_msgTypes[1]
+	mi := &file_pvz_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -148,7 +474,7 @@ func (x *GetPVZListRequest) String() string {
 func (*GetPVZListRequest) ProtoMessage() {}
 
 func (x *GetPVZListRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pvz_proto_msgTypes[1]
+	mi := &file_pvz_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -161,7 +487,7 @@ func (x *GetPVZListRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPVZListRequest.ProtoReflect.Descriptor instead.
 func (*GetPVZListRequest) Descriptor() ([]byte, []int) {
-	return file_pvz_proto_rawDescGZIP(), []int{1}
+	return file_pvz_proto_rawDescGZIP(), []int{6}
 }
 
 type GetPVZListResponse struct {
@@ -173,7 +499,7 @@ type GetPVZListResponse struct {
 
 func (x *GetPVZListResponse) Reset() {
 	*x = GetPVZListResponse{}
-	mi := &file_pvz_proto_msgTypes[2]
+	mi := &file_pvz_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -185,7 +511,7 @@ func (x *GetPVZListResponse) String() string {
 func (*GetPVZListResponse) ProtoMessage() {}
 
 func (x *GetPVZListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pvz_proto_msgTypes[2]
+	mi := &file_pvz_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -198,7 +524,7 @@ func (x *GetPVZListResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPVZListResponse.ProtoReflect.Descriptor instead.
 func (*GetPVZListResponse) Descriptor() ([]byte, []int) {
-	return file_pvz_proto_rawDescGZIP(), []int{2}
+	return file_pvz_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *GetPVZListResponse) GetPvzs() []*PVZ {
@@ -208,25 +534,137 @@ func (x *GetPVZListResponse) GetPvzs() []*PVZ {
 	return nil
 }
 
+type ListPVZsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only PVZs located in one of these cities. Empty means any city.
+	Cities []string `protobuf:"bytes,1,rep,name=cities,proto3" json:"cities,omitempty"`
+	// Only PVZs with receptions in [start_date, end_date]. Unset bounds are open.
+	StartDate         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate           *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	IncludeReceptions bool                   `protobuf:"varint,4,opt,name=include_receptions,json=includeReceptions,proto3" json:"include_receptions,omitempty"`
+	// Ignored unless include_receptions is set.
+	IncludeProducts bool `protobuf:"varint,5,opt,name=include_products,json=includeProducts,proto3" json:"include_products,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListPVZsRequest) Reset() {
+	*x = ListPVZsRequest{}
+	mi := &file_pvz_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPVZsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPVZsRequest) ProtoMessage() {}
+
+func (x *ListPVZsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPVZsRequest.ProtoReflect.Descriptor instead.
+func (*ListPVZsRequest) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListPVZsRequest) GetCities() []string {
+	if x != nil {
+		return x.Cities
+	}
+	return nil
+}
+
+func (x *ListPVZsRequest) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *ListPVZsRequest) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *ListPVZsRequest) GetIncludeReceptions() bool {
+	if x != nil {
+		return x.IncludeReceptions
+	}
+	return false
+}
+
+func (x *ListPVZsRequest) GetIncludeProducts() bool {
+	if x != nil {
+		return x.IncludeProducts
+	}
+	return false
+}
+
 var File_pvz_proto protoreflect.FileDescriptor
 
 const file_pvz_proto_rawDesc = "" +
 	"\n" +
-	"\tpvz.proto\x12\x06pvz.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"r\n" +
+	"\tpvz.proto\x12\x06pvz.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"D\n" +
+	"\bLocation\x12\x1a\n" +
+	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\"\xeb\x01\n" +
 	"\x03PVZ\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12G\n" +
 	"\x11registration_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x10registrationDate\x12\x12\n" +
-	"\x04city\x18\x03 \x01(\tR\x04city\"\x13\n" +
+	"\x04city\x18\x03 \x01(\tR\x04city\x12\x18\n" +
+	"\aaddress\x18\x04 \x01(\tR\aaddress\x12,\n" +
+	"\blocation\x18\x05 \x01(\v2\x10.pvz.v1.LocationR\blocation\x12\x1a\n" +
+	"\bcapacity\x18\a \x01(\x05R\bcapacityJ\x04\b\x06\x10\aR\ropening_hours\"\x9c\x01\n" +
+	"\tReception\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x127\n" +
+	"\tdate_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\bdateTime\x12\x15\n" +
+	"\x06pvz_id\x18\x03 \x01(\tR\x05pvzId\x12/\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x17.pvz.v1.ReceptionStatusR\x06status\"\xa1\x01\n" +
+	"\aProduct\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x127\n" +
+	"\tdate_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\bdateTime\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12!\n" +
+	"\freception_id\x18\x04 \x01(\tR\vreceptionId\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\"m\n" +
+	"\rReceptionInfo\x12/\n" +
+	"\treception\x18\x01 \x01(\v2\x11.pvz.v1.ReceptionR\treception\x12+\n" +
+	"\bproducts\x18\x02 \x03(\v2\x0f.pvz.v1.ProductR\bproducts\"_\n" +
+	"\aPVZInfo\x12\x1d\n" +
+	"\x03pvz\x18\x01 \x01(\v2\v.pvz.v1.PVZR\x03pvz\x125\n" +
+	"\n" +
+	"receptions\x18\x02 \x03(\v2\x15.pvz.v1.ReceptionInfoR\n" +
+	"receptions\"\x13\n" +
 	"\x11GetPVZListRequest\"5\n" +
 	"\x12GetPVZListResponse\x12\x1f\n" +
-	"\x04pvzs\x18\x01 \x03(\v2\v.pvz.v1.PVZR\x04pvzs*P\n" +
+	"\x04pvzs\x18\x01 \x03(\v2\v.pvz.v1.PVZR\x04pvzs\"\xf5\x01\n" +
+	"\x0fListPVZsRequest\x12\x16\n" +
+	"\x06cities\x18\x01 \x03(\tR\x06cities\x129\n" +
+	"\n" +
+	"start_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
+	"\bend_date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x12-\n" +
+	"\x12include_receptions\x18\x04 \x01(\bR\x11includeReceptions\x12)\n" +
+	"\x10include_products\x18\x05 \x01(\bR\x0fincludeProducts*P\n" +
 	"\x0fReceptionStatus\x12 \n" +
 	"\x1cRECEPTION_STATUS_IN_PROGRESS\x10\x00\x12\x1b\n" +
-	"\x17RECEPTION_STATUS_CLOSED\x10\x012Q\n" +
+	"\x17RECEPTION_STATUS_CLOSED\x10\x012\x89\x01\n" +
 	"\n" +
 	"PVZService\x12C\n" +
 	"\n" +
-	"GetPVZList\x12\x19.pvz.v1.GetPVZListRequest\x1a\x1a.pvz.v1.GetPVZListResponseB3Z1github.com/R0st0k/PVZ_Service/api/proto_v1;pvz_v1b\x06proto3"
+	"GetPVZList\x12\x19.pvz.v1.GetPVZListRequest\x1a\x1a.pvz.v1.GetPVZListResponse\x126\n" +
+	"\bListPVZs\x12\x17.pvz.v1.ListPVZsRequest\x1a\x0f.pvz.v1.PVZInfo0\x01B3Z1github.com/R0st0k/PVZ_Service/api/proto_v1;pvz_v1b\x06proto3"
 
 var (
 	file_pvz_proto_rawDescOnce sync.Once
@@ -241,24 +679,42 @@ func file_pvz_proto_rawDescGZIP() []byte {
 }
 
 var file_pvz_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_pvz_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_pvz_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_pvz_proto_goTypes = []any{
 	(ReceptionStatus)(0),          // 0: pvz.v1.ReceptionStatus
-	(*PVZ)(nil),                   // 1: pvz.v1.PVZ
-	(*GetPVZListRequest)(nil),     // 2: pvz.v1.GetPVZListRequest
-	(*GetPVZListResponse)(nil),    // 3: pvz.v1.GetPVZListResponse
-	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
+	(*Location)(nil),              // 1: pvz.v1.Location
+	(*PVZ)(nil),                   // 2: pvz.v1.PVZ
+	(*Reception)(nil),             // 3: pvz.v1.Reception
+	(*Product)(nil),               // 4: pvz.v1.Product
+	(*ReceptionInfo)(nil),         // 5: pvz.v1.ReceptionInfo
+	(*PVZInfo)(nil),               // 6: pvz.v1.PVZInfo
+	(*GetPVZListRequest)(nil),     // 7: pvz.v1.GetPVZListRequest
+	(*GetPVZListResponse)(nil),    // 8: pvz.v1.GetPVZListResponse
+	(*ListPVZsRequest)(nil),       // 9: pvz.v1.ListPVZsRequest
+	(*timestamppb.Timestamp)(nil), // 10: google.protobuf.Timestamp
 }
 var file_pvz_proto_depIdxs = []int32{
-	4, // 0: pvz.v1.PVZ.registration_date:type_name -> google.protobuf.Timestamp
-	1, // 1: pvz.v1.GetPVZListResponse.pvzs:type_name -> pvz.v1.PVZ
-	2, // 2: pvz.v1.PVZService.GetPVZList:input_type -> pvz.v1.GetPVZListRequest
-	3, // 3: pvz.v1.PVZService.GetPVZList:output_type -> pvz.v1.GetPVZListResponse
-	3, // [3:4] is the sub-list for method output_type
-	2, // [2:3] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	10, // 0: pvz.v1.PVZ.registration_date:type_name -> google.protobuf.Timestamp
+	1,  // 1: pvz.v1.PVZ.location:type_name -> pvz.v1.Location
+	10, // 2: pvz.v1.Reception.date_time:type_name -> google.protobuf.Timestamp
+	0,  // 3: pvz.v1.Reception.status:type_name -> pvz.v1.ReceptionStatus
+	10, // 4: pvz.v1.Product.date_time:type_name -> google.protobuf.Timestamp
+	3,  // 5: pvz.v1.ReceptionInfo.reception:type_name -> pvz.v1.Reception
+	4,  // 6: pvz.v1.ReceptionInfo.products:type_name -> pvz.v1.Product
+	2,  // 7: pvz.v1.PVZInfo.pvz:type_name -> pvz.v1.PVZ
+	5,  // 8: pvz.v1.PVZInfo.receptions:type_name -> pvz.v1.ReceptionInfo
+	2,  // 9: pvz.v1.GetPVZListResponse.pvzs:type_name -> pvz.v1.PVZ
+	10, // 10: pvz.v1.ListPVZsRequest.start_date:type_name -> google.protobuf.Timestamp
+	10, // 11: pvz.v1.ListPVZsRequest.end_date:type_name -> google.protobuf.Timestamp
+	7,  // 12: pvz.v1.PVZService.GetPVZList:input_type -> pvz.v1.GetPVZListRequest
+	9,  // 13: pvz.v1.PVZService.ListPVZs:input_type -> pvz.v1.ListPVZsRequest
+	8,  // 14: pvz.v1.PVZService.GetPVZList:output_type -> pvz.v1.GetPVZListResponse
+	6,  // 15: pvz.v1.PVZService.ListPVZs:output_type -> pvz.v1.PVZInfo
+	14, // [14:16] is the sub-list for method output_type
+	12, // [12:14] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_pvz_proto_init() }
@@ -272,7 +728,7 @@ func file_pvz_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pvz_proto_rawDesc), len(file_pvz_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   3,
+			NumMessages:   9,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
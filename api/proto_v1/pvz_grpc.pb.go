@@ -20,6 +20,7 @@ const _ = grpc.SupportPackageIsVersion9
 
 const (
 	PVZService_GetPVZList_FullMethodName = "/pvz.v1.PVZService/GetPVZList"
+	PVZService_ListPVZs_FullMethodName   = "/pvz.v1.PVZService/ListPVZs"
 )
 
 // PVZServiceClient is the client API for PVZService service.
@@ -27,6 +28,7 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type PVZServiceClient interface {
 	GetPVZList(ctx context.Context, in *GetPVZListRequest, opts ...grpc.CallOption) (*GetPVZListResponse, error)
+	ListPVZs(ctx context.Context, in *ListPVZsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PVZInfo], error)
 }
 
 type pVZServiceClient struct {
@@ -47,11 +49,31 @@ func (c *pVZServiceClient) GetPVZList(ctx context.Context, in *GetPVZListRequest
 	return out, nil
 }
 
+func (c *pVZServiceClient) ListPVZs(ctx context.Context, in *ListPVZsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PVZInfo], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PVZService_ServiceDesc.Streams[0], PVZService_ListPVZs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListPVZsRequest, PVZInfo]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PVZService_ListPVZsClient = grpc.ServerStreamingClient[PVZInfo]
+
 // PVZServiceServer is the server API for PVZService service.
 // All implementations must embed UnimplementedPVZServiceServer
 // for forward compatibility.
 type PVZServiceServer interface {
 	GetPVZList(context.Context, *GetPVZListRequest) (*GetPVZListResponse, error)
+	ListPVZs(*ListPVZsRequest, grpc.ServerStreamingServer[PVZInfo]) error
 	mustEmbedUnimplementedPVZServiceServer()
 }
 
@@ -65,6 +87,9 @@ type UnimplementedPVZServiceServer struct{}
 func (UnimplementedPVZServiceServer) GetPVZList(context.Context, *GetPVZListRequest) (*GetPVZListResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPVZList not implemented")
 }
+func (UnimplementedPVZServiceServer) ListPVZs(*ListPVZsRequest, grpc.ServerStreamingServer[PVZInfo]) error {
+	return status.Errorf(codes.Unimplemented, "method ListPVZs not implemented")
+}
 func (UnimplementedPVZServiceServer) mustEmbedUnimplementedPVZServiceServer() {}
 func (UnimplementedPVZServiceServer) testEmbeddedByValue()                    {}
 
@@ -104,6 +129,17 @@ func _PVZService_GetPVZList_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PVZService_ListPVZs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListPVZsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PVZServiceServer).ListPVZs(m, &grpc.GenericServerStream[ListPVZsRequest, PVZInfo]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PVZService_ListPVZsServer = grpc.ServerStreamingServer[PVZInfo]
+
 // PVZService_ServiceDesc is the grpc.ServiceDesc for PVZService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -116,6 +152,12 @@ var PVZService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _PVZService_GetPVZList_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListPVZs",
+			Handler:       _PVZService_ListPVZs_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "pvz.proto",
 }
@@ -2,16 +2,25 @@ package main
 
 import (
 	"log"
+	"log/slog"
 	"os"
 	"pvz-service/internal/app"
 	"pvz-service/internal/config"
+	"pvz-service/internal/consistency"
 	"pvz-service/internal/controller/http/router"
+	"pvz-service/internal/eventbus"
 	"pvz-service/internal/logger"
 	"pvz-service/internal/logger/sl"
 	"pvz-service/internal/metrics"
+	"pvz-service/internal/outbox"
 	"pvz-service/internal/repository"
 	"pvz-service/internal/service"
+	"pvz-service/internal/storage"
+	"pvz-service/internal/webhook"
 	"sync"
+
+	// PVZ schedules use IANA timezones and the runtime image has no tzdata
+	_ "time/tzdata"
 )
 
 func main() {
@@ -24,36 +33,152 @@ func main() {
 	// Init logger
 	log := logger.Setup()
 
+	// Run a subcommand instead of the service
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			if err := migrate(cfg, log, os.Stdout, os.Args[2:]); err != nil {
+				log.Error("migrate failed", sl.Err(err))
+				os.Exit(1)
+			}
+		case "seed":
+			if err := seedData(cfg, log, os.Stdout, os.Args[2:]); err != nil {
+				log.Error("seed failed", sl.Err(err))
+				os.Exit(1)
+			}
+		default:
+			log.Error("unknown command", slog.String("command", os.Args[1]))
+			os.Exit(2)
+		}
+		return
+	}
+
+	// Connect to the database shared by all repositories
+	db, err := repository.Open(cfg, log)
+	if err != nil {
+		log.Error("failed to open database", sl.Err(err))
+		os.Exit(1)
+	}
+	defer db.CloseConnection()
+
 	// Init AuthRepo and AuthService
-	authRepo, err := repository.CreateAuthRepo(cfg, log)
+	authRepo, err := repository.CreateAuthRepo(db)
 	if err != nil {
 		log.Error("failed to init auth repo", sl.Err(err))
 		os.Exit(1)
 	}
-	defer authRepo.CloseConnection()
 	authService := service.NewAuthService(authRepo, cfg, log)
 
 	// Init PVZRepo and PVZService
-	pvzRepo, err := repository.CreatePVZRepo(cfg, log)
+	pvzRepo, err := repository.CreatePVZRepo(db)
 	if err != nil {
 		log.Error("failed to init pvz repo", sl.Err(err))
 		os.Exit(1)
 	}
-	defer pvzRepo.CloseConnection()
-	pvzService := service.NewPVZService(pvzRepo, log)
+	photoStorage, err := storage.CreateStorage(cfg, log)
+	if err != nil {
+		log.Error("failed to init photo storage", sl.Err(err))
+		os.Exit(1)
+	}
+	eventBus := eventbus.NewBus()
+	pvzService := service.NewPVZService(pvzRepo, photoStorage, eventBus, log)
+	transferService := service.NewTransferService(pvzRepo, eventBus, log)
+	manifestService := service.NewManifestService(pvzRepo, log)
+	productConditionService := service.NewProductConditionService(pvzRepo, photoStorage, eventBus, log)
+	staleReceptionService := service.NewStaleReceptionService(pvzRepo, eventBus, log)
+	archiveService := service.NewArchiveService(pvzRepo, photoStorage, log)
+
+	// Init WebhookRepo and WebhookService
+	webhookRepo, err := repository.CreateWebhookRepo(db)
+	if err != nil {
+		log.Error("failed to init webhook repo", sl.Err(err))
+		os.Exit(1)
+	}
+	webhookService := service.NewWebhookService(webhookRepo, log)
+
+	// Init IdempotencyRepo and IdempotencyService
+	idempotencyRepo, err := repository.CreateIdempotencyRepo(db)
+	if err != nil {
+		log.Error("failed to init idempotency repo", sl.Err(err))
+		os.Exit(1)
+	}
+	idempotencyService := service.NewIdempotencyService(idempotencyRepo, cfg, log)
 
 	metrics := metrics.NewMetrics()
 
-	serversStopFuncs := make([]func(*sync.WaitGroup), 0, 3)
+	serversStopFuncs := make([]func(*sync.WaitGroup), 0, 7)
+
+	// Setup expired idempotency keys purger
+	serversStopFuncs = append(serversStopFuncs, app.StartIdempotencyPurger(cfg, log, idempotencyService))
+
+	// Setup outbox relay
+	if cfg.Outbox.IsAble {
+		log.Info("outbox relay is enabled")
+
+		outboxRepo, err := repository.CreateOutboxRepo(db)
+		if err != nil {
+			log.Error("failed to init outbox repo", sl.Err(err))
+			os.Exit(1)
+		}
+
+		sink, err := outbox.CreateSink(cfg, webhookRepo, log)
+		if err != nil {
+			log.Error("failed to init outbox sink", sl.Err(err))
+			os.Exit(1)
+		}
+
+		serversStopFuncs = append(serversStopFuncs, app.StartOutboxRelay(log, outbox.NewRelay(outboxRepo, sink, cfg, log)))
+	}
+
+	// Setup webhook dispatcher
+	if cfg.Webhooks.IsAble {
+		log.Info("webhook dispatcher is enabled")
+		serversStopFuncs = append(serversStopFuncs, app.StartWebhookDispatcher(log, webhook.NewDispatcher(webhookRepo, cfg, log)))
+	}
+
+	// Setup stale receptions closer
+	if cfg.StaleReceptions.IsAble {
+		log.Info("stale receptions closer is enabled")
+		serversStopFuncs = append(serversStopFuncs, app.StartStaleReceptionCloser(cfg, log, staleReceptionService, metrics))
+	}
+
+	// Setup reception archiver
+	if cfg.Retention.IsAble {
+		log.Info("reception archiver is enabled")
+		serversStopFuncs = append(serversStopFuncs, app.StartReceptionArchiver(cfg, log, archiveService, metrics))
+	}
+
+	// Setup partition maintainer, only Postgres partitions its tables
+	if partitioner, ok := db.(repository.Partitioner); ok && cfg.Partitions.IsAble {
+		log.Info("partition maintainer is enabled")
+		serversStopFuncs = append(serversStopFuncs, app.StartPartitionMaintainer(cfg, log, partitioner, metrics))
+	}
 
 	// Setup prometheus server
 	if cfg.Prometheus.IsAble {
 		log.Info("metrics server is enabled")
+		metrics.CollectOccupancy(pvzService.ListPVZOccupancy)
+		if pool, ok := db.(repository.PoolStats); ok {
+			metrics.CollectDBStats(pool.Stats)
+		}
 		serversStopFuncs = append(serversStopFuncs, app.StartMetricsServer(cfg, log, metrics))
 	}
 
+	// Users who have just written read from the primary while the replicas
+	// catch up
+	var tracker *consistency.Tracker
+	if len(cfg.Database.Replicas) > 0 {
+		log.Info("read replicas are enabled")
+		tracker = consistency.NewTracker(cfg.Database.ReadYourWritesWindow)
+	}
+
 	// Setup http server router
-	router := router.Setup(log, metrics, *authService, *pvzService)
+	router := router.Setup(
+		log, metrics,
+		*authService, *pvzService, *transferService, *manifestService, *productConditionService,
+		*staleReceptionService, *archiveService, *webhookService, *idempotencyService,
+		tracker,
+	)
 
 	// Start http server
 	serversStopFuncs = append(serversStopFuncs, app.StartHTTPServer(cfg, log, &router))
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"pvz-service/internal/config"
+	"pvz-service/internal/repository"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pressly/goose/v3"
+)
+
+const migrateUsage = "usage: pvz-service migrate up|down|status|redo|version"
+
+// migrate runs a migrate subcommand against the configured database and
+// prints its outcome to out:
+//
+//	up      applies all pending migrations
+//	down    rolls back the last applied migration
+//	redo    rolls back the last applied migration and applies it again
+//	status  lists the migrations and whether they are applied
+//	version prints the version of the schema
+func migrate(cfg *config.Config, log *slog.Logger, out io.Writer, args []string) error {
+	if len(args) != 1 {
+		return errors.New(migrateUsage)
+	}
+
+	var run func(ctx context.Context, provider *goose.Provider, out io.Writer) error
+	switch args[0] {
+	case "up":
+		run = migrateUp
+	case "down":
+		run = migrateDown
+	case "redo":
+		run = migrateRedo
+	case "status":
+		run = migrateStatus
+	case "version":
+		run = migrateVersion
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", args[0], migrateUsage)
+	}
+
+	provider, err := repository.NewMigrator(cfg, log)
+	if err != nil {
+		return err
+	}
+	defer provider.Close()
+
+	return run(context.Background(), provider, out)
+}
+
+func migrateUp(ctx context.Context, provider *goose.Provider, out io.Writer) error {
+	results, err := provider.Up(ctx)
+	for _, result := range results {
+		fmt.Fprintln(out, result)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(results) == 0 {
+		fmt.Fprintln(out, "no migrations to apply")
+	}
+	return nil
+}
+
+func migrateDown(ctx context.Context, provider *goose.Provider, out io.Writer) error {
+	result, err := provider.Down(ctx)
+	if errors.Is(err, goose.ErrNoNextVersion) {
+		fmt.Fprintln(out, "no migrations to roll back")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, result)
+	return nil
+}
+
+func migrateRedo(ctx context.Context, provider *goose.Provider, out io.Writer) error {
+	down, err := provider.Down(ctx)
+	if errors.Is(err, goose.ErrNoNextVersion) {
+		return errors.New("no migrations to redo")
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, down)
+
+	up, err := provider.UpByOne(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, up)
+	return nil
+}
+
+func migrateStatus(ctx context.Context, provider *goose.Provider, out io.Writer) error {
+	statuses, err := provider.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tSTATE\tAPPLIED AT\tMIGRATION")
+	for _, status := range statuses {
+		appliedAt := "-"
+		if status.State == goose.StateApplied {
+			appliedAt = status.AppliedAt.Format(time.DateTime)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Source.Version, status.State, appliedAt, status.Source.Path)
+	}
+	return w.Flush()
+}
+
+func migrateVersion(ctx context.Context, provider *goose.Provider, out io.Writer) error {
+	version, err := provider.GetDBVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, version)
+	return nil
+}
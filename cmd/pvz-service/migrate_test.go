@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"pvz-service/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{Database: config.Database{
+		Protocol: "sqlite",
+		Path:     filepath.Join(t.TempDir(), "pvz.db"),
+	}}
+
+	run := func(t *testing.T, command string) string {
+		t.Helper()
+
+		var out bytes.Buffer
+		require.NoError(t, migrate(cfg, log, &out, []string{command}))
+		return out.String()
+	}
+
+	assert.Equal(t, "0\n", run(t, "version"))
+	assert.Contains(t, run(t, "status"), "pending")
+
+	up := run(t, "up")
+	assert.Contains(t, up, "OK    up 00001_")
+	assert.Equal(t, "no migrations to apply\n", run(t, "up"))
+	assert.NotContains(t, run(t, "status"), "pending")
+
+	latest := run(t, "version")
+
+	down := run(t, "down")
+	assert.True(t, strings.HasPrefix(down, "OK    down"), down)
+	assert.NotEqual(t, latest, run(t, "version"))
+
+	redo := run(t, "redo")
+	assert.Equal(t, 2, strings.Count(redo, "\n"), redo)
+
+	assert.Contains(t, run(t, "up"), "OK    up")
+	assert.Equal(t, latest, run(t, "version"))
+
+	t.Run("UnknownCommand", func(t *testing.T) {
+		err := migrate(cfg, log, io.Discard, []string{"sideways"})
+		assert.ErrorContains(t, err, migrateUsage)
+	})
+
+	t.Run("MemoryHasNoMigrations", func(t *testing.T) {
+		memory := &config.Config{Database: config.Database{Protocol: "memory"}}
+		assert.Error(t, migrate(memory, log, io.Discard, []string{"up"}))
+	})
+}
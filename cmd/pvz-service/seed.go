@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"pvz-service/internal/config"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/seed"
+	"time"
+)
+
+// seedData runs the seed subcommand, which fills the configured database
+// with generated data and prints what it has written to out
+func seedData(cfg *config.Config, log *slog.Logger, out io.Writer, args []string) error {
+	opts := seed.Options{}
+	var end string
+
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	flags.SetOutput(out)
+	flags.Int64Var(&opts.Seed, "seed", 1, "value the generated data is derived from")
+	flags.IntVar(&opts.Cities, "cities", 3, "number of cities, up to the three allowed ones")
+	flags.IntVar(&opts.PVZs, "pvzs", 50, "number of PVZs")
+	flags.IntVar(&opts.Users, "users", 20, "number of users, every tenth is a moderator")
+	flags.IntVar(&opts.ReceptionsPerPVZ, "receptions", 100, "average number of receptions of a PVZ open for the whole history")
+	flags.IntVar(&opts.ProductsPerReception, "products", 20, "average number of products in a reception")
+	flags.IntVar(&opts.Months, "months", 12, "months of history")
+	flags.StringVar(&end, "end", "", "date the history ends at, YYYY-MM-DD, today by default")
+	flags.StringVar(&opts.Password, "password", "password", "password of the generated users")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	opts.End = time.Now().UTC().Truncate(24 * time.Hour)
+	if end != "" {
+		var err error
+		if opts.End, err = time.Parse(time.DateOnly, end); err != nil {
+			return fmt.Errorf("invalid end date: %w", err)
+		}
+	}
+
+	db, err := repository.Open(cfg, log)
+	if err != nil {
+		return err
+	}
+	defer db.CloseConnection()
+
+	pvzRepo, err := repository.CreatePVZRepo(db)
+	if err != nil {
+		return err
+	}
+	authRepo, err := repository.CreateAuthRepo(db)
+	if err != nil {
+		return err
+	}
+
+	summary, err := seed.New(pvzRepo, authRepo, log).Run(context.Background(), opts)
+	if summary != nil {
+		fmt.Fprintf(out, "cities: %d\npvzs: %d\nusers: %d\nreceptions: %d\nproducts: %d\nissued: %d\nreturned: %d\n",
+			summary.Cities, summary.PVZs, summary.Users, summary.Receptions, summary.Products, summary.Issued, summary.Returned)
+	}
+	return err
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"pvz-service/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeedData(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{Database: config.Database{
+		Protocol: "sqlite",
+		Path:     filepath.Join(t.TempDir(), "pvz.db"),
+	}}
+
+	var out bytes.Buffer
+	err := seedData(cfg, log, &out, []string{"-pvzs", "2", "-users", "1", "-receptions", "3", "-products", "2", "-end", "2025-06-01"})
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "pvzs: 2\nusers: 1\n")
+
+	t.Run("InvalidEnd", func(t *testing.T) {
+		assert.Error(t, seedData(cfg, log, io.Discard, []string{"-end", "June"}))
+	})
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"pvz-service/internal/config"
+	"pvz-service/internal/logger/sl"
+	"pvz-service/internal/service"
+	"sync"
+	"time"
+)
+
+func StartIdempotencyPurger(cfg *config.Config, log *slog.Logger, idempotencyService service.IdempotencyServiceInterface) func(*sync.WaitGroup) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	// Starting purger
+	go func() {
+		defer close(done)
+
+		log.Info("starting idempotency keys purger", slog.String("interval", cfg.Idempotency.PurgeInterval.String()))
+
+		ticker := time.NewTicker(cfg.Idempotency.PurgeInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				deleted, err := idempotencyService.PurgeExpired(ctx)
+				if err != nil {
+					log.Error("failed to purge idempotency keys", sl.Err(err))
+					continue
+				}
+				if deleted > 0 {
+					log.Info("expired idempotency keys purged", slog.Int("deleted", deleted))
+				}
+			}
+		}
+	}()
+
+	// Graceful Stop
+	return func(wg *sync.WaitGroup) {
+		defer wg.Done()
+
+		log.Info("Shutting down idempotency keys purger")
+		cancel()
+		<-done
+		log.Info("idempotency keys purger gracefully stopped")
+	}
+}
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"pvz-service/internal/outbox"
+	"sync"
+)
+
+func StartOutboxRelay(log *slog.Logger, relay *outbox.Relay) func(*sync.WaitGroup) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	// Starting relay
+	go func() {
+		defer close(done)
+
+		log.Info("starting outbox relay")
+		relay.Run(ctx)
+	}()
+
+	// Graceful Stop
+	return func(wg *sync.WaitGroup) {
+		defer wg.Done()
+
+		log.Info("Shutting down outbox relay")
+		cancel()
+		<-done
+		log.Info("outbox relay gracefully stopped")
+	}
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"pvz-service/internal/config"
+	"pvz-service/internal/logger/sl"
+	"pvz-service/internal/metrics"
+	"pvz-service/internal/repository"
+	"sync"
+	"time"
+)
+
+func StartPartitionMaintainer(cfg *config.Config, log *slog.Logger, partitioner repository.Partitioner, metrics *metrics.Metrics) func(*sync.WaitGroup) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	ensure := func() {
+		created, err := partitioner.EnsurePartitions(ctx, time.Now(), cfg.Partitions.AheadMonths)
+		metrics.PartitionsCreated.Add(float64(len(created)))
+		if len(created) > 0 {
+			log.Info("partitions created", slog.Any("partitions", created))
+		}
+
+		if err != nil {
+			metrics.PartitionRunFailures.Inc()
+			log.Error("failed to create partitions", sl.Err(err))
+			return
+		}
+		metrics.PartitionLastSuccess.SetToCurrentTime()
+	}
+
+	// Starting maintainer
+	go func() {
+		defer close(done)
+
+		log.Info("starting partition maintainer",
+			slog.Int("ahead_months", cfg.Partitions.AheadMonths),
+			slog.String("interval", cfg.Partitions.Interval.String()),
+		)
+
+		// The month ahead must exist before the first tick, which may be a
+		// day away
+		ensure()
+
+		ticker := time.NewTicker(cfg.Partitions.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				ensure()
+			}
+		}
+	}()
+
+	// Graceful Stop
+	return func(wg *sync.WaitGroup) {
+		defer wg.Done()
+
+		log.Info("Shutting down partition maintainer")
+		cancel()
+		<-done
+		log.Info("partition maintainer gracefully stopped")
+	}
+}
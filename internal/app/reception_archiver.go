@@ -0,0 +1,67 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"pvz-service/internal/config"
+	"pvz-service/internal/logger/sl"
+	"pvz-service/internal/metrics"
+	"pvz-service/internal/service"
+	"sync"
+	"time"
+)
+
+func StartReceptionArchiver(cfg *config.Config, log *slog.Logger, archiveService service.ArchiveServiceInterface, metrics *metrics.Metrics) func(*sync.WaitGroup) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	// Starting archiver
+	go func() {
+		defer close(done)
+
+		log.Info("starting reception archiver",
+			slog.Int("months", cfg.Retention.Months),
+			slog.String("interval", cfg.Retention.Interval.String()),
+			slog.Int("batch_size", cfg.Retention.BatchSize),
+		)
+
+		ticker := time.NewTicker(cfg.Retention.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				start := time.Now()
+				receptions, products, err := archiveService.ArchiveReceptions(ctx, cfg.Retention.Months, cfg.Retention.BatchSize)
+				metrics.ArchiveRunDuration.Observe(time.Since(start).Seconds())
+
+				// Batches committed before a failure stay archived
+				metrics.ReceptionsArchived.Add(float64(receptions))
+				metrics.ProductsArchived.Add(float64(products))
+
+				if err != nil {
+					metrics.ArchiveRunFailures.Inc()
+					log.Error("failed to archive receptions", sl.Err(err))
+					continue
+				}
+				metrics.ArchiveLastSuccess.SetToCurrentTime()
+
+				if receptions > 0 {
+					log.Info("receptions archived", slog.Int("receptions", receptions), slog.Int("products", products))
+				}
+			}
+		}
+	}()
+
+	// Graceful Stop
+	return func(wg *sync.WaitGroup) {
+		defer wg.Done()
+
+		log.Info("Shutting down reception archiver")
+		cancel()
+		<-done
+		log.Info("reception archiver gracefully stopped")
+	}
+}
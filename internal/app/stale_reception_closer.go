@@ -0,0 +1,65 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"pvz-service/internal/config"
+	"pvz-service/internal/logger/sl"
+	"pvz-service/internal/metrics"
+	"pvz-service/internal/service"
+	"sync"
+	"time"
+)
+
+func StartStaleReceptionCloser(cfg *config.Config, log *slog.Logger, staleReceptionService service.StaleReceptionServiceInterface, metrics *metrics.Metrics) func(*sync.WaitGroup) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	// Starting closer
+	go func() {
+		defer close(done)
+
+		log.Info("starting stale receptions closer",
+			slog.String("timeout", cfg.StaleReceptions.Timeout.String()),
+			slog.String("interval", cfg.StaleReceptions.Interval.String()),
+			slog.Bool("flag_only", cfg.StaleReceptions.FlagOnly),
+		)
+
+		ticker := time.NewTicker(cfg.StaleReceptions.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				handled, err := staleReceptionService.CloseStaleReceptions(ctx, cfg.StaleReceptions.Timeout, cfg.StaleReceptions.FlagOnly)
+				if err != nil {
+					log.Error("failed to close stale receptions", sl.Err(err))
+					continue
+				}
+				if handled == 0 {
+					continue
+				}
+
+				if cfg.StaleReceptions.FlagOnly {
+					metrics.StaleReceptionsFlagged.Add(float64(handled))
+					log.Info("stale receptions flagged", slog.Int("flagged", handled))
+				} else {
+					metrics.ReceptionsForceClosed.WithLabelValues("scheduler").Add(float64(handled))
+					log.Info("stale receptions closed", slog.Int("closed", handled))
+				}
+			}
+		}
+	}()
+
+	// Graceful Stop
+	return func(wg *sync.WaitGroup) {
+		defer wg.Done()
+
+		log.Info("Shutting down stale receptions closer")
+		cancel()
+		<-done
+		log.Info("stale receptions closer gracefully stopped")
+	}
+}
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"pvz-service/internal/webhook"
+	"sync"
+)
+
+func StartWebhookDispatcher(log *slog.Logger, dispatcher *webhook.Dispatcher) func(*sync.WaitGroup) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	// Starting dispatcher
+	go func() {
+		defer close(done)
+
+		log.Info("starting webhook dispatcher")
+		dispatcher.Run(ctx)
+	}()
+
+	// Graceful Stop
+	return func(wg *sync.WaitGroup) {
+		defer wg.Done()
+
+		log.Info("Shutting down webhook dispatcher")
+		cancel()
+		<-done
+		log.Info("webhook dispatcher gracefully stopped")
+	}
+}
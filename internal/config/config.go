@@ -10,11 +10,18 @@ import (
 )
 
 type Config struct {
-	HTTP       HTTP       `yaml:"http"`
-	GRPC       GRPC       `yaml:"grpc"`
-	Prometheus Prometheus `yaml:"prometheus"`
-	Database   Database   `yaml:"database"`
-	JWT        JWT        `yaml:"jwt"`
+	HTTP            HTTP            `yaml:"http"`
+	GRPC            GRPC            `yaml:"grpc"`
+	Prometheus      Prometheus      `yaml:"prometheus"`
+	Database        Database        `yaml:"database"`
+	JWT             JWT             `yaml:"jwt"`
+	Outbox          Outbox          `yaml:"outbox"`
+	Webhooks        Webhooks        `yaml:"webhooks"`
+	Idempotency     Idempotency     `yaml:"idempotency"`
+	Storage         Storage         `yaml:"storage"`
+	StaleReceptions StaleReceptions `yaml:"stale_receptions"`
+	Retention       Retention       `yaml:"retention"`
+	Partitions      Partitions      `yaml:"partitions"`
 }
 
 type HTTP struct {
@@ -46,6 +53,36 @@ type Database struct {
 	Password string `yaml:"password" env:"DB_PASSWORD" env-default:"postgres"`
 	Name     string `yaml:"name" env:"DB_NAME" env-default:"pvz"`
 	SSLMode  string `yaml:"sslmode" env:"DB_SSLMODE" env-default:"disable"`
+	// Path is the database file used by the sqlite protocol
+	Path string `yaml:"path" env:"DB_PATH" env-default:"pvz.db"`
+
+	// Connection pool of the postgres protocol
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS" env-default:"25"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" env-default:"10"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" env-default:"30m"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME" env-default:"5m"`
+	// StatementTimeout aborts queries running longer, zero disables it
+	StatementTimeout time.Duration `yaml:"statement_timeout" env:"DB_STATEMENT_TIMEOUT" env-default:"30s"`
+
+	// Startup waits for the server with exponential backoff between
+	// ConnectAttempts tries
+	ConnectAttempts       int           `yaml:"connect_attempts" env:"DB_CONNECT_ATTEMPTS" env-default:"5"`
+	ConnectInitialBackoff time.Duration `yaml:"connect_initial_backoff" env:"DB_CONNECT_INITIAL_BACKOFF" env-default:"1s"`
+	ConnectMaxBackoff     time.Duration `yaml:"connect_max_backoff" env:"DB_CONNECT_MAX_BACKOFF" env-default:"15s"`
+
+	// AutoMigrate applies the postgres migrations on startup. Without it the
+	// service refuses to start until they are applied by the migrate command.
+	AutoMigrate bool `yaml:"auto_migrate" env:"DB_AUTO_MIGRATE" env-default:"true"`
+
+	// Replicas are DSNs of read-only servers that serve the PVZ listing
+	// while they pass the health check run every ReplicaCheckInterval. A user
+	// who has changed something reads from the primary for
+	// ReadYourWritesWindow, which should exceed the replication lag. The
+	// writes are remembered per instance, so this holds across instances only
+	// when the balancer keeps a user on one of them.
+	Replicas             []string      `yaml:"replicas" env:"DB_REPLICAS" env-separator:";"`
+	ReplicaCheckInterval time.Duration `yaml:"replica_check_interval" env:"DB_REPLICA_CHECK_INTERVAL" env-default:"5s"`
+	ReadYourWritesWindow time.Duration `yaml:"read_your_writes_window" env:"DB_READ_YOUR_WRITES_WINDOW" env-default:"5s"`
 }
 
 type JWT struct {
@@ -53,6 +90,86 @@ type JWT struct {
 	ExpiresIn time.Duration `yaml:"expires_in" env:"JWT_EXPIRES_IN" env-default:"24h"`
 }
 
+type Outbox struct {
+	IsAble     bool          `yaml:"is_able" env:"OUTBOX_IS_ABLE" env-default:"false"`
+	Sink       string        `yaml:"sink" env:"OUTBOX_SINK" env-default:"webhook"`
+	WebhookURL string        `yaml:"webhook_url" env:"OUTBOX_WEBHOOK_URL"`
+	Timeout    time.Duration `yaml:"timeout" env:"OUTBOX_TIMEOUT" env-default:"5s"`
+	Interval   time.Duration `yaml:"interval" env:"OUTBOX_INTERVAL" env-default:"1s"`
+	BatchSize  int           `yaml:"batch_size" env:"OUTBOX_BATCH_SIZE" env-default:"100"`
+
+	MaxAttempts    int           `yaml:"max_attempts" env:"OUTBOX_MAX_ATTEMPTS" env-default:"10"`
+	InitialBackoff time.Duration `yaml:"initial_backoff" env:"OUTBOX_INITIAL_BACKOFF" env-default:"1s"`
+	MaxBackoff     time.Duration `yaml:"max_backoff" env:"OUTBOX_MAX_BACKOFF" env-default:"10m"`
+	// Published events are deleted once older than Retention
+	Retention     time.Duration `yaml:"retention" env:"OUTBOX_RETENTION" env-default:"168h"`
+	PurgeInterval time.Duration `yaml:"purge_interval" env:"OUTBOX_PURGE_INTERVAL" env-default:"1h"`
+}
+
+type Webhooks struct {
+	IsAble         bool          `yaml:"is_able" env:"WEBHOOKS_IS_ABLE" env-default:"false"`
+	Timeout        time.Duration `yaml:"timeout" env:"WEBHOOKS_TIMEOUT" env-default:"5s"`
+	Interval       time.Duration `yaml:"interval" env:"WEBHOOKS_INTERVAL" env-default:"1s"`
+	BatchSize      int           `yaml:"batch_size" env:"WEBHOOKS_BATCH_SIZE" env-default:"50"`
+	MaxAttempts    int           `yaml:"max_attempts" env:"WEBHOOKS_MAX_ATTEMPTS" env-default:"8"`
+	InitialBackoff time.Duration `yaml:"initial_backoff" env:"WEBHOOKS_INITIAL_BACKOFF" env-default:"5s"`
+	MaxBackoff     time.Duration `yaml:"max_backoff" env:"WEBHOOKS_MAX_BACKOFF" env-default:"1h"`
+}
+
+type Idempotency struct {
+	TTL           time.Duration `yaml:"ttl" env:"IDEMPOTENCY_TTL" env-default:"24h"`
+	PurgeInterval time.Duration `yaml:"purge_interval" env:"IDEMPOTENCY_PURGE_INTERVAL" env-default:"1h"`
+	// A request holding its key longer than Lease is presumed lost and may be
+	// retried. It must exceed the longest request.
+	Lease time.Duration `yaml:"lease" env:"IDEMPOTENCY_LEASE" env-default:"30s"`
+}
+
+// StaleReceptions configures the scheduler that closes receptions left in
+// progress without new products for longer than Timeout. With FlagOnly set
+// such receptions are only recorded in the audit for a moderator to close.
+type StaleReceptions struct {
+	IsAble   bool          `yaml:"is_able" env:"STALE_RECEPTIONS_IS_ABLE" env-default:"false"`
+	Timeout  time.Duration `yaml:"timeout" env:"STALE_RECEPTIONS_TIMEOUT" env-default:"12h"`
+	Interval time.Duration `yaml:"interval" env:"STALE_RECEPTIONS_INTERVAL" env-default:"5m"`
+	FlagOnly bool          `yaml:"flag_only" env:"STALE_RECEPTIONS_FLAG_ONLY" env-default:"false"`
+}
+
+// Retention configures the job that moves closed receptions started more
+// than Months ago to the archive tables every Interval, BatchSize receptions
+// per transaction
+type Retention struct {
+	IsAble    bool          `yaml:"is_able" env:"RETENTION_IS_ABLE" env-default:"false"`
+	Months    int           `yaml:"months" env:"RETENTION_MONTHS" env-default:"12"`
+	Interval  time.Duration `yaml:"interval" env:"RETENTION_INTERVAL" env-default:"24h"`
+	BatchSize int           `yaml:"batch_size" env:"RETENTION_BATCH_SIZE" env-default:"500"`
+}
+
+// Partitions configures the job that creates the monthly partitions of
+// receptions and products from the current month to AheadMonths ahead every
+// Interval. Only Postgres partitions its tables.
+type Partitions struct {
+	IsAble      bool          `yaml:"is_able" env:"PARTITIONS_IS_ABLE" env-default:"true"`
+	AheadMonths int           `yaml:"ahead_months" env:"PARTITIONS_AHEAD_MONTHS" env-default:"3"`
+	Interval    time.Duration `yaml:"interval" env:"PARTITIONS_INTERVAL" env-default:"24h"`
+}
+
+// Storage keeps product photos either in a local directory or in an
+// S3-compatible bucket
+type Storage struct {
+	Type     string `yaml:"type" env:"STORAGE_TYPE" env-default:"local"`
+	LocalDir string `yaml:"local_dir" env:"STORAGE_LOCAL_DIR" env-default:"./data/photos"`
+	S3       S3     `yaml:"s3"`
+}
+
+type S3 struct {
+	Endpoint  string        `yaml:"endpoint" env:"S3_ENDPOINT"`
+	Region    string        `yaml:"region" env:"S3_REGION" env-default:"us-east-1"`
+	Bucket    string        `yaml:"bucket" env:"S3_BUCKET"`
+	AccessKey string        `yaml:"access_key" env:"S3_ACCESS_KEY"`
+	SecretKey string        `yaml:"secret_key" env:"S3_SECRET_KEY"`
+	Timeout   time.Duration `yaml:"timeout" env:"S3_TIMEOUT" env-default:"10s"`
+}
+
 func Load() (*Config, error) {
 	configPath, ok := os.LookupEnv("CONFIG_PATH")
 	if !ok {
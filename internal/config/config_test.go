@@ -36,6 +36,10 @@ func TestConfigDefaults(t *testing.T) {
 	assert.Equal(t, "", cfg.Database.Password)
 	assert.Equal(t, "", cfg.Database.Name)
 	assert.Equal(t, "", cfg.Database.SSLMode)
+	assert.Equal(t, "", cfg.Database.Path)
+	assert.Equal(t, 0, cfg.Database.MaxOpenConns)
+	assert.Equal(t, time.Duration(0), cfg.Database.StatementTimeout)
+	assert.Equal(t, 0, cfg.Database.ConnectAttempts)
 
 	assert.Equal(t, "", cfg.JWT.SecretKey)
 	assert.Equal(t, time.Duration(0), cfg.JWT.ExpiresIn)
@@ -103,6 +107,10 @@ func TestLoad(t *testing.T) {
 				assert.Equal(t, "8080", cfg.HTTP.Port)
 				assert.Equal(t, 4*time.Second, cfg.HTTP.Timeout)
 				assert.Equal(t, 30*time.Second, cfg.HTTP.IdleTimeout)
+				assert.Equal(t, 25, cfg.Database.MaxOpenConns)
+				assert.Equal(t, 10, cfg.Database.MaxIdleConns)
+				assert.Equal(t, 30*time.Second, cfg.Database.StatementTimeout)
+				assert.Equal(t, 5, cfg.Database.ConnectAttempts)
 			}
 		})
 	}
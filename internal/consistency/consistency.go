@@ -0,0 +1,74 @@
+// Package consistency lets a caller read its own writes while the listing
+// queries are served by replicas that may lag behind the primary.
+package consistency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type primaryKey struct{}
+
+// WithPrimary marks ctx so that read-only queries made with it go to the
+// primary database
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// PrimaryRequired reports whether ctx was marked with WithPrimary
+func PrimaryRequired(ctx context.Context) bool {
+	required, _ := ctx.Value(primaryKey{}).(bool)
+	return required
+}
+
+// Tracker remembers the callers that wrote to the database within the last
+// window. The window should exceed the replication lag, so that by the time
+// it ends the replicas have caught up with the writes. Writes are kept in
+// memory, so another instance of the service does not know about them.
+type Tracker struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	writes    map[string]time.Time
+	lastSweep time.Time
+}
+
+func NewTracker(window time.Duration) *Tracker {
+	return &Tracker{
+		window: window,
+		now:    time.Now,
+		writes: make(map[string]time.Time),
+	}
+}
+
+// Wrote records a write made by caller
+func (t *Tracker) Wrote(caller string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.writes[caller] = now
+
+	// Forget callers whose window is over once per window, so the map only
+	// holds the recent writers
+	if now.Sub(t.lastSweep) < t.window {
+		return
+	}
+	for c, at := range t.writes {
+		if now.Sub(at) >= t.window {
+			delete(t.writes, c)
+		}
+	}
+	t.lastSweep = now
+}
+
+// Recent reports whether caller wrote within the window
+func (t *Tracker) Recent(caller string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	at, ok := t.writes[caller]
+	return ok && t.now().Sub(at) < t.window
+}
@@ -0,0 +1,38 @@
+package consistency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithPrimary(t *testing.T) {
+	ctx := context.Background()
+	assert.False(t, PrimaryRequired(ctx))
+	assert.True(t, PrimaryRequired(WithPrimary(ctx)))
+}
+
+func TestTracker(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewTracker(5 * time.Second)
+	tracker.now = func() time.Time { return now }
+
+	assert.False(t, tracker.Recent("employee@example.com"))
+
+	tracker.Wrote("employee@example.com")
+	assert.True(t, tracker.Recent("employee@example.com"))
+	assert.False(t, tracker.Recent("moderator@example.com"))
+
+	now = now.Add(4 * time.Second)
+	assert.True(t, tracker.Recent("employee@example.com"))
+
+	now = now.Add(time.Second)
+	assert.False(t, tracker.Recent("employee@example.com"))
+
+	// The next write after the window sweeps the expired callers
+	tracker.Wrote("moderator@example.com")
+	assert.Len(t, tracker.writes, 1)
+	assert.True(t, tracker.Recent("moderator@example.com"))
+}
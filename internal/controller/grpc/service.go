@@ -4,9 +4,12 @@ import (
 	"context"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	pvz_v1 "pvz-service/api/proto_v1"
+	"pvz-service/internal/models"
 	"pvz-service/internal/service"
 )
 
@@ -34,12 +37,83 @@ func (s *PVZServer) GetPVZList(ctx context.Context, req *pvz_v1.GetPVZListReques
 	// Конвертируем в gRPC формат
 	var pvzs []*pvz_v1.PVZ
 	for _, info := range pvzInfos {
-		pvzs = append(pvzs, &pvz_v1.PVZ{
-			Id:               info.ID.String(),
-			RegistrationDate: timestamppb.New(info.RegistrationDate),
-			City:             info.CityName,
-		})
+		pvzs = append(pvzs, toProtoPVZ(info))
 	}
 
 	return &pvz_v1.GetPVZListResponse{Pvzs: pvzs}, nil
 }
+
+func (s *PVZServer) ListPVZs(req *pvz_v1.ListPVZsRequest, stream grpc.ServerStreamingServer[pvz_v1.PVZInfo]) error {
+	filter := models.PVZFilter{Cities: req.GetCities()}
+	if req.GetStartDate() != nil {
+		filter.From = req.GetStartDate().AsTime()
+	}
+	if req.GetEndDate() != nil {
+		filter.To = req.GetEndDate().AsTime()
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.From.After(filter.To) {
+		return status.Error(codes.InvalidArgument, "start_date is after end_date")
+	}
+
+	// Отдаем ПВЗ клиенту по мере чтения из репозитория
+	return s.service.StreamPVZs(stream.Context(), filter, req.GetIncludeReceptions(), req.GetIncludeProducts(),
+		func(info models.PVZInfo) error {
+			return stream.Send(toProtoPVZInfo(info))
+		},
+	)
+}
+
+func toProtoPVZInfo(info models.PVZInfo) *pvz_v1.PVZInfo {
+	receptions := make([]*pvz_v1.ReceptionInfo, 0, len(info.Receptions))
+	for _, rec := range info.Receptions {
+		products := make([]*pvz_v1.Product, 0, len(rec.Products))
+		for _, prod := range rec.Products {
+			products = append(products, &pvz_v1.Product{
+				Id:          prod.ID.String(),
+				DateTime:    timestamppb.New(prod.DateTime),
+				Type:        prod.TypeName,
+				ReceptionId: prod.ReceptionID.String(),
+				Status:      string(prod.Status),
+			})
+		}
+
+		receptions = append(receptions, &pvz_v1.ReceptionInfo{
+			Reception: &pvz_v1.Reception{
+				Id:       rec.Reception.ID.String(),
+				DateTime: timestamppb.New(rec.Reception.DateTime),
+				PvzId:    rec.Reception.PVZID.String(),
+				Status:   toProtoReceptionStatus(rec.Reception.Status),
+			},
+			Products: products,
+		})
+	}
+
+	return &pvz_v1.PVZInfo{
+		Pvz:        toProtoPVZ(info.PVZ),
+		Receptions: receptions,
+	}
+}
+
+func toProtoReceptionStatus(s models.ReceptionStatus) pvz_v1.ReceptionStatus {
+	if s == models.ReceptionStatusClose {
+		return pvz_v1.ReceptionStatus_RECEPTION_STATUS_CLOSED
+	}
+	return pvz_v1.ReceptionStatus_RECEPTION_STATUS_IN_PROGRESS
+}
+
+func toProtoPVZ(pvz models.PVZ) *pvz_v1.PVZ {
+	resp := &pvz_v1.PVZ{
+		Id:               pvz.ID.String(),
+		RegistrationDate: timestamppb.New(pvz.RegistrationDate),
+		City:             pvz.CityName,
+		Address:          pvz.Address,
+		Capacity:         int32(pvz.Capacity),
+	}
+	if pvz.Location != nil {
+		resp.Location = &pvz_v1.Location{
+			Latitude:  pvz.Location.Latitude,
+			Longitude: pvz.Location.Longitude,
+		}
+	}
+	return resp
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	pvz_v1 "pvz-service/api/proto_v1"
@@ -25,23 +27,125 @@ func (m *MockPVZService) CreatePVZ(ctx context.Context, pvz *models.PVZ) (*model
 	return args.Get(0).(*models.PVZ), args.Error(1)
 }
 
-func (m *MockPVZService) StartReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
+func (m *MockPVZService) UpdatePVZ(ctx context.Context, pvzID uuid.UUID, update models.PVZUpdate, ifMatch *models.ETag) (*models.PVZ, error) {
+	args := m.Called(ctx, pvzID, update, ifMatch)
+	return args.Get(0).(*models.PVZ), args.Error(1)
+}
+
+func (m *MockPVZService) DeletePVZ(ctx context.Context, pvzID uuid.UUID, ifMatch *models.ETag) error {
+	args := m.Called(ctx, pvzID, ifMatch)
+	return args.Error(0)
+}
+
+func (m *MockPVZService) GetNearbyPVZs(ctx context.Context, point models.GeoPoint, radius float64, limit int) ([]models.NearbyPVZ, error) {
+	args := m.Called(ctx, point, radius, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.NearbyPVZ), args.Error(1)
+}
+
+func (m *MockPVZService) GetPVZSchedule(ctx context.Context, pvzID uuid.UUID) (*models.PVZSchedule, error) {
 	args := m.Called(ctx, pvzID)
-	return args.Get(0).(*models.Reception), args.Error(1)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PVZSchedule), args.Error(1)
 }
 
-func (m *MockPVZService) AddProduct(ctx context.Context, pvzID uuid.UUID, productTypeName string) (*models.Product, error) {
-	args := m.Called(ctx, pvzID, productTypeName)
-	return args.Get(0).(*models.Product), args.Error(1)
+func (m *MockPVZService) SetPVZSchedule(ctx context.Context, schedule *models.PVZSchedule) (*models.PVZSchedule, error) {
+	args := m.Called(ctx, schedule)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PVZSchedule), args.Error(1)
 }
 
-func (m *MockPVZService) DeleteLastProduct(ctx context.Context, pvzID uuid.UUID) error {
+func (m *MockPVZService) AllowReceptions(ctx context.Context, pvzID uuid.UUID, until time.Time) (*models.PVZSchedule, error) {
+	args := m.Called(ctx, pvzID, until)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PVZSchedule), args.Error(1)
+}
+
+func (m *MockPVZService) DeletePVZSchedule(ctx context.Context, pvzID uuid.UUID) error {
 	args := m.Called(ctx, pvzID)
 	return args.Error(0)
 }
 
-func (m *MockPVZService) CloseReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
+func (m *MockPVZService) GetPVZOccupancy(ctx context.Context, pvzID uuid.UUID) (*models.PVZOccupancy, error) {
 	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PVZOccupancy), args.Error(1)
+}
+
+func (m *MockPVZService) ListPVZOccupancy(ctx context.Context) ([]models.PVZOccupancy, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PVZOccupancy), args.Error(1)
+}
+
+func (m *MockPVZService) SetTypeCapacities(ctx context.Context, pvzID uuid.UUID, capacities []models.TypeCapacity) error {
+	args := m.Called(ctx, pvzID, capacities)
+	return args.Error(0)
+}
+
+func (m *MockPVZService) IssueProduct(ctx context.Context, productID uuid.UUID, comment string) (*models.Product, error) {
+	args := m.Called(ctx, productID, comment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockPVZService) ReturnProduct(ctx context.Context, productID uuid.UUID, comment string) (*models.Product, error) {
+	args := m.Called(ctx, productID, comment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockPVZService) WriteOffProduct(ctx context.Context, productID uuid.UUID, comment string) (*models.Product, error) {
+	args := m.Called(ctx, productID, comment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockPVZService) GetProductHistory(ctx context.Context, productID uuid.UUID) ([]models.ProductStatusChange, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ProductStatusChange), args.Error(1)
+}
+
+func (m *MockPVZService) StartReception(ctx context.Context, pvzID uuid.UUID, manifestID *uuid.UUID) (*models.Reception, error) {
+	args := m.Called(ctx, pvzID, manifestID)
+	return args.Get(0).(*models.Reception), args.Error(1)
+}
+
+func (m *MockPVZService) AddProduct(ctx context.Context, pvzID uuid.UUID, productTypeName, barcode string, ifMatch *models.ETag) (*models.Product, models.ETag, error) {
+	args := m.Called(ctx, pvzID, productTypeName, barcode, ifMatch)
+	etag, _ := args.Get(1).(models.ETag)
+	return args.Get(0).(*models.Product), etag, args.Error(2)
+}
+
+func (m *MockPVZService) DeleteLastProduct(ctx context.Context, pvzID uuid.UUID, ifMatch *models.ETag) (models.ETag, error) {
+	args := m.Called(ctx, pvzID, ifMatch)
+	etag, _ := args.Get(0).(models.ETag)
+	return etag, args.Error(1)
+}
+
+func (m *MockPVZService) CloseReception(ctx context.Context, pvzID uuid.UUID, ifMatch *models.ETag) (*models.Reception, error) {
+	args := m.Called(ctx, pvzID, ifMatch)
 	return args.Get(0).(*models.Reception), args.Error(1)
 }
 
@@ -55,6 +159,25 @@ func (m *MockPVZService) GetPVZs(ctx context.Context) ([]models.PVZ, error) {
 	return args.Get(0).([]models.PVZ), args.Error(1)
 }
 
+func (m *MockPVZService) StreamPVZs(ctx context.Context, filter models.PVZFilter, withReceptions, withProducts bool, send func(models.PVZInfo) error) error {
+	args := m.Called(ctx, filter, withReceptions, withProducts)
+	if list, ok := args.Get(0).([]models.PVZInfo); ok {
+		for _, info := range list {
+			if err := send(info); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockPVZService) SubscribeEvents(ctx context.Context, pvzID uuid.UUID) (<-chan models.Event, func(), error) {
+	args := m.Called(ctx, pvzID)
+	events, _ := args.Get(0).(chan models.Event)
+	unsubscribe, _ := args.Get(1).(func())
+	return events, unsubscribe, args.Error(2)
+}
+
 func TestNewPVZServer(t *testing.T) {
 	mockService := new(MockPVZService)
 	server := NewPVZServer(mockService)
@@ -94,6 +217,9 @@ func TestGetPVZList(t *testing.T) {
 					ID:               testUUID,
 					RegistrationDate: now,
 					CityName:         "Москва",
+					Address:          "ул. Тверская, 1",
+					Location:         &models.GeoPoint{Latitude: 55.7575, Longitude: 37.6132},
+					Capacity:         500,
 				},
 				{
 					ID:               testUUID,
@@ -108,6 +234,9 @@ func TestGetPVZList(t *testing.T) {
 						Id:               testUUID.String(),
 						RegistrationDate: timestamppb.New(now),
 						City:             "Москва",
+						Address:          "ул. Тверская, 1",
+						Location:         &pvz_v1.Location{Latitude: 55.7575, Longitude: 37.6132},
+						Capacity:         500,
 					},
 					{
 						Id:               testUUID.String(),
@@ -153,6 +282,11 @@ func TestGetPVZList(t *testing.T) {
 				for i, expectedPVZ := range tt.expected.Pvzs {
 					assert.Equal(t, expectedPVZ.Id, resp.Pvzs[i].Id)
 					assert.Equal(t, expectedPVZ.City, resp.Pvzs[i].City)
+					assert.Equal(t, expectedPVZ.Address, resp.Pvzs[i].Address)
+					assert.Equal(t, expectedPVZ.GetLocation().GetLatitude(), resp.Pvzs[i].GetLocation().GetLatitude())
+					assert.Equal(t, expectedPVZ.GetLocation().GetLongitude(), resp.Pvzs[i].GetLocation().GetLongitude())
+					assert.Equal(t, expectedPVZ.Location == nil, resp.Pvzs[i].Location == nil)
+					assert.Equal(t, expectedPVZ.Capacity, resp.Pvzs[i].Capacity)
 					assert.True(t, expectedPVZ.RegistrationDate.AsTime().Equal(resp.Pvzs[i].RegistrationDate.AsTime()))
 				}
 			}
@@ -161,3 +295,120 @@ func TestGetPVZList(t *testing.T) {
 		})
 	}
 }
+
+// mockPVZInfoStream collects messages sent by a server-streaming handler
+type mockPVZInfoStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*pvz_v1.PVZInfo
+}
+
+func (m *mockPVZInfoStream) Context() context.Context {
+	return m.ctx
+}
+
+func (m *mockPVZInfoStream) Send(info *pvz_v1.PVZInfo) error {
+	m.sent = append(m.sent, info)
+	return nil
+}
+
+func TestListPVZs(t *testing.T) {
+	now := time.Now()
+	pvzID := uuid.New()
+	receptionID := uuid.New()
+	productID := uuid.New()
+
+	infos := []models.PVZInfo{
+		{
+			PVZ: models.PVZ{ID: pvzID, RegistrationDate: now, CityName: "Казань"},
+			Receptions: []models.ReceptionInfo{
+				{
+					Reception: models.Reception{ID: receptionID, DateTime: now, PVZID: pvzID, Status: models.ReceptionStatusClose},
+					Products:  []models.Product{{ID: productID, DateTime: now, TypeName: "обувь", ReceptionID: receptionID}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		req           *pvz_v1.ListPVZsRequest
+		mockSetup     func(*MockPVZService)
+		expectedSent  int
+		expectedError codes.Code
+	}{
+		{
+			name: "streams filtered PVZs with receptions and products",
+			req: &pvz_v1.ListPVZsRequest{
+				Cities:            []string{"Казань"},
+				StartDate:         timestamppb.New(now.Add(-time.Hour)),
+				EndDate:           timestamppb.New(now.Add(time.Hour)),
+				IncludeReceptions: true,
+				IncludeProducts:   true,
+			},
+			mockSetup: func(m *MockPVZService) {
+				m.On("StreamPVZs", mock.Anything, mock.MatchedBy(func(f models.PVZFilter) bool {
+					return len(f.Cities) == 1 && f.Cities[0] == "Казань" &&
+						f.From.Equal(now.Add(-time.Hour)) && f.To.Equal(now.Add(time.Hour))
+				}), true, true).Return(infos, nil)
+			},
+			expectedSent: 1,
+		},
+		{
+			name: "no filters",
+			req:  &pvz_v1.ListPVZsRequest{},
+			mockSetup: func(m *MockPVZService) {
+				m.On("StreamPVZs", mock.Anything, models.PVZFilter{}, false, false).Return([]models.PVZInfo{}, nil)
+			},
+			expectedSent: 0,
+		},
+		{
+			name: "start date after end date",
+			req: &pvz_v1.ListPVZsRequest{
+				StartDate: timestamppb.New(now),
+				EndDate:   timestamppb.New(now.Add(-time.Hour)),
+			},
+			mockSetup:     func(m *MockPVZService) {},
+			expectedError: codes.InvalidArgument,
+		},
+		{
+			name: "service error",
+			req:  &pvz_v1.ListPVZsRequest{},
+			mockSetup: func(m *MockPVZService) {
+				m.On("StreamPVZs", mock.Anything, models.PVZFilter{}, false, false).Return(nil, assert.AnError)
+			},
+			expectedError: codes.Unknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockPVZService)
+			tt.mockSetup(mockService)
+			server := NewPVZServer(mockService)
+
+			stream := &mockPVZInfoStream{ctx: context.Background()}
+			err := server.ListPVZs(tt.req, stream)
+
+			if tt.expectedError != codes.OK {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError, status.Code(err))
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, stream.sent, tt.expectedSent)
+			}
+
+			if tt.expectedSent > 0 {
+				got := stream.sent[0]
+				assert.Equal(t, pvzID.String(), got.Pvz.Id)
+				assert.Equal(t, "Казань", got.Pvz.City)
+				assert.Len(t, got.Receptions, 1)
+				assert.Equal(t, pvz_v1.ReceptionStatus_RECEPTION_STATUS_CLOSED, got.Receptions[0].Reception.Status)
+				assert.Len(t, got.Receptions[0].Products, 1)
+				assert.Equal(t, productID.String(), got.Receptions[0].Products[0].Id)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -56,7 +56,22 @@ func (h *Handler) AddProduct() http.HandlerFunc {
 			return
 		}
 
-		product, err := h.pvzService.AddProduct(r.Context(), req.PvzId, string(req.Type))
+		ifMatch, err := parseIfMatch(r)
+		if err != nil {
+			log.Error("invalid If-Match header", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "invalid If-Match header"})
+
+			return
+		}
+
+		var barcode string
+		if req.Barcode != nil {
+			barcode = *req.Barcode
+		}
+
+		product, etag, err := h.pvzService.AddProduct(r.Context(), req.PvzId, string(req.Type), barcode, ifMatch)
 		if err == e.ErrNoActiveReception() {
 			log.Error("no active reception", sl.Err(err))
 
@@ -73,6 +88,22 @@ func (h *Handler) AddProduct() http.HandlerFunc {
 
 			return
 		}
+		if err == e.ErrCapacityExceeded() {
+			log.Error("pvz capacity exceeded", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "pvz capacity exceeded"})
+
+			return
+		}
+		if err == e.ErrVersionMismatch() {
+			log.Error("reception was modified", sl.Err(err))
+
+			w.WriteHeader(http.StatusPreconditionFailed)
+			render.JSON(w, r, api.Error{Message: "reception was modified"})
+
+			return
+		}
 		if err != nil {
 			log.Error("failed to add product", sl.Err(err))
 
@@ -83,6 +114,7 @@ func (h *Handler) AddProduct() http.HandlerFunc {
 		}
 
 		h.metrics.ProductsAdded.Inc()
+		w.Header().Set(ETagHeader, etag.String())
 		w.WriteHeader(http.StatusCreated)
 		render.JSON(w, r, product)
 	}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	api "pvz-service/api/generated"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/logger/sl"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// maxReceptionAllowance bounds how long receptions may be allowed outside
+// the working hours at once
+const maxReceptionAllowance = 24 * time.Hour
+
+func (h *Handler) AllowReception() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.AllowReception"
+
+		log := h.log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		pvzId := chi.URLParam(r, "pvzId")
+		if pvzId == "" {
+			log.Error("url param is empty")
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "empty request"})
+
+			return
+		}
+
+		id, err := uuid.Parse(pvzId)
+		if err != nil {
+			log.Error("invalid param", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "invalid param"})
+
+			return
+		}
+
+		var req api.PostPvzPvzIdAllowReceptionJSONRequestBody
+
+		err = render.DecodeJSON(r.Body, &req)
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "empty request"})
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "failed to decode request"})
+
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: e.ValidationError(validateErr)})
+
+			return
+		}
+
+		now := time.Now()
+		if !req.Until.After(now) || req.Until.After(now.Add(maxReceptionAllowance)) {
+			log.Error("invalid until", slog.Time("until", req.Until))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "until must be within the next 24 hours"})
+
+			return
+		}
+
+		schedule, err := h.pvzService.AllowReceptions(r.Context(), id, req.Until)
+		if err == e.ErrNotFound() {
+			log.Error("schedule not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, api.Error{Message: "schedule not found"})
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to allow receptions", sl.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, api.Error{Message: "failed to allow receptions"})
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, schedule)
+	}
+}
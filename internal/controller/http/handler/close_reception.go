@@ -6,6 +6,7 @@ import (
 	api "pvz-service/api/generated"
 	e "pvz-service/internal/errors"
 	"pvz-service/internal/logger/sl"
+	"pvz-service/internal/models"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -44,7 +45,17 @@ func (h *Handler) CloseReception() http.HandlerFunc {
 			return
 		}
 
-		reception, err := h.pvzService.CloseReception(r.Context(), id)
+		ifMatch, err := parseIfMatch(r)
+		if err != nil {
+			log.Error("invalid If-Match header", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "invalid If-Match header"})
+
+			return
+		}
+
+		reception, err := h.pvzService.CloseReception(r.Context(), id, ifMatch)
 		if err == e.ErrNoActiveReception() {
 			log.Error("no active reception", sl.Err(err))
 
@@ -53,6 +64,14 @@ func (h *Handler) CloseReception() http.HandlerFunc {
 
 			return
 		}
+		if err == e.ErrVersionMismatch() {
+			log.Error("reception was modified", sl.Err(err))
+
+			w.WriteHeader(http.StatusPreconditionFailed)
+			render.JSON(w, r, api.Error{Message: "reception was modified"})
+
+			return
+		}
 		if err != nil {
 			log.Error("failed to close reception", sl.Err(err))
 
@@ -62,6 +81,7 @@ func (h *Handler) CloseReception() http.HandlerFunc {
 			return
 		}
 
+		w.Header().Set(ETagHeader, models.ETag{ID: reception.ID, Version: reception.Version}.String())
 		w.WriteHeader(http.StatusOK)
 		render.JSON(w, r, reception)
 	}
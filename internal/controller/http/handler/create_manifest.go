@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	api "pvz-service/api/generated"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/logger/sl"
+	"pvz-service/internal/models"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+func (h *Handler) CreateManifest() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.CreateManifest"
+
+		log := h.log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req api.PostManifestsJSONRequestBody
+
+		err := render.DecodeJSON(r.Body, &req)
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "empty request"})
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "failed to decode request"})
+
+			return
+		}
+
+		log.Info("request body decoded", slog.String("supplier", req.Supplier), slog.Int("items", len(req.Items)))
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: e.ValidationError(validateErr)})
+
+			return
+		}
+
+		items := make([]models.ManifestItem, len(req.Items))
+		for i, item := range req.Items {
+			items[i] = models.ManifestItem{Barcode: item.Barcode, TypeName: string(item.Type)}
+		}
+
+		manifest, err := h.manifestService.CreateManifest(r.Context(), req.PvzId, req.Supplier, items)
+		if err == e.ErrNotFound() {
+			log.Error("pvz not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, api.Error{Message: "pvz not found"})
+
+			return
+		}
+		if err == e.ErrProductTypeNotAllowed() {
+			log.Error("product type not allowed", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "product type not allowed"})
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to create manifest", sl.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, api.Error{Message: "failed to create manifest"})
+
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		render.JSON(w, r, manifest)
+	}
+}
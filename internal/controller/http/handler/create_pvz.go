@@ -61,6 +61,13 @@ func (h *Handler) CreatePVZ() http.HandlerFunc {
 
 		pvz := models.PVZ{
 			CityName: string(req.City),
+			Location: toGeoPoint(req.Location),
+		}
+		if req.Address != nil {
+			pvz.Address = *req.Address
+		}
+		if req.Capacity != nil {
+			pvz.Capacity = *req.Capacity
 		}
 
 		if req.RegistrationDate == nil {
@@ -86,7 +93,15 @@ func (h *Handler) CreatePVZ() http.HandlerFunc {
 		}
 
 		h.metrics.PVZCreated.Inc()
+		w.Header().Set(ETagHeader, models.ETag{ID: resp.ID, Version: resp.Version}.String())
 		w.WriteHeader(http.StatusCreated)
 		render.JSON(w, r, resp)
 	}
 }
+
+func toGeoPoint(point *api.GeoPoint) *models.GeoPoint {
+	if point == nil {
+		return nil
+	}
+	return &models.GeoPoint{Latitude: point.Latitude, Longitude: point.Longitude}
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	api "pvz-service/api/generated"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/logger/sl"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+func (h *Handler) CreateTransfer() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.CreateTransfer"
+
+		log := h.log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req api.PostTransfersJSONRequestBody
+
+		err := render.DecodeJSON(r.Body, &req)
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "empty request"})
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "failed to decode request"})
+
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: e.ValidationError(validateErr)})
+
+			return
+		}
+
+		if req.SourcePvzId == req.DestinationPvzId {
+			log.Error("source and destination pvz are the same")
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "source and destination pvz must differ"})
+
+			return
+		}
+
+		transfer, err := h.transferService.CreateTransfer(r.Context(), req.SourcePvzId, req.DestinationPvzId, req.ProductIds)
+		if err == e.ErrNotFound() {
+			log.Error("pvz not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, api.Error{Message: "pvz not found"})
+
+			return
+		}
+		if err == e.ErrPVZNotActive() {
+			log.Error("destination pvz is not active", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "destination pvz is not active"})
+
+			return
+		}
+		if err == e.ErrProductNotTransferable() {
+			log.Error("product cannot be transferred", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "product cannot be transferred"})
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to create transfer", sl.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, api.Error{Message: "failed to create transfer"})
+
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		render.JSON(w, r, transfer)
+	}
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	api "pvz-service/api/generated"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/logger/sl"
+	"pvz-service/internal/models"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) CreateWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.CreateWebhook"
+
+		log := h.log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req api.PostWebhooksJSONRequestBody
+
+		err := render.DecodeJSON(r.Body, &req)
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "empty request"})
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, api.Error{Message: "failed to decode request"})
+
+			return
+		}
+
+		log.Info("request body decoded", slog.String("url", req.Url))
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: e.ValidationError(validateErr)})
+
+			return
+		}
+
+		sub := models.WebhookSubscription{
+			URL:   req.Url,
+			PVZID: req.PvzId,
+		}
+
+		if req.Id != nil {
+			sub.ID = *req.Id
+		} else {
+			sub.ID = uuid.New()
+		}
+
+		if req.Secret != nil {
+			sub.Secret = *req.Secret
+		}
+
+		if req.City != nil {
+			sub.CityName = string(*req.City)
+		}
+
+		if req.EventTypes != nil {
+			for _, t := range *req.EventTypes {
+				sub.EventTypes = append(sub.EventTypes, models.EventType(t))
+			}
+		}
+
+		resp, err := h.webhookService.CreateSubscription(r.Context(), &sub)
+		if err == e.ErrNotFound() {
+			log.Error("pvz not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, api.Error{Message: "pvz not found"})
+
+			return
+		}
+		if err == e.ErrCityNotAllowed() {
+			log.Error("city not allowed", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "city not allowed"})
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to create webhook", sl.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, api.Error{Message: "failed to create webhook"})
+
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		render.JSON(w, r, resp)
+	}
+}
@@ -44,7 +44,17 @@ func (h *Handler) DeleteLastProduct() http.HandlerFunc {
 			return
 		}
 
-		err = h.pvzService.DeleteLastProduct(r.Context(), id)
+		ifMatch, err := parseIfMatch(r)
+		if err != nil {
+			log.Error("invalid If-Match header", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "invalid If-Match header"})
+
+			return
+		}
+
+		etag, err := h.pvzService.DeleteLastProduct(r.Context(), id, ifMatch)
 		if err == e.ErrNoActiveReception() {
 			log.Error("no active reception", sl.Err(err))
 
@@ -61,6 +71,14 @@ func (h *Handler) DeleteLastProduct() http.HandlerFunc {
 
 			return
 		}
+		if err == e.ErrVersionMismatch() {
+			log.Error("reception was modified", sl.Err(err))
+
+			w.WriteHeader(http.StatusPreconditionFailed)
+			render.JSON(w, r, api.Error{Message: "reception was modified"})
+
+			return
+		}
 		if err != nil {
 			log.Error("failed to delete product", sl.Err(err))
 
@@ -70,6 +88,7 @@ func (h *Handler) DeleteLastProduct() http.HandlerFunc {
 			return
 		}
 
+		w.Header().Set(ETagHeader, etag.String())
 		w.WriteHeader(http.StatusOK)
 		render.NoContent(w, r)
 	}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	api "pvz-service/api/generated"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/logger/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) DeletePVZ() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.DeletePVZ"
+
+		log := h.log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		pvzId := chi.URLParam(r, "pvzId")
+		if pvzId == "" {
+			log.Error("url param is empty")
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "empty request"})
+
+			return
+		}
+
+		log.Info("url param decoded", slog.Any("param", pvzId))
+
+		id, err := uuid.Parse(pvzId)
+		if err != nil {
+			log.Error("invalid param", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "invalid param"})
+
+			return
+		}
+
+		ifMatch, err := parseIfMatch(r)
+		if err != nil {
+			log.Error("invalid If-Match header", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "invalid If-Match header"})
+
+			return
+		}
+
+		err = h.pvzService.DeletePVZ(r.Context(), id, ifMatch)
+		if err == e.ErrNotFound() {
+			log.Error("pvz not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, api.Error{Message: "pvz not found"})
+
+			return
+		}
+		if err == e.ErrActiveReceptionExists() {
+			log.Error("active reception exists", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "active reception exists"})
+
+			return
+		}
+		if err == e.ErrVersionMismatch() {
+			log.Error("pvz was modified", sl.Err(err))
+
+			w.WriteHeader(http.StatusPreconditionFailed)
+			render.JSON(w, r, api.Error{Message: "pvz was modified"})
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to delete pvz", sl.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, api.Error{Message: "failed to delete pvz"})
+
+			return
+		}
+
+		render.NoContent(w, r)
+	}
+}
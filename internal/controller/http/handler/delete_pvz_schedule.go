@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	api "pvz-service/api/generated"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/logger/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) DeletePVZSchedule() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.DeletePVZSchedule"
+
+		log := h.log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		pvzId := chi.URLParam(r, "pvzId")
+		if pvzId == "" {
+			log.Error("url param is empty")
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "empty request"})
+
+			return
+		}
+
+		id, err := uuid.Parse(pvzId)
+		if err != nil {
+			log.Error("invalid param", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "invalid param"})
+
+			return
+		}
+
+		err = h.pvzService.DeletePVZSchedule(r.Context(), id)
+		if err == e.ErrNotFound() {
+			log.Error("schedule not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, api.Error{Message: "schedule not found"})
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to delete schedule", sl.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, api.Error{Message: "failed to delete schedule"})
+
+			return
+		}
+
+		render.NoContent(w, r)
+	}
+}
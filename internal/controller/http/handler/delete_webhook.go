@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	api "pvz-service/api/generated"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/logger/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) DeleteWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.DeleteWebhook"
+
+		log := h.log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		webhookId := chi.URLParam(r, "webhookId")
+		if webhookId == "" {
+			log.Error("url param is empty")
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "empty request"})
+
+			return
+		}
+
+		log.Info("url param decoded", slog.Any("param", webhookId))
+
+		id, err := uuid.Parse(webhookId)
+		if err != nil {
+			log.Error("invalid param", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "invalid param"})
+
+			return
+		}
+
+		err = h.webhookService.DeleteSubscription(r.Context(), id)
+		if err == e.ErrNotFound() {
+			log.Error("webhook not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, api.Error{Message: "webhook not found"})
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to delete webhook", sl.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, api.Error{Message: "failed to delete webhook"})
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.NoContent(w, r)
+	}
+}
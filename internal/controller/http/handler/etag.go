@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"net/http"
+	"pvz-service/internal/models"
+	"strings"
+)
+
+const (
+	ETagHeader    = "ETag"
+	IfMatchHeader = "If-Match"
+)
+
+// parseIfMatch returns the tag a mutation must match, or nil when If-Match
+// is absent or "*". Lists of tags are not supported.
+func parseIfMatch(r *http.Request) (*models.ETag, error) {
+	value := strings.TrimSpace(r.Header.Get(IfMatchHeader))
+	if value == "" || value == "*" {
+		return nil, nil
+	}
+
+	tag, err := models.ParseETag(value)
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	api "pvz-service/api/generated"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/logger/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// forceCloseActor is recorded in the reception audit when the moderator's
+// email is not known
+const forceCloseActor = "moderator"
+
+func (h *Handler) ForceCloseReception() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.ForceCloseReception"
+
+		log := h.log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		pvzId := chi.URLParam(r, "pvzId")
+		if pvzId == "" {
+			log.Error("url param is empty")
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "empty request"})
+
+			return
+		}
+
+		id, err := uuid.Parse(pvzId)
+		if err != nil {
+			log.Error("invalid param", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "invalid param"})
+
+			return
+		}
+
+		var req api.ForceCloseReceptionRequest
+
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil && !errors.Is(err, io.EOF) {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "failed to decode request"})
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: e.ValidationError(validateErr)})
+
+			return
+		}
+
+		var reason string
+		if req.Reason != nil {
+			reason = *req.Reason
+		}
+
+		actor, _ := r.Context().Value("user_email").(string)
+		if actor == "" {
+			actor = forceCloseActor
+		}
+
+		reception, err := h.staleReceptionService.ForceCloseReception(r.Context(), id, actor, reason)
+		if err == e.ErrNoActiveReception() {
+			log.Error("no active reception", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "no active reception"})
+
+			return
+		}
+		if err == e.ErrVersionMismatch() {
+			log.Error("reception was modified", sl.Err(err))
+
+			w.WriteHeader(http.StatusConflict)
+			render.JSON(w, r, api.Error{Message: "reception was modified"})
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to close reception", sl.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, api.Error{Message: "failed to close reception"})
+
+			return
+		}
+
+		h.metrics.ReceptionsForceClosed.WithLabelValues("moderator").Inc()
+
+		log.Info("reception force closed", slog.Any("reception_id", reception.ID), slog.String("actor", actor))
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, reception)
+	}
+}
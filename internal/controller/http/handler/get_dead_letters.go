@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	api "pvz-service/api/generated"
+	"pvz-service/internal/logger/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) GetDeadLetters() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.GetDeadLetters"
+
+		log := h.log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		webhookId := chi.URLParam(r, "webhookId")
+		if webhookId == "" {
+			log.Error("url param is empty")
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "empty request"})
+
+			return
+		}
+
+		log.Info("url param decoded", slog.Any("param", webhookId))
+
+		id, err := uuid.Parse(webhookId)
+		if err != nil {
+			log.Error("invalid param", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "invalid param"})
+
+			return
+		}
+
+		resp, err := h.webhookService.GetDeadLetters(r.Context(), id)
+		if err != nil {
+			log.Error("failed to get dead letters", sl.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, api.Error{Message: "failed to get dead letters"})
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	api "pvz-service/api/generated"
+	"pvz-service/internal/logger/sl"
+	"pvz-service/internal/models"
+	"strconv"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+const (
+	maxNearbyRadius = 50000
+	defaultNearby   = 20
+	maxNearby       = 100
+)
+
+func (h *Handler) GetNearbyPVZs() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.GetNearbyPVZs"
+
+		log := h.log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		query := r.URL.Query()
+
+		lat, err := strconv.ParseFloat(query.Get("lat"), 64)
+		if err != nil || math.IsNaN(lat) || lat < -90 || lat > 90 {
+			log.Error("invalid lat param", slog.String("lat", query.Get("lat")))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "invalid lat param"})
+
+			return
+		}
+
+		lon, err := strconv.ParseFloat(query.Get("lon"), 64)
+		if err != nil || math.IsNaN(lon) || lon < -180 || lon > 180 {
+			log.Error("invalid lon param", slog.String("lon", query.Get("lon")))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "invalid lon param"})
+
+			return
+		}
+
+		radius, err := strconv.ParseFloat(query.Get("radius"), 64)
+		if err != nil || math.IsNaN(radius) || radius <= 0 || radius > maxNearbyRadius {
+			log.Error("invalid radius param", slog.String("radius", query.Get("radius")))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "invalid radius param"})
+
+			return
+		}
+
+		limit := defaultNearby
+		if param := query.Get("limit"); param != "" {
+			limit, err = strconv.Atoi(param)
+			if err != nil || limit < 1 || limit > maxNearby {
+				log.Error("invalid limit param", slog.String("limit", query.Get("limit")))
+
+				w.WriteHeader(http.StatusBadRequest)
+				render.JSON(w, r, api.Error{Message: "invalid limit param"})
+
+				return
+			}
+		}
+
+		point := models.GeoPoint{Latitude: lat, Longitude: lon}
+
+		log.Info("query param decoded and validated",
+			slog.Any("point", point),
+			slog.Float64("radius", radius),
+			slog.Int("limit", limit),
+		)
+
+		resp, err := h.pvzService.GetNearbyPVZs(r.Context(), point, radius, limit)
+		if err != nil {
+			log.Error("failed to get nearby pvz list", sl.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, api.Error{Message: "failed to get nearby pvz list"})
+
+			return
+		}
+		if resp == nil {
+			resp = []models.NearbyPVZ{}
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
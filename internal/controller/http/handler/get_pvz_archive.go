@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	api "pvz-service/api/generated"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/logger/sl"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+// GetPVZArchive lists the receptions of the PVZ moved to the archive by the
+// retention job, filtered and paged like GetPVZsWithReceptions
+func (h *Handler) GetPVZArchive() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.GetPVZArchive"
+
+		log := h.log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		pvzId := chi.URLParam(r, "pvzId")
+		if pvzId == "" {
+			log.Error("url param is empty")
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "empty request"})
+
+			return
+		}
+
+		id, err := uuid.Parse(pvzId)
+		if err != nil {
+			log.Error("invalid param", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "invalid param"})
+
+			return
+		}
+
+		query := r.URL.Query()
+		var (
+			param     string
+			layout    string = time.RFC3339
+			startDate time.Time
+			endDate   time.Time
+			page      int
+			limit     int
+		)
+
+		param = query.Get("startDate")
+		if param != "" {
+			startDate, err = time.Parse(layout, param)
+			if err != nil {
+				log.Error("invalid startDate param", sl.Err(err))
+
+				w.WriteHeader(http.StatusBadRequest)
+				render.JSON(w, r, api.Error{Message: "invalid startDate param"})
+
+				return
+			}
+		}
+
+		param = query.Get("endDate")
+		if param == "" {
+			endDate = time.Now()
+		} else {
+			endDate, err = time.Parse(layout, param)
+			if err != nil {
+				log.Error("invalid endDate param", sl.Err(err))
+
+				w.WriteHeader(http.StatusBadRequest)
+				render.JSON(w, r, api.Error{Message: "invalid endDate param"})
+
+				return
+			}
+		}
+
+		param = query.Get("page")
+		if param == "" {
+			page = 1
+		} else {
+			page, err = strconv.Atoi(param)
+			if err != nil || page < 1 {
+				log.Error("invalid page param", slog.String("page", param))
+
+				w.WriteHeader(http.StatusBadRequest)
+				render.JSON(w, r, api.Error{Message: "invalid page param"})
+
+				return
+			}
+		}
+
+		param = query.Get("limit")
+		if param == "" {
+			limit = 10
+		} else {
+			limit, err = strconv.Atoi(param)
+			if err != nil || limit < 1 || limit > 30 {
+				log.Error("invalid limit param", slog.String("limit", param))
+
+				w.WriteHeader(http.StatusBadRequest)
+				render.JSON(w, r, api.Error{Message: "invalid limit param"})
+
+				return
+			}
+		}
+
+		receptions, err := h.archiveService.GetArchivedReceptions(r.Context(), id, startDate, endDate, page, limit)
+		if err == e.ErrNotFound() {
+			log.Error("pvz not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, api.Error{Message: "pvz not found"})
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to get archived receptions", sl.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, api.Error{Message: "failed to get archived receptions"})
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, receptions)
+	}
+}
@@ -7,10 +7,16 @@ import (
 )
 
 type Handler struct {
-	log         *slog.Logger
-	metrics     *metrics.Metrics
-	authService service.AuthServiceInterface
-	pvzService  service.PVZServiceInterface
+	log                     *slog.Logger
+	metrics                 *metrics.Metrics
+	authService             service.AuthServiceInterface
+	pvzService              service.PVZServiceInterface
+	transferService         service.TransferServiceInterface
+	manifestService         service.ManifestServiceInterface
+	productConditionService service.ProductConditionServiceInterface
+	staleReceptionService   service.StaleReceptionServiceInterface
+	archiveService          service.ArchiveServiceInterface
+	webhookService          service.WebhookServiceInterface
 }
 
 func NewHandler(
@@ -18,11 +24,23 @@ func NewHandler(
 	metrics *metrics.Metrics,
 	authService service.AuthServiceInterface,
 	pvzService service.PVZServiceInterface,
+	transferService service.TransferServiceInterface,
+	manifestService service.ManifestServiceInterface,
+	productConditionService service.ProductConditionServiceInterface,
+	staleReceptionService service.StaleReceptionServiceInterface,
+	archiveService service.ArchiveServiceInterface,
+	webhookService service.WebhookServiceInterface,
 ) *Handler {
 	return &Handler{
-		log:         log,
-		metrics:     metrics,
-		authService: authService,
-		pvzService:  pvzService,
+		log:                     log,
+		metrics:                 metrics,
+		authService:             authService,
+		pvzService:              pvzService,
+		transferService:         transferService,
+		manifestService:         manifestService,
+		productConditionService: productConditionService,
+		staleReceptionService:   staleReceptionService,
+		archiveService:          archiveService,
+		webhookService:          webhookService,
 	}
 }
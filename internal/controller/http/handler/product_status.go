@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	api "pvz-service/api/generated"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/logger/sl"
+	"pvz-service/internal/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) IssueProduct() http.HandlerFunc {
+	return h.changeProductStatus("handler.IssueProduct", h.pvzService.IssueProduct)
+}
+
+func (h *Handler) ReturnProduct() http.HandlerFunc {
+	return h.changeProductStatus("handler.ReturnProduct", h.pvzService.ReturnProduct)
+}
+
+func (h *Handler) WriteOffProduct() http.HandlerFunc {
+	return h.changeProductStatus("handler.WriteOffProduct", h.pvzService.WriteOffProduct)
+}
+
+// changeProductStatus serves the product status endpoints, which differ only
+// in the service call. The request body with a comment is optional.
+func (h *Handler) changeProductStatus(op string, change func(ctx context.Context, productID uuid.UUID, comment string) (*models.Product, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := h.log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		productId := chi.URLParam(r, "productId")
+		if productId == "" {
+			log.Error("url param is empty")
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "empty request"})
+
+			return
+		}
+
+		id, err := uuid.Parse(productId)
+		if err != nil {
+			log.Error("invalid param", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "invalid param"})
+
+			return
+		}
+
+		var req api.ProductStatusRequest
+
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil && !errors.Is(err, io.EOF) {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "failed to decode request"})
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: e.ValidationError(validateErr)})
+
+			return
+		}
+
+		var comment string
+		if req.Comment != nil {
+			comment = *req.Comment
+		}
+
+		product, err := change(r.Context(), id, comment)
+		if err == e.ErrNotFound() {
+			log.Error("product not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, api.Error{Message: "product not found"})
+
+			return
+		}
+		if err == e.ErrInvalidStatusTransition() {
+			log.Error("invalid product status transition", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "invalid product status transition"})
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to change product status", sl.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, api.Error{Message: "failed to change product status"})
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, product)
+	}
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	api "pvz-service/api/generated"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/logger/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) Redeliver() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.Redeliver"
+
+		log := h.log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		deliveryId := chi.URLParam(r, "deliveryId")
+		if deliveryId == "" {
+			log.Error("url param is empty")
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "empty request"})
+
+			return
+		}
+
+		log.Info("url param decoded", slog.Any("param", deliveryId))
+
+		id, err := uuid.Parse(deliveryId)
+		if err != nil {
+			log.Error("invalid param", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "invalid param"})
+
+			return
+		}
+
+		err = h.webhookService.Redeliver(r.Context(), id)
+		if err == e.ErrNotFound() {
+			log.Error("dead delivery not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, api.Error{Message: "dead delivery not found"})
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to redeliver", sl.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, api.Error{Message: "failed to redeliver"})
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.NoContent(w, r)
+	}
+}
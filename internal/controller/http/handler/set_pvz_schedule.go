@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	api "pvz-service/api/generated"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/logger/sl"
+	"pvz-service/internal/models"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) SetPVZSchedule() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.SetPVZSchedule"
+
+		log := h.log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		pvzId := chi.URLParam(r, "pvzId")
+		if pvzId == "" {
+			log.Error("url param is empty")
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "empty request"})
+
+			return
+		}
+
+		id, err := uuid.Parse(pvzId)
+		if err != nil {
+			log.Error("invalid param", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "invalid param"})
+
+			return
+		}
+
+		var req api.PutPvzPvzIdScheduleJSONRequestBody
+
+		err = render.DecodeJSON(r.Body, &req)
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "empty request"})
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "failed to decode request"})
+
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: e.ValidationError(validateErr)})
+
+			return
+		}
+
+		schedule := toSchedule(id, req)
+		if err := schedule.Validate(); err != nil {
+			log.Error("invalid schedule", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "invalid schedule: " + err.Error()})
+
+			return
+		}
+
+		resp, err := h.pvzService.SetPVZSchedule(r.Context(), schedule)
+		if err == e.ErrNotFound() {
+			log.Error("pvz not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, api.Error{Message: "pvz not found"})
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to set schedule", sl.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, api.Error{Message: "failed to set schedule"})
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
+
+func toSchedule(pvzID uuid.UUID, req api.PVZSchedule) *models.PVZSchedule {
+	schedule := &models.PVZSchedule{
+		PVZID:      pvzID,
+		Timezone:   req.Timezone,
+		Weekly:     make([]models.ScheduleDay, 0, len(req.Weekly)),
+		Exceptions: []models.ScheduleException{},
+	}
+
+	for _, day := range req.Weekly {
+		schedule.Weekly = append(schedule.Weekly, models.ScheduleDay{
+			Weekday:   string(day.Weekday),
+			Intervals: toScheduleIntervals(day.Intervals),
+		})
+	}
+
+	if req.Exceptions != nil {
+		for _, exception := range *req.Exceptions {
+			converted := models.ScheduleException{
+				Date:      exception.Date.Format(time.DateOnly),
+				Intervals: []models.ScheduleInterval{},
+			}
+			if exception.Intervals != nil {
+				converted.Intervals = toScheduleIntervals(*exception.Intervals)
+			}
+			if exception.Note != nil {
+				converted.Note = *exception.Note
+			}
+			schedule.Exceptions = append(schedule.Exceptions, converted)
+		}
+	}
+
+	return schedule
+}
+
+func toScheduleIntervals(intervals []api.ScheduleInterval) []models.ScheduleInterval {
+	result := make([]models.ScheduleInterval, len(intervals))
+	for i, interval := range intervals {
+		result[i] = models.ScheduleInterval{Opens: interval.Opens, Closes: interval.Closes}
+	}
+	return result
+}
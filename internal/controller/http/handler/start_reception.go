@@ -8,6 +8,7 @@ import (
 	api "pvz-service/api/generated"
 	e "pvz-service/internal/errors"
 	"pvz-service/internal/logger/sl"
+	"pvz-service/internal/models"
 
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
@@ -56,7 +57,7 @@ func (h *Handler) StartReception() http.HandlerFunc {
 			return
 		}
 
-		reception, err := h.pvzService.StartReception(r.Context(), req.PvzId)
+		reception, err := h.pvzService.StartReception(r.Context(), req.PvzId, req.ManifestId)
 		if err == e.ErrCityNotAllowed() {
 			log.Error("city not allowed", sl.Err(err))
 
@@ -65,6 +66,30 @@ func (h *Handler) StartReception() http.HandlerFunc {
 
 			return
 		}
+		if err == e.ErrPVZNotActive() {
+			log.Error("pvz is not active", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "pvz is not active"})
+
+			return
+		}
+		if err == e.ErrOutsideWorkingHours() {
+			log.Error("pvz is closed", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "pvz is closed at this time"})
+
+			return
+		}
+		if err == e.ErrManifestNotAvailable() {
+			log.Error("manifest not available", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "manifest cannot be used for this reception"})
+
+			return
+		}
 		if err == e.ErrActiveReceptionExists() {
 			log.Error("active reception exists", sl.Err(err))
 
@@ -83,6 +108,7 @@ func (h *Handler) StartReception() http.HandlerFunc {
 		}
 
 		h.metrics.ReceptionsCreated.Inc()
+		w.Header().Set(ETagHeader, models.ETag{ID: reception.ID, Version: reception.Version}.String())
 		w.WriteHeader(http.StatusCreated)
 		render.JSON(w, r, reception)
 	}
@@ -26,7 +26,8 @@ func TestAddProduct_Success(t *testing.T) {
 		ReceptionID: uuid.New(),
 	}
 
-	pvzMock.On("AddProduct", mock.Anything, pvzID, productType).Return(expectedProduct, nil)
+	etag := models.ETag{ID: expectedProduct.ReceptionID, Version: 2}
+	pvzMock.On("AddProduct", mock.Anything, pvzID, productType, "", (*models.ETag)(nil)).Return(expectedProduct, etag, nil)
 
 	reqBody := api.PostProductsJSONRequestBody{
 		PvzId: pvzID,
@@ -37,6 +38,7 @@ func TestAddProduct_Success(t *testing.T) {
 	handler.AddProduct().ServeHTTP(rec, req)
 
 	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, etag.String(), rec.Header().Get("ETag"))
 
 	var resp api.Product
 	err := json.NewDecoder(rec.Body).Decode(&resp)
@@ -49,8 +51,8 @@ func TestAddProduct_NoActiveReception(t *testing.T) {
 	_, pvzMock, handler := setupHandler(t)
 
 	pvzID := uuid.New()
-	pvzMock.On("AddProduct", mock.Anything, pvzID, mock.Anything).Return(
-		(*models.Product)(nil), e.ErrNoActiveReception(),
+	pvzMock.On("AddProduct", mock.Anything, pvzID, mock.Anything, mock.Anything, mock.Anything).Return(
+		(*models.Product)(nil), models.ETag{}, e.ErrNoActiveReception(),
 	)
 
 	reqBody := api.PostProductsJSONRequestBody{
@@ -68,3 +70,69 @@ func TestAddProduct_NoActiveReception(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "no active reception", resp.Message)
 }
+
+func TestAddProduct_CapacityExceeded(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzID := uuid.New()
+	pvzMock.On("AddProduct", mock.Anything, pvzID, mock.Anything, mock.Anything, mock.Anything).Return(
+		(*models.Product)(nil), models.ETag{}, e.ErrCapacityExceeded(),
+	)
+
+	reqBody := api.PostProductsJSONRequestBody{
+		PvzId: pvzID,
+		Type:  "обувь",
+	}
+
+	req, rec := createRequest(http.MethodPost, "/products", reqBody)
+	handler.AddProduct().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var resp api.Error
+	err := json.NewDecoder(rec.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "pvz capacity exceeded", resp.Message)
+}
+
+func TestAddProduct_IfMatch(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzID := uuid.New()
+	ifMatch := models.ETag{ID: uuid.New(), Version: 3}
+	pvzMock.On("AddProduct", mock.Anything, pvzID, "одежда", "", &ifMatch).Return(
+		(*models.Product)(nil), models.ETag{}, e.ErrVersionMismatch(),
+	)
+
+	reqBody := api.PostProductsJSONRequestBody{
+		PvzId: pvzID,
+		Type:  "одежда",
+	}
+
+	req, rec := createRequest(http.MethodPost, "/products", reqBody)
+	req.Header.Set("If-Match", ifMatch.String())
+	handler.AddProduct().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+	var resp api.Error
+	err := json.NewDecoder(rec.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "reception was modified", resp.Message)
+}
+
+func TestAddProduct_InvalidIfMatch(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	reqBody := api.PostProductsJSONRequestBody{
+		PvzId: uuid.New(),
+		Type:  "одежда",
+	}
+
+	req, rec := createRequest(http.MethodPost, "/products", reqBody)
+	req.Header.Set("If-Match", `"not-a-tag"`)
+	handler.AddProduct().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	pvzMock.AssertNotCalled(t, "AddProduct")
+}
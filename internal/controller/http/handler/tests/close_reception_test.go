@@ -23,15 +23,17 @@ func TestCloseReception_Success(t *testing.T) {
 		DateTime: time.Now(),
 		PVZID:    pvzID,
 		Status:   models.ReceptionStatusClose,
+		Version:  3,
 	}
 
-	pvzMock.On("CloseReception", mock.Anything, pvzID).Return(expectedReception, nil)
+	pvzMock.On("CloseReception", mock.Anything, pvzID, (*models.ETag)(nil)).Return(expectedReception, nil)
 
 	req, rec := createRequest(http.MethodPost, "/pvz/"+pvzID.String()+"/receptions/close", nil)
 	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
 	handler.CloseReception().ServeHTTP(rec, req)
 
 	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"`+expectedReception.ID.String()+`.3"`, rec.Header().Get("ETag"))
 
 	var resp api.Reception
 	err := json.NewDecoder(rec.Body).Decode(&resp)
@@ -44,7 +46,7 @@ func TestCloseReception_NoActiveReception(t *testing.T) {
 	_, pvzMock, handler := setupHandler(t)
 
 	pvzID := uuid.New()
-	pvzMock.On("CloseReception", mock.Anything, pvzID).Return(
+	pvzMock.On("CloseReception", mock.Anything, pvzID, mock.Anything).Return(
 		(*models.Reception)(nil), e.ErrNoActiveReception(),
 	)
 
@@ -54,3 +56,35 @@ func TestCloseReception_NoActiveReception(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 }
+
+func TestCloseReception_IfMatch(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzID := uuid.New()
+	ifMatch := models.ETag{ID: uuid.New(), Version: 2}
+	pvzMock.On("CloseReception", mock.Anything, pvzID, &ifMatch).Return(
+		(*models.Reception)(nil), e.ErrVersionMismatch(),
+	)
+
+	req, rec := createRequest(http.MethodPost, "/pvz/"+pvzID.String()+"/receptions/close", nil)
+	req.Header.Set("If-Match", ifMatch.String())
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.CloseReception().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+}
+
+func TestCloseReception_IfMatchAny(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzID := uuid.New()
+	reception := &models.Reception{ID: uuid.New(), PVZID: pvzID, Status: models.ReceptionStatusClose, Version: 2}
+	pvzMock.On("CloseReception", mock.Anything, pvzID, (*models.ETag)(nil)).Return(reception, nil)
+
+	req, rec := createRequest(http.MethodPost, "/pvz/"+pvzID.String()+"/receptions/close", nil)
+	req.Header.Set("If-Match", "*")
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.CloseReception().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
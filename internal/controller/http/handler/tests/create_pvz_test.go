@@ -45,6 +45,45 @@ func TestCreatePVZ_Success(t *testing.T) {
 	assert.Equal(t, api.PVZCity("Москва"), resp.City)
 }
 
+func TestCreatePVZ_WithLocation(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	address := "ул. Баумана, 10"
+	capacity := 200
+
+	pvzMock.On("CreatePVZ", mock.Anything, mock.MatchedBy(func(p *models.PVZ) bool {
+		return p.Address == address && p.Capacity == capacity &&
+			p.Location != nil && *p.Location == models.GeoPoint{Latitude: 55.7887, Longitude: 49.1221}
+	})).Return(&models.PVZ{ID: uuid.New(), CityName: "Казань"}, nil)
+
+	reqBody := api.PostPvzJSONRequestBody{
+		City:     "Казань",
+		Address:  &address,
+		Location: &api.GeoPoint{Latitude: 55.7887, Longitude: 49.1221},
+		Capacity: &capacity,
+	}
+
+	req, rec := createRequest(http.MethodPost, "/pvz", reqBody)
+	handler.CreatePVZ().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	pvzMock.AssertExpectations(t)
+}
+
+func TestCreatePVZ_InvalidLocation(t *testing.T) {
+	_, _, handler := setupHandler(t)
+
+	reqBody := api.PostPvzJSONRequestBody{
+		City:     "Москва",
+		Location: &api.GeoPoint{Latitude: 95, Longitude: 37.6},
+	}
+
+	req, rec := createRequest(http.MethodPost, "/pvz", reqBody)
+	handler.CreatePVZ().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
 func TestCreatePVZ_ValidationError(t *testing.T) {
 	_, _, handler := setupHandler(t)
 
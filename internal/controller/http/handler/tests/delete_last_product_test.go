@@ -3,6 +3,7 @@ package tests
 import (
 	"net/http"
 	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
 	"testing"
 
 	"github.com/google/uuid"
@@ -14,13 +15,15 @@ func TestDeleteLastProduct_Success(t *testing.T) {
 	_, pvzMock, handler := setupHandler(t)
 
 	pvzID := uuid.New()
-	pvzMock.On("DeleteLastProduct", mock.Anything, pvzID).Return(nil)
+	etag := models.ETag{ID: uuid.New(), Version: 4}
+	pvzMock.On("DeleteLastProduct", mock.Anything, pvzID, (*models.ETag)(nil)).Return(etag, nil)
 
 	req, rec := createRequest(http.MethodDelete, "/pvz/"+pvzID.String()+"/products/last", nil)
 	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
 	handler.DeleteLastProduct().ServeHTTP(rec, req)
 
 	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, etag.String(), rec.Header().Get("ETag"))
 	assert.Equal(t, 0, rec.Body.Len()) // Check empty response
 }
 
@@ -28,7 +31,7 @@ func TestDeleteLastProduct_NoActiveReception(t *testing.T) {
 	_, pvzMock, handler := setupHandler(t)
 
 	pvzID := uuid.New()
-	pvzMock.On("DeleteLastProduct", mock.Anything, pvzID).Return(e.ErrNoActiveReception())
+	pvzMock.On("DeleteLastProduct", mock.Anything, pvzID, mock.Anything).Return(models.ETag{}, e.ErrNoActiveReception())
 
 	req, rec := createRequest(http.MethodDelete, "/pvz/"+pvzID.String()+"/products/last", nil)
 	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
@@ -41,7 +44,7 @@ func TestDeleteLastProduct_NoProducts(t *testing.T) {
 	_, pvzMock, handler := setupHandler(t)
 
 	pvzID := uuid.New()
-	pvzMock.On("DeleteLastProduct", mock.Anything, pvzID).Return(e.ErrNoProduct())
+	pvzMock.On("DeleteLastProduct", mock.Anything, pvzID, mock.Anything).Return(models.ETag{}, e.ErrNoProduct())
 
 	req, rec := createRequest(http.MethodDelete, "/pvz/"+pvzID.String()+"/products/last", nil)
 	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
@@ -59,3 +62,18 @@ func TestDeleteLastProduct_InvalidUUID(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 }
+
+func TestDeleteLastProduct_VersionMismatch(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzID := uuid.New()
+	ifMatch := models.ETag{ID: uuid.New(), Version: 1}
+	pvzMock.On("DeleteLastProduct", mock.Anything, pvzID, &ifMatch).Return(models.ETag{}, e.ErrVersionMismatch())
+
+	req, rec := createRequest(http.MethodDelete, "/pvz/"+pvzID.String()+"/products/last", nil)
+	req.Header.Set("If-Match", ifMatch.String())
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.DeleteLastProduct().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+}
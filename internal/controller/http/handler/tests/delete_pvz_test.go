@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"net/http"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeletePVZ_Success(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzID := uuid.New()
+	pvzMock.On("DeletePVZ", mock.Anything, pvzID, (*models.ETag)(nil)).Return(nil)
+
+	req, rec := createRequest(http.MethodDelete, "/pvz/"+pvzID.String(), nil)
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.DeletePVZ().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestDeletePVZ_ActiveReception(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzID := uuid.New()
+	pvzMock.On("DeletePVZ", mock.Anything, pvzID, mock.Anything).Return(e.ErrActiveReceptionExists())
+
+	req, rec := createRequest(http.MethodDelete, "/pvz/"+pvzID.String(), nil)
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.DeletePVZ().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestDeletePVZ_NotFound(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzID := uuid.New()
+	pvzMock.On("DeletePVZ", mock.Anything, pvzID, mock.Anything).Return(e.ErrNotFound())
+
+	req, rec := createRequest(http.MethodDelete, "/pvz/"+pvzID.String(), nil)
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.DeletePVZ().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestDeletePVZ_VersionMismatch(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzID := uuid.New()
+	ifMatch := models.ETag{ID: pvzID, Version: 7}
+	pvzMock.On("DeletePVZ", mock.Anything, pvzID, &ifMatch).Return(e.ErrVersionMismatch())
+
+	req, rec := createRequest(http.MethodDelete, "/pvz/"+pvzID.String(), nil)
+	req.Header.Set("If-Match", ifMatch.String())
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.DeletePVZ().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+}
+
+func TestDeletePVZ_InvalidUUID(t *testing.T) {
+	_, _, handler := setupHandler(t)
+
+	req, rec := createRequest(http.MethodDelete, "/pvz/invalid_uuid", nil)
+	req = addURLParams(req, map[string]string{"pvzId": "invalid_uuid"})
+	handler.DeletePVZ().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"pvz-service/internal/models"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetNearbyPVZs_Success(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	point := models.GeoPoint{Latitude: 55.7558, Longitude: 37.6173}
+	expected := []models.NearbyPVZ{
+		{
+			PVZ: models.PVZ{
+				ID:       uuid.New(),
+				CityName: "Москва",
+				Address:  "ул. Тверская, 1",
+				Location: &models.GeoPoint{Latitude: 55.7575, Longitude: 37.6132},
+				Status:   models.PVZStatusActive,
+			},
+			Distance: 297.4,
+		},
+	}
+
+	pvzMock.On("GetNearbyPVZs", mock.Anything, point, 1500.0, 5).Return(expected, nil)
+
+	req, rec := createRequest(http.MethodGet, "/pvz/nearby?lat=55.7558&lon=37.6173&radius=1500&limit=5", nil)
+	handler.GetNearbyPVZs().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp []models.NearbyPVZ
+	err := json.NewDecoder(rec.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp, 1)
+	assert.Equal(t, expected[0].PVZ.Location, resp[0].PVZ.Location)
+	assert.Equal(t, 297.4, resp[0].Distance)
+	pvzMock.AssertExpectations(t)
+}
+
+func TestGetNearbyPVZs_DefaultLimit(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzMock.On("GetNearbyPVZs", mock.Anything, models.GeoPoint{Latitude: 55.79, Longitude: 49.12}, 500.0, 20).
+		Return(nil, nil)
+
+	req, rec := createRequest(http.MethodGet, "/pvz/nearby?lat=55.79&lon=49.12&radius=500", nil)
+	handler.GetNearbyPVZs().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[]`, rec.Body.String())
+}
+
+func TestGetNearbyPVZs_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		message string
+	}{
+		{"Missing lat", "lon=37.6&radius=1000", "invalid lat param"},
+		{"Latitude out of range", "lat=91&lon=37.6&radius=1000", "invalid lat param"},
+		{"Longitude out of range", "lat=55.7&lon=-181&radius=1000", "invalid lon param"},
+		{"Zero radius", "lat=55.7&lon=37.6&radius=0", "invalid radius param"},
+		{"Radius too large", "lat=55.7&lon=37.6&radius=50001", "invalid radius param"},
+		{"Limit too large", "lat=55.7&lon=37.6&radius=1000&limit=101", "invalid limit param"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, handler := setupHandler(t)
+
+			req, rec := createRequest(http.MethodGet, "/pvz/nearby?"+tt.query, nil)
+			handler.GetNearbyPVZs().ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.message)
+		})
+	}
+}
+
+func TestGetNearbyPVZs_ServiceError(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzMock.On("GetNearbyPVZs", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, errors.New("db down"))
+
+	req, rec := createRequest(http.MethodGet, "/pvz/nearby?lat=55.7&lon=37.6&radius=1000", nil)
+	handler.GetNearbyPVZs().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,109 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetPVZEvents_Streams(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzID := uuid.New()
+	events := make(chan models.Event, 1)
+	unsubscribed := false
+	pvzMock.On("SubscribeEvents", mock.Anything, pvzID).
+		Return(events, func() { unsubscribed = true }, nil)
+
+	event := models.Event{
+		ID:          uuid.New(),
+		Type:        models.EventProductAdded,
+		PVZID:       pvzID,
+		ReceptionID: uuid.New(),
+		Payload:     json.RawMessage(`{"type":"обувь"}`),
+		CreatedAt:   time.Now(),
+	}
+	events <- event
+	// Closed channel ends the stream once the buffered event is sent
+	close(events)
+
+	req, rec := createRequest(http.MethodGet, "/pvz/"+pvzID.String()+"/events", nil)
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.GetPVZEvents().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	assert.True(t, unsubscribed)
+
+	body := rec.Body.String()
+	assert.Contains(t, body, "id: "+event.ID.String()+"\n")
+	assert.Contains(t, body, "event: product.added\n")
+
+	var dataLine string
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "data: ") {
+			dataLine = strings.TrimPrefix(line, "data: ")
+		}
+	}
+	var got models.Event
+	assert.NoError(t, json.Unmarshal([]byte(dataLine), &got))
+	assert.Equal(t, event.ID, got.ID)
+	assert.JSONEq(t, string(event.Payload), string(got.Payload))
+}
+
+func TestGetPVZEvents_Errors(t *testing.T) {
+	pvzID := uuid.New()
+
+	tests := []struct {
+		name           string
+		param          string
+		mockSetup      func(*MockPVZService)
+		expectedStatus int
+	}{
+		{
+			name:           "Invalid pvzId",
+			param:          "invalid",
+			mockSetup:      func(m *MockPVZService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "PVZ not found",
+			param: pvzID.String(),
+			mockSetup: func(m *MockPVZService) {
+				m.On("SubscribeEvents", mock.Anything, pvzID).Return(nil, nil, e.ErrNotFound())
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:  "Service error",
+			param: pvzID.String(),
+			mockSetup: func(m *MockPVZService) {
+				m.On("SubscribeEvents", mock.Anything, pvzID).Return(nil, nil, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, pvzMock, handler := setupHandler(t)
+			tt.mockSetup(pvzMock)
+
+			req, rec := createRequest(http.MethodGet, "/pvz/"+tt.param+"/events", nil)
+			req = addURLParams(req, map[string]string{"pvzId": tt.param})
+			handler.GetPVZEvents().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			pvzMock.AssertExpectations(t)
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"pvz-service/internal/controller/http/handler"
 	"pvz-service/internal/metrics"
 	"pvz-service/internal/models"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -64,23 +65,125 @@ func (m *MockPVZService) CreatePVZ(ctx context.Context, pvz *models.PVZ) (*model
 	return args.Get(0).(*models.PVZ), args.Error(1)
 }
 
-func (m *MockPVZService) StartReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
+func (m *MockPVZService) UpdatePVZ(ctx context.Context, pvzID uuid.UUID, update models.PVZUpdate, ifMatch *models.ETag) (*models.PVZ, error) {
+	args := m.Called(ctx, pvzID, update, ifMatch)
+	return args.Get(0).(*models.PVZ), args.Error(1)
+}
+
+func (m *MockPVZService) DeletePVZ(ctx context.Context, pvzID uuid.UUID, ifMatch *models.ETag) error {
+	args := m.Called(ctx, pvzID, ifMatch)
+	return args.Error(0)
+}
+
+func (m *MockPVZService) GetNearbyPVZs(ctx context.Context, point models.GeoPoint, radius float64, limit int) ([]models.NearbyPVZ, error) {
+	args := m.Called(ctx, point, radius, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.NearbyPVZ), args.Error(1)
+}
+
+func (m *MockPVZService) GetPVZSchedule(ctx context.Context, pvzID uuid.UUID) (*models.PVZSchedule, error) {
 	args := m.Called(ctx, pvzID)
-	return args.Get(0).(*models.Reception), args.Error(1)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PVZSchedule), args.Error(1)
 }
 
-func (m *MockPVZService) AddProduct(ctx context.Context, pvzID uuid.UUID, productTypeName string) (*models.Product, error) {
-	args := m.Called(ctx, pvzID, productTypeName)
-	return args.Get(0).(*models.Product), args.Error(1)
+func (m *MockPVZService) SetPVZSchedule(ctx context.Context, schedule *models.PVZSchedule) (*models.PVZSchedule, error) {
+	args := m.Called(ctx, schedule)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PVZSchedule), args.Error(1)
 }
 
-func (m *MockPVZService) DeleteLastProduct(ctx context.Context, pvzID uuid.UUID) error {
+func (m *MockPVZService) AllowReceptions(ctx context.Context, pvzID uuid.UUID, until time.Time) (*models.PVZSchedule, error) {
+	args := m.Called(ctx, pvzID, until)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PVZSchedule), args.Error(1)
+}
+
+func (m *MockPVZService) DeletePVZSchedule(ctx context.Context, pvzID uuid.UUID) error {
 	args := m.Called(ctx, pvzID)
 	return args.Error(0)
 }
 
-func (m *MockPVZService) CloseReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
+func (m *MockPVZService) GetPVZOccupancy(ctx context.Context, pvzID uuid.UUID) (*models.PVZOccupancy, error) {
 	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PVZOccupancy), args.Error(1)
+}
+
+func (m *MockPVZService) ListPVZOccupancy(ctx context.Context) ([]models.PVZOccupancy, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PVZOccupancy), args.Error(1)
+}
+
+func (m *MockPVZService) SetTypeCapacities(ctx context.Context, pvzID uuid.UUID, capacities []models.TypeCapacity) error {
+	args := m.Called(ctx, pvzID, capacities)
+	return args.Error(0)
+}
+
+func (m *MockPVZService) IssueProduct(ctx context.Context, productID uuid.UUID, comment string) (*models.Product, error) {
+	args := m.Called(ctx, productID, comment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockPVZService) ReturnProduct(ctx context.Context, productID uuid.UUID, comment string) (*models.Product, error) {
+	args := m.Called(ctx, productID, comment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockPVZService) WriteOffProduct(ctx context.Context, productID uuid.UUID, comment string) (*models.Product, error) {
+	args := m.Called(ctx, productID, comment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockPVZService) GetProductHistory(ctx context.Context, productID uuid.UUID) ([]models.ProductStatusChange, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ProductStatusChange), args.Error(1)
+}
+
+func (m *MockPVZService) StartReception(ctx context.Context, pvzID uuid.UUID, manifestID *uuid.UUID) (*models.Reception, error) {
+	args := m.Called(ctx, pvzID, manifestID)
+	return args.Get(0).(*models.Reception), args.Error(1)
+}
+
+func (m *MockPVZService) AddProduct(ctx context.Context, pvzID uuid.UUID, productTypeName, barcode string, ifMatch *models.ETag) (*models.Product, models.ETag, error) {
+	args := m.Called(ctx, pvzID, productTypeName, barcode, ifMatch)
+	etag, _ := args.Get(1).(models.ETag)
+	return args.Get(0).(*models.Product), etag, args.Error(2)
+}
+
+func (m *MockPVZService) DeleteLastProduct(ctx context.Context, pvzID uuid.UUID, ifMatch *models.ETag) (models.ETag, error) {
+	args := m.Called(ctx, pvzID, ifMatch)
+	etag, _ := args.Get(0).(models.ETag)
+	return etag, args.Error(1)
+}
+
+func (m *MockPVZService) CloseReception(ctx context.Context, pvzID uuid.UUID, ifMatch *models.ETag) (*models.Reception, error) {
+	args := m.Called(ctx, pvzID, ifMatch)
 	return args.Get(0).(*models.Reception), args.Error(1)
 }
 
@@ -94,16 +197,251 @@ func (m *MockPVZService) GetPVZs(ctx context.Context) ([]models.PVZ, error) {
 	return args.Get(0).([]models.PVZ), args.Error(1)
 }
 
+func (m *MockPVZService) StreamPVZs(ctx context.Context, filter models.PVZFilter, withReceptions, withProducts bool, send func(models.PVZInfo) error) error {
+	args := m.Called(ctx, filter, withReceptions, withProducts)
+	if list, ok := args.Get(0).([]models.PVZInfo); ok {
+		for _, info := range list {
+			if err := send(info); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockPVZService) SubscribeEvents(ctx context.Context, pvzID uuid.UUID) (<-chan models.Event, func(), error) {
+	args := m.Called(ctx, pvzID)
+	events, _ := args.Get(0).(chan models.Event)
+	unsubscribe, _ := args.Get(1).(func())
+	return events, unsubscribe, args.Error(2)
+}
+
+type MockTransferService struct {
+	mock.Mock
+}
+
+func (m *MockTransferService) CreateTransfer(ctx context.Context, sourcePVZID, destinationPVZID uuid.UUID, productIDs []uuid.UUID) (*models.Transfer, error) {
+	args := m.Called(ctx, sourcePVZID, destinationPVZID, productIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transfer), args.Error(1)
+}
+
+func (m *MockTransferService) GetTransfer(ctx context.Context, transferID uuid.UUID) (*models.Transfer, error) {
+	args := m.Called(ctx, transferID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transfer), args.Error(1)
+}
+
+func (m *MockTransferService) GetPVZTransfers(ctx context.Context, pvzID uuid.UUID, status models.TransferStatus) ([]models.Transfer, error) {
+	args := m.Called(ctx, pvzID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Transfer), args.Error(1)
+}
+
+func (m *MockTransferService) DispatchTransfer(ctx context.Context, transferID uuid.UUID) (*models.Transfer, error) {
+	args := m.Called(ctx, transferID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transfer), args.Error(1)
+}
+
+func (m *MockTransferService) ReceiveTransfer(ctx context.Context, transferID uuid.UUID) (*models.Transfer, error) {
+	args := m.Called(ctx, transferID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transfer), args.Error(1)
+}
+
+func (m *MockTransferService) CancelTransfer(ctx context.Context, transferID uuid.UUID) (*models.Transfer, error) {
+	args := m.Called(ctx, transferID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transfer), args.Error(1)
+}
+
+type MockManifestService struct {
+	mock.Mock
+}
+
+func (m *MockManifestService) CreateManifest(ctx context.Context, pvzID uuid.UUID, supplier string, items []models.ManifestItem) (*models.Manifest, error) {
+	args := m.Called(ctx, pvzID, supplier, items)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Manifest), args.Error(1)
+}
+
+func (m *MockManifestService) GetManifest(ctx context.Context, manifestID uuid.UUID) (*models.Manifest, error) {
+	args := m.Called(ctx, manifestID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Manifest), args.Error(1)
+}
+
+func (m *MockManifestService) GetPVZManifests(ctx context.Context, pvzID uuid.UUID, status models.ManifestStatus) ([]models.Manifest, error) {
+	args := m.Called(ctx, pvzID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Manifest), args.Error(1)
+}
+
+type MockProductConditionService struct {
+	mock.Mock
+}
+
+func (m *MockProductConditionService) SetProductCondition(ctx context.Context, productID uuid.UUID, condition models.ProductCondition, note string) (*models.Product, error) {
+	args := m.Called(ctx, productID, condition, note)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockProductConditionService) UploadProductPhoto(ctx context.Context, productID uuid.UUID, contentType string, data []byte) (*models.Product, error) {
+	args := m.Called(ctx, productID, contentType, data)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockProductConditionService) GetProductPhoto(ctx context.Context, productID uuid.UUID) (io.ReadCloser, string, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).(io.ReadCloser), args.String(1), args.Error(2)
+}
+
+type MockStaleReceptionService struct {
+	mock.Mock
+}
+
+func (m *MockStaleReceptionService) ForceCloseReception(ctx context.Context, pvzID uuid.UUID, actor, reason string) (*models.Reception, error) {
+	args := m.Called(ctx, pvzID, actor, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Reception), args.Error(1)
+}
+
+func (m *MockStaleReceptionService) CloseStaleReceptions(ctx context.Context, idleTimeout time.Duration, flagOnly bool) (int, error) {
+	args := m.Called(ctx, idleTimeout, flagOnly)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockStaleReceptionService) GetPVZReceptionAudit(ctx context.Context, pvzID uuid.UUID) ([]models.ReceptionAuditEntry, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ReceptionAuditEntry), args.Error(1)
+}
+
+type MockArchiveService struct {
+	mock.Mock
+}
+
+func (m *MockArchiveService) ArchiveReceptions(ctx context.Context, retentionMonths, batchSize int) (int, int, error) {
+	args := m.Called(ctx, retentionMonths, batchSize)
+	return args.Int(0), args.Int(1), args.Error(2)
+}
+
+func (m *MockArchiveService) GetArchivedReceptions(ctx context.Context, pvzID uuid.UUID, from, to time.Time, page, limit int) ([]models.ReceptionInfo, error) {
+	args := m.Called(ctx, pvzID, from, to, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ReceptionInfo), args.Error(1)
+}
+
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	args := m.Called(ctx, sub)
+	return args.Get(0).(*models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookService) GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookService) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) GetDeadLetters(ctx context.Context, subscriptionID uuid.UUID) ([]models.WebhookDelivery, error) {
+	args := m.Called(ctx, subscriptionID)
+	return args.Get(0).([]models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookService) Redeliver(ctx context.Context, deliveryID uuid.UUID) error {
+	args := m.Called(ctx, deliveryID)
+	return args.Error(0)
+}
+
+// serviceMocks holds the mocked services behind a handler from
+// setupHandlerWithMocks
+type serviceMocks struct {
+	auth             *MockAuthService
+	pvz              *MockPVZService
+	transfer         *MockTransferService
+	manifest         *MockManifestService
+	productCondition *MockProductConditionService
+	staleReception   *MockStaleReceptionService
+	archive          *MockArchiveService
+	webhook          *MockWebhookService
+}
+
 func setupHandler(t *testing.T) (*MockAuthService, *MockPVZService, *handler.Handler) {
+	t.Helper()
+	mocks, handler := setupHandlerWithMocks(t)
+	return mocks.auth, mocks.pvz, handler
+}
+
+func setupHandlerWithWebhooks(t *testing.T) (*MockAuthService, *MockPVZService, *MockWebhookService, *handler.Handler) {
+	t.Helper()
+	mocks, handler := setupHandlerWithMocks(t)
+	return mocks.auth, mocks.pvz, mocks.webhook, handler
+}
+
+func setupHandlerWithMocks(t *testing.T) (*serviceMocks, *handler.Handler) {
 	t.Helper()
 	log := slog.New(slog.NewTextHandler(io.Discard, nil))
 	metricsOnce.Do(func() {
 		testMetrics = metrics.NewMetrics()
 	})
-	authMock := new(MockAuthService)
-	pvzMock := new(MockPVZService)
-	var handler = handler.NewHandler(log, testMetrics, authMock, pvzMock)
-	return authMock, pvzMock, handler
+	mocks := &serviceMocks{
+		auth:             new(MockAuthService),
+		pvz:              new(MockPVZService),
+		transfer:         new(MockTransferService),
+		manifest:         new(MockManifestService),
+		productCondition: new(MockProductConditionService),
+		staleReception:   new(MockStaleReceptionService),
+		archive:          new(MockArchiveService),
+		webhook:          new(MockWebhookService),
+	}
+	var handler = handler.NewHandler(
+		log, testMetrics,
+		mocks.auth, mocks.pvz, mocks.transfer, mocks.manifest, mocks.productCondition,
+		mocks.staleReception, mocks.archive, mocks.webhook,
+	)
+	return mocks, handler
 }
 
 func createRequest(method, url string, body interface{}) (*http.Request, *httptest.ResponseRecorder) {
@@ -116,6 +454,14 @@ func createRequest(method, url string, body interface{}) (*http.Request, *httpte
 	return req, rec
 }
 
+// createRawRequest is createRequest for bodies that cannot be built from the
+// generated types, e.g. enum values the spec rejects
+func createRawRequest(method, url, body string) (*http.Request, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, url, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	return req, rec
+}
+
 func addURLParams(r *http.Request, params map[string]string) *http.Request {
 	ctx := chi.NewRouteContext()
 	for key, val := range params {
@@ -8,6 +8,7 @@ import (
 	api "pvz-service/api/generated"
 	"pvz-service/internal/config"
 	"pvz-service/internal/controller/http/handler"
+	"pvz-service/internal/eventbus"
 	"pvz-service/internal/metrics"
 	"pvz-service/internal/models"
 	"pvz-service/internal/repository"
@@ -43,31 +44,38 @@ func TestFullPVZWorkflow(t *testing.T) {
 	}
 
 	// Init repo
-	originalPostgresGetter := repository.PostgresGetter
-	repository.PostgresGetter = func(cfg *config.Config) (interface{}, error) {
-		p := &postgres.Postgres{}
-		p.SetRepository(db)
-		return p, nil
-	}
-	defer func() { repository.PostgresGetter = originalPostgresGetter }()
+	repo := &postgres.Postgres{}
+	repo.SetRepository(db)
+
+	authRepo, err := repository.CreateAuthRepo(repo)
+	require.NoError(t, err)
 
-	authRepo, err := repository.CreateAuthRepo(cfg, log)
+	pvzRepo, err := repository.CreatePVZRepo(repo)
 	require.NoError(t, err)
-	defer authRepo.CloseConnection()
 
-	pvzRepo, err := repository.CreatePVZRepo(cfg, log)
+	webhookRepo, err := repository.CreateWebhookRepo(repo)
 	require.NoError(t, err)
-	defer pvzRepo.CloseConnection()
 
 	// Create services
 	metricsOnce.Do(func() {
 		testMetrics = metrics.NewMetrics()
 	})
 	authService := service.NewAuthService(authRepo, cfg, log)
-	pvzService := service.NewPVZService(pvzRepo, log)
+	bus := eventbus.NewBus()
+	pvzService := service.NewPVZService(pvzRepo, nil, bus, log)
+	transferService := service.NewTransferService(pvzRepo, bus, log)
+	manifestService := service.NewManifestService(pvzRepo, log)
+	productConditionService := service.NewProductConditionService(pvzRepo, nil, bus, log)
+	staleReceptionService := service.NewStaleReceptionService(pvzRepo, bus, log)
+	archiveService := service.NewArchiveService(pvzRepo, nil, log)
+	webhookService := service.NewWebhookService(webhookRepo, log)
 
 	// Create handler
-	h := handler.NewHandler(log, testMetrics, authService, pvzService)
+	h := handler.NewHandler(
+		log, testMetrics,
+		authService, pvzService, transferService, manifestService, productConditionService,
+		staleReceptionService, archiveService, webhookService,
+	)
 
 	// Test data
 	pvzID := uuid.New()
@@ -82,14 +90,17 @@ func TestFullPVZWorkflow(t *testing.T) {
 			WithArgs("Москва").
 			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(cityID))
 
-		mock.ExpectExec("INSERT INTO pvz \\(id, registration_date, city_id\\) VALUES \\(\\$1, \\$2, \\$3\\)").
-			WithArgs(pvzID, sqlmock.AnyArg(), cityID).
+		address := "ул. Тверская, 1"
+		mock.ExpectExec("INSERT INTO pvz \\(id, registration_date, city_id, address, latitude, longitude, capacity\\)").
+			WithArgs(pvzID, sqlmock.AnyArg(), cityID, "ул. Тверская, 1", 55.7575, 37.6132, 0).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		reqBody := api.PostPvzJSONRequestBody{
 			City:             "Москва",
 			Id:               &pvzID,
 			RegistrationDate: &regDate,
+			Address:          &address,
+			Location:         &api.GeoPoint{Latitude: 55.7575, Longitude: 37.6132},
 		}
 
 		req, rec := createRequest(http.MethodPost, "/pvz", reqBody)
@@ -100,17 +111,27 @@ func TestFullPVZWorkflow(t *testing.T) {
 
 	// Test: Create reception
 	t.Run("Start Reception", func(t *testing.T) {
-		mock.ExpectQuery("SELECT EXISTS\\(SELECT 1 FROM pvz WHERE id = \\$1\\)").
+		mock.ExpectQuery("SELECT (.+) FROM pvz p JOIN cities c ON p.city_id = c.id WHERE p.id = \\$1 AND p.deleted_at IS NULL").
 			WithArgs(pvzID).
-			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city_id", "name", "address", "latitude", "longitude", "capacity", "status", "deleted_at", "version"}).
+				AddRow(pvzID, time.Now(), 1, "Москва", "ул. Тверская, 1", 55.7575, 37.6132, 0, models.PVZStatusActive, nil, 1))
+
+		mock.ExpectQuery("SELECT pvz_id, timezone, weekly, exceptions, receptions_allowed_until, updated_at FROM pvz_schedules WHERE pvz_id = \\$1").
+			WithArgs(pvzID).
+			WillReturnError(sql.ErrNoRows)
 
-		mock.ExpectQuery("SELECT id, date_time, pvz_id, status FROM receptions WHERE pvz_id = \\$1 AND status = 'in_progress' ORDER BY date_time DESC LIMIT 1").
+		mock.ExpectQuery("SELECT r.id, r.date_time, r.pvz_id, r.status, r.version, m.id FROM receptions r LEFT JOIN manifests m ON m.reception_id = r.id WHERE r.pvz_id = \\$1 AND r.status = 'in_progress' ORDER BY r.date_time DESC LIMIT 1").
 			WithArgs(pvzID).
 			WillReturnError(sql.ErrNoRows)
 
+		mock.ExpectBegin()
 		mock.ExpectExec("INSERT INTO receptions \\(id, date_time, pvz_id, status\\) VALUES \\(\\$1, \\$2, \\$3, \\$4\\)").
 			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), pvzID, models.ReceptionStatusInProgress).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO outbox").
+			WithArgs(sqlmock.AnyArg(), models.EventReceptionOpened, pvzID, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
 
 		reqBody := api.PostReceptionsJSONRequestBody{
 			PvzId: pvzID,
@@ -126,19 +147,39 @@ func TestFullPVZWorkflow(t *testing.T) {
 	t.Run("Add 50 Products", func(t *testing.T) {
 		for i := 0; i < 50; i++ {
 
-			mock.ExpectQuery("SELECT id, date_time, pvz_id, status FROM receptions WHERE pvz_id = \\$1 AND status = 'in_progress' ORDER BY date_time DESC LIMIT 1").
+			mock.ExpectQuery("SELECT r.id, r.date_time, r.pvz_id, r.status, r.version, m.id FROM receptions r LEFT JOIN manifests m ON m.reception_id = r.id WHERE r.pvz_id = \\$1 AND r.status = 'in_progress' ORDER BY r.date_time DESC LIMIT 1").
 				WithArgs(pvzID).
 				WillReturnRows(
-					sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}).
-						AddRow(receptionID, time.Now(), pvzID, models.ReceptionStatusInProgress))
+					sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status", "version", "manifest_id"}).
+						AddRow(receptionID, time.Now(), pvzID, models.ReceptionStatusInProgress, i+1, nil))
 
 			mock.ExpectQuery("SELECT id FROM product_types WHERE name = \\$1").
 				WithArgs("одежда").
 				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(productTypeID))
 
-			mock.ExpectExec("INSERT INTO products \\(id, date_time, type_id, reception_id\\) VALUES \\(\\$1, \\$2, \\$3, \\$4\\)").
-				WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), productTypeID, receptionID).
+			mock.ExpectBegin()
+			mock.ExpectQuery("UPDATE receptions SET version = version \\+ 1 WHERE id = \\$1 AND version = \\$2 RETURNING pvz_id, version").
+				WithArgs(receptionID, i+1).
+				WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "version"}).AddRow(pvzID, i+2))
+			mock.ExpectQuery("SELECT p.capacity, COALESCE\\(tc.capacity, 0\\)").
+				WithArgs(pvzID, productTypeID).
+				WillReturnRows(sqlmock.NewRows([]string{"capacity", "type_capacity", "count", "type_count"}).AddRow(0, 0, i, i))
+			mock.ExpectExec("INSERT INTO pvz_occupancy").
+				WithArgs(pvzID, productTypeID, 1).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectExec("INSERT INTO product_keys \\(id, date_time\\) VALUES \\(\\$1, \\$2\\)").
+				WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectExec("INSERT INTO products \\(id, date_time, type_id, reception_id, status, barcode\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, NULLIF\\(\\$6, ''\\)\\)").
+				WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), productTypeID, receptionID, models.ProductStatusAccepted, "").
 				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectExec("INSERT INTO product_status_history").
+				WithArgs(sqlmock.AnyArg(), pvzID, receptionID, sqlmock.AnyArg(), models.ProductStatusAccepted, "", sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectExec("INSERT INTO outbox").
+				WithArgs(sqlmock.AnyArg(), models.EventProductAdded, pvzID, receptionID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectCommit()
 		}
 
 		for i := 0; i < 50; i++ {
@@ -156,15 +197,28 @@ func TestFullPVZWorkflow(t *testing.T) {
 
 	// Test: Close reception
 	t.Run("Close Reception", func(t *testing.T) {
-		mock.ExpectQuery("SELECT id, date_time, pvz_id, status FROM receptions WHERE pvz_id = \\$1 AND status = 'in_progress' ORDER BY date_time DESC LIMIT 1").
+		mock.ExpectQuery("SELECT r.id, r.date_time, r.pvz_id, r.status, r.version, m.id FROM receptions r LEFT JOIN manifests m ON m.reception_id = r.id WHERE r.pvz_id = \\$1 AND r.status = 'in_progress' ORDER BY r.date_time DESC LIMIT 1").
 			WithArgs(pvzID).
 			WillReturnRows(
-				sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}).
-					AddRow(receptionID, time.Now(), pvzID, models.ReceptionStatusInProgress))
+				sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status", "version", "manifest_id"}).
+					AddRow(receptionID, time.Now(), pvzID, models.ReceptionStatusInProgress, 51, nil))
 
-		mock.ExpectExec("UPDATE receptions SET status = \\$1 WHERE id = \\$2").
-			WithArgs(models.ReceptionStatusClose, receptionID).
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE receptions SET status = \\$1, version = version \\+ 1 WHERE id = \\$2 AND version = \\$3 RETURNING id, date_time, pvz_id, status, version").
+			WithArgs(models.ReceptionStatusClose, receptionID, 51).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status", "version"}).
+					AddRow(receptionID, time.Now(), pvzID, models.ReceptionStatusClose, 52))
+		mock.ExpectExec("WITH stored AS \\( UPDATE products SET status = \\$2").
+			WithArgs(receptionID, models.ProductStatusStored, models.ProductStatusAccepted, pvzID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 50))
+		mock.ExpectQuery("SELECT id FROM manifests WHERE reception_id = \\$1").
+			WithArgs(receptionID, models.ManifestStatusReceiving).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectExec("INSERT INTO outbox").
+			WithArgs(sqlmock.AnyArg(), models.EventReceptionClosed, pvzID, receptionID, sqlmock.AnyArg(), sqlmock.AnyArg()).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
 
 		req, rec := createRequest(http.MethodPost, "/pvz/"+pvzID.String()+"/receptions/close", nil)
 		req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
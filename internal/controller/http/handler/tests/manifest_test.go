@@ -0,0 +1,185 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	api "pvz-service/api/generated"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateManifest_Success(t *testing.T) {
+	mocks, handler := setupHandlerWithMocks(t)
+
+	pvzID := uuid.New()
+	items := []models.ManifestItem{
+		{Barcode: "4600000000017", TypeName: "электроника"},
+		{Barcode: "4600000000024", TypeName: "одежда"},
+	}
+	mocks.manifest.On("CreateManifest", mock.Anything, pvzID, "ООО Поставщик", items).Return(&models.Manifest{
+		ID:        uuid.New(),
+		PVZID:     pvzID,
+		Supplier:  "ООО Поставщик",
+		Status:    models.ManifestStatusPending,
+		Items:     items,
+		CreatedAt: time.Now(),
+	}, nil)
+
+	req, rec := createRequest(http.MethodPost, "/manifests", api.ManifestCreate{
+		PvzId:    pvzID,
+		Supplier: "ООО Поставщик",
+		Items: []api.ManifestItem{
+			{Barcode: "4600000000017", Type: api.ManifestItemTypeЭлектроника},
+			{Barcode: "4600000000024", Type: api.ManifestItemTypeОдежда},
+		},
+	})
+	handler.CreateManifest().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var resp api.Manifest
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "pending", resp.Status)
+	assert.Len(t, resp.Items, 2)
+	assert.Equal(t, api.ManifestItemTypeОдежда, resp.Items[1].Type)
+	mocks.manifest.AssertExpectations(t)
+}
+
+func TestCreateManifest_InvalidRequest(t *testing.T) {
+	pvzID := uuid.New()
+
+	tests := []struct {
+		name string
+		body api.ManifestCreate
+	}{
+		{
+			name: "No items",
+			body: api.ManifestCreate{PvzId: pvzID, Supplier: "ООО Поставщик", Items: []api.ManifestItem{}},
+		},
+		{
+			name: "No supplier",
+			body: api.ManifestCreate{PvzId: pvzID, Items: []api.ManifestItem{{Barcode: "A1", Type: api.ManifestItemTypeОбувь}}},
+		},
+		{
+			name: "Duplicate barcodes",
+			body: api.ManifestCreate{PvzId: pvzID, Supplier: "ООО Поставщик", Items: []api.ManifestItem{
+				{Barcode: "A1", Type: api.ManifestItemTypeОбувь},
+				{Barcode: "A1", Type: api.ManifestItemTypeОдежда},
+			}},
+		},
+		{
+			name: "Empty barcode",
+			body: api.ManifestCreate{PvzId: pvzID, Supplier: "ООО Поставщик", Items: []api.ManifestItem{{Type: api.ManifestItemTypeОбувь}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks, handler := setupHandlerWithMocks(t)
+
+			req, rec := createRequest(http.MethodPost, "/manifests", tt.body)
+			handler.CreateManifest().ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			mocks.manifest.AssertNotCalled(t, "CreateManifest", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCreateManifest_PVZNotFound(t *testing.T) {
+	mocks, handler := setupHandlerWithMocks(t)
+
+	mocks.manifest.On("CreateManifest", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, e.ErrNotFound())
+
+	req, rec := createRequest(http.MethodPost, "/manifests", api.ManifestCreate{
+		PvzId:    uuid.New(),
+		Supplier: "ООО Поставщик",
+		Items:    []api.ManifestItem{{Barcode: "A1", Type: api.ManifestItemTypeОбувь}},
+	})
+	handler.CreateManifest().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestGetManifest_WithReport(t *testing.T) {
+	mocks, handler := setupHandlerWithMocks(t)
+
+	manifestID := uuid.New()
+	receptionID := uuid.New()
+	mocks.manifest.On("GetManifest", mock.Anything, manifestID).Return(&models.Manifest{
+		ID:          manifestID,
+		Status:      models.ManifestStatusReconciled,
+		ReceptionID: &receptionID,
+		Items:       []models.ManifestItem{{Barcode: "A1", TypeName: "обувь"}},
+		Report: &models.DiscrepancyReport{
+			Expected:   1,
+			Received:   2,
+			Missing:    []models.ManifestItem{},
+			Unexpected: []string{"Z9"},
+			Duplicates: []models.DuplicateItem{},
+		},
+	}, nil)
+
+	req, rec := createRequest(http.MethodGet, "/manifests/"+manifestID.String(), nil)
+	req = addURLParams(req, map[string]string{"manifestId": manifestID.String()})
+	handler.GetManifest().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp api.Manifest
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, receptionID, *resp.ReceptionId)
+	if assert.NotNil(t, resp.Report) {
+		assert.Equal(t, []string{"Z9"}, resp.Report.Unexpected)
+	}
+}
+
+func TestGetManifest_NotFound(t *testing.T) {
+	mocks, handler := setupHandlerWithMocks(t)
+
+	manifestID := uuid.New()
+	mocks.manifest.On("GetManifest", mock.Anything, manifestID).Return(nil, e.ErrNotFound())
+
+	req, rec := createRequest(http.MethodGet, "/manifests/"+manifestID.String(), nil)
+	req = addURLParams(req, map[string]string{"manifestId": manifestID.String()})
+	handler.GetManifest().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestGetPVZManifests(t *testing.T) {
+	mocks, handler := setupHandlerWithMocks(t)
+
+	pvzID := uuid.New()
+	mocks.manifest.On("GetPVZManifests", mock.Anything, pvzID, models.ManifestStatusPending).
+		Return([]models.Manifest{{ID: uuid.New(), PVZID: pvzID, Status: models.ManifestStatusPending}}, nil)
+
+	req, rec := createRequest(http.MethodGet, "/pvz/"+pvzID.String()+"/manifests?status=pending", nil)
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.GetPVZManifests().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp []api.Manifest
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Len(t, resp, 1)
+	mocks.manifest.AssertExpectations(t)
+}
+
+func TestGetPVZManifests_InvalidStatus(t *testing.T) {
+	mocks, handler := setupHandlerWithMocks(t)
+
+	pvzID := uuid.New()
+	req, rec := createRequest(http.MethodGet, "/pvz/"+pvzID.String()+"/manifests?status=lost", nil)
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.GetPVZManifests().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mocks.manifest.AssertNotCalled(t, "GetPVZManifests", mock.Anything, mock.Anything, mock.Anything)
+}
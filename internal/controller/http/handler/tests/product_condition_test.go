@@ -0,0 +1,169 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	api "pvz-service/api/generated"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// pngHeader is enough of a PNG file for content sniffing
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestSetProductCondition_Success(t *testing.T) {
+	mocks, handler := setupHandlerWithMocks(t)
+
+	productID := uuid.New()
+	note := "разбита упаковка"
+	mocks.productCondition.On("SetProductCondition", mock.Anything, productID, models.ProductConditionDamaged, note).Return(&models.Product{
+		ID:            productID,
+		Status:        models.ProductStatusAccepted,
+		Condition:     models.ProductConditionDamaged,
+		ConditionNote: note,
+	}, nil)
+
+	req, rec := createRequest(http.MethodPut, "/products/"+productID.String()+"/condition", api.ProductConditionRequest{
+		Condition: api.Damaged,
+		Note:      &note,
+	})
+	req = addURLParams(req, map[string]string{"productId": productID.String()})
+	handler.SetProductCondition().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp api.Product
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, api.Damaged, *resp.Condition)
+	assert.Equal(t, note, *resp.ConditionNote)
+	assert.False(t, *resp.HasPhoto)
+	mocks.productCondition.AssertExpectations(t)
+}
+
+func TestSetProductCondition_InvalidCondition(t *testing.T) {
+	mocks, handler := setupHandlerWithMocks(t)
+
+	productID := uuid.New()
+	req, rec := createRequest(http.MethodPut, "/products/"+productID.String()+"/condition", map[string]string{"condition": "lost"})
+	req = addURLParams(req, map[string]string{"productId": productID.String()})
+	handler.SetProductCondition().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mocks.productCondition.AssertNotCalled(t, "SetProductCondition", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSetProductCondition_NotInReception(t *testing.T) {
+	mocks, handler := setupHandlerWithMocks(t)
+
+	productID := uuid.New()
+	mocks.productCondition.On("SetProductCondition", mock.Anything, productID, models.ProductConditionOK, "").Return(nil, e.ErrProductNotInReception())
+
+	req, rec := createRequest(http.MethodPut, "/products/"+productID.String()+"/condition", api.ProductConditionRequest{Condition: api.Ok})
+	req = addURLParams(req, map[string]string{"productId": productID.String()})
+	handler.SetProductCondition().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var resp api.Error
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "product is not in an open reception", resp.Message)
+}
+
+func TestUploadProductPhoto(t *testing.T) {
+	productID := uuid.New()
+
+	tests := []struct {
+		name         string
+		body         []byte
+		mockSetup    func(*MockProductConditionService)
+		expectedCode int
+	}{
+		{
+			name: "Success",
+			body: pngHeader,
+			mockSetup: func(m *MockProductConditionService) {
+				m.On("UploadProductPhoto", mock.Anything, productID, "image/png", pngHeader).
+					Return(&models.Product{ID: productID, HasPhoto: true}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Empty body",
+			body:         nil,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Not an image",
+			body:         []byte("just some text"),
+			expectedCode: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:         "Too large",
+			body:         append(append([]byte{}, pngHeader...), make([]byte, 10<<20)...),
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "Reception closed",
+			body: pngHeader,
+			mockSetup: func(m *MockProductConditionService) {
+				m.On("UploadProductPhoto", mock.Anything, productID, "image/png", pngHeader).Return(nil, e.ErrProductNotInReception())
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks, handler := setupHandlerWithMocks(t)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mocks.productCondition)
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/products/"+productID.String()+"/photo", bytes.NewReader(tt.body))
+			req = addURLParams(req, map[string]string{"productId": productID.String()})
+			rec := httptest.NewRecorder()
+			handler.UploadProductPhoto().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.mockSetup == nil {
+				mocks.productCondition.AssertNotCalled(t, "UploadProductPhoto", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestGetProductPhoto(t *testing.T) {
+	mocks, handler := setupHandlerWithMocks(t)
+
+	productID := uuid.New()
+	mocks.productCondition.On("GetProductPhoto", mock.Anything, productID).Return(io.NopCloser(bytes.NewReader(pngHeader)), "image/png", nil)
+
+	req, rec := createRequest(http.MethodGet, "/products/"+productID.String()+"/photo", nil)
+	req = addURLParams(req, map[string]string{"productId": productID.String()})
+	handler.GetProductPhoto().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+	assert.Equal(t, pngHeader, rec.Body.Bytes())
+}
+
+func TestGetProductPhoto_NotFound(t *testing.T) {
+	mocks, handler := setupHandlerWithMocks(t)
+
+	productID := uuid.New()
+	mocks.productCondition.On("GetProductPhoto", mock.Anything, productID).Return(nil, "", e.ErrNotFound())
+
+	req, rec := createRequest(http.MethodGet, "/products/"+productID.String()+"/photo", nil)
+	req = addURLParams(req, map[string]string{"productId": productID.String()})
+	handler.GetProductPhoto().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	api "pvz-service/api/generated"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIssueProduct_Success(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	productID := uuid.New()
+	pvzMock.On("IssueProduct", mock.Anything, productID, "").Return(&models.Product{
+		ID:          productID,
+		DateTime:    time.Now(),
+		TypeName:    "обувь",
+		ReceptionID: uuid.New(),
+		Status:      models.ProductStatusIssued,
+	}, nil)
+
+	req, rec := createRequest(http.MethodPost, "/products/"+productID.String()+"/issue", nil)
+	req = addURLParams(req, map[string]string{"productId": productID.String()})
+	handler.IssueProduct().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp api.Product
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, api.Issued, *resp.Status)
+	pvzMock.AssertExpectations(t)
+}
+
+func TestReturnProduct_WithComment(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	productID := uuid.New()
+	pvzMock.On("ReturnProduct", mock.Anything, productID, "не подошел размер").Return(&models.Product{
+		ID:     productID,
+		Status: models.ProductStatusReturned,
+	}, nil)
+
+	req, rec := createRawRequest(http.MethodPost, "/products/"+productID.String()+"/return", `{"comment": "не подошел размер"}`)
+	req = addURLParams(req, map[string]string{"productId": productID.String()})
+	handler.ReturnProduct().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	pvzMock.AssertExpectations(t)
+}
+
+func TestChangeProductStatus_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    int
+		message string
+	}{
+		{name: "Product not found", err: e.ErrNotFound(), code: http.StatusNotFound, message: "product not found"},
+		{name: "Invalid transition", err: e.ErrInvalidStatusTransition(), code: http.StatusBadRequest, message: "invalid product status transition"},
+		{name: "Internal error", err: assert.AnError, code: http.StatusInternalServerError, message: "failed to change product status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, pvzMock, handler := setupHandler(t)
+
+			productID := uuid.New()
+			pvzMock.On("WriteOffProduct", mock.Anything, productID, "").Return(nil, tt.err)
+
+			req, rec := createRequest(http.MethodPost, "/products/"+productID.String()+"/write_off", nil)
+			req = addURLParams(req, map[string]string{"productId": productID.String()})
+			handler.WriteOffProduct().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.code, rec.Code)
+
+			var resp api.Error
+			assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, tt.message, resp.Message)
+		})
+	}
+}
+
+func TestIssueProduct_InvalidID(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	req, rec := createRequest(http.MethodPost, "/products/not-a-uuid/issue", nil)
+	req = addURLParams(req, map[string]string{"productId": "not-a-uuid"})
+	handler.IssueProduct().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	pvzMock.AssertNotCalled(t, "IssueProduct", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetProductHistory_Success(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	productID := uuid.New()
+	pvzID := uuid.New()
+	pvzMock.On("GetProductHistory", mock.Anything, productID).Return([]models.ProductStatusChange{
+		{ProductID: productID, PVZID: pvzID, To: models.ProductStatusAccepted, ChangedAt: time.Now()},
+		{ProductID: productID, PVZID: pvzID, From: models.ProductStatusAccepted, To: models.ProductStatusStored, ChangedAt: time.Now()},
+	}, nil)
+
+	req, rec := createRequest(http.MethodGet, "/products/"+productID.String()+"/history", nil)
+	req = addURLParams(req, map[string]string{"productId": productID.String()})
+	handler.GetProductHistory().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp []api.ProductStatusChange
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Len(t, resp, 2)
+	assert.Nil(t, resp[0].From)
+	assert.Equal(t, "accepted", *resp[1].From)
+	assert.Equal(t, "stored", resp[1].To)
+}
+
+func TestGetProductHistory_NotFound(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	productID := uuid.New()
+	pvzMock.On("GetProductHistory", mock.Anything, productID).Return(nil, e.ErrNotFound())
+
+	req, rec := createRequest(http.MethodGet, "/products/"+productID.String()+"/history", nil)
+	req = addURLParams(req, map[string]string{"productId": productID.String()})
+	handler.GetProductHistory().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
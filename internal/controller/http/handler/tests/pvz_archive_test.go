@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetPVZArchive_Success(t *testing.T) {
+	mocks, handler := setupHandlerWithMocks(t)
+
+	pvzID := uuid.New()
+	startDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	reception := models.Reception{ID: uuid.New(), DateTime: startDate.Add(time.Hour), PVZID: pvzID, Status: models.ReceptionStatusClose}
+	mocks.archive.On("GetArchivedReceptions", mock.Anything, pvzID, startDate, endDate, 2, 5).Return([]models.ReceptionInfo{{
+		Reception: reception,
+		Products:  []models.Product{{ID: uuid.New(), ReceptionID: reception.ID, TypeName: "электроника", Status: models.ProductStatusIssued}},
+	}}, nil)
+
+	req, rec := createRequest(http.MethodGet,
+		"/pvz/"+pvzID.String()+"/archive?startDate=2024-01-01T00:00:00Z&endDate=2024-12-31T00:00:00Z&page=2&limit=5", nil)
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.GetPVZArchive().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp []struct {
+		Reception struct {
+			ID     uuid.UUID `json:"id"`
+			Status string    `json:"status"`
+		} `json:"reception"`
+		Products []struct {
+			Status string `json:"status"`
+		} `json:"products"`
+	}
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	if assert.Len(t, resp, 1) {
+		assert.Equal(t, reception.ID, resp[0].Reception.ID)
+		assert.Equal(t, "close", resp[0].Reception.Status)
+		if assert.Len(t, resp[0].Products, 1) {
+			assert.Equal(t, "issued", resp[0].Products[0].Status)
+		}
+	}
+}
+
+func TestGetPVZArchive_Defaults(t *testing.T) {
+	mocks, handler := setupHandlerWithMocks(t)
+
+	pvzID := uuid.New()
+	mocks.archive.On("GetArchivedReceptions", mock.Anything, pvzID, time.Time{}, mock.AnythingOfType("time.Time"), 1, 10).
+		Return([]models.ReceptionInfo{}, nil)
+
+	req, rec := createRequest(http.MethodGet, "/pvz/"+pvzID.String()+"/archive", nil)
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.GetPVZArchive().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, "[]", rec.Body.String())
+}
+
+func TestGetPVZArchive_InvalidParams(t *testing.T) {
+	pvzID := uuid.New().String()
+	tests := []struct {
+		name  string
+		pvzID string
+		query string
+	}{
+		{"invalid pvz id", "not-a-uuid", ""},
+		{"invalid startDate", pvzID, "?startDate=yesterday"},
+		{"invalid endDate", pvzID, "?endDate=2024-01-01"},
+		{"invalid page", pvzID, "?page=0"},
+		{"limit too large", pvzID, "?limit=31"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks, handler := setupHandlerWithMocks(t)
+
+			req, rec := createRequest(http.MethodGet, "/pvz/"+tt.pvzID+"/archive"+tt.query, nil)
+			req = addURLParams(req, map[string]string{"pvzId": tt.pvzID})
+			handler.GetPVZArchive().ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			mocks.archive.AssertNotCalled(t, "GetArchivedReceptions", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetPVZArchive_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"pvz not found", e.ErrNotFound(), http.StatusNotFound},
+		{"service error", errors.New("db error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks, handler := setupHandlerWithMocks(t)
+
+			pvzID := uuid.New()
+			mocks.archive.On("GetArchivedReceptions", mock.Anything, pvzID, mock.Anything, mock.Anything, 1, 10).Return(nil, tt.err)
+
+			req, rec := createRequest(http.MethodGet, "/pvz/"+pvzID.String()+"/archive", nil)
+			req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+			handler.GetPVZArchive().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.code, rec.Code)
+		})
+	}
+}
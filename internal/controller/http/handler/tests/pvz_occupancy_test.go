@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	api "pvz-service/api/generated"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetPVZOccupancy_Success(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzID := uuid.New()
+	pvzMock.On("GetPVZOccupancy", mock.Anything, pvzID).Return(&models.PVZOccupancy{
+		PVZID:    pvzID,
+		Count:    7,
+		Capacity: 10,
+		Types: []models.TypeOccupancy{
+			{TypeName: "электроника", Count: 2},
+			{TypeName: "обувь", Count: 5, Capacity: 5},
+		},
+	}, nil)
+
+	req, rec := createRequest(http.MethodGet, "/pvz/"+pvzID.String()+"/occupancy", nil)
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.GetPVZOccupancy().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp api.PVZOccupancy
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, 7, resp.Count)
+	assert.Equal(t, 10, *resp.Capacity)
+	assert.Len(t, *resp.Types, 2)
+	assert.Nil(t, (*resp.Types)[0].Capacity)
+	assert.Equal(t, 5, *(*resp.Types)[1].Capacity)
+}
+
+func TestGetPVZOccupancy_NotFound(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzID := uuid.New()
+	pvzMock.On("GetPVZOccupancy", mock.Anything, pvzID).Return(nil, e.ErrNotFound())
+
+	req, rec := createRequest(http.MethodGet, "/pvz/"+pvzID.String()+"/occupancy", nil)
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.GetPVZOccupancy().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestSetTypeCapacities_Success(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzID := uuid.New()
+	pvzMock.On("SetTypeCapacities", mock.Anything, pvzID, []models.TypeCapacity{
+		{TypeName: "обувь", Capacity: 20},
+		{TypeName: "одежда", Capacity: 50},
+	}).Return(nil)
+
+	body := `{"capacities": [{"type": "обувь", "capacity": 20}, {"type": "одежда", "capacity": 50}]}`
+	req, rec := createRawRequest(http.MethodPut, "/pvz/"+pvzID.String()+"/capacities", body)
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.SetTypeCapacities().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	pvzMock.AssertExpectations(t)
+}
+
+func TestSetTypeCapacities_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "Unknown type", body: `{"capacities": [{"type": "мебель", "capacity": 5}]}`},
+		{name: "Zero capacity", body: `{"capacities": [{"type": "обувь", "capacity": 0}]}`},
+		{name: "Type listed twice", body: `{"capacities": [{"type": "обувь", "capacity": 5}, {"type": "обувь", "capacity": 7}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, pvzMock, handler := setupHandler(t)
+
+			pvzID := uuid.New()
+			req, rec := createRawRequest(http.MethodPut, "/pvz/"+pvzID.String()+"/capacities", tt.body)
+			req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+			handler.SetTypeCapacities().ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			pvzMock.AssertNotCalled(t, "SetTypeCapacities", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestSetTypeCapacities_NotFound(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzID := uuid.New()
+	pvzMock.On("SetTypeCapacities", mock.Anything, pvzID, mock.Anything).Return(e.ErrNotFound())
+
+	req, rec := createRawRequest(http.MethodPut, "/pvz/"+pvzID.String()+"/capacities", `{"capacities": []}`)
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.SetTypeCapacities().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
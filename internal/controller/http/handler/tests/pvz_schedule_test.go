@@ -0,0 +1,210 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	api "pvz-service/api/generated"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSetPVZSchedule_Success(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzID := uuid.New()
+	body := `{
+		"timezone": "Europe/Moscow",
+		"weekly": [
+			{"weekday": "monday", "intervals": [{"opens": "09:00", "closes": "13:00"}, {"opens": "14:00", "closes": "21:00"}]},
+			{"weekday": "saturday", "intervals": [{"opens": "10:00", "closes": "24:00"}]}
+		],
+		"exceptions": [
+			{"date": "2026-01-01", "note": "Новый год"}
+		]
+	}`
+
+	pvzMock.On("SetPVZSchedule", mock.Anything, mock.MatchedBy(func(s *models.PVZSchedule) bool {
+		return s.PVZID == pvzID && s.Timezone == "Europe/Moscow" && len(s.Weekly) == 2 &&
+			len(s.Weekly[0].Intervals) == 2 && len(s.Exceptions) == 1 &&
+			s.Exceptions[0].Date == "2026-01-01" && len(s.Exceptions[0].Intervals) == 0
+	})).Return(&models.PVZSchedule{PVZID: pvzID, Timezone: "Europe/Moscow"}, nil)
+
+	req, rec := createRawRequest(http.MethodPut, "/pvz/"+pvzID.String()+"/schedule", body)
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.SetPVZSchedule().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp models.PVZSchedule
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, pvzID, resp.PVZID)
+	pvzMock.AssertExpectations(t)
+}
+
+func TestSetPVZSchedule_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		message string
+	}{
+		{
+			name:    "Unknown timezone",
+			body:    `{"timezone": "Mars/Olympus", "weekly": []}`,
+			message: "unknown timezone",
+		},
+		{
+			name:    "Unknown weekday",
+			body:    `{"timezone": "UTC", "weekly": [{"weekday": "someday", "intervals": []}]}`,
+			message: "field Weekday is not a valid",
+		},
+		{
+			name:    "Interval closes before it opens",
+			body:    `{"timezone": "UTC", "weekly": [{"weekday": "monday", "intervals": [{"opens": "21:00", "closes": "09:00"}]}]}`,
+			message: "must open before it closes",
+		},
+		{
+			name:    "Malformed time",
+			body:    `{"timezone": "UTC", "weekly": [{"weekday": "monday", "intervals": [{"opens": "9am", "closes": "18:00"}]}]}`,
+			message: "invalid time",
+		},
+		{
+			name:    "Weekday listed twice",
+			body:    `{"timezone": "UTC", "weekly": [{"weekday": "monday", "intervals": []}, {"weekday": "monday", "intervals": []}]}`,
+			message: "listed twice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, pvzMock, handler := setupHandler(t)
+
+			pvzID := uuid.New()
+			req, rec := createRawRequest(http.MethodPut, "/pvz/"+pvzID.String()+"/schedule", tt.body)
+			req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+			handler.SetPVZSchedule().ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.message)
+			pvzMock.AssertNotCalled(t, "SetPVZSchedule", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestSetPVZSchedule_PVZNotFound(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzID := uuid.New()
+	pvzMock.On("SetPVZSchedule", mock.Anything, mock.Anything).Return(nil, e.ErrNotFound())
+
+	req, rec := createRawRequest(http.MethodPut, "/pvz/"+pvzID.String()+"/schedule", `{"timezone": "UTC", "weekly": []}`)
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.SetPVZSchedule().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestGetPVZSchedule(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		_, pvzMock, handler := setupHandler(t)
+
+		pvzID := uuid.New()
+		pvzMock.On("GetPVZSchedule", mock.Anything, pvzID).Return(&models.PVZSchedule{PVZID: pvzID, Timezone: "Asia/Omsk"}, nil)
+
+		req, rec := createRequest(http.MethodGet, "/pvz/"+pvzID.String()+"/schedule", nil)
+		req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+		handler.GetPVZSchedule().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Asia/Omsk")
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, pvzMock, handler := setupHandler(t)
+
+		pvzID := uuid.New()
+		pvzMock.On("GetPVZSchedule", mock.Anything, pvzID).Return(nil, e.ErrNotFound())
+
+		req, rec := createRequest(http.MethodGet, "/pvz/"+pvzID.String()+"/schedule", nil)
+		req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+		handler.GetPVZSchedule().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestDeletePVZSchedule(t *testing.T) {
+	tests := []struct {
+		name       string
+		serviceErr error
+		expectCode int
+	}{
+		{"Success", nil, http.StatusNoContent},
+		{"NotFound", e.ErrNotFound(), http.StatusNotFound},
+		{"ServiceError", errors.New("db down"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, pvzMock, handler := setupHandler(t)
+
+			pvzID := uuid.New()
+			pvzMock.On("DeletePVZSchedule", mock.Anything, pvzID).Return(tt.serviceErr)
+
+			req, rec := createRequest(http.MethodDelete, "/pvz/"+pvzID.String()+"/schedule", nil)
+			req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+			handler.DeletePVZSchedule().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectCode, rec.Code)
+		})
+	}
+}
+
+func TestAllowReception(t *testing.T) {
+	tests := []struct {
+		name       string
+		until      time.Duration
+		serviceErr error
+		expectCode int
+	}{
+		{"Success", 2 * time.Hour, nil, http.StatusOK},
+		{"InThePast", -time.Minute, nil, http.StatusBadRequest},
+		{"LongerThanADay", 25 * time.Hour, nil, http.StatusBadRequest},
+		{"NoSchedule", 2 * time.Hour, e.ErrNotFound(), http.StatusNotFound},
+		{"ServiceError", 2 * time.Hour, errors.New("db down"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, pvzMock, handler := setupHandler(t)
+
+			pvzID := uuid.New()
+			until := time.Now().Add(tt.until).UTC().Truncate(time.Second)
+			if tt.serviceErr != nil {
+				pvzMock.On("AllowReceptions", mock.Anything, pvzID, until).Return(nil, tt.serviceErr)
+			} else {
+				pvzMock.On("AllowReceptions", mock.Anything, pvzID, until).Return(
+					&models.PVZSchedule{PVZID: pvzID, Timezone: "UTC", ReceptionsAllowedUntil: &until}, nil,
+				)
+			}
+
+			req, rec := createRequest(http.MethodPost, "/pvz/"+pvzID.String()+"/allow_reception",
+				api.PostPvzPvzIdAllowReceptionJSONRequestBody{Until: until})
+			req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+			handler.AllowReception().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectCode, rec.Code)
+			if tt.expectCode == http.StatusBadRequest {
+				pvzMock.AssertNotCalled(t, "AllowReceptions", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				pvzMock.AssertExpectations(t)
+			}
+		})
+	}
+}
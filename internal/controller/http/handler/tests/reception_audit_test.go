@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	api "pvz-service/api/generated"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestForceCloseReception_Success(t *testing.T) {
+	mocks, handler := setupHandlerWithMocks(t)
+
+	pvzID := uuid.New()
+	mocks.staleReception.On("ForceCloseReception", mock.Anything, pvzID, "moderator@example.com", "scanner crashed").Return(&models.Reception{
+		ID:     uuid.New(),
+		PVZID:  pvzID,
+		Status: models.ReceptionStatusClose,
+	}, nil)
+
+	reason := "scanner crashed"
+	req, rec := createRequest(http.MethodPost, "/pvz/"+pvzID.String()+"/force_close_reception", api.ForceCloseReceptionRequest{Reason: &reason})
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	req = req.WithContext(context.WithValue(req.Context(), "user_email", "moderator@example.com"))
+	handler.ForceCloseReception().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp api.Reception
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, api.ReceptionStatus("close"), resp.Status)
+	mocks.staleReception.AssertExpectations(t)
+}
+
+func TestForceCloseReception_WithoutBody(t *testing.T) {
+	mocks, handler := setupHandlerWithMocks(t)
+
+	pvzID := uuid.New()
+	mocks.staleReception.On("ForceCloseReception", mock.Anything, pvzID, "moderator", "").
+		Return(&models.Reception{ID: uuid.New(), PVZID: pvzID, Status: models.ReceptionStatusClose}, nil)
+
+	req, rec := createRequest(http.MethodPost, "/pvz/"+pvzID.String()+"/force_close_reception", nil)
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.ForceCloseReception().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mocks.staleReception.AssertExpectations(t)
+}
+
+func TestForceCloseReception_ReasonTooLong(t *testing.T) {
+	mocks, handler := setupHandlerWithMocks(t)
+
+	pvzID := uuid.New()
+	reason := strings.Repeat("a", 256)
+	req, rec := createRequest(http.MethodPost, "/pvz/"+pvzID.String()+"/force_close_reception", api.ForceCloseReceptionRequest{Reason: &reason})
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.ForceCloseReception().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mocks.staleReception.AssertNotCalled(t, "ForceCloseReception", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestForceCloseReception_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode int
+		expectedMsg  string
+	}{
+		{name: "No active reception", err: e.ErrNoActiveReception(), expectedCode: http.StatusBadRequest, expectedMsg: "no active reception"},
+		{name: "Reception modified", err: e.ErrVersionMismatch(), expectedCode: http.StatusConflict, expectedMsg: "reception was modified"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks, handler := setupHandlerWithMocks(t)
+
+			pvzID := uuid.New()
+			mocks.staleReception.On("ForceCloseReception", mock.Anything, pvzID, mock.Anything, mock.Anything).Return(nil, tt.err)
+
+			req, rec := createRequest(http.MethodPost, "/pvz/"+pvzID.String()+"/force_close_reception", nil)
+			req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+			handler.ForceCloseReception().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+
+			var resp api.Error
+			assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, tt.expectedMsg, resp.Message)
+		})
+	}
+}
+
+func TestGetPVZReceptionAudit(t *testing.T) {
+	mocks, handler := setupHandlerWithMocks(t)
+
+	pvzID := uuid.New()
+	mocks.staleReception.On("GetPVZReceptionAudit", mock.Anything, pvzID).Return([]models.ReceptionAuditEntry{{
+		ReceptionID: uuid.New(),
+		PVZID:       pvzID,
+		Action:      models.ReceptionAuditAutoClosed,
+		Actor:       models.ReceptionAuditActorSystem,
+		CreatedAt:   time.Now(),
+	}}, nil)
+
+	req, rec := createRequest(http.MethodGet, "/pvz/"+pvzID.String()+"/reception_audit", nil)
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.GetPVZReceptionAudit().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp []api.ReceptionAuditEntry
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	if assert.Len(t, resp, 1) {
+		assert.Equal(t, "auto_closed", resp[0].Action)
+		assert.Equal(t, "system", resp[0].Actor)
+	}
+}
+
+func TestGetPVZReceptionAudit_NotFound(t *testing.T) {
+	mocks, handler := setupHandlerWithMocks(t)
+
+	pvzID := uuid.New()
+	mocks.staleReception.On("GetPVZReceptionAudit", mock.Anything, pvzID).Return(nil, e.ErrNotFound())
+
+	req, rec := createRequest(http.MethodGet, "/pvz/"+pvzID.String()+"/reception_audit", nil)
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.GetPVZReceptionAudit().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
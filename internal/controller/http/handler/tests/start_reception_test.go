@@ -25,7 +25,7 @@ func TestStartReception_Success(t *testing.T) {
 		Status:   models.ReceptionStatusInProgress,
 	}
 
-	pvzMock.On("StartReception", mock.Anything, pvzID).Return(expectedReception, nil)
+	pvzMock.On("StartReception", mock.Anything, pvzID, (*uuid.UUID)(nil)).Return(expectedReception, nil)
 
 	reqBody := api.PostReceptionsJSONRequestBody{
 		PvzId: pvzID,
@@ -47,7 +47,7 @@ func TestStartReception_ActiveReceptionExists(t *testing.T) {
 	_, pvzMock, handler := setupHandler(t)
 
 	pvzID := uuid.New()
-	pvzMock.On("StartReception", mock.Anything, pvzID).Return(
+	pvzMock.On("StartReception", mock.Anything, pvzID, (*uuid.UUID)(nil)).Return(
 		(*models.Reception)(nil), e.ErrActiveReceptionExists(),
 	)
 
@@ -65,3 +65,63 @@ func TestStartReception_ActiveReceptionExists(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "active reception exists", resp.Message)
 }
+
+func TestStartReception_PVZNotActive(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzID := uuid.New()
+	pvzMock.On("StartReception", mock.Anything, pvzID, (*uuid.UUID)(nil)).Return(
+		(*models.Reception)(nil), e.ErrPVZNotActive(),
+	)
+
+	reqBody := api.PostReceptionsJSONRequestBody{
+		PvzId: pvzID,
+	}
+
+	req, rec := createRequest(http.MethodPost, "/receptions", reqBody)
+	handler.StartReception().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var resp api.Error
+	err := json.NewDecoder(rec.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "pvz is not active", resp.Message)
+}
+
+func TestStartReception_OutsideWorkingHours(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzID := uuid.New()
+	pvzMock.On("StartReception", mock.Anything, pvzID, (*uuid.UUID)(nil)).Return(
+		(*models.Reception)(nil), e.ErrOutsideWorkingHours(),
+	)
+
+	req, rec := createRequest(http.MethodPost, "/receptions", api.PostReceptionsJSONRequestBody{PvzId: pvzID})
+	handler.StartReception().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "pvz is closed at this time")
+}
+
+func TestStartReception_ManifestNotAvailable(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzID := uuid.New()
+	manifestID := uuid.New()
+	pvzMock.On("StartReception", mock.Anything, pvzID, &manifestID).Return(
+		(*models.Reception)(nil), e.ErrManifestNotAvailable(),
+	)
+
+	req, rec := createRequest(http.MethodPost, "/receptions", api.PostReceptionsJSONRequestBody{
+		PvzId:      pvzID,
+		ManifestId: &manifestID,
+	})
+	handler.StartReception().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var resp api.Error
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "manifest cannot be used for this reception", resp.Message)
+}
@@ -0,0 +1,201 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	api "pvz-service/api/generated"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateTransfer_Success(t *testing.T) {
+	mocks, handler := setupHandlerWithMocks(t)
+
+	sourceID, destinationID := uuid.New(), uuid.New()
+	productIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	mocks.transfer.On("CreateTransfer", mock.Anything, sourceID, destinationID, productIDs).Return(&models.Transfer{
+		ID:               uuid.New(),
+		SourcePVZID:      sourceID,
+		DestinationPVZID: destinationID,
+		Status:           models.TransferStatusOutbound,
+		ProductIDs:       productIDs,
+		CreatedAt:        time.Now(),
+	}, nil)
+
+	req, rec := createRequest(http.MethodPost, "/transfers", api.TransferCreate{
+		SourcePvzId:      sourceID,
+		DestinationPvzId: destinationID,
+		ProductIds:       productIDs,
+	})
+	handler.CreateTransfer().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var resp api.Transfer
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "outbound", resp.Status)
+	assert.Equal(t, productIDs, resp.ProductIds)
+	mocks.transfer.AssertExpectations(t)
+}
+
+func TestCreateTransfer_InvalidRequest(t *testing.T) {
+	pvzID := uuid.New()
+	productID := uuid.New()
+
+	tests := []struct {
+		name string
+		body api.TransferCreate
+	}{
+		{
+			name: "Same PVZ",
+			body: api.TransferCreate{SourcePvzId: pvzID, DestinationPvzId: pvzID, ProductIds: []uuid.UUID{productID}},
+		},
+		{
+			name: "No products",
+			body: api.TransferCreate{SourcePvzId: pvzID, DestinationPvzId: uuid.New(), ProductIds: []uuid.UUID{}},
+		},
+		{
+			name: "Duplicate products",
+			body: api.TransferCreate{SourcePvzId: pvzID, DestinationPvzId: uuid.New(), ProductIds: []uuid.UUID{productID, productID}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks, handler := setupHandlerWithMocks(t)
+
+			req, rec := createRequest(http.MethodPost, "/transfers", tt.body)
+			handler.CreateTransfer().ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			mocks.transfer.AssertNotCalled(t, "CreateTransfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCreateTransfer_ProductNotTransferable(t *testing.T) {
+	mocks, handler := setupHandlerWithMocks(t)
+
+	mocks.transfer.On("CreateTransfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, e.ErrProductNotTransferable())
+
+	req, rec := createRequest(http.MethodPost, "/transfers", api.TransferCreate{
+		SourcePvzId:      uuid.New(),
+		DestinationPvzId: uuid.New(),
+		ProductIds:       []uuid.UUID{uuid.New()},
+	})
+	handler.CreateTransfer().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var resp api.Error
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "product cannot be transferred", resp.Message)
+}
+
+func TestGetPVZTransfers(t *testing.T) {
+	mocks, handler := setupHandlerWithMocks(t)
+
+	pvzID := uuid.New()
+	mocks.transfer.On("GetPVZTransfers", mock.Anything, pvzID, models.TransferStatusInTransit).
+		Return([]models.Transfer{{ID: uuid.New(), SourcePVZID: pvzID, Status: models.TransferStatusInTransit}}, nil)
+
+	req, rec := createRequest(http.MethodGet, "/pvz/"+pvzID.String()+"/transfers?status=in_transit", nil)
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.GetPVZTransfers().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp []api.Transfer
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Len(t, resp, 1)
+	mocks.transfer.AssertExpectations(t)
+}
+
+func TestGetPVZTransfers_InvalidStatus(t *testing.T) {
+	mocks, handler := setupHandlerWithMocks(t)
+
+	pvzID := uuid.New()
+	req, rec := createRequest(http.MethodGet, "/pvz/"+pvzID.String()+"/transfers?status=lost", nil)
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.GetPVZTransfers().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mocks.transfer.AssertNotCalled(t, "GetPVZTransfers", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetTransfer_NotFound(t *testing.T) {
+	mocks, handler := setupHandlerWithMocks(t)
+
+	transferID := uuid.New()
+	mocks.transfer.On("GetTransfer", mock.Anything, transferID).Return(nil, e.ErrNotFound())
+
+	req, rec := createRequest(http.MethodGet, "/transfers/"+transferID.String(), nil)
+	req = addURLParams(req, map[string]string{"transferId": transferID.String()})
+	handler.GetTransfer().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestReceiveTransfer_Success(t *testing.T) {
+	mocks, handler := setupHandlerWithMocks(t)
+
+	transferID := uuid.New()
+	receptionID := uuid.New()
+	mocks.transfer.On("ReceiveTransfer", mock.Anything, transferID).Return(&models.Transfer{
+		ID:          transferID,
+		Status:      models.TransferStatusReceived,
+		ReceptionID: &receptionID,
+	}, nil)
+
+	req, rec := createRequest(http.MethodPost, "/transfers/"+transferID.String()+"/receive", nil)
+	req = addURLParams(req, map[string]string{"transferId": transferID.String()})
+	handler.ReceiveTransfer().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp api.Transfer
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "received", resp.Status)
+	assert.Equal(t, receptionID, *resp.ReceptionId)
+}
+
+func TestChangeTransferStatus_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode int
+		expectedMsg  string
+	}{
+		{name: "Not found", err: e.ErrNotFound(), expectedCode: http.StatusNotFound, expectedMsg: "transfer not found"},
+		{name: "Invalid state", err: e.ErrInvalidTransferState(), expectedCode: http.StatusBadRequest, expectedMsg: "invalid transfer status transition"},
+		{name: "No active reception", err: e.ErrNoActiveReception(), expectedCode: http.StatusBadRequest, expectedMsg: "no active reception"},
+		{name: "Capacity exceeded", err: e.ErrCapacityExceeded(), expectedCode: http.StatusBadRequest, expectedMsg: "pvz capacity exceeded"},
+		{name: "Reception modified", err: e.ErrVersionMismatch(), expectedCode: http.StatusConflict, expectedMsg: "reception was modified"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks, handler := setupHandlerWithMocks(t)
+
+			transferID := uuid.New()
+			mocks.transfer.On("ReceiveTransfer", mock.Anything, transferID).Return(nil, tt.err)
+
+			req, rec := createRequest(http.MethodPost, "/transfers/"+transferID.String()+"/receive", nil)
+			req = addURLParams(req, map[string]string{"transferId": transferID.String()})
+			handler.ReceiveTransfer().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+
+			var resp api.Error
+			assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, tt.expectedMsg, resp.Message)
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	api "pvz-service/api/generated"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdatePVZ_Success(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzID := uuid.New()
+	status := api.PVZUpdateStatusSuspended
+	updated := &models.PVZ{
+		ID:               pvzID,
+		RegistrationDate: time.Now(),
+		CityName:         "Москва",
+		Status:           models.PVZStatusSuspended,
+		Version:          2,
+	}
+
+	pvzMock.On("UpdatePVZ", mock.Anything, pvzID, mock.MatchedBy(func(u models.PVZUpdate) bool {
+		return u.CityName == nil && u.Status != nil && *u.Status == models.PVZStatusSuspended
+	}), (*models.ETag)(nil)).Return(updated, nil)
+
+	req, rec := createRequest(http.MethodPatch, "/pvz/"+pvzID.String(), api.PatchPvzPvzIdJSONRequestBody{Status: &status})
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.UpdatePVZ().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, models.ETag{ID: pvzID, Version: 2}.String(), rec.Header().Get("ETag"))
+
+	var resp api.PVZ
+	err := json.NewDecoder(rec.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, api.PVZStatusSuspended, *resp.Status)
+}
+
+func TestUpdatePVZ_InvalidStatus(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzID := uuid.New()
+	status := api.PVZUpdateStatus("deleted")
+
+	req, rec := createRequest(http.MethodPatch, "/pvz/"+pvzID.String(), api.PatchPvzPvzIdJSONRequestBody{Status: &status})
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.UpdatePVZ().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	pvzMock.AssertNotCalled(t, "UpdatePVZ")
+}
+
+func TestUpdatePVZ_ClearLocation(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzID := uuid.New()
+	clearLocation := true
+
+	pvzMock.On("UpdatePVZ", mock.Anything, pvzID, mock.MatchedBy(func(u models.PVZUpdate) bool {
+		return u.ClearLocation && u.Location == nil
+	}), (*models.ETag)(nil)).Return(&models.PVZ{ID: pvzID, CityName: "Москва", Status: models.PVZStatusActive, Version: 2}, nil)
+
+	req, rec := createRequest(http.MethodPatch, "/pvz/"+pvzID.String(), api.PatchPvzPvzIdJSONRequestBody{ClearLocation: &clearLocation})
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.UpdatePVZ().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestUpdatePVZ_LocationSetAndCleared(t *testing.T) {
+	_, pvzMock, handler := setupHandler(t)
+
+	pvzID := uuid.New()
+	clearLocation := true
+	body := api.PatchPvzPvzIdJSONRequestBody{
+		Location:      &api.GeoPoint{Latitude: 55.7575, Longitude: 37.6132},
+		ClearLocation: &clearLocation,
+	}
+
+	req, rec := createRequest(http.MethodPatch, "/pvz/"+pvzID.String(), body)
+	req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+	handler.UpdatePVZ().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	pvzMock.AssertNotCalled(t, "UpdatePVZ")
+}
+
+func TestUpdatePVZ_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		expectCode int
+	}{
+		{"not found", e.ErrNotFound(), http.StatusNotFound},
+		{"city not allowed", e.ErrCityNotAllowed(), http.StatusBadRequest},
+		{"active reception", e.ErrActiveReceptionExists(), http.StatusBadRequest},
+		{"version mismatch", e.ErrVersionMismatch(), http.StatusPreconditionFailed},
+		{"internal", assert.AnError, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, pvzMock, handler := setupHandler(t)
+
+			pvzID := uuid.New()
+			city := "Казань"
+			ifMatch := models.ETag{ID: pvzID, Version: 1}
+			pvzMock.On("UpdatePVZ", mock.Anything, pvzID, mock.Anything, &ifMatch).Return((*models.PVZ)(nil), tt.err)
+
+			req, rec := createRequest(http.MethodPatch, "/pvz/"+pvzID.String(), api.PatchPvzPvzIdJSONRequestBody{City: &city})
+			req.Header.Set("If-Match", ifMatch.String())
+			req = addURLParams(req, map[string]string{"pvzId": pvzID.String()})
+			handler.UpdatePVZ().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectCode, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	api "pvz-service/api/generated"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	eventTypes := []api.WebhookSubscriptionEventTypes{api.WebhookSubscriptionEventTypesReceptionClosed}
+	shortSecret := "short"
+	pvzID := uuid.New()
+
+	tests := []struct {
+		name           string
+		body           interface{}
+		setupMock      func(m *MockWebhookService)
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			body: api.PostWebhooksJSONRequestBody{Url: "http://example.com/hook", EventTypes: &eventTypes},
+			setupMock: func(m *MockWebhookService) {
+				m.On("CreateSubscription", mock.Anything, mock.MatchedBy(func(sub *models.WebhookSubscription) bool {
+					return sub.URL == "http://example.com/hook" &&
+						len(sub.EventTypes) == 1 && sub.EventTypes[0] == models.EventReceptionClosed
+				})).Return(&models.WebhookSubscription{ID: uuid.New(), URL: "http://example.com/hook", CreatedAt: time.Now()}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Empty body",
+			body:           nil,
+			setupMock:      func(m *MockWebhookService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid url",
+			body:           api.PostWebhooksJSONRequestBody{Url: "not a url"},
+			setupMock:      func(m *MockWebhookService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Short secret",
+			body:           api.PostWebhooksJSONRequestBody{Url: "http://example.com/hook", Secret: &shortSecret},
+			setupMock:      func(m *MockWebhookService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "City not allowed",
+			body: api.PostWebhooksJSONRequestBody{Url: "http://example.com/hook"},
+			setupMock: func(m *MockWebhookService) {
+				m.On("CreateSubscription", mock.Anything, mock.Anything).
+					Return((*models.WebhookSubscription)(nil), e.ErrCityNotAllowed())
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "PVZ not found",
+			body: api.PostWebhooksJSONRequestBody{Url: "http://example.com/hook", PvzId: &pvzID},
+			setupMock: func(m *MockWebhookService) {
+				m.On("CreateSubscription", mock.Anything, mock.Anything).
+					Return((*models.WebhookSubscription)(nil), e.ErrNotFound())
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "Service error",
+			body: api.PostWebhooksJSONRequestBody{Url: "http://example.com/hook"},
+			setupMock: func(m *MockWebhookService) {
+				m.On("CreateSubscription", mock.Anything, mock.Anything).
+					Return((*models.WebhookSubscription)(nil), errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, webhookMock, handler := setupHandlerWithWebhooks(t)
+			tt.setupMock(webhookMock)
+
+			req, rec := createRequest(http.MethodPost, "/webhooks", tt.body)
+			handler.CreateWebhook().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			webhookMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetWebhooks_Success(t *testing.T) {
+	_, _, webhookMock, handler := setupHandlerWithWebhooks(t)
+
+	subs := []models.WebhookSubscription{{ID: uuid.New(), URL: "http://example.com/hook", EventTypes: []models.EventType{}}}
+	webhookMock.On("GetSubscriptions", mock.Anything).Return(subs, nil)
+
+	req, rec := createRequest(http.MethodGet, "/webhooks", nil)
+	handler.GetWebhooks().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp []models.WebhookSubscription
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Len(t, resp, 1)
+	assert.Equal(t, subs[0].ID, resp[0].ID)
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name           string
+		param          string
+		serviceErr     error
+		callService    bool
+		expectedStatus int
+	}{
+		{name: "Success", param: id.String(), callService: true, expectedStatus: http.StatusOK},
+		{name: "Not found", param: id.String(), serviceErr: e.ErrNotFound(), callService: true, expectedStatus: http.StatusNotFound},
+		{name: "Invalid id", param: "invalid", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, webhookMock, handler := setupHandlerWithWebhooks(t)
+			if tt.callService {
+				webhookMock.On("DeleteSubscription", mock.Anything, id).Return(tt.serviceErr)
+			}
+
+			req, rec := createRequest(http.MethodDelete, "/webhooks/"+tt.param, nil)
+			req = addURLParams(req, map[string]string{"webhookId": tt.param})
+			handler.DeleteWebhook().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			webhookMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetDeadLetters_Success(t *testing.T) {
+	_, _, webhookMock, handler := setupHandlerWithWebhooks(t)
+
+	id := uuid.New()
+	deliveries := []models.WebhookDelivery{{ID: uuid.New(), SubscriptionID: id, Status: models.DeliveryStatusDead, Attempts: 8}}
+	webhookMock.On("GetDeadLetters", mock.Anything, id).Return(deliveries, nil)
+
+	req, rec := createRequest(http.MethodGet, "/webhooks/"+id.String()+"/dead_letters", nil)
+	req = addURLParams(req, map[string]string{"webhookId": id.String()})
+	handler.GetDeadLetters().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp []models.WebhookDelivery
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Len(t, resp, 1)
+	assert.Equal(t, deliveries[0].ID, resp[0].ID)
+}
+
+func TestRedeliver(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "Success", expectedStatus: http.StatusOK},
+		{name: "Not found", serviceErr: e.ErrNotFound(), expectedStatus: http.StatusNotFound},
+		{name: "Service error", serviceErr: errors.New("db error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, webhookMock, handler := setupHandlerWithWebhooks(t)
+			webhookMock.On("Redeliver", mock.Anything, id).Return(tt.serviceErr)
+
+			req, rec := createRequest(http.MethodPost, "/webhooks/deliveries/"+id.String()+"/redeliver", nil)
+			req = addURLParams(req, map[string]string{"deliveryId": id.String()})
+			handler.Redeliver().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			webhookMock.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	api "pvz-service/api/generated"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/logger/sl"
+	"pvz-service/internal/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) DispatchTransfer() http.HandlerFunc {
+	return h.changeTransferStatus("handler.DispatchTransfer", h.transferService.DispatchTransfer)
+}
+
+func (h *Handler) ReceiveTransfer() http.HandlerFunc {
+	return h.changeTransferStatus("handler.ReceiveTransfer", h.transferService.ReceiveTransfer)
+}
+
+func (h *Handler) CancelTransfer() http.HandlerFunc {
+	return h.changeTransferStatus("handler.CancelTransfer", h.transferService.CancelTransfer)
+}
+
+// changeTransferStatus serves the transfer status endpoints, which differ only
+// in the service call
+func (h *Handler) changeTransferStatus(op string, change func(ctx context.Context, transferID uuid.UUID) (*models.Transfer, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := h.log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		transferId := chi.URLParam(r, "transferId")
+		if transferId == "" {
+			log.Error("url param is empty")
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "empty request"})
+
+			return
+		}
+
+		id, err := uuid.Parse(transferId)
+		if err != nil {
+			log.Error("invalid param", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "invalid param"})
+
+			return
+		}
+
+		transfer, err := change(r.Context(), id)
+		if err == e.ErrNotFound() {
+			log.Error("transfer not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, api.Error{Message: "transfer not found"})
+
+			return
+		}
+		if err == e.ErrInvalidTransferState() {
+			log.Error("invalid transfer status transition", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "invalid transfer status transition"})
+
+			return
+		}
+		if err == e.ErrProductNotTransferable() {
+			log.Error("product cannot be transferred", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "product cannot be transferred"})
+
+			return
+		}
+		if err == e.ErrNoActiveReception() {
+			log.Error("no active reception", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "no active reception"})
+
+			return
+		}
+		if err == e.ErrCapacityExceeded() {
+			log.Error("pvz capacity exceeded", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "pvz capacity exceeded"})
+
+			return
+		}
+		if err == e.ErrVersionMismatch() {
+			log.Error("reception was modified", sl.Err(err))
+
+			w.WriteHeader(http.StatusConflict)
+			render.JSON(w, r, api.Error{Message: "reception was modified"})
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to change transfer status", sl.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, api.Error{Message: "failed to change transfer status"})
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, transfer)
+	}
+}
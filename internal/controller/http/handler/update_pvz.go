@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	api "pvz-service/api/generated"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/logger/sl"
+	"pvz-service/internal/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) UpdatePVZ() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.UpdatePVZ"
+
+		log := h.log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		pvzId := chi.URLParam(r, "pvzId")
+		if pvzId == "" {
+			log.Error("url param is empty")
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "empty request"})
+
+			return
+		}
+
+		id, err := uuid.Parse(pvzId)
+		if err != nil {
+			log.Error("invalid param", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "invalid param"})
+
+			return
+		}
+
+		var req api.PatchPvzPvzIdJSONRequestBody
+
+		err = render.DecodeJSON(r.Body, &req)
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "empty request"})
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, api.Error{Message: "failed to decode request"})
+
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: e.ValidationError(validateErr)})
+
+			return
+		}
+
+		clearLocation := req.ClearLocation != nil && *req.ClearLocation
+		if clearLocation && req.Location != nil {
+			log.Error("location is both set and cleared")
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "location and clearLocation are mutually exclusive"})
+
+			return
+		}
+
+		ifMatch, err := parseIfMatch(r)
+		if err != nil {
+			log.Error("invalid If-Match header", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "invalid If-Match header"})
+
+			return
+		}
+
+		update := models.PVZUpdate{
+			CityName:      req.City,
+			Address:       req.Address,
+			Location:      toGeoPoint(req.Location),
+			ClearLocation: clearLocation,
+			Capacity:      req.Capacity,
+		}
+		if req.Status != nil {
+			status := models.PVZStatus(*req.Status)
+			update.Status = &status
+		}
+
+		pvz, err := h.pvzService.UpdatePVZ(r.Context(), id, update, ifMatch)
+		if err == e.ErrNotFound() {
+			log.Error("pvz not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, api.Error{Message: "pvz not found"})
+
+			return
+		}
+		if err == e.ErrCityNotAllowed() {
+			log.Error("city not allowed", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "city not allowed"})
+
+			return
+		}
+		if err == e.ErrActiveReceptionExists() {
+			log.Error("active reception exists", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "active reception exists"})
+
+			return
+		}
+		if err == e.ErrVersionMismatch() {
+			log.Error("pvz was modified", sl.Err(err))
+
+			w.WriteHeader(http.StatusPreconditionFailed)
+			render.JSON(w, r, api.Error{Message: "pvz was modified"})
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to update pvz", sl.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, api.Error{Message: "failed to update pvz"})
+
+			return
+		}
+
+		w.Header().Set(ETagHeader, models.ETag{ID: pvz.ID, Version: pvz.Version}.String())
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, pvz)
+	}
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	api "pvz-service/api/generated"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/logger/sl"
+	"pvz-service/internal/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+const maxPhotoSize = 10 << 20
+
+func (h *Handler) UploadProductPhoto() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.UploadProductPhoto"
+
+		log := h.log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		productId := chi.URLParam(r, "productId")
+		if productId == "" {
+			log.Error("url param is empty")
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "empty request"})
+
+			return
+		}
+
+		id, err := uuid.Parse(productId)
+		if err != nil {
+			log.Error("invalid param", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "invalid param"})
+
+			return
+		}
+
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPhotoSize))
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			log.Error("photo is too large", sl.Err(err))
+
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			render.JSON(w, r, api.Error{Message: "photo is too large"})
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to read request body", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "failed to read request"})
+
+			return
+		}
+		if len(data) == 0 {
+			log.Error("request body is empty")
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "empty request"})
+
+			return
+		}
+
+		// Trust the content, not the Content-Type header of the request
+		contentType := http.DetectContentType(data)
+		if _, ok := models.PhotoExtensions[contentType]; !ok {
+			log.Error("unsupported photo type", slog.String("content_type", contentType))
+
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			render.JSON(w, r, api.Error{Message: "unsupported photo type"})
+
+			return
+		}
+
+		log.Info("photo received", slog.String("content_type", contentType), slog.Int("size", len(data)))
+
+		product, err := h.productConditionService.UploadProductPhoto(r.Context(), id, contentType, data)
+		if err == e.ErrNotFound() {
+			log.Error("product not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, api.Error{Message: "product not found"})
+
+			return
+		}
+		if err == e.ErrProductNotInReception() {
+			log.Error("product is not in an open reception", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, api.Error{Message: "product is not in an open reception"})
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to upload photo", sl.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, api.Error{Message: "failed to upload photo"})
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, product)
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"pvz-service/internal/consistency"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// ReadYourWritesMiddleware sends the reads of a user who has just changed
+// something to the primary database, so the listing shows the change even
+// if the replicas have not received it yet. Only successful requests count
+// as changes. Without a tracker, i.e. without replicas, requests pass
+// through unchanged.
+func ReadYourWritesMiddleware(tracker *consistency.Tracker) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if tracker == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			email, _ := r.Context().Value("user_email").(string)
+
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				if tracker.Recent(email) {
+					r = r.WithContext(consistency.WithPrimary(r.Context()))
+				}
+				next.ServeHTTP(w, r)
+
+			default:
+				ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+				next.ServeHTTP(ww, r)
+
+				// A handler that writes nothing answers 200
+				if status := ww.Status(); status == 0 || status >= 200 && status < 300 {
+					tracker.Wrote(email)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"pvz-service/internal/consistency"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadYourWritesMiddleware(t *testing.T) {
+	var primary bool
+	handler := ReadYourWritesMiddleware(consistency.NewTracker(time.Minute))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			primary = consistency.PrimaryRequired(r.Context())
+			if r.URL.Query().Has("fail") {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		}),
+	)
+
+	serve := func(method, target, email string) bool {
+		ctx := context.WithValue(context.Background(), "user_email", email)
+		req := httptest.NewRequest(method, target, nil).WithContext(ctx)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		return primary
+	}
+
+	assert.False(t, serve(http.MethodGet, "/pvz", "moderator@example.com"), "no writes yet")
+	assert.False(t, serve(http.MethodPost, "/pvz", "moderator@example.com"), "writes are not marked")
+	assert.True(t, serve(http.MethodGet, "/pvz", "moderator@example.com"), "reads after a write")
+	assert.False(t, serve(http.MethodGet, "/pvz", "employee@example.com"), "other users read from replicas")
+
+	serve(http.MethodPost, "/pvz?fail", "employee@example.com")
+	assert.False(t, serve(http.MethodGet, "/pvz", "employee@example.com"), "failed writes change nothing")
+}
+
+func TestReadYourWritesMiddlewareWithoutTracker(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.False(t, consistency.PrimaryRequired(r.Context()))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	rec := httptest.NewRecorder()
+	ReadYourWritesMiddleware(nil)(next).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/pvz", nil))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/logger/sl"
+	"pvz-service/internal/service"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// IdempotencyMiddleware replays the stored response of a POST request retried
+// with the same Idempotency-Key instead of executing it again. Keys are scoped
+// to the authenticated user, or the client address for anonymous requests,
+// and the request path. Server errors are not
+// stored, so such requests may be retried with the same key.
+func IdempotencyMiddleware(idempotencyService service.IdempotencyServiceInterface, log *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		log := log.With(
+			slog.String("component", "middleware/idempotency"),
+		)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if r.Method != http.MethodPost || key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			log := log.With(slog.String("request_id", middleware.GetReqID(r.Context())))
+
+			if len(key) > maxIdempotencyKeyLength {
+				http.Error(w, "idempotency key is too long", http.StatusBadRequest)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				log.Error("failed to read request body", sl.Err(err))
+				http.Error(w, "failed to read request", http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			// Anonymous requests, such as /register, have no user to scope
+			// the key to and are scoped to the client address instead
+			email, _ := r.Context().Value("user_email").(string)
+			client := ""
+			if email == "" {
+				client = clientHost(r.RemoteAddr)
+			}
+			storageKey := hash(email, client, r.URL.Path, key)
+			fingerprint := hash(r.Method, r.URL.Path, string(body))
+
+			record, err := idempotencyService.Begin(r.Context(), storageKey, fingerprint)
+			if err == e.ErrIdempotencyKeyMismatch() {
+				http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+				return
+			}
+			if err == e.ErrIdempotencyKeyInProgress() {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			if err != nil {
+				log.Error("failed to check idempotency key", sl.Err(err))
+				http.Error(w, "failed to check idempotency key", http.StatusInternalServerError)
+				return
+			}
+
+			if record != nil {
+				log.Info("replaying stored response", slog.Int("status", record.StatusCode))
+
+				if record.ContentType != "" {
+					w.Header().Set("Content-Type", record.ContentType)
+				}
+				w.Header().Set(IdempotentReplayedHeader, "true")
+				w.WriteHeader(record.StatusCode)
+				w.Write(record.Body)
+				return
+			}
+
+			rec := &recordingWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			// The client may already be gone, the outcome must be stored anyway
+			ctx := context.WithoutCancel(r.Context())
+
+			if rec.status >= http.StatusInternalServerError {
+				if err := idempotencyService.Release(ctx, storageKey); err != nil {
+					log.Error("failed to release idempotency key", sl.Err(err))
+				}
+				return
+			}
+
+			if err := idempotencyService.Complete(ctx, storageKey, rec.status, rec.Header().Get("Content-Type"), rec.body.Bytes()); err != nil {
+				log.Error("failed to store response", sl.Err(err))
+			}
+		})
+	}
+}
+
+// recordingWriter passes the response through while keeping a copy of it
+type recordingWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rw *recordingWriter) WriteHeader(statusCode int) {
+	if !rw.wroteHeader {
+		rw.status = statusCode
+		rw.wroteHeader = true
+	}
+	rw.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rw *recordingWriter) Write(b []byte) (int, error) {
+	rw.wroteHeader = true
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
+
+func (rw *recordingWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// clientHost returns the host part of a RemoteAddr
+func clientHost(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
+
+// Helper function: Hash parts into a fixed-length hex string
+func hash(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,231 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeIdempotencyService keeps records in memory with the semantics of IdempotencyService
+type fakeIdempotencyService struct {
+	mu       sync.Mutex
+	records  map[string]*models.IdempotencyRecord
+	beginErr error
+}
+
+func newFakeIdempotencyService() *fakeIdempotencyService {
+	return &fakeIdempotencyService{records: make(map[string]*models.IdempotencyRecord)}
+}
+
+func (f *fakeIdempotencyService) Begin(ctx context.Context, key, fingerprint string) (*models.IdempotencyRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.beginErr != nil {
+		return nil, f.beginErr
+	}
+
+	record, ok := f.records[key]
+	if !ok {
+		f.records[key] = &models.IdempotencyRecord{Key: key, Fingerprint: fingerprint}
+		return nil, nil
+	}
+	if record.Fingerprint != fingerprint {
+		return nil, e.ErrIdempotencyKeyMismatch()
+	}
+	if !record.Completed() {
+		return nil, e.ErrIdempotencyKeyInProgress()
+	}
+	return record, nil
+}
+
+func (f *fakeIdempotencyService) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	record := f.records[key]
+	record.StatusCode = statusCode
+	record.ContentType = contentType
+	record.Body = body
+	return nil
+}
+
+func (f *fakeIdempotencyService) Release(ctx context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.records, key)
+	return nil
+}
+
+func (f *fakeIdempotencyService) PurgeExpired(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+// countingHandler answers with status and counts how many times it ran
+func countingHandler(status int, calls *int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(`{"call":` + strconv.Itoa(*calls) + `,"body":` + string(body) + `}`))
+	})
+}
+
+func doRequest(h http.Handler, method, path, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func newIdempotencyHandler(svc *fakeIdempotencyService, next http.Handler) http.Handler {
+	return IdempotencyMiddleware(svc, slog.New(slog.NewTextHandler(io.Discard, nil)))(next)
+}
+
+func TestIdempotencyMiddleware_ReplaysResponse(t *testing.T) {
+	calls := 0
+	h := newIdempotencyHandler(newFakeIdempotencyService(), countingHandler(http.StatusCreated, &calls))
+
+	first := doRequest(h, http.MethodPost, "/products", "key-1", `{"type":"обувь"}`)
+	second := doRequest(h, http.MethodPost, "/products", "key-1", `{"type":"обувь"}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+	assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+	assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+}
+
+func TestIdempotencyMiddleware_ReplaysClientError(t *testing.T) {
+	calls := 0
+	h := newIdempotencyHandler(newFakeIdempotencyService(), countingHandler(http.StatusBadRequest, &calls))
+
+	doRequest(h, http.MethodPost, "/receptions", "key-1", `{}`)
+	second := doRequest(h, http.MethodPost, "/receptions", "key-1", `{}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusBadRequest, second.Code)
+}
+
+func TestIdempotencyMiddleware_ServerErrorIsRetried(t *testing.T) {
+	calls := 0
+	h := newIdempotencyHandler(newFakeIdempotencyService(), countingHandler(http.StatusInternalServerError, &calls))
+
+	doRequest(h, http.MethodPost, "/products", "key-1", `{}`)
+	doRequest(h, http.MethodPost, "/products", "key-1", `{}`)
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyMiddleware_Passthrough(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		key    string
+	}{
+		{name: "No key", method: http.MethodPost},
+		{name: "Not a POST", method: http.MethodGet, key: "key-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			h := newIdempotencyHandler(newFakeIdempotencyService(), countingHandler(http.StatusOK, &calls))
+
+			doRequest(h, tt.method, "/pvz", tt.key, `{}`)
+			doRequest(h, tt.method, "/pvz", tt.key, `{}`)
+
+			assert.Equal(t, 2, calls)
+		})
+	}
+}
+
+func TestIdempotencyMiddleware_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		key            string
+		beginErr       error
+		expectedStatus int
+	}{
+		{name: "Key too long", key: strings.Repeat("k", maxIdempotencyKeyLength+1), expectedStatus: http.StatusBadRequest},
+		{name: "Different request", key: "key-1", beginErr: e.ErrIdempotencyKeyMismatch(), expectedStatus: http.StatusUnprocessableEntity},
+		{name: "In progress", key: "key-1", beginErr: e.ErrIdempotencyKeyInProgress(), expectedStatus: http.StatusConflict},
+		{name: "Storage error", key: "key-1", beginErr: errors.New("db error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			svc := newFakeIdempotencyService()
+			svc.beginErr = tt.beginErr
+			h := newIdempotencyHandler(svc, countingHandler(http.StatusOK, &calls))
+
+			rec := doRequest(h, http.MethodPost, "/products", tt.key, `{}`)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, 0, calls)
+		})
+	}
+}
+
+func TestIdempotencyMiddleware_KeyReusedWithDifferentBody(t *testing.T) {
+	calls := 0
+	h := newIdempotencyHandler(newFakeIdempotencyService(), countingHandler(http.StatusCreated, &calls))
+
+	doRequest(h, http.MethodPost, "/products", "key-1", `{"type":"обувь"}`)
+	rec := doRequest(h, http.MethodPost, "/products", "key-1", `{"type":"одежда"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotencyMiddleware_KeyScopedToPath(t *testing.T) {
+	calls := 0
+	h := newIdempotencyHandler(newFakeIdempotencyService(), countingHandler(http.StatusCreated, &calls))
+
+	doRequest(h, http.MethodPost, "/products", "key-1", `{}`)
+	rec := doRequest(h, http.MethodPost, "/receptions", "key-1", `{}`)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyMiddleware_AnonymousKeyScopedToClient(t *testing.T) {
+	calls := 0
+	h := newIdempotencyHandler(newFakeIdempotencyService(), countingHandler(http.StatusCreated, &calls))
+
+	register := func(remoteAddr, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(body))
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	register("10.0.0.1:5000", `{"email":"first@example.com"}`)
+	rec := register("10.0.0.2:5000", `{"email":"second@example.com"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	// The same client retrying from another port is still replayed
+	rec = register("10.0.0.2:6000", `{"email":"second@example.com"}`)
+	assert.Equal(t, "true", rec.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 2, calls)
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	generate "pvz-service/api"
 	api "pvz-service/api/generated"
+	"pvz-service/internal/consistency"
 	"pvz-service/internal/controller/http/handler"
 	httpMiddleware "pvz-service/internal/controller/http/middleware"
 	"pvz-service/internal/metrics"
@@ -19,8 +20,21 @@ func Setup(
 	metrics *metrics.Metrics,
 	authService service.AuthService,
 	pvzService service.PVZService,
+	transferService service.TransferService,
+	manifestService service.ManifestService,
+	productConditionService service.ProductConditionService,
+	staleReceptionService service.StaleReceptionService,
+	archiveService service.ArchiveService,
+	webhookService service.WebhookService,
+	idempotencyService service.IdempotencyService,
+	tracker *consistency.Tracker,
 ) http.Handler {
-	h := handler.NewHandler(log, metrics, &authService, &pvzService)
+	h := handler.NewHandler(
+		log, metrics,
+		&authService, &pvzService, &transferService, &manifestService, &productConditionService,
+		&staleReceptionService, &archiveService, &webhookService,
+	)
+	idempotency := httpMiddleware.IdempotencyMiddleware(&idempotencyService, log)
 
 	router := chi.NewRouter()
 
@@ -36,18 +50,34 @@ func Setup(
 		// Раздача Swagger UI из embed
 		r.Handle("/*", http.FileServer(http.FS(generate.APIEmbeddedFiles)))
 
+		// /dummyLogin и /login не пишут в базу и безопасны для повтора,
+		// поэтому идут без идемпотентности: иначе выданный токен
+		// сохранялся бы в кеше ответов и возвращался повторно по ключу.
 		r.Post("/dummyLogin", h.DummyLogin())
-		r.Post("/register", h.Register())
+		r.With(idempotency).Post("/register", h.Register())
 		r.Post("/login", h.Login())
 	})
 
 	// Protected routes
 	router.Group(func(r chi.Router) {
 		r.Use(httpMiddleware.AuthMiddleware(&authService))
+		r.Use(httpMiddleware.ReadYourWritesMiddleware(tracker))
+		r.Use(idempotency)
 
 		// Routes for all auth users
 		r.Group(func(r chi.Router) {
 			r.Get("/pvz", h.GetPVZsWithReceptions())
+			r.Get("/pvz/nearby", h.GetNearbyPVZs())
+			r.Get("/pvz/{pvzId}/events", h.GetPVZEvents())
+			r.Get("/pvz/{pvzId}/schedule", h.GetPVZSchedule())
+			r.Get("/pvz/{pvzId}/occupancy", h.GetPVZOccupancy())
+			r.Get("/pvz/{pvzId}/archive", h.GetPVZArchive())
+			r.Get("/products/{productId}/history", h.GetProductHistory())
+			r.Get("/products/{productId}/photo", h.GetProductPhoto())
+			r.Get("/pvz/{pvzId}/transfers", h.GetPVZTransfers())
+			r.Get("/transfers/{transferId}", h.GetTransfer())
+			r.Get("/pvz/{pvzId}/manifests", h.GetPVZManifests())
+			r.Get("/manifests/{manifestId}", h.GetManifest())
 		})
 
 		// Routes for role='moderator'
@@ -55,6 +85,22 @@ func Setup(
 			r.Use(httpMiddleware.RoleMiddlewareMulti(api.UserRoleModerator))
 
 			r.Post("/pvz", h.CreatePVZ())
+			r.Patch("/pvz/{pvzId}", h.UpdatePVZ())
+			r.Delete("/pvz/{pvzId}", h.DeletePVZ())
+			r.Put("/pvz/{pvzId}/schedule", h.SetPVZSchedule())
+			r.Delete("/pvz/{pvzId}/schedule", h.DeletePVZSchedule())
+			r.Post("/pvz/{pvzId}/allow_reception", h.AllowReception())
+			r.Put("/pvz/{pvzId}/capacities", h.SetTypeCapacities())
+			r.Post("/manifests", h.CreateManifest())
+			r.Post("/products/{productId}/write_off", h.WriteOffProduct())
+			r.Post("/pvz/{pvzId}/force_close_reception", h.ForceCloseReception())
+			r.Get("/pvz/{pvzId}/reception_audit", h.GetPVZReceptionAudit())
+
+			r.Post("/webhooks", h.CreateWebhook())
+			r.Get("/webhooks", h.GetWebhooks())
+			r.Delete("/webhooks/{webhookId}", h.DeleteWebhook())
+			r.Get("/webhooks/{webhookId}/dead_letters", h.GetDeadLetters())
+			r.Post("/webhooks/deliveries/{deliveryId}/redeliver", h.Redeliver())
 		})
 
 		// Routes for role='employee'
@@ -65,6 +111,14 @@ func Setup(
 			r.Post("/products", h.AddProduct())
 			r.Post("/pvz/{pvzId}/delete_last_product", h.DeleteLastProduct())
 			r.Post("/pvz/{pvzId}/close_last_reception", h.CloseReception())
+			r.Post("/products/{productId}/issue", h.IssueProduct())
+			r.Post("/products/{productId}/return", h.ReturnProduct())
+			r.Put("/products/{productId}/condition", h.SetProductCondition())
+			r.Put("/products/{productId}/photo", h.UploadProductPhoto())
+			r.Post("/transfers", h.CreateTransfer())
+			r.Post("/transfers/{transferId}/dispatch", h.DispatchTransfer())
+			r.Post("/transfers/{transferId}/receive", h.ReceiveTransfer())
+			r.Post("/transfers/{transferId}/cancel", h.CancelTransfer())
 		})
 
 	})
@@ -12,25 +12,51 @@ var (
 	errNotFound      = errors.New("not found")
 	errAlreadyExists = errors.New("already exists")
 
-	errCityNotAllowed        = errors.New("city not allowed")
-	errProductTypeNotAllowed = errors.New("product type not allowed")
-	errActiveReceptionExists = errors.New("active reception already exists")
-	errNoActiveReception     = errors.New("no active reception")
-	errNoProduct             = errors.New("no product")
+	errCityNotAllowed          = errors.New("city not allowed")
+	errProductTypeNotAllowed   = errors.New("product type not allowed")
+	errActiveReceptionExists   = errors.New("active reception already exists")
+	errNoActiveReception       = errors.New("no active reception")
+	errNoProduct               = errors.New("no product")
+	errPVZNotActive            = errors.New("pvz is not active")
+	errOutsideWorkingHours     = errors.New("pvz is closed at this time")
+	errCapacityExceeded        = errors.New("pvz capacity exceeded")
+	errInvalidStatusTransition = errors.New("invalid product status transition")
+	errProductNotTransferable  = errors.New("product cannot be transferred")
+	errInvalidTransferState    = errors.New("invalid transfer status transition")
+	errManifestNotAvailable    = errors.New("manifest cannot be used for this reception")
+	errProductNotInReception   = errors.New("product is not in an open reception")
+
+	errVersionMismatch = errors.New("version mismatch")
+
+	errIdempotencyKeyMismatch   = errors.New("idempotency key reused with different request")
+	errIdempotencyKeyInProgress = errors.New("request with this idempotency key is in progress")
 
 	errInvalidCredentials = errors.New("invalid credentials")
 	errWrongSigningMethod = errors.New("unexpected signing method")
 )
 
-func ErrNotFound() error              { return errNotFound }
-func ErrAlreadyExists() error         { return errAlreadyExists }
-func ErrInvalidCredentials() error    { return errInvalidCredentials }
-func ErrWrongSigningMethod() error    { return errWrongSigningMethod }
-func ErrCityNotAllowed() error        { return errCityNotAllowed }
-func ErrActiveReceptionExists() error { return errActiveReceptionExists }
-func ErrNoActiveReception() error     { return errNoActiveReception }
-func ErrProductTypeNotAllowed() error { return errProductTypeNotAllowed }
-func ErrNoProduct() error             { return errNoProduct }
+func ErrNotFound() error                { return errNotFound }
+func ErrAlreadyExists() error           { return errAlreadyExists }
+func ErrInvalidCredentials() error      { return errInvalidCredentials }
+func ErrWrongSigningMethod() error      { return errWrongSigningMethod }
+func ErrCityNotAllowed() error          { return errCityNotAllowed }
+func ErrActiveReceptionExists() error   { return errActiveReceptionExists }
+func ErrNoActiveReception() error       { return errNoActiveReception }
+func ErrProductTypeNotAllowed() error   { return errProductTypeNotAllowed }
+func ErrNoProduct() error               { return errNoProduct }
+func ErrPVZNotActive() error            { return errPVZNotActive }
+func ErrOutsideWorkingHours() error     { return errOutsideWorkingHours }
+func ErrCapacityExceeded() error        { return errCapacityExceeded }
+func ErrInvalidStatusTransition() error { return errInvalidStatusTransition }
+func ErrProductNotTransferable() error  { return errProductNotTransferable }
+func ErrInvalidTransferState() error    { return errInvalidTransferState }
+func ErrManifestNotAvailable() error    { return errManifestNotAvailable }
+func ErrProductNotInReception() error   { return errProductNotInReception }
+
+func ErrVersionMismatch() error { return errVersionMismatch }
+
+func ErrIdempotencyKeyMismatch() error   { return errIdempotencyKeyMismatch }
+func ErrIdempotencyKeyInProgress() error { return errIdempotencyKeyInProgress }
 
 func ValidationError(errs validator.ValidationErrors) string {
 	var errMsgs []string
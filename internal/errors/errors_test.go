@@ -25,6 +25,17 @@ func TestErrorFunctions(t *testing.T) {
 		{"ErrNoActiveReception", ErrNoActiveReception, errNoActiveReception},
 		{"ErrProductTypeNotAllowed", ErrProductTypeNotAllowed, errProductTypeNotAllowed},
 		{"ErrNoProduct", ErrNoProduct, errNoProduct},
+		{"ErrPVZNotActive", ErrPVZNotActive, errPVZNotActive},
+		{"ErrOutsideWorkingHours", ErrOutsideWorkingHours, errOutsideWorkingHours},
+		{"ErrCapacityExceeded", ErrCapacityExceeded, errCapacityExceeded},
+		{"ErrInvalidStatusTransition", ErrInvalidStatusTransition, errInvalidStatusTransition},
+		{"ErrProductNotTransferable", ErrProductNotTransferable, errProductNotTransferable},
+		{"ErrInvalidTransferState", ErrInvalidTransferState, errInvalidTransferState},
+		{"ErrManifestNotAvailable", ErrManifestNotAvailable, errManifestNotAvailable},
+		{"ErrProductNotInReception", ErrProductNotInReception, errProductNotInReception},
+		{"ErrVersionMismatch", ErrVersionMismatch, errVersionMismatch},
+		{"ErrIdempotencyKeyMismatch", ErrIdempotencyKeyMismatch, errIdempotencyKeyMismatch},
+		{"ErrIdempotencyKeyInProgress", ErrIdempotencyKeyInProgress, errIdempotencyKeyInProgress},
 	}
 
 	for _, tt := range tests {
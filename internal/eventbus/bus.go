@@ -0,0 +1,65 @@
+package eventbus
+
+import (
+	"sync"
+
+	"pvz-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// subscriberBuffer is how many events a subscriber may lag behind before
+// new events for it are dropped.
+const subscriberBuffer = 64
+
+// Bus fans out domain events to in-process subscribers of a single PVZ.
+// Publish never blocks: a subscriber that does not keep up loses events
+// instead of stalling the request that produced them.
+type Bus struct {
+	mu   sync.RWMutex
+	subs map[uuid.UUID]map[chan models.Event]struct{}
+}
+
+func NewBus() *Bus {
+	return &Bus{subs: make(map[uuid.UUID]map[chan models.Event]struct{})}
+}
+
+// Subscribe returns a channel receiving events of pvzID and a function that
+// cancels the subscription and closes the channel.
+func (b *Bus) Subscribe(pvzID uuid.UUID) (<-chan models.Event, func()) {
+	ch := make(chan models.Event, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.subs[pvzID] == nil {
+		b.subs[pvzID] = make(map[chan models.Event]struct{})
+	}
+	b.subs[pvzID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs[pvzID], ch)
+			if len(b.subs[pvzID]) == 0 {
+				delete(b.subs, pvzID)
+			}
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+func (b *Bus) Publish(event models.Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subs[event.PVZID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package eventbus
+
+import (
+	"testing"
+
+	"pvz-service/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBus_PublishToSubscribers(t *testing.T) {
+	bus := NewBus()
+	pvzID, otherPVZID := uuid.New(), uuid.New()
+
+	first, unsubscribeFirst := bus.Subscribe(pvzID)
+	defer unsubscribeFirst()
+	second, unsubscribeSecond := bus.Subscribe(pvzID)
+	defer unsubscribeSecond()
+	other, unsubscribeOther := bus.Subscribe(otherPVZID)
+	defer unsubscribeOther()
+
+	event := models.Event{ID: uuid.New(), Type: models.EventProductAdded, PVZID: pvzID}
+	bus.Publish(event)
+
+	assert.Equal(t, event, <-first)
+	assert.Equal(t, event, <-second)
+	assert.Len(t, other, 0)
+}
+
+func TestBus_Unsubscribe(t *testing.T) {
+	bus := NewBus()
+	pvzID := uuid.New()
+
+	ch, unsubscribe := bus.Subscribe(pvzID)
+	unsubscribe()
+	unsubscribe() // Second call is a no-op
+
+	_, ok := <-ch
+	assert.False(t, ok)
+	assert.Empty(t, bus.subs)
+
+	// Publishing without subscribers must not panic
+	bus.Publish(models.Event{PVZID: pvzID})
+}
+
+func TestBus_SlowSubscriberDoesNotBlock(t *testing.T) {
+	bus := NewBus()
+	pvzID := uuid.New()
+
+	ch, unsubscribe := bus.Subscribe(pvzID)
+	defer unsubscribe()
+
+	for i := 0; i < subscriberBuffer+10; i++ {
+		bus.Publish(models.Event{ID: uuid.New(), PVZID: pvzID})
+	}
+
+	assert.Len(t, ch, subscriberBuffer)
+}
@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DBStatsSource возвращает текущее состояние пула соединений с базой
+type DBStatsSource func() sql.DBStats
+
+// dbStatsCollector снимает статистику пула соединений при каждом сборе метрик
+type dbStatsCollector struct {
+	source DBStatsSource
+
+	maxOpen      *prometheus.Desc
+	open         *prometheus.Desc
+	inUse        *prometheus.Desc
+	idle         *prometheus.Desc
+	waitCount    *prometheus.Desc
+	waitDuration *prometheus.Desc
+	closed       *prometheus.Desc
+}
+
+func newDBStatsCollector(source DBStatsSource) *dbStatsCollector {
+	return &dbStatsCollector{
+		source: source,
+		maxOpen: prometheus.NewDesc(
+			"db_pool_max_open_connections",
+			"Maximum number of open connections to the database",
+			nil, nil,
+		),
+		open: prometheus.NewDesc(
+			"db_pool_open_connections",
+			"Number of established connections, both in use and idle",
+			nil, nil,
+		),
+		inUse: prometheus.NewDesc(
+			"db_pool_in_use_connections",
+			"Number of connections currently in use",
+			nil, nil,
+		),
+		idle: prometheus.NewDesc(
+			"db_pool_idle_connections",
+			"Number of idle connections",
+			nil, nil,
+		),
+		waitCount: prometheus.NewDesc(
+			"db_pool_wait_count_total",
+			"Total number of connections waited for",
+			nil, nil,
+		),
+		waitDuration: prometheus.NewDesc(
+			"db_pool_wait_duration_seconds_total",
+			"Total time blocked waiting for a new connection",
+			nil, nil,
+		),
+		closed: prometheus.NewDesc(
+			"db_pool_closed_connections_total",
+			"Total number of connections closed by the pool limits",
+			[]string{"reason"}, nil,
+		),
+	}
+}
+
+// CollectDBStats регистрирует метрики пула соединений с базой
+func (m *Metrics) CollectDBStats(source DBStatsSource) {
+	prometheus.MustRegister(newDBStatsCollector(source))
+}
+
+func (c *dbStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.maxOpen
+	ch <- c.open
+	ch <- c.inUse
+	ch <- c.idle
+	ch <- c.waitCount
+	ch <- c.waitDuration
+	ch <- c.closed
+}
+
+func (c *dbStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.source()
+
+	ch <- prometheus.MustNewConstMetric(c.maxOpen, prometheus.GaugeValue, float64(stats.MaxOpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.open, prometheus.GaugeValue, float64(stats.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.inUse, prometheus.GaugeValue, float64(stats.InUse))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(stats.Idle))
+	ch <- prometheus.MustNewConstMetric(c.waitCount, prometheus.CounterValue, float64(stats.WaitCount))
+	ch <- prometheus.MustNewConstMetric(c.waitDuration, prometheus.CounterValue, stats.WaitDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.closed, prometheus.CounterValue, float64(stats.MaxIdleClosed), "max_idle")
+	ch <- prometheus.MustNewConstMetric(c.closed, prometheus.CounterValue, float64(stats.MaxIdleTimeClosed), "max_idle_time")
+	ch <- prometheus.MustNewConstMetric(c.closed, prometheus.CounterValue, float64(stats.MaxLifetimeClosed), "max_lifetime")
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBStatsCollector(t *testing.T) {
+	collector := newDBStatsCollector(func() sql.DBStats {
+		return sql.DBStats{
+			MaxOpenConnections: 25,
+			OpenConnections:    7,
+			InUse:              4,
+			Idle:               3,
+			WaitCount:          12,
+			WaitDuration:       1500 * time.Millisecond,
+			MaxIdleClosed:      2,
+			MaxIdleTimeClosed:  5,
+			MaxLifetimeClosed:  1,
+		}
+	})
+
+	expected := `
+# HELP db_pool_closed_connections_total Total number of connections closed by the pool limits
+# TYPE db_pool_closed_connections_total counter
+db_pool_closed_connections_total{reason="max_idle"} 2
+db_pool_closed_connections_total{reason="max_idle_time"} 5
+db_pool_closed_connections_total{reason="max_lifetime"} 1
+# HELP db_pool_idle_connections Number of idle connections
+# TYPE db_pool_idle_connections gauge
+db_pool_idle_connections 3
+# HELP db_pool_in_use_connections Number of connections currently in use
+# TYPE db_pool_in_use_connections gauge
+db_pool_in_use_connections 4
+# HELP db_pool_max_open_connections Maximum number of open connections to the database
+# TYPE db_pool_max_open_connections gauge
+db_pool_max_open_connections 25
+# HELP db_pool_open_connections Number of established connections, both in use and idle
+# TYPE db_pool_open_connections gauge
+db_pool_open_connections 7
+# HELP db_pool_wait_count_total Total number of connections waited for
+# TYPE db_pool_wait_count_total counter
+db_pool_wait_count_total 12
+# HELP db_pool_wait_duration_seconds_total Total time blocked waiting for a new connection
+# TYPE db_pool_wait_duration_seconds_total counter
+db_pool_wait_duration_seconds_total 1.5
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
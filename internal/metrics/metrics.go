@@ -30,6 +30,11 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return rw.ResponseWriter.Write(b)
 }
 
+// Unwrap даёт http.ResponseController доступ к Flush исходного writer'а
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 type Metrics struct {
 	// Технические метрики
 	HTTPRequestsTotal *prometheus.CounterVec
@@ -39,6 +44,23 @@ type Metrics struct {
 	PVZCreated        prometheus.Counter
 	ReceptionsCreated prometheus.Counter
 	ProductsAdded     prometheus.Counter
+
+	// Приемки, закрытые планировщиком или модератором, и приемки,
+	// помеченные как зависшие
+	ReceptionsForceClosed  *prometheus.CounterVec
+	StaleReceptionsFlagged prometheus.Counter
+
+	// Приемки и товары, перенесенные в архив, и запуски архивации
+	ReceptionsArchived prometheus.Counter
+	ProductsArchived   prometheus.Counter
+	ArchiveRunDuration prometheus.Histogram
+	ArchiveRunFailures prometheus.Counter
+	ArchiveLastSuccess prometheus.Gauge
+
+	// Партиции приемок и товаров, созданные заранее, и запуски их обслуживания
+	PartitionsCreated    prometheus.Counter
+	PartitionRunFailures prometheus.Counter
+	PartitionLastSuccess prometheus.Gauge
 }
 
 func NewMetrics() *Metrics {
@@ -76,6 +98,68 @@ func NewMetrics() *Metrics {
 				Help: "Total number of products added",
 			},
 		),
+		ReceptionsForceClosed: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "receptions_force_closed_total",
+				Help: "Total number of receptions closed by the stale receptions scheduler or a moderator",
+			},
+			[]string{"initiator"},
+		),
+		StaleReceptionsFlagged: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "receptions_stale_flagged_total",
+				Help: "Total number of receptions flagged as stale",
+			},
+		),
+		ReceptionsArchived: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "receptions_archived_total",
+				Help: "Total number of closed receptions moved to the archive",
+			},
+		),
+		ProductsArchived: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "products_archived_total",
+				Help: "Total number of products moved to the archive with their receptions",
+			},
+		),
+		ArchiveRunDuration: promauto.NewHistogram(
+			prometheus.HistogramOpts{
+				Name:    "archive_run_duration_seconds",
+				Help:    "Duration of reception archive runs in seconds",
+				Buckets: []float64{0.1, 0.5, 1, 5, 30, 60, 300},
+			},
+		),
+		ArchiveRunFailures: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "archive_run_failures_total",
+				Help: "Total number of reception archive runs that failed",
+			},
+		),
+		ArchiveLastSuccess: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "archive_last_success_timestamp_seconds",
+				Help: "Unix time of the last reception archive run that succeeded",
+			},
+		),
+		PartitionsCreated: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "partitions_created_total",
+				Help: "Total number of monthly partitions created for receptions and products",
+			},
+		),
+		PartitionRunFailures: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "partition_run_failures_total",
+				Help: "Total number of partition maintenance runs that failed",
+			},
+		),
+		PartitionLastSuccess: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "partition_last_success_timestamp_seconds",
+				Help: "Unix time of the last partition maintenance run that succeeded",
+			},
+		),
 	}
 }
 
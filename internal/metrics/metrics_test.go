@@ -41,6 +41,17 @@ func TestResponseWriter(t *testing.T) {
 		assert.Equal(t, "test", rec.Body.String())
 	})
 
+	t.Run("Unwrap allows flushing", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		rw := &responseWriter{
+			ResponseWriter: rec,
+			status:         http.StatusOK,
+		}
+
+		assert.NoError(t, http.NewResponseController(rw).Flush())
+		assert.True(t, rec.Flushed)
+	})
+
 	t.Run("Write preserves custom status", func(t *testing.T) {
 		rec := httptest.NewRecorder()
 		rw := &responseWriter{
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"context"
+	"pvz-service/internal/models"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// occupancyTimeout ограничивает время чтения заполненности ПВЗ при сборе метрик
+const occupancyTimeout = 5 * time.Second
+
+// OccupancySource возвращает текущую заполненность всех активных ПВЗ
+type OccupancySource func(ctx context.Context) ([]models.PVZOccupancy, error)
+
+// occupancyCollector читает заполненность ПВЗ из хранилища при каждом сборе
+// метрик, поэтому значения совпадают на всех репликах сервиса
+type occupancyCollector struct {
+	source      OccupancySource
+	items       *prometheus.Desc
+	utilization *prometheus.Desc
+}
+
+func newOccupancyCollector(source OccupancySource) *occupancyCollector {
+	return &occupancyCollector{
+		source: source,
+		items: prometheus.NewDesc(
+			"pvz_occupancy_items",
+			"Number of products currently held by a PVZ",
+			[]string{"pvz_id"}, nil,
+		),
+		utilization: prometheus.NewDesc(
+			"pvz_capacity_utilization_ratio",
+			"Share of the PVZ capacity taken by products, for PVZs with limited capacity",
+			[]string{"pvz_id"}, nil,
+		),
+	}
+}
+
+// CollectOccupancy регистрирует метрики заполненности ПВЗ
+func (m *Metrics) CollectOccupancy(source OccupancySource) {
+	prometheus.MustRegister(newOccupancyCollector(source))
+}
+
+func (c *occupancyCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.items
+	ch <- c.utilization
+}
+
+func (c *occupancyCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), occupancyTimeout)
+	defer cancel()
+
+	occupancy, err := c.source(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.items, err)
+		return
+	}
+
+	for _, o := range occupancy {
+		pvzID := o.PVZID.String()
+		ch <- prometheus.MustNewConstMetric(c.items, prometheus.GaugeValue, float64(o.Count), pvzID)
+		if o.Capacity > 0 {
+			ch <- prometheus.MustNewConstMetric(c.utilization, prometheus.GaugeValue, o.Utilization(), pvzID)
+		}
+	}
+}
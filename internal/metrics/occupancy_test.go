@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"context"
+	"pvz-service/internal/models"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOccupancyCollector(t *testing.T) {
+	limitedID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	unlimitedID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+
+	t.Run("exports occupancy and utilization", func(t *testing.T) {
+		collector := newOccupancyCollector(func(ctx context.Context) ([]models.PVZOccupancy, error) {
+			return []models.PVZOccupancy{
+				{PVZID: limitedID, Count: 30, Capacity: 40},
+				{PVZID: unlimitedID, Count: 5},
+			}, nil
+		})
+
+		expected := `
+# HELP pvz_capacity_utilization_ratio Share of the PVZ capacity taken by products, for PVZs with limited capacity
+# TYPE pvz_capacity_utilization_ratio gauge
+pvz_capacity_utilization_ratio{pvz_id="11111111-1111-1111-1111-111111111111"} 0.75
+# HELP pvz_occupancy_items Number of products currently held by a PVZ
+# TYPE pvz_occupancy_items gauge
+pvz_occupancy_items{pvz_id="11111111-1111-1111-1111-111111111111"} 30
+pvz_occupancy_items{pvz_id="22222222-2222-2222-2222-222222222222"} 5
+`
+		assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+	})
+
+	t.Run("reports source errors", func(t *testing.T) {
+		registry := prometheus.NewPedanticRegistry()
+		registry.MustRegister(newOccupancyCollector(func(ctx context.Context) ([]models.PVZOccupancy, error) {
+			return nil, assert.AnError
+		}))
+
+		_, err := registry.Gather()
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ETag identifies a version of a single PVZ or reception. It is rendered as
+// "<id>.<version>" so that tags of different resources never collide.
+type ETag struct {
+	ID      uuid.UUID
+	Version int
+}
+
+func (t ETag) String() string {
+	return fmt.Sprintf(`"%s.%d"`, t.ID, t.Version)
+}
+
+func ParseETag(s string) (ETag, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return ETag{}, errors.New("etag must be a quoted string")
+	}
+
+	id, version, ok := strings.Cut(s[1:len(s)-1], ".")
+	if !ok {
+		return ETag{}, errors.New("etag has no version")
+	}
+
+	var (
+		tag ETag
+		err error
+	)
+	if tag.ID, err = uuid.Parse(id); err != nil {
+		return ETag{}, fmt.Errorf("invalid etag id: %w", err)
+	}
+	if tag.Version, err = strconv.Atoi(version); err != nil || tag.Version < 1 {
+		return ETag{}, errors.New("invalid etag version")
+	}
+
+	return tag, nil
+}
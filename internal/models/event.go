@@ -0,0 +1,31 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	EventReceptionOpened EventType = "reception.opened"
+	EventReceptionClosed EventType = "reception.closed"
+	EventProductAdded    EventType = "product.added"
+	EventProductDeleted  EventType = "product.deleted"
+
+	EventProductStatusChanged    EventType = "product.status_changed"
+	EventProductConditionChanged EventType = "product.condition_changed"
+)
+
+// Event is a domain event recorded in the outbox together with the change it describes.
+type Event struct {
+	ID          uuid.UUID       `db:"id" json:"id"`
+	Type        EventType       `db:"event_type" json:"type"`
+	PVZID       uuid.UUID       `db:"pvz_id" json:"pvzId"`
+	ReceptionID uuid.UUID       `db:"reception_id" json:"receptionId"`
+	Payload     json.RawMessage `db:"payload" json:"payload"`
+	CreatedAt   time.Time       `db:"created_at" json:"createdAt"`
+	Attempts    int             `db:"attempts" json:"-"`
+}
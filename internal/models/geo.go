@@ -0,0 +1,49 @@
+package models
+
+import "math"
+
+// EarthRadius is the mean Earth radius in meters used for distance searches
+const EarthRadius = 6371000.0
+
+type GeoPoint struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Valid reports whether the point has coordinates within WGS 84 bounds
+func (p GeoPoint) Valid() bool {
+	return p.Latitude >= -90 && p.Latitude <= 90 && p.Longitude >= -180 && p.Longitude <= 180
+}
+
+// BoundingBox returns the latitude and longitude ranges covering every point
+// within radius meters of p. Near the poles or the antimeridian the longitude
+// range widens to the whole globe instead of wrapping.
+func (p GeoPoint) BoundingBox(radius float64) (minLat, maxLat, minLon, maxLon float64) {
+	delta := radius / EarthRadius * 180 / math.Pi
+
+	minLat = math.Max(p.Latitude-delta, -90)
+	maxLat = math.Min(p.Latitude+delta, 90)
+	if minLat == -90 || maxLat == 90 {
+		return minLat, maxLat, -180, 180
+	}
+
+	lonDelta := delta / math.Cos(p.Latitude*math.Pi/180)
+	minLon = p.Longitude - lonDelta
+	maxLon = p.Longitude + lonDelta
+	if minLon < -180 || maxLon > 180 {
+		return minLat, maxLat, -180, 180
+	}
+
+	return minLat, maxLat, minLon, maxLon
+}
+
+// Distance returns the great-circle distance to q in meters
+func (p GeoPoint) Distance(q GeoPoint) float64 {
+	lat1 := p.Latitude * math.Pi / 180
+	lat2 := q.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (q.Longitude - p.Longitude) * math.Pi / 180
+
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dLon/2), 2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
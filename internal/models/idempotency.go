@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// IdempotencyRecord is the stored outcome of a write request sent with an
+// Idempotency-Key. StatusCode stays zero while the original request is running.
+// A request still running past LockedUntil is presumed lost, and a retry of
+// it may take the key over.
+type IdempotencyRecord struct {
+	Key         string    `db:"key"`
+	Fingerprint string    `db:"fingerprint"`
+	StatusCode  int       `db:"status_code"`
+	ContentType string    `db:"content_type"`
+	Body        []byte    `db:"response_body"`
+	CreatedAt   time.Time `db:"created_at"`
+	ExpiresAt   time.Time `db:"expires_at"`
+	LockedUntil time.Time `db:"locked_until"`
+}
+
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ManifestStatus string
+
+// A manifest is pending until a reception is started with it, receiving while
+// that reception is open and reconciled once it is closed.
+const (
+	ManifestStatusPending    ManifestStatus = "pending"
+	ManifestStatusReceiving  ManifestStatus = "receiving"
+	ManifestStatusReconciled ManifestStatus = "reconciled"
+)
+
+// Manifest is the list of items a supplier expects to deliver to a PVZ
+type Manifest struct {
+	ID           uuid.UUID          `db:"id" json:"id"`
+	PVZID        uuid.UUID          `db:"pvz_id" json:"pvzId"`
+	Supplier     string             `db:"supplier" json:"supplier"`
+	Status       ManifestStatus     `db:"status" json:"status"`
+	ReceptionID  *uuid.UUID         `db:"reception_id" json:"receptionId,omitempty"`
+	Items        []ManifestItem     `db:"-" json:"items"`
+	Report       *DiscrepancyReport `db:"report" json:"report,omitempty"`
+	CreatedAt    time.Time          `db:"created_at" json:"createdAt"`
+	ReconciledAt *time.Time         `db:"reconciled_at" json:"reconciledAt,omitempty"`
+}
+
+type ManifestItem struct {
+	Barcode  string `db:"barcode" json:"barcode"`
+	TypeID   int    `db:"type_id" json:"-"`
+	TypeName string `db:"type_name" json:"type"`
+}
+
+type DuplicateItem struct {
+	Barcode string `json:"barcode"`
+	Count   int    `json:"count"`
+}
+
+// DiscrepancyReport compares the products accepted in a reception with its
+// manifest. Unlabeled counts the products accepted without a barcode.
+type DiscrepancyReport struct {
+	Expected   int             `json:"expected"`
+	Received   int             `json:"received"`
+	Missing    []ManifestItem  `json:"missing"`
+	Unexpected []string        `json:"unexpected"`
+	Duplicates []DuplicateItem `json:"duplicates"`
+	Unlabeled  int             `json:"unlabeled"`
+}
+
+// HasDiscrepancies reports whether the delivery differs from the manifest
+func (r *DiscrepancyReport) HasDiscrepancies() bool {
+	return len(r.Missing) > 0 || len(r.Unexpected) > 0 || len(r.Duplicates) > 0 || r.Unlabeled > 0
+}
+
+// Reconcile builds the discrepancy report of a delivery. scanned maps the
+// barcodes of the accepted products to the number of times each was scanned.
+func Reconcile(expected []ManifestItem, scanned map[string]int, unlabeled int) *DiscrepancyReport {
+	report := &DiscrepancyReport{
+		Expected:   len(expected),
+		Missing:    []ManifestItem{},
+		Unexpected: []string{},
+		Duplicates: []DuplicateItem{},
+		Unlabeled:  unlabeled,
+	}
+
+	inManifest := make(map[string]bool, len(expected))
+	for _, item := range expected {
+		inManifest[item.Barcode] = true
+		if scanned[item.Barcode] == 0 {
+			report.Missing = append(report.Missing, item)
+		}
+	}
+
+	for barcode, count := range scanned {
+		report.Received += count
+		if !inManifest[barcode] {
+			report.Unexpected = append(report.Unexpected, barcode)
+		}
+		if count > 1 {
+			report.Duplicates = append(report.Duplicates, DuplicateItem{Barcode: barcode, Count: count})
+		}
+	}
+	report.Received += unlabeled
+
+	sort.Slice(report.Missing, func(i, j int) bool { return report.Missing[i].Barcode < report.Missing[j].Barcode })
+	sort.Strings(report.Unexpected)
+	sort.Slice(report.Duplicates, func(i, j int) bool { return report.Duplicates[i].Barcode < report.Duplicates[j].Barcode })
+
+	return report
+}
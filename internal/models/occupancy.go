@@ -0,0 +1,36 @@
+package models
+
+import "github.com/google/uuid"
+
+// TypeCapacity limits how many products of one type a PVZ may hold
+type TypeCapacity struct {
+	TypeID   int    `json:"-"`
+	TypeName string `json:"type"`
+	Capacity int    `json:"capacity"`
+}
+
+// TypeOccupancy is the number of products of one type held by a PVZ.
+// Capacity is zero when the type has no own limit.
+type TypeOccupancy struct {
+	TypeName string `json:"type"`
+	Count    int    `json:"count"`
+	Capacity int    `json:"capacity,omitempty"`
+}
+
+// PVZOccupancy is the number of products a PVZ currently holds. Capacity is
+// zero when the PVZ has no overall limit.
+type PVZOccupancy struct {
+	PVZID    uuid.UUID       `json:"pvzId"`
+	Count    int             `json:"count"`
+	Capacity int             `json:"capacity,omitempty"`
+	Types    []TypeOccupancy `json:"types,omitempty"`
+}
+
+// Utilization returns the occupied share of the PVZ capacity, or zero when
+// the capacity is not limited
+func (o PVZOccupancy) Utilization() float64 {
+	if o.Capacity <= 0 {
+		return 0
+	}
+	return float64(o.Count) / float64(o.Capacity)
+}
@@ -6,10 +6,93 @@ import (
 	"github.com/google/uuid"
 )
 
+type ProductStatus string
+
+const (
+	ProductStatusAccepted   ProductStatus = "accepted"
+	ProductStatusStored     ProductStatus = "stored"
+	ProductStatusIssued     ProductStatus = "issued"
+	ProductStatusReturned   ProductStatus = "returned"
+	ProductStatusWrittenOff ProductStatus = "written_off"
+	ProductStatusInTransit  ProductStatus = "in_transit"
+)
+
+// productTransitions lists the statuses a product may move to from each one.
+// Accepted products are stored once their reception is closed. Transferred
+// products are accepted again in a reception of the receiving PVZ.
+var productTransitions = map[ProductStatus][]ProductStatus{
+	ProductStatusAccepted:  {ProductStatusStored, ProductStatusWrittenOff},
+	ProductStatusStored:    {ProductStatusIssued, ProductStatusWrittenOff, ProductStatusInTransit},
+	ProductStatusIssued:    {ProductStatusReturned},
+	ProductStatusReturned:  {ProductStatusWrittenOff},
+	ProductStatusInTransit: {ProductStatusAccepted, ProductStatusWrittenOff},
+}
+
+// CanTransitionTo reports whether a product with the status may move to next
+func (s ProductStatus) CanTransitionTo(next ProductStatus) bool {
+	for _, allowed := range productTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// InPVZ reports whether a product with the status is physically held by the
+// PVZ and so takes up its capacity
+func (s ProductStatus) InPVZ() bool {
+	return s == ProductStatusAccepted || s == ProductStatusStored || s == ProductStatusReturned
+}
+
+// Settled reports whether a product with the status has left the PVZ. A
+// closed reception whose products are all settled may be archived once the
+// retention period is over.
+func (s ProductStatus) Settled() bool {
+	return s == ProductStatusIssued || s == ProductStatusWrittenOff
+}
+
+// ProductCondition is the state an employee found a product in when it was
+// accepted
+type ProductCondition string
+
+const (
+	ProductConditionOK        ProductCondition = "ok"
+	ProductConditionDamaged   ProductCondition = "damaged"
+	ProductConditionWrongItem ProductCondition = "wrong_item"
+)
+
+// PhotoExtensions maps the content types accepted for condition photos to
+// the extension the photo is stored with
+var PhotoExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// Product is an item accepted by a PVZ. PhotoKey is the storage key of the
+// photo attached to the condition report, if any.
 type Product struct {
-	ID          uuid.UUID `db:"id" json:"id"`
-	DateTime    time.Time `db:"date_time" json:"dateTime"`
-	TypeID      int       `db:"type_id" json:"-"`
-	TypeName    string    `db:"type_name" json:"type"`
-	ReceptionID uuid.UUID `db:"reception_id" json:"receptionId"`
+	ID            uuid.UUID        `db:"id" json:"id"`
+	DateTime      time.Time        `db:"date_time" json:"dateTime"`
+	TypeID        int              `db:"type_id" json:"-"`
+	TypeName      string           `db:"type_name" json:"type"`
+	ReceptionID   uuid.UUID        `db:"reception_id" json:"receptionId"`
+	Status        ProductStatus    `db:"status" json:"status"`
+	Barcode       string           `db:"barcode" json:"barcode,omitempty"`
+	Condition     ProductCondition `db:"condition" json:"condition"`
+	ConditionNote string           `db:"condition_note" json:"conditionNote,omitempty"`
+	PhotoKey      string           `db:"photo_key" json:"-"`
+	HasPhoto      bool             `db:"-" json:"hasPhoto"`
+}
+
+// ProductStatusChange is an entry of the product history. From is empty for
+// the entry made when the product was accepted.
+type ProductStatusChange struct {
+	ProductID   uuid.UUID     `db:"product_id" json:"productId"`
+	PVZID       uuid.UUID     `db:"pvz_id" json:"pvzId"`
+	ReceptionID uuid.UUID     `db:"reception_id" json:"receptionId"`
+	From        ProductStatus `db:"from_status" json:"from,omitempty"`
+	To          ProductStatus `db:"to_status" json:"to"`
+	Comment     string        `db:"comment" json:"comment,omitempty"`
+	ChangedAt   time.Time     `db:"changed_at" json:"changedAt"`
 }
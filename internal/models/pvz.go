@@ -6,9 +6,40 @@ import (
 	"github.com/google/uuid"
 )
 
+type PVZStatus string
+
+const (
+	PVZStatusActive    PVZStatus = "active"
+	PVZStatusSuspended PVZStatus = "suspended"
+	PVZStatusClosed    PVZStatus = "closed"
+)
+
 type PVZ struct {
-	ID               uuid.UUID `db:"id" json:"id"`
-	RegistrationDate time.Time `db:"registration_date" json:"registrationDate"`
-	CityID           int       `db:"city_id" json:"-"`
-	CityName         string    `db:"city_name" json:"city"`
+	ID               uuid.UUID  `db:"id" json:"id"`
+	RegistrationDate time.Time  `db:"registration_date" json:"registrationDate"`
+	CityID           int        `db:"city_id" json:"-"`
+	CityName         string     `db:"city_name" json:"city"`
+	Address          string     `db:"address" json:"address,omitempty"`
+	Location         *GeoPoint  `json:"location,omitempty"`
+	Capacity         int        `db:"capacity" json:"capacity,omitempty"`
+	Status           PVZStatus  `db:"status" json:"status"`
+	DeletedAt        *time.Time `db:"deleted_at" json:"deletedAt,omitempty"`
+	Version          int        `db:"version" json:"-"`
+}
+
+// PVZUpdate holds the PVZ fields a moderator may change; nil fields are kept.
+// ClearLocation removes the location, which a nil Location keeps.
+type PVZUpdate struct {
+	CityName      *string
+	Address       *string
+	Location      *GeoPoint
+	ClearLocation bool
+	Capacity      *int
+	Status        *PVZStatus
+}
+
+// NearbyPVZ is a PVZ found by a distance search
+type NearbyPVZ struct {
+	PVZ      PVZ     `json:"pvz"`
+	Distance float64 `json:"distance"`
 }
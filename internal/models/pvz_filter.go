@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// PVZFilter narrows PVZ listings. Zero values mean "no restriction".
+type PVZFilter struct {
+	Cities []string
+	From   time.Time
+	To     time.Time
+}
@@ -13,9 +13,15 @@ const (
 	ReceptionStatusClose      ReceptionStatus = "close"
 )
 
+// Reception is a delivery accepted by a PVZ. ManifestID is set when the
+// reception was started with a manifest, and Discrepancies is the report
+// made against it when the reception is closed.
 type Reception struct {
-	ID       uuid.UUID       `db:"id" json:"id"`
-	DateTime time.Time       `db:"date_time" json:"dateTime"`
-	PVZID    uuid.UUID       `db:"pvz_id" json:"pvzId"`
-	Status   ReceptionStatus `db:"status" json:"status"`
+	ID            uuid.UUID          `db:"id" json:"id"`
+	DateTime      time.Time          `db:"date_time" json:"dateTime"`
+	PVZID         uuid.UUID          `db:"pvz_id" json:"pvzId"`
+	Status        ReceptionStatus    `db:"status" json:"status"`
+	Version       int                `db:"version" json:"-"`
+	ManifestID    *uuid.UUID         `db:"-" json:"manifestId,omitempty"`
+	Discrepancies *DiscrepancyReport `db:"-" json:"discrepancies,omitempty"`
 }
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReceptionAuditAction string
+
+const (
+	ReceptionAuditAutoClosed   ReceptionAuditAction = "auto_closed"
+	ReceptionAuditFlaggedStale ReceptionAuditAction = "flagged_stale"
+	ReceptionAuditForceClosed  ReceptionAuditAction = "force_closed"
+)
+
+// ReceptionAuditActorSystem is the actor of the entries made by the stale
+// receptions scheduler
+const ReceptionAuditActorSystem = "system"
+
+// ReceptionAuditEntry records an action on a reception taken by the service
+// or a moderator rather than by the PVZ employee
+type ReceptionAuditEntry struct {
+	ReceptionID uuid.UUID            `db:"reception_id" json:"receptionId"`
+	PVZID       uuid.UUID            `db:"pvz_id" json:"pvzId"`
+	Action      ReceptionAuditAction `db:"action" json:"action"`
+	Actor       string               `db:"actor" json:"actor"`
+	Reason      string               `db:"reason" json:"reason,omitempty"`
+	CreatedAt   time.Time            `db:"created_at" json:"createdAt"`
+}
+
+// StaleReception is a reception in progress without new products since
+// LastActivity. Flagged is set once it has been reported as stale.
+type StaleReception struct {
+	Reception
+	LastActivity time.Time
+	Flagged      bool
+}
@@ -0,0 +1,176 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduleInterval is a working interval within a day. Both bounds use the
+// "15:04" layout; Closes may be "24:00" to keep the PVZ open until midnight.
+type ScheduleInterval struct {
+	Opens  string `json:"opens"`
+	Closes string `json:"closes"`
+}
+
+// ScheduleDay lists the working intervals of a weekday. Weekdays missing
+// from the weekly schedule are days off.
+type ScheduleDay struct {
+	Weekday   string             `json:"weekday"`
+	Intervals []ScheduleInterval `json:"intervals"`
+}
+
+// ScheduleException overrides the weekly schedule on a single date, e.g. a
+// holiday. A date without intervals is a day off.
+type ScheduleException struct {
+	Date      string             `json:"date"`
+	Intervals []ScheduleInterval `json:"intervals"`
+	Note      string             `json:"note,omitempty"`
+}
+
+// PVZSchedule holds the working hours of a PVZ in its local timezone.
+// Until ReceptionsAllowedUntil, set by a moderator, receptions may also be
+// started outside the working hours.
+type PVZSchedule struct {
+	PVZID                  uuid.UUID           `json:"pvzId"`
+	Timezone               string              `json:"timezone"`
+	Weekly                 []ScheduleDay       `json:"weekly"`
+	Exceptions             []ScheduleException `json:"exceptions"`
+	ReceptionsAllowedUntil *time.Time          `json:"receptionsAllowedUntil,omitempty"`
+	UpdatedAt              time.Time           `json:"updatedAt"`
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// Validate checks the timezone, weekdays, dates and intervals of the schedule
+func (s PVZSchedule) Validate() error {
+	if s.Timezone == "" {
+		return errors.New("timezone is required")
+	}
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", s.Timezone)
+	}
+
+	seenDays := make(map[string]bool, len(s.Weekly))
+	for _, day := range s.Weekly {
+		if _, ok := weekdays[day.Weekday]; !ok {
+			return fmt.Errorf("unknown weekday %q", day.Weekday)
+		}
+		if seenDays[day.Weekday] {
+			return fmt.Errorf("weekday %s is listed twice", day.Weekday)
+		}
+		seenDays[day.Weekday] = true
+
+		if err := validateIntervals(day.Intervals); err != nil {
+			return fmt.Errorf("%s: %w", day.Weekday, err)
+		}
+	}
+
+	seenDates := make(map[string]bool, len(s.Exceptions))
+	for _, exception := range s.Exceptions {
+		if _, err := time.Parse(time.DateOnly, exception.Date); err != nil {
+			return fmt.Errorf("invalid exception date %q", exception.Date)
+		}
+		if seenDates[exception.Date] {
+			return fmt.Errorf("exception date %s is listed twice", exception.Date)
+		}
+		seenDates[exception.Date] = true
+
+		if err := validateIntervals(exception.Intervals); err != nil {
+			return fmt.Errorf("%s: %w", exception.Date, err)
+		}
+	}
+
+	return nil
+}
+
+// IsOpen reports whether t falls into working hours. An exception for the
+// local date of t replaces the weekly schedule of that day.
+func (s PVZSchedule) IsOpen(t time.Time) bool {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := t.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+
+	date := local.Format(time.DateOnly)
+	for _, exception := range s.Exceptions {
+		if exception.Date == date {
+			return containsMinute(exception.Intervals, minute)
+		}
+	}
+
+	for _, day := range s.Weekly {
+		if weekdays[day.Weekday] == local.Weekday() {
+			return containsMinute(day.Intervals, minute)
+		}
+	}
+
+	return false
+}
+
+// AcceptsReceptions reports whether a reception may be started at t: within
+// working hours or before ReceptionsAllowedUntil.
+func (s PVZSchedule) AcceptsReceptions(t time.Time) bool {
+	if s.ReceptionsAllowedUntil != nil && t.Before(*s.ReceptionsAllowedUntil) {
+		return true
+	}
+	return s.IsOpen(t)
+}
+
+func validateIntervals(intervals []ScheduleInterval) error {
+	for _, interval := range intervals {
+		opens, err := parseClock(interval.Opens)
+		if err != nil {
+			return err
+		}
+		closes, err := parseClock(interval.Closes)
+		if err != nil {
+			return err
+		}
+		if opens >= closes {
+			return fmt.Errorf("interval %s-%s must open before it closes", interval.Opens, interval.Closes)
+		}
+	}
+	return nil
+}
+
+func containsMinute(intervals []ScheduleInterval, minute int) bool {
+	for _, interval := range intervals {
+		opens, err := parseClock(interval.Opens)
+		if err != nil {
+			continue
+		}
+		closes, err := parseClock(interval.Closes)
+		if err != nil {
+			continue
+		}
+		if minute >= opens && minute < closes {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClock converts "15:04" to minutes since midnight
+func parseClock(clock string) (int, error) {
+	if clock == "24:00" {
+		return 24 * 60, nil
+	}
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", clock)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
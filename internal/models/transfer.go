@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TransferStatus string
+
+// A transfer is assembled in the source PVZ (outbound), dispatched (in_transit)
+// and received by the destination PVZ inside its active reception. Only an
+// outbound transfer can be cancelled.
+const (
+	TransferStatusOutbound  TransferStatus = "outbound"
+	TransferStatusInTransit TransferStatus = "in_transit"
+	TransferStatusReceived  TransferStatus = "received"
+	TransferStatusCancelled TransferStatus = "cancelled"
+)
+
+// Transfer moves stored products from one PVZ to another. ReceptionID is the
+// reception of the destination PVZ the products were received in.
+type Transfer struct {
+	ID               uuid.UUID      `db:"id" json:"id"`
+	SourcePVZID      uuid.UUID      `db:"source_pvz_id" json:"sourcePvzId"`
+	DestinationPVZID uuid.UUID      `db:"destination_pvz_id" json:"destinationPvzId"`
+	Status           TransferStatus `db:"status" json:"status"`
+	ReceptionID      *uuid.UUID     `db:"reception_id" json:"receptionId,omitempty"`
+	ProductIDs       []uuid.UUID    `db:"-" json:"productIds"`
+	CreatedAt        time.Time      `db:"created_at" json:"createdAt"`
+	DispatchedAt     *time.Time     `db:"dispatched_at" json:"dispatchedAt,omitempty"`
+	ReceivedAt       *time.Time     `db:"received_at" json:"receivedAt,omitempty"`
+}
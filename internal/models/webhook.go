@@ -0,0 +1,46 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookSubscription struct {
+	ID         uuid.UUID   `db:"id" json:"id"`
+	URL        string      `db:"url" json:"url"`
+	Secret     string      `db:"secret" json:"secret,omitempty"`
+	EventTypes []EventType `db:"event_types" json:"eventTypes"`
+	PVZID      *uuid.UUID  `db:"pvz_id" json:"pvzId,omitempty"`
+	CityID     *int        `db:"city_id" json:"-"`
+	CityName   string      `db:"city_name" json:"city,omitempty"`
+	CreatedAt  time.Time   `db:"created_at" json:"createdAt"`
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusDelivered DeliveryStatus = "delivered"
+	DeliveryStatusDead      DeliveryStatus = "dead"
+)
+
+// WebhookDelivery is one event queued for one subscription
+type WebhookDelivery struct {
+	ID             uuid.UUID       `db:"id" json:"id"`
+	SubscriptionID uuid.UUID       `db:"subscription_id" json:"subscriptionId"`
+	EventID        uuid.UUID       `db:"event_id" json:"eventId"`
+	EventType      EventType       `db:"event_type" json:"eventType"`
+	Payload        json.RawMessage `db:"payload" json:"-"`
+	Status         DeliveryStatus  `db:"status" json:"status"`
+	Attempts       int             `db:"attempts" json:"attempts"`
+	NextAttemptAt  time.Time       `db:"next_attempt_at" json:"-"`
+	LastError      string          `db:"last_error" json:"lastError,omitempty"`
+	LastStatusCode int             `db:"last_status_code" json:"lastStatusCode,omitempty"`
+	CreatedAt      time.Time       `db:"created_at" json:"createdAt"`
+
+	// Filled from the subscription when a delivery is claimed for sending
+	URL    string `db:"url" json:"-"`
+	Secret string `db:"secret" json:"-"`
+}
@@ -0,0 +1,150 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"pvz-service/internal/config"
+	"pvz-service/internal/logger/sl"
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/webhook"
+	"time"
+)
+
+// Relay periodically moves unpublished events from the outbox table to a
+// Sink. A failed event is retried with exponential backoff without holding
+// up the events after it, and is parked after MaxAttempts.
+type Relay struct {
+	repo repository.OutboxRepository
+	sink Sink
+	log  *slog.Logger
+	now  func() time.Time
+
+	interval       time.Duration
+	timeout        time.Duration
+	lease          time.Duration
+	batchSize      int
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	retention      time.Duration
+	purgeInterval  time.Duration
+}
+
+func NewRelay(repo repository.OutboxRepository, sink Sink, cfg *config.Config, log *slog.Logger) *Relay {
+	return &Relay{
+		repo: repo,
+		sink: sink,
+		log:  log.With(slog.String("component", "outbox/relay")),
+		now:  time.Now,
+
+		interval: cfg.Outbox.Interval,
+		timeout:  cfg.Outbox.Timeout,
+		// A claimed event must not become due again while its batch is being
+		// published
+		lease:          webhook.Lease(cfg.Outbox.BatchSize, cfg.Outbox.Timeout),
+		batchSize:      cfg.Outbox.BatchSize,
+		maxAttempts:    cfg.Outbox.MaxAttempts,
+		initialBackoff: cfg.Outbox.InitialBackoff,
+		maxBackoff:     cfg.Outbox.MaxBackoff,
+		retention:      cfg.Outbox.Retention,
+		purgeInterval:  cfg.Outbox.PurgeInterval,
+	}
+}
+
+// Run polls the outbox and purges published events until ctx is cancelled
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	purge := time.NewTicker(r.purgeInterval)
+	defer purge.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.drain(ctx)
+		case <-purge.C:
+			r.purge(ctx)
+		}
+	}
+}
+
+// drain publishes batches back to back until one comes out short, because
+// the backlog is empty or some of its events failed
+func (r *Relay) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		published, err := r.PublishPending(ctx)
+		if err != nil {
+			r.log.Error("failed to publish outbox events", slog.Int("published", published), sl.Err(err))
+			return
+		}
+		if published > 0 {
+			r.log.Debug("outbox events published", slog.Int("published", published))
+		}
+		if published < r.batchSize {
+			return
+		}
+	}
+}
+
+// PublishPending claims one batch of due events, publishes them and records
+// the outcome of each. It returns how many were published. Each publish is
+// cut off after the timeout to keep the batch within its lease.
+func (r *Relay) PublishPending(ctx context.Context) (int, error) {
+	events, err := r.repo.ClaimOutboxEvents(ctx, r.batchSize, r.lease)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim events: %w", err)
+	}
+
+	published := 0
+	for _, event := range events {
+		publishErr := r.publish(ctx, event)
+		if publishErr == nil {
+			if err := r.repo.MarkEventPublished(ctx, event.ID); err != nil {
+				return published, fmt.Errorf("failed to mark event %s: %w", event.ID, err)
+			}
+			published++
+			continue
+		}
+
+		attempt := event.Attempts + 1
+		dead := attempt >= r.maxAttempts
+		nextAttemptAt := r.now().Add(webhook.Backoff(attempt, r.initialBackoff, r.maxBackoff))
+
+		r.log.Warn("outbox event publish failed",
+			slog.String("event_id", event.ID.String()),
+			slog.Int("attempt", attempt),
+			slog.Bool("dead", dead),
+			sl.Err(publishErr),
+		)
+
+		if err := r.repo.MarkEventFailed(ctx, event.ID, publishErr.Error(), nextAttemptAt, dead); err != nil {
+			return published, fmt.Errorf("failed to mark event %s: %w", event.ID, err)
+		}
+	}
+
+	return published, nil
+}
+
+func (r *Relay) publish(ctx context.Context, event models.Event) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	return r.sink.Publish(ctx, event)
+}
+
+// purge deletes the events published longer than the retention ago
+func (r *Relay) purge(ctx context.Context) {
+	deleted, err := r.repo.PurgePublishedEvents(ctx, r.now().Add(-r.retention))
+	if err != nil {
+		r.log.Error("failed to purge published outbox events", sl.Err(err))
+		return
+	}
+	if deleted > 0 {
+		r.log.Info("published outbox events purged", slog.Int("deleted", deleted))
+	}
+}
@@ -0,0 +1,259 @@
+package outbox
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"pvz-service/internal/config"
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOutboxRepository hands out its pending events like the real
+// repository does: published ones are dropped, failed ones stay pending
+type MockOutboxRepository struct {
+	mock.Mock
+	pending []models.Event
+}
+
+func (m *MockOutboxRepository) ClaimOutboxEvents(ctx context.Context, limit int, lease time.Duration) ([]models.Event, error) {
+	m.Called(ctx, limit, lease)
+	return append([]models.Event(nil), m.pending[:min(limit, len(m.pending))]...), nil
+}
+
+func (m *MockOutboxRepository) MarkEventPublished(ctx context.Context, id uuid.UUID) error {
+	m.Called(ctx, id)
+	for i, ev := range m.pending {
+		if ev.ID == id {
+			m.pending = append(m.pending[:i], m.pending[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (m *MockOutboxRepository) MarkEventFailed(ctx context.Context, id uuid.UUID, lastErr string, nextAttemptAt time.Time, dead bool) error {
+	args := m.Called(ctx, id, lastErr, nextAttemptAt, dead)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) PurgePublishedEvents(ctx context.Context, before time.Time) (int, error) {
+	args := m.Called(ctx, before)
+	return args.Int(0), args.Error(1)
+}
+
+// leasingOutboxRepository keeps claimed events leased like the real
+// repositories do, on a clock the test moves
+type leasingOutboxRepository struct {
+	MockOutboxRepository
+	now time.Time
+	due map[uuid.UUID]time.Time
+}
+
+func (m *leasingOutboxRepository) ClaimOutboxEvents(ctx context.Context, limit int, lease time.Duration) ([]models.Event, error) {
+	var events []models.Event
+	for _, ev := range m.pending {
+		if len(events) == limit {
+			break
+		}
+		if m.due[ev.ID].After(m.now) {
+			continue
+		}
+		m.due[ev.ID] = m.now.Add(lease)
+		events = append(events, ev)
+	}
+	return events, nil
+}
+
+type MockSink struct {
+	mock.Mock
+}
+
+func (m *MockSink) Publish(ctx context.Context, event models.Event) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func newTestRelay(repo repository.OutboxRepository, sink *MockSink, batchSize int) *Relay {
+	cfg := &config.Config{Outbox: config.Outbox{
+		Interval:       10 * time.Millisecond,
+		Timeout:        time.Second,
+		BatchSize:      batchSize,
+		MaxAttempts:    3,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+		Retention:      time.Hour,
+		PurgeInterval:  time.Hour,
+	}}
+	return NewRelay(repo, sink, cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func testEvents(n int) []models.Event {
+	events := make([]models.Event, n)
+	for i := range events {
+		events[i] = models.Event{ID: uuid.New(), Type: models.EventProductAdded}
+	}
+	return events
+}
+
+func TestRelay_PublishPending(t *testing.T) {
+	events := testEvents(3)
+
+	repo := &MockOutboxRepository{pending: events}
+	repo.On("ClaimOutboxEvents", mock.Anything, 10, 11*time.Second).Return()
+	repo.On("MarkEventPublished", mock.Anything, mock.Anything).Return().Times(3)
+
+	sink := new(MockSink)
+	sink.On("Publish", mock.Anything, mock.Anything).Return(nil).Times(3)
+
+	relay := newTestRelay(repo, sink, 10)
+	published, err := relay.PublishPending(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, published)
+	assert.Empty(t, repo.pending)
+	sink.AssertExpectations(t)
+	repo.AssertExpectations(t)
+}
+
+func TestRelay_PublishPending_KeepsBatchLeased(t *testing.T) {
+	repo := &leasingOutboxRepository{
+		MockOutboxRepository: MockOutboxRepository{pending: testEvents(5)},
+		now:                  time.Now(),
+		due:                  map[uuid.UUID]time.Time{},
+	}
+	repo.On("MarkEventPublished", mock.Anything, mock.Anything).Return()
+
+	var relay *Relay
+	var reclaimed []models.Event
+	sink := new(MockSink)
+	sink.On("Publish", mock.Anything, mock.Anything).Return(nil).Run(func(mock.Arguments) {
+		// Every publish takes the whole timeout, meanwhile another relay
+		// instance polls the outbox
+		repo.now = repo.now.Add(time.Second)
+		events, _ := repo.ClaimOutboxEvents(context.Background(), 5, relay.lease)
+		reclaimed = append(reclaimed, events...)
+	})
+
+	relay = newTestRelay(repo, sink, 5)
+	published, err := relay.PublishPending(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, published)
+	assert.Empty(t, reclaimed)
+}
+
+func TestRelay_PublishPending_SinkError(t *testing.T) {
+	events := testEvents(2)
+	events[0].Attempts = 1
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	repo := &MockOutboxRepository{pending: events}
+	repo.On("ClaimOutboxEvents", mock.Anything, 10, mock.Anything).Return()
+	// The second attempt waits twice the initial backoff
+	repo.On("MarkEventFailed", mock.Anything, events[0].ID, assert.AnError.Error(), now.Add(2*time.Second), false).Return(nil).Once()
+	repo.On("MarkEventPublished", mock.Anything, events[1].ID).Return().Once()
+
+	sink := new(MockSink)
+	sink.On("Publish", mock.Anything, events[0]).Return(assert.AnError).Once()
+	sink.On("Publish", mock.Anything, events[1]).Return(nil).Once()
+
+	relay := newTestRelay(repo, sink, 10)
+	relay.now = func() time.Time { return now }
+	published, err := relay.PublishPending(context.Background())
+
+	// The failed event does not hold up the one after it
+	assert.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, []models.Event{events[0]}, repo.pending)
+	sink.AssertExpectations(t)
+	repo.AssertExpectations(t)
+}
+
+func TestRelay_PublishPending_ParksAfterMaxAttempts(t *testing.T) {
+	events := testEvents(1)
+	events[0].Attempts = 2
+
+	repo := &MockOutboxRepository{pending: events}
+	repo.On("ClaimOutboxEvents", mock.Anything, 10, mock.Anything).Return()
+	repo.On("MarkEventFailed", mock.Anything, events[0].ID, assert.AnError.Error(), mock.Anything, true).Return(nil).Once()
+
+	sink := new(MockSink)
+	sink.On("Publish", mock.Anything, events[0]).Return(assert.AnError).Once()
+
+	relay := newTestRelay(repo, sink, 10)
+	published, err := relay.PublishPending(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, published)
+	repo.AssertExpectations(t)
+}
+
+func TestRelay_DrainsBacklogInBatches(t *testing.T) {
+	repo := &MockOutboxRepository{pending: testEvents(5)}
+	repo.On("ClaimOutboxEvents", mock.Anything, 2, mock.Anything).Return()
+	repo.On("MarkEventPublished", mock.Anything, mock.Anything).Return()
+
+	sink := new(MockSink)
+	sink.On("Publish", mock.Anything, mock.Anything).Return(nil)
+
+	relay := newTestRelay(repo, sink, 2)
+	relay.drain(context.Background())
+
+	assert.Empty(t, repo.pending)
+	// 2 + 2 + 1: the short batch ends the drain
+	repo.AssertNumberOfCalls(t, "ClaimOutboxEvents", 3)
+}
+
+func TestRelay_Purge(t *testing.T) {
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	repo := new(MockOutboxRepository)
+	repo.On("PurgePublishedEvents", mock.Anything, now.Add(-time.Hour)).Return(4, nil).Once()
+
+	relay := newTestRelay(repo, new(MockSink), 10)
+	relay.now = func() time.Time { return now }
+	relay.purge(context.Background())
+
+	repo.AssertExpectations(t)
+}
+
+func TestRelay_RunStopsOnCancel(t *testing.T) {
+	repo := &MockOutboxRepository{pending: testEvents(1)}
+	repo.On("ClaimOutboxEvents", mock.Anything, 10, mock.Anything).Return()
+	repo.On("MarkEventPublished", mock.Anything, mock.Anything).Return()
+
+	publishedCh := make(chan struct{}, 1)
+	sink := new(MockSink)
+	sink.On("Publish", mock.Anything, mock.Anything).Return(nil).Run(func(mock.Arguments) {
+		publishedCh <- struct{}{}
+	})
+
+	relay := newTestRelay(repo, sink, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		relay.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-publishedCh:
+	case <-time.After(time.Second):
+		t.Fatal("relay did not publish pending event")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("relay did not stop after cancel")
+	}
+}
@@ -0,0 +1,41 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"pvz-service/internal/config"
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/webhook"
+)
+
+// Sink delivers outbox events to downstream systems. Delivery is
+// at-least-once: the same event may be passed to Publish more than once,
+// so consumers should deduplicate by event ID.
+type Sink interface {
+	Publish(ctx context.Context, event models.Event) error
+}
+
+// CreateSink builds the sink selected by cfg.Outbox.Sink. webhookRepo is
+// used by the subscriptions sink.
+func CreateSink(cfg *config.Config, webhookRepo repository.WebhookRepository, log *slog.Logger) (Sink, error) {
+	const op = "outbox.sink.CreateSink"
+
+	switch cfg.Outbox.Sink {
+
+	case "webhook":
+		if cfg.Outbox.WebhookURL == "" {
+			return nil, fmt.Errorf("%s: webhook_url is required for webhook sink", op)
+		}
+		log.Info("outbox sink is webhook", slog.String("url", cfg.Outbox.WebhookURL))
+		return NewWebhookSink(cfg.Outbox.WebhookURL, cfg.Outbox.Timeout), nil
+
+	case "subscriptions":
+		log.Info("outbox sink is webhook subscriptions")
+		return webhook.NewSubscriptionSink(webhookRepo), nil
+
+	default:
+		return nil, fmt.Errorf("%s: unknown outbox sink (%s)", op, cfg.Outbox.Sink)
+	}
+}
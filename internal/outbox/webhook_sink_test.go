@@ -0,0 +1,104 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"pvz-service/internal/config"
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository/memory"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSink_Publish(t *testing.T) {
+	event := models.Event{
+		ID:          uuid.New(),
+		Type:        models.EventReceptionClosed,
+		PVZID:       uuid.New(),
+		ReceptionID: uuid.New(),
+		Payload:     json.RawMessage(`{"status":"close"}`),
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	var received models.Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, event.ID.String(), r.Header.Get(EventIDHeader))
+		assert.Equal(t, string(event.Type), r.Header.Get(EventTypeHeader))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	sink := NewWebhookSink(srv.URL, time.Second)
+	err := sink.Publish(context.Background(), event)
+
+	assert.NoError(t, err)
+	assert.Equal(t, event.ID, received.ID)
+	assert.Equal(t, event.PVZID, received.PVZID)
+	assert.JSONEq(t, string(event.Payload), string(received.Payload))
+}
+
+func TestWebhookSink_PublishErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	sink := NewWebhookSink(srv.URL, time.Second)
+	err := sink.Publish(context.Background(), models.Event{ID: uuid.New()})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "503")
+}
+
+func TestCreateSink(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := []struct {
+		name        string
+		outbox      config.Outbox
+		expectError bool
+	}{
+		{
+			name:   "webhook",
+			outbox: config.Outbox{Sink: "webhook", WebhookURL: "http://localhost/events", Timeout: time.Second},
+		},
+		{
+			name:   "subscriptions",
+			outbox: config.Outbox{Sink: "subscriptions"},
+		},
+		{
+			name:        "webhook without url",
+			outbox:      config.Outbox{Sink: "webhook"},
+			expectError: true,
+		},
+		{
+			name:        "unknown sink",
+			outbox:      config.Outbox{Sink: "kafka"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink, err := CreateSink(&config.Config{Outbox: tt.outbox}, memory.New(), log)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, sink)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, sink)
+			}
+		})
+	}
+}
@@ -2,10 +2,9 @@ package repository
 
 import (
 	"context"
-	"errors"
-	"log/slog"
-	"pvz-service/internal/config"
 	"pvz-service/internal/models"
+	"pvz-service/internal/repository/memory"
+	"pvz-service/internal/repository/postgres"
 	"testing"
 
 	"github.com/google/uuid"
@@ -42,92 +41,42 @@ func (m *MockAuthRepository) VerifyPassword(ctx context.Context, email, password
 	return args.Bool(0), args.Error(1)
 }
 
-// MockPostgresAuthGetter mocks the postgres repository getter
-type MockPostgresAuthGetter struct {
-	mock.Mock
-}
-
-func (m *MockPostgresAuthGetter) GetRepository(cfg *config.Config) (AuthRepository, error) {
-	args := m.Called(cfg)
-	return args.Get(0).(AuthRepository), args.Error(1)
-}
-
 func TestCreateAuthRepo(t *testing.T) {
-	// Save original PostgresGetter and restore after test
-	originalPostgresGetter := PostgresGetter
-	defer func() { PostgresGetter = originalPostgresGetter }()
-
 	tests := []struct {
-		name          string
-		cfg           *config.Config
-		mockSetup     func(*MockPostgresAuthGetter)
-		expectError   bool
-		expectNilRepo bool
+		name        string
+		db          Database
+		expectError bool
 	}{
 		{
 			name: "Success with postgres",
-			cfg: &config.Config{
-				Database: config.Database{
-					Protocol: "postgres",
-				},
-			},
-			mockSetup: func(m *MockPostgresAuthGetter) {
-				mockRepo := new(MockAuthRepository)
-				m.On("GetRepository", mock.Anything).Return(mockRepo, nil)
-			},
-			expectError:   false,
-			expectNilRepo: false,
+			db:   new(postgres.Postgres),
+		},
+		{
+			name: "Success with memory",
+			db:   memory.New(),
 		},
 		{
-			name: "Postgres error",
-			cfg: &config.Config{
-				Database: config.Database{
-					Protocol: "postgres",
-				},
-			},
-			mockSetup: func(m *MockPostgresAuthGetter) {
-				m.On("GetRepository", mock.Anything).Return(&MockAuthRepository{}, errors.New("connection failed"))
-			},
-			expectError:   true,
-			expectNilRepo: true,
+			name: "Success with sqlite",
+			db:   openSQLite(t),
 		},
 		{
-			name: "Unknown protocol",
-			cfg: &config.Config{
-				Database: config.Database{
-					Protocol: "unknown",
-				},
-			},
-			mockSetup:     func(m *MockPostgresAuthGetter) {},
-			expectError:   true,
-			expectNilRepo: true,
+			name:        "Not an auth repository",
+			db:          stubDatabase{},
+			expectError: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockGetter := new(MockPostgresAuthGetter)
-			tt.mockSetup(mockGetter)
-			PostgresGetter = func(cfg *config.Config) (interface{}, error) {
-				return mockGetter.GetRepository(cfg)
-			}
-
-			log := slog.Default()
-			repo, err := CreateAuthRepo(tt.cfg, log)
+			repo, err := CreateAuthRepo(tt.db)
 
 			if tt.expectError {
 				assert.Error(t, err)
-			} else {
-				assert.NoError(t, err)
-			}
-
-			if tt.expectNilRepo {
 				assert.Nil(t, repo)
 			} else {
+				assert.NoError(t, err)
 				assert.NotNil(t, repo)
 			}
-
-			mockGetter.AssertExpectations(t)
 		})
 	}
 }
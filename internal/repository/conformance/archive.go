@@ -0,0 +1,143 @@
+package conformance
+
+import (
+	"context"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testArchive(t *testing.T, repo repository.PVZRepository) {
+	ctx := context.Background()
+	pvz := insertPVZ(t, repo, 0)
+
+	closeReception := func(reception *models.Reception, version int) {
+		t.Helper()
+		_, err := repo.UpdateReceptionStatus(ctx, reception.ID, models.ReceptionStatusClose, version)
+		require.NoError(t, err)
+	}
+	settle := func(product *models.Product, to models.ProductStatus) {
+		t.Helper()
+		change := &models.ProductStatusChange{ProductID: product.ID, From: models.ProductStatusStored, To: to, ChangedAt: base.Add(time.Hour)}
+		require.NoError(t, repo.ChangeProductStatus(ctx, change))
+	}
+
+	empty := openReception(t, repo, pvz.ID, base)
+	closeReception(empty, 1)
+
+	settled := openReception(t, repo, pvz.ID, base.Add(time.Hour))
+	issued, version := addProduct(t, repo, settled.ID, 1, base.Add(time.Hour+time.Minute))
+	writtenOff, version := addProduct(t, repo, settled.ID, version, base.Add(time.Hour+2*time.Minute))
+	_, err := repo.SetProductPhoto(ctx, issued.ID, "products/issued.jpg")
+	require.NoError(t, err)
+	closeReception(settled, version)
+	settle(issued, models.ProductStatusIssued)
+	settle(writtenOff, models.ProductStatusWrittenOff)
+
+	// A product still on the shelf keeps its reception in place
+	pending := openReception(t, repo, pvz.ID, base.Add(2*time.Hour))
+	_, version = addProduct(t, repo, pending.ID, 1, base.Add(2*time.Hour+time.Minute))
+	closeReception(pending, version)
+
+	// An audit entry does not, it stays with the archived reception
+	audited := openReception(t, repo, pvz.ID, base.Add(3*time.Hour))
+	_, err = repo.ForceCloseReception(ctx, audited.ID, 1, &models.ReceptionAuditEntry{
+		Action:    models.ReceptionAuditForceClosed,
+		Actor:     "moderator@example.com",
+		CreatedAt: base.Add(4 * time.Hour),
+	})
+	require.NoError(t, err)
+
+	// The retention period does
+	recent := openReception(t, repo, pvz.ID, base.Add(48*time.Hour))
+	closeReception(recent, 1)
+
+	closedBefore, archivedAt := base.Add(24*time.Hour), base.Add(96*time.Hour)
+
+	// The oldest receptions go first
+	receptions, products, photoKeys, err := repo.ArchiveReceptions(ctx, closedBefore, archivedAt, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, receptions)
+	assert.Equal(t, 0, products)
+	assert.Empty(t, photoKeys)
+
+	// Photos are not archived, their keys come back for the caller to remove
+	receptions, products, photoKeys, err = repo.ArchiveReceptions(ctx, closedBefore, archivedAt, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, receptions)
+	assert.Equal(t, 2, products)
+	assert.Equal(t, []string{"products/issued.jpg"}, photoKeys)
+
+	receptions, products, photoKeys, err = repo.ArchiveReceptions(ctx, closedBefore, archivedAt, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, receptions)
+	assert.Equal(t, 0, products)
+	assert.Empty(t, photoKeys)
+
+	// Archived receptions and products leave the live tables
+	live, err := repo.GetReceptionsForPVZs(ctx, []uuid.UUID{pvz.ID}, base, base.Add(96*time.Hour))
+	require.NoError(t, err)
+	liveIDs := make([]uuid.UUID, 0, len(live))
+	for _, reception := range live {
+		liveIDs = append(liveIDs, reception.ID)
+	}
+	assert.Equal(t, []uuid.UUID{recent.ID, pending.ID}, liveIDs)
+
+	_, err = repo.GetProduct(ctx, issued.ID)
+	assert.Equal(t, e.ErrNotFound(), err)
+
+	occupancy, err := repo.GetPVZOccupancy(ctx, pvz.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, occupancy.Count)
+
+	// and are listed from the archive, newest first
+	archived, err := repo.GetArchivedReceptions(ctx, pvz.ID, base, base.Add(96*time.Hour), 10, 0)
+	require.NoError(t, err)
+	require.Len(t, archived, 3)
+	assert.Equal(t, audited.ID, archived[0].ID)
+	assert.Equal(t, settled.ID, archived[1].ID)
+	assert.Equal(t, empty.ID, archived[2].ID)
+	assert.Equal(t, models.ReceptionStatusClose, archived[1].Status)
+	assert.WithinDuration(t, settled.DateTime, archived[1].DateTime, 0)
+
+	archived, err = repo.GetArchivedReceptions(ctx, pvz.ID, base, base.Add(96*time.Hour), 1, 2)
+	require.NoError(t, err)
+	require.Len(t, archived, 1)
+	assert.Equal(t, empty.ID, archived[0].ID)
+
+	archived, err = repo.GetArchivedReceptions(ctx, pvz.ID, base.Add(time.Minute), base.Add(96*time.Hour), 10, 0)
+	require.NoError(t, err)
+	require.Len(t, archived, 2)
+	assert.Equal(t, settled.ID, archived[1].ID)
+
+	// The audit entry still names its reception
+	audit, err := repo.GetPVZReceptionAudit(ctx, pvz.ID)
+	require.NoError(t, err)
+	require.Len(t, audit, 1)
+	assert.Equal(t, audited.ID, audit[0].ReceptionID)
+
+	archived, err = repo.GetArchivedReceptions(ctx, uuid.New(), base, base.Add(96*time.Hour), 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, archived)
+
+	archivedProducts, err := repo.GetArchivedProducts(ctx, []uuid.UUID{settled.ID})
+	require.NoError(t, err)
+	require.Len(t, archivedProducts, 2)
+	assert.Equal(t, writtenOff.ID, archivedProducts[0].ID)
+	assert.Equal(t, models.ProductStatusWrittenOff, archivedProducts[0].Status)
+	assert.Equal(t, issued.ID, archivedProducts[1].ID)
+	assert.Equal(t, models.ProductStatusIssued, archivedProducts[1].Status)
+	assert.Equal(t, settled.ID, archivedProducts[1].ReceptionID)
+	assert.NotEmpty(t, archivedProducts[1].TypeName)
+	assert.Empty(t, archivedProducts[1].PhotoKey)
+
+	archivedProducts, err = repo.GetArchivedProducts(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, archivedProducts)
+}
@@ -0,0 +1,75 @@
+package conformance
+
+import (
+	"context"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// AuthRepository runs the AuthRepository suite. newRepo must return an
+// empty, freshly migrated repository for every subtest.
+func AuthRepository(t *testing.T, newRepo func(t *testing.T) repository.AuthRepository) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, repo repository.AuthRepository)
+	}{
+		{"Users", testUsers},
+		{"Passwords", testPasswords},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newRepo(t))
+		})
+	}
+}
+
+func testUsers(t *testing.T, repo repository.AuthRepository) {
+	ctx := context.Background()
+
+	user, err := repo.CreateUser(ctx, "employee@example.com", "secret", models.UserRoleEmployee)
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, user.ID)
+	assert.NotEqual(t, "secret", user.PasswordHash)
+
+	_, err = repo.CreateUser(ctx, "employee@example.com", "other", models.UserRoleModerator)
+	assert.Equal(t, e.ErrAlreadyExists(), err)
+
+	byEmail, err := repo.GetUserByEmail(ctx, "employee@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, user, byEmail)
+
+	byID, err := repo.GetUserByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, user, byID)
+
+	_, err = repo.GetUserByEmail(ctx, "unknown@example.com")
+	assert.Equal(t, e.ErrNotFound(), err)
+
+	_, err = repo.GetUserByID(ctx, uuid.New())
+	assert.Equal(t, e.ErrNotFound(), err)
+}
+
+func testPasswords(t *testing.T, repo repository.AuthRepository) {
+	ctx := context.Background()
+
+	_, err := repo.CreateUser(ctx, "moderator@example.com", "secret", models.UserRoleModerator)
+	require.NoError(t, err)
+
+	ok, err := repo.VerifyPassword(ctx, "moderator@example.com", "secret")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = repo.VerifyPassword(ctx, "moderator@example.com", "wrong")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = repo.VerifyPassword(ctx, "unknown@example.com", "secret")
+	assert.Equal(t, e.ErrNotFound(), err)
+}
@@ -0,0 +1,67 @@
+// Package conformance holds black-box tests for the repository interfaces.
+// Every backend runs the same suite from its own tests, so the behaviour
+// the services rely on is checked once instead of per implementation.
+package conformance
+
+import (
+	"context"
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// City and product type IDs seeded by the migrations
+const (
+	moscowID      = 1
+	electronicsID = 1
+)
+
+// now returns the current time rounded to what every backend stores.
+// Postgres keeps microseconds.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+func insertPVZ(t *testing.T, repo repository.PVZRepository, capacity int) *models.PVZ {
+	t.Helper()
+
+	pvz := &models.PVZ{
+		ID:               uuid.New(),
+		RegistrationDate: now(),
+		CityID:           moscowID,
+		Address:          "ул. Тверская, 1",
+		Capacity:         capacity,
+		Status:           models.PVZStatusActive,
+	}
+	require.NoError(t, repo.InsertPVZ(context.Background(), pvz))
+	return pvz
+}
+
+func openReception(t *testing.T, repo repository.PVZRepository, pvzID uuid.UUID, at time.Time) *models.Reception {
+	t.Helper()
+
+	reception := &models.Reception{ID: uuid.New(), DateTime: at, PVZID: pvzID, Status: models.ReceptionStatusInProgress}
+	require.NoError(t, repo.InsertReception(context.Background(), reception))
+	return reception
+}
+
+// addProduct accepts a product of the type into the reception, which must
+// have the given version, and returns the product and the new version
+func addProduct(t *testing.T, repo repository.PVZRepository, receptionID uuid.UUID, version int, at time.Time) (*models.Product, int) {
+	t.Helper()
+
+	product := &models.Product{
+		ID:          uuid.New(),
+		DateTime:    at,
+		TypeID:      electronicsID,
+		ReceptionID: receptionID,
+		Status:      models.ProductStatusAccepted,
+	}
+	version, err := repo.InsertProduct(context.Background(), product, version)
+	require.NoError(t, err)
+	return product, version
+}
@@ -0,0 +1,120 @@
+package conformance
+
+import (
+	"database/sql"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/stretchr/testify/require"
+)
+
+// LocalPostgres is a throwaway Postgres server started from the binaries
+// installed on the machine. Every test gets a database of its own on it.
+type LocalPostgres struct {
+	port      int
+	admin     *sql.DB
+	databases atomic.Int64
+}
+
+// StartPostgres starts a server that lives until t finishes. The binaries
+// are looked up in $PG_BIN, then in PATH and the Debian install directory.
+// t is skipped when they are missing or Postgres refuses to run, as it does
+// for root.
+func StartPostgres(t *testing.T) *LocalPostgres {
+	t.Helper()
+
+	bin, ok := postgresBinDir()
+	if !ok {
+		t.Skip("postgres binaries not found, set PG_BIN to run the postgres suite")
+	}
+	if os.Geteuid() == 0 {
+		t.Skip("postgres cannot run as root")
+	}
+
+	dataDir := t.TempDir()
+	initdb := exec.Command(filepath.Join(bin, "initdb"), "-D", dataDir, "-U", "postgres", "-A", "trust", "-E", "UTF8", "--no-sync")
+	if out, err := initdb.CombinedOutput(); err != nil {
+		t.Skipf("initdb failed: %v\n%s", err, out)
+	}
+
+	port, err := freePort()
+	require.NoError(t, err)
+
+	pgCtl := filepath.Join(bin, "pg_ctl")
+	options := fmt.Sprintf("-p %d -k %s -c listen_addresses=localhost -c fsync=off", port, os.TempDir())
+	start := exec.Command(pgCtl, "-D", dataDir, "-l", filepath.Join(dataDir, "server.log"), "-o", options, "-w", "start")
+	if out, err := start.CombinedOutput(); err != nil {
+		t.Skipf("postgres failed to start: %v\n%s", err, out)
+	}
+	t.Cleanup(func() {
+		exec.Command(pgCtl, "-D", dataDir, "-m", "immediate", "stop").Run()
+	})
+
+	pg := &LocalPostgres{port: port}
+	pg.admin, err = sql.Open("pgx", pg.dsn("postgres"))
+	require.NoError(t, err)
+	require.NoError(t, pg.admin.Ping())
+	t.Cleanup(func() { pg.admin.Close() })
+
+	return pg
+}
+
+// NewDatabase creates an empty database and connects to it. The connection
+// is closed when t finishes.
+func (p *LocalPostgres) NewDatabase(t *testing.T) *sql.DB {
+	t.Helper()
+
+	name := fmt.Sprintf("conformance_%d", p.databases.Add(1))
+	_, err := p.admin.Exec("CREATE DATABASE " + name)
+	require.NoError(t, err)
+
+	db, err := sql.Open("pgx", p.dsn(name))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func (p *LocalPostgres) dsn(name string) string {
+	return fmt.Sprintf("host=localhost port=%d user=postgres dbname=%s sslmode=disable", p.port, name)
+}
+
+func postgresBinDir() (string, bool) {
+	if dir := os.Getenv("PG_BIN"); dir != "" {
+		return dir, true
+	}
+
+	if path, err := exec.LookPath("pg_ctl"); err == nil {
+		return filepath.Dir(path), true
+	}
+
+	// Debian and Ubuntu keep the server binaries out of PATH
+	dirs, _ := filepath.Glob("/usr/lib/postgresql/*/bin")
+	if len(dirs) == 0 {
+		return "", false
+	}
+	// Pick the newest major version, so "16" wins over "9.6"
+	version := func(dir string) float64 {
+		v, _ := strconv.ParseFloat(filepath.Base(filepath.Dir(dir)), 64)
+		return v
+	}
+	sort.Slice(dirs, func(i, j int) bool { return version(dirs[i]) < version(dirs[j]) })
+	return dirs[len(dirs)-1], true
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
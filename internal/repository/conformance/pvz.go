@@ -0,0 +1,394 @@
+package conformance
+
+import (
+	"context"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// PVZRepository runs the PVZRepository suite. newRepo must return an empty,
+// freshly migrated repository for every subtest.
+func PVZRepository(t *testing.T, newRepo func(t *testing.T) repository.PVZRepository) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, repo repository.PVZRepository)
+	}{
+		{"Dictionaries", testDictionaries},
+		{"PVZLifecycle", testPVZLifecycle},
+		{"NearbyPVZs", testNearbyPVZs},
+		{"Schedule", testSchedule},
+		{"ReceptionFlow", testReceptionFlow},
+		{"DeleteProduct", testDeleteProduct},
+		{"Capacity", testCapacity},
+		{"ProductStatus", testProductStatus},
+		{"Queries", testQueries},
+		{"GetPVZsOrderingAndPagination", testGetPVZsOrderingAndPagination},
+		{"TimeRangeBoundaries", testTimeRangeBoundaries},
+		{"IteratePVZs", testIteratePVZs},
+		{"ProductOrdering", testProductOrdering},
+		{"StaleReceptions", testStaleReceptions},
+		{"ManifestOrdering", testManifestOrdering},
+		{"Archive", testArchive},
+		{"ErrorSentinels", testErrorSentinels},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newRepo(t))
+		})
+	}
+}
+
+func testDictionaries(t *testing.T, repo repository.PVZRepository) {
+	ctx := context.Background()
+
+	cityID, err := repo.GetCityID(ctx, "Москва")
+	require.NoError(t, err)
+	assert.Equal(t, moscowID, cityID)
+
+	_, err = repo.GetCityID(ctx, "Новосибирск")
+	assert.Equal(t, e.ErrCityNotAllowed(), err)
+
+	typeID, err := repo.GetProductTypeID(ctx, "электроника")
+	require.NoError(t, err)
+	assert.Equal(t, electronicsID, typeID)
+
+	_, err = repo.GetProductTypeID(ctx, "мебель")
+	assert.Equal(t, e.ErrProductTypeNotAllowed(), err)
+}
+
+func testPVZLifecycle(t *testing.T, repo repository.PVZRepository) {
+	ctx := context.Background()
+	pvz := insertPVZ(t, repo, 10)
+
+	got, err := repo.GetPVZ(ctx, pvz.ID)
+	require.NoError(t, err)
+	assert.Equal(t, pvz.ID, got.ID)
+	assert.Equal(t, "Москва", got.CityName)
+	assert.Equal(t, pvz.Address, got.Address)
+	assert.Equal(t, 10, got.Capacity)
+	assert.Equal(t, models.PVZStatusActive, got.Status)
+	assert.Equal(t, 1, got.Version)
+	assert.Nil(t, got.Location)
+	assert.WithinDuration(t, pvz.RegistrationDate, got.RegistrationDate, 0)
+
+	exists, err := repo.CheckPVZ(ctx, pvz.ID)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	got.Address = "ул. Арбат, 2"
+	got.Location = &models.GeoPoint{Latitude: 55.75, Longitude: 37.59}
+	version, err := repo.UpdatePVZ(ctx, got, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, version)
+
+	_, err = repo.UpdatePVZ(ctx, got, 1)
+	assert.Equal(t, e.ErrVersionMismatch(), err)
+
+	updated, err := repo.GetPVZ(ctx, pvz.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "ул. Арбат, 2", updated.Address)
+	assert.Equal(t, got.Location, updated.Location)
+
+	// A nil location clears the stored one
+	updated.Location = nil
+	version, err = repo.UpdatePVZ(ctx, updated, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 3, version)
+
+	cleared, err := repo.GetPVZ(ctx, pvz.ID)
+	require.NoError(t, err)
+	assert.Nil(t, cleared.Location)
+
+	assert.Equal(t, e.ErrVersionMismatch(), repo.DeletePVZ(ctx, pvz.ID, 2))
+	require.NoError(t, repo.DeletePVZ(ctx, pvz.ID, 3))
+
+	_, err = repo.GetPVZ(ctx, pvz.ID)
+	assert.Equal(t, e.ErrNotFound(), err)
+
+	_, err = repo.CheckPVZ(ctx, pvz.ID)
+	assert.Equal(t, e.ErrNotFound(), err)
+
+	_, err = repo.GetPVZ(ctx, uuid.New())
+	assert.Equal(t, e.ErrNotFound(), err)
+}
+
+func testNearbyPVZs(t *testing.T, repo repository.PVZRepository) {
+	ctx := context.Background()
+
+	locate := func(pvz *models.PVZ, point models.GeoPoint) {
+		pvz.Location = &point
+		_, err := repo.UpdatePVZ(ctx, pvz, 1)
+		require.NoError(t, err)
+	}
+
+	near := insertPVZ(t, repo, 0)
+	locate(near, models.GeoPoint{Latitude: 55.7558, Longitude: 37.6173})
+	far := insertPVZ(t, repo, 0)
+	locate(far, models.GeoPoint{Latitude: 55.7600, Longitude: 37.6300})
+	insertPVZ(t, repo, 0) // without a location
+	distant := insertPVZ(t, repo, 0)
+	locate(distant, models.GeoPoint{Latitude: 59.9343, Longitude: 30.3351})
+
+	// Suspended PVZs accept no parcels and are not offered
+	suspended := insertPVZ(t, repo, 0)
+	suspended.Status = models.PVZStatusSuspended
+	locate(suspended, models.GeoPoint{Latitude: 55.7559, Longitude: 37.6171})
+
+	nearby, err := repo.GetNearbyPVZs(ctx, models.GeoPoint{Latitude: 55.7558, Longitude: 37.6170}, 5000, 10)
+	require.NoError(t, err)
+	require.Len(t, nearby, 2)
+	assert.Equal(t, near.ID, nearby[0].PVZ.ID)
+	assert.Equal(t, far.ID, nearby[1].PVZ.ID)
+	assert.Less(t, nearby[0].Distance, nearby[1].Distance)
+	assert.InDelta(t, 18.8, nearby[0].Distance, 1)
+
+	nearby, err = repo.GetNearbyPVZs(ctx, models.GeoPoint{Latitude: 55.7558, Longitude: 37.6170}, 5000, 1)
+	require.NoError(t, err)
+	require.Len(t, nearby, 1)
+	assert.Equal(t, near.ID, nearby[0].PVZ.ID)
+}
+
+func testSchedule(t *testing.T, repo repository.PVZRepository) {
+	ctx := context.Background()
+	pvz := insertPVZ(t, repo, 0)
+
+	_, err := repo.GetPVZSchedule(ctx, pvz.ID)
+	assert.Equal(t, e.ErrNotFound(), err)
+
+	schedule := &models.PVZSchedule{
+		PVZID:    pvz.ID,
+		Timezone: "Europe/Moscow",
+		Weekly: []models.ScheduleDay{
+			{Weekday: "monday", Intervals: []models.ScheduleInterval{{Opens: "09:00", Closes: "21:00"}}},
+		},
+		UpdatedAt: now(),
+	}
+	require.NoError(t, repo.SavePVZSchedule(ctx, schedule))
+
+	got, err := repo.GetPVZSchedule(ctx, pvz.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Moscow", got.Timezone)
+	assert.Equal(t, schedule.Weekly, got.Weekly)
+	assert.Empty(t, got.Exceptions)
+	assert.Nil(t, got.ReceptionsAllowedUntil)
+
+	// Replacing the working hours keeps the allowance
+	until := now().Add(time.Hour)
+	require.NoError(t, repo.AllowReceptionsUntil(ctx, pvz.ID, until))
+	require.NoError(t, repo.SavePVZSchedule(ctx, schedule))
+	got, err = repo.GetPVZSchedule(ctx, pvz.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got.ReceptionsAllowedUntil)
+	assert.WithinDuration(t, until, *got.ReceptionsAllowedUntil, time.Millisecond)
+
+	require.NoError(t, repo.DeletePVZSchedule(ctx, pvz.ID))
+	assert.Equal(t, e.ErrNotFound(), repo.AllowReceptionsUntil(ctx, pvz.ID, until))
+	assert.Equal(t, e.ErrNotFound(), repo.DeletePVZSchedule(ctx, pvz.ID))
+}
+
+func testReceptionFlow(t *testing.T, repo repository.PVZRepository) {
+	ctx := context.Background()
+	pvz := insertPVZ(t, repo, 0)
+
+	_, err := repo.GetActiveReception(ctx, pvz.ID)
+	assert.Equal(t, e.ErrNoActiveReception(), err)
+
+	// Acceptance happens before the closing time the backend records
+	start := now().Add(-time.Hour)
+	reception := openReception(t, repo, pvz.ID, start)
+
+	active, err := repo.GetActiveReception(ctx, pvz.ID)
+	require.NoError(t, err)
+	assert.Equal(t, reception.ID, active.ID)
+	assert.Equal(t, models.ReceptionStatusInProgress, active.Status)
+	assert.Equal(t, 1, active.Version)
+	assert.WithinDuration(t, start, active.DateTime, 0)
+
+	product, version := addProduct(t, repo, reception.ID, 1, start.Add(time.Second))
+	assert.Equal(t, 2, version)
+
+	// A stale version is rejected without adding the product
+	_, err = repo.InsertProduct(ctx, &models.Product{ID: uuid.New(), DateTime: now(), TypeID: electronicsID, ReceptionID: reception.ID, Status: models.ProductStatusAccepted}, 1)
+	assert.Equal(t, e.ErrVersionMismatch(), err)
+
+	last, err := repo.GetLastProduct(ctx, reception.ID, reception.DateTime)
+	require.NoError(t, err)
+	assert.Equal(t, product.ID, last.ID)
+
+	_, err = repo.UpdateReceptionStatus(ctx, reception.ID, models.ReceptionStatusClose, 1)
+	assert.Equal(t, e.ErrVersionMismatch(), err)
+
+	version, err = repo.UpdateReceptionStatus(ctx, reception.ID, models.ReceptionStatusClose, version)
+	require.NoError(t, err)
+	assert.Equal(t, 3, version)
+
+	_, err = repo.GetActiveReception(ctx, pvz.ID)
+	assert.Equal(t, e.ErrNoActiveReception(), err)
+
+	stored, err := repo.GetProduct(ctx, product.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ProductStatusStored, stored.Status)
+	assert.Equal(t, "электроника", stored.TypeName)
+	assert.Equal(t, models.ProductConditionOK, stored.Condition)
+
+	history, err := repo.GetProductHistory(ctx, product.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, models.ProductStatus(""), history[0].From)
+	assert.Equal(t, models.ProductStatusAccepted, history[0].To)
+	assert.Equal(t, models.ProductStatusAccepted, history[1].From)
+	assert.Equal(t, models.ProductStatusStored, history[1].To)
+	assert.Equal(t, pvz.ID, history[1].PVZID)
+
+	_, err = repo.GetProduct(ctx, uuid.New())
+	assert.Equal(t, e.ErrNotFound(), err)
+}
+
+func testDeleteProduct(t *testing.T, repo repository.PVZRepository) {
+	ctx := context.Background()
+	pvz := insertPVZ(t, repo, 0)
+	start := now()
+	reception := openReception(t, repo, pvz.ID, start)
+
+	_, err := repo.GetLastProduct(ctx, reception.ID, reception.DateTime)
+	assert.Equal(t, e.ErrNotFound(), err)
+
+	first, version := addProduct(t, repo, reception.ID, 1, start.Add(time.Second))
+	second, version := addProduct(t, repo, reception.ID, version, start.Add(2*time.Second))
+
+	last, err := repo.GetLastProduct(ctx, reception.ID, reception.DateTime)
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, last.ID)
+
+	_, err = repo.SetProductPhoto(ctx, second.ID, "products/second.jpg")
+	require.NoError(t, err)
+
+	_, _, err = repo.DeleteProduct(ctx, second.ID, version-1)
+	assert.Equal(t, e.ErrVersionMismatch(), err)
+
+	// The photo key comes back for the caller to remove the photo
+	version, photoKey, err := repo.DeleteProduct(ctx, second.ID, version)
+	require.NoError(t, err)
+	assert.Equal(t, 4, version)
+	assert.Equal(t, "products/second.jpg", photoKey)
+
+	last, err = repo.GetLastProduct(ctx, reception.ID, reception.DateTime)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, last.ID)
+
+	_, _, err = repo.DeleteProduct(ctx, second.ID, version)
+	assert.Equal(t, e.ErrNotFound(), err)
+
+	occupancy, err := repo.GetPVZOccupancy(ctx, pvz.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, occupancy.Count)
+}
+
+func testCapacity(t *testing.T, repo repository.PVZRepository) {
+	ctx := context.Background()
+	pvz := insertPVZ(t, repo, 2)
+	reception := openReception(t, repo, pvz.ID, now())
+
+	require.NoError(t, repo.ReplaceTypeCapacities(ctx, pvz.ID, []models.TypeCapacity{{TypeID: electronicsID, Capacity: 1}}))
+
+	_, version := addProduct(t, repo, reception.ID, 1, now())
+
+	// The type limit is reached before the PVZ one
+	_, err := repo.InsertProduct(ctx, &models.Product{ID: uuid.New(), DateTime: now(), TypeID: electronicsID, ReceptionID: reception.ID, Status: models.ProductStatusAccepted}, version)
+	assert.Equal(t, e.ErrCapacityExceeded(), err)
+
+	// A rejected product leaves the reception version unchanged
+	_, err = repo.InsertProduct(ctx, &models.Product{ID: uuid.New(), DateTime: now(), TypeID: electronicsID + 1, ReceptionID: reception.ID, Status: models.ProductStatusAccepted}, version)
+	require.NoError(t, err)
+
+	occupancy, err := repo.GetPVZOccupancy(ctx, pvz.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, occupancy.Capacity)
+	assert.Equal(t, 2, occupancy.Count)
+
+	list, err := repo.ListPVZOccupancy(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, pvz.ID, list[0].PVZID)
+	assert.Equal(t, 2, list[0].Count)
+
+	_, err = repo.GetPVZOccupancy(ctx, uuid.New())
+	assert.Equal(t, e.ErrNotFound(), err)
+}
+
+func testProductStatus(t *testing.T, repo repository.PVZRepository) {
+	ctx := context.Background()
+	pvz := insertPVZ(t, repo, 0)
+	reception := openReception(t, repo, pvz.ID, now())
+	product, version := addProduct(t, repo, reception.ID, 1, now())
+	_, err := repo.UpdateReceptionStatus(ctx, reception.ID, models.ReceptionStatusClose, version)
+	require.NoError(t, err)
+
+	change := &models.ProductStatusChange{
+		ProductID: product.ID,
+		From:      models.ProductStatusStored,
+		To:        models.ProductStatusIssued,
+		ChangedAt: now(),
+	}
+	require.NoError(t, repo.ChangeProductStatus(ctx, change))
+	assert.Equal(t, pvz.ID, change.PVZID)
+	assert.Equal(t, reception.ID, change.ReceptionID)
+
+	// The product is no longer stored
+	again := &models.ProductStatusChange{ProductID: product.ID, From: models.ProductStatusStored, To: models.ProductStatusIssued, ChangedAt: now()}
+	assert.Equal(t, e.ErrInvalidStatusTransition(), repo.ChangeProductStatus(ctx, again))
+
+	occupancy, err := repo.GetPVZOccupancy(ctx, pvz.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, occupancy.Count)
+}
+
+func testQueries(t *testing.T, repo repository.PVZRepository) {
+	ctx := context.Background()
+	start := now()
+
+	withReception := insertPVZ(t, repo, 0)
+	reception := openReception(t, repo, withReception.ID, start)
+	product, _ := addProduct(t, repo, reception.ID, 1, start.Add(time.Second))
+	insertPVZ(t, repo, 0)
+
+	pvzs, err := repo.GetPVZs(ctx, start.Add(-time.Hour), start.Add(time.Hour), 10, 0)
+	require.NoError(t, err)
+	require.Len(t, pvzs, 1)
+	assert.Equal(t, withReception.ID, pvzs[0].ID)
+
+	all, err := repo.GetPVZsWithNoFilter(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	receptions, err := repo.GetReceptionsForPVZs(ctx, []uuid.UUID{withReception.ID}, start.Add(-time.Hour), start.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, receptions, 1)
+	assert.Equal(t, reception.ID, receptions[0].ID)
+
+	products, err := repo.GetProductsForReceptions(ctx, []uuid.UUID{reception.ID}, reception.DateTime)
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+	assert.Equal(t, product.ID, products[0].ID)
+	assert.Equal(t, "электроника", products[0].TypeName)
+
+	products, err = repo.GetProductsForReceptions(ctx, nil, start)
+	require.NoError(t, err)
+	assert.Empty(t, products)
+
+	var iterated []uuid.UUID
+	err = repo.IteratePVZs(ctx, models.PVZFilter{Cities: []string{"Москва"}, From: start.Add(-time.Hour)}, func(pvz models.PVZ) error {
+		iterated = append(iterated, pvz.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{withReception.ID}, iterated)
+}
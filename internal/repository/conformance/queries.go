@@ -0,0 +1,365 @@
+package conformance
+
+import (
+	"context"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// base is the start of the fixed timeline the query tests are laid out on
+var base = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// listingFixture holds four PVZs registered an hour apart in this order.
+// older, newer and latest have a reception one, two and three days after
+// base; idle has none.
+type listingFixture struct {
+	older, newer, latest, idle          *models.PVZ
+	olderRec, newerRec, latestRec       *models.Reception
+	olderRecAt, newerRecAt, latestRecAt time.Time
+}
+
+func newListingFixture(t *testing.T, repo repository.PVZRepository) *listingFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	register := func(offset time.Duration, cityID int) *models.PVZ {
+		pvz := &models.PVZ{ID: uuid.New(), RegistrationDate: base.Add(offset), CityID: cityID, Status: models.PVZStatusActive}
+		require.NoError(t, repo.InsertPVZ(ctx, pvz))
+		return pvz
+	}
+
+	f := &listingFixture{
+		older:       register(0, moscowID),
+		newer:       register(time.Hour, moscowID+1),
+		latest:      register(2*time.Hour, moscowID),
+		idle:        register(3*time.Hour, moscowID+2),
+		olderRecAt:  base.Add(24 * time.Hour),
+		newerRecAt:  base.Add(48 * time.Hour),
+		latestRecAt: base.Add(72 * time.Hour),
+	}
+	f.olderRec = openReception(t, repo, f.older.ID, f.olderRecAt)
+	f.newerRec = openReception(t, repo, f.newer.ID, f.newerRecAt)
+	f.latestRec = openReception(t, repo, f.latest.ID, f.latestRecAt)
+	return f
+}
+
+func pvzIDs(pvzs []models.PVZ) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(pvzs))
+	for _, pvz := range pvzs {
+		ids = append(ids, pvz.ID)
+	}
+	return ids
+}
+
+func testGetPVZsOrderingAndPagination(t *testing.T, repo repository.PVZRepository) {
+	ctx := context.Background()
+	f := newListingFixture(t, repo)
+
+	// Only PVZs with a reception in the range are listed, newest registration first
+	pvzs, err := repo.GetPVZs(ctx, base, base.Add(96*time.Hour), 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{f.latest.ID, f.newer.ID, f.older.ID}, pvzIDs(pvzs))
+	assert.Equal(t, "Санкт-Петербург", pvzs[1].CityName)
+
+	pages := []struct {
+		limit, offset int
+		want          []uuid.UUID
+	}{
+		{2, 0, []uuid.UUID{f.latest.ID, f.newer.ID}},
+		{2, 2, []uuid.UUID{f.older.ID}},
+		{2, 3, []uuid.UUID{}},
+		{1, 1, []uuid.UUID{f.newer.ID}},
+	}
+	for _, page := range pages {
+		pvzs, err := repo.GetPVZs(ctx, base, base.Add(96*time.Hour), page.limit, page.offset)
+		require.NoError(t, err)
+		assert.Equal(t, page.want, pvzIDs(pvzs), "limit %d offset %d", page.limit, page.offset)
+	}
+
+	// Deleted PVZs stay in the history but leave the full list
+	require.NoError(t, repo.DeletePVZ(ctx, f.older.ID, 1))
+
+	pvzs, err = repo.GetPVZs(ctx, base, base.Add(96*time.Hour), 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{f.latest.ID, f.newer.ID, f.older.ID}, pvzIDs(pvzs))
+	assert.NotNil(t, pvzs[2].DeletedAt)
+	assert.Equal(t, models.PVZStatusClosed, pvzs[2].Status)
+
+	all, err := repo.GetPVZsWithNoFilter(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{f.idle.ID, f.latest.ID, f.newer.ID}, pvzIDs(all))
+}
+
+func testTimeRangeBoundaries(t *testing.T, repo repository.PVZRepository) {
+	ctx := context.Background()
+	f := newListingFixture(t, repo)
+
+	ranges := []struct {
+		name     string
+		from, to time.Time
+		want     []uuid.UUID
+	}{
+		{"both bounds inclusive", f.olderRecAt, f.latestRecAt, []uuid.UUID{f.latest.ID, f.newer.ID, f.older.ID}},
+		{"bounds just inside", f.olderRecAt.Add(time.Microsecond), f.latestRecAt.Add(-time.Microsecond), []uuid.UUID{f.newer.ID}},
+		{"single instant", f.newerRecAt, f.newerRecAt, []uuid.UUID{f.newer.ID}},
+		{"before every reception", base, f.olderRecAt.Add(-time.Microsecond), []uuid.UUID{}},
+		{"after every reception", f.latestRecAt.Add(time.Microsecond), base.Add(96 * time.Hour), []uuid.UUID{}},
+		{"inverted", f.latestRecAt, f.olderRecAt, []uuid.UUID{}},
+	}
+	for _, r := range ranges {
+		t.Run(r.name, func(t *testing.T) {
+			pvzs, err := repo.GetPVZs(ctx, r.from, r.to, 10, 0)
+			require.NoError(t, err)
+			assert.Equal(t, r.want, pvzIDs(pvzs))
+		})
+	}
+
+	all := []uuid.UUID{f.older.ID, f.newer.ID, f.latest.ID, f.idle.ID}
+
+	receptions, err := repo.GetReceptionsForPVZs(ctx, all, f.olderRecAt, f.latestRecAt)
+	require.NoError(t, err)
+	require.Len(t, receptions, 3)
+	// Newest first
+	assert.Equal(t, f.latestRec.ID, receptions[0].ID)
+	assert.Equal(t, f.newerRec.ID, receptions[1].ID)
+	assert.Equal(t, f.olderRec.ID, receptions[2].ID)
+	assert.WithinDuration(t, f.latestRecAt, receptions[0].DateTime, 0)
+
+	receptions, err = repo.GetReceptionsForPVZs(ctx, all, f.olderRecAt.Add(time.Microsecond), f.latestRecAt.Add(-time.Microsecond))
+	require.NoError(t, err)
+	require.Len(t, receptions, 1)
+	assert.Equal(t, f.newerRec.ID, receptions[0].ID)
+
+	// Receptions of PVZs that were not asked for are left out
+	receptions, err = repo.GetReceptionsForPVZs(ctx, []uuid.UUID{f.older.ID}, base, base.Add(96*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, receptions, 1)
+	assert.Equal(t, f.olderRec.ID, receptions[0].ID)
+}
+
+func testIteratePVZs(t *testing.T, repo repository.PVZRepository) {
+	ctx := context.Background()
+	f := newListingFixture(t, repo)
+
+	filters := []struct {
+		name   string
+		filter models.PVZFilter
+		want   []uuid.UUID
+	}{
+		{"no filter", models.PVZFilter{}, []uuid.UUID{f.idle.ID, f.latest.ID, f.newer.ID, f.older.ID}},
+		{"cities", models.PVZFilter{Cities: []string{"Москва", "Казань"}}, []uuid.UUID{f.idle.ID, f.latest.ID, f.older.ID}},
+		{"from inclusive", models.PVZFilter{From: f.newerRecAt}, []uuid.UUID{f.latest.ID, f.newer.ID}},
+		{"to inclusive", models.PVZFilter{To: f.newerRecAt}, []uuid.UUID{f.newer.ID, f.older.ID}},
+		{"single instant", models.PVZFilter{From: f.newerRecAt, To: f.newerRecAt}, []uuid.UUID{f.newer.ID}},
+		{"cities and range", models.PVZFilter{Cities: []string{"Москва"}, From: f.newerRecAt}, []uuid.UUID{f.latest.ID}},
+	}
+	for _, tt := range filters {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []uuid.UUID{}
+			err := repo.IteratePVZs(ctx, tt.filter, func(pvz models.PVZ) error {
+				got = append(got, pvz.ID)
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	// Iteration stops at the first error of fn
+	stop := assert.AnError
+	calls := 0
+	err := repo.IteratePVZs(ctx, models.PVZFilter{}, func(models.PVZ) error {
+		calls++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, calls)
+
+	// A deleted PVZ is only iterated when filtering by reception date
+	require.NoError(t, repo.DeletePVZ(ctx, f.older.ID, 1))
+
+	deleted := []struct {
+		name   string
+		filter models.PVZFilter
+		want   []uuid.UUID
+	}{
+		{"no filter", models.PVZFilter{}, []uuid.UUID{f.idle.ID, f.latest.ID, f.newer.ID}},
+		{"cities", models.PVZFilter{Cities: []string{"Москва", "Казань"}}, []uuid.UUID{f.idle.ID, f.latest.ID}},
+		{"to inclusive", models.PVZFilter{To: f.newerRecAt}, []uuid.UUID{f.newer.ID, f.older.ID}},
+	}
+	for _, tt := range deleted {
+		t.Run("deleted "+tt.name, func(t *testing.T) {
+			got := []uuid.UUID{}
+			err := repo.IteratePVZs(ctx, tt.filter, func(pvz models.PVZ) error {
+				got = append(got, pvz.ID)
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func testProductOrdering(t *testing.T, repo repository.PVZRepository) {
+	ctx := context.Background()
+	pvz := insertPVZ(t, repo, 0)
+	reception := openReception(t, repo, pvz.ID, base)
+	other := openReception(t, repo, insertPVZ(t, repo, 0).ID, base)
+
+	// Products are added out of time order
+	middle, version := addProduct(t, repo, reception.ID, 1, base.Add(2*time.Minute))
+	last, version := addProduct(t, repo, reception.ID, version, base.Add(3*time.Minute))
+	first, _ := addProduct(t, repo, reception.ID, version, base.Add(time.Minute))
+	foreign, _ := addProduct(t, repo, other.ID, 1, base.Add(4*time.Minute))
+
+	got, err := repo.GetLastProduct(ctx, reception.ID, reception.DateTime)
+	require.NoError(t, err)
+	assert.Equal(t, last.ID, got.ID)
+	assert.WithinDuration(t, last.DateTime, got.DateTime, 0)
+
+	// Products dated before since are not looked at
+	_, err = repo.GetLastProduct(ctx, reception.ID, last.DateTime.Add(time.Second))
+	assert.Equal(t, e.ErrNotFound(), err)
+
+	products, err := repo.GetProductsForReceptions(ctx, []uuid.UUID{reception.ID}, base)
+	require.NoError(t, err)
+	ids := make([]uuid.UUID, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.ID)
+	}
+	assert.Equal(t, []uuid.UUID{last.ID, middle.ID, first.ID}, ids)
+
+	products, err = repo.GetProductsForReceptions(ctx, []uuid.UUID{reception.ID, other.ID}, base)
+	require.NoError(t, err)
+	require.Len(t, products, 4)
+	assert.Equal(t, foreign.ID, products[0].ID)
+
+	// Products dated before the bound are left out
+	products, err = repo.GetProductsForReceptions(ctx, []uuid.UUID{reception.ID}, middle.DateTime)
+	require.NoError(t, err)
+	ids = ids[:0]
+	for _, product := range products {
+		ids = append(ids, product.ID)
+	}
+	assert.Equal(t, []uuid.UUID{last.ID, middle.ID}, ids)
+}
+
+func testStaleReceptions(t *testing.T, repo repository.PVZRepository) {
+	ctx := context.Background()
+
+	idle := openReception(t, repo, insertPVZ(t, repo, 0).ID, base)
+	busy := openReception(t, repo, insertPVZ(t, repo, 0).ID, base.Add(time.Hour))
+	product, _ := addProduct(t, repo, busy.ID, 1, base.Add(3*time.Hour))
+
+	// Idleness is strict: activity exactly at idleSince is recent
+	stale, err := repo.GetStaleReceptions(ctx, base)
+	require.NoError(t, err)
+	assert.Empty(t, stale)
+
+	stale, err = repo.GetStaleReceptions(ctx, base.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, stale, 1)
+	assert.Equal(t, idle.ID, stale[0].ID)
+	assert.WithinDuration(t, base, stale[0].LastActivity, 0)
+
+	// The last product counts as activity, least recently active first
+	stale, err = repo.GetStaleReceptions(ctx, base.Add(4*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, stale, 2)
+	assert.Equal(t, idle.ID, stale[0].ID)
+	assert.Equal(t, busy.ID, stale[1].ID)
+	assert.WithinDuration(t, product.DateTime, stale[1].LastActivity, 0)
+	assert.Equal(t, 2, stale[1].Version)
+	assert.False(t, stale[1].Flagged)
+
+	flag := &models.ReceptionAuditEntry{
+		ReceptionID: idle.ID,
+		PVZID:       idle.PVZID,
+		Action:      models.ReceptionAuditFlaggedStale,
+		Actor:       models.ReceptionAuditActorSystem,
+		CreatedAt:   base.Add(5 * time.Hour),
+	}
+	require.NoError(t, repo.InsertReceptionAudit(ctx, flag))
+
+	closed := &models.ReceptionAuditEntry{
+		Action:    models.ReceptionAuditForceClosed,
+		Actor:     "moderator@example.com",
+		Reason:    "forgotten",
+		CreatedAt: base.Add(6 * time.Hour),
+	}
+	_, err = repo.ForceCloseReception(ctx, idle.ID, 1, closed)
+	require.NoError(t, err)
+
+	stale, err = repo.GetStaleReceptions(ctx, base.Add(4*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, stale, 1)
+	assert.Equal(t, busy.ID, stale[0].ID)
+
+	// Audit entries are listed newest first
+	audit, err := repo.GetPVZReceptionAudit(ctx, idle.PVZID)
+	require.NoError(t, err)
+	require.Len(t, audit, 2)
+	assert.Equal(t, models.ReceptionAuditForceClosed, audit[0].Action)
+	assert.Equal(t, idle.ID, audit[0].ReceptionID)
+	assert.Equal(t, models.ReceptionAuditFlaggedStale, audit[1].Action)
+}
+
+func testManifestOrdering(t *testing.T, repo repository.PVZRepository) {
+	ctx := context.Background()
+	pvz := insertPVZ(t, repo, 0)
+
+	create := func(createdAt time.Time) *models.Manifest {
+		manifest := &models.Manifest{
+			ID:        uuid.New(),
+			PVZID:     pvz.ID,
+			Supplier:  "supplier",
+			Status:    models.ManifestStatusPending,
+			Items:     []models.ManifestItem{{Barcode: "B2", TypeID: electronicsID + 1}, {Barcode: "A1", TypeID: electronicsID}},
+			CreatedAt: createdAt,
+		}
+		require.NoError(t, repo.CreateManifest(ctx, manifest))
+		return manifest
+	}
+	older := create(base)
+	newer := create(base.Add(time.Hour))
+
+	manifests, err := repo.GetPVZManifests(ctx, pvz.ID, "")
+	require.NoError(t, err)
+	require.Len(t, manifests, 2)
+	assert.Equal(t, newer.ID, manifests[0].ID)
+	assert.Equal(t, older.ID, manifests[1].ID)
+	// Items are sorted by barcode
+	require.Len(t, manifests[0].Items, 2)
+	assert.Equal(t, "A1", manifests[0].Items[0].Barcode)
+	assert.Equal(t, "электроника", manifests[0].Items[0].TypeName)
+
+	reception := &models.Reception{ID: uuid.New(), DateTime: base.Add(2 * time.Hour), PVZID: pvz.ID, Status: models.ReceptionStatusInProgress, ManifestID: &older.ID}
+	require.NoError(t, repo.InsertReception(ctx, reception))
+	product := &models.Product{ID: uuid.New(), DateTime: base.Add(3 * time.Hour), TypeID: electronicsID, ReceptionID: reception.ID, Status: models.ProductStatusAccepted, Barcode: "A1"}
+	version, err := repo.InsertProduct(ctx, product, 1)
+	require.NoError(t, err)
+	_, err = repo.UpdateReceptionStatus(ctx, reception.ID, models.ReceptionStatusClose, version)
+	require.NoError(t, err)
+
+	manifests, err = repo.GetPVZManifests(ctx, pvz.ID, models.ManifestStatusPending)
+	require.NoError(t, err)
+	require.Len(t, manifests, 1)
+	assert.Equal(t, newer.ID, manifests[0].ID)
+
+	reconciled, err := repo.GetManifest(ctx, older.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ManifestStatusReconciled, reconciled.Status)
+	assert.Equal(t, reception.ID, *reconciled.ReceptionID)
+	if assert.NotNil(t, reconciled.Report) {
+		assert.Equal(t, 2, reconciled.Report.Expected)
+		require.Len(t, reconciled.Report.Missing, 1)
+		assert.Equal(t, "B2", reconciled.Report.Missing[0].Barcode)
+	}
+}
@@ -0,0 +1,157 @@
+package conformance
+
+import (
+	"context"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testErrorSentinels checks that missing rows and lost races are reported
+// with the errors the services map to HTTP statuses, never as driver errors
+func testErrorSentinels(t *testing.T, repo repository.PVZRepository) {
+	ctx := context.Background()
+	unknown := uuid.New()
+
+	pvz := insertPVZ(t, repo, 0)
+	reception := openReception(t, repo, pvz.ID, base)
+	product, version := addProduct(t, repo, reception.ID, 1, base.Add(time.Minute))
+
+	checks := []struct {
+		name string
+		want error
+		call func() error
+	}{
+		{"GetPVZ unknown", e.ErrNotFound(), func() error {
+			_, err := repo.GetPVZ(ctx, unknown)
+			return err
+		}},
+		{"CheckPVZ unknown", e.ErrNotFound(), func() error {
+			_, err := repo.CheckPVZ(ctx, unknown)
+			return err
+		}},
+		{"UpdatePVZ unknown", e.ErrVersionMismatch(), func() error {
+			_, err := repo.UpdatePVZ(ctx, &models.PVZ{ID: unknown, CityID: moscowID, Status: models.PVZStatusActive}, 1)
+			return err
+		}},
+		{"DeletePVZ unknown", e.ErrVersionMismatch(), func() error {
+			return repo.DeletePVZ(ctx, unknown, 1)
+		}},
+		{"GetPVZOccupancy unknown", e.ErrNotFound(), func() error {
+			_, err := repo.GetPVZOccupancy(ctx, unknown)
+			return err
+		}},
+		{"GetPVZSchedule unknown", e.ErrNotFound(), func() error {
+			_, err := repo.GetPVZSchedule(ctx, unknown)
+			return err
+		}},
+		{"DeletePVZSchedule unknown", e.ErrNotFound(), func() error {
+			return repo.DeletePVZSchedule(ctx, unknown)
+		}},
+		{"GetActiveReception without one", e.ErrNoActiveReception(), func() error {
+			_, err := repo.GetActiveReception(ctx, unknown)
+			return err
+		}},
+		{"UpdateReceptionStatus unknown", e.ErrVersionMismatch(), func() error {
+			_, err := repo.UpdateReceptionStatus(ctx, unknown, models.ReceptionStatusClose, 1)
+			return err
+		}},
+		{"UpdateReceptionStatus stale version", e.ErrVersionMismatch(), func() error {
+			_, err := repo.UpdateReceptionStatus(ctx, reception.ID, models.ReceptionStatusClose, version-1)
+			return err
+		}},
+		{"InsertProduct unknown reception", e.ErrVersionMismatch(), func() error {
+			_, err := repo.InsertProduct(ctx, &models.Product{ID: uuid.New(), DateTime: base, TypeID: electronicsID, ReceptionID: unknown, Status: models.ProductStatusAccepted}, 1)
+			return err
+		}},
+		{"GetLastProduct empty reception", e.ErrNotFound(), func() error {
+			_, err := repo.GetLastProduct(ctx, unknown, time.Time{})
+			return err
+		}},
+		{"DeleteProduct unknown", e.ErrNotFound(), func() error {
+			_, _, err := repo.DeleteProduct(ctx, unknown, version)
+			return err
+		}},
+		{"DeleteProduct stale version", e.ErrVersionMismatch(), func() error {
+			_, _, err := repo.DeleteProduct(ctx, product.ID, version-1)
+			return err
+		}},
+		{"GetProduct unknown", e.ErrNotFound(), func() error {
+			_, err := repo.GetProduct(ctx, unknown)
+			return err
+		}},
+		{"ChangeProductStatus unknown", e.ErrInvalidStatusTransition(), func() error {
+			return repo.ChangeProductStatus(ctx, &models.ProductStatusChange{ProductID: unknown, From: models.ProductStatusStored, To: models.ProductStatusIssued, ChangedAt: base})
+		}},
+		{"ChangeProductStatus wrong from", e.ErrInvalidStatusTransition(), func() error {
+			return repo.ChangeProductStatus(ctx, &models.ProductStatusChange{ProductID: product.ID, From: models.ProductStatusStored, To: models.ProductStatusIssued, ChangedAt: base})
+		}},
+		{"SetProductCondition unknown", e.ErrProductNotInReception(), func() error {
+			_, _, err := repo.SetProductCondition(ctx, unknown, models.ProductConditionDamaged, "")
+			return err
+		}},
+		{"SetProductPhoto unknown", e.ErrProductNotInReception(), func() error {
+			_, err := repo.SetProductPhoto(ctx, unknown, "photo.jpg")
+			return err
+		}},
+		{"CreateTransfer product not stored", e.ErrProductNotTransferable(), func() error {
+			return repo.CreateTransfer(ctx, &models.Transfer{
+				ID:               uuid.New(),
+				SourcePVZID:      pvz.ID,
+				DestinationPVZID: insertPVZ(t, repo, 0).ID,
+				Status:           models.TransferStatusOutbound,
+				ProductIDs:       []uuid.UUID{product.ID},
+				CreatedAt:        base,
+			})
+		}},
+		{"GetTransfer unknown", e.ErrNotFound(), func() error {
+			_, err := repo.GetTransfer(ctx, unknown)
+			return err
+		}},
+		{"DispatchTransfer unknown", e.ErrInvalidTransferState(), func() error {
+			_, err := repo.DispatchTransfer(ctx, unknown, base)
+			return err
+		}},
+		{"ReceiveTransfer unknown", e.ErrInvalidTransferState(), func() error {
+			_, _, err := repo.ReceiveTransfer(ctx, unknown, reception.ID, version, base)
+			return err
+		}},
+		{"CancelTransfer unknown", e.ErrInvalidTransferState(), func() error {
+			return repo.CancelTransfer(ctx, unknown)
+		}},
+		{"GetManifest unknown", e.ErrNotFound(), func() error {
+			_, err := repo.GetManifest(ctx, unknown)
+			return err
+		}},
+		{"InsertReception unknown manifest", e.ErrManifestNotAvailable(), func() error {
+			return repo.InsertReception(ctx, &models.Reception{ID: uuid.New(), DateTime: base, PVZID: insertPVZ(t, repo, 0).ID, Status: models.ReceptionStatusInProgress, ManifestID: &unknown})
+		}},
+		{"ForceCloseReception stale version", e.ErrVersionMismatch(), func() error {
+			_, err := repo.ForceCloseReception(ctx, reception.ID, version-1, &models.ReceptionAuditEntry{Action: models.ReceptionAuditForceClosed, CreatedAt: base})
+			return err
+		}},
+	}
+
+	for _, check := range checks {
+		t.Run(check.name, func(t *testing.T) {
+			assert.Equal(t, check.want, check.call())
+		})
+	}
+
+	// None of the failed calls changed the reception
+	active, err := repo.GetActiveReception(ctx, pvz.ID)
+	require.NoError(t, err)
+	assert.Equal(t, reception.ID, active.ID)
+	assert.Equal(t, version, active.Version)
+
+	last, err := repo.GetLastProduct(ctx, reception.ID, reception.DateTime)
+	require.NoError(t, err)
+	assert.Equal(t, product.ID, last.ID)
+	assert.Equal(t, models.ProductStatusAccepted, last.Status)
+}
@@ -0,0 +1,81 @@
+package repository_test
+
+import (
+	"context"
+	"path/filepath"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/repository/conformance"
+	"pvz-service/internal/repository/memory"
+	"pvz-service/internal/repository/migrations"
+	"pvz-service/internal/repository/postgres"
+	"pvz-service/internal/repository/sqlite"
+	"testing"
+
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryConformance(t *testing.T) {
+	t.Run("PVZRepository", func(t *testing.T) {
+		conformance.PVZRepository(t, func(t *testing.T) repository.PVZRepository {
+			return memory.New()
+		})
+	})
+	t.Run("AuthRepository", func(t *testing.T) {
+		conformance.AuthRepository(t, func(t *testing.T) repository.AuthRepository {
+			return memory.New()
+		})
+	})
+}
+
+func openSQLite(t *testing.T) *sqlite.SQLite {
+	t.Helper()
+
+	repo, err := sqlite.Open(filepath.Join(t.TempDir(), "pvz.db"))
+	require.NoError(t, err)
+	t.Cleanup(repo.CloseConnection)
+	return repo
+}
+
+func TestSQLiteConformance(t *testing.T) {
+	t.Run("PVZRepository", func(t *testing.T) {
+		conformance.PVZRepository(t, func(t *testing.T) repository.PVZRepository {
+			return openSQLite(t)
+		})
+	})
+	t.Run("AuthRepository", func(t *testing.T) {
+		conformance.AuthRepository(t, func(t *testing.T) repository.AuthRepository {
+			return openSQLite(t)
+		})
+	})
+}
+
+// TestPostgresConformance runs the suite against a Postgres server started
+// from the local binaries and is skipped where there are none
+func TestPostgresConformance(t *testing.T) {
+	pg := conformance.StartPostgres(t)
+
+	openPostgres := func(t *testing.T) *postgres.Postgres {
+		db := pg.NewDatabase(t)
+		provider, err := goose.NewProvider(goose.DialectPostgres, db, migrations.FS,
+			goose.WithGoMigrations(migrations.GoMigrations()...))
+		require.NoError(t, err)
+		_, err = provider.Up(context.Background())
+		require.NoError(t, err)
+
+		repo := new(postgres.Postgres)
+		repo.SetRepository(db)
+		return repo
+	}
+
+	t.Run("PVZRepository", func(t *testing.T) {
+		conformance.PVZRepository(t, func(t *testing.T) repository.PVZRepository {
+			return openPostgres(t)
+		})
+	})
+	t.Run("AuthRepository", func(t *testing.T) {
+		conformance.AuthRepository(t, func(t *testing.T) repository.AuthRepository {
+			return openPostgres(t)
+		})
+	})
+}
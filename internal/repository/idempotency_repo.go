@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"pvz-service/internal/models"
+	"time"
+)
+
+type IdempotencyRepository interface {
+	// ReserveIdempotencyKey claims key for a new request, locking it for
+	// lease. It returns nil when the key was free or expired, or held past
+	// its lease by a request with the same fingerprint, and the existing
+	// record otherwise.
+	ReserveIdempotencyKey(ctx context.Context, key, fingerprint string, ttl, lease time.Duration) (*models.IdempotencyRecord, error)
+	SaveIdempotencyResponse(ctx context.Context, key string, statusCode int, contentType string, body []byte) error
+	DeleteIdempotencyKey(ctx context.Context, key string) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context) (int, error)
+}
+
+func CreateIdempotencyRepo(db Database) (IdempotencyRepository, error) {
+	const op = "repository.idempotency_repo.CreateIdempotencyRepo"
+
+	repo, ok := db.(IdempotencyRepository)
+	if !ok {
+		return nil, fmt.Errorf("%s: %T does not implement IdempotencyRepository", op, db)
+	}
+
+	return repo, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository/memory"
+	"pvz-service/internal/repository/postgres"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockIdempotencyRepository implements IdempotencyRepository for testing
+type MockIdempotencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyRepository) ReserveIdempotencyKey(ctx context.Context, key, fingerprint string, ttl, lease time.Duration) (*models.IdempotencyRecord, error) {
+	args := m.Called(ctx, key, fingerprint, ttl, lease)
+	record, _ := args.Get(0).(*models.IdempotencyRecord)
+	return record, args.Error(1)
+}
+
+func (m *MockIdempotencyRepository) SaveIdempotencyResponse(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	args := m.Called(ctx, key, statusCode, contentType, body)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) DeleteExpiredIdempotencyKeys(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func TestCreateIdempotencyRepo(t *testing.T) {
+	tests := []struct {
+		name        string
+		db          Database
+		expectError bool
+	}{
+		{
+			name: "Success with postgres",
+			db:   new(postgres.Postgres),
+		},
+		{
+			name: "Success with memory",
+			db:   memory.New(),
+		},
+		{
+			name: "Success with sqlite",
+			db:   openSQLite(t),
+		},
+		{
+			name:        "Not an idempotency repository",
+			db:          stubDatabase{},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := CreateIdempotencyRepo(tt.db)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, repo)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, repo)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package memory
+
+import (
+	"context"
+	"pvz-service/internal/models"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// archivable reports whether the reception may be moved to the archive: all
+// its products are settled and neither transfers nor the history of other
+// products refer to it. Manifests and audit entries keep referring to the
+// archived reception. Callers hold mu.
+func (m *Memory) archivable(reception *models.Reception) bool {
+	for _, product := range m.products {
+		if product.ReceptionID != reception.ID {
+			continue
+		}
+		if !product.Status.Settled() || m.transferred(product.ID) {
+			return false
+		}
+	}
+
+	for _, transfer := range m.transfers {
+		if transfer.ReceptionID != nil && *transfer.ReceptionID == reception.ID {
+			return false
+		}
+	}
+	for _, change := range m.history {
+		if change.ReceptionID != reception.ID {
+			continue
+		}
+		if product, ok := m.products[change.ProductID]; ok && product.ReceptionID != reception.ID {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Memory) ArchiveReceptions(ctx context.Context, closedBefore, archivedAt time.Time, limit int) (int, int, []string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var candidates []*models.Reception
+	for _, reception := range m.receptions {
+		if reception.Status == models.ReceptionStatusClose && reception.DateTime.Before(closedBefore) && m.archivable(reception) {
+			candidates = append(candidates, reception)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].DateTime.Before(candidates[j].DateTime)
+	})
+	candidates = page(candidates, limit, 0)
+
+	products := 0
+	var photoKeys []string
+	for _, reception := range candidates {
+		for id, product := range m.products {
+			if product.ReceptionID != reception.ID {
+				continue
+			}
+			// Photos are not archived, the caller removes them
+			if product.PhotoKey != "" {
+				photoKeys = append(photoKeys, product.PhotoKey)
+				product.PhotoKey = ""
+			}
+			m.archivedProducts[id] = product
+			delete(m.products, id)
+			products++
+		}
+		m.archivedReceptions[reception.ID] = reception
+		delete(m.receptions, reception.ID)
+	}
+
+	m.history = slices.DeleteFunc(m.history, func(change models.ProductStatusChange) bool {
+		if _, ok := m.archivedProducts[change.ProductID]; !ok {
+			return false
+		}
+		m.archivedHistory = append(m.archivedHistory, change)
+		return true
+	})
+
+	return len(candidates), products, photoKeys, nil
+}
+
+func (m *Memory) GetArchivedReceptions(ctx context.Context, pvzID uuid.UUID, from, to time.Time, limit, offset int) ([]models.Reception, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	receptions := []models.Reception{}
+	for _, reception := range m.archivedReceptions {
+		if reception.PVZID != pvzID || reception.DateTime.Before(from) || reception.DateTime.After(to) {
+			continue
+		}
+		receptions = append(receptions, models.Reception{
+			ID:       reception.ID,
+			DateTime: reception.DateTime,
+			PVZID:    reception.PVZID,
+			Status:   reception.Status,
+		})
+	}
+
+	sort.Slice(receptions, func(i, j int) bool {
+		return receptions[i].DateTime.After(receptions[j].DateTime)
+	})
+	return append([]models.Reception{}, page(receptions, limit, offset)...), nil
+}
+
+func (m *Memory) GetArchivedProducts(ctx context.Context, receptionIDs []uuid.UUID) ([]models.Product, error) {
+	if len(receptionIDs) == 0 {
+		return []models.Product{}, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	products := []models.Product{}
+	for _, product := range m.archivedProducts {
+		if slices.Contains(receptionIDs, product.ReceptionID) {
+			products = append(products, m.productView(product))
+		}
+	}
+
+	sort.Slice(products, func(i, j int) bool {
+		return products[i].DateTime.After(products[j].DateTime)
+	})
+	return products, nil
+}
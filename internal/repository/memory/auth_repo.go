@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func (m *Memory) CreateUser(ctx context.Context, email, password string, role models.UserRole) (*models.User, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.users[email]; ok {
+		return nil, e.ErrAlreadyExists()
+	}
+
+	user := &models.User{
+		ID:           uuid.New(),
+		Email:        email,
+		PasswordHash: string(hashedPassword),
+		Role:         role,
+	}
+	m.users[user.Email] = user
+
+	stored := *user
+	return &stored, nil
+}
+
+func (m *Memory) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	user, ok := m.users[email]
+	if !ok {
+		return nil, e.ErrNotFound()
+	}
+
+	found := *user
+	return &found, nil
+}
+
+func (m *Memory) GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, user := range m.users {
+		if user.ID == id {
+			found := *user
+			return &found, nil
+		}
+	}
+	return nil, e.ErrNotFound()
+}
+
+func (m *Memory) VerifyPassword(ctx context.Context, email, password string) (bool, error) {
+	user, err := m.GetUserByEmail(ctx, email)
+	if err != nil {
+		return false, err
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
+	return err == nil, nil
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"pvz-service/internal/models"
+	"slices"
+	"time"
+)
+
+func (m *Memory) ReserveIdempotencyKey(ctx context.Context, key, fingerprint string, ttl, lease time.Duration) (*models.IdempotencyRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	// An expired record is taken over as if the key were new, and so is one
+	// whose request was lost before completing, if the retry is the same
+	if record, ok := m.idempotency[key]; ok && record.ExpiresAt.After(now) {
+		lost := !record.Completed() && !record.LockedUntil.After(now) && record.Fingerprint == fingerprint
+		if !lost {
+			existing := *record
+			existing.Body = slices.Clone(record.Body)
+			return &existing, nil
+		}
+	}
+
+	m.idempotency[key] = &models.IdempotencyRecord{
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+		LockedUntil: now.Add(lease),
+	}
+	return nil, nil
+}
+
+func (m *Memory) SaveIdempotencyResponse(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if record, ok := m.idempotency[key]; ok {
+		record.StatusCode = statusCode
+		record.ContentType = contentType
+		record.Body = slices.Clone(body)
+	}
+	return nil
+}
+
+func (m *Memory) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.idempotency, key)
+	return nil
+}
+
+func (m *Memory) DeleteExpiredIdempotencyKeys(ctx context.Context) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	var deleted int
+	for key, record := range m.idempotency {
+		if !record.ExpiresAt.After(now) {
+			delete(m.idempotency, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
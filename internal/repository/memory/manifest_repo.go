@@ -0,0 +1,119 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (m *Memory) CreateManifest(ctx context.Context, manifest *models.Manifest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.manifests[manifest.ID]; ok {
+		return fmt.Errorf("manifest %s already exists", manifest.ID)
+	}
+
+	m.manifests[manifest.ID] = &models.Manifest{
+		ID:        manifest.ID,
+		PVZID:     manifest.PVZID,
+		Supplier:  manifest.Supplier,
+		Status:    manifest.Status,
+		Items:     append([]models.ManifestItem{}, manifest.Items...),
+		CreatedAt: manifest.CreatedAt,
+	}
+	return nil
+}
+
+// manifestView returns a copy of a stored manifest with its items sorted by
+// barcode and named by type. Callers hold mu.
+func (m *Memory) manifestView(stored *models.Manifest) models.Manifest {
+	manifest := *stored
+	manifest.Items = make([]models.ManifestItem, len(stored.Items))
+	for i, item := range stored.Items {
+		manifest.Items[i] = models.ManifestItem{
+			Barcode:  item.Barcode,
+			TypeID:   item.TypeID,
+			TypeName: m.productTypeName(item.TypeID),
+		}
+	}
+	sort.SliceStable(manifest.Items, func(i, j int) bool {
+		return manifest.Items[i].Barcode < manifest.Items[j].Barcode
+	})
+	return manifest
+}
+
+func (m *Memory) GetManifest(ctx context.Context, manifestID uuid.UUID) (*models.Manifest, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stored, ok := m.manifests[manifestID]
+	if !ok {
+		return nil, e.ErrNotFound()
+	}
+
+	manifest := m.manifestView(stored)
+	return &manifest, nil
+}
+
+func (m *Memory) GetPVZManifests(ctx context.Context, pvzID uuid.UUID, status models.ManifestStatus) ([]models.Manifest, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	manifests := []models.Manifest{}
+	for _, manifest := range m.manifests {
+		if manifest.PVZID != pvzID || (status != "" && manifest.Status != status) {
+			continue
+		}
+		manifests = append(manifests, m.manifestView(manifest))
+	}
+
+	sort.Slice(manifests, func(i, j int) bool {
+		return manifests[i].CreatedAt.After(manifests[j].CreatedAt)
+	})
+	return manifests, nil
+}
+
+// reconcileManifest compares the products of a closed reception with the
+// manifest it was started with and saves the discrepancy report. It returns
+// nil if the reception has no manifest. Callers hold mu.
+func (m *Memory) reconcileManifest(receptionID uuid.UUID, reconciledAt time.Time) *models.DiscrepancyReport {
+	var manifest *models.Manifest
+	for _, candidate := range m.manifests {
+		if candidate.ReceptionID != nil && *candidate.ReceptionID == receptionID && candidate.Status == models.ManifestStatusReceiving {
+			manifest = candidate
+			break
+		}
+	}
+	if manifest == nil {
+		return nil
+	}
+
+	var (
+		scanned   = map[string]int{}
+		unlabeled int
+	)
+	for _, product := range m.products {
+		if product.ReceptionID != receptionID {
+			continue
+		}
+		if product.Barcode == "" {
+			unlabeled++
+		} else {
+			scanned[product.Barcode]++
+		}
+	}
+
+	report := models.Reconcile(m.manifestView(manifest).Items, scanned, unlabeled)
+
+	manifest.Status = models.ManifestStatusReconciled
+	manifest.Report = report
+	manifest.ReconciledAt = &reconciledAt
+
+	return report
+}
@@ -0,0 +1,120 @@
+// Package memory keeps the service data in process memory. It implements the
+// same repository interfaces and error semantics as the postgres package, so
+// the service can run for local development and tests without a database.
+// Nothing survives a restart.
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"pvz-service/internal/models"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cities and product types seeded by the migrations. Their IDs are the index
+// in the list plus one.
+var (
+	seedCities       = []string{"Москва", "Санкт-Петербург", "Казань"}
+	seedProductTypes = []string{"электроника", "одежда", "обувь"}
+)
+
+// Memory holds every table behind a single mutex. Methods check all their
+// preconditions before changing anything, so a failed call leaves the data
+// as it was, like a rolled back transaction.
+type Memory struct {
+	mu sync.RWMutex
+
+	users        map[string]*models.User // by email
+	cities       []string
+	productTypes []string
+
+	pvzs       map[uuid.UUID]*models.PVZ
+	schedules  map[uuid.UUID]*pvzSchedule
+	occupancy  map[uuid.UUID]map[int]int
+	capacities map[uuid.UUID]map[int]int
+
+	receptions map[uuid.UUID]*models.Reception
+	products   map[uuid.UUID]*models.Product
+	history    []models.ProductStatusChange
+	transfers  map[uuid.UUID]*models.Transfer
+	manifests  map[uuid.UUID]*models.Manifest
+	audit      []models.ReceptionAuditEntry
+
+	// Receptions moved by ArchiveReceptions with their products and history
+	archivedReceptions map[uuid.UUID]*models.Reception
+	archivedProducts   map[uuid.UUID]*models.Product
+	archivedHistory    []models.ProductStatusChange
+
+	outbox        []*outboxEvent
+	subscriptions map[uuid.UUID]*models.WebhookSubscription
+	deliveries    []*webhookDelivery
+	idempotency   map[string]*models.IdempotencyRecord
+}
+
+func New() *Memory {
+	return &Memory{
+		users:              make(map[string]*models.User),
+		cities:             append([]string(nil), seedCities...),
+		productTypes:       append([]string(nil), seedProductTypes...),
+		pvzs:               make(map[uuid.UUID]*models.PVZ),
+		schedules:          make(map[uuid.UUID]*pvzSchedule),
+		occupancy:          make(map[uuid.UUID]map[int]int),
+		capacities:         make(map[uuid.UUID]map[int]int),
+		receptions:         make(map[uuid.UUID]*models.Reception),
+		products:           make(map[uuid.UUID]*models.Product),
+		transfers:          make(map[uuid.UUID]*models.Transfer),
+		manifests:          make(map[uuid.UUID]*models.Manifest),
+		archivedReceptions: make(map[uuid.UUID]*models.Reception),
+		archivedProducts:   make(map[uuid.UUID]*models.Product),
+		subscriptions:      make(map[uuid.UUID]*models.WebhookSubscription),
+		idempotency:        make(map[string]*models.IdempotencyRecord),
+	}
+}
+
+func (m *Memory) CloseConnection() {}
+
+// cityName and productTypeName return an empty string for unknown IDs, like
+// the joins of the postgres queries would drop the row
+func (m *Memory) cityName(id int) string {
+	if id < 1 || id > len(m.cities) {
+		return ""
+	}
+	return m.cities[id-1]
+}
+
+func (m *Memory) productTypeName(id int) string {
+	if id < 1 || id > len(m.productTypes) {
+		return ""
+	}
+	return m.productTypes[id-1]
+}
+
+// outboxEvent is an event waiting in the outbox
+type outboxEvent struct {
+	models.Event
+	nextAttemptAt time.Time
+	publishedAt   *time.Time
+	deadAt        *time.Time
+	lastError     string
+}
+
+// insertEvent records an event in the outbox. Callers hold mu.
+func (m *Memory) insertEvent(eventType models.EventType, pvzID, receptionID uuid.UUID, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event: %w", eventType, err)
+	}
+
+	m.outbox = append(m.outbox, &outboxEvent{Event: models.Event{
+		ID:          uuid.New(),
+		Type:        eventType,
+		PVZID:       pvzID,
+		ReceptionID: receptionID,
+		Payload:     data,
+		CreatedAt:   time.Now(),
+	}})
+	return nil
+}
@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"context"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+func (m *Memory) GetPVZOccupancy(ctx context.Context, pvzID uuid.UUID) (*models.PVZOccupancy, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	pvz, ok := m.activePVZ(pvzID)
+	if !ok {
+		return nil, e.ErrNotFound()
+	}
+
+	occupancy := models.PVZOccupancy{PVZID: pvzID, Capacity: pvz.Capacity}
+	for i, name := range m.productTypes {
+		typeID := i + 1
+		t := models.TypeOccupancy{
+			TypeName: name,
+			Count:    m.occupancy[pvzID][typeID],
+			Capacity: m.capacities[pvzID][typeID],
+		}
+		occupancy.Count += t.Count
+		occupancy.Types = append(occupancy.Types, t)
+	}
+
+	return &occupancy, nil
+}
+
+func (m *Memory) ListPVZOccupancy(ctx context.Context) ([]models.PVZOccupancy, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []models.PVZOccupancy
+	for _, pvz := range m.pvzs {
+		if pvz.DeletedAt != nil {
+			continue
+		}
+
+		o := models.PVZOccupancy{PVZID: pvz.ID, Capacity: pvz.Capacity}
+		for _, count := range m.occupancy[pvz.ID] {
+			o.Count += count
+		}
+		result = append(result, o)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].PVZID.String() < result[j].PVZID.String()
+	})
+	return result, nil
+}
+
+func (m *Memory) ReplaceTypeCapacities(ctx context.Context, pvzID uuid.UUID, capacities []models.TypeCapacity) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	limits := make(map[int]int, len(capacities))
+	for _, c := range capacities {
+		limits[c.TypeID] = c.Capacity
+	}
+	m.capacities[pvzID] = limits
+
+	return nil
+}
+
+// checkCapacity reports ErrCapacityExceeded if the PVZ cannot take the
+// products counted by type in added. Callers hold mu.
+func (m *Memory) checkCapacity(pvzID uuid.UUID, added map[int]int) error {
+	pvz, ok := m.pvzs[pvzID]
+	if !ok {
+		return e.ErrNotFound()
+	}
+
+	count, total := 0, 0
+	for _, n := range m.occupancy[pvzID] {
+		count += n
+	}
+	for typeID, n := range added {
+		total += n
+
+		typeCapacity := m.capacities[pvzID][typeID]
+		if typeCapacity > 0 && m.occupancy[pvzID][typeID]+n > typeCapacity {
+			return e.ErrCapacityExceeded()
+		}
+	}
+	if pvz.Capacity > 0 && count+total > pvz.Capacity {
+		return e.ErrCapacityExceeded()
+	}
+
+	return nil
+}
+
+// adjustOccupancy changes the number of products of the type held by the PVZ
+// by delta, never going below zero. Callers hold mu.
+func (m *Memory) adjustOccupancy(pvzID uuid.UUID, typeID int, delta int) {
+	counts, ok := m.occupancy[pvzID]
+	if !ok {
+		counts = make(map[int]int)
+		m.occupancy[pvzID] = counts
+	}
+	counts[typeID] = max(counts[typeID]+delta, 0)
+}
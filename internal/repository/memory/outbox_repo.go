@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"pvz-service/internal/models"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (m *Memory) ClaimOutboxEvents(ctx context.Context, limit int, lease time.Duration) ([]models.Event, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	// Events are appended in creation order, so the first due ones are the
+	// oldest
+	var events []models.Event
+	for _, ev := range m.outbox {
+		if len(events) == limit {
+			break
+		}
+		if ev.publishedAt != nil || ev.deadAt != nil || ev.nextAttemptAt.After(now) {
+			continue
+		}
+
+		// Claimed events are not due again until the lease runs out
+		ev.nextAttemptAt = now.Add(lease)
+
+		event := ev.Event
+		event.Payload = slices.Clone(ev.Payload)
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func (m *Memory) MarkEventPublished(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if ev := m.outboxEvent(id); ev != nil {
+		now := time.Now()
+		ev.Attempts++
+		ev.lastError = ""
+		ev.publishedAt = &now
+	}
+	return nil
+}
+
+func (m *Memory) MarkEventFailed(ctx context.Context, id uuid.UUID, lastErr string, nextAttemptAt time.Time, dead bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if ev := m.outboxEvent(id); ev != nil {
+		ev.Attempts++
+		ev.lastError = lastErr
+		ev.nextAttemptAt = nextAttemptAt
+		if dead {
+			now := time.Now()
+			ev.deadAt = &now
+		}
+	}
+	return nil
+}
+
+func (m *Memory) PurgePublishedEvents(ctx context.Context, before time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	kept := m.outbox[:0]
+	for _, ev := range m.outbox {
+		if ev.publishedAt == nil || !ev.publishedAt.Before(before) {
+			kept = append(kept, ev)
+		}
+	}
+
+	deleted := len(m.outbox) - len(kept)
+	clear(m.outbox[len(kept):])
+	m.outbox = kept
+	return deleted, nil
+}
+
+// outboxEvent returns the outbox event with the given ID, or nil. Callers
+// hold mu.
+func (m *Memory) outboxEvent(id uuid.UUID) *outboxEvent {
+	for _, ev := range m.outbox {
+		if ev.ID == id {
+			return ev
+		}
+	}
+	return nil
+}
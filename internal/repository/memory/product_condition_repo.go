@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// productInOpenReception returns a product that is accepted in a reception
+// still in progress, together with that reception. Callers hold mu.
+func (m *Memory) productInOpenReception(productID uuid.UUID) (*models.Product, *models.Reception, error) {
+	product, ok := m.products[productID]
+	if !ok || product.Status != models.ProductStatusAccepted {
+		return nil, nil, e.ErrProductNotInReception()
+	}
+
+	reception, ok := m.receptions[product.ReceptionID]
+	if !ok || reception.Status != models.ReceptionStatusInProgress {
+		return nil, nil, e.ErrProductNotInReception()
+	}
+
+	return product, reception, nil
+}
+
+func (m *Memory) SetProductCondition(ctx context.Context, productID uuid.UUID, condition models.ProductCondition, note string) (*models.Product, uuid.UUID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, reception, err := m.productInOpenReception(productID)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+
+	stored.Condition = condition
+	stored.ConditionNote = note
+
+	product := m.productView(stored)
+	if err := m.insertEvent(models.EventProductConditionChanged, reception.PVZID, product.ReceptionID, product); err != nil {
+		return nil, uuid.Nil, err
+	}
+
+	return &product, reception.PVZID, nil
+}
+
+// SetProductPhoto attaches a photo to the condition report of a product in
+// an open reception and returns the key of the photo it replaced, if any
+func (m *Memory) SetProductPhoto(ctx context.Context, productID uuid.UUID, photoKey string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	product, _, err := m.productInOpenReception(productID)
+	if err != nil {
+		return "", err
+	}
+
+	previous := product.PhotoKey
+	product.PhotoKey = photoKey
+
+	return previous, nil
+}
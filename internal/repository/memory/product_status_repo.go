@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"context"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// productView returns a copy of a stored product with its type name
+func (m *Memory) productView(product *models.Product) models.Product {
+	view := *product
+	view.TypeName = m.productTypeName(product.TypeID)
+	view.HasPhoto = product.PhotoKey != ""
+	return view
+}
+
+func (m *Memory) GetProduct(ctx context.Context, productID uuid.UUID) (*models.Product, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stored, ok := m.products[productID]
+	if !ok {
+		return nil, e.ErrNotFound()
+	}
+
+	product := m.productView(stored)
+	return &product, nil
+}
+
+func (m *Memory) ChangeProductStatus(ctx context.Context, change *models.ProductStatusChange) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	product, ok := m.products[change.ProductID]
+	if !ok || product.Status != change.From {
+		return e.ErrInvalidStatusTransition()
+	}
+	reception, ok := m.receptions[product.ReceptionID]
+	if !ok {
+		return e.ErrInvalidStatusTransition()
+	}
+
+	product.Status = change.To
+	change.ReceptionID = product.ReceptionID
+	change.PVZID = reception.PVZID
+
+	// Issued and written-off products leave the PVZ, returned ones come back
+	if change.From.InPVZ() != change.To.InPVZ() {
+		delta := 1
+		if !change.To.InPVZ() {
+			delta = -1
+		}
+		m.adjustOccupancy(change.PVZID, product.TypeID, delta)
+	}
+
+	return m.recordStatusChange(change)
+}
+
+func (m *Memory) GetProductHistory(ctx context.Context, productID uuid.UUID) ([]models.ProductStatusChange, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	history := []models.ProductStatusChange{}
+	for _, change := range m.history {
+		if change.ProductID == productID {
+			history = append(history, change)
+		}
+	}
+
+	// The history is kept in insertion order, which breaks ties like the id
+	// column of the postgres table
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].ChangedAt.Before(history[j].ChangedAt)
+	})
+	return history, nil
+}
+
+// recordStatusChange adds the change to the product history and the outbox.
+// Callers hold mu.
+func (m *Memory) recordStatusChange(change *models.ProductStatusChange) error {
+	m.history = append(m.history, *change)
+	return m.insertEvent(models.EventProductStatusChanged, change.PVZID, change.ReceptionID, change)
+}
+
+// storeAcceptedProducts puts the products of a closed reception on the
+// shelves. Callers hold mu.
+func (m *Memory) storeAcceptedProducts(receptionID, pvzID uuid.UUID) {
+	now := time.Now()
+	for _, product := range m.products {
+		if product.ReceptionID != receptionID || product.Status != models.ProductStatusAccepted {
+			continue
+		}
+
+		product.Status = models.ProductStatusStored
+		m.history = append(m.history, models.ProductStatusChange{
+			ProductID:   product.ID,
+			PVZID:       pvzID,
+			ReceptionID: receptionID,
+			From:        models.ProductStatusAccepted,
+			To:          models.ProductStatusStored,
+			ChangedAt:   now,
+		})
+	}
+}
@@ -0,0 +1,540 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (m *Memory) InsertPVZ(ctx context.Context, pvz *models.PVZ) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.pvzs[pvz.ID]; ok {
+		return fmt.Errorf("pvz %s already exists", pvz.ID)
+	}
+
+	m.pvzs[pvz.ID] = &models.PVZ{
+		ID:               pvz.ID,
+		RegistrationDate: pvz.RegistrationDate,
+		CityID:           pvz.CityID,
+		Address:          pvz.Address,
+		Location:         copyLocation(pvz.Location),
+		Capacity:         pvz.Capacity,
+		Status:           models.PVZStatusActive,
+		Version:          1,
+	}
+	return nil
+}
+
+// pvzView returns a copy of a stored PVZ as the postgres listing queries
+// read it, i.e. with the city name and without the version
+func (m *Memory) pvzView(pvz *models.PVZ) models.PVZ {
+	view := *pvz
+	view.CityName = m.cityName(pvz.CityID)
+	view.Location = copyLocation(pvz.Location)
+	view.Version = 0
+	if pvz.DeletedAt != nil {
+		deletedAt := *pvz.DeletedAt
+		view.DeletedAt = &deletedAt
+	}
+	return view
+}
+
+func copyLocation(location *models.GeoPoint) *models.GeoPoint {
+	if location == nil {
+		return nil
+	}
+	point := *location
+	return &point
+}
+
+// activePVZ returns the PVZ unless it is unknown or soft-deleted
+func (m *Memory) activePVZ(pvzID uuid.UUID) (*models.PVZ, bool) {
+	pvz, ok := m.pvzs[pvzID]
+	if !ok || pvz.DeletedAt != nil {
+		return nil, false
+	}
+	return pvz, true
+}
+
+func (m *Memory) GetCityID(ctx context.Context, city string) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for i, name := range m.cities {
+		if name == city {
+			return i + 1, nil
+		}
+	}
+	return 0, e.ErrCityNotAllowed()
+}
+
+func (m *Memory) CheckPVZ(ctx context.Context, pvzID uuid.UUID) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.activePVZ(pvzID); !ok {
+		return false, e.ErrNotFound()
+	}
+	return true, nil
+}
+
+func (m *Memory) GetPVZ(ctx context.Context, pvzID uuid.UUID) (*models.PVZ, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stored, ok := m.activePVZ(pvzID)
+	if !ok {
+		return nil, e.ErrNotFound()
+	}
+
+	pvz := m.pvzView(stored)
+	pvz.Version = stored.Version
+	return &pvz, nil
+}
+
+func (m *Memory) UpdatePVZ(ctx context.Context, pvz *models.PVZ, version int) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.activePVZ(pvz.ID)
+	if !ok || stored.Version != version {
+		return 0, e.ErrVersionMismatch()
+	}
+
+	stored.CityID = pvz.CityID
+	stored.Address = pvz.Address
+	stored.Location = copyLocation(pvz.Location)
+	stored.Capacity = pvz.Capacity
+	stored.Status = pvz.Status
+	stored.Version++
+
+	return stored.Version, nil
+}
+
+func (m *Memory) DeletePVZ(ctx context.Context, pvzID uuid.UUID, version int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.activePVZ(pvzID)
+	if !ok || stored.Version != version {
+		return e.ErrVersionMismatch()
+	}
+
+	now := time.Now()
+	stored.Status = models.PVZStatusClosed
+	stored.DeletedAt = &now
+	stored.Version++
+
+	return nil
+}
+
+func (m *Memory) GetNearbyPVZs(ctx context.Context, point models.GeoPoint, radius float64, limit int) ([]models.NearbyPVZ, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []models.NearbyPVZ
+	for _, pvz := range m.pvzs {
+		if pvz.DeletedAt != nil || pvz.Status != models.PVZStatusActive || pvz.Location == nil {
+			continue
+		}
+
+		distance := point.Distance(*pvz.Location)
+		if distance <= radius {
+			result = append(result, models.NearbyPVZ{PVZ: m.pvzView(pvz), Distance: distance})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Distance < result[j].Distance
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (m *Memory) GetActiveReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var active *models.Reception
+	for _, reception := range m.receptions {
+		if reception.PVZID != pvzID || reception.Status != models.ReceptionStatusInProgress {
+			continue
+		}
+		if active == nil || reception.DateTime.After(active.DateTime) {
+			active = reception
+		}
+	}
+	if active == nil {
+		return nil, e.ErrNoActiveReception()
+	}
+
+	reception := *active
+	return &reception, nil
+}
+
+func (m *Memory) InsertReception(ctx context.Context, reception *models.Reception) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.receptions[reception.ID]; ok {
+		return fmt.Errorf("reception %s already exists", reception.ID)
+	}
+
+	var manifest *models.Manifest
+	if reception.ManifestID != nil {
+		manifest = m.manifests[*reception.ManifestID]
+		if manifest == nil || manifest.PVZID != reception.PVZID || manifest.Status != models.ManifestStatusPending {
+			return e.ErrManifestNotAvailable()
+		}
+	}
+
+	stored := &models.Reception{
+		ID:       reception.ID,
+		DateTime: reception.DateTime,
+		PVZID:    reception.PVZID,
+		Status:   reception.Status,
+		Version:  1,
+	}
+	m.receptions[stored.ID] = stored
+
+	if manifest != nil {
+		receptionID := stored.ID
+		manifest.Status = models.ManifestStatusReceiving
+		manifest.ReceptionID = &receptionID
+		stored.ManifestID = &manifest.ID
+	}
+
+	return m.insertEvent(models.EventReceptionOpened, reception.PVZID, reception.ID, reception)
+}
+
+func (m *Memory) GetProductTypeID(ctx context.Context, productTypeName string) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for i, name := range m.productTypes {
+		if name == productTypeName {
+			return i + 1, nil
+		}
+	}
+	return 0, e.ErrProductTypeNotAllowed()
+}
+
+func (m *Memory) InsertProduct(ctx context.Context, product *models.Product, receptionVersion int) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	reception, err := m.receptionWithVersion(product.ReceptionID, receptionVersion)
+	if err != nil {
+		return 0, err
+	}
+	if err := m.checkCapacity(reception.PVZID, map[int]int{product.TypeID: 1}); err != nil {
+		return 0, err
+	}
+
+	reception.Version++
+	m.adjustOccupancy(reception.PVZID, product.TypeID, 1)
+
+	condition := product.Condition
+	if condition == "" {
+		condition = models.ProductConditionOK
+	}
+	m.products[product.ID] = &models.Product{
+		ID:          product.ID,
+		DateTime:    product.DateTime,
+		TypeID:      product.TypeID,
+		ReceptionID: product.ReceptionID,
+		Status:      product.Status,
+		Barcode:     product.Barcode,
+		Condition:   condition,
+	}
+
+	m.history = append(m.history, models.ProductStatusChange{
+		ProductID:   product.ID,
+		PVZID:       reception.PVZID,
+		ReceptionID: product.ReceptionID,
+		To:          product.Status,
+		ChangedAt:   product.DateTime,
+	})
+
+	if err := m.insertEvent(models.EventProductAdded, reception.PVZID, product.ReceptionID, product); err != nil {
+		return 0, err
+	}
+	return reception.Version, nil
+}
+
+func (m *Memory) GetLastProduct(ctx context.Context, receptionID uuid.UUID, since time.Time) (*models.Product, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var last *models.Product
+	for _, product := range m.products {
+		if product.ReceptionID != receptionID || product.DateTime.Before(since) || m.transferred(product.ID) {
+			continue
+		}
+		if last == nil || product.DateTime.After(last.DateTime) {
+			last = product
+		}
+	}
+	if last == nil {
+		return nil, e.ErrNotFound()
+	}
+
+	return &models.Product{
+		ID:          last.ID,
+		DateTime:    last.DateTime,
+		TypeID:      last.TypeID,
+		ReceptionID: last.ReceptionID,
+		Status:      last.Status,
+	}, nil
+}
+
+func (m *Memory) DeleteProduct(ctx context.Context, productID uuid.UUID, receptionVersion int) (int, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.products[productID]
+	if !ok || m.transferred(productID) {
+		return 0, "", e.ErrNotFound()
+	}
+
+	reception, err := m.receptionWithVersion(stored.ReceptionID, receptionVersion)
+	if err != nil {
+		return 0, "", err
+	}
+
+	product := models.Product{
+		ID:          stored.ID,
+		DateTime:    stored.DateTime,
+		TypeID:      stored.TypeID,
+		TypeName:    m.productTypeName(stored.TypeID),
+		ReceptionID: stored.ReceptionID,
+		Status:      stored.Status,
+	}
+
+	delete(m.products, productID)
+	m.history = slices.DeleteFunc(m.history, func(change models.ProductStatusChange) bool {
+		return change.ProductID == productID
+	})
+	reception.Version++
+	m.adjustOccupancy(reception.PVZID, product.TypeID, -1)
+
+	if err := m.insertEvent(models.EventProductDeleted, reception.PVZID, product.ReceptionID, product); err != nil {
+		return 0, "", err
+	}
+	return reception.Version, stored.PhotoKey, nil
+}
+
+func (m *Memory) UpdateReceptionStatus(ctx context.Context, receptionID uuid.UUID, status models.ReceptionStatus, version int) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	reception, err := m.setReceptionStatus(receptionID, status, version)
+	if err != nil {
+		return 0, err
+	}
+	return reception.Version, nil
+}
+
+// setReceptionStatus moves a reception that still has the expected version
+// to status. Closing a reception also stores its products and reconciles it
+// with the manifest. Callers hold mu.
+func (m *Memory) setReceptionStatus(receptionID uuid.UUID, status models.ReceptionStatus, version int) (*models.Reception, error) {
+	stored, err := m.receptionWithVersion(receptionID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	stored.Status = status
+	stored.Version++
+
+	reception := models.Reception{
+		ID:       stored.ID,
+		DateTime: stored.DateTime,
+		PVZID:    stored.PVZID,
+		Status:   stored.Status,
+		Version:  stored.Version,
+	}
+
+	eventType := models.EventReceptionOpened
+	if status == models.ReceptionStatusClose {
+		eventType = models.EventReceptionClosed
+
+		m.storeAcceptedProducts(reception.ID, reception.PVZID)
+		reception.Discrepancies = m.reconcileManifest(reception.ID, time.Now())
+	}
+
+	if err := m.insertEvent(eventType, reception.PVZID, reception.ID, reception); err != nil {
+		return nil, err
+	}
+	return &reception, nil
+}
+
+// receptionWithVersion returns the stored reception if it still has the
+// expected version. Callers hold mu.
+func (m *Memory) receptionWithVersion(receptionID uuid.UUID, version int) (*models.Reception, error) {
+	reception, ok := m.receptions[receptionID]
+	if !ok || reception.Version != version {
+		return nil, e.ErrVersionMismatch()
+	}
+	return reception, nil
+}
+
+func (m *Memory) GetPVZs(ctx context.Context, from, to time.Time, limit, offset int) ([]models.PVZ, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	received := make(map[uuid.UUID]bool)
+	for _, reception := range m.receptions {
+		if !reception.DateTime.Before(from) && !reception.DateTime.After(to) {
+			received[reception.PVZID] = true
+		}
+	}
+
+	var pvzs []models.PVZ
+	for _, pvz := range m.sortedPVZs() {
+		if received[pvz.ID] {
+			pvzs = append(pvzs, m.pvzView(pvz))
+		}
+	}
+	return page(pvzs, limit, offset), nil
+}
+
+func (m *Memory) GetPVZsWithNoFilter(ctx context.Context) ([]models.PVZ, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var pvzs []models.PVZ
+	for _, pvz := range m.sortedPVZs() {
+		if pvz.DeletedAt == nil {
+			pvzs = append(pvzs, m.pvzView(pvz))
+		}
+	}
+	return pvzs, nil
+}
+
+// sortedPVZs returns the stored PVZs, newest first. Callers hold mu.
+func (m *Memory) sortedPVZs() []*models.PVZ {
+	pvzs := make([]*models.PVZ, 0, len(m.pvzs))
+	for _, pvz := range m.pvzs {
+		pvzs = append(pvzs, pvz)
+	}
+	sort.Slice(pvzs, func(i, j int) bool {
+		return pvzs[i].RegistrationDate.After(pvzs[j].RegistrationDate)
+	})
+	return pvzs
+}
+
+// page applies LIMIT and OFFSET to items
+func page[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+	items = items[offset:]
+	if limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+func (m *Memory) GetReceptionsForPVZs(ctx context.Context, pvzIDs []uuid.UUID, from, to time.Time) ([]models.Reception, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var receptions []models.Reception
+	for _, reception := range m.receptions {
+		if !slices.Contains(pvzIDs, reception.PVZID) || reception.DateTime.Before(from) || reception.DateTime.After(to) {
+			continue
+		}
+		receptions = append(receptions, models.Reception{
+			ID:       reception.ID,
+			DateTime: reception.DateTime,
+			PVZID:    reception.PVZID,
+			Status:   reception.Status,
+		})
+	}
+
+	sort.Slice(receptions, func(i, j int) bool {
+		return receptions[i].DateTime.After(receptions[j].DateTime)
+	})
+	return receptions, nil
+}
+
+func (m *Memory) GetProductsForReceptions(ctx context.Context, receptionIDs []uuid.UUID, since time.Time) ([]models.Product, error) {
+	if len(receptionIDs) == 0 {
+		return []models.Product{}, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var products []models.Product
+	for _, product := range m.products {
+		if slices.Contains(receptionIDs, product.ReceptionID) && !product.DateTime.Before(since) {
+			products = append(products, m.productView(product))
+		}
+	}
+
+	sort.Slice(products, func(i, j int) bool {
+		return products[i].DateTime.After(products[j].DateTime)
+	})
+	return products, nil
+}
+
+func (m *Memory) IteratePVZs(ctx context.Context, filter models.PVZFilter, fn func(models.PVZ) error) error {
+	// The PVZs are copied first so that fn may call back into the repository
+	m.mu.RLock()
+	var pvzs []models.PVZ
+	for _, pvz := range m.sortedPVZs() {
+		if len(filter.Cities) > 0 && !slices.Contains(filter.Cities, m.cityName(pvz.CityID)) {
+			continue
+		}
+		dated := !filter.From.IsZero() || !filter.To.IsZero()
+		if dated && !m.hasReceptionBetween(pvz.ID, filter.From, filter.To) {
+			continue
+		}
+		// Deleted PVZs stay only in the listings of receptions by date
+		if !dated && pvz.DeletedAt != nil {
+			continue
+		}
+		pvzs = append(pvzs, m.pvzView(pvz))
+	}
+	m.mu.RUnlock()
+
+	for _, pvz := range pvzs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(pvz); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasReceptionBetween reports whether the PVZ has a reception inside the
+// range. A zero bound leaves that side open. Callers hold mu.
+func (m *Memory) hasReceptionBetween(pvzID uuid.UUID, from, to time.Time) bool {
+	for _, reception := range m.receptions {
+		if reception.PVZID != pvzID {
+			continue
+		}
+		if !from.IsZero() && reception.DateTime.Before(from) {
+			continue
+		}
+		if !to.IsZero() && reception.DateTime.After(to) {
+			continue
+		}
+		return true
+	}
+	return false
+}
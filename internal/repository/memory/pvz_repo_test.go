@@ -0,0 +1,200 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func insertTestPVZ(t *testing.T, repo *Memory, capacity int) uuid.UUID {
+	t.Helper()
+
+	pvz := &models.PVZ{ID: uuid.New(), RegistrationDate: time.Now(), CityID: 1, Capacity: capacity}
+	require.NoError(t, repo.InsertPVZ(context.Background(), pvz))
+	return pvz.ID
+}
+
+func openTestReception(t *testing.T, repo *Memory, pvzID uuid.UUID, at time.Time) uuid.UUID {
+	t.Helper()
+
+	reception := &models.Reception{ID: uuid.New(), DateTime: at, PVZID: pvzID, Status: models.ReceptionStatusInProgress}
+	require.NoError(t, repo.InsertReception(context.Background(), reception))
+	return reception.ID
+}
+
+func TestGetCityID(t *testing.T) {
+	repo := New()
+
+	cityID, err := repo.GetCityID(context.Background(), "Казань")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, cityID)
+
+	_, err = repo.GetCityID(context.Background(), "Новосибирск")
+	assert.Equal(t, e.ErrCityNotAllowed(), err)
+}
+
+func TestReceptionFlow(t *testing.T) {
+	repo := New()
+	ctx := context.Background()
+	pvzID := insertTestPVZ(t, repo, 0)
+
+	_, err := repo.GetActiveReception(ctx, pvzID)
+	assert.Equal(t, e.ErrNoActiveReception(), err)
+
+	receptionID := openTestReception(t, repo, pvzID, time.Now())
+
+	active, err := repo.GetActiveReception(ctx, pvzID)
+	require.NoError(t, err)
+	assert.Equal(t, receptionID, active.ID)
+	assert.Equal(t, 1, active.Version)
+
+	product := &models.Product{ID: uuid.New(), DateTime: time.Now(), TypeID: 1, ReceptionID: receptionID, Status: models.ProductStatusAccepted}
+	version, err := repo.InsertProduct(ctx, product, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, version)
+
+	// A stale version is rejected without adding the product
+	_, err = repo.InsertProduct(ctx, &models.Product{ID: uuid.New(), TypeID: 1, ReceptionID: receptionID}, 1)
+	assert.Equal(t, e.ErrVersionMismatch(), err)
+
+	last, err := repo.GetLastProduct(ctx, receptionID, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, product.ID, last.ID)
+
+	version, err = repo.UpdateReceptionStatus(ctx, receptionID, models.ReceptionStatusClose, version)
+	require.NoError(t, err)
+	assert.Equal(t, 3, version)
+
+	_, err = repo.GetActiveReception(ctx, pvzID)
+	assert.Equal(t, e.ErrNoActiveReception(), err)
+
+	stored, err := repo.GetProduct(ctx, product.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ProductStatusStored, stored.Status)
+
+	history, err := repo.GetProductHistory(ctx, product.ID)
+	require.NoError(t, err)
+	assert.Len(t, history, 2)
+}
+
+func TestDeleteProduct(t *testing.T) {
+	repo := New()
+	ctx := context.Background()
+	pvzID := insertTestPVZ(t, repo, 0)
+	receptionID := openTestReception(t, repo, pvzID, time.Now())
+
+	_, _, err := repo.DeleteProduct(ctx, uuid.New(), 1)
+	assert.Equal(t, e.ErrNotFound(), err)
+
+	product := &models.Product{ID: uuid.New(), DateTime: time.Now(), TypeID: 2, ReceptionID: receptionID, Status: models.ProductStatusAccepted}
+	version, err := repo.InsertProduct(ctx, product, 1)
+	require.NoError(t, err)
+
+	version, _, err = repo.DeleteProduct(ctx, product.ID, version)
+	require.NoError(t, err)
+	assert.Equal(t, 3, version)
+
+	_, err = repo.GetLastProduct(ctx, receptionID, time.Time{})
+	assert.Equal(t, e.ErrNotFound(), err)
+
+	occupancy, err := repo.GetPVZOccupancy(ctx, pvzID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, occupancy.Count)
+}
+
+func TestInsertProduct_CapacityExceeded(t *testing.T) {
+	repo := New()
+	ctx := context.Background()
+	pvzID := insertTestPVZ(t, repo, 1)
+	receptionID := openTestReception(t, repo, pvzID, time.Now())
+
+	version, err := repo.InsertProduct(ctx, &models.Product{ID: uuid.New(), TypeID: 1, ReceptionID: receptionID}, 1)
+	require.NoError(t, err)
+
+	_, err = repo.InsertProduct(ctx, &models.Product{ID: uuid.New(), TypeID: 1, ReceptionID: receptionID}, version)
+	assert.Equal(t, e.ErrCapacityExceeded(), err)
+
+	// The failed insert leaves the reception version as it was
+	active, err := repo.GetActiveReception(ctx, pvzID)
+	require.NoError(t, err)
+	assert.Equal(t, version, active.Version)
+}
+
+func TestGetPVZs(t *testing.T) {
+	repo := New()
+	ctx := context.Background()
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	older := &models.PVZ{ID: uuid.New(), RegistrationDate: base, CityID: 1}
+	newer := &models.PVZ{ID: uuid.New(), RegistrationDate: base.Add(time.Hour), CityID: 2}
+	idle := &models.PVZ{ID: uuid.New(), RegistrationDate: base.Add(2 * time.Hour), CityID: 3}
+	for _, pvz := range []*models.PVZ{older, newer, idle} {
+		require.NoError(t, repo.InsertPVZ(ctx, pvz))
+	}
+	openTestReception(t, repo, older.ID, base.Add(24*time.Hour))
+	openTestReception(t, repo, newer.ID, base.Add(48*time.Hour))
+
+	// Only PVZs with a reception in the range are listed, newest first
+	pvzs, err := repo.GetPVZs(ctx, base, base.Add(72*time.Hour), 10, 0)
+	require.NoError(t, err)
+	require.Len(t, pvzs, 2)
+	assert.Equal(t, newer.ID, pvzs[0].ID)
+	assert.Equal(t, "Санкт-Петербург", pvzs[0].CityName)
+	assert.Equal(t, older.ID, pvzs[1].ID)
+
+	pvzs, err = repo.GetPVZs(ctx, base, base.Add(72*time.Hour), 1, 1)
+	require.NoError(t, err)
+	require.Len(t, pvzs, 1)
+	assert.Equal(t, older.ID, pvzs[0].ID)
+
+	// Both bounds are inclusive
+	pvzs, err = repo.GetPVZs(ctx, base.Add(24*time.Hour), base.Add(24*time.Hour), 10, 0)
+	require.NoError(t, err)
+	require.Len(t, pvzs, 1)
+	assert.Equal(t, older.ID, pvzs[0].ID)
+
+	all, err := repo.GetPVZsWithNoFilter(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+}
+
+func TestInsertReception_Manifest(t *testing.T) {
+	repo := New()
+	ctx := context.Background()
+	pvzID := insertTestPVZ(t, repo, 0)
+
+	manifest := &models.Manifest{
+		ID:     uuid.New(),
+		PVZID:  pvzID,
+		Status: models.ManifestStatusPending,
+		Items:  []models.ManifestItem{{Barcode: "B2", TypeID: 2}, {Barcode: "A1", TypeID: 1}},
+	}
+	require.NoError(t, repo.CreateManifest(ctx, manifest))
+
+	reception := &models.Reception{ID: uuid.New(), DateTime: time.Now(), PVZID: pvzID, Status: models.ReceptionStatusInProgress, ManifestID: &manifest.ID}
+	require.NoError(t, repo.InsertReception(ctx, reception))
+
+	other := &models.Reception{ID: uuid.New(), DateTime: time.Now(), PVZID: pvzID, Status: models.ReceptionStatusInProgress, ManifestID: &manifest.ID}
+	assert.Equal(t, e.ErrManifestNotAvailable(), repo.InsertReception(ctx, other))
+
+	version, err := repo.InsertProduct(ctx, &models.Product{ID: uuid.New(), TypeID: 1, ReceptionID: reception.ID, Barcode: "A1", Status: models.ProductStatusAccepted}, 1)
+	require.NoError(t, err)
+	_, err = repo.UpdateReceptionStatus(ctx, reception.ID, models.ReceptionStatusClose, version)
+	require.NoError(t, err)
+
+	reconciled, err := repo.GetManifest(ctx, manifest.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ManifestStatusReconciled, reconciled.Status)
+	assert.Equal(t, "A1", reconciled.Items[0].Barcode)
+	if assert.NotNil(t, reconciled.Report) {
+		assert.Equal(t, 2, reconciled.Report.Expected)
+		assert.Equal(t, "B2", reconciled.Report.Missing[0].Barcode)
+	}
+}
@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"pvz-service/internal/models"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (m *Memory) InsertReceptionAudit(ctx context.Context, entry *models.ReceptionAuditEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.audit = append(m.audit, *entry)
+	return nil
+}
+
+func (m *Memory) ForceCloseReception(ctx context.Context, receptionID uuid.UUID, version int, entry *models.ReceptionAuditEntry) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	reception, err := m.setReceptionStatus(receptionID, models.ReceptionStatusClose, version)
+	if err != nil {
+		return 0, err
+	}
+
+	entry.ReceptionID = reception.ID
+	entry.PVZID = reception.PVZID
+	m.audit = append(m.audit, *entry)
+
+	return reception.Version, nil
+}
+
+func (m *Memory) GetStaleReceptions(ctx context.Context, idleSince time.Time) ([]models.StaleReception, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	lastActivity := make(map[uuid.UUID]time.Time)
+	for _, reception := range m.receptions {
+		if reception.Status == models.ReceptionStatusInProgress {
+			lastActivity[reception.ID] = reception.DateTime
+		}
+	}
+	for _, product := range m.products {
+		if last, ok := lastActivity[product.ReceptionID]; ok && product.DateTime.After(last) {
+			lastActivity[product.ReceptionID] = product.DateTime
+		}
+	}
+
+	stale := []models.StaleReception{}
+	for receptionID, last := range lastActivity {
+		if !last.Before(idleSince) {
+			continue
+		}
+
+		reception := m.receptions[receptionID]
+		entry := models.StaleReception{
+			Reception: models.Reception{
+				ID:       reception.ID,
+				DateTime: reception.DateTime,
+				PVZID:    reception.PVZID,
+				Status:   reception.Status,
+				Version:  reception.Version,
+			},
+			LastActivity: last,
+		}
+		for _, manifest := range m.manifests {
+			if manifest.ReceptionID != nil && *manifest.ReceptionID == receptionID {
+				id := manifest.ID
+				entry.ManifestID = &id
+				break
+			}
+		}
+		for _, audit := range m.audit {
+			if audit.ReceptionID == receptionID && audit.Action == models.ReceptionAuditFlaggedStale {
+				entry.Flagged = true
+				break
+			}
+		}
+		stale = append(stale, entry)
+	}
+
+	sort.Slice(stale, func(i, j int) bool {
+		return stale[i].LastActivity.Before(stale[j].LastActivity)
+	})
+	return stale, nil
+}
+
+func (m *Memory) GetPVZReceptionAudit(ctx context.Context, pvzID uuid.UUID) ([]models.ReceptionAuditEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Newest entries first, the ones written later win a tie
+	entries := []models.ReceptionAuditEntry{}
+	for i := len(m.audit) - 1; i >= 0; i-- {
+		if m.audit[i].PVZID == pvzID {
+			entries = append(entries, m.audit[i])
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.After(entries[j].CreatedAt)
+	})
+	return entries, nil
+}
@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// pvzSchedule keeps the schedule parts as JSON, like the postgres table does,
+// so callers never share slices with the store
+type pvzSchedule struct {
+	timezone               string
+	weekly                 []byte
+	exceptions             []byte
+	receptionsAllowedUntil *time.Time
+	updatedAt              time.Time
+}
+
+func (m *Memory) GetPVZSchedule(ctx context.Context, pvzID uuid.UUID) (*models.PVZSchedule, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stored, ok := m.schedules[pvzID]
+	if !ok {
+		return nil, e.ErrNotFound()
+	}
+
+	schedule := models.PVZSchedule{PVZID: pvzID, Timezone: stored.timezone, UpdatedAt: stored.updatedAt}
+	if stored.receptionsAllowedUntil != nil {
+		until := *stored.receptionsAllowedUntil
+		schedule.ReceptionsAllowedUntil = &until
+	}
+	if err := json.Unmarshal(stored.weekly, &schedule.Weekly); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(stored.exceptions, &schedule.Exceptions); err != nil {
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+func (m *Memory) SavePVZSchedule(ctx context.Context, schedule *models.PVZSchedule) error {
+	weekly, err := json.Marshal(nonNil(schedule.Weekly))
+	if err != nil {
+		return err
+	}
+	exceptions, err := json.Marshal(nonNil(schedule.Exceptions))
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Replacing the working hours keeps the moderator's reception override
+	stored := &pvzSchedule{
+		timezone:   schedule.Timezone,
+		weekly:     weekly,
+		exceptions: exceptions,
+		updatedAt:  schedule.UpdatedAt,
+	}
+	if previous, ok := m.schedules[schedule.PVZID]; ok {
+		stored.receptionsAllowedUntil = previous.receptionsAllowedUntil
+	}
+	m.schedules[schedule.PVZID] = stored
+
+	return nil
+}
+
+func (m *Memory) AllowReceptionsUntil(ctx context.Context, pvzID uuid.UUID, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.schedules[pvzID]
+	if !ok {
+		return e.ErrNotFound()
+	}
+	stored.receptionsAllowedUntil = &until
+
+	return nil
+}
+
+func (m *Memory) DeletePVZSchedule(ctx context.Context, pvzID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.schedules[pvzID]; !ok {
+		return e.ErrNotFound()
+	}
+	delete(m.schedules, pvzID)
+
+	return nil
+}
+
+// nonNil keeps empty schedule parts stored as JSON arrays rather than null
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
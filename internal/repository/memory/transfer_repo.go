@@ -0,0 +1,231 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (m *Memory) CreateTransfer(ctx context.Context, transfer *models.Transfer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.transfers[transfer.ID]; ok {
+		return fmt.Errorf("transfer %s already exists", transfer.ID)
+	}
+
+	// Only products stored in the source PVZ and not assembled into another
+	// transfer can be moved
+	found := make(map[uuid.UUID]bool, len(transfer.ProductIDs))
+	for _, productID := range transfer.ProductIDs {
+		product, ok := m.products[productID]
+		if !ok || product.Status != models.ProductStatusStored || m.inOutboundTransfer(productID) {
+			continue
+		}
+		if reception, ok := m.receptions[product.ReceptionID]; ok && reception.PVZID == transfer.SourcePVZID {
+			found[productID] = true
+		}
+	}
+	if len(found) != len(transfer.ProductIDs) {
+		return e.ErrProductNotTransferable()
+	}
+
+	stored := *transfer
+	stored.ReceptionID = nil
+	stored.DispatchedAt = nil
+	stored.ReceivedAt = nil
+	stored.ProductIDs = slices.Clone(transfer.ProductIDs)
+	m.transfers[stored.ID] = &stored
+
+	return nil
+}
+
+// inOutboundTransfer reports whether the product is assembled into a
+// transfer that has not been dispatched yet. Callers hold mu.
+func (m *Memory) inOutboundTransfer(productID uuid.UUID) bool {
+	for _, transfer := range m.transfers {
+		if transfer.Status == models.TransferStatusOutbound && slices.Contains(transfer.ProductIDs, productID) {
+			return true
+		}
+	}
+	return false
+}
+
+// transferred reports whether the product is part of any transfer. Callers
+// hold mu.
+func (m *Memory) transferred(productID uuid.UUID) bool {
+	for _, transfer := range m.transfers {
+		if slices.Contains(transfer.ProductIDs, productID) {
+			return true
+		}
+	}
+	return false
+}
+
+func copyTransfer(transfer *models.Transfer) models.Transfer {
+	copied := *transfer
+	copied.ProductIDs = append([]uuid.UUID{}, transfer.ProductIDs...)
+	return copied
+}
+
+func (m *Memory) GetTransfer(ctx context.Context, transferID uuid.UUID) (*models.Transfer, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stored, ok := m.transfers[transferID]
+	if !ok {
+		return nil, e.ErrNotFound()
+	}
+
+	transfer := copyTransfer(stored)
+	return &transfer, nil
+}
+
+func (m *Memory) GetPVZTransfers(ctx context.Context, pvzID uuid.UUID, status models.TransferStatus) ([]models.Transfer, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	transfers := []models.Transfer{}
+	for _, transfer := range m.transfers {
+		if transfer.SourcePVZID != pvzID && transfer.DestinationPVZID != pvzID {
+			continue
+		}
+		if status != "" && transfer.Status != status {
+			continue
+		}
+		transfers = append(transfers, copyTransfer(transfer))
+	}
+
+	sort.Slice(transfers, func(i, j int) bool {
+		return transfers[i].CreatedAt.After(transfers[j].CreatedAt)
+	})
+	return transfers, nil
+}
+
+func (m *Memory) DispatchTransfer(ctx context.Context, transferID uuid.UUID, dispatchedAt time.Time) ([]models.ProductStatusChange, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	transfer, ok := m.transfers[transferID]
+	if !ok || transfer.Status != models.TransferStatusOutbound {
+		return nil, e.ErrInvalidTransferState()
+	}
+
+	// Some product was issued or written off after the transfer was assembled
+	products := m.transferProducts(transfer, models.ProductStatusStored)
+	if len(products) != len(transfer.ProductIDs) {
+		return nil, e.ErrProductNotTransferable()
+	}
+
+	transfer.Status = models.TransferStatusInTransit
+	transfer.DispatchedAt = &dispatchedAt
+
+	changes := make([]models.ProductStatusChange, 0, len(products))
+	for _, product := range products {
+		product.Status = models.ProductStatusInTransit
+		m.adjustOccupancy(transfer.SourcePVZID, product.TypeID, -1)
+
+		change := models.ProductStatusChange{
+			ProductID:   product.ID,
+			PVZID:       transfer.SourcePVZID,
+			ReceptionID: product.ReceptionID,
+			From:        models.ProductStatusStored,
+			To:          models.ProductStatusInTransit,
+			ChangedAt:   dispatchedAt,
+		}
+		if err := m.recordStatusChange(&change); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+func (m *Memory) ReceiveTransfer(ctx context.Context, transferID, receptionID uuid.UUID, receptionVersion int, receivedAt time.Time) (int, []models.ProductStatusChange, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	transfer, ok := m.transfers[transferID]
+	if !ok || transfer.Status != models.TransferStatusInTransit {
+		return 0, nil, e.ErrInvalidTransferState()
+	}
+
+	reception, err := m.receptionWithVersion(receptionID, receptionVersion)
+	if err != nil {
+		return 0, nil, err
+	}
+	if reception.PVZID != transfer.DestinationPVZID {
+		return 0, nil, e.ErrNoActiveReception()
+	}
+
+	// Products written off as lost on the way are not received
+	arrived := m.transferProducts(transfer, models.ProductStatusInTransit)
+
+	added := make(map[int]int)
+	for _, product := range arrived {
+		added[product.TypeID]++
+	}
+	if err := m.checkCapacity(transfer.DestinationPVZID, added); err != nil {
+		return 0, nil, err
+	}
+
+	transfer.Status = models.TransferStatusReceived
+	transfer.ReceivedAt = &receivedAt
+	transfer.ReceptionID = &receptionID
+	reception.Version++
+
+	changes := make([]models.ProductStatusChange, 0, len(arrived))
+	for _, product := range arrived {
+		m.adjustOccupancy(transfer.DestinationPVZID, product.TypeID, 1)
+		product.Status = models.ProductStatusAccepted
+		product.ReceptionID = receptionID
+		product.DateTime = receivedAt
+
+		change := models.ProductStatusChange{
+			ProductID:   product.ID,
+			PVZID:       transfer.DestinationPVZID,
+			ReceptionID: receptionID,
+			From:        models.ProductStatusInTransit,
+			To:          models.ProductStatusAccepted,
+			ChangedAt:   receivedAt,
+		}
+		if err := m.recordStatusChange(&change); err != nil {
+			return 0, nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	return reception.Version, changes, nil
+}
+
+func (m *Memory) CancelTransfer(ctx context.Context, transferID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	transfer, ok := m.transfers[transferID]
+	if !ok || transfer.Status != models.TransferStatusOutbound {
+		return e.ErrInvalidTransferState()
+	}
+	transfer.Status = models.TransferStatusCancelled
+
+	return nil
+}
+
+// transferProducts returns the stored products of the transfer that are in
+// status. Callers hold mu.
+func (m *Memory) transferProducts(transfer *models.Transfer, status models.ProductStatus) []*models.Product {
+	var products []*models.Product
+	for _, productID := range transfer.ProductIDs {
+		if product, ok := m.products[productID]; ok && product.Status == status {
+			products = append(products, product)
+		}
+	}
+	return products
+}
@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferFlow(t *testing.T) {
+	repo := New()
+	ctx := context.Background()
+	sourceID := insertTestPVZ(t, repo, 0)
+	destinationID := insertTestPVZ(t, repo, 0)
+
+	receptionID := openTestReception(t, repo, sourceID, time.Now())
+	product := &models.Product{ID: uuid.New(), TypeID: 1, ReceptionID: receptionID, Status: models.ProductStatusAccepted}
+	version, err := repo.InsertProduct(ctx, product, 1)
+	require.NoError(t, err)
+
+	transfer := &models.Transfer{
+		ID:               uuid.New(),
+		SourcePVZID:      sourceID,
+		DestinationPVZID: destinationID,
+		Status:           models.TransferStatusOutbound,
+		ProductIDs:       []uuid.UUID{product.ID},
+		CreatedAt:        time.Now(),
+	}
+
+	// Products are transferable only once they are stored
+	assert.Equal(t, e.ErrProductNotTransferable(), repo.CreateTransfer(ctx, transfer))
+
+	_, err = repo.UpdateReceptionStatus(ctx, receptionID, models.ReceptionStatusClose, version)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateTransfer(ctx, transfer))
+
+	// The product is already part of an outbound transfer
+	again := *transfer
+	again.ID = uuid.New()
+	assert.Equal(t, e.ErrProductNotTransferable(), repo.CreateTransfer(ctx, &again))
+
+	changes, err := repo.DispatchTransfer(ctx, transfer.ID, time.Now())
+	require.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, e.ErrInvalidTransferState(), repo.CancelTransfer(ctx, transfer.ID))
+
+	// Receiving needs a reception in the destination PVZ
+	otherReceptionID := openTestReception(t, repo, sourceID, time.Now())
+	_, _, err = repo.ReceiveTransfer(ctx, transfer.ID, otherReceptionID, 1, time.Now())
+	assert.Equal(t, e.ErrNoActiveReception(), err)
+
+	destinationReceptionID := openTestReception(t, repo, destinationID, time.Now())
+	_, _, err = repo.ReceiveTransfer(ctx, transfer.ID, destinationReceptionID, 5, time.Now())
+	assert.Equal(t, e.ErrVersionMismatch(), err)
+
+	version, changes, err = repo.ReceiveTransfer(ctx, transfer.ID, destinationReceptionID, 1, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 2, version)
+	assert.Len(t, changes, 1)
+
+	received, err := repo.GetTransfer(ctx, transfer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.TransferStatusReceived, received.Status)
+	assert.Equal(t, destinationReceptionID, *received.ReceptionID)
+
+	moved, err := repo.GetProduct(ctx, product.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ProductStatusAccepted, moved.Status)
+	assert.Equal(t, destinationReceptionID, moved.ReceptionID)
+
+	occupancy, err := repo.GetPVZOccupancy(ctx, destinationID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, occupancy.Count)
+
+	// A received product was not scanned in the reception and cannot be
+	// deleted from it
+	_, err = repo.GetLastProduct(ctx, destinationReceptionID, time.Time{})
+	assert.Equal(t, e.ErrNotFound(), err)
+	_, _, err = repo.DeleteProduct(ctx, product.ID, version)
+	assert.Equal(t, e.ErrNotFound(), err)
+}
+
+func TestGetTransfer_NotFound(t *testing.T) {
+	_, err := New().GetTransfer(context.Background(), uuid.New())
+	assert.Equal(t, e.ErrNotFound(), err)
+}
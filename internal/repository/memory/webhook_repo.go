@@ -0,0 +1,260 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// webhookDelivery is a queued delivery with the columns the model does not
+// expose
+type webhookDelivery struct {
+	models.WebhookDelivery
+	deliveredAt *time.Time
+}
+
+func (m *Memory) InsertSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	if sub.PVZID != nil {
+		if _, err := m.CheckPVZ(ctx, *sub.PVZID); err != nil {
+			return err
+		}
+	}
+	if sub.CityName != "" {
+		cityID, err := m.GetCityID(ctx, sub.CityName)
+		if err != nil {
+			return err
+		}
+		sub.CityID = &cityID
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.subscriptions[sub.ID]; ok {
+		return fmt.Errorf("subscription %s already exists", sub.ID)
+	}
+
+	stored := *sub
+	stored.EventTypes = slices.Clone(sub.EventTypes)
+	m.subscriptions[stored.ID] = &stored
+
+	return nil
+}
+
+func (m *Memory) GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var subs []models.WebhookSubscription
+	for _, stored := range m.subscriptions {
+		sub := *stored
+		sub.EventTypes = append([]models.EventType{}, stored.EventTypes...)
+		sub.CityName = ""
+		if sub.CityID != nil {
+			sub.CityName = m.cityName(*sub.CityID)
+		}
+		subs = append(subs, sub)
+	}
+
+	sort.Slice(subs, func(i, j int) bool {
+		return subs[i].CreatedAt.After(subs[j].CreatedAt)
+	})
+	return subs, nil
+}
+
+func (m *Memory) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.subscriptions[id]; !ok {
+		return e.ErrNotFound()
+	}
+	delete(m.subscriptions, id)
+
+	m.deliveries = slices.DeleteFunc(m.deliveries, func(d *webhookDelivery) bool {
+		return d.SubscriptionID == id
+	})
+	return nil
+}
+
+func (m *Memory) EnqueueDeliveries(ctx context.Context, event models.Event) (int, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var pvzCityID *int
+	if pvz, ok := m.pvzs[event.PVZID]; ok {
+		pvzCityID = &pvz.CityID
+	}
+
+	now := time.Now()
+
+	var enqueued int
+	for _, sub := range m.subscriptions {
+		if len(sub.EventTypes) > 0 && !slices.Contains(sub.EventTypes, event.Type) {
+			continue
+		}
+		if sub.PVZID != nil && *sub.PVZID != event.PVZID {
+			continue
+		}
+		if sub.CityID != nil && (pvzCityID == nil || *sub.CityID != *pvzCityID) {
+			continue
+		}
+		if m.hasDelivery(sub.ID, event.ID) {
+			continue
+		}
+
+		m.deliveries = append(m.deliveries, &webhookDelivery{WebhookDelivery: models.WebhookDelivery{
+			ID:             uuid.New(),
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        payload,
+			Status:         models.DeliveryStatusPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		}})
+		enqueued++
+	}
+
+	return enqueued, nil
+}
+
+// hasDelivery reports whether the event is already queued for the
+// subscription. Callers hold mu.
+func (m *Memory) hasDelivery(subscriptionID, eventID uuid.UUID) bool {
+	for _, d := range m.deliveries {
+		if d.SubscriptionID == subscriptionID && d.EventID == eventID {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Memory) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	var due []*webhookDelivery
+	for _, d := range m.deliveries {
+		if d.Status == models.DeliveryStatusPending && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	var deliveries []models.WebhookDelivery
+	for _, d := range due {
+		// Claimed deliveries are not due again until the lease runs out
+		d.NextAttemptAt = now.Add(lease)
+
+		delivery := d.WebhookDelivery
+		delivery.Payload = slices.Clone(d.Payload)
+		if sub, ok := m.subscriptions[d.SubscriptionID]; ok {
+			delivery.URL = sub.URL
+			delivery.Secret = sub.Secret
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+// delivery returns the stored delivery by ID. Callers hold mu.
+func (m *Memory) delivery(id uuid.UUID) (*webhookDelivery, bool) {
+	for _, d := range m.deliveries {
+		if d.ID == id {
+			return d, true
+		}
+	}
+	return nil, false
+}
+
+func (m *Memory) MarkDeliverySucceeded(ctx context.Context, id uuid.UUID, statusCode int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if d, ok := m.delivery(id); ok {
+		now := time.Now()
+		d.Status = models.DeliveryStatusDelivered
+		d.Attempts++
+		d.LastError = ""
+		d.LastStatusCode = statusCode
+		d.deliveredAt = &now
+	}
+	return nil
+}
+
+func (m *Memory) MarkDeliveryFailed(ctx context.Context, id uuid.UUID, statusCode int, lastErr string, nextAttemptAt time.Time, dead bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status := models.DeliveryStatusPending
+	if dead {
+		status = models.DeliveryStatusDead
+	}
+
+	if d, ok := m.delivery(id); ok {
+		d.Status = status
+		d.Attempts++
+		d.LastError = lastErr
+		d.LastStatusCode = statusCode
+		d.NextAttemptAt = nextAttemptAt
+	}
+	return nil
+}
+
+func (m *Memory) GetDeadDeliveries(ctx context.Context, subscriptionID uuid.UUID) ([]models.WebhookDelivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var deliveries []models.WebhookDelivery
+	for _, d := range m.deliveries {
+		if d.SubscriptionID != subscriptionID || d.Status != models.DeliveryStatusDead {
+			continue
+		}
+		delivery := d.WebhookDelivery
+		delivery.Payload = slices.Clone(d.Payload)
+		deliveries = append(deliveries, delivery)
+	}
+
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+	return deliveries, nil
+}
+
+func (m *Memory) RedeliverDelivery(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	d, ok := m.delivery(id)
+	if !ok || d.Status != models.DeliveryStatusDead {
+		return e.ErrNotFound()
+	}
+
+	d.Status = models.DeliveryStatusPending
+	d.Attempts = 0
+	d.NextAttemptAt = time.Now()
+	d.deliveredAt = nil
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"fmt"
+	"log/slog"
+	"pvz-service/internal/config"
+	"pvz-service/internal/repository/postgres"
+	"pvz-service/internal/repository/sqlite"
+
+	"github.com/pressly/goose/v3"
+)
+
+// NewMigrator connects to the database selected by cfg.Database.Protocol
+// and returns a provider for its migrations. The memory protocol has no
+// schema to migrate.
+func NewMigrator(cfg *config.Config, log *slog.Logger) (*goose.Provider, error) {
+	const op = "repository.NewMigrator"
+
+	switch cfg.Database.Protocol {
+	case "postgres":
+		return postgres.NewMigrator(cfg, log)
+	case "sqlite":
+		return sqlite.NewMigrator(cfg.Database.Path)
+	default:
+		return nil, fmt.Errorf("%s: %s database has no migrations", op, cfg.Database.Protocol)
+	}
+}
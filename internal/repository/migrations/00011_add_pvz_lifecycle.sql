@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'pvz_status') THEN
+        CREATE TYPE pvz_status AS ENUM ('active', 'suspended', 'closed');
+    END IF;
+END $$;
+
+ALTER TABLE pvz ADD COLUMN IF NOT EXISTS status pvz_status NOT NULL DEFAULT 'active';
+ALTER TABLE pvz ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- PVZs are soft-deleted, so their receptions must never go with them
+ALTER TABLE receptions DROP CONSTRAINT IF EXISTS receptions_pvz_id_fkey;
+ALTER TABLE receptions ADD CONSTRAINT receptions_pvz_id_fkey
+    FOREIGN KEY (pvz_id) REFERENCES pvz(id) ON DELETE RESTRICT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE receptions DROP CONSTRAINT IF EXISTS receptions_pvz_id_fkey;
+ALTER TABLE receptions ADD CONSTRAINT receptions_pvz_id_fkey
+    FOREIGN KEY (pvz_id) REFERENCES pvz(id) ON DELETE CASCADE;
+
+ALTER TABLE pvz DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE pvz DROP COLUMN IF EXISTS status;
+
+DROP TYPE IF EXISTS pvz_status;
+-- +goose StatementEnd
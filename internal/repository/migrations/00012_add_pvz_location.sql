@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE pvz ADD COLUMN IF NOT EXISTS address TEXT NOT NULL DEFAULT '';
+ALTER TABLE pvz ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION;
+ALTER TABLE pvz ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+ALTER TABLE pvz ADD COLUMN IF NOT EXISTS opening_hours TEXT NOT NULL DEFAULT '';
+ALTER TABLE pvz ADD COLUMN IF NOT EXISTS capacity INT NOT NULL DEFAULT 0;
+
+ALTER TABLE pvz ADD CONSTRAINT pvz_location_check CHECK (
+    (latitude IS NULL AND longitude IS NULL)
+    OR (latitude BETWEEN -90 AND 90 AND longitude BETWEEN -180 AND 180)
+);
+ALTER TABLE pvz ADD CONSTRAINT pvz_capacity_check CHECK (capacity >= 0);
+
+-- Bounding box prefilter for the nearby search
+CREATE INDEX IF NOT EXISTS idx_pvz_latitude_longitude
+    ON pvz(latitude, longitude)
+    WHERE deleted_at IS NULL AND latitude IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_pvz_latitude_longitude;
+
+ALTER TABLE pvz DROP CONSTRAINT IF EXISTS pvz_capacity_check;
+ALTER TABLE pvz DROP CONSTRAINT IF EXISTS pvz_location_check;
+
+ALTER TABLE pvz DROP COLUMN IF EXISTS capacity;
+ALTER TABLE pvz DROP COLUMN IF EXISTS opening_hours;
+ALTER TABLE pvz DROP COLUMN IF EXISTS longitude;
+ALTER TABLE pvz DROP COLUMN IF EXISTS latitude;
+ALTER TABLE pvz DROP COLUMN IF EXISTS address;
+-- +goose StatementEnd
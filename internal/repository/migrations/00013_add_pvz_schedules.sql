@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS pvz_schedules (
+    pvz_id UUID PRIMARY KEY REFERENCES pvz(id) ON DELETE CASCADE,
+    timezone TEXT NOT NULL,
+    weekly JSONB NOT NULL DEFAULT '[]',
+    exceptions JSONB NOT NULL DEFAULT '[]',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS pvz_schedules;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_status_check;
+ALTER TABLE products ADD CONSTRAINT products_status_check
+    CHECK (status IN ('accepted', 'stored', 'issued', 'returned', 'written_off', 'in_transit'));
+
+-- A transferred product moves to a reception of the receiving PVZ, so the
+-- history keeps the reception each change was made in
+ALTER TABLE product_status_history ADD COLUMN IF NOT EXISTS reception_id UUID REFERENCES receptions(id);
+UPDATE product_status_history h SET reception_id = p.reception_id
+FROM products p
+WHERE p.id = h.product_id;
+ALTER TABLE product_status_history ALTER COLUMN reception_id SET NOT NULL;
+
+CREATE TABLE IF NOT EXISTS transfers (
+    id UUID PRIMARY KEY,
+    source_pvz_id UUID NOT NULL REFERENCES pvz(id),
+    destination_pvz_id UUID NOT NULL REFERENCES pvz(id),
+    status TEXT NOT NULL CHECK (status IN ('outbound', 'in_transit', 'received', 'cancelled')),
+    reception_id UUID REFERENCES receptions(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    dispatched_at TIMESTAMPTZ,
+    received_at TIMESTAMPTZ,
+    CHECK (source_pvz_id <> destination_pvz_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transfers_source_pvz_id ON transfers(source_pvz_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_transfers_destination_pvz_id ON transfers(destination_pvz_id, created_at);
+
+CREATE TABLE IF NOT EXISTS transfer_items (
+    transfer_id UUID NOT NULL REFERENCES transfers(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    PRIMARY KEY (transfer_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transfer_items_product_id ON transfer_items(product_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS transfer_items;
+DROP TABLE IF EXISTS transfers;
+
+ALTER TABLE product_status_history DROP COLUMN IF EXISTS reception_id;
+
+UPDATE products SET status = 'stored' WHERE status = 'in_transit';
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_status_check;
+ALTER TABLE products ADD CONSTRAINT products_status_check
+    CHECK (status IN ('accepted', 'stored', 'issued', 'returned', 'written_off'));
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE products ADD COLUMN IF NOT EXISTS barcode VARCHAR(64);
+CREATE INDEX IF NOT EXISTS idx_products_reception_barcode ON products(reception_id, barcode);
+
+-- A manifest lists the items a supplier is going to deliver to a PVZ. It is
+-- linked to the reception the delivery is accepted in and gets the
+-- discrepancy report when that reception is closed.
+CREATE TABLE IF NOT EXISTS manifests (
+    id UUID PRIMARY KEY,
+    pvz_id UUID NOT NULL REFERENCES pvz(id),
+    supplier VARCHAR(255) NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('pending', 'receiving', 'reconciled')),
+    reception_id UUID UNIQUE REFERENCES receptions(id),
+    report JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reconciled_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_manifests_pvz_id ON manifests(pvz_id, created_at);
+
+CREATE TABLE IF NOT EXISTS manifest_items (
+    manifest_id UUID NOT NULL REFERENCES manifests(id) ON DELETE CASCADE,
+    barcode VARCHAR(64) NOT NULL,
+    type_id INT NOT NULL REFERENCES product_types(id),
+    PRIMARY KEY (manifest_id, barcode)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS manifest_items;
+DROP TABLE IF EXISTS manifests;
+
+DROP INDEX IF EXISTS idx_products_reception_barcode;
+ALTER TABLE products DROP COLUMN IF EXISTS barcode;
+-- +goose StatementEnd
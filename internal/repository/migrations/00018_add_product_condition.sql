@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE products ADD COLUMN IF NOT EXISTS condition TEXT NOT NULL DEFAULT 'ok'
+    CHECK (condition IN ('ok', 'damaged', 'wrong_item'));
+ALTER TABLE products ADD COLUMN IF NOT EXISTS condition_note TEXT NOT NULL DEFAULT '';
+ALTER TABLE products ADD COLUMN IF NOT EXISTS photo_key TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE products DROP COLUMN IF EXISTS photo_key;
+ALTER TABLE products DROP COLUMN IF EXISTS condition_note;
+ALTER TABLE products DROP COLUMN IF EXISTS condition;
+-- +goose StatementEnd
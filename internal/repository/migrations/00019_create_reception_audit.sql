@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS reception_audit (
+    id BIGSERIAL PRIMARY KEY,
+    reception_id UUID NOT NULL REFERENCES receptions(id),
+    pvz_id UUID NOT NULL REFERENCES pvz(id),
+    action TEXT NOT NULL CHECK (action IN ('auto_closed', 'flagged_stale', 'force_closed')),
+    actor TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reception_audit_reception_id ON reception_audit(reception_id, action);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS reception_audit;
+-- +goose StatementEnd
@@ -0,0 +1,179 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Receptions and products move to tables split into monthly range
+-- partitions on date_time, so listings filtered by date only read the months
+-- they cover. The move runs online in three steps:
+--   00021 creates the partitioned tables next to the live ones and mirrors
+--         every write of the live tables into them
+--   00022 copies the existing rows over in small batches
+--   00023 swaps the tables in with a short rename
+-- Until the swap the partitioned tables live under the _partitioned names.
+--
+-- A partitioned table can only enforce keys that include the partition key,
+-- so the primary keys become (id, date_time). Product IDs stay unique through
+-- product_keys below; reception IDs are unique only as generated UUIDs. The
+-- foreign keys pointing at the two tables are dropped by the swap.
+CREATE TABLE IF NOT EXISTS receptions_partitioned (
+    id UUID NOT NULL DEFAULT gen_random_uuid(),
+    date_time TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    pvz_id UUID NOT NULL,
+    status reception_status NOT NULL DEFAULT 'in_progress',
+    version INT NOT NULL DEFAULT 1,
+    CONSTRAINT receptions_partitioned_pkey PRIMARY KEY (id, date_time),
+    CONSTRAINT receptions_pvz_id_fkey FOREIGN KEY (pvz_id) REFERENCES pvz(id) ON DELETE RESTRICT
+) PARTITION BY RANGE (date_time);
+
+CREATE TABLE IF NOT EXISTS products_partitioned (
+    id UUID NOT NULL DEFAULT gen_random_uuid(),
+    date_time TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    type_id INT NOT NULL,
+    reception_id UUID NOT NULL,
+    status TEXT NOT NULL DEFAULT 'accepted',
+    barcode VARCHAR(64),
+    condition TEXT NOT NULL DEFAULT 'ok',
+    condition_note TEXT NOT NULL DEFAULT '',
+    photo_key TEXT,
+    CONSTRAINT products_partitioned_pkey PRIMARY KEY (id, date_time),
+    CONSTRAINT products_type_id_fkey FOREIGN KEY (type_id) REFERENCES product_types(id) ON DELETE CASCADE,
+    CONSTRAINT products_status_check
+        CHECK (status IN ('accepted', 'stored', 'issued', 'returned', 'written_off', 'in_transit')),
+    CONSTRAINT products_condition_check
+        CHECK (condition IN ('ok', 'damaged', 'wrong_item'))
+) PARTITION BY RANGE (date_time);
+
+-- Rows of months without a partition yet land here until the partition
+-- maintainer creates one and moves them over
+CREATE TABLE IF NOT EXISTS receptions_default PARTITION OF receptions_partitioned DEFAULT;
+CREATE TABLE IF NOT EXISTS products_default PARTITION OF products_partitioned DEFAULT;
+
+-- Indexes on the parents are created on every partition, present and
+-- future. They get their final names with the swap.
+CREATE INDEX IF NOT EXISTS idx_receptions_partitioned_pvz_id_date_time
+    ON receptions_partitioned(pvz_id, date_time DESC);
+CREATE INDEX IF NOT EXISTS idx_receptions_partitioned_in_progress_date_time_desc
+    ON receptions_partitioned(date_time DESC)
+    WHERE status = 'in_progress';
+CREATE INDEX IF NOT EXISTS idx_receptions_partitioned_close_date_time
+    ON receptions_partitioned(date_time)
+    WHERE status = 'close';
+CREATE INDEX IF NOT EXISTS idx_products_partitioned_reception_id_date_time_desc
+    ON products_partitioned(reception_id, date_time DESC);
+CREATE INDEX IF NOT EXISTS idx_products_partitioned_reception_barcode
+    ON products_partitioned(reception_id, barcode);
+
+-- ensure_monthly_partition creates the partition of the table named parent
+-- holding the calendar month (UTC) of moment, moving the rows of that month
+-- out of the default partition. It returns the name of the new partition,
+-- or NULL when the partition already exists.
+CREATE OR REPLACE FUNCTION ensure_monthly_partition(parent TEXT, moment TIMESTAMPTZ)
+RETURNS TEXT AS $$
+DECLARE
+    month_start TIMESTAMP := date_trunc('month', moment AT TIME ZONE 'UTC');
+    lower_bound TIMESTAMPTZ := month_start AT TIME ZONE 'UTC';
+    upper_bound TIMESTAMPTZ := (month_start + INTERVAL '1 month') AT TIME ZONE 'UTC';
+    part_name TEXT := format('%s_p%s', parent, to_char(month_start, 'YYYY_MM'));
+    target TEXT := parent;
+BEGIN
+    -- Before the swap the partitioned table is not the live one yet
+    IF to_regclass(parent || '_partitioned') IS NOT NULL THEN
+        target := parent || '_partitioned';
+    END IF;
+
+    -- Instances running the maintainer at once create each partition once
+    PERFORM pg_advisory_xact_lock(hashtext(part_name));
+    IF to_regclass(part_name) IS NOT NULL THEN
+        RETURN NULL;
+    END IF;
+
+    EXECUTE format('CREATE TABLE %I (LIKE %I INCLUDING DEFAULTS INCLUDING CONSTRAINTS)',
+        part_name, target);
+    EXECUTE format(
+        'WITH moved AS (DELETE FROM %I WHERE date_time >= %L AND date_time < %L RETURNING *)
+         INSERT INTO %I SELECT * FROM moved',
+        parent || '_default', lower_bound, upper_bound, part_name);
+    EXECUTE format('ALTER TABLE %I ATTACH PARTITION %I FOR VALUES FROM (%L) TO (%L)',
+        target, part_name, lower_bound, upper_bound);
+    RETURN part_name;
+END;
+$$ LANGUAGE plpgsql;
+
+-- The current month and the two after it are ready before the first write
+SELECT ensure_monthly_partition(parent, month_start)
+FROM (VALUES ('receptions'), ('products')) AS tables(parent),
+     generate_series(
+         date_trunc('month', NOW() AT TIME ZONE 'UTC'),
+         date_trunc('month', NOW() AT TIME ZONE 'UTC') + INTERVAL '2 months',
+         INTERVAL '1 month') AS month_start;
+
+-- Writes to the live tables are repeated on the partitioned ones. A changed
+-- row is deleted and inserted again, as its date_time, and so its
+-- partition, may change. Rows the backfill has not reached yet are inserted
+-- here and skipped by the backfill later.
+CREATE OR REPLACE FUNCTION mirror_receptions() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        DELETE FROM receptions_partitioned WHERE id = OLD.id AND date_time = OLD.date_time;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        INSERT INTO receptions_partitioned (id, date_time, pvz_id, status, version)
+        VALUES (NEW.id, NEW.date_time, NEW.pvz_id, NEW.status, NEW.version)
+        ON CONFLICT DO NOTHING;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION mirror_products() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        DELETE FROM products_partitioned WHERE id = OLD.id AND date_time = OLD.date_time;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        INSERT INTO products_partitioned (id, date_time, type_id, reception_id, status, barcode, condition, condition_note, photo_key)
+        VALUES (NEW.id, NEW.date_time, NEW.type_id, NEW.reception_id, NEW.status, NEW.barcode, NEW.condition, NEW.condition_note, NEW.photo_key)
+        ON CONFLICT DO NOTHING;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER receptions_mirror
+    AFTER INSERT OR UPDATE OR DELETE ON receptions
+    FOR EACH ROW EXECUTE FUNCTION mirror_receptions();
+CREATE TRIGGER products_mirror
+    AFTER INSERT OR UPDATE OR DELETE ON products
+    FOR EACH ROW EXECUTE FUNCTION mirror_products();
+
+-- product_keys holds the date_time of every product, which together with the
+-- id makes up its key in the partitioned table, so a product looked up by id
+-- is read from one partition. Its primary key keeps product IDs unique across
+-- partitions. The repository writes it along with products, and the backfill
+-- fills it in for the existing rows.
+CREATE TABLE IF NOT EXISTS product_keys (
+    id UUID PRIMARY KEY,
+    date_time TIMESTAMPTZ NOT NULL
+);
+
+-- Where the backfill of each table stopped, so an interrupted one resumes
+CREATE TABLE IF NOT EXISTS partition_backfill (
+    table_name TEXT PRIMARY KEY,
+    last_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000',
+    done BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+INSERT INTO partition_backfill (table_name) VALUES ('receptions'), ('products');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS products_mirror ON products;
+DROP TRIGGER IF EXISTS receptions_mirror ON receptions;
+DROP FUNCTION IF EXISTS mirror_products();
+DROP FUNCTION IF EXISTS mirror_receptions();
+
+DROP TABLE IF EXISTS partition_backfill;
+DROP TABLE IF EXISTS product_keys;
+DROP TABLE IF EXISTS products_partitioned;
+DROP TABLE IF EXISTS receptions_partitioned;
+DROP FUNCTION IF EXISTS ensure_monthly_partition(TEXT, TIMESTAMPTZ);
+-- +goose StatementEnd
@@ -0,0 +1,141 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/pressly/goose/v3"
+)
+
+// backfillBatch is the number of rows copied per transaction. Each batch
+// locks its rows in the live table until it commits.
+const backfillBatch = 5000
+
+// backfillTables lists the columns copied to the partitioned tables. The
+// keys of keyed tables are copied to product_keys as well.
+var backfillTables = []struct {
+	name    string
+	columns string
+	keyed   bool
+}{
+	{"receptions", "id, date_time, pvz_id, status, version", false},
+	{"products", "id, date_time, type_id, reception_id, status, barcode, condition, condition_note, photo_key", true},
+}
+
+// backfillPartitioned copies the rows of the live tables to the partitioned
+// ones created by 00021. It runs outside a transaction, in batches committed
+// one by one, so the service keeps working meanwhile. The position is kept
+// in partition_backfill, and an interrupted run continues from it.
+func backfillPartitioned(ctx context.Context, db *sql.DB) error {
+	for _, table := range backfillTables {
+		copied := 0
+		for {
+			n, done, err := backfillNext(ctx, db, table.name, table.columns, table.keyed)
+			if err != nil {
+				return fmt.Errorf("backfill %s: %w", table.name, err)
+			}
+			if done {
+				break
+			}
+
+			copied += n
+			slog.Info("backfilling partitioned table",
+				slog.String("table", table.name),
+				slog.Int("rows", copied),
+			)
+		}
+	}
+	return nil
+}
+
+// backfillNext copies the batch of rows after the saved position. It reports
+// done once there is nothing left to copy.
+func backfillNext(ctx context.Context, db *sql.DB, table, columns string, keyed bool) (int, bool, error) {
+	var lastID string
+	var done bool
+	if err := db.QueryRowContext(ctx,
+		"SELECT last_id, done FROM partition_backfill WHERE table_name = $1",
+		table).Scan(&lastID, &done); err != nil {
+		return 0, false, err
+	}
+	if done {
+		return 0, true, nil
+	}
+
+	// The months of the batch get their partitions first, so the copy does
+	// not pile rows into the default partition
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(
+		`SELECT DISTINCT date_trunc('month', date_time AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'
+		 FROM (SELECT date_time FROM %s WHERE id > $1 ORDER BY id LIMIT $2) batch`, table),
+		lastID, backfillBatch)
+	if err != nil {
+		return 0, false, err
+	}
+	months := []time.Time{}
+	for rows.Next() {
+		var month time.Time
+		if err := rows.Scan(&month); err != nil {
+			rows.Close()
+			return 0, false, err
+		}
+		months = append(months, month)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, false, err
+	}
+
+	for _, month := range months {
+		if _, err := db.ExecContext(ctx, "SELECT ensure_monthly_partition($1, $2)", table, month); err != nil {
+			return 0, false, err
+		}
+	}
+
+	// Rows written by the service since 00021 are in place already, through
+	// the mirror triggers and the repository, and are skipped
+	keys := ""
+	if keyed {
+		keys = `, keyed AS (
+			INSERT INTO product_keys (id, date_time) SELECT id, date_time FROM batch
+			ON CONFLICT DO NOTHING
+		)`
+	}
+	var n int
+	if err := db.QueryRowContext(ctx, fmt.Sprintf(
+		`WITH batch AS (
+			SELECT %[2]s FROM %[1]s WHERE id > $1 ORDER BY id LIMIT $2 FOR UPDATE
+		), copied AS (
+			INSERT INTO %[1]s_partitioned (%[2]s) SELECT %[2]s FROM batch
+			ON CONFLICT DO NOTHING
+		)%[3]s, moved AS (
+			UPDATE partition_backfill
+			SET last_id = COALESCE((SELECT id FROM batch ORDER BY id DESC LIMIT 1), last_id),
+			    done = NOT EXISTS (SELECT 1 FROM batch)
+			WHERE table_name = $3
+		)
+		SELECT COUNT(*) FROM batch`, table, columns, keys),
+		lastID, backfillBatch, table).Scan(&n); err != nil {
+		return 0, false, err
+	}
+
+	return n, n == 0, nil
+}
+
+// clearPartitioned empties the partitioned tables for the backfill to run
+// again from the start
+func clearPartitioned(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		TRUNCATE receptions_partitioned, products_partitioned, product_keys;
+		UPDATE partition_backfill SET last_id = '00000000-0000-0000-0000-000000000000', done = FALSE;`)
+	return err
+}
+
+func backfillMigration() *goose.Migration {
+	return goose.NewGoMigration(22,
+		&goose.GoFunc{RunDB: backfillPartitioned},
+		&goose.GoFunc{RunDB: clearPartitioned},
+	)
+}
@@ -0,0 +1,126 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The partitioned tables replace the live ones. The exclusive locks are held
+-- only for the renames, and the migration gives up rather than queue the
+-- service queries behind a long wait for them.
+SET LOCAL lock_timeout = '5s';
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM partition_backfill WHERE NOT done) THEN
+        RAISE EXCEPTION 'receptions and products are not copied to the partitioned tables yet';
+    END IF;
+END;
+$$;
+
+LOCK TABLE receptions, products IN ACCESS EXCLUSIVE MODE;
+
+DROP TRIGGER IF EXISTS products_mirror ON products;
+DROP TRIGGER IF EXISTS receptions_mirror ON receptions;
+
+-- Keys pointing at the tables cannot point at the partitioned ones, whose
+-- keys include date_time. The repository removes dependent rows itself.
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_reception_id_fkey;
+ALTER TABLE product_status_history DROP CONSTRAINT IF EXISTS product_status_history_product_id_fkey;
+ALTER TABLE product_status_history DROP CONSTRAINT IF EXISTS product_status_history_reception_id_fkey;
+ALTER TABLE transfers DROP CONSTRAINT IF EXISTS transfers_reception_id_fkey;
+ALTER TABLE transfer_items DROP CONSTRAINT IF EXISTS transfer_items_product_id_fkey;
+ALTER TABLE manifests DROP CONSTRAINT IF EXISTS manifests_reception_id_fkey;
+ALTER TABLE reception_audit DROP CONSTRAINT IF EXISTS reception_audit_reception_id_fkey;
+
+DROP TABLE products;
+DROP TABLE receptions;
+
+ALTER TABLE receptions_partitioned RENAME TO receptions;
+ALTER TABLE receptions RENAME CONSTRAINT receptions_partitioned_pkey TO receptions_pkey;
+ALTER INDEX idx_receptions_partitioned_pvz_id_date_time RENAME TO idx_receptions_pvz_id_date_time;
+ALTER INDEX idx_receptions_partitioned_in_progress_date_time_desc RENAME TO idx_receptions_in_progress_date_time_desc;
+ALTER INDEX idx_receptions_partitioned_close_date_time RENAME TO idx_receptions_close_date_time;
+
+ALTER TABLE products_partitioned RENAME TO products;
+ALTER TABLE products RENAME CONSTRAINT products_partitioned_pkey TO products_pkey;
+ALTER INDEX idx_products_partitioned_reception_id_date_time_desc RENAME TO idx_products_reception_id_date_time_desc;
+ALTER INDEX idx_products_partitioned_reception_barcode RENAME TO idx_products_reception_barcode;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- The way back copies the rows into plain tables under an exclusive lock,
+-- so it stops the service queries for as long as the copy takes. The
+-- partitioned tables stay in place, mirrored again, as 00021 left them.
+LOCK TABLE receptions, products IN ACCESS EXCLUSIVE MODE;
+
+ALTER INDEX idx_products_reception_barcode RENAME TO idx_products_partitioned_reception_barcode;
+ALTER INDEX idx_products_reception_id_date_time_desc RENAME TO idx_products_partitioned_reception_id_date_time_desc;
+ALTER TABLE products RENAME CONSTRAINT products_pkey TO products_partitioned_pkey;
+ALTER TABLE products RENAME TO products_partitioned;
+
+ALTER INDEX idx_receptions_close_date_time RENAME TO idx_receptions_partitioned_close_date_time;
+ALTER INDEX idx_receptions_in_progress_date_time_desc RENAME TO idx_receptions_partitioned_in_progress_date_time_desc;
+ALTER INDEX idx_receptions_pvz_id_date_time RENAME TO idx_receptions_partitioned_pvz_id_date_time;
+ALTER TABLE receptions RENAME CONSTRAINT receptions_pkey TO receptions_partitioned_pkey;
+ALTER TABLE receptions RENAME TO receptions_partitioned;
+
+CREATE TABLE receptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    date_time TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    pvz_id UUID NOT NULL,
+    status reception_status NOT NULL DEFAULT 'in_progress',
+    version INT NOT NULL DEFAULT 1,
+    CONSTRAINT receptions_pvz_id_fkey FOREIGN KEY (pvz_id) REFERENCES pvz(id) ON DELETE RESTRICT
+);
+
+INSERT INTO receptions (id, date_time, pvz_id, status, version)
+SELECT id, date_time, pvz_id, status, version
+FROM receptions_partitioned;
+
+CREATE TABLE products (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    date_time TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    type_id INT NOT NULL REFERENCES product_types(id) ON DELETE CASCADE,
+    reception_id UUID NOT NULL REFERENCES receptions(id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'accepted',
+    barcode VARCHAR(64),
+    condition TEXT NOT NULL DEFAULT 'ok'
+        CHECK (condition IN ('ok', 'damaged', 'wrong_item')),
+    condition_note TEXT NOT NULL DEFAULT '',
+    photo_key TEXT,
+    CONSTRAINT products_status_check
+        CHECK (status IN ('accepted', 'stored', 'issued', 'returned', 'written_off', 'in_transit'))
+);
+
+INSERT INTO products (id, date_time, type_id, reception_id, status, barcode, condition, condition_note, photo_key)
+SELECT id, date_time, type_id, reception_id, status, barcode, condition, condition_note, photo_key
+FROM products_partitioned;
+
+CREATE INDEX IF NOT EXISTS idx_receptions_in_progress_date_time_desc
+    ON receptions(date_time DESC)
+    WHERE status = 'in_progress';
+CREATE INDEX IF NOT EXISTS idx_receptions_close_date_time
+    ON receptions(date_time)
+    WHERE status = 'close';
+CREATE INDEX IF NOT EXISTS idx_products_reception_id_date_time_desc
+    ON products(reception_id, date_time DESC);
+CREATE INDEX IF NOT EXISTS idx_products_reception_barcode
+    ON products(reception_id, barcode);
+
+ALTER TABLE product_status_history ADD CONSTRAINT product_status_history_product_id_fkey
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE;
+ALTER TABLE product_status_history ADD CONSTRAINT product_status_history_reception_id_fkey
+    FOREIGN KEY (reception_id) REFERENCES receptions(id);
+ALTER TABLE transfers ADD CONSTRAINT transfers_reception_id_fkey
+    FOREIGN KEY (reception_id) REFERENCES receptions(id);
+ALTER TABLE transfer_items ADD CONSTRAINT transfer_items_product_id_fkey
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE;
+ALTER TABLE manifests ADD CONSTRAINT manifests_reception_id_fkey
+    FOREIGN KEY (reception_id) REFERENCES receptions(id);
+ALTER TABLE reception_audit ADD CONSTRAINT reception_audit_reception_id_fkey
+    FOREIGN KEY (reception_id) REFERENCES receptions(id);
+
+CREATE TRIGGER receptions_mirror
+    AFTER INSERT OR UPDATE OR DELETE ON receptions
+    FOR EACH ROW EXECUTE FUNCTION mirror_receptions();
+CREATE TRIGGER products_mirror
+    AFTER INSERT OR UPDATE OR DELETE ON products
+    FOR EACH ROW EXECUTE FUNCTION mirror_products();
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Failed events are retried with backoff instead of holding up the ones
+-- after them, and are parked after too many attempts
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS dead_at TIMESTAMPTZ;
+
+DROP INDEX IF EXISTS idx_outbox_unpublished_created_at;
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending_created_at
+    ON outbox(created_at)
+    WHERE published_at IS NULL AND dead_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_outbox_published_at
+    ON outbox(published_at)
+    WHERE published_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_outbox_published_at;
+DROP INDEX IF EXISTS idx_outbox_pending_created_at;
+
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished_created_at
+    ON outbox(created_at)
+    WHERE published_at IS NULL;
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS dead_at;
+ALTER TABLE outbox DROP COLUMN IF EXISTS next_attempt_at;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Manifests and audit entries stay in place when their reception moves to
+-- the archive: reception_id is cleared and the ID is kept in
+-- archived_reception_id, which points into receptions_archive
+ALTER TABLE manifests ADD COLUMN IF NOT EXISTS archived_reception_id UUID;
+
+ALTER TABLE reception_audit ALTER COLUMN reception_id DROP NOT NULL;
+ALTER TABLE reception_audit ADD COLUMN IF NOT EXISTS archived_reception_id UUID;
+ALTER TABLE reception_audit ADD CONSTRAINT reception_audit_reception_check
+    CHECK (reception_id IS NOT NULL OR archived_reception_id IS NOT NULL);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Entries of archived receptions have no live reception to point at
+DELETE FROM reception_audit WHERE reception_id IS NULL;
+
+ALTER TABLE reception_audit DROP CONSTRAINT IF EXISTS reception_audit_reception_check;
+ALTER TABLE reception_audit DROP COLUMN IF EXISTS archived_reception_id;
+ALTER TABLE reception_audit ALTER COLUMN reception_id SET NOT NULL;
+
+ALTER TABLE manifests DROP COLUMN IF EXISTS archived_reception_id;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The schedule is the only source of the working hours of a PVZ. Texts like
+-- "09:00-21:00" become a daily schedule in Moscow time, the timezone of all
+-- the allowed cities; free-form texts cannot be enforced and are dropped.
+INSERT INTO pvz_schedules (pvz_id, timezone, weekly)
+SELECT p.id, 'Europe/Moscow', (
+    SELECT jsonb_agg(jsonb_build_object(
+        'weekday', d.weekday,
+        'intervals', jsonb_build_array(jsonb_build_object(
+            'opens', split_part(p.opening_hours, '-', 1),
+            'closes', split_part(p.opening_hours, '-', 2)))))
+    FROM unnest(ARRAY['monday', 'tuesday', 'wednesday', 'thursday', 'friday', 'saturday', 'sunday']) AS d(weekday)
+)
+FROM pvz p
+WHERE p.opening_hours ~ '^([01][0-9]|2[0-3]):[0-5][0-9]-(([01][0-9]|2[0-3]):[0-5][0-9]|24:00)$'
+  AND split_part(p.opening_hours, '-', 1) < split_part(p.opening_hours, '-', 2)
+ON CONFLICT (pvz_id) DO NOTHING;
+
+ALTER TABLE pvz DROP COLUMN IF EXISTS opening_hours;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- The schedules stay, the texts are not restored
+ALTER TABLE pvz ADD COLUMN IF NOT EXISTS opening_hours TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
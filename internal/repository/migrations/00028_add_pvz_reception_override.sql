@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE pvz_schedules ADD COLUMN IF NOT EXISTS receptions_allowed_until TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE pvz_schedules DROP COLUMN IF EXISTS receptions_allowed_until;
+-- +goose StatementEnd
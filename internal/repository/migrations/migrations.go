@@ -0,0 +1,30 @@
+// Package migrations holds the Postgres schema migrations. They are embedded
+// into the binary, so neither the service nor the migrate command needs the
+// SQL files on disk.
+package migrations
+
+import (
+	"embed"
+	"slices"
+
+	"github.com/pressly/goose/v3"
+)
+
+//go:embed *.sql
+var FS embed.FS
+
+// manual are the versions that copy whole tables. On a populated database
+// they run for a long time, so the service does not apply them on start and
+// they are run with the migrate command while the service keeps working.
+var manual = []int64{22, 23}
+
+// GoMigrations returns the migrations written in Go, to be registered with
+// the provider next to FS
+func GoMigrations() []*goose.Migration {
+	return []*goose.Migration{backfillMigration()}
+}
+
+// IsManual reports whether version is applied by the migrate command only
+func IsManual(version int64) bool {
+	return slices.Contains(manual, version)
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"pvz-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OutboxRepository interface {
+	// ClaimOutboxEvents returns up to limit unpublished events whose next
+	// attempt is due, oldest first, and postpones them by lease so other
+	// relays skip them meanwhile. Parked events are not returned.
+	ClaimOutboxEvents(ctx context.Context, limit int, lease time.Duration) ([]models.Event, error)
+	MarkEventPublished(ctx context.Context, id uuid.UUID) error
+	// MarkEventFailed records a failed attempt. The event is retried at
+	// nextAttemptAt, or parked when dead is set.
+	MarkEventFailed(ctx context.Context, id uuid.UUID, lastErr string, nextAttemptAt time.Time, dead bool) error
+	// PurgePublishedEvents deletes the events published before the given
+	// moment and returns how many were deleted
+	PurgePublishedEvents(ctx context.Context, before time.Time) (int, error)
+}
+
+func CreateOutboxRepo(db Database) (OutboxRepository, error) {
+	const op = "repository.outbox_repo.CreateOutboxRepo"
+
+	repo, ok := db.(OutboxRepository)
+	if !ok {
+		return nil, fmt.Errorf("%s: %T does not implement OutboxRepository", op, db)
+	}
+
+	return repo, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository/memory"
+	"pvz-service/internal/repository/postgres"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOutboxRepository implements OutboxRepository for testing
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepository) ClaimOutboxEvents(ctx context.Context, limit int, lease time.Duration) ([]models.Event, error) {
+	args := m.Called(ctx, limit, lease)
+	return args.Get(0).([]models.Event), args.Error(1)
+}
+
+func (m *MockOutboxRepository) MarkEventPublished(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) MarkEventFailed(ctx context.Context, id uuid.UUID, lastErr string, nextAttemptAt time.Time, dead bool) error {
+	args := m.Called(ctx, id, lastErr, nextAttemptAt, dead)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) PurgePublishedEvents(ctx context.Context, before time.Time) (int, error) {
+	args := m.Called(ctx, before)
+	return args.Int(0), args.Error(1)
+}
+
+func TestCreateOutboxRepo(t *testing.T) {
+	tests := []struct {
+		name        string
+		db          Database
+		expectError bool
+	}{
+		{
+			name: "Success with postgres",
+			db:   new(postgres.Postgres),
+		},
+		{
+			name: "Success with memory",
+			db:   memory.New(),
+		},
+		{
+			name: "Success with sqlite",
+			db:   openSQLite(t),
+		},
+		{
+			name:        "Not an outbox repository",
+			db:          stubDatabase{},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := CreateOutboxRepo(tt.db)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, repo)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, repo)
+			}
+		})
+	}
+}
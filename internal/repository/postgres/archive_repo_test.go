@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"pvz-service/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArchiveReceptions(t *testing.T) {
+	closedBefore := time.Now().AddDate(0, -12, 0)
+	archivedAt := time.Now()
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+
+	expectCandidates := func(mock sqlmock.Sqlmock) *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery("SELECT r.id FROM receptions r WHERE r.status = \\$1 AND r.date_time < \\$2 (.+) FOR UPDATE OF r SKIP LOCKED").
+			WithArgs(models.ReceptionStatusClose, closedBefore, models.ProductStatusIssued, models.ProductStatusWrittenOff, 100)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New(pgxArgs)
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		repo := &Postgres{db: db}
+
+		mock.ExpectBegin()
+		expectCandidates(mock).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(ids[0]).AddRow(ids[1]))
+		mock.ExpectExec("INSERT INTO receptions_archive (.+) FROM receptions WHERE id = ANY\\(\\$1\\)").
+			WithArgs(ids, archivedAt).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectQuery("SELECT photo_key FROM products WHERE reception_id = ANY\\(\\$1\\) AND photo_key IS NOT NULL").
+			WithArgs(ids).
+			WillReturnRows(sqlmock.NewRows([]string{"photo_key"}).AddRow("products/a.jpg"))
+		mock.ExpectExec("INSERT INTO products_archive (.+) FROM products WHERE reception_id = ANY\\(\\$1\\)").
+			WithArgs(ids).
+			WillReturnResult(sqlmock.NewResult(0, 5))
+		mock.ExpectExec("INSERT INTO product_status_history_archive (.+) FROM product_status_history h (.+) WHERE p.reception_id = ANY\\(\\$1\\)").
+			WithArgs(ids).
+			WillReturnResult(sqlmock.NewResult(0, 12))
+		mock.ExpectExec("UPDATE manifests SET archived_reception_id = reception_id, reception_id = NULL WHERE reception_id = ANY\\(\\$1\\)").
+			WithArgs(ids).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE reception_audit SET archived_reception_id = reception_id, reception_id = NULL WHERE reception_id = ANY\\(\\$1\\)").
+			WithArgs(ids).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM product_status_history h USING products p (.+) AND p.reception_id = ANY\\(\\$1\\)").
+			WithArgs(ids).
+			WillReturnResult(sqlmock.NewResult(0, 12))
+		mock.ExpectExec("DELETE FROM product_keys k USING products p WHERE p.id = k.id AND p.reception_id = ANY\\(\\$1\\)").
+			WithArgs(ids).
+			WillReturnResult(sqlmock.NewResult(0, 5))
+		mock.ExpectExec("DELETE FROM products WHERE reception_id = ANY\\(\\$1\\)").
+			WithArgs(ids).
+			WillReturnResult(sqlmock.NewResult(0, 5))
+		mock.ExpectExec("DELETE FROM receptions WHERE id = ANY\\(\\$1\\)").
+			WithArgs(ids).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		receptions, products, photoKeys, err := repo.ArchiveReceptions(context.Background(), closedBefore, archivedAt, 100)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, receptions)
+		assert.Equal(t, 5, products)
+		assert.Equal(t, []string{"products/a.jpg"}, photoKeys)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NothingToArchive", func(t *testing.T) {
+		db, mock, err := sqlmock.New(pgxArgs)
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		repo := &Postgres{db: db}
+
+		mock.ExpectBegin()
+		expectCandidates(mock).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectCommit()
+
+		receptions, products, photoKeys, err := repo.ArchiveReceptions(context.Background(), closedBefore, archivedAt, 100)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, receptions)
+		assert.Equal(t, 0, products)
+		assert.Empty(t, photoKeys)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("RollbackOnError", func(t *testing.T) {
+		db, mock, err := sqlmock.New(pgxArgs)
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		repo := &Postgres{db: db}
+
+		mock.ExpectBegin()
+		expectCandidates(mock).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(ids[0]))
+		mock.ExpectExec("INSERT INTO receptions_archive").
+			WillReturnError(errors.New("database error"))
+		mock.ExpectRollback()
+
+		receptions, products, photoKeys, err := repo.ArchiveReceptions(context.Background(), closedBefore, archivedAt, 100)
+		assert.Error(t, err)
+		assert.Equal(t, 0, receptions)
+		assert.Equal(t, 0, products)
+		assert.Empty(t, photoKeys)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetArchivedReceptions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	pvzID := uuid.New()
+	from := time.Now().AddDate(-2, 0, 0)
+	to := time.Now()
+	reception := models.Reception{ID: uuid.New(), DateTime: from.Add(time.Hour), PVZID: pvzID, Status: models.ReceptionStatusClose}
+
+	mock.ExpectQuery("SELECT id, date_time, pvz_id, status FROM receptions_archive WHERE pvz_id = \\$1 AND date_time BETWEEN \\$2 AND \\$3 (.+) LIMIT \\$4 OFFSET \\$5").
+		WithArgs(pvzID, from, to, 10, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}).
+			AddRow(reception.ID, reception.DateTime, reception.PVZID, reception.Status))
+
+	receptions, err := repo.GetArchivedReceptions(context.Background(), pvzID, from, to, 10, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Reception{reception}, receptions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
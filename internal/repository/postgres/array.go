@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// arrayScanner reads a PostgreSQL array into the slice dst points to.
+// pgx takes slices as query arguments directly, but hands array columns to
+// database/sql in the text format, which pgtype parses into the elements.
+type arrayScanner struct {
+	oid uint32
+	dst any
+}
+
+// uuidArray scans a uuid[] column into *[]uuid.UUID
+func uuidArray(dst any) sql.Scanner {
+	return arrayScanner{oid: pgtype.UUIDArrayOID, dst: dst}
+}
+
+// textArray scans a text[] column into *[]string
+func textArray(dst any) sql.Scanner {
+	return arrayScanner{oid: pgtype.TextArrayOID, dst: dst}
+}
+
+func (a arrayScanner) Scan(src any) error {
+	var buf []byte
+	switch src := src.(type) {
+	case nil:
+	case string:
+		buf = []byte(src)
+	case []byte:
+		buf = src
+	default:
+		return fmt.Errorf("cannot scan %T into array", src)
+	}
+
+	// Map caches scan plans and is not safe for concurrent use
+	return pgtype.NewMap().Scan(a.oid, pgtype.TextFormatCode, buf, a.dst)
+}
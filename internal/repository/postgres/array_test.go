@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArrayScanner(t *testing.T) {
+	t.Run("UUIDs", func(t *testing.T) {
+		first, second := uuid.New(), uuid.New()
+
+		var ids []uuid.UUID
+		err := uuidArray(&ids).Scan("{" + first.String() + "," + second.String() + "}")
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{first, second}, ids)
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		var values []string
+		err := textArray(&values).Scan([]byte(`{product.added,"with space"}`))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"product.added", "with space"}, values)
+	})
+
+	t.Run("Null", func(t *testing.T) {
+		values := []string{"stale"}
+		assert.NoError(t, textArray(&values).Scan(nil))
+		assert.Nil(t, values)
+	})
+
+	t.Run("UnsupportedSource", func(t *testing.T) {
+		var values []string
+		assert.Error(t, textArray(&values).Scan(42))
+	})
+}
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// copyFunc bulk-inserts rows into the columns of table with COPY
+type copyFunc func(table string, columns []string, rows [][]any) error
+
+// withCopyTx is withTx for bulk inserts. The transaction runs on a
+// dedicated connection, and copyRows sends COPY on it, so the copied rows
+// commit or roll back together with the rest of fn.
+func (p *Postgres) withCopyTx(ctx context.Context, fn func(tx *sql.Tx, copyRows copyFunc) error) error {
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	copyRows := p.copyRows
+	if copyRows == nil {
+		copyRows = pgxCopyRows
+	}
+
+	err = fn(tx, func(table string, columns []string, rows [][]any) error {
+		if len(rows) == 0 {
+			return nil
+		}
+		return copyRows(ctx, conn, table, columns, rows)
+	})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// pgxCopyRows runs COPY with the pgx connection under conn
+func pgxCopyRows(ctx context.Context, conn *sql.Conn, table string, columns []string, rows [][]any) error {
+	return conn.Raw(func(driverConn any) error {
+		c, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("copy into %s: %T is not a pgx connection", table, driverConn)
+		}
+
+		_, err := c.Conn().CopyFrom(ctx, pgx.Identifier{table}, columns, pgx.CopyFromRows(rows))
+		return err
+	})
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"pvz-service/internal/models"
+	"time"
+)
+
+func (p *Postgres) ReserveIdempotencyKey(ctx context.Context, key, fingerprint string, ttl, lease time.Duration) (*models.IdempotencyRecord, error) {
+	// An expired record is taken over as if the key were new, and so is one
+	// whose request was lost before completing, if the retry is the same
+	var reserved string
+	err := p.db.QueryRowContext(ctx,
+		`INSERT INTO idempotency_keys (key, fingerprint, created_at, expires_at, locked_until)
+		 VALUES ($1, $2, NOW(), NOW() + $3 * INTERVAL '1 millisecond', NOW() + $4 * INTERVAL '1 millisecond')
+		 ON CONFLICT (key) DO UPDATE
+		 SET fingerprint = EXCLUDED.fingerprint, status_code = NULL, content_type = NULL, response_body = NULL,
+		     created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at, locked_until = EXCLUDED.locked_until
+		 WHERE idempotency_keys.expires_at <= NOW()
+		 OR (idempotency_keys.status_code IS NULL AND idempotency_keys.locked_until <= NOW()
+		     AND idempotency_keys.fingerprint = EXCLUDED.fingerprint)
+		 RETURNING key`,
+		key, fingerprint, ttl.Milliseconds(), lease.Milliseconds()).Scan(&reserved)
+	if err == nil {
+		return nil, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	var (
+		record      models.IdempotencyRecord
+		statusCode  sql.NullInt64
+		contentType sql.NullString
+	)
+	err = p.db.QueryRowContext(ctx,
+		`SELECT key, fingerprint, status_code, content_type, response_body, created_at, expires_at, locked_until
+		 FROM idempotency_keys
+		 WHERE key = $1`,
+		key).Scan(&record.Key, &record.Fingerprint, &statusCode, &contentType, &record.Body, &record.CreatedAt, &record.ExpiresAt, &record.LockedUntil)
+	if err != nil {
+		return nil, err
+	}
+
+	record.StatusCode = int(statusCode.Int64)
+	record.ContentType = contentType.String
+
+	return &record, nil
+}
+
+func (p *Postgres) SaveIdempotencyResponse(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	_, err := p.db.ExecContext(ctx,
+		`UPDATE idempotency_keys
+		 SET status_code = $1, content_type = $2, response_body = $3
+		 WHERE key = $4`,
+		statusCode, contentType, body, key)
+	return err
+}
+
+func (p *Postgres) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	_, err := p.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE key = $1", key)
+	return err
+}
+
+func (p *Postgres) DeleteExpiredIdempotencyKeys(ctx context.Context) (int, error) {
+	res, err := p.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE expires_at <= NOW()")
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := res.RowsAffected()
+	return int(affected), err
+}
@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReserveIdempotencyKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+	now := time.Now()
+
+	t.Run("Reserved", func(t *testing.T) {
+		mock.ExpectQuery("INSERT INTO idempotency_keys (.+) ON CONFLICT \\(key\\) DO UPDATE (.+) WHERE idempotency_keys.expires_at <= NOW\\(\\) OR \\(idempotency_keys.status_code IS NULL AND idempotency_keys.locked_until <= NOW\\(\\) AND idempotency_keys.fingerprint = EXCLUDED.fingerprint\\) RETURNING key").
+			WithArgs("key", "fp", int64(3600000), int64(30000)).
+			WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow("key"))
+
+		record, err := repo.ReserveIdempotencyKey(context.Background(), "key", "fp", time.Hour, 30*time.Second)
+		assert.NoError(t, err)
+		assert.Nil(t, record)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Completed", func(t *testing.T) {
+		mock.ExpectQuery("INSERT INTO idempotency_keys").
+			WithArgs("key", "fp", int64(3600000), int64(30000)).
+			WillReturnRows(sqlmock.NewRows([]string{"key"}))
+		mock.ExpectQuery("SELECT (.+) FROM idempotency_keys WHERE key = \\$1").
+			WithArgs("key").
+			WillReturnRows(sqlmock.NewRows([]string{"key", "fingerprint", "status_code", "content_type", "response_body", "created_at", "expires_at", "locked_until"}).
+				AddRow("key", "fp", 201, "application/json", []byte(`{}`), now, now.Add(time.Hour), now.Add(30*time.Second)))
+
+		record, err := repo.ReserveIdempotencyKey(context.Background(), "key", "fp", time.Hour, 30*time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, 201, record.StatusCode)
+		assert.Equal(t, "application/json", record.ContentType)
+		assert.True(t, record.Completed())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("InProgress", func(t *testing.T) {
+		mock.ExpectQuery("INSERT INTO idempotency_keys").
+			WithArgs("key", "fp", int64(3600000), int64(30000)).
+			WillReturnRows(sqlmock.NewRows([]string{"key"}))
+		mock.ExpectQuery("SELECT (.+) FROM idempotency_keys WHERE key = \\$1").
+			WithArgs("key").
+			WillReturnRows(sqlmock.NewRows([]string{"key", "fingerprint", "status_code", "content_type", "response_body", "created_at", "expires_at", "locked_until"}).
+				AddRow("key", "fp", nil, nil, nil, now, now.Add(time.Hour), now.Add(30*time.Second)))
+
+		record, err := repo.ReserveIdempotencyKey(context.Background(), "key", "fp", time.Hour, 30*time.Second)
+		assert.NoError(t, err)
+		assert.False(t, record.Completed())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSaveIdempotencyResponse(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	mock.ExpectExec("UPDATE idempotency_keys SET status_code = \\$1, content_type = \\$2, response_body = \\$3 WHERE key = \\$4").
+		WithArgs(201, "application/json", []byte(`{}`), "key").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.SaveIdempotencyResponse(context.Background(), "key", 201, "application/json", []byte(`{}`)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteExpiredIdempotencyKeys(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	mock.ExpectExec("DELETE FROM idempotency_keys WHERE expires_at <= NOW\\(\\)").
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	deleted, err := repo.DeleteExpiredIdempotencyKeys(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 4, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
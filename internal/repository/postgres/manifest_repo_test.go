@@ -0,0 +1,247 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+	"time"
+
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateManifest(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := copied{}
+	repo := &Postgres{db: db, copyRows: rows.copyRows}
+
+	manifest := &models.Manifest{
+		ID:       uuid.New(),
+		PVZID:    uuid.New(),
+		Supplier: "ООО Поставщик",
+		Status:   models.ManifestStatusPending,
+		Items: []models.ManifestItem{
+			{Barcode: "4600000000017", TypeID: 1},
+			{Barcode: "4600000000024", TypeID: 2},
+		},
+		CreatedAt: time.Now(),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO manifests").
+		WithArgs(manifest.ID, manifest.PVZID, manifest.Supplier, manifest.Status, manifest.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.CreateManifest(context.Background(), manifest))
+	assert.Equal(t, [][]any{
+		{manifest.ID, "4600000000017", 1},
+		{manifest.ID, "4600000000024", 2},
+	}, rows["manifest_items"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetManifest(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	manifestID := uuid.New()
+	pvzID := uuid.New()
+	receptionID := uuid.New()
+	now := time.Now()
+	columns := []string{"id", "pvz_id", "supplier", "status", "reception_id", "report", "created_at", "reconciled_at"}
+
+	t.Run("Reconciled", func(t *testing.T) {
+		report, _ := json.Marshal(models.DiscrepancyReport{
+			Expected:   2,
+			Received:   1,
+			Missing:    []models.ManifestItem{{Barcode: "4600000000024", TypeName: "одежда"}},
+			Unexpected: []string{},
+			Duplicates: []models.DuplicateItem{},
+		})
+
+		mock.ExpectQuery("SELECT (.+) FROM manifests WHERE id = \\$1").
+			WithArgs(manifestID).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(manifestID, pvzID, "ООО Поставщик", models.ManifestStatusReconciled, receptionID, report, now, now))
+		mock.ExpectQuery("SELECT mi.manifest_id, mi.barcode, mi.type_id, pt.name FROM manifest_items mi").
+			WithArgs(sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"manifest_id", "barcode", "type_id", "name"}).
+				AddRow(manifestID, "4600000000017", 1, "электроника").
+				AddRow(manifestID, "4600000000024", 2, "одежда"))
+
+		manifest, err := repo.GetManifest(context.Background(), manifestID)
+		assert.NoError(t, err)
+		assert.Equal(t, receptionID, *manifest.ReceptionID)
+		assert.Len(t, manifest.Items, 2)
+		assert.Equal(t, "электроника", manifest.Items[0].TypeName)
+		if assert.NotNil(t, manifest.Report) {
+			assert.Equal(t, 2, manifest.Report.Expected)
+			assert.Equal(t, "4600000000024", manifest.Report.Missing[0].Barcode)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Pending", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM manifests").
+			WithArgs(manifestID).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(manifestID, pvzID, "ООО Поставщик", models.ManifestStatusPending, nil, nil, now, nil))
+		mock.ExpectQuery("SELECT (.+) FROM manifest_items mi").
+			WillReturnRows(sqlmock.NewRows([]string{"manifest_id", "barcode", "type_id", "name"}))
+
+		manifest, err := repo.GetManifest(context.Background(), manifestID)
+		assert.NoError(t, err)
+		assert.Nil(t, manifest.ReceptionID)
+		assert.Nil(t, manifest.Report)
+		assert.Equal(t, []models.ManifestItem{}, manifest.Items)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM manifests").
+			WithArgs(manifestID).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		_, err := repo.GetManifest(context.Background(), manifestID)
+		assert.Equal(t, e.ErrNotFound(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestInsertReception_WithManifest(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	manifestID := uuid.New()
+	reception := &models.Reception{
+		ID:         uuid.New(),
+		DateTime:   time.Now(),
+		PVZID:      uuid.New(),
+		Status:     models.ReceptionStatusInProgress,
+		ManifestID: &manifestID,
+	}
+
+	t.Run("Linked", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO receptions").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("UPDATE manifests SET status = \\$1, reception_id = \\$2 WHERE id = \\$3 AND pvz_id = \\$4 AND status = \\$5").
+			WithArgs(models.ManifestStatusReceiving, reception.ID, manifestID, reception.PVZID, models.ManifestStatusPending).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO outbox").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.InsertReception(context.Background(), reception))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("AlreadyUsed", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO receptions").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("UPDATE manifests SET status").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.InsertReception(context.Background(), reception)
+		assert.Equal(t, e.ErrManifestNotAvailable(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUpdateReceptionStatus_ReconcilesManifest(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	receptionID := uuid.New()
+	pvzID := uuid.New()
+	manifestID := uuid.New()
+	startedAt := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE receptions SET status").
+		WithArgs(models.ReceptionStatusClose, receptionID, 4).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status", "version"}).
+			AddRow(receptionID, startedAt, pvzID, models.ReceptionStatusClose, 5))
+	mock.ExpectExec("WITH stored AS").
+		WillReturnResult(sqlmock.NewResult(0, 5))
+	mock.ExpectQuery("SELECT id FROM manifests WHERE reception_id = \\$1").
+		WithArgs(receptionID, models.ManifestStatusReceiving).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(manifestID))
+	mock.ExpectQuery("SELECT (.+) FROM manifest_items mi").
+		WillReturnRows(sqlmock.NewRows([]string{"manifest_id", "barcode", "type_id", "name"}).
+			AddRow(manifestID, "A1", 1, "электроника").
+			AddRow(manifestID, "B2", 2, "одежда").
+			AddRow(manifestID, "C3", 3, "обувь"))
+	mock.ExpectQuery("SELECT barcode, COUNT\\(\\*\\) FROM products WHERE reception_id = \\$1 AND date_time >= \\$2 GROUP BY barcode").
+		WithArgs(receptionID, startedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"barcode", "count"}).
+			AddRow("A1", 1).
+			AddRow("B2", 2).
+			AddRow("Z9", 1).
+			AddRow(nil, 1))
+	mock.ExpectExec("UPDATE manifests SET status = \\$1, report = \\$2, reconciled_at = \\$3 WHERE id = \\$4").
+		WithArgs(models.ManifestStatusReconciled, sqlmock.AnyArg(), sqlmock.AnyArg(), manifestID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO outbox").
+		WithArgs(sqlmock.AnyArg(), models.EventReceptionClosed, pvzID, receptionID, reportWith{func(r models.DiscrepancyReport) bool {
+			return r.Expected == 3 && r.Received == 5 &&
+				len(r.Missing) == 1 && r.Missing[0].Barcode == "C3" &&
+				len(r.Unexpected) == 1 && r.Unexpected[0] == "Z9" &&
+				len(r.Duplicates) == 1 && r.Duplicates[0] == models.DuplicateItem{Barcode: "B2", Count: 2} &&
+				r.Unlabeled == 1
+		}}, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	version, err := repo.UpdateReceptionStatus(context.Background(), receptionID, models.ReceptionStatusClose, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// reportWith matches a reception event payload whose discrepancy report
+// satisfies fn
+type reportWith struct {
+	fn func(models.DiscrepancyReport) bool
+}
+
+func (m reportWith) Match(v driver.Value) bool {
+	data, ok := v.([]byte)
+	if !ok {
+		return false
+	}
+
+	var reception models.Reception
+	if err := json.Unmarshal(data, &reception); err != nil || reception.Discrepancies == nil {
+		return false
+	}
+	return m.fn(*reception.Discrepancies)
+}
@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pvz-service/internal/config"
+	"pvz-service/internal/repository/migrations"
+
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/database"
+	"github.com/pressly/goose/v3/lock"
+)
+
+// NewMigrator connects to the server described by cfg.Database for the
+// migrate command. Closing the returned provider closes the connection.
+func NewMigrator(cfg *config.Config, log *slog.Logger) (*goose.Provider, error) {
+	const op = "repository.postgres.NewMigrator"
+
+	provider, _, err := openMigrator(cfg.Database, log)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return provider, nil
+}
+
+// openMigrator connects a goose provider on a connection of its own.
+// Migrations may rewrite large tables, which takes longer than the statement
+// timeout of the service queries, so the connection has none. The returned
+// database is the one the provider migrates.
+func openMigrator(cfg config.Database, log *slog.Logger) (*goose.Provider, *sql.DB, error) {
+	cfg.StatementTimeout = 0
+
+	db, err := sql.Open("pgx", dsn(cfg))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := connect(db, cfg, log); err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+
+	provider, err := newProvider(db)
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+
+	return provider, db, nil
+}
+
+// newProvider returns a goose provider for the embedded migrations. The
+// session lock keeps instances that start together from migrating at once.
+// Migrations newer than the manual ones may be applied before them, so the
+// provider accepts older versions left unapplied.
+func newProvider(db *sql.DB) (*goose.Provider, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, err
+	}
+
+	return goose.NewProvider(goose.DialectPostgres, db, migrations.FS,
+		goose.WithSessionLocker(locker),
+		goose.WithAllowOutofOrder(true),
+		goose.WithGoMigrations(migrations.GoMigrations()...),
+	)
+}
+
+// migrate brings the schema up to date. With auto-migration off it only
+// checks that nothing is pending, so the service does not run against a
+// schema older than its queries. Either way it runs on a connection apart
+// from the pool of the repository, without the statement timeout.
+//
+// Migrations copying whole tables are left to the migrate command: the
+// service skips them, applies the rest and works with the tables as they
+// were until they are applied. An empty database has nothing to copy and is
+// migrated in full.
+func migrate(cfg config.Database, log *slog.Logger) error {
+	const op = "repository.postgres.migrate"
+
+	provider, db, err := openMigrator(cfg, log)
+	if err != nil {
+		return fmt.Errorf("%s: migration error: %w", op, err)
+	}
+	defer provider.Close()
+
+	ctx := context.Background()
+
+	// Neither call waits for the session lock, which a running migrate
+	// command holds until it is done. GetVersions also creates the version
+	// table on an empty database.
+	current, _, err := provider.GetVersions(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: migration error: %w", op, err)
+	}
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return fmt.Errorf("%s: migration error: %w", op, err)
+	}
+
+	var pending, manual []int64
+	for _, source := range provider.ListSources() {
+		switch {
+		case applied[source.Version]:
+		case current > 0 && migrations.IsManual(source.Version):
+			manual = append(manual, source.Version)
+		default:
+			pending = append(pending, source.Version)
+		}
+	}
+
+	if len(pending) > 0 && !cfg.AutoMigrate {
+		return fmt.Errorf("%s: schema has pending migrations and auto-migration is off, run the migrate up command", op)
+	}
+
+	for _, version := range pending {
+		result, err := provider.ApplyVersion(ctx, version, true)
+		if errors.Is(err, goose.ErrAlreadyApplied) {
+			// Another instance got there first
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: migration error: %w", op, err)
+		}
+
+		log.Info("migration applied",
+			slog.Int64("version", result.Source.Version),
+			slog.Duration("duration", result.Duration),
+		)
+	}
+
+	if len(manual) > 0 {
+		log.Warn("migrations are applied by the migrate up command only",
+			slog.Any("versions", manual),
+		)
+	}
+
+	return nil
+}
+
+// appliedVersions returns the versions recorded as applied in the goose
+// version table
+func appliedVersions(ctx context.Context, db *sql.DB) (map[int64]bool, error) {
+	store, err := database.NewStore(goose.DialectPostgres, goose.DefaultTablename)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := store.ListMigrations(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]bool, len(list))
+	for _, m := range list {
+		if m.IsApplied {
+			applied[m.Version] = true
+		}
+	}
+	return applied, nil
+}
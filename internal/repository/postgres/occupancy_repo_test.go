@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPVZOccupancy(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	pvzID := uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery("SELECT capacity FROM pvz WHERE id = \\$1 AND deleted_at IS NULL").
+			WithArgs(pvzID).
+			WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(100))
+		mock.ExpectQuery("SELECT pt.name, COALESCE\\(o.count, 0\\), COALESCE\\(tc.capacity, 0\\) FROM product_types pt").
+			WithArgs(pvzID).
+			WillReturnRows(sqlmock.NewRows([]string{"name", "count", "capacity"}).
+				AddRow("электроника", 4, 0).
+				AddRow("одежда", 0, 0).
+				AddRow("обувь", 10, 10))
+
+		occupancy, err := repo.GetPVZOccupancy(context.Background(), pvzID)
+		assert.NoError(t, err)
+		assert.Equal(t, &models.PVZOccupancy{
+			PVZID:    pvzID,
+			Count:    14,
+			Capacity: 100,
+			Types: []models.TypeOccupancy{
+				{TypeName: "электроника", Count: 4},
+				{TypeName: "одежда"},
+				{TypeName: "обувь", Count: 10, Capacity: 10},
+			},
+		}, occupancy)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mock.ExpectQuery("SELECT capacity FROM pvz").
+			WithArgs(pvzID).
+			WillReturnRows(sqlmock.NewRows([]string{"capacity"}))
+
+		_, err := repo.GetPVZOccupancy(context.Background(), pvzID)
+		assert.Equal(t, e.ErrNotFound(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestListPVZOccupancy(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	firstID, secondID := uuid.New(), uuid.New()
+	mock.ExpectQuery("SELECT p.id, p.capacity, COALESCE\\(SUM\\(o.count\\), 0\\) FROM pvz p").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "capacity", "count"}).
+			AddRow(firstID, 50, 25).
+			AddRow(secondID, 0, 3))
+
+	occupancy, err := repo.ListPVZOccupancy(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []models.PVZOccupancy{
+		{PVZID: firstID, Count: 25, Capacity: 50},
+		{PVZID: secondID, Count: 3},
+	}, occupancy)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReplaceTypeCapacities(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	pvzID := uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		rows := copied{}
+		repo.copyRows = rows.copyRows
+
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM pvz_type_capacities WHERE pvz_id = \\$1").
+			WithArgs(pvzID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		err := repo.ReplaceTypeCapacities(context.Background(), pvzID, []models.TypeCapacity{
+			{TypeID: 1, Capacity: 30},
+			{TypeID: 3, Capacity: 10},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]any{{pvzID, 1, 30}, {pvzID, 3, 10}}, rows["pvz_type_capacities"])
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("CopyFailureRollsBack", func(t *testing.T) {
+		repo.copyRows = func(context.Context, *sql.Conn, string, []string, [][]any) error {
+			return assert.AnError
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM pvz_type_capacities").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.ReplaceTypeCapacities(context.Background(), pvzID, []models.TypeCapacity{{TypeID: 1, Capacity: 30}})
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
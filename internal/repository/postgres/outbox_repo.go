@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"pvz-service/internal/models"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// withTx runs fn inside a transaction and commits it if fn succeeds
+func (p *Postgres) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const insertEventQuery = "INSERT INTO outbox (id, event_type, pvz_id, reception_id, payload, created_at) VALUES ($1, $2, $3, $4, $5, $6)"
+
+// insertEvent records an event in the outbox as part of tx
+func insertEvent(ctx context.Context, tx *sql.Tx, eventType models.EventType, pvzID, receptionID uuid.UUID, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event: %w", eventType, err)
+	}
+
+	_, err = tx.ExecContext(ctx, insertEventQuery,
+		uuid.New(), eventType, pvzID, receptionID, data, time.Now())
+	return err
+}
+
+func (p *Postgres) ClaimOutboxEvents(ctx context.Context, limit int, lease time.Duration) ([]models.Event, error) {
+	// The claim commits right away, the events are published outside of it
+	rows, err := p.db.QueryContext(ctx,
+		`UPDATE outbox
+		 SET next_attempt_at = NOW() + $2 * INTERVAL '1 millisecond'
+		 WHERE id IN (
+			 SELECT id FROM outbox
+			 WHERE published_at IS NULL AND dead_at IS NULL AND next_attempt_at <= NOW()
+			 ORDER BY created_at
+			 LIMIT $1
+			 FOR UPDATE SKIP LOCKED
+		 )
+		 RETURNING id, event_type, pvz_id, reception_id, payload, created_at, attempts`,
+		limit, lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.Event
+	for rows.Next() {
+		var ev models.Event
+		if err := rows.Scan(&ev.ID, &ev.Type, &ev.PVZID, &ev.ReceptionID, &ev.Payload, &ev.CreatedAt, &ev.Attempts); err != nil {
+			return nil, err
+		}
+		events = append(events, ev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING keeps no order
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].CreatedAt.Before(events[j].CreatedAt)
+	})
+	return events, nil
+}
+
+func (p *Postgres) MarkEventPublished(ctx context.Context, id uuid.UUID) error {
+	_, err := p.db.ExecContext(ctx,
+		"UPDATE outbox SET attempts = attempts + 1, last_error = NULL, published_at = $1 WHERE id = $2",
+		time.Now(), id)
+	return err
+}
+
+func (p *Postgres) MarkEventFailed(ctx context.Context, id uuid.UUID, lastErr string, nextAttemptAt time.Time, dead bool) error {
+	var deadAt *time.Time
+	if dead {
+		now := time.Now()
+		deadAt = &now
+	}
+
+	_, err := p.db.ExecContext(ctx,
+		"UPDATE outbox SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2, dead_at = $3 WHERE id = $4",
+		lastErr, nextAttemptAt, deadAt, id)
+	return err
+}
+
+func (p *Postgres) PurgePublishedEvents(ctx context.Context, before time.Time) (int, error) {
+	res, err := p.db.ExecContext(ctx, "DELETE FROM outbox WHERE published_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := res.RowsAffected()
+	return int(affected), err
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"pvz-service/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClaimOutboxEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	columns := []string{"id", "event_type", "pvz_id", "reception_id", "payload", "created_at", "attempts"}
+	firstID, secondID := uuid.New(), uuid.New()
+	pvzID, receptionID := uuid.New(), uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery("UPDATE outbox SET next_attempt_at = (.+) WHERE id IN (.+) WHERE published_at IS NULL AND dead_at IS NULL AND next_attempt_at <= NOW\\(\\) ORDER BY created_at LIMIT \\$1 FOR UPDATE SKIP LOCKED (.+) RETURNING (.+)").
+		WithArgs(10, int64(10000)).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(secondID, models.EventProductAdded, pvzID, receptionID, []byte(`{}`), now.Add(time.Second), 0).
+			AddRow(firstID, models.EventReceptionOpened, pvzID, receptionID, []byte(`{}`), now, 2))
+
+	events, err := repo.ClaimOutboxEvents(context.Background(), 10, 10*time.Second)
+	assert.NoError(t, err)
+	if assert.Len(t, events, 2) {
+		// Oldest first, whatever order RETURNING gives
+		assert.Equal(t, firstID, events[0].ID)
+		assert.Equal(t, 2, events[0].Attempts)
+		assert.Equal(t, secondID, events[1].ID)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkEventFailed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+	id := uuid.New()
+	next := time.Now().Add(time.Minute)
+
+	t.Run("Retried", func(t *testing.T) {
+		mock.ExpectExec("UPDATE outbox SET attempts = attempts \\+ 1, last_error = \\$1, next_attempt_at = \\$2, dead_at = \\$3 WHERE id = \\$4").
+			WithArgs("boom", next, nil, id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.MarkEventFailed(context.Background(), id, "boom", next, false))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Parked", func(t *testing.T) {
+		mock.ExpectExec("UPDATE outbox SET (.+) dead_at = \\$3 WHERE id = \\$4").
+			WithArgs("boom", next, sqlmock.AnyArg(), id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.MarkEventFailed(context.Background(), id, "boom", next, true))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPurgePublishedEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+	before := time.Now().Add(-time.Hour)
+
+	mock.ExpectExec("DELETE FROM outbox WHERE published_at < \\$1").
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	deleted, err := repo.PurgePublishedEvents(context.Background(), before)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// partitionedTables are split into monthly partitions on date_time by
+// ensure_monthly_partition
+var partitionedTables = []string{"receptions", "products"}
+
+func (p *Postgres) EnsurePartitions(ctx context.Context, from time.Time, months int) ([]string, error) {
+	from = from.UTC()
+
+	upcoming := make([]time.Time, 0, months+1)
+	for i := 0; i <= months; i++ {
+		upcoming = append(upcoming, time.Date(from.Year(), from.Month()+time.Month(i), 1, 0, 0, 0, 0, time.UTC))
+	}
+
+	created := []string{}
+	for _, table := range partitionedTables {
+		// Rows of months without a partition wait in the default one
+		stray, err := p.defaultPartitionMonths(ctx, table)
+		if err != nil {
+			return created, err
+		}
+
+		seen := map[time.Time]bool{}
+		for _, month := range append(stray, upcoming...) {
+			if seen[month] {
+				continue
+			}
+			seen[month] = true
+
+			// Every call runs in a transaction of its own, so a failure keeps
+			// the partitions created before it
+			var name sql.NullString
+			if err := p.db.QueryRowContext(ctx,
+				"SELECT ensure_monthly_partition($1, $2)",
+				table, month).Scan(&name); err != nil {
+				return created, err
+			}
+			if name.Valid {
+				created = append(created, name.String)
+			}
+		}
+	}
+	return created, nil
+}
+
+// defaultPartitionMonths returns the first moments (UTC) of the months that
+// have rows in the default partition of table
+func (p *Postgres) defaultPartitionMonths(ctx context.Context, table string) ([]time.Time, error) {
+	rows, err := p.db.QueryContext(ctx, fmt.Sprintf(
+		`SELECT DISTINCT date_trunc('month', date_time AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'
+		 FROM %s_default`, table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	months := []time.Time{}
+	for rows.Next() {
+		var month time.Time
+		if err := rows.Scan(&month); err != nil {
+			return nil, err
+		}
+		months = append(months, month.UTC())
+	}
+	return months, rows.Err()
+}
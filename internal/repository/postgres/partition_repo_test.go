@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnsurePartitions(t *testing.T) {
+	from := time.Date(2025, time.December, 20, 23, 30, 0, 0, time.FixedZone("MSK", 3*60*60))
+	december := time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)
+	january := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	june := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New(pgxArgs)
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		repo := &Postgres{db: db}
+
+		// A June reception waits in the default partition, the December
+		// product partition exists already
+		mock.ExpectQuery("SELECT DISTINCT date_trunc(.+) FROM receptions_default").
+			WillReturnRows(sqlmock.NewRows([]string{"month"}).AddRow(june))
+		mock.ExpectQuery("SELECT ensure_monthly_partition\\(\\$1, \\$2\\)").
+			WithArgs("receptions", june).
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("receptions_p2025_06"))
+		mock.ExpectQuery("SELECT ensure_monthly_partition\\(\\$1, \\$2\\)").
+			WithArgs("receptions", december).
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("receptions_p2025_12"))
+		mock.ExpectQuery("SELECT ensure_monthly_partition\\(\\$1, \\$2\\)").
+			WithArgs("receptions", january).
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("receptions_p2026_01"))
+		mock.ExpectQuery("SELECT DISTINCT date_trunc(.+) FROM products_default").
+			WillReturnRows(sqlmock.NewRows([]string{"month"}).AddRow(december))
+		mock.ExpectQuery("SELECT ensure_monthly_partition\\(\\$1, \\$2\\)").
+			WithArgs("products", december).
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow(nil))
+		mock.ExpectQuery("SELECT ensure_monthly_partition\\(\\$1, \\$2\\)").
+			WithArgs("products", january).
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("products_p2026_01"))
+
+		created, err := repo.EnsurePartitions(context.Background(), from, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"receptions_p2025_06", "receptions_p2025_12", "receptions_p2026_01", "products_p2026_01"}, created)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("FailureKeepsCreated", func(t *testing.T) {
+		db, mock, err := sqlmock.New(pgxArgs)
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		repo := &Postgres{db: db}
+
+		mock.ExpectQuery("SELECT DISTINCT date_trunc(.+) FROM receptions_default").
+			WillReturnRows(sqlmock.NewRows([]string{"month"}))
+		mock.ExpectQuery("SELECT ensure_monthly_partition\\(\\$1, \\$2\\)").
+			WithArgs("receptions", december).
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("receptions_p2025_12"))
+		mock.ExpectQuery("SELECT ensure_monthly_partition\\(\\$1, \\$2\\)").
+			WithArgs("receptions", january).
+			WillReturnError(assert.AnError)
+
+		created, err := repo.EnsurePartitions(context.Background(), from, 1)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, []string{"receptions_p2025_12"}, created)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -1,80 +1,168 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"log/slog"
 	"pvz-service/internal/config"
+	"pvz-service/internal/logger/sl"
+	"sync/atomic"
+	"time"
 
-	"github.com/pressly/goose/v3"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 )
 
 type Postgres struct {
 	db *sql.DB
-}
 
-var repo *Postgres
+	// replicas serve the listing queries, nil without replicas
+	replicas *replicaSet
 
-func newPostgresRepository(cfg *config.Config) (*Postgres, error) {
-	const op = "repository.postgres.NewPostgresRepository"
+	// migrated is set once the schema is up to date, from then on new
+	// connections prepare the hot queries
+	migrated atomic.Bool
 
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Database.Host,
-		cfg.Database.Port,
-		cfg.Database.User,
-		cfg.Database.Password,
-		cfg.Database.Name,
-		cfg.Database.SSLMode,
-	)
+	// copyRows runs COPY on the driver connection, nil for pgxCopyRows
+	copyRows func(ctx context.Context, conn *sql.Conn, table string, columns []string, rows [][]any) error
+}
+
+// hotQueries are prepared on every new connection of the pool. pgx runs a
+// query by its prepared statement when the SQL matches, so the reception
+// paths skip parsing and planning from the first call. Other queries are
+// prepared on first use by the pgx statement cache.
+var hotQueries = []string{
+	activeReceptionQuery,
+	lastProductQuery,
+	bumpReceptionVersionQuery,
+	reserveCapacityQuery,
+	adjustOccupancyQuery,
+	insertProductKeyQuery,
+	insertProductQuery,
+	productDateQuery,
+	insertStatusChangeQuery,
+	insertEventQuery,
+}
 
-	db, err := sql.Open("postgres", dsn)
+// New connects to the server described by cfg.Database, waiting for it to
+// come up, configures the connection pool and applies the migrations unless
+// auto-migration is off. The
+// replicas, if any, are connected too. The returned repository owns the
+// pools and must be closed with CloseConnection.
+func New(cfg *config.Config, log *slog.Logger) (*Postgres, error) {
+	const op = "repository.postgres.New"
+
+	connConfig, err := pgx.ParseConfig(dsn(cfg.Database))
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = db.Ping()
-	if err != nil {
+	psg := &Postgres{}
+	db := stdlib.OpenDB(*connConfig, stdlib.OptionAfterConnect(psg.prepareHotQueries))
+	psg.db = db
+
+	db.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.Database.ConnMaxIdleTime)
+
+	if err := connect(db, cfg.Database, log); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	psg := Postgres{db: db}
-
-	err = psg.migrate()
-	if err != nil {
-		return nil, err
+	if err := migrate(cfg.Database, log); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	psg.migrated.Store(true)
+
+	if len(cfg.Database.Replicas) > 0 {
+		psg.replicas, err = openReplicas(cfg.Database, log)
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
 	}
 
-	return &psg, nil
+	return psg, nil
 }
 
-func (p *Postgres) migrate() error {
-	const op = "repository.postgres.migrate"
-
-	if err := goose.SetDialect("postgres"); err != nil {
-		return fmt.Errorf("%s: migration error: %w", op, err)
+// prepareHotQueries prepares hotQueries on a new connection. Naming a
+// statement by its SQL makes pgx use it for queries with the same text.
+func (p *Postgres) prepareHotQueries(ctx context.Context, conn *pgx.Conn) error {
+	if !p.migrated.Load() {
+		return nil
 	}
 
-	if err := goose.Up(p.db, "/migrations"); err != nil {
-		return fmt.Errorf("%s: migration error: %w", op, err)
+	for _, query := range hotQueries {
+		if _, err := conn.Prepare(ctx, query, query); err != nil {
+			return fmt.Errorf("prepare %q: %w", query, err)
+		}
 	}
-
 	return nil
 }
 
-func GetRepository(cfg *config.Config) (*Postgres, error) {
-	var err error = nil
+// dsn builds the connection string. The statement timeout is passed as a
+// run-time parameter, so it applies to every connection of the pool.
+func dsn(cfg config.Database) string {
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host,
+		cfg.Port,
+		cfg.User,
+		cfg.Password,
+		cfg.Name,
+		cfg.SSLMode,
+	)
 
-	if repo == nil {
-		repo, err = newPostgresRepository(cfg)
+	if cfg.StatementTimeout > 0 {
+		dsn = withParam(dsn, "statement_timeout", fmt.Sprint(cfg.StatementTimeout.Milliseconds()))
 	}
 
-	return repo, err
+	return dsn
+}
+
+// connect pings the server until it answers or the attempts run out. The
+// delay between attempts doubles from ConnectInitialBackoff up to
+// ConnectMaxBackoff.
+func connect(db *sql.DB, cfg config.Database, log *slog.Logger) error {
+	attempts := max(cfg.ConnectAttempts, 1)
+	delay := cfg.ConnectInitialBackoff
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = db.Ping(); err == nil {
+			return nil
+		}
+		if attempt == attempts {
+			return fmt.Errorf("no connection after %d attempts: %w", attempts, err)
+		}
+
+		log.Warn("database is unavailable, retrying",
+			slog.Int("attempt", attempt),
+			slog.Duration("delay", delay),
+			sl.Err(err),
+		)
+		time.Sleep(delay)
+
+		delay = min(delay*2, cfg.ConnectMaxBackoff)
+	}
 }
 
 func (p *Postgres) SetRepository(db *sql.DB) {
 	p.db = db
 }
 
+// Stats reports the state of the connection pool
+func (p *Postgres) Stats() sql.DBStats {
+	return p.db.Stats()
+}
+
 func (p *Postgres) CloseConnection() {
+	if p.replicas != nil {
+		p.replicas.close()
+	}
 	if p.db != nil {
 		p.db.Close()
 	}
@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	"pvz-service/internal/config"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDSN(t *testing.T) {
+	cfg := config.Database{
+		Host:     "db",
+		Port:     "5432",
+		User:     "postgres",
+		Password: "secret",
+		Name:     "pvz",
+		SSLMode:  "disable",
+	}
+
+	assert.Equal(t, "host=db port=5432 user=postgres password=secret dbname=pvz sslmode=disable", dsn(cfg))
+
+	cfg.StatementTimeout = 1500 * time.Millisecond
+	assert.Equal(t, "host=db port=5432 user=postgres password=secret dbname=pvz sslmode=disable statement_timeout=1500", dsn(cfg))
+}
+
+func TestConnect(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := config.Database{
+		ConnectAttempts:       3,
+		ConnectInitialBackoff: time.Millisecond,
+		ConnectMaxBackoff:     2 * time.Millisecond,
+	}
+	refused := errors.New("connection refused")
+
+	t.Run("Retries until the server answers", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectPing().WillReturnError(refused)
+		mock.ExpectPing().WillReturnError(refused)
+		mock.ExpectPing()
+
+		assert.NoError(t, connect(db, cfg, log))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Gives up after the last attempt", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+		require.NoError(t, err)
+		defer db.Close()
+
+		for range cfg.ConnectAttempts {
+			mock.ExpectPing().WillReturnError(refused)
+		}
+
+		err = connect(db, cfg, log)
+		assert.ErrorIs(t, err, refused)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// pgxArgs makes sqlmock take slice arguments as they are, the way the pgx
+// driver does, so tests can match them with WithArgs
+var pgxArgs = sqlmock.ValueConverterOption(pgxConverter{})
+
+type pgxConverter struct{}
+
+func (pgxConverter) ConvertValue(v any) (driver.Value, error) {
+	if reflect.ValueOf(v).Kind() == reflect.Slice {
+		if _, ok := v.([]byte); !ok {
+			return v, nil
+		}
+	}
+	return driver.DefaultParameterConverter.ConvertValue(v)
+}
+
+// copied records the rows sent with COPY in place of the pgx connection
+type copied map[string][][]any
+
+func (c copied) copyRows(_ context.Context, _ *sql.Conn, table string, _ []string, rows [][]any) error {
+	c[table] = append(c[table], rows...)
+	return nil
+}
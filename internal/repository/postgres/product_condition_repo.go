@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// scanProduct reads the columns selected by the product queries into product
+func scanProduct(row rowScanner, product *models.Product) error {
+	err := row.Scan(&product.ID, &product.DateTime, &product.TypeID, &product.TypeName, &product.ReceptionID, &product.Status,
+		&product.Barcode, &product.Condition, &product.ConditionNote, &product.PhotoKey)
+	if err != nil {
+		return err
+	}
+	product.HasPhoto = product.PhotoKey != ""
+	return nil
+}
+
+func (p *Postgres) SetProductCondition(ctx context.Context, productID uuid.UUID, condition models.ProductCondition, note string) (*models.Product, uuid.UUID, error) {
+	var (
+		product models.Product
+		pvzID   uuid.UUID
+	)
+
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		dateTime, err := productDate(ctx, tx, productID)
+		if err == e.ErrNotFound() {
+			return e.ErrProductNotInReception()
+		}
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRowContext(ctx,
+			`UPDATE products p SET condition = $1, condition_note = $2
+			 FROM receptions r
+			 WHERE p.id = $3 AND p.date_time = $6 AND p.status = $4
+			   AND r.id = p.reception_id AND r.date_time <= $6 AND r.status = $5
+			 RETURNING r.pvz_id`,
+			condition, note, productID, models.ProductStatusAccepted, models.ReceptionStatusInProgress, dateTime).Scan(&pvzID)
+		if err == sql.ErrNoRows {
+			return e.ErrProductNotInReception()
+		}
+		if err != nil {
+			return err
+		}
+
+		err = scanProduct(tx.QueryRowContext(ctx,
+			`SELECT p.id, p.date_time, p.type_id, pt.name, p.reception_id, p.status,
+			        COALESCE(p.barcode, ''), p.condition, p.condition_note, COALESCE(p.photo_key, '')
+			 FROM products p
+			 JOIN product_types pt ON p.type_id = pt.id
+			 WHERE p.id = $1 AND p.date_time = $2`,
+			productID, dateTime), &product)
+		if err != nil {
+			return err
+		}
+
+		return insertEvent(ctx, tx, models.EventProductConditionChanged, pvzID, product.ReceptionID, product)
+	})
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+
+	return &product, pvzID, nil
+}
+
+// SetProductPhoto attaches a photo to the condition report of a product in
+// an open reception and returns the key of the photo it replaced, if any
+func (p *Postgres) SetProductPhoto(ctx context.Context, productID uuid.UUID, photoKey string) (string, error) {
+	var previous string
+
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		dateTime, err := productDate(ctx, tx, productID)
+		if err == e.ErrNotFound() {
+			return e.ErrProductNotInReception()
+		}
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRowContext(ctx,
+			`SELECT COALESCE(p.photo_key, '')
+			 FROM products p
+			 JOIN receptions r ON r.id = p.reception_id AND r.date_time <= $4
+			 WHERE p.id = $1 AND p.date_time = $4 AND p.status = $2 AND r.status = $3
+			 FOR UPDATE OF p`,
+			productID, models.ProductStatusAccepted, models.ReceptionStatusInProgress, dateTime).Scan(&previous)
+		if err == sql.ErrNoRows {
+			return e.ErrProductNotInReception()
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "UPDATE products SET photo_key = $1 WHERE id = $2 AND date_time = $3", photoKey, productID, dateTime)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return previous, nil
+}
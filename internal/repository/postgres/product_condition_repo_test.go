@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetProductCondition(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	productID := uuid.New()
+	receptionID := uuid.New()
+	pvzID := uuid.New()
+	dateTime := time.Now()
+	columns := []string{"id", "date_time", "type_id", "name", "reception_id", "status", "barcode", "condition", "condition_note", "photo_key"}
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectProductDate(mock, productID, dateTime)
+		mock.ExpectQuery("UPDATE products p SET condition = \\$1, condition_note = \\$2 FROM receptions r WHERE p.id = \\$3 AND p.date_time = \\$6 AND p.status = \\$4 AND r.id = p.reception_id AND r.date_time <= \\$6 AND r.status = \\$5 RETURNING r.pvz_id").
+			WithArgs(models.ProductConditionDamaged, "разбит экран", productID, models.ProductStatusAccepted, models.ReceptionStatusInProgress, dateTime).
+			WillReturnRows(sqlmock.NewRows([]string{"pvz_id"}).AddRow(pvzID))
+		mock.ExpectQuery("SELECT (.+) FROM products p JOIN product_types pt ON p.type_id = pt.id WHERE p.id = \\$1 AND p.date_time = \\$2").
+			WithArgs(productID, dateTime).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(productID, dateTime, 1, "электроника", receptionID, models.ProductStatusAccepted, "", models.ProductConditionDamaged, "разбит экран", ""))
+		mock.ExpectExec("INSERT INTO outbox").
+			WithArgs(sqlmock.AnyArg(), models.EventProductConditionChanged, pvzID, receptionID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		product, gotPVZID, err := repo.SetProductCondition(context.Background(), productID, models.ProductConditionDamaged, "разбит экран")
+		assert.NoError(t, err)
+		assert.Equal(t, pvzID, gotPVZID)
+		assert.Equal(t, models.ProductConditionDamaged, product.Condition)
+		assert.Equal(t, "разбит экран", product.ConditionNote)
+		assert.False(t, product.HasPhoto)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ReceptionClosed", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectProductDate(mock, productID, dateTime)
+		mock.ExpectQuery("UPDATE products p SET condition").
+			WillReturnRows(sqlmock.NewRows([]string{"pvz_id"}))
+		mock.ExpectRollback()
+
+		_, _, err := repo.SetProductCondition(context.Background(), productID, models.ProductConditionOK, "")
+		assert.Equal(t, e.ErrProductNotInReception(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("UnknownProduct", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT date_time FROM product_keys").
+			WithArgs(productID).
+			WillReturnRows(sqlmock.NewRows([]string{"date_time"}))
+		mock.ExpectRollback()
+
+		_, _, err := repo.SetProductCondition(context.Background(), productID, models.ProductConditionOK, "")
+		assert.Equal(t, e.ErrProductNotInReception(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSetProductPhoto(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	productID := uuid.New()
+	dateTime := time.Now()
+
+	t.Run("ReplacesPrevious", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectProductDate(mock, productID, dateTime)
+		mock.ExpectQuery("SELECT COALESCE\\(p.photo_key, ''\\) FROM products p JOIN receptions r ON r.id = p.reception_id AND r.date_time <= \\$4 WHERE p.id = \\$1 AND p.date_time = \\$4 AND p.status = \\$2 AND r.status = \\$3 FOR UPDATE OF p").
+			WithArgs(productID, models.ProductStatusAccepted, models.ReceptionStatusInProgress, dateTime).
+			WillReturnRows(sqlmock.NewRows([]string{"photo_key"}).AddRow("products/old.jpg"))
+		mock.ExpectExec("UPDATE products SET photo_key = \\$1 WHERE id = \\$2 AND date_time = \\$3").
+			WithArgs("products/new.jpg", productID, dateTime).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		previous, err := repo.SetProductPhoto(context.Background(), productID, "products/new.jpg")
+		assert.NoError(t, err)
+		assert.Equal(t, "products/old.jpg", previous)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ReceptionClosed", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectProductDate(mock, productID, dateTime)
+		mock.ExpectQuery("SELECT COALESCE\\(p.photo_key, ''\\) FROM products p").
+			WillReturnRows(sqlmock.NewRows([]string{"photo_key"}))
+		mock.ExpectRollback()
+
+		_, err := repo.SetProductPhoto(context.Background(), productID, "products/new.jpg")
+		assert.Equal(t, e.ErrProductNotInReception(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// rowQueryer is implemented by both *sql.DB and *sql.Tx
+type rowQueryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+const insertProductKeyQuery = "INSERT INTO product_keys (id, date_time) VALUES ($1, $2)"
+
+// productDateQuery finds the date_time of a product, which together with the
+// id makes up its key in the partitioned products table. Products the
+// partitioning backfill has not reached yet have no row in product_keys and
+// are read from products itself.
+const productDateQuery = `SELECT date_time FROM product_keys WHERE id = $1
+	UNION ALL
+	SELECT date_time FROM products WHERE id = $1
+	LIMIT 1`
+
+// productDate returns the date_time of the product for the queries to find
+// it by its full key, reading one partition instead of all of them
+func productDate(ctx context.Context, q rowQueryer, productID uuid.UUID) (time.Time, error) {
+	var dateTime time.Time
+	err := q.QueryRowContext(ctx, productDateQuery, productID).Scan(&dateTime)
+	if err == sql.ErrNoRows {
+		return time.Time{}, e.ErrNotFound()
+	}
+	return dateTime, err
+}
+
+func (p *Postgres) GetProduct(ctx context.Context, productID uuid.UUID) (*models.Product, error) {
+	dateTime, err := productDate(ctx, p.db, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	var product models.Product
+	err = scanProduct(p.db.QueryRowContext(ctx,
+		`SELECT p.id, p.date_time, p.type_id, pt.name, p.reception_id, p.status,
+		        COALESCE(p.barcode, ''), p.condition, p.condition_note, COALESCE(p.photo_key, '')
+		 FROM products p
+		 JOIN product_types pt ON p.type_id = pt.id
+		 WHERE p.id = $1 AND p.date_time = $2`,
+		productID, dateTime), &product)
+	if err == sql.ErrNoRows {
+		return nil, e.ErrNotFound()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (p *Postgres) ChangeProductStatus(ctx context.Context, change *models.ProductStatusChange) error {
+	return p.withTx(ctx, func(tx *sql.Tx) error {
+		dateTime, err := productDate(ctx, tx, change.ProductID)
+		if err == e.ErrNotFound() {
+			return e.ErrInvalidStatusTransition()
+		}
+		if err != nil {
+			return err
+		}
+
+		// A product never predates its reception
+		var typeID int
+		err = tx.QueryRowContext(ctx,
+			`UPDATE products p SET status = $1
+			 FROM receptions r
+			 WHERE p.id = $2 AND p.date_time = $4 AND p.status = $3
+			   AND r.id = p.reception_id AND r.date_time <= $4
+			 RETURNING p.reception_id, p.type_id, r.pvz_id`,
+			change.To, change.ProductID, change.From, dateTime).Scan(&change.ReceptionID, &typeID, &change.PVZID)
+		if err == sql.ErrNoRows {
+			return e.ErrInvalidStatusTransition()
+		}
+		if err != nil {
+			return err
+		}
+
+		// Issued and written-off products leave the PVZ, returned ones come back
+		if change.From.InPVZ() != change.To.InPVZ() {
+			delta := 1
+			if !change.To.InPVZ() {
+				delta = -1
+			}
+			if err := adjustOccupancy(ctx, tx, change.PVZID, typeID, delta); err != nil {
+				return err
+			}
+		}
+
+		return recordStatusChange(ctx, tx, change)
+	})
+}
+
+func (p *Postgres) GetProductHistory(ctx context.Context, productID uuid.UUID) ([]models.ProductStatusChange, error) {
+	rows, err := p.db.QueryContext(ctx,
+		`SELECT product_id, pvz_id, reception_id, COALESCE(from_status, ''), to_status, comment, changed_at
+		 FROM product_status_history
+		 WHERE product_id = $1
+		 ORDER BY changed_at, id`,
+		productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []models.ProductStatusChange{}
+	for rows.Next() {
+		var change models.ProductStatusChange
+		if err := rows.Scan(&change.ProductID, &change.PVZID, &change.ReceptionID, &change.From, &change.To, &change.Comment, &change.ChangedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, change)
+	}
+
+	return history, rows.Err()
+}
+
+const insertStatusChangeQuery = `INSERT INTO product_status_history (product_id, pvz_id, reception_id, from_status, to_status, comment, changed_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+// insertStatusChange appends an entry to the product history
+func insertStatusChange(ctx context.Context, tx *sql.Tx, change *models.ProductStatusChange) error {
+	from := sql.NullString{String: string(change.From), Valid: change.From != ""}
+
+	_, err := tx.ExecContext(ctx, insertStatusChangeQuery,
+		change.ProductID, change.PVZID, change.ReceptionID, from, change.To, change.Comment, change.ChangedAt)
+	return err
+}
+
+// storeAcceptedProducts puts the products of a closed reception, dated since
+// the reception started, on the shelves
+func storeAcceptedProducts(ctx context.Context, tx *sql.Tx, receptionID, pvzID uuid.UUID, since time.Time) error {
+	_, err := tx.ExecContext(ctx,
+		`WITH stored AS (
+			UPDATE products SET status = $2
+			WHERE reception_id = $1 AND date_time >= $6 AND status = $3
+			RETURNING id
+		 )
+		 INSERT INTO product_status_history (product_id, pvz_id, reception_id, from_status, to_status, changed_at)
+		 SELECT id, $4, $1, $3, $2, $5 FROM stored`,
+		receptionID, models.ProductStatusStored, models.ProductStatusAccepted, pvzID, time.Now(), since)
+	return err
+}
@@ -0,0 +1,195 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// expectProductDate expects the lookup of the date_time a product is keyed by
+func expectProductDate(mock sqlmock.Sqlmock, productID uuid.UUID, dateTime time.Time) {
+	mock.ExpectQuery("SELECT date_time FROM product_keys WHERE id = \\$1 UNION ALL SELECT date_time FROM products WHERE id = \\$1 LIMIT 1").
+		WithArgs(productID).
+		WillReturnRows(sqlmock.NewRows([]string{"date_time"}).AddRow(dateTime))
+}
+
+func TestGetProduct(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	productID := uuid.New()
+	receptionID := uuid.New()
+	now := time.Now()
+	columns := []string{"id", "date_time", "type_id", "name", "reception_id", "status", "barcode", "condition", "condition_note", "photo_key"}
+
+	t.Run("Success", func(t *testing.T) {
+		expectProductDate(mock, productID, now)
+		mock.ExpectQuery("SELECT p.id, p.date_time, p.type_id, pt.name, p.reception_id, p.status, COALESCE\\(p.barcode, ''\\), p.condition, p.condition_note, COALESCE\\(p.photo_key, ''\\) FROM products p JOIN product_types pt ON p.type_id = pt.id WHERE p.id = \\$1 AND p.date_time = \\$2").
+			WithArgs(productID, now).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(productID, now, 2, "одежда", receptionID, models.ProductStatusStored, "4600000000017", models.ProductConditionDamaged, "помята коробка", "products/1/photo.jpg"))
+
+		product, err := repo.GetProduct(context.Background(), productID)
+		assert.NoError(t, err)
+		assert.Equal(t, &models.Product{
+			ID:            productID,
+			DateTime:      now,
+			TypeID:        2,
+			TypeName:      "одежда",
+			ReceptionID:   receptionID,
+			Status:        models.ProductStatusStored,
+			Barcode:       "4600000000017",
+			Condition:     models.ProductConditionDamaged,
+			ConditionNote: "помята коробка",
+			PhotoKey:      "products/1/photo.jpg",
+			HasPhoto:      true,
+		}, product)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mock.ExpectQuery("SELECT date_time FROM product_keys").
+			WithArgs(productID).
+			WillReturnRows(sqlmock.NewRows([]string{"date_time"}))
+
+		_, err := repo.GetProduct(context.Background(), productID)
+		assert.Equal(t, e.ErrNotFound(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestChangeProductStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	productID := uuid.New()
+	receptionID := uuid.New()
+	pvzID := uuid.New()
+	dateTime := time.Now()
+	columns := []string{"reception_id", "type_id", "pvz_id"}
+
+	t.Run("IssueReleasesCapacity", func(t *testing.T) {
+		change := &models.ProductStatusChange{
+			ProductID: productID,
+			From:      models.ProductStatusStored,
+			To:        models.ProductStatusIssued,
+			ChangedAt: time.Now(),
+		}
+
+		mock.ExpectBegin()
+		expectProductDate(mock, productID, dateTime)
+		mock.ExpectQuery("UPDATE products p SET status = \\$1 FROM receptions r WHERE p.id = \\$2 AND p.date_time = \\$4 AND p.status = \\$3 AND r.id = p.reception_id AND r.date_time <= \\$4").
+			WithArgs(models.ProductStatusIssued, productID, models.ProductStatusStored, dateTime).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(receptionID, 2, pvzID))
+		mock.ExpectExec("INSERT INTO pvz_occupancy").
+			WithArgs(pvzID, 2, -1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO product_status_history").
+			WithArgs(productID, pvzID, receptionID, "stored", models.ProductStatusIssued, "", change.ChangedAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO outbox").
+			WithArgs(sqlmock.AnyArg(), models.EventProductStatusChanged, pvzID, receptionID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.ChangeProductStatus(context.Background(), change))
+		assert.Equal(t, pvzID, change.PVZID)
+		assert.Equal(t, receptionID, change.ReceptionID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ReturnTakesCapacity", func(t *testing.T) {
+		change := &models.ProductStatusChange{
+			ProductID: productID,
+			From:      models.ProductStatusIssued,
+			To:        models.ProductStatusReturned,
+			Comment:   "не подошел размер",
+			ChangedAt: time.Now(),
+		}
+
+		mock.ExpectBegin()
+		expectProductDate(mock, productID, dateTime)
+		mock.ExpectQuery("UPDATE products p SET status").
+			WithArgs(models.ProductStatusReturned, productID, models.ProductStatusIssued, dateTime).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(receptionID, 2, pvzID))
+		mock.ExpectExec("INSERT INTO pvz_occupancy").
+			WithArgs(pvzID, 2, 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO product_status_history").
+			WithArgs(productID, pvzID, receptionID, "issued", models.ProductStatusReturned, "не подошел размер", change.ChangedAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO outbox").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.ChangeProductStatus(context.Background(), change))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("StatusChangedMeanwhile", func(t *testing.T) {
+		change := &models.ProductStatusChange{
+			ProductID: productID,
+			From:      models.ProductStatusStored,
+			To:        models.ProductStatusIssued,
+			ChangedAt: time.Now(),
+		}
+
+		mock.ExpectBegin()
+		expectProductDate(mock, productID, dateTime)
+		mock.ExpectQuery("UPDATE products p SET status").
+			WithArgs(models.ProductStatusIssued, productID, models.ProductStatusStored, dateTime).
+			WillReturnRows(sqlmock.NewRows(columns))
+		mock.ExpectRollback()
+
+		err := repo.ChangeProductStatus(context.Background(), change)
+		assert.Equal(t, e.ErrInvalidStatusTransition(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetProductHistory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	productID := uuid.New()
+	pvzID := uuid.New()
+	receptionID := uuid.New()
+	accepted := time.Now().Add(-2 * time.Hour)
+	stored := time.Now().Add(-time.Hour)
+
+	mock.ExpectQuery("SELECT product_id, pvz_id, reception_id, COALESCE\\(from_status, ''\\), to_status, comment, changed_at FROM product_status_history WHERE product_id = \\$1 ORDER BY changed_at, id").
+		WithArgs(productID).
+		WillReturnRows(sqlmock.NewRows([]string{"product_id", "pvz_id", "reception_id", "from_status", "to_status", "comment", "changed_at"}).
+			AddRow(productID, pvzID, receptionID, "", "accepted", "", accepted).
+			AddRow(productID, pvzID, receptionID, "accepted", "stored", "", stored))
+
+	history, err := repo.GetProductHistory(context.Background(), productID)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.ProductStatusChange{
+		{ProductID: productID, PVZID: pvzID, ReceptionID: receptionID, To: models.ProductStatusAccepted, ChangedAt: accepted},
+		{ProductID: productID, PVZID: pvzID, ReceptionID: receptionID, From: models.ProductStatusAccepted, To: models.ProductStatusStored, ChangedAt: stored},
+	}, history)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -6,19 +6,53 @@ import (
 	"fmt"
 	e "pvz-service/internal/errors"
 	"pvz-service/internal/models"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/lib/pq"
 )
 
 func (p *Postgres) InsertPVZ(ctx context.Context, pvz *models.PVZ) error {
+	lat, lon := locationArgs(pvz.Location)
 	_, err := p.db.ExecContext(ctx,
-		"INSERT INTO pvz (id, registration_date, city_id) VALUES ($1, $2, $3)",
-		pvz.ID, pvz.RegistrationDate, pvz.CityID)
+		`INSERT INTO pvz (id, registration_date, city_id, address, latitude, longitude, capacity)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		pvz.ID, pvz.RegistrationDate, pvz.CityID, pvz.Address, lat, lon, pvz.Capacity)
 	return err
 }
 
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanPVZ reads the columns selected by the PVZ queries into pvz followed by
+// any extra destinations
+func scanPVZ(row rowScanner, pvz *models.PVZ, extra ...interface{}) error {
+	var lat, lon sql.NullFloat64
+	dest := []interface{}{
+		&pvz.ID, &pvz.RegistrationDate, &pvz.CityID, &pvz.CityName, &pvz.Address,
+		&lat, &lon, &pvz.Capacity, &pvz.Status, &pvz.DeletedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+
+	pvz.Location = nil
+	if lat.Valid && lon.Valid {
+		pvz.Location = &models.GeoPoint{Latitude: lat.Float64, Longitude: lon.Float64}
+	}
+	return nil
+}
+
+func locationArgs(location *models.GeoPoint) (sql.NullFloat64, sql.NullFloat64) {
+	if location == nil {
+		return sql.NullFloat64{}, sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: location.Latitude, Valid: true},
+		sql.NullFloat64{Float64: location.Longitude, Valid: true}
+}
+
 func (p *Postgres) GetCityID(ctx context.Context, city string) (int, error) {
 	var cityID int
 
@@ -35,7 +69,7 @@ func (p *Postgres) GetCityID(ctx context.Context, city string) (int, error) {
 
 func (p *Postgres) CheckPVZ(ctx context.Context, pvzID uuid.UUID) (bool, error) {
 	var exists bool
-	err := p.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM pvz WHERE id = $1)", pvzID).Scan(&exists)
+	err := p.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM pvz WHERE id = $1 AND deleted_at IS NULL)", pvzID).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("failed to check PVZ existence: %w", err)
 	}
@@ -46,15 +80,109 @@ func (p *Postgres) CheckPVZ(ctx context.Context, pvzID uuid.UUID) (bool, error)
 	return true, nil
 }
 
+func (p *Postgres) GetPVZ(ctx context.Context, pvzID uuid.UUID) (*models.PVZ, error) {
+	var pvz models.PVZ
+	err := scanPVZ(p.db.QueryRowContext(ctx,
+		`SELECT p.id, p.registration_date, p.city_id, c.name, p.address, p.latitude, p.longitude, p.capacity, p.status, p.deleted_at, p.version
+		 FROM pvz p
+		 JOIN cities c ON p.city_id = c.id
+		 WHERE p.id = $1 AND p.deleted_at IS NULL`,
+		pvzID), &pvz, &pvz.Version)
+	if err == sql.ErrNoRows {
+		return nil, e.ErrNotFound()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pvz, nil
+}
+
+func (p *Postgres) UpdatePVZ(ctx context.Context, pvz *models.PVZ, version int) (int, error) {
+	var newVersion int
+	lat, lon := locationArgs(pvz.Location)
+	err := p.db.QueryRowContext(ctx,
+		`UPDATE pvz SET city_id = $1, address = $2, latitude = $3, longitude = $4,
+		 capacity = $5, status = $6, version = version + 1
+		 WHERE id = $7 AND version = $8 AND deleted_at IS NULL
+		 RETURNING version`,
+		pvz.CityID, pvz.Address, lat, lon, pvz.Capacity, pvz.Status, pvz.ID, version).Scan(&newVersion)
+	if err == sql.ErrNoRows {
+		return 0, e.ErrVersionMismatch()
+	}
+	if err != nil {
+		return 0, err
+	}
+	return newVersion, nil
+}
+
+func (p *Postgres) DeletePVZ(ctx context.Context, pvzID uuid.UUID, version int) error {
+	res, err := p.db.ExecContext(ctx,
+		`UPDATE pvz SET status = 'closed', deleted_at = NOW(), version = version + 1
+		 WHERE id = $1 AND version = $2 AND deleted_at IS NULL`,
+		pvzID, version)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return e.ErrVersionMismatch()
+	}
+
+	return nil
+}
+
+func (p *Postgres) GetNearbyPVZs(ctx context.Context, point models.GeoPoint, radius float64, limit int) ([]models.NearbyPVZ, error) {
+	// The bounding box lets the (latitude, longitude) index discard most rows
+	// before the exact haversine distance is computed
+	minLat, maxLat, minLon, maxLon := point.BoundingBox(radius)
+
+	rows, err := p.db.QueryContext(ctx,
+		`SELECT p.id, p.registration_date, p.city_id, c.name, p.address, p.latitude, p.longitude, p.capacity, p.status, p.deleted_at, d.distance
+		 FROM pvz p
+		 JOIN cities c ON p.city_id = c.id
+		 CROSS JOIN LATERAL (
+			 SELECT 2 * $1 * ASIN(LEAST(1, SQRT(
+				 POWER(SIN(RADIANS(p.latitude - $2) / 2), 2) +
+				 COS(RADIANS($2)) * COS(RADIANS(p.latitude)) * POWER(SIN(RADIANS(p.longitude - $3) / 2), 2)
+			 ))) AS distance
+		 ) d
+		 WHERE p.deleted_at IS NULL AND p.status = 'active'
+		 AND p.latitude BETWEEN $4 AND $5
+		 AND p.longitude BETWEEN $6 AND $7
+		 AND d.distance <= $8
+		 ORDER BY d.distance
+		 LIMIT $9`,
+		models.EarthRadius, point.Latitude, point.Longitude, minLat, maxLat, minLon, maxLon, radius, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []models.NearbyPVZ
+	for rows.Next() {
+		var nearby models.NearbyPVZ
+		if err := scanPVZ(rows, &nearby.PVZ, &nearby.Distance); err != nil {
+			return nil, err
+		}
+		result = append(result, nearby)
+	}
+	return result, rows.Err()
+}
+
+const activeReceptionQuery = `SELECT r.id, r.date_time, r.pvz_id, r.status, r.version, m.id
+		 FROM receptions r
+		 LEFT JOIN manifests m ON m.reception_id = r.id
+		 WHERE r.pvz_id = $1 AND r.status = 'in_progress'
+		 ORDER BY r.date_time DESC
+		 LIMIT 1`
+
 func (p *Postgres) GetActiveReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
 	var reception models.Reception
-	err := p.db.QueryRowContext(ctx,
-		`SELECT id, date_time, pvz_id, status 
-		 FROM receptions 
-		 WHERE pvz_id = $1 AND status = 'in_progress' 
-		 ORDER BY date_time DESC 
-		 LIMIT 1`,
-		pvzID).Scan(&reception.ID, &reception.DateTime, &reception.PVZID, &reception.Status)
+	err := p.db.QueryRowContext(ctx, activeReceptionQuery, pvzID).Scan(&reception.ID, &reception.DateTime, &reception.PVZID, &reception.Status, &reception.Version, &reception.ManifestID)
 	if err == sql.ErrNoRows {
 		return nil, e.ErrNoActiveReception()
 	}
@@ -65,10 +193,22 @@ func (p *Postgres) GetActiveReception(ctx context.Context, pvzID uuid.UUID) (*mo
 }
 
 func (p *Postgres) InsertReception(ctx context.Context, reception *models.Reception) error {
-	_, err := p.db.ExecContext(ctx,
-		"INSERT INTO receptions (id, date_time, pvz_id, status) VALUES ($1, $2, $3, $4)",
-		reception.ID, reception.DateTime, reception.PVZID, reception.Status)
-	return err
+	return p.withTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO receptions (id, date_time, pvz_id, status) VALUES ($1, $2, $3, $4)",
+			reception.ID, reception.DateTime, reception.PVZID, reception.Status)
+		if err != nil {
+			return err
+		}
+
+		if reception.ManifestID != nil {
+			if err := linkManifest(ctx, tx, *reception.ManifestID, reception.ID, reception.PVZID); err != nil {
+				return err
+			}
+		}
+
+		return insertEvent(ctx, tx, models.EventReceptionOpened, reception.PVZID, reception.ID, reception)
+	})
 }
 
 func (p *Postgres) GetProductTypeID(ctx context.Context, productTypeName string) (int, error) {
@@ -83,22 +223,62 @@ func (p *Postgres) GetProductTypeID(ctx context.Context, productTypeName string)
 	return productTypeID, nil
 }
 
-func (p *Postgres) InsertProduct(ctx context.Context, product *models.Product) error {
-	_, err := p.db.ExecContext(ctx,
-		"INSERT INTO products (id, date_time, type_id, reception_id) VALUES ($1, $2, $3, $4)",
-		product.ID, product.DateTime, product.TypeID, product.ReceptionID)
-	return err
+const insertProductQuery = "INSERT INTO products (id, date_time, type_id, reception_id, status, barcode) VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))"
+
+func (p *Postgres) InsertProduct(ctx context.Context, product *models.Product, receptionVersion int) (int, error) {
+	var version int
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		pvzID, newVersion, err := bumpReceptionVersion(ctx, tx, product.ReceptionID, receptionVersion)
+		if err != nil {
+			return err
+		}
+		version = newVersion
+
+		if err := reserveCapacity(ctx, tx, pvzID, product.TypeID); err != nil {
+			return err
+		}
+
+		// The key goes first and fails the insert on a duplicate product ID
+		if _, err := tx.ExecContext(ctx, insertProductKeyQuery, product.ID, product.DateTime); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, insertProductQuery,
+			product.ID, product.DateTime, product.TypeID, product.ReceptionID, product.Status, product.Barcode)
+		if err != nil {
+			return err
+		}
+
+		err = insertStatusChange(ctx, tx, &models.ProductStatusChange{
+			ProductID:   product.ID,
+			PVZID:       pvzID,
+			ReceptionID: product.ReceptionID,
+			To:          product.Status,
+			ChangedAt:   product.DateTime,
+		})
+		if err != nil {
+			return err
+		}
+
+		return insertEvent(ctx, tx, models.EventProductAdded, pvzID, product.ReceptionID, product)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return version, nil
 }
 
-func (p *Postgres) GetLastProduct(ctx context.Context, receptionID uuid.UUID) (*models.Product, error) {
-	var product models.Product
-	err := p.db.QueryRowContext(ctx,
-		`SELECT id, date_time, type_id, reception_id 
+// lastProductQuery finds the last product scanned in a reception. Products
+// received with a transfer belong to the transfer record and are skipped.
+const lastProductQuery = `SELECT id, date_time, type_id, reception_id, status
 		 FROM products 
-		 WHERE reception_id = $1 
+		 WHERE reception_id = $1 AND date_time >= $2
+		   AND NOT EXISTS (SELECT 1 FROM transfer_items ti WHERE ti.product_id = products.id)
 		 ORDER BY date_time DESC 
-		 LIMIT 1`,
-		receptionID).Scan(&product.ID, &product.DateTime, &product.TypeID, &product.ReceptionID)
+		 LIMIT 1`
+
+func (p *Postgres) GetLastProduct(ctx context.Context, receptionID uuid.UUID, since time.Time) (*models.Product, error) {
+	var product models.Product
+	err := p.db.QueryRowContext(ctx, lastProductQuery, receptionID, since).Scan(&product.ID, &product.DateTime, &product.TypeID, &product.ReceptionID, &product.Status)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, e.ErrNotFound()
@@ -108,21 +288,129 @@ func (p *Postgres) GetLastProduct(ctx context.Context, receptionID uuid.UUID) (*
 	return &product, nil
 }
 
-func (p *Postgres) DeleteProduct(ctx context.Context, productID uuid.UUID) error {
-	_, err := p.db.ExecContext(ctx, "DELETE FROM products WHERE id = $1", productID)
-	return err
+func (p *Postgres) DeleteProduct(ctx context.Context, productID uuid.UUID, receptionVersion int) (int, string, error) {
+	var version int
+	var photoKey string
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		dateTime, err := productDate(ctx, tx, productID)
+		if err != nil {
+			return err
+		}
+
+		var product models.Product
+		err = tx.QueryRowContext(ctx,
+			`DELETE FROM products p
+			 USING product_types pt
+			 WHERE p.id = $1 AND p.date_time = $2 AND pt.id = p.type_id
+			   AND NOT EXISTS (SELECT 1 FROM transfer_items ti WHERE ti.product_id = p.id)
+			 RETURNING p.id, p.date_time, p.type_id, pt.name, p.reception_id, p.status, COALESCE(p.photo_key, '')`,
+			productID, dateTime).Scan(&product.ID, &product.DateTime, &product.TypeID, &product.TypeName, &product.ReceptionID, &product.Status, &photoKey)
+		if err == sql.ErrNoRows {
+			return e.ErrNotFound()
+		}
+		if err != nil {
+			return err
+		}
+
+		// Rolls the deletion back if the reception has changed meanwhile
+		pvzID, newVersion, err := bumpReceptionVersion(ctx, tx, product.ReceptionID, receptionVersion)
+		if err != nil {
+			return err
+		}
+		version = newVersion
+
+		// Products are partitioned and have no foreign keys to cascade along
+		if _, err := tx.ExecContext(ctx, `DELETE FROM product_status_history WHERE product_id = $1`, productID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM product_keys WHERE id = $1`, productID); err != nil {
+			return err
+		}
+
+		if err := adjustOccupancy(ctx, tx, pvzID, product.TypeID, -1); err != nil {
+			return err
+		}
+
+		return insertEvent(ctx, tx, models.EventProductDeleted, pvzID, product.ReceptionID, product)
+	})
+	if err != nil {
+		return 0, "", err
+	}
+	return version, photoKey, nil
 }
 
-func (p *Postgres) UpdateReceptionStatus(ctx context.Context, receptionID uuid.UUID, status models.ReceptionStatus) error {
-	_, err := p.db.ExecContext(ctx,
-		"UPDATE receptions SET status = $1 WHERE id = $2",
-		status, receptionID)
-	return err
+func (p *Postgres) UpdateReceptionStatus(ctx context.Context, receptionID uuid.UUID, status models.ReceptionStatus, version int) (int, error) {
+	var reception *models.Reception
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		reception, err = setReceptionStatus(ctx, tx, receptionID, status, version)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return reception.Version, nil
+}
+
+// setReceptionStatus moves a reception that still has the expected version
+// to status. Closing a reception also stores its products and reconciles it
+// with the manifest.
+func setReceptionStatus(ctx context.Context, tx *sql.Tx, receptionID uuid.UUID, status models.ReceptionStatus, version int) (*models.Reception, error) {
+	var reception models.Reception
+	err := tx.QueryRowContext(ctx,
+		`UPDATE receptions SET status = $1, version = version + 1
+		 WHERE id = $2 AND version = $3
+		 RETURNING id, date_time, pvz_id, status, version`,
+		status, receptionID, version).Scan(&reception.ID, &reception.DateTime, &reception.PVZID, &reception.Status, &reception.Version)
+	if err == sql.ErrNoRows {
+		return nil, e.ErrVersionMismatch()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	eventType := models.EventReceptionOpened
+	if status == models.ReceptionStatusClose {
+		eventType = models.EventReceptionClosed
+
+		if err := storeAcceptedProducts(ctx, tx, reception.ID, reception.PVZID, reception.DateTime); err != nil {
+			return nil, err
+		}
+
+		reception.Discrepancies, err = reconcileManifest(ctx, tx, reception.ID, reception.DateTime, time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := insertEvent(ctx, tx, eventType, reception.PVZID, reception.ID, reception); err != nil {
+		return nil, err
+	}
+	return &reception, nil
+}
+
+const bumpReceptionVersionQuery = "UPDATE receptions SET version = version + 1 WHERE id = $1 AND version = $2 RETURNING pvz_id, version"
+
+// bumpReceptionVersion increments the version of a reception that still has
+// the expected one and returns its PVZ and new version
+func bumpReceptionVersion(ctx context.Context, tx *sql.Tx, receptionID uuid.UUID, version int) (uuid.UUID, int, error) {
+	var (
+		pvzID      uuid.UUID
+		newVersion int
+	)
+	err := tx.QueryRowContext(ctx, bumpReceptionVersionQuery, receptionID, version).Scan(&pvzID, &newVersion)
+	if err == sql.ErrNoRows {
+		return uuid.Nil, 0, e.ErrVersionMismatch()
+	}
+	if err != nil {
+		return uuid.Nil, 0, err
+	}
+	return pvzID, newVersion, nil
 }
 
 func (p *Postgres) GetPVZs(ctx context.Context, from, to time.Time, limit, offset int) ([]models.PVZ, error) {
-	rows, err := p.db.QueryContext(ctx,
-		`SELECT p.id, p.registration_date, p.city_id, c.name
+	rows, err := p.queryRead(ctx,
+		`SELECT p.id, p.registration_date, p.city_id, c.name, p.address, p.latitude, p.longitude, p.capacity, p.status, p.deleted_at
 		 FROM pvz p
 		 JOIN cities c ON p.city_id = c.id
 		 WHERE EXISTS (
@@ -141,7 +429,7 @@ func (p *Postgres) GetPVZs(ctx context.Context, from, to time.Time, limit, offse
 	var pvzs []models.PVZ
 	for rows.Next() {
 		var pvz models.PVZ
-		if err := rows.Scan(&pvz.ID, &pvz.RegistrationDate, &pvz.CityID, &pvz.CityName); err != nil {
+		if err := scanPVZ(rows, &pvz); err != nil {
 			return nil, err
 		}
 		pvzs = append(pvzs, pvz)
@@ -150,10 +438,11 @@ func (p *Postgres) GetPVZs(ctx context.Context, from, to time.Time, limit, offse
 }
 
 func (p *Postgres) GetPVZsWithNoFilter(ctx context.Context) ([]models.PVZ, error) {
-	rows, err := p.db.QueryContext(ctx,
-		`SELECT p.id, p.registration_date, p.city_id, c.name
+	rows, err := p.queryRead(ctx,
+		`SELECT p.id, p.registration_date, p.city_id, c.name, p.address, p.latitude, p.longitude, p.capacity, p.status, p.deleted_at
 		 FROM pvz p
 		 JOIN cities c ON p.city_id = c.id
+		 WHERE p.deleted_at IS NULL
 		 ORDER BY p.registration_date DESC`)
 	if err != nil {
 		return nil, err
@@ -163,7 +452,7 @@ func (p *Postgres) GetPVZsWithNoFilter(ctx context.Context) ([]models.PVZ, error
 	var pvzs []models.PVZ
 	for rows.Next() {
 		var pvz models.PVZ
-		if err := rows.Scan(&pvz.ID, &pvz.RegistrationDate, &pvz.CityID, &pvz.CityName); err != nil {
+		if err := scanPVZ(rows, &pvz); err != nil {
 			return nil, err
 		}
 		pvzs = append(pvzs, pvz)
@@ -172,12 +461,12 @@ func (p *Postgres) GetPVZsWithNoFilter(ctx context.Context) ([]models.PVZ, error
 }
 
 func (p *Postgres) GetReceptionsForPVZs(ctx context.Context, pvzIDs []uuid.UUID, from, to time.Time) ([]models.Reception, error) {
-	rows, err := p.db.QueryContext(ctx,
+	rows, err := p.queryRead(ctx,
 		`SELECT id, date_time, pvz_id, status
          FROM receptions 
          WHERE pvz_id = ANY($1) AND date_time BETWEEN $2 AND $3
          ORDER BY date_time DESC`,
-		pq.Array(pvzIDs), from, to)
+		pvzIDs, from, to)
 	if err != nil {
 		return nil, err
 	}
@@ -194,18 +483,19 @@ func (p *Postgres) GetReceptionsForPVZs(ctx context.Context, pvzIDs []uuid.UUID,
 	return receptions, rows.Err()
 }
 
-func (p *Postgres) GetProductsForReceptions(ctx context.Context, receptionIDs []uuid.UUID) ([]models.Product, error) {
+func (p *Postgres) GetProductsForReceptions(ctx context.Context, receptionIDs []uuid.UUID, since time.Time) ([]models.Product, error) {
 	if len(receptionIDs) == 0 {
 		return []models.Product{}, nil
 	}
 
-	rows, err := p.db.QueryContext(ctx,
-		`SELECT p.id, p.date_time, p.type_id, pt.name, p.reception_id
+	rows, err := p.queryRead(ctx,
+		`SELECT p.id, p.date_time, p.type_id, pt.name, p.reception_id, p.status,
+                COALESCE(p.barcode, ''), p.condition, p.condition_note, COALESCE(p.photo_key, '')
          FROM products p
          JOIN product_types pt ON p.type_id = pt.id
-         WHERE p.reception_id = ANY($1)
+         WHERE p.reception_id = ANY($1) AND p.date_time >= $2
          ORDER BY p.date_time DESC`,
-		pq.Array(receptionIDs))
+		receptionIDs, since)
 	if err != nil {
 		return nil, err
 	}
@@ -214,10 +504,66 @@ func (p *Postgres) GetProductsForReceptions(ctx context.Context, receptionIDs []
 	var products []models.Product
 	for rows.Next() {
 		var prod models.Product
-		if err := rows.Scan(&prod.ID, &prod.DateTime, &prod.TypeID, &prod.TypeName, &prod.ReceptionID); err != nil {
+		if err := scanProduct(rows, &prod); err != nil {
 			return nil, err
 		}
 		products = append(products, prod)
 	}
 	return products, rows.Err()
 }
+
+func (p *Postgres) IteratePVZs(ctx context.Context, filter models.PVZFilter, fn func(models.PVZ) error) error {
+	query := `SELECT p.id, p.registration_date, p.city_id, c.name, p.address, p.latitude, p.longitude, p.capacity, p.status, p.deleted_at
+		 FROM pvz p
+		 JOIN cities c ON p.city_id = c.id`
+
+	var (
+		conds []string
+		args  []interface{}
+	)
+
+	if len(filter.Cities) > 0 {
+		args = append(args, filter.Cities)
+		conds = append(conds, fmt.Sprintf("c.name = ANY($%d)", len(args)))
+	}
+
+	if !filter.From.IsZero() || !filter.To.IsZero() {
+		recConds := []string{"r.pvz_id = p.id"}
+		if !filter.From.IsZero() {
+			args = append(args, filter.From)
+			recConds = append(recConds, fmt.Sprintf("r.date_time >= $%d", len(args)))
+		}
+		if !filter.To.IsZero() {
+			args = append(args, filter.To)
+			recConds = append(recConds, fmt.Sprintf("r.date_time <= $%d", len(args)))
+		}
+		conds = append(conds, "EXISTS (SELECT 1 FROM receptions r WHERE "+strings.Join(recConds, " AND ")+")")
+	} else {
+		// Deleted PVZs stay only in the listings of receptions by date
+		conds = append(conds, "p.deleted_at IS NULL")
+	}
+
+	if len(conds) > 0 {
+		query += "\n\t\t WHERE " + strings.Join(conds, " AND ")
+	}
+	query += "\n\t\t ORDER BY p.registration_date DESC"
+
+	// Only the query itself falls back to the primary. Once rows were passed
+	// to fn, a broken connection ends the iteration with an error.
+	rows, err := p.queryRead(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var pvz models.PVZ
+		if err := scanPVZ(rows, &pvz); err != nil {
+			return err
+		}
+		if err := fn(pvz); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	e "pvz-service/internal/errors"
 	"pvz-service/internal/models"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -13,7 +14,7 @@ import (
 )
 
 func TestInsertPVZ(t *testing.T) {
-	db, mock, err := sqlmock.New()
+	db, mock, err := sqlmock.New(pgxArgs)
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
@@ -28,18 +29,33 @@ func TestInsertPVZ(t *testing.T) {
 	}
 
 	t.Run("Success", func(t *testing.T) {
-		mock.ExpectExec("INSERT INTO pvz \\(id, registration_date, city_id\\) VALUES \\(\\$1, \\$2, \\$3\\)").
-			WithArgs(pvz.ID, pvz.RegistrationDate, pvz.CityID).
+		mock.ExpectExec("INSERT INTO pvz \\(id, registration_date, city_id, address, latitude, longitude, capacity\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6, \\$7\\)").
+			WithArgs(pvz.ID, pvz.RegistrationDate, pvz.CityID, "", nil, nil, 0).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		err := repo.InsertPVZ(context.Background(), pvz)
 		assert.NoError(t, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
+
+	t.Run("WithLocation", func(t *testing.T) {
+		located := *pvz
+		located.Address = "ул. Баумана, 10"
+		located.Location = &models.GeoPoint{Latitude: 55.7887, Longitude: 49.1221}
+		located.Capacity = 200
+
+		mock.ExpectExec("INSERT INTO pvz").
+			WithArgs(located.ID, located.RegistrationDate, located.CityID, located.Address, 55.7887, 49.1221, 200).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := repo.InsertPVZ(context.Background(), &located)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 }
 
 func TestGetActiveReception(t *testing.T) {
-	db, mock, err := sqlmock.New()
+	db, mock, err := sqlmock.New(pgxArgs)
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
@@ -52,8 +68,8 @@ func TestGetActiveReception(t *testing.T) {
 	now := time.Now()
 
 	t.Run("Success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}).
-			AddRow(receptionID, now, pvzID, models.ReceptionStatusInProgress)
+		rows := sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status", "version", "manifest_id"}).
+			AddRow(receptionID, now, pvzID, models.ReceptionStatusInProgress, 3, nil)
 		mock.ExpectQuery("SELECT(.*)").
 			WithArgs(pvzID).
 			WillReturnRows(rows)
@@ -65,13 +81,14 @@ func TestGetActiveReception(t *testing.T) {
 			DateTime: now,
 			PVZID:    pvzID,
 			Status:   models.ReceptionStatusInProgress,
+			Version:  3,
 		}, reception)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
 
 func TestInsertReception(t *testing.T) {
-	db, mock, err := sqlmock.New()
+	db, mock, err := sqlmock.New(pgxArgs)
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
@@ -87,9 +104,14 @@ func TestInsertReception(t *testing.T) {
 	}
 
 	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
 		mock.ExpectExec("INSERT INTO receptions \\(id, date_time, pvz_id, status\\) VALUES \\(\\$1, \\$2, \\$3, \\$4\\)").
 			WithArgs(reception.ID, reception.DateTime, reception.PVZID, reception.Status).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO outbox").
+			WithArgs(sqlmock.AnyArg(), models.EventReceptionOpened, reception.PVZID, reception.ID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
 
 		err := repo.InsertReception(context.Background(), reception)
 		assert.NoError(t, err)
@@ -98,7 +120,7 @@ func TestInsertReception(t *testing.T) {
 }
 
 func TestInsertProduct(t *testing.T) {
-	db, mock, err := sqlmock.New()
+	db, mock, err := sqlmock.New(pgxArgs)
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
@@ -111,21 +133,124 @@ func TestInsertProduct(t *testing.T) {
 		DateTime:    time.Now(),
 		TypeID:      1,
 		ReceptionID: uuid.New(),
+		Status:      models.ProductStatusAccepted,
 	}
 
 	t.Run("Success", func(t *testing.T) {
-		mock.ExpectExec("INSERT INTO products \\(id, date_time, type_id, reception_id\\) VALUES \\(\\$1, \\$2, \\$3, \\$4\\)").
-			WithArgs(product.ID, product.DateTime, product.TypeID, product.ReceptionID).
+		pvzID := uuid.New()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE receptions SET version = version \\+ 1 WHERE id = \\$1 AND version = \\$2 RETURNING pvz_id, version").
+			WithArgs(product.ReceptionID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "version"}).AddRow(pvzID, 2))
+		mock.ExpectQuery("SELECT p.capacity, COALESCE\\(tc.capacity, 0\\)").
+			WithArgs(pvzID, product.TypeID).
+			WillReturnRows(sqlmock.NewRows([]string{"capacity", "type_capacity", "count", "type_count"}).AddRow(10, 5, 9, 4))
+		mock.ExpectExec("INSERT INTO pvz_occupancy").
+			WithArgs(pvzID, product.TypeID, 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO product_keys \\(id, date_time\\) VALUES \\(\\$1, \\$2\\)").
+			WithArgs(product.ID, product.DateTime).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO products \\(id, date_time, type_id, reception_id, status, barcode\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, NULLIF\\(\\$6, ''\\)\\)").
+			WithArgs(product.ID, product.DateTime, product.TypeID, product.ReceptionID, models.ProductStatusAccepted, product.Barcode).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO product_status_history \\(product_id, pvz_id, reception_id, from_status, to_status, comment, changed_at\\)").
+			WithArgs(product.ID, pvzID, product.ReceptionID, nil, models.ProductStatusAccepted, "", product.DateTime).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO outbox").
+			WithArgs(sqlmock.AnyArg(), models.EventProductAdded, pvzID, product.ReceptionID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
 
-		err := repo.InsertProduct(context.Background(), product)
+		version, err := repo.InsertProduct(context.Background(), product, 1)
 		assert.NoError(t, err)
+		assert.Equal(t, 2, version)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("VersionMismatch", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE receptions SET version").
+			WithArgs(product.ReceptionID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "version"}))
+		mock.ExpectRollback()
+
+		_, err := repo.InsertProduct(context.Background(), product, 1)
+		assert.Equal(t, e.ErrVersionMismatch(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("PVZCapacityExceeded", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE receptions SET version").
+			WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "version"}).AddRow(uuid.New(), 2))
+		mock.ExpectQuery("SELECT p.capacity").
+			WillReturnRows(sqlmock.NewRows([]string{"capacity", "type_capacity", "count", "type_count"}).AddRow(10, 0, 10, 3))
+		mock.ExpectRollback()
+
+		_, err := repo.InsertProduct(context.Background(), product, 1)
+		assert.Equal(t, e.ErrCapacityExceeded(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("TypeCapacityExceeded", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE receptions SET version").
+			WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "version"}).AddRow(uuid.New(), 2))
+		mock.ExpectQuery("SELECT p.capacity").
+			WillReturnRows(sqlmock.NewRows([]string{"capacity", "type_capacity", "count", "type_count"}).AddRow(0, 3, 7, 3))
+		mock.ExpectRollback()
+
+		_, err := repo.InsertProduct(context.Background(), product, 1)
+		assert.Equal(t, e.ErrCapacityExceeded(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("OutboxFailureRollsBack", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE receptions SET version").
+			WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "version"}).AddRow(uuid.New(), 2))
+		mock.ExpectQuery("SELECT p.capacity").
+			WillReturnRows(sqlmock.NewRows([]string{"capacity", "type_capacity", "count", "type_count"}).AddRow(0, 0, 0, 0))
+		mock.ExpectExec("INSERT INTO pvz_occupancy").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO product_keys").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO products").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO product_status_history").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO outbox").
+			WillReturnError(assert.AnError)
+		mock.ExpectRollback()
+
+		_, err := repo.InsertProduct(context.Background(), product, 1)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("DuplicateIDRollsBack", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE receptions SET version").
+			WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "version"}).AddRow(uuid.New(), 2))
+		mock.ExpectQuery("SELECT p.capacity").
+			WillReturnRows(sqlmock.NewRows([]string{"capacity", "type_capacity", "count", "type_count"}).AddRow(0, 0, 0, 0))
+		mock.ExpectExec("INSERT INTO pvz_occupancy").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO product_keys").
+			WithArgs(product.ID, product.DateTime).
+			WillReturnError(assert.AnError)
+		mock.ExpectRollback()
+
+		_, err := repo.InsertProduct(context.Background(), product, 1)
+		assert.ErrorIs(t, err, assert.AnError)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
 
 func TestGetPVZs(t *testing.T) {
-	db, mock, err := sqlmock.New()
+	db, mock, err := sqlmock.New(pgxArgs)
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
@@ -138,8 +263,8 @@ func TestGetPVZs(t *testing.T) {
 	cityName := "Москва"
 
 	t.Run("Success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "registration_date", "city_id", "name"}).
-			AddRow(pvzID, now, 1, cityName)
+		rows := sqlmock.NewRows([]string{"id", "registration_date", "city_id", "name", "address", "latitude", "longitude", "capacity", "status", "deleted_at"}).
+			AddRow(pvzID, now, 1, cityName, "", nil, nil, 0, models.PVZStatusActive, nil)
 		mock.ExpectQuery("SELECT(.*)").
 			WithArgs(now.Add(-24*time.Hour), now, 10, 0).
 			WillReturnRows(rows)
@@ -152,6 +277,7 @@ func TestGetPVZs(t *testing.T) {
 				RegistrationDate: now,
 				CityID:           1,
 				CityName:         cityName,
+				Status:           models.PVZStatusActive,
 			},
 		}, pvzs)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -159,7 +285,7 @@ func TestGetPVZs(t *testing.T) {
 }
 
 func TestGetPVZsWithNoFilter(t *testing.T) {
-	db, mock, err := sqlmock.New()
+	db, mock, err := sqlmock.New(pgxArgs)
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
@@ -172,13 +298,14 @@ func TestGetPVZsWithNoFilter(t *testing.T) {
 	cityName := "Москва"
 
 	t.Run("SuccessWithResults", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "registration_date", "city_id", "name"}).
-			AddRow(pvzID, now, 1, cityName).
-			AddRow(uuid.New(), now.Add(-time.Hour), 2, "Санкт-Петербург")
+		rows := sqlmock.NewRows([]string{"id", "registration_date", "city_id", "name", "address", "latitude", "longitude", "capacity", "status", "deleted_at"}).
+			AddRow(pvzID, now, 1, cityName, "", nil, nil, 0, models.PVZStatusActive, nil).
+			AddRow(uuid.New(), now.Add(-time.Hour), 2, "Санкт-Петербург", "", nil, nil, 0, models.PVZStatusActive, nil)
 
-		mock.ExpectQuery(`SELECT p.id, p.registration_date, p.city_id, c.name
+		mock.ExpectQuery(`SELECT p.id, p.registration_date, p.city_id, c.name, p.address, p.latitude, p.longitude, p.capacity, p.status, p.deleted_at
 		 FROM pvz p
 		 JOIN cities c ON p.city_id = c.id
+		 WHERE p.deleted_at IS NULL
 		 ORDER BY p.registration_date DESC`).
 			WillReturnRows(rows)
 
@@ -191,23 +318,26 @@ func TestGetPVZsWithNoFilter(t *testing.T) {
 				RegistrationDate: now,
 				CityID:           1,
 				CityName:         cityName,
+				Status:           models.PVZStatusActive,
 			},
 			{
 				ID:               pvzs[1].ID, // Проверяем только что ID установлен
 				RegistrationDate: now.Add(-time.Hour),
 				CityID:           2,
 				CityName:         "Санкт-Петербург",
+				Status:           models.PVZStatusActive,
 			},
 		}, pvzs)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("SuccessNoResults", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "registration_date", "city_id", "name"})
+		rows := sqlmock.NewRows([]string{"id", "registration_date", "city_id", "name", "address", "latitude", "longitude", "capacity", "status", "deleted_at"})
 
-		mock.ExpectQuery(`SELECT p.id, p.registration_date, p.city_id, c.name
+		mock.ExpectQuery(`SELECT p.id, p.registration_date, p.city_id, c.name, p.address, p.latitude, p.longitude, p.capacity, p.status, p.deleted_at
 		 FROM pvz p
 		 JOIN cities c ON p.city_id = c.id
+		 WHERE p.deleted_at IS NULL
 		 ORDER BY p.registration_date DESC`).
 			WillReturnRows(rows)
 
@@ -217,3 +347,402 @@ func TestGetPVZsWithNoFilter(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestIteratePVZs(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	now := time.Now()
+	pvzID := uuid.New()
+
+	t.Run("NoFilter", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "registration_date", "city_id", "name", "address", "latitude", "longitude", "capacity", "status", "deleted_at"}).
+			AddRow(pvzID, now, 1, "Москва", "", nil, nil, 0, models.PVZStatusActive, nil).
+			AddRow(uuid.New(), now.Add(-time.Hour), 2, "Санкт-Петербург", "", nil, nil, 0, models.PVZStatusActive, nil)
+
+		mock.ExpectQuery(`SELECT p.id, p.registration_date, p.city_id, c.name, p.address, p.latitude, p.longitude, p.capacity, p.status, p.deleted_at\s+FROM pvz p\s+JOIN cities c ON p.city_id = c.id\s+WHERE p.deleted_at IS NULL\s+ORDER BY p.registration_date DESC`).
+			WillReturnRows(rows)
+
+		var pvzs []models.PVZ
+		err := repo.IteratePVZs(context.Background(), models.PVZFilter{}, func(pvz models.PVZ) error {
+			pvzs = append(pvzs, pvz)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Len(t, pvzs, 2)
+		assert.Equal(t, pvzID, pvzs[0].ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("CityAndDateFilter", func(t *testing.T) {
+		filter := models.PVZFilter{
+			Cities: []string{"Москва"},
+			From:   now.Add(-24 * time.Hour),
+			To:     now,
+		}
+		rows := sqlmock.NewRows([]string{"id", "registration_date", "city_id", "name", "address", "latitude", "longitude", "capacity", "status", "deleted_at"}).
+			AddRow(pvzID, now, 1, "Москва", "", nil, nil, 0, models.PVZStatusActive, nil)
+
+		mock.ExpectQuery(`WHERE c.name = ANY\(\$1\) AND EXISTS \(SELECT 1 FROM receptions r WHERE r.pvz_id = p.id AND r.date_time >= \$2 AND r.date_time <= \$3\)`).
+			WithArgs(sqlmock.AnyArg(), filter.From, filter.To).
+			WillReturnRows(rows)
+
+		count := 0
+		err := repo.IteratePVZs(context.Background(), filter, func(pvz models.PVZ) error {
+			count++
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("CallbackErrorStopsIteration", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "registration_date", "city_id", "name", "address", "latitude", "longitude", "capacity", "status", "deleted_at"}).
+			AddRow(pvzID, now, 1, "Москва", "", nil, nil, 0, models.PVZStatusActive, nil).
+			AddRow(uuid.New(), now, 1, "Москва", "", nil, nil, 0, models.PVZStatusActive, nil)
+
+		mock.ExpectQuery(`SELECT(.*)`).
+			WithArgs(now).
+			WillReturnRows(rows)
+
+		count := 0
+		err := repo.IteratePVZs(context.Background(), models.PVZFilter{From: now}, func(pvz models.PVZ) error {
+			count++
+			return assert.AnError
+		})
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 1, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ReadFromReplica", func(t *testing.T) {
+		set, replicaMocks := newTestReplicaSet(t, 1)
+		set.replicas[0].healthy.Store(true)
+		repo := &Postgres{db: db, replicas: set}
+
+		rows := sqlmock.NewRows([]string{"id", "registration_date", "city_id", "name", "address", "latitude", "longitude", "capacity", "status", "deleted_at"}).
+			AddRow(pvzID, now, 1, "Москва", "", nil, nil, 0, models.PVZStatusActive, nil)
+		replicaMocks[0].ExpectQuery(`SELECT(.*)FROM pvz p`).WillReturnRows(rows)
+
+		count := 0
+		err := repo.IteratePVZs(context.Background(), models.PVZFilter{}, func(pvz models.PVZ) error {
+			count++
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.NoError(t, replicaMocks[0].ExpectationsWereMet())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDeleteProduct(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	productID := uuid.New()
+	receptionID := uuid.New()
+	pvzID := uuid.New()
+	dateTime := time.Now()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectProductDate(mock, productID, dateTime)
+		mock.ExpectQuery("DELETE FROM products p USING product_types pt WHERE p.id = \\$1 AND p.date_time = \\$2").
+			WithArgs(productID, dateTime).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "type_id", "name", "reception_id", "status", "photo_key"}).
+				AddRow(productID, dateTime, 3, "обувь", receptionID, models.ProductStatusAccepted, "products/a.jpg"))
+		mock.ExpectQuery("UPDATE receptions SET version = version \\+ 1").
+			WithArgs(receptionID, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "version"}).AddRow(pvzID, 3))
+		mock.ExpectExec("DELETE FROM product_status_history WHERE product_id = \\$1").
+			WithArgs(productID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM product_keys WHERE id = \\$1").
+			WithArgs(productID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO pvz_occupancy").
+			WithArgs(pvzID, 3, -1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO outbox").
+			WithArgs(sqlmock.AnyArg(), models.EventProductDeleted, pvzID, receptionID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		version, photoKey, err := repo.DeleteProduct(context.Background(), productID, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, version)
+		assert.Equal(t, "products/a.jpg", photoKey)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("AlreadyDeleted", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT date_time FROM product_keys").
+			WithArgs(productID).
+			WillReturnRows(sqlmock.NewRows([]string{"date_time"}))
+		mock.ExpectRollback()
+
+		_, _, err := repo.DeleteProduct(context.Background(), productID, 2)
+		assert.Equal(t, e.ErrNotFound(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ReceivedWithTransfer", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectProductDate(mock, productID, dateTime)
+		mock.ExpectQuery("DELETE FROM products").
+			WithArgs(productID, dateTime).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "type_id", "name", "reception_id", "status", "photo_key"}))
+		mock.ExpectRollback()
+
+		_, _, err := repo.DeleteProduct(context.Background(), productID, 2)
+		assert.Equal(t, e.ErrNotFound(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("VersionMismatchRestoresProduct", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectProductDate(mock, productID, dateTime)
+		mock.ExpectQuery("DELETE FROM products").
+			WithArgs(productID, dateTime).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "type_id", "name", "reception_id", "status", "photo_key"}).
+				AddRow(productID, dateTime, 3, "обувь", receptionID, models.ProductStatusAccepted, ""))
+		mock.ExpectQuery("UPDATE receptions SET version").
+			WithArgs(receptionID, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "version"}))
+		mock.ExpectRollback()
+
+		_, _, err := repo.DeleteProduct(context.Background(), productID, 2)
+		assert.Equal(t, e.ErrVersionMismatch(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUpdateReceptionStatus(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	receptionID := uuid.New()
+	pvzID := uuid.New()
+	startedAt := time.Now()
+
+	t.Run("Close", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE receptions SET status = \\$1, version = version \\+ 1 WHERE id = \\$2 AND version = \\$3 RETURNING id, date_time, pvz_id, status, version").
+			WithArgs(models.ReceptionStatusClose, receptionID, 4).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status", "version"}).
+				AddRow(receptionID, startedAt, pvzID, models.ReceptionStatusClose, 5))
+		mock.ExpectExec("WITH stored AS \\( UPDATE products SET status = \\$2 WHERE reception_id = \\$1 AND date_time >= \\$6 AND status = \\$3").
+			WithArgs(receptionID, models.ProductStatusStored, models.ProductStatusAccepted, pvzID, sqlmock.AnyArg(), startedAt).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectQuery("SELECT id FROM manifests WHERE reception_id = \\$1 AND status = \\$2 FOR UPDATE").
+			WithArgs(receptionID, models.ManifestStatusReceiving).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectExec("INSERT INTO outbox").
+			WithArgs(sqlmock.AnyArg(), models.EventReceptionClosed, pvzID, receptionID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		version, err := repo.UpdateReceptionStatus(context.Background(), receptionID, models.ReceptionStatusClose, 4)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, version)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("VersionMismatch", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE receptions SET status").
+			WithArgs(models.ReceptionStatusClose, receptionID, 4).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status", "version"}))
+		mock.ExpectRollback()
+
+		_, err := repo.UpdateReceptionStatus(context.Background(), receptionID, models.ReceptionStatusClose, 4)
+		assert.Equal(t, e.ErrVersionMismatch(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetPVZ(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	pvzID := uuid.New()
+	now := time.Now()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM pvz p JOIN cities c ON p.city_id = c.id WHERE p.id = \\$1 AND p.deleted_at IS NULL").
+			WithArgs(pvzID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city_id", "name", "address", "latitude", "longitude", "capacity", "status", "deleted_at", "version"}).
+				AddRow(pvzID, now, 1, "Москва", "ул. Тверская, 1", 55.7575, 37.6132, 500, models.PVZStatusSuspended, nil, 4))
+
+		pvz, err := repo.GetPVZ(context.Background(), pvzID)
+		assert.NoError(t, err)
+		assert.Equal(t, &models.PVZ{
+			ID:               pvzID,
+			RegistrationDate: now,
+			CityID:           1,
+			CityName:         "Москва",
+			Address:          "ул. Тверская, 1",
+			Location:         &models.GeoPoint{Latitude: 55.7575, Longitude: 37.6132},
+			Capacity:         500,
+			Status:           models.PVZStatusSuspended,
+			Version:          4,
+		}, pvz)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotFoundOrDeleted", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM pvz p").
+			WithArgs(pvzID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city_id", "name", "address", "latitude", "longitude", "capacity", "status", "deleted_at", "version"}))
+
+		_, err := repo.GetPVZ(context.Background(), pvzID)
+		assert.Equal(t, e.ErrNotFound(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUpdatePVZ(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	pvz := &models.PVZ{
+		ID:       uuid.New(),
+		CityID:   2,
+		Address:  "Невский пр., 28",
+		Location: &models.GeoPoint{Latitude: 55.7575, Longitude: 37.6132},
+		Capacity: 300,
+		Status:   models.PVZStatusSuspended,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery("UPDATE pvz SET city_id = \\$1, address = \\$2, latitude = \\$3, longitude = \\$4, capacity = \\$5, status = \\$6, version = version \\+ 1 WHERE id = \\$7 AND version = \\$8 AND deleted_at IS NULL RETURNING version").
+			WithArgs(pvz.CityID, pvz.Address, 55.7575, 37.6132, pvz.Capacity, pvz.Status, pvz.ID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+
+		version, err := repo.UpdatePVZ(context.Background(), pvz, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, version)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("VersionMismatch", func(t *testing.T) {
+		mock.ExpectQuery("UPDATE pvz SET").
+			WithArgs(pvz.CityID, pvz.Address, 55.7575, 37.6132, pvz.Capacity, pvz.Status, pvz.ID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}))
+
+		_, err := repo.UpdatePVZ(context.Background(), pvz, 1)
+		assert.Equal(t, e.ErrVersionMismatch(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDeletePVZ(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	pvzID := uuid.New()
+
+	t.Run("SoftDeletes", func(t *testing.T) {
+		mock.ExpectExec("UPDATE pvz SET status = 'closed', deleted_at = NOW\\(\\), version = version \\+ 1 WHERE id = \\$1 AND version = \\$2 AND deleted_at IS NULL").
+			WithArgs(pvzID, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.DeletePVZ(context.Background(), pvzID, 3)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("VersionMismatch", func(t *testing.T) {
+		mock.ExpectExec("UPDATE pvz SET status = 'closed'").
+			WithArgs(pvzID, 3).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.DeletePVZ(context.Background(), pvzID, 3)
+		assert.Equal(t, e.ErrVersionMismatch(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetNearbyPVZs(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	point := models.GeoPoint{Latitude: 55.7558, Longitude: 37.6173}
+	minLat, maxLat, minLon, maxLon := point.BoundingBox(1000)
+	pvzID := uuid.New()
+	now := time.Now()
+	columns := []string{"id", "registration_date", "city_id", "name", "address", "latitude", "longitude", "capacity", "status", "deleted_at", "distance"}
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(`CROSS JOIN LATERAL (.+) WHERE p.deleted_at IS NULL AND p.status = 'active' AND p.latitude BETWEEN \$4 AND \$5 AND p.longitude BETWEEN \$6 AND \$7 AND d.distance <= \$8 ORDER BY d.distance LIMIT \$9`).
+			WithArgs(models.EarthRadius, point.Latitude, point.Longitude, minLat, maxLat, minLon, maxLon, 1000.0, 5).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(pvzID, now, 1, "Москва", "ул. Тверская, 1", 55.7575, 37.6132, 0, models.PVZStatusActive, nil, 297.4))
+
+		nearby, err := repo.GetNearbyPVZs(context.Background(), point, 1000, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, []models.NearbyPVZ{
+			{
+				PVZ: models.PVZ{
+					ID:               pvzID,
+					RegistrationDate: now,
+					CityID:           1,
+					CityName:         "Москва",
+					Address:          "ул. Тверская, 1",
+					Location:         &models.GeoPoint{Latitude: 55.7575, Longitude: 37.6132},
+					Status:           models.PVZStatusActive,
+				},
+				Distance: 297.4,
+			},
+		}, nearby)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NothingInRadius", func(t *testing.T) {
+		mock.ExpectQuery(`CROSS JOIN LATERAL`).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		nearby, err := repo.GetNearbyPVZs(context.Background(), point, 1000, 5)
+		assert.NoError(t, err)
+		assert.Empty(t, nearby)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
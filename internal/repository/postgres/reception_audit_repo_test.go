@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStaleReceptions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	idleSince := time.Now().Add(-12 * time.Hour)
+	opened := idleSince.Add(-2 * time.Hour)
+	lastProduct := idleSince.Add(-time.Hour)
+	manifestID := uuid.New()
+	first := models.StaleReception{
+		Reception: models.Reception{
+			ID:         uuid.New(),
+			DateTime:   opened,
+			PVZID:      uuid.New(),
+			Status:     models.ReceptionStatusInProgress,
+			Version:    7,
+			ManifestID: &manifestID,
+		},
+		LastActivity: lastProduct,
+	}
+	second := models.StaleReception{
+		Reception: models.Reception{
+			ID:       uuid.New(),
+			DateTime: opened,
+			PVZID:    uuid.New(),
+			Status:   models.ReceptionStatusInProgress,
+			Version:  1,
+		},
+		LastActivity: opened,
+		Flagged:      true,
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM receptions r LEFT JOIN manifests m (.+) LEFT JOIN LATERAL \\(\\s*SELECT MAX\\(p.date_time\\) (.+) FROM products p\\s+WHERE p.reception_id = r.id AND p.date_time >= r.date_time\\s*\\) latest ON true\\s+WHERE r.status = \\$1\\s+AND (.+) < \\$3").
+		WithArgs(models.ReceptionStatusInProgress, models.ReceptionAuditFlaggedStale, idleSince).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status", "version", "id", "last_activity", "exists"}).
+			AddRow(first.ID, opened, first.PVZID, first.Status, first.Version, manifestID, lastProduct, false).
+			AddRow(second.ID, opened, second.PVZID, second.Status, second.Version, nil, opened, true))
+
+	stale, err := repo.GetStaleReceptions(context.Background(), idleSince)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.StaleReception{first, second}, stale)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestForceCloseReception(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	receptionID := uuid.New()
+	pvzID := uuid.New()
+	now := time.Now()
+
+	t.Run("Success", func(t *testing.T) {
+		entry := &models.ReceptionAuditEntry{
+			Action:    models.ReceptionAuditForceClosed,
+			Actor:     "moderator@example.com",
+			Reason:    "scanner crashed",
+			CreatedAt: now,
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE receptions SET status").
+			WithArgs(models.ReceptionStatusClose, receptionID, 3).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status", "version"}).
+				AddRow(receptionID, now, pvzID, models.ReceptionStatusClose, 4))
+		mock.ExpectExec("WITH stored AS").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectQuery("SELECT id FROM manifests WHERE reception_id = \\$1").
+			WithArgs(receptionID, models.ManifestStatusReceiving).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectExec("INSERT INTO outbox").
+			WithArgs(sqlmock.AnyArg(), models.EventReceptionClosed, pvzID, receptionID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO reception_audit").
+			WithArgs(receptionID, pvzID, models.ReceptionAuditForceClosed, "moderator@example.com", "scanner crashed", now).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		version, err := repo.ForceCloseReception(context.Background(), receptionID, 3, entry)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, version)
+		assert.Equal(t, pvzID, entry.PVZID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("VersionMismatch", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE receptions SET status").
+			WithArgs(models.ReceptionStatusClose, receptionID, 3).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status", "version"}))
+		mock.ExpectRollback()
+
+		_, err := repo.ForceCloseReception(context.Background(), receptionID, 3, &models.ReceptionAuditEntry{
+			Action: models.ReceptionAuditAutoClosed,
+			Actor:  models.ReceptionAuditActorSystem,
+		})
+		assert.Equal(t, e.ErrVersionMismatch(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetPVZReceptionAudit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	pvzID := uuid.New()
+	entry := models.ReceptionAuditEntry{
+		ReceptionID: uuid.New(),
+		PVZID:       pvzID,
+		Action:      models.ReceptionAuditFlaggedStale,
+		Actor:       models.ReceptionAuditActorSystem,
+		Reason:      "no activity since 2026-01-01T00:00:00Z",
+		CreatedAt:   time.Now(),
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM reception_audit WHERE pvz_id = \\$1").
+		WithArgs(pvzID).
+		WillReturnRows(sqlmock.NewRows([]string{"reception_id", "pvz_id", "action", "actor", "reason", "created_at"}).
+			AddRow(entry.ReceptionID, entry.PVZID, entry.Action, entry.Actor, entry.Reason, entry.CreatedAt))
+
+	entries, err := repo.GetPVZReceptionAudit(context.Background(), pvzID)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.ReceptionAuditEntry{entry}, entries)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
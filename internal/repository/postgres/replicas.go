@@ -0,0 +1,215 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"pvz-service/internal/config"
+	"pvz-service/internal/consistency"
+	"pvz-service/internal/logger/sl"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// replica is a read-only server. It serves the listing queries while it
+// passes the health check.
+type replica struct {
+	name    string
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+// replicaSet spreads reads over the healthy replicas and checks their health
+// in the background until closed
+type replicaSet struct {
+	replicas []*replica
+	next     atomic.Uint64
+	log      *slog.Logger
+
+	stop      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// openReplicas connects to the replicas of cfg with the pool settings of the
+// primary. A replica that is down at startup does not stop the service, it
+// takes reads once the health check sees it up.
+func openReplicas(cfg config.Database, log *slog.Logger) (*replicaSet, error) {
+	set := &replicaSet{
+		log:  log.With(slog.String("component", "repository/postgres/replicas")),
+		stop: make(chan struct{}),
+	}
+
+	for i, dsn := range cfg.Replicas {
+		if cfg.StatementTimeout > 0 {
+			dsn = withParam(dsn, "statement_timeout", fmt.Sprint(cfg.StatementTimeout.Milliseconds()))
+		}
+
+		db, err := sql.Open("pgx", dsn)
+		if err != nil {
+			set.close()
+			return nil, fmt.Errorf("replica %d: %w", i, err)
+		}
+
+		db.SetMaxOpenConns(cfg.MaxOpenConns)
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+		db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+		db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+		set.replicas = append(set.replicas, &replica{name: fmt.Sprintf("replica-%d", i), db: db})
+	}
+
+	set.check(cfg.ReplicaCheckInterval)
+
+	set.wg.Add(1)
+	go set.run(cfg.ReplicaCheckInterval)
+
+	return set, nil
+}
+
+// withParam adds a connection parameter to a DSN given either as a URL or
+// as key=value pairs
+func withParam(dsn, key, value string) string {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			// sql.Open reports the malformed DSN
+			return dsn
+		}
+		query := u.Query()
+		query.Set(key, value)
+		u.RawQuery = query.Encode()
+		return u.String()
+	}
+
+	return dsn + " " + key + "=" + value
+}
+
+func (s *replicaSet) run(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.check(interval)
+		}
+	}
+}
+
+// check pings every replica and updates its health, logging the changes
+func (s *replicaSet) check(timeout time.Duration) {
+	for _, r := range s.replicas {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err := r.db.PingContext(ctx)
+		cancel()
+
+		healthy := err == nil
+		if r.healthy.Swap(healthy) == healthy {
+			continue
+		}
+
+		if healthy {
+			s.log.Info("replica is up", slog.String("replica", r.name))
+		} else {
+			s.log.Warn("replica is down, reading from the other servers", slog.String("replica", r.name), sl.Err(err))
+		}
+	}
+}
+
+// pick returns the healthy replicas in turn, or nil when all are down
+func (s *replicaSet) pick() *replica {
+	healthy := make([]*replica, 0, len(s.replicas))
+	for _, r := range s.replicas {
+		if r.healthy.Load() {
+			healthy = append(healthy, r)
+		}
+	}
+
+	if len(healthy) == 0 {
+		return nil
+	}
+
+	return healthy[s.next.Add(1)%uint64(len(healthy))]
+}
+
+// markDown takes a replica out of rotation until the health check sees it up
+func (s *replicaSet) markDown(r *replica, err error) {
+	if r.healthy.Swap(false) {
+		s.log.Warn("replica is down, reading from the other servers", slog.String("replica", r.name), sl.Err(err))
+	}
+}
+
+func (s *replicaSet) close() {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		s.wg.Wait()
+
+		for _, r := range s.replicas {
+			r.db.Close()
+		}
+	})
+}
+
+// reader returns the replica for the read-only listing queries, or nil when
+// they go to the primary: there is no healthy replica or the caller has to
+// read its own writes
+func (p *Postgres) reader(ctx context.Context) *replica {
+	if p.replicas == nil || consistency.PrimaryRequired(ctx) {
+		return nil
+	}
+
+	return p.replicas.pick()
+}
+
+// queryRead runs a read-only listing query on the reader. When a replica
+// fails to run it because the connection broke, the replica is taken out of
+// rotation and the query is retried on the primary.
+func (p *Postgres) queryRead(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	r := p.reader(ctx)
+	if r == nil {
+		return p.db.QueryContext(ctx, query, args...)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err == nil || ctx.Err() != nil || !connectionError(err) {
+		return rows, err
+	}
+
+	p.replicas.markDown(r, err)
+	return p.db.QueryContext(ctx, query, args...)
+}
+
+// connectionError reports whether err means the server could not be reached
+// or dropped the connection, rather than rejected the query
+func connectionError(err error) bool {
+	var (
+		netErr     net.Error
+		connectErr *pgconn.ConnectError
+		pgErr      *pgconn.PgError
+	)
+	switch {
+	case errors.Is(err, driver.ErrBadConn), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return true
+	case errors.As(err, &netErr), errors.As(err, &connectErr):
+		return true
+	case errors.As(err, &pgErr):
+		// connection_exception, or the server is shutting down or starting up
+		return strings.HasPrefix(pgErr.Code, "08") || pgErr.Code == "57P01" || pgErr.Code == "57P02" || pgErr.Code == "57P03"
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (p *Postgres) GetPVZSchedule(ctx context.Context, pvzID uuid.UUID) (*models.PVZSchedule, error) {
+	var (
+		schedule   models.PVZSchedule
+		weekly     []byte
+		exceptions []byte
+	)
+	err := p.db.QueryRowContext(ctx,
+		`SELECT pvz_id, timezone, weekly, exceptions, receptions_allowed_until, updated_at
+		 FROM pvz_schedules
+		 WHERE pvz_id = $1`,
+		pvzID).Scan(&schedule.PVZID, &schedule.Timezone, &weekly, &exceptions, &schedule.ReceptionsAllowedUntil, &schedule.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, e.ErrNotFound()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(weekly, &schedule.Weekly); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(exceptions, &schedule.Exceptions); err != nil {
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+func (p *Postgres) SavePVZSchedule(ctx context.Context, schedule *models.PVZSchedule) error {
+	weekly, err := json.Marshal(nonNil(schedule.Weekly))
+	if err != nil {
+		return err
+	}
+	exceptions, err := json.Marshal(nonNil(schedule.Exceptions))
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.ExecContext(ctx,
+		`INSERT INTO pvz_schedules (pvz_id, timezone, weekly, exceptions, updated_at)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (pvz_id) DO UPDATE
+		 SET timezone = EXCLUDED.timezone, weekly = EXCLUDED.weekly,
+		     exceptions = EXCLUDED.exceptions, updated_at = EXCLUDED.updated_at`,
+		schedule.PVZID, schedule.Timezone, weekly, exceptions, schedule.UpdatedAt)
+	return err
+}
+
+func (p *Postgres) AllowReceptionsUntil(ctx context.Context, pvzID uuid.UUID, until time.Time) error {
+	res, err := p.db.ExecContext(ctx,
+		"UPDATE pvz_schedules SET receptions_allowed_until = $1 WHERE pvz_id = $2",
+		until, pvzID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return e.ErrNotFound()
+	}
+
+	return nil
+}
+
+func (p *Postgres) DeletePVZSchedule(ctx context.Context, pvzID uuid.UUID) error {
+	res, err := p.db.ExecContext(ctx, "DELETE FROM pvz_schedules WHERE pvz_id = $1", pvzID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return e.ErrNotFound()
+	}
+
+	return nil
+}
+
+// nonNil keeps empty schedule parts stored as JSON arrays rather than null
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPVZSchedule(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	pvzID := uuid.New()
+	now := time.Now()
+	columns := []string{"pvz_id", "timezone", "weekly", "exceptions", "receptions_allowed_until", "updated_at"}
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery("SELECT pvz_id, timezone, weekly, exceptions, receptions_allowed_until, updated_at FROM pvz_schedules WHERE pvz_id = \\$1").
+			WithArgs(pvzID).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(pvzID, "Europe/Moscow",
+				[]byte(`[{"weekday":"monday","intervals":[{"opens":"09:00","closes":"21:00"}]}]`),
+				[]byte(`[{"date":"2026-01-01","intervals":[],"note":"Новый год"}]`),
+				nil, now))
+
+		schedule, err := repo.GetPVZSchedule(context.Background(), pvzID)
+		assert.NoError(t, err)
+		assert.Equal(t, &models.PVZSchedule{
+			PVZID:    pvzID,
+			Timezone: "Europe/Moscow",
+			Weekly: []models.ScheduleDay{
+				{Weekday: "monday", Intervals: []models.ScheduleInterval{{Opens: "09:00", Closes: "21:00"}}},
+			},
+			Exceptions: []models.ScheduleException{
+				{Date: "2026-01-01", Intervals: []models.ScheduleInterval{}, Note: "Новый год"},
+			},
+			UpdatedAt: now,
+		}, schedule)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM pvz_schedules").
+			WithArgs(pvzID).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		_, err := repo.GetPVZSchedule(context.Background(), pvzID)
+		assert.Equal(t, e.ErrNotFound(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSavePVZSchedule(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	schedule := &models.PVZSchedule{
+		PVZID:     uuid.New(),
+		Timezone:  "Europe/Moscow",
+		UpdatedAt: time.Now(),
+	}
+
+	t.Run("EmptyPartsStoredAsArrays", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO pvz_schedules (.+) ON CONFLICT \\(pvz_id\\) DO UPDATE").
+			WithArgs(schedule.PVZID, schedule.Timezone, []byte(`[]`), []byte(`[]`), schedule.UpdatedAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.SavePVZSchedule(context.Background(), schedule)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAllowReceptionsUntil(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	pvzID := uuid.New()
+	until := time.Now().Add(time.Hour)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectExec("UPDATE pvz_schedules SET receptions_allowed_until = \\$1 WHERE pvz_id = \\$2").
+			WithArgs(until, pvzID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.AllowReceptionsUntil(context.Background(), pvzID, until))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NoSchedule", func(t *testing.T) {
+		mock.ExpectExec("UPDATE pvz_schedules").
+			WithArgs(until, pvzID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.Equal(t, e.ErrNotFound(), repo.AllowReceptionsUntil(context.Background(), pvzID, until))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDeletePVZSchedule(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	pvzID := uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM pvz_schedules WHERE pvz_id = \\$1").
+			WithArgs(pvzID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.DeletePVZSchedule(context.Background(), pvzID))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NoSchedule", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM pvz_schedules").
+			WithArgs(pvzID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.Equal(t, e.ErrNotFound(), repo.DeletePVZSchedule(context.Background(), pvzID))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
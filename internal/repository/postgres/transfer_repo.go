@@ -0,0 +1,308 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const transferColumns = `t.id, t.source_pvz_id, t.destination_pvz_id, t.status, t.reception_id,
+	t.created_at, t.dispatched_at, t.received_at,
+	COALESCE(array_agg(ti.product_id) FILTER (WHERE ti.product_id IS NOT NULL), '{}')`
+
+func scanTransfer(row rowScanner, transfer *models.Transfer) error {
+	transfer.ProductIDs = []uuid.UUID{}
+	return row.Scan(&transfer.ID, &transfer.SourcePVZID, &transfer.DestinationPVZID, &transfer.Status,
+		&transfer.ReceptionID, &transfer.CreatedAt, &transfer.DispatchedAt, &transfer.ReceivedAt,
+		uuidArray(&transfer.ProductIDs))
+}
+
+func (p *Postgres) CreateTransfer(ctx context.Context, transfer *models.Transfer) error {
+	return p.withCopyTx(ctx, func(tx *sql.Tx, copyRows copyFunc) error {
+		// Only products stored in the source PVZ and not assembled into another
+		// transfer can be moved. They stay locked until the transfer is saved.
+		rows, err := tx.QueryContext(ctx,
+			`SELECT p.id
+			 FROM products p
+			 JOIN receptions r ON r.id = p.reception_id
+			 WHERE p.id = ANY($1) AND r.pvz_id = $2 AND p.status = $3
+			   AND NOT EXISTS (
+			       SELECT 1
+			       FROM transfer_items ti
+			       JOIN transfers t ON t.id = ti.transfer_id
+			       WHERE ti.product_id = p.id AND t.status = $4
+			   )
+			 FOR UPDATE OF p`,
+			transfer.ProductIDs, transfer.SourcePVZID, models.ProductStatusStored, models.TransferStatusOutbound)
+		if err != nil {
+			return err
+		}
+
+		found := 0
+		for rows.Next() {
+			found++
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if found != len(transfer.ProductIDs) {
+			return e.ErrProductNotTransferable()
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO transfers (id, source_pvz_id, destination_pvz_id, status, created_at)
+			 VALUES ($1, $2, $3, $4, $5)`,
+			transfer.ID, transfer.SourcePVZID, transfer.DestinationPVZID, transfer.Status, transfer.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		items := make([][]any, len(transfer.ProductIDs))
+		for i, productID := range transfer.ProductIDs {
+			items[i] = []any{transfer.ID, productID}
+		}
+		return copyRows("transfer_items", []string{"transfer_id", "product_id"}, items)
+	})
+}
+
+func (p *Postgres) GetTransfer(ctx context.Context, transferID uuid.UUID) (*models.Transfer, error) {
+	var transfer models.Transfer
+	row := p.db.QueryRowContext(ctx,
+		`SELECT `+transferColumns+`
+		 FROM transfers t
+		 LEFT JOIN transfer_items ti ON ti.transfer_id = t.id
+		 WHERE t.id = $1
+		 GROUP BY t.id`,
+		transferID)
+	if err := scanTransfer(row, &transfer); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, e.ErrNotFound()
+		}
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (p *Postgres) GetPVZTransfers(ctx context.Context, pvzID uuid.UUID, status models.TransferStatus) ([]models.Transfer, error) {
+	rows, err := p.db.QueryContext(ctx,
+		`SELECT `+transferColumns+`
+		 FROM transfers t
+		 LEFT JOIN transfer_items ti ON ti.transfer_id = t.id
+		 WHERE (t.source_pvz_id = $1 OR t.destination_pvz_id = $1)
+		   AND ($2 = '' OR t.status = $2)
+		 GROUP BY t.id
+		 ORDER BY t.created_at DESC`,
+		pvzID, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transfers := []models.Transfer{}
+	for rows.Next() {
+		var transfer models.Transfer
+		if err := scanTransfer(rows, &transfer); err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, transfer)
+	}
+	return transfers, rows.Err()
+}
+
+func (p *Postgres) DispatchTransfer(ctx context.Context, transferID uuid.UUID, dispatchedAt time.Time) ([]models.ProductStatusChange, error) {
+	var changes []models.ProductStatusChange
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		var (
+			sourceID uuid.UUID
+			items    int
+		)
+		err := tx.QueryRowContext(ctx,
+			`UPDATE transfers SET status = $1, dispatched_at = $2
+			 WHERE id = $3 AND status = $4
+			 RETURNING source_pvz_id, (SELECT COUNT(*) FROM transfer_items WHERE transfer_id = $3)`,
+			models.TransferStatusInTransit, dispatchedAt, transferID, models.TransferStatusOutbound).Scan(&sourceID, &items)
+		if err == sql.ErrNoRows {
+			return e.ErrInvalidTransferState()
+		}
+		if err != nil {
+			return err
+		}
+
+		rows, err := tx.QueryContext(ctx,
+			`UPDATE products p SET status = $1
+			 FROM transfer_items ti
+			 WHERE ti.transfer_id = $2 AND p.id = ti.product_id AND p.status = $3
+			 RETURNING p.id, p.type_id, p.reception_id`,
+			models.ProductStatusInTransit, transferID, models.ProductStatusStored)
+		if err != nil {
+			return err
+		}
+		moved, err := scanTransferProducts(rows)
+		if err != nil {
+			return err
+		}
+		// Some product was issued or written off after the transfer was assembled
+		if len(moved) != items {
+			return e.ErrProductNotTransferable()
+		}
+
+		changes = make([]models.ProductStatusChange, 0, len(moved))
+		for _, product := range moved {
+			if err := adjustOccupancy(ctx, tx, sourceID, product.TypeID, -1); err != nil {
+				return err
+			}
+
+			change := models.ProductStatusChange{
+				ProductID:   product.ID,
+				PVZID:       sourceID,
+				ReceptionID: product.ReceptionID,
+				From:        models.ProductStatusStored,
+				To:          models.ProductStatusInTransit,
+				ChangedAt:   dispatchedAt,
+			}
+			if err := recordStatusChange(ctx, tx, &change); err != nil {
+				return err
+			}
+			changes = append(changes, change)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func (p *Postgres) ReceiveTransfer(ctx context.Context, transferID, receptionID uuid.UUID, receptionVersion int, receivedAt time.Time) (int, []models.ProductStatusChange, error) {
+	var (
+		version int
+		changes []models.ProductStatusChange
+	)
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		var destinationID uuid.UUID
+		err := tx.QueryRowContext(ctx,
+			`UPDATE transfers SET status = $1, received_at = $2, reception_id = $3
+			 WHERE id = $4 AND status = $5
+			 RETURNING destination_pvz_id`,
+			models.TransferStatusReceived, receivedAt, receptionID, transferID, models.TransferStatusInTransit).Scan(&destinationID)
+		if err == sql.ErrNoRows {
+			return e.ErrInvalidTransferState()
+		}
+		if err != nil {
+			return err
+		}
+
+		pvzID, newVersion, err := bumpReceptionVersion(ctx, tx, receptionID, receptionVersion)
+		if err != nil {
+			return err
+		}
+		if pvzID != destinationID {
+			return e.ErrNoActiveReception()
+		}
+		version = newVersion
+
+		// Products written off as lost on the way are not received
+		rows, err := tx.QueryContext(ctx,
+			`SELECT p.id, p.type_id, p.reception_id
+			 FROM products p
+			 JOIN transfer_items ti ON ti.product_id = p.id
+			 WHERE ti.transfer_id = $1 AND p.status = $2
+			 FOR UPDATE OF p`,
+			transferID, models.ProductStatusInTransit)
+		if err != nil {
+			return err
+		}
+		arrived, err := scanTransferProducts(rows)
+		if err != nil {
+			return err
+		}
+
+		changes = make([]models.ProductStatusChange, 0, len(arrived))
+		for _, product := range arrived {
+			if err := reserveCapacity(ctx, tx, destinationID, product.TypeID); err != nil {
+				return err
+			}
+
+			// A product is dated by its acceptance, so it never predates the
+			// reception it belongs to and listings may bound products by it
+			_, err := tx.ExecContext(ctx,
+				"UPDATE products SET status = $1, reception_id = $2, date_time = $3 WHERE id = $4",
+				models.ProductStatusAccepted, receptionID, receivedAt, product.ID)
+			if err != nil {
+				return err
+			}
+			_, err = tx.ExecContext(ctx, "UPDATE product_keys SET date_time = $1 WHERE id = $2", receivedAt, product.ID)
+			if err != nil {
+				return err
+			}
+
+			change := models.ProductStatusChange{
+				ProductID:   product.ID,
+				PVZID:       destinationID,
+				ReceptionID: receptionID,
+				From:        models.ProductStatusInTransit,
+				To:          models.ProductStatusAccepted,
+				ChangedAt:   receivedAt,
+			}
+			if err := recordStatusChange(ctx, tx, &change); err != nil {
+				return err
+			}
+			changes = append(changes, change)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return version, changes, nil
+}
+
+func (p *Postgres) CancelTransfer(ctx context.Context, transferID uuid.UUID) error {
+	res, err := p.db.ExecContext(ctx,
+		"UPDATE transfers SET status = $1 WHERE id = $2 AND status = $3",
+		models.TransferStatusCancelled, transferID, models.TransferStatusOutbound)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return e.ErrInvalidTransferState()
+	}
+
+	return nil
+}
+
+// scanTransferProducts reads and closes the id, type_id and reception_id rows
+// of transfer products, so the transaction can be used again
+func scanTransferProducts(rows *sql.Rows) ([]models.Product, error) {
+	defer rows.Close()
+
+	var products []models.Product
+	for rows.Next() {
+		var product models.Product
+		if err := rows.Scan(&product.ID, &product.TypeID, &product.ReceptionID); err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+	return products, rows.Err()
+}
+
+// recordStatusChange adds the change to the product history and the outbox
+func recordStatusChange(ctx context.Context, tx *sql.Tx, change *models.ProductStatusChange) error {
+	if err := insertStatusChange(ctx, tx, change); err != nil {
+		return err
+	}
+	return insertEvent(ctx, tx, models.EventProductStatusChanged, change.PVZID, change.ReceptionID, change)
+}
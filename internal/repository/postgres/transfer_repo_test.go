@@ -0,0 +1,286 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTransfer(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := copied{}
+	repo := &Postgres{db: db, copyRows: rows.copyRows}
+
+	first, second := uuid.New(), uuid.New()
+	transfer := &models.Transfer{
+		ID:               uuid.New(),
+		SourcePVZID:      uuid.New(),
+		DestinationPVZID: uuid.New(),
+		Status:           models.TransferStatusOutbound,
+		ProductIDs:       []uuid.UUID{first, second},
+		CreatedAt:        time.Now(),
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT p.id FROM products p JOIN receptions r (.+) FOR UPDATE OF p").
+			WithArgs(transfer.ProductIDs, transfer.SourcePVZID, models.ProductStatusStored, models.TransferStatusOutbound).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(first).AddRow(second))
+		mock.ExpectExec("INSERT INTO transfers").
+			WithArgs(transfer.ID, transfer.SourcePVZID, transfer.DestinationPVZID, transfer.Status, transfer.CreatedAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.CreateTransfer(context.Background(), transfer))
+		assert.Equal(t, [][]any{{transfer.ID, first}, {transfer.ID, second}}, rows["transfer_items"])
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ProductNotStored", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT p.id FROM products p").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(first))
+		mock.ExpectRollback()
+
+		err := repo.CreateTransfer(context.Background(), transfer)
+		assert.Equal(t, e.ErrProductNotTransferable(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetTransfer(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	transferID := uuid.New()
+	sourceID := uuid.New()
+	destinationID := uuid.New()
+	productID := uuid.New()
+	now := time.Now()
+	columns := []string{"id", "source_pvz_id", "destination_pvz_id", "status", "reception_id",
+		"created_at", "dispatched_at", "received_at", "product_ids"}
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM transfers t LEFT JOIN transfer_items ti (.+) WHERE t.id = \\$1").
+			WithArgs(transferID).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(transferID, sourceID, destinationID, models.TransferStatusInTransit, nil, now, now, nil, "{"+productID.String()+"}"))
+
+		transfer, err := repo.GetTransfer(context.Background(), transferID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.TransferStatusInTransit, transfer.Status)
+		assert.Equal(t, []uuid.UUID{productID}, transfer.ProductIDs)
+		assert.Nil(t, transfer.ReceptionID)
+		assert.NotNil(t, transfer.DispatchedAt)
+		assert.Nil(t, transfer.ReceivedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM transfers t").
+			WithArgs(transferID).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		_, err := repo.GetTransfer(context.Background(), transferID)
+		assert.Equal(t, e.ErrNotFound(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDispatchTransfer(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	transferID := uuid.New()
+	sourceID := uuid.New()
+	productID := uuid.New()
+	receptionID := uuid.New()
+	now := time.Now()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE transfers SET status = \\$1, dispatched_at = \\$2").
+			WithArgs(models.TransferStatusInTransit, now, transferID, models.TransferStatusOutbound).
+			WillReturnRows(sqlmock.NewRows([]string{"source_pvz_id", "count"}).AddRow(sourceID, 1))
+		mock.ExpectQuery("UPDATE products p SET status = \\$1 FROM transfer_items ti").
+			WithArgs(models.ProductStatusInTransit, transferID, models.ProductStatusStored).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "type_id", "reception_id"}).AddRow(productID, 1, receptionID))
+		mock.ExpectExec("INSERT INTO pvz_occupancy").
+			WithArgs(sourceID, 1, -1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO product_status_history").
+			WithArgs(productID, sourceID, receptionID, "stored", models.ProductStatusInTransit, "", now).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO outbox").
+			WithArgs(sqlmock.AnyArg(), models.EventProductStatusChanged, sourceID, receptionID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		changes, err := repo.DispatchTransfer(context.Background(), transferID, now)
+		assert.NoError(t, err)
+		assert.Len(t, changes, 1)
+		assert.Equal(t, productID, changes[0].ProductID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotOutbound", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE transfers SET status").
+			WillReturnRows(sqlmock.NewRows([]string{"source_pvz_id", "count"}))
+		mock.ExpectRollback()
+
+		_, err := repo.DispatchTransfer(context.Background(), transferID, now)
+		assert.Equal(t, e.ErrInvalidTransferState(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ProductIssuedMeanwhile", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE transfers SET status").
+			WillReturnRows(sqlmock.NewRows([]string{"source_pvz_id", "count"}).AddRow(sourceID, 2))
+		mock.ExpectQuery("UPDATE products p SET status").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "type_id", "reception_id"}).AddRow(productID, 1, receptionID))
+		mock.ExpectRollback()
+
+		_, err := repo.DispatchTransfer(context.Background(), transferID, now)
+		assert.Equal(t, e.ErrProductNotTransferable(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReceiveTransfer(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	transferID := uuid.New()
+	destinationID := uuid.New()
+	productID := uuid.New()
+	sourceReceptionID := uuid.New()
+	receptionID := uuid.New()
+	now := time.Now()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE transfers SET status = \\$1, received_at = \\$2, reception_id = \\$3").
+			WithArgs(models.TransferStatusReceived, now, receptionID, transferID, models.TransferStatusInTransit).
+			WillReturnRows(sqlmock.NewRows([]string{"destination_pvz_id"}).AddRow(destinationID))
+		mock.ExpectQuery("UPDATE receptions SET version").
+			WithArgs(receptionID, 3).
+			WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "version"}).AddRow(destinationID, 4))
+		mock.ExpectQuery("SELECT p.id, p.type_id, p.reception_id FROM products p JOIN transfer_items ti").
+			WithArgs(transferID, models.ProductStatusInTransit).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "type_id", "reception_id"}).AddRow(productID, 1, sourceReceptionID))
+		mock.ExpectQuery("SELECT p.capacity").
+			WithArgs(destinationID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"capacity", "type_capacity", "count", "type_count"}).AddRow(0, 0, 0, 0))
+		mock.ExpectExec("INSERT INTO pvz_occupancy").
+			WithArgs(destinationID, 1, 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("UPDATE products SET status = \\$1, reception_id = \\$2, date_time = \\$3 WHERE id = \\$4").
+			WithArgs(models.ProductStatusAccepted, receptionID, now, productID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("UPDATE product_keys SET date_time = \\$1 WHERE id = \\$2").
+			WithArgs(now, productID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO product_status_history").
+			WithArgs(productID, destinationID, receptionID, "in_transit", models.ProductStatusAccepted, "", now).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO outbox").
+			WithArgs(sqlmock.AnyArg(), models.EventProductStatusChanged, destinationID, receptionID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		version, changes, err := repo.ReceiveTransfer(context.Background(), transferID, receptionID, 3, now)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, version)
+		assert.Len(t, changes, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ReceptionOfAnotherPVZ", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE transfers SET status").
+			WillReturnRows(sqlmock.NewRows([]string{"destination_pvz_id"}).AddRow(destinationID))
+		mock.ExpectQuery("UPDATE receptions SET version").
+			WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "version"}).AddRow(uuid.New(), 4))
+		mock.ExpectRollback()
+
+		_, _, err := repo.ReceiveTransfer(context.Background(), transferID, receptionID, 3, now)
+		assert.Equal(t, e.ErrNoActiveReception(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("CapacityExceeded", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE transfers SET status").
+			WillReturnRows(sqlmock.NewRows([]string{"destination_pvz_id"}).AddRow(destinationID))
+		mock.ExpectQuery("UPDATE receptions SET version").
+			WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "version"}).AddRow(destinationID, 4))
+		mock.ExpectQuery("SELECT p.id, p.type_id, p.reception_id FROM products p").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "type_id", "reception_id"}).AddRow(productID, 1, sourceReceptionID))
+		mock.ExpectQuery("SELECT p.capacity").
+			WillReturnRows(sqlmock.NewRows([]string{"capacity", "type_capacity", "count", "type_count"}).AddRow(5, 0, 5, 2))
+		mock.ExpectRollback()
+
+		_, _, err := repo.ReceiveTransfer(context.Background(), transferID, receptionID, 3, now)
+		assert.Equal(t, e.ErrCapacityExceeded(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCancelTransfer(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	transferID := uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectExec("UPDATE transfers SET status = \\$1 WHERE id = \\$2 AND status = \\$3").
+			WithArgs(models.TransferStatusCancelled, transferID, models.TransferStatusOutbound).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.CancelTransfer(context.Background(), transferID))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("AlreadyDispatched", func(t *testing.T) {
+		mock.ExpectExec("UPDATE transfers SET status").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.CancelTransfer(context.Background(), transferID)
+		assert.Equal(t, e.ErrInvalidTransferState(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
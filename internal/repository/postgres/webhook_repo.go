@@ -0,0 +1,222 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (p *Postgres) InsertSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	if sub.PVZID != nil {
+		if _, err := p.CheckPVZ(ctx, *sub.PVZID); err != nil {
+			return err
+		}
+	}
+	if sub.CityName != "" {
+		cityID, err := p.GetCityID(ctx, sub.CityName)
+		if err != nil {
+			return err
+		}
+		sub.CityID = &cityID
+	}
+
+	eventTypes := make([]string, len(sub.EventTypes))
+	for i, t := range sub.EventTypes {
+		eventTypes[i] = string(t)
+	}
+
+	_, err := p.db.ExecContext(ctx,
+		`INSERT INTO webhook_subscriptions (id, url, secret, event_types, pvz_id, city_id, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		sub.ID, sub.URL, sub.Secret, eventTypes, sub.PVZID, sub.CityID, sub.CreatedAt)
+	return err
+}
+
+func (p *Postgres) GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	rows, err := p.db.QueryContext(ctx,
+		`SELECT s.id, s.url, s.secret, s.event_types, s.pvz_id, s.city_id, COALESCE(c.name, ''), s.created_at
+		 FROM webhook_subscriptions s
+		 LEFT JOIN cities c ON s.city_id = c.id
+		 ORDER BY s.created_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []models.WebhookSubscription
+	for rows.Next() {
+		var (
+			sub        models.WebhookSubscription
+			eventTypes []string
+			pvzID      uuid.NullUUID
+			cityID     sql.NullInt64
+		)
+		if err := rows.Scan(&sub.ID, &sub.URL, &sub.Secret, textArray(&eventTypes), &pvzID, &cityID, &sub.CityName, &sub.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		sub.EventTypes = make([]models.EventType, len(eventTypes))
+		for i, t := range eventTypes {
+			sub.EventTypes[i] = models.EventType(t)
+		}
+		if pvzID.Valid {
+			sub.PVZID = &pvzID.UUID
+		}
+		if cityID.Valid {
+			id := int(cityID.Int64)
+			sub.CityID = &id
+		}
+
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+func (p *Postgres) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	res, err := p.db.ExecContext(ctx, "DELETE FROM webhook_subscriptions WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return e.ErrNotFound()
+	}
+
+	return nil
+}
+
+func (p *Postgres) EnqueueDeliveries(ctx context.Context, event models.Event) (int, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := p.db.ExecContext(ctx,
+		`INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, next_attempt_at)
+		 SELECT s.id, $1, $2, $3, NOW()
+		 FROM webhook_subscriptions s
+		 WHERE (cardinality(s.event_types) = 0 OR $2 = ANY(s.event_types))
+		 AND (s.pvz_id IS NULL OR s.pvz_id = $4)
+		 AND (s.city_id IS NULL OR s.city_id = (SELECT city_id FROM pvz WHERE id = $4))
+		 ON CONFLICT (subscription_id, event_id) DO NOTHING`,
+		event.ID, string(event.Type), payload, event.PVZID)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := res.RowsAffected()
+	return int(affected), err
+}
+
+func (p *Postgres) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	rows, err := p.db.QueryContext(ctx,
+		`UPDATE webhook_deliveries d
+		 SET next_attempt_at = NOW() + $2 * INTERVAL '1 millisecond'
+		 FROM webhook_subscriptions s
+		 WHERE s.id = d.subscription_id
+		 AND d.id IN (
+			 SELECT id FROM webhook_deliveries
+			 WHERE status = 'pending' AND next_attempt_at <= NOW()
+			 ORDER BY next_attempt_at
+			 LIMIT $1
+			 FOR UPDATE SKIP LOCKED
+		 )
+		 RETURNING d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status,
+		           d.attempts, d.next_attempt_at, d.last_error, d.last_status_code, d.created_at,
+		           s.url, s.secret`,
+		limit, lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		var d models.WebhookDelivery
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &d.Status,
+			&d.Attempts, &d.NextAttemptAt, &d.LastError, &d.LastStatusCode, &d.CreatedAt,
+			&d.URL, &d.Secret); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func (p *Postgres) MarkDeliverySucceeded(ctx context.Context, id uuid.UUID, statusCode int) error {
+	_, err := p.db.ExecContext(ctx,
+		`UPDATE webhook_deliveries
+		 SET status = 'delivered', attempts = attempts + 1, last_error = '', last_status_code = $1, delivered_at = NOW()
+		 WHERE id = $2`,
+		statusCode, id)
+	return err
+}
+
+func (p *Postgres) MarkDeliveryFailed(ctx context.Context, id uuid.UUID, statusCode int, lastErr string, nextAttemptAt time.Time, dead bool) error {
+	status := models.DeliveryStatusPending
+	if dead {
+		status = models.DeliveryStatusDead
+	}
+
+	_, err := p.db.ExecContext(ctx,
+		`UPDATE webhook_deliveries
+		 SET status = $1, attempts = attempts + 1, last_error = $2, last_status_code = $3, next_attempt_at = $4
+		 WHERE id = $5`,
+		status, lastErr, statusCode, nextAttemptAt, id)
+	return err
+}
+
+func (p *Postgres) GetDeadDeliveries(ctx context.Context, subscriptionID uuid.UUID) ([]models.WebhookDelivery, error) {
+	rows, err := p.db.QueryContext(ctx,
+		`SELECT id, subscription_id, event_id, event_type, payload, status,
+		        attempts, next_attempt_at, last_error, last_status_code, created_at
+		 FROM webhook_deliveries
+		 WHERE subscription_id = $1 AND status = 'dead'
+		 ORDER BY created_at DESC`,
+		subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		var d models.WebhookDelivery
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &d.Status,
+			&d.Attempts, &d.NextAttemptAt, &d.LastError, &d.LastStatusCode, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func (p *Postgres) RedeliverDelivery(ctx context.Context, id uuid.UUID) error {
+	res, err := p.db.ExecContext(ctx,
+		`UPDATE webhook_deliveries
+		 SET status = 'pending', attempts = 0, next_attempt_at = NOW(), delivered_at = NULL
+		 WHERE id = $1 AND status = 'dead'`,
+		id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return e.ErrNotFound()
+	}
+
+	return nil
+}
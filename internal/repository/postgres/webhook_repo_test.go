@@ -0,0 +1,221 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertSubscription(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	t.Run("WithCity", func(t *testing.T) {
+		sub := &models.WebhookSubscription{
+			ID:         uuid.New(),
+			URL:        "http://example.com/hook",
+			Secret:     "secret-secret-secret",
+			EventTypes: []models.EventType{models.EventReceptionClosed},
+			CityName:   "Москва",
+			CreatedAt:  time.Now(),
+		}
+
+		mock.ExpectQuery("SELECT id FROM cities WHERE name = \\$1").
+			WithArgs("Москва").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec("INSERT INTO webhook_subscriptions").
+			WithArgs(sub.ID, sub.URL, sub.Secret, []string{"reception.closed"}, nil, sqlmock.AnyArg(), sub.CreatedAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := repo.InsertSubscription(context.Background(), sub)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, *sub.CityID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("UnknownPVZ", func(t *testing.T) {
+		pvzID := uuid.New()
+		mock.ExpectQuery("SELECT EXISTS\\(SELECT 1 FROM pvz WHERE id = \\$1 AND deleted_at IS NULL\\)").
+			WithArgs(pvzID).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+		err := repo.InsertSubscription(context.Background(), &models.WebhookSubscription{PVZID: &pvzID})
+		assert.Equal(t, e.ErrNotFound(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("CityNotAllowed", func(t *testing.T) {
+		mock.ExpectQuery("SELECT id FROM cities WHERE name = \\$1").
+			WithArgs("Unknown").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		err := repo.InsertSubscription(context.Background(), &models.WebhookSubscription{CityName: "Unknown"})
+		assert.Equal(t, e.ErrCityNotAllowed(), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetSubscriptions(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	subID, pvzID := uuid.New(), uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM webhook_subscriptions s LEFT JOIN cities c").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url", "secret", "event_types", "pvz_id", "city_id", "name", "created_at"}).
+			AddRow(subID, "http://example.com/hook", "secret", "{product.added,product.deleted}", pvzID, nil, "", now))
+
+	subs, err := repo.GetSubscriptions(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, subs, 1)
+	assert.Equal(t, []models.EventType{models.EventProductAdded, models.EventProductDeleted}, subs[0].EventTypes)
+	assert.Equal(t, pvzID, *subs[0].PVZID)
+	assert.Nil(t, subs[0].CityID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteSubscription(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+	id := uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM webhook_subscriptions WHERE id = \\$1").
+			WithArgs(id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.DeleteSubscription(context.Background(), id))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM webhook_subscriptions WHERE id = \\$1").
+			WithArgs(id).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.Equal(t, e.ErrNotFound(), repo.DeleteSubscription(context.Background(), id))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestEnqueueDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+	event := models.Event{ID: uuid.New(), Type: models.EventReceptionOpened, PVZID: uuid.New(), ReceptionID: uuid.New()}
+
+	mock.ExpectExec("INSERT INTO webhook_deliveries (.+) SELECT (.+) FROM webhook_subscriptions s (.+) ON CONFLICT \\(subscription_id, event_id\\) DO NOTHING").
+		WithArgs(event.ID, "reception.opened", sqlmock.AnyArg(), event.PVZID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	enqueued, err := repo.EnqueueDeliveries(context.Background(), event)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, enqueued)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClaimDueDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+
+	deliveryID, subID, eventID := uuid.New(), uuid.New(), uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery("UPDATE webhook_deliveries d SET next_attempt_at (.+) FOR UPDATE SKIP LOCKED (.+) RETURNING").
+		WithArgs(50, int64(10000)).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "subscription_id", "event_id", "event_type", "payload", "status",
+			"attempts", "next_attempt_at", "last_error", "last_status_code", "created_at", "url", "secret",
+		}).AddRow(deliveryID, subID, eventID, "reception.closed", []byte(`{}`), "pending",
+			1, now, "timeout", 0, now, "http://example.com/hook", "secret"))
+
+	deliveries, err := repo.ClaimDueDeliveries(context.Background(), 50, 10*time.Second)
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+	assert.Equal(t, deliveryID, deliveries[0].ID)
+	assert.Equal(t, models.EventReceptionClosed, deliveries[0].EventType)
+	assert.Equal(t, "http://example.com/hook", deliveries[0].URL)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkDeliveryFailed(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+	id := uuid.New()
+	next := time.Now().Add(time.Minute)
+
+	tests := []struct {
+		name   string
+		dead   bool
+		status models.DeliveryStatus
+	}{
+		{name: "Retry", dead: false, status: models.DeliveryStatusPending},
+		{name: "Dead", dead: true, status: models.DeliveryStatusDead},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectExec("UPDATE webhook_deliveries SET status = \\$1, attempts = attempts \\+ 1").
+				WithArgs(tt.status, "boom", 500, next, id).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			assert.NoError(t, repo.MarkDeliveryFailed(context.Background(), id, 500, "boom", next, tt.dead))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRedeliverDelivery(t *testing.T) {
+	db, mock, err := sqlmock.New(pgxArgs)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Postgres{db: db}
+	id := uuid.New()
+
+	mock.ExpectExec("UPDATE webhook_deliveries SET status = 'pending', attempts = 0, next_attempt_at = NOW\\(\\), delivered_at = NULL WHERE id = \\$1 AND status = 'dead'").
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.Equal(t, e.ErrNotFound(), repo.RedeliverDelivery(context.Background(), id))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -3,8 +3,6 @@ package repository
 import (
 	"context"
 	"fmt"
-	"log/slog"
-	"pvz-service/internal/config"
 	"pvz-service/internal/models"
 	"time"
 
@@ -12,23 +10,107 @@ import (
 )
 
 type PVZRepository interface {
-	CloseConnection()
-
 	// Basic PVZ operations
 	InsertPVZ(ctx context.Context, pvz *models.PVZ) error
 	CheckPVZ(ctx context.Context, pvzID uuid.UUID) (bool, error)
 	GetCityID(ctx context.Context, cityName string) (int, error)
 	GetPVZsWithNoFilter(ctx context.Context) ([]models.PVZ, error)
 
+	// PVZ lifecycle operations. Soft-deleted PVZs are reported as ErrNotFound
+	// by GetPVZ and CheckPVZ but stay in the historic queries below.
+	GetPVZ(ctx context.Context, pvzID uuid.UUID) (*models.PVZ, error)
+	UpdatePVZ(ctx context.Context, pvz *models.PVZ, version int) (int, error)
+	DeletePVZ(ctx context.Context, pvzID uuid.UUID, version int) error
+
+	// GetNearbyPVZs returns up to limit active PVZs within radius meters of
+	// point, closest first. PVZs without a location are never returned.
+	GetNearbyPVZs(ctx context.Context, point models.GeoPoint, radius float64, limit int) ([]models.NearbyPVZ, error)
+
+	// PVZ working hours. GetPVZSchedule, AllowReceptionsUntil and
+	// DeletePVZSchedule return ErrNotFound when the PVZ has no schedule, i.e.
+	// it is always open. SavePVZSchedule keeps ReceptionsAllowedUntil, which
+	// only AllowReceptionsUntil sets.
+	GetPVZSchedule(ctx context.Context, pvzID uuid.UUID) (*models.PVZSchedule, error)
+	SavePVZSchedule(ctx context.Context, schedule *models.PVZSchedule) error
+	AllowReceptionsUntil(ctx context.Context, pvzID uuid.UUID, until time.Time) error
+	DeletePVZSchedule(ctx context.Context, pvzID uuid.UUID) error
+
+	// PVZ occupancy. InsertProduct returns ErrCapacityExceeded when the PVZ
+	// or the product type limit is already reached.
+	GetPVZOccupancy(ctx context.Context, pvzID uuid.UUID) (*models.PVZOccupancy, error)
+	ListPVZOccupancy(ctx context.Context) ([]models.PVZOccupancy, error)
+	ReplaceTypeCapacities(ctx context.Context, pvzID uuid.UUID, capacities []models.TypeCapacity) error
+
 	// Reception operations
 	InsertReception(ctx context.Context, reception *models.Reception) error
 	GetActiveReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error)
-	UpdateReceptionStatus(ctx context.Context, receptionID uuid.UUID, status models.ReceptionStatus) error
+	// UpdateReceptionStatus, InsertProduct and DeleteProduct change a reception
+	// only while its version still equals the given one and return the new
+	// version; otherwise they return ErrVersionMismatch.
+	UpdateReceptionStatus(ctx context.Context, receptionID uuid.UUID, status models.ReceptionStatus, version int) (int, error)
+
+	// Product operations. GetLastProduct and DeleteProduct only see products
+	// scanned in the reception; ones received with a transfer stay with it.
+	// GetLastProduct looks at products dated since or later, which passing the
+	// reception date loses nothing. DeleteProduct also returns the photo key of
+	// the deleted product, empty when it had no photo, for the caller to remove
+	// the stored photo. Product IDs are unique across receptions.
+	InsertProduct(ctx context.Context, product *models.Product, receptionVersion int) (int, error)
+	GetLastProduct(ctx context.Context, receptionID uuid.UUID, since time.Time) (*models.Product, error)
+	DeleteProduct(ctx context.Context, productID uuid.UUID, receptionVersion int) (int, string, error)
+
+	// Product status operations. ChangeProductStatus moves a product from
+	// change.From to change.To, filling in change.PVZID, and returns
+	// ErrInvalidStatusTransition when the product is no longer in change.From.
+	GetProduct(ctx context.Context, productID uuid.UUID) (*models.Product, error)
+	ChangeProductStatus(ctx context.Context, change *models.ProductStatusChange) error
+	GetProductHistory(ctx context.Context, productID uuid.UUID) ([]models.ProductStatusChange, error)
+
+	// Condition reports of products in an open reception. Both return
+	// ErrProductNotInReception once the product is no longer accepted or its
+	// reception is closed. SetProductCondition also returns the PVZ of the
+	// reception and SetProductPhoto the key of the replaced photo.
+	SetProductCondition(ctx context.Context, productID uuid.UUID, condition models.ProductCondition, note string) (*models.Product, uuid.UUID, error)
+	SetProductPhoto(ctx context.Context, productID uuid.UUID, photoKey string) (string, error)
 
-	// Product operations
-	InsertProduct(ctx context.Context, product *models.Product) error
-	GetLastProduct(ctx context.Context, receptionID uuid.UUID) (*models.Product, error)
-	DeleteProduct(ctx context.Context, productID uuid.UUID) error
+	// Transfer operations. DispatchTransfer, ReceiveTransfer and CancelTransfer
+	// return ErrInvalidTransferState when the transfer is not in the status
+	// they start from. ReceiveTransfer adds the products to the given reception
+	// of the destination PVZ like InsertProduct does.
+	CreateTransfer(ctx context.Context, transfer *models.Transfer) error
+	GetTransfer(ctx context.Context, transferID uuid.UUID) (*models.Transfer, error)
+	GetPVZTransfers(ctx context.Context, pvzID uuid.UUID, status models.TransferStatus) ([]models.Transfer, error)
+	DispatchTransfer(ctx context.Context, transferID uuid.UUID, dispatchedAt time.Time) ([]models.ProductStatusChange, error)
+	ReceiveTransfer(ctx context.Context, transferID, receptionID uuid.UUID, receptionVersion int, receivedAt time.Time) (int, []models.ProductStatusChange, error)
+	CancelTransfer(ctx context.Context, transferID uuid.UUID) error
+
+	// Delivery manifests. InsertReception links the reception's manifest and
+	// returns ErrManifestNotAvailable unless it is pending for the same PVZ.
+	// Closing the reception with UpdateReceptionStatus reconciles it.
+	CreateManifest(ctx context.Context, manifest *models.Manifest) error
+	GetManifest(ctx context.Context, manifestID uuid.UUID) (*models.Manifest, error)
+	GetPVZManifests(ctx context.Context, pvzID uuid.UUID, status models.ManifestStatus) ([]models.Manifest, error)
+
+	// Stale reception handling. GetStaleReceptions returns the in_progress
+	// receptions without activity since idleSince. ForceCloseReception closes a
+	// reception like UpdateReceptionStatus and records entry in the audit in
+	// the same transaction.
+	GetStaleReceptions(ctx context.Context, idleSince time.Time) ([]models.StaleReception, error)
+	ForceCloseReception(ctx context.Context, receptionID uuid.UUID, version int, entry *models.ReceptionAuditEntry) (int, error)
+	InsertReceptionAudit(ctx context.Context, entry *models.ReceptionAuditEntry) error
+	GetPVZReceptionAudit(ctx context.Context, pvzID uuid.UUID) ([]models.ReceptionAuditEntry, error)
+
+	// Reception archive. ArchiveReceptions moves up to limit closed receptions
+	// started before closedBefore, oldest first, to the archive together with
+	// their products and the product history, and returns how many receptions
+	// and products were moved. Only receptions whose products are all settled
+	// and that no transfer refers to are moved. Their manifests and audit
+	// entries stay, detached from the live reception. Photos are not
+	// archived: the keys of the moved products' photos are returned for the
+	// caller to remove the stored photos.
+	ArchiveReceptions(ctx context.Context, closedBefore, archivedAt time.Time, limit int) (int, int, []string, error)
+	GetArchivedReceptions(ctx context.Context, pvzID uuid.UUID, from, to time.Time, limit, offset int) ([]models.Reception, error)
+	GetArchivedProducts(ctx context.Context, receptionIDs []uuid.UUID) ([]models.Product, error)
 
 	// Product type operations
 	GetProductTypeID(ctx context.Context, productTypeName string) (int, error)
@@ -36,23 +118,26 @@ type PVZRepository interface {
 	// Query operations
 	GetPVZs(ctx context.Context, from, to time.Time, limit, offset int) ([]models.PVZ, error)
 	GetReceptionsForPVZs(ctx context.Context, pvzIDs []uuid.UUID, from, to time.Time) ([]models.Reception, error)
-	GetProductsForReceptions(ctx context.Context, receptionIDs []uuid.UUID) ([]models.Product, error)
+	// GetProductsForReceptions returns the products of the receptions dated
+	// since or later. A product is never dated before its reception, so the
+	// earliest reception date loses nothing and lets the database skip the
+	// months before it.
+	GetProductsForReceptions(ctx context.Context, receptionIDs []uuid.UUID, since time.Time) ([]models.Product, error)
+
+	// IteratePVZs reads PVZs matching filter row by row and calls fn for each one.
+	// Iteration stops at the first error returned by fn. Like GetPVZs, a filter
+	// by reception date includes soft-deleted PVZs; without one they are left
+	// out as in GetPVZsWithNoFilter.
+	IteratePVZs(ctx context.Context, filter models.PVZFilter, fn func(models.PVZ) error) error
 }
 
-func CreatePVZRepo(cfg *config.Config, log *slog.Logger) (PVZRepository, error) {
+func CreatePVZRepo(db Database) (PVZRepository, error) {
 	const op = "repository.pvz_repo.CreatePVZRepo"
 
-	switch cfg.Database.Protocol {
-
-	case "postgres":
-		repo, err := PostgresGetter(cfg)
-		if err != nil {
-			return nil, err
-		}
-		log.Info("pvz_repo is postgres")
-		return repo.(PVZRepository), nil
-
-	default:
-		return nil, fmt.Errorf("%s: unknown database protocol", op)
+	repo, ok := db.(PVZRepository)
+	if !ok {
+		return nil, fmt.Errorf("%s: %T does not implement PVZRepository", op, db)
 	}
+
+	return repo, nil
 }
@@ -2,10 +2,9 @@ package repository
 
 import (
 	"context"
-	"errors"
-	"log/slog"
-	"pvz-service/internal/config"
 	"pvz-service/internal/models"
+	"pvz-service/internal/repository/memory"
+	"pvz-service/internal/repository/postgres"
 	"testing"
 	"time"
 
@@ -48,24 +47,24 @@ func (m *MockPVZRepository) GetActiveReception(ctx context.Context, pvzID uuid.U
 	return args.Get(0).(*models.Reception), args.Error(1)
 }
 
-func (m *MockPVZRepository) UpdateReceptionStatus(ctx context.Context, receptionID uuid.UUID, status models.ReceptionStatus) error {
-	args := m.Called(ctx, receptionID, status)
-	return args.Error(0)
+func (m *MockPVZRepository) UpdateReceptionStatus(ctx context.Context, receptionID uuid.UUID, status models.ReceptionStatus, version int) (int, error) {
+	args := m.Called(ctx, receptionID, status, version)
+	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPVZRepository) InsertProduct(ctx context.Context, product *models.Product) error {
-	args := m.Called(ctx, product)
-	return args.Error(0)
+func (m *MockPVZRepository) InsertProduct(ctx context.Context, product *models.Product, receptionVersion int) (int, error) {
+	args := m.Called(ctx, product, receptionVersion)
+	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPVZRepository) GetLastProduct(ctx context.Context, receptionID uuid.UUID) (*models.Product, error) {
-	args := m.Called(ctx, receptionID)
+func (m *MockPVZRepository) GetLastProduct(ctx context.Context, receptionID uuid.UUID, since time.Time) (*models.Product, error) {
+	args := m.Called(ctx, receptionID, since)
 	return args.Get(0).(*models.Product), args.Error(1)
 }
 
-func (m *MockPVZRepository) DeleteProduct(ctx context.Context, productID uuid.UUID) error {
-	args := m.Called(ctx, productID)
-	return args.Error(0)
+func (m *MockPVZRepository) DeleteProduct(ctx context.Context, productID uuid.UUID, receptionVersion int) (int, string, error) {
+	args := m.Called(ctx, productID, receptionVersion)
+	return args.Int(0), args.String(1), args.Error(2)
 }
 
 func (m *MockPVZRepository) GetProductTypeID(ctx context.Context, productTypeName string) (int, error) {
@@ -83,102 +82,279 @@ func (m *MockPVZRepository) GetPVZsWithNoFilter(ctx context.Context) ([]models.P
 	return args.Get(0).([]models.PVZ), args.Error(1)
 }
 
-func (m *MockPVZRepository) GetReceptionsForPVZs(ctx context.Context, pvzIDs []uuid.UUID, from, to time.Time) ([]models.Reception, error) {
-	args := m.Called(ctx, pvzIDs, from, to)
+func (m *MockPVZRepository) GetPVZ(ctx context.Context, pvzID uuid.UUID) (*models.PVZ, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PVZ), args.Error(1)
+}
+
+func (m *MockPVZRepository) UpdatePVZ(ctx context.Context, pvz *models.PVZ, version int) (int, error) {
+	args := m.Called(ctx, pvz, version)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPVZRepository) DeletePVZ(ctx context.Context, pvzID uuid.UUID, version int) error {
+	args := m.Called(ctx, pvzID, version)
+	return args.Error(0)
+}
+
+func (m *MockPVZRepository) GetPVZSchedule(ctx context.Context, pvzID uuid.UUID) (*models.PVZSchedule, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PVZSchedule), args.Error(1)
+}
+
+func (m *MockPVZRepository) SavePVZSchedule(ctx context.Context, schedule *models.PVZSchedule) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (m *MockPVZRepository) AllowReceptionsUntil(ctx context.Context, pvzID uuid.UUID, until time.Time) error {
+	args := m.Called(ctx, pvzID, until)
+	return args.Error(0)
+}
+
+func (m *MockPVZRepository) DeletePVZSchedule(ctx context.Context, pvzID uuid.UUID) error {
+	args := m.Called(ctx, pvzID)
+	return args.Error(0)
+}
+
+func (m *MockPVZRepository) GetPVZOccupancy(ctx context.Context, pvzID uuid.UUID) (*models.PVZOccupancy, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PVZOccupancy), args.Error(1)
+}
+
+func (m *MockPVZRepository) ListPVZOccupancy(ctx context.Context) ([]models.PVZOccupancy, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PVZOccupancy), args.Error(1)
+}
+
+func (m *MockPVZRepository) ReplaceTypeCapacities(ctx context.Context, pvzID uuid.UUID, capacities []models.TypeCapacity) error {
+	args := m.Called(ctx, pvzID, capacities)
+	return args.Error(0)
+}
+
+func (m *MockPVZRepository) GetProduct(ctx context.Context, productID uuid.UUID) (*models.Product, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockPVZRepository) ChangeProductStatus(ctx context.Context, change *models.ProductStatusChange) error {
+	args := m.Called(ctx, change)
+	return args.Error(0)
+}
+
+func (m *MockPVZRepository) SetProductCondition(ctx context.Context, productID uuid.UUID, condition models.ProductCondition, note string) (*models.Product, uuid.UUID, error) {
+	args := m.Called(ctx, productID, condition, note)
+	if args.Get(0) == nil {
+		return nil, uuid.Nil, args.Error(2)
+	}
+	return args.Get(0).(*models.Product), args.Get(1).(uuid.UUID), args.Error(2)
+}
+
+func (m *MockPVZRepository) SetProductPhoto(ctx context.Context, productID uuid.UUID, photoKey string) (string, error) {
+	args := m.Called(ctx, productID, photoKey)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockPVZRepository) GetStaleReceptions(ctx context.Context, idleSince time.Time) ([]models.StaleReception, error) {
+	args := m.Called(ctx, idleSince)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.StaleReception), args.Error(1)
+}
+
+func (m *MockPVZRepository) ForceCloseReception(ctx context.Context, receptionID uuid.UUID, version int, entry *models.ReceptionAuditEntry) (int, error) {
+	args := m.Called(ctx, receptionID, version, entry)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPVZRepository) InsertReceptionAudit(ctx context.Context, entry *models.ReceptionAuditEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockPVZRepository) GetPVZReceptionAudit(ctx context.Context, pvzID uuid.UUID) ([]models.ReceptionAuditEntry, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ReceptionAuditEntry), args.Error(1)
+}
+
+func (m *MockPVZRepository) ArchiveReceptions(ctx context.Context, closedBefore, archivedAt time.Time, limit int) (int, int, []string, error) {
+	args := m.Called(ctx, closedBefore, archivedAt, limit)
+	photoKeys, _ := args.Get(2).([]string)
+	return args.Int(0), args.Int(1), photoKeys, args.Error(3)
+}
+
+func (m *MockPVZRepository) GetArchivedReceptions(ctx context.Context, pvzID uuid.UUID, from, to time.Time, limit, offset int) ([]models.Reception, error) {
+	args := m.Called(ctx, pvzID, from, to, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
 	return args.Get(0).([]models.Reception), args.Error(1)
 }
 
-func (m *MockPVZRepository) GetProductsForReceptions(ctx context.Context, receptionIDs []uuid.UUID) ([]models.Product, error) {
+func (m *MockPVZRepository) GetArchivedProducts(ctx context.Context, receptionIDs []uuid.UUID) ([]models.Product, error) {
 	args := m.Called(ctx, receptionIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
 	return args.Get(0).([]models.Product), args.Error(1)
 }
 
-// MockPostgresGetter mocks the postgres repository getter
-type MockPostgresPVZGetter struct {
-	mock.Mock
+func (m *MockPVZRepository) GetProductHistory(ctx context.Context, productID uuid.UUID) ([]models.ProductStatusChange, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ProductStatusChange), args.Error(1)
 }
 
-func (m *MockPostgresPVZGetter) GetRepository(cfg *config.Config) (PVZRepository, error) {
-	args := m.Called(cfg)
-	return args.Get(0).(PVZRepository), args.Error(1)
+func (m *MockPVZRepository) CreateTransfer(ctx context.Context, transfer *models.Transfer) error {
+	args := m.Called(ctx, transfer)
+	return args.Error(0)
 }
 
-func TestCreatePVZRepo(t *testing.T) {
-	// Save original PostgresGetter and restore after test
-	originalPostgresGetter := PostgresGetter
-	defer func() { PostgresGetter = originalPostgresGetter }()
+func (m *MockPVZRepository) GetTransfer(ctx context.Context, transferID uuid.UUID) (*models.Transfer, error) {
+	args := m.Called(ctx, transferID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transfer), args.Error(1)
+}
+
+func (m *MockPVZRepository) GetPVZTransfers(ctx context.Context, pvzID uuid.UUID, status models.TransferStatus) ([]models.Transfer, error) {
+	args := m.Called(ctx, pvzID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Transfer), args.Error(1)
+}
+
+func (m *MockPVZRepository) DispatchTransfer(ctx context.Context, transferID uuid.UUID, dispatchedAt time.Time) ([]models.ProductStatusChange, error) {
+	args := m.Called(ctx, transferID, dispatchedAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ProductStatusChange), args.Error(1)
+}
+
+func (m *MockPVZRepository) ReceiveTransfer(ctx context.Context, transferID, receptionID uuid.UUID, receptionVersion int, receivedAt time.Time) (int, []models.ProductStatusChange, error) {
+	args := m.Called(ctx, transferID, receptionID, receptionVersion, receivedAt)
+	if args.Get(1) == nil {
+		return args.Int(0), nil, args.Error(2)
+	}
+	return args.Int(0), args.Get(1).([]models.ProductStatusChange), args.Error(2)
+}
+
+func (m *MockPVZRepository) CancelTransfer(ctx context.Context, transferID uuid.UUID) error {
+	args := m.Called(ctx, transferID)
+	return args.Error(0)
+}
+
+func (m *MockPVZRepository) CreateManifest(ctx context.Context, manifest *models.Manifest) error {
+	args := m.Called(ctx, manifest)
+	return args.Error(0)
+}
+
+func (m *MockPVZRepository) GetManifest(ctx context.Context, manifestID uuid.UUID) (*models.Manifest, error) {
+	args := m.Called(ctx, manifestID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Manifest), args.Error(1)
+}
 
+func (m *MockPVZRepository) GetPVZManifests(ctx context.Context, pvzID uuid.UUID, status models.ManifestStatus) ([]models.Manifest, error) {
+	args := m.Called(ctx, pvzID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Manifest), args.Error(1)
+}
+
+func (m *MockPVZRepository) GetNearbyPVZs(ctx context.Context, point models.GeoPoint, radius float64, limit int) ([]models.NearbyPVZ, error) {
+	args := m.Called(ctx, point, radius, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.NearbyPVZ), args.Error(1)
+}
+
+func (m *MockPVZRepository) GetReceptionsForPVZs(ctx context.Context, pvzIDs []uuid.UUID, from, to time.Time) ([]models.Reception, error) {
+	args := m.Called(ctx, pvzIDs, from, to)
+	return args.Get(0).([]models.Reception), args.Error(1)
+}
+
+func (m *MockPVZRepository) GetProductsForReceptions(ctx context.Context, receptionIDs []uuid.UUID, since time.Time) ([]models.Product, error) {
+	args := m.Called(ctx, receptionIDs, since)
+	return args.Get(0).([]models.Product), args.Error(1)
+}
+
+func (m *MockPVZRepository) IteratePVZs(ctx context.Context, filter models.PVZFilter, fn func(models.PVZ) error) error {
+	args := m.Called(ctx, filter)
+	if list, ok := args.Get(0).([]models.PVZ); ok {
+		for _, pvz := range list {
+			if err := fn(pvz); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func TestCreatePVZRepo(t *testing.T) {
 	tests := []struct {
-		name          string
-		cfg           *config.Config
-		mockSetup     func(*MockPostgresPVZGetter)
-		expectError   bool
-		expectNilRepo bool
+		name        string
+		db          Database
+		expectError bool
 	}{
 		{
 			name: "Success with postgres",
-			cfg: &config.Config{
-				Database: config.Database{
-					Protocol: "postgres",
-				},
-			},
-			mockSetup: func(m *MockPostgresPVZGetter) {
-				mockRepo := new(MockPVZRepository)
-				m.On("GetRepository", mock.Anything).Return(mockRepo, nil)
-			},
-			expectError:   false,
-			expectNilRepo: false,
+			db:   new(postgres.Postgres),
+		},
+		{
+			name: "Success with memory",
+			db:   memory.New(),
 		},
 		{
-			name: "Postgres error",
-			cfg: &config.Config{
-				Database: config.Database{
-					Protocol: "postgres",
-				},
-			},
-			mockSetup: func(m *MockPostgresPVZGetter) {
-				m.On("GetRepository", mock.Anything).Return(&MockPVZRepository{}, errors.New("connection failed"))
-			},
-			expectError:   true,
-			expectNilRepo: true,
+			name: "Success with sqlite",
+			db:   openSQLite(t),
 		},
 		{
-			name: "Unknown protocol",
-			cfg: &config.Config{
-				Database: config.Database{
-					Protocol: "unknown",
-				},
-			},
-			mockSetup:     func(m *MockPostgresPVZGetter) {},
-			expectError:   true,
-			expectNilRepo: true,
+			name:        "Not a PVZ repository",
+			db:          stubDatabase{},
+			expectError: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockGetter := new(MockPostgresPVZGetter)
-			tt.mockSetup(mockGetter)
-			PostgresGetter = func(cfg *config.Config) (interface{}, error) {
-				return mockGetter.GetRepository(cfg)
-			}
-
-			log := slog.Default()
-			repo, err := CreatePVZRepo(tt.cfg, log)
+			repo, err := CreatePVZRepo(tt.db)
 
 			if tt.expectError {
 				assert.Error(t, err)
-			} else {
-				assert.NoError(t, err)
-			}
-
-			if tt.expectNilRepo {
 				assert.Nil(t, repo)
 			} else {
+				assert.NoError(t, err)
 				assert.NotNil(t, repo)
 			}
-
-			mockGetter.AssertExpectations(t)
 		})
 	}
 }
@@ -228,20 +404,26 @@ func TestPVZRepositoryInterface(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, testReception, reception)
 
-	mockRepo.On("UpdateReceptionStatus", ctx, testUUID, models.ReceptionStatusClose).Return(nil).Once()
-	assert.NoError(t, mockRepo.UpdateReceptionStatus(ctx, testUUID, models.ReceptionStatusClose))
+	mockRepo.On("UpdateReceptionStatus", ctx, testUUID, models.ReceptionStatusClose, 1).Return(2, nil).Once()
+	version, err := mockRepo.UpdateReceptionStatus(ctx, testUUID, models.ReceptionStatusClose, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, version)
 
 	// Test Product operations
-	mockRepo.On("InsertProduct", ctx, testProduct).Return(nil).Once()
-	assert.NoError(t, mockRepo.InsertProduct(ctx, testProduct))
+	mockRepo.On("InsertProduct", ctx, testProduct, 2).Return(3, nil).Once()
+	version, err = mockRepo.InsertProduct(ctx, testProduct, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, version)
 
-	mockRepo.On("GetLastProduct", ctx, testUUID).Return(testProduct, nil).Once()
-	product, err := mockRepo.GetLastProduct(ctx, testUUID)
+	mockRepo.On("GetLastProduct", ctx, testUUID, testReception.DateTime).Return(testProduct, nil).Once()
+	product, err := mockRepo.GetLastProduct(ctx, testUUID, testReception.DateTime)
 	assert.NoError(t, err)
 	assert.Equal(t, testProduct, product)
 
-	mockRepo.On("DeleteProduct", ctx, testUUID).Return(nil).Once()
-	assert.NoError(t, mockRepo.DeleteProduct(ctx, testUUID))
+	mockRepo.On("DeleteProduct", ctx, testUUID, 3).Return(4, "", nil).Once()
+	version, _, err = mockRepo.DeleteProduct(ctx, testUUID, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, version)
 
 	// Test Product type operations
 	mockRepo.On("GetProductTypeID", ctx, "Electronics").Return(2, nil).Once()
@@ -260,8 +442,8 @@ func TestPVZRepositoryInterface(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, testReceptions, receptions)
 
-	mockRepo.On("GetProductsForReceptions", ctx, []uuid.UUID{testUUID}).Return(testProducts, nil).Once()
-	products, err := mockRepo.GetProductsForReceptions(ctx, []uuid.UUID{testUUID})
+	mockRepo.On("GetProductsForReceptions", ctx, []uuid.UUID{testUUID}, now).Return(testProducts, nil).Once()
+	products, err := mockRepo.GetProductsForReceptions(ctx, []uuid.UUID{testUUID}, now)
 	assert.NoError(t, err)
 	assert.Equal(t, testProducts, products)
 
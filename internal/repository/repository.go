@@ -1,10 +1,72 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
 	"pvz-service/internal/config"
+	"pvz-service/internal/repository/memory"
 	"pvz-service/internal/repository/postgres"
+	"pvz-service/internal/repository/sqlite"
+	"time"
 )
 
-var PostgresGetter = func(cfg *config.Config) (interface{}, error) {
-	return postgres.GetRepository(cfg)
+// Database is an open connection to one of the storage backends. It
+// implements every repository interface of its backend, so all repositories
+// of the process share it, and is closed once by its owner.
+type Database interface {
+	CloseConnection()
+}
+
+// PoolStats is implemented by databases that keep a connection pool
+type PoolStats interface {
+	Stats() sql.DBStats
+}
+
+// Partitioner is implemented by databases that split receptions and
+// products into monthly partitions
+type Partitioner interface {
+	// EnsurePartitions creates the missing partitions from the month of from
+	// to months ahead, and for the months found in the default partitions,
+	// and returns the names of the partitions created
+	EnsurePartitions(ctx context.Context, from time.Time, months int) ([]string, error)
+}
+
+var PostgresGetter = func(cfg *config.Config, log *slog.Logger) (Database, error) {
+	return postgres.New(cfg, log)
+}
+
+var MemoryGetter = func(cfg *config.Config, log *slog.Logger) (Database, error) {
+	return memory.New(), nil
+}
+
+var SQLiteGetter = func(cfg *config.Config, log *slog.Logger) (Database, error) {
+	return sqlite.Open(cfg.Database.Path)
+}
+
+// Open connects to the database selected by cfg.Database.Protocol
+func Open(cfg *config.Config, log *slog.Logger) (Database, error) {
+	const op = "repository.Open"
+
+	var getter func(*config.Config, *slog.Logger) (Database, error)
+
+	switch cfg.Database.Protocol {
+	case "postgres":
+		getter = PostgresGetter
+	case "memory":
+		getter = MemoryGetter
+	case "sqlite":
+		getter = SQLiteGetter
+	default:
+		return nil, fmt.Errorf("%s: unknown database protocol (%s)", op, cfg.Database.Protocol)
+	}
+
+	db, err := getter(cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("database is " + cfg.Database.Protocol)
+	return db, nil
 }
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"pvz-service/internal/config"
+	"pvz-service/internal/repository/sqlite"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubDatabase is a database that implements none of the repositories
+type stubDatabase struct{}
+
+func (stubDatabase) CloseConnection() {}
+
+func openSQLite(t *testing.T) *sqlite.SQLite {
+	t.Helper()
+
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "pvz.db"))
+	require.NoError(t, err)
+	t.Cleanup(db.CloseConnection)
+	return db
+}
+
+func TestOpen(t *testing.T) {
+	// Save original PostgresGetter and restore after test
+	originalPostgresGetter := PostgresGetter
+	defer func() { PostgresGetter = originalPostgresGetter }()
+
+	dbPath := filepath.Join(t.TempDir(), "pvz.db")
+
+	tests := []struct {
+		name        string
+		protocol    string
+		getterDB    Database
+		getterErr   error
+		expectError bool
+	}{
+		{
+			name:     "Success with postgres",
+			protocol: "postgres",
+			getterDB: stubDatabase{},
+		},
+		{
+			name:        "Postgres error",
+			protocol:    "postgres",
+			getterErr:   errors.New("connection failed"),
+			expectError: true,
+		},
+		{
+			name:     "Success with memory",
+			protocol: "memory",
+		},
+		{
+			name:     "Success with sqlite",
+			protocol: "sqlite",
+		},
+		{
+			name:        "Unknown protocol",
+			protocol:    "unknown",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			PostgresGetter = func(cfg *config.Config, log *slog.Logger) (Database, error) {
+				return tt.getterDB, tt.getterErr
+			}
+
+			cfg := &config.Config{Database: config.Database{Protocol: tt.protocol, Path: dbPath}}
+			db, err := Open(cfg, slog.Default())
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, db)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, db)
+				db.CloseConnection()
+			}
+		})
+	}
+}
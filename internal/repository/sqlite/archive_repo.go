@@ -0,0 +1,205 @@
+package sqlite
+
+import (
+	"context"
+	"pvz-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *SQLite) ArchiveReceptions(ctx context.Context, closedBefore, archivedAt time.Time, limit int) (int, int, []string, error) {
+	var receptions, products int
+	var photoKeys []string
+	err := s.withTx(ctx, func(tx tx) error {
+		rows, err := tx.QueryContext(ctx,
+			`SELECT r.id
+			 FROM receptions r
+			 WHERE r.status = $1 AND r.date_time < $2
+			   AND NOT EXISTS (
+				   SELECT 1 FROM products p
+				   WHERE p.reception_id = r.id AND p.status NOT IN ($3, $4)
+			   )
+			   AND NOT EXISTS (SELECT 1 FROM transfers t WHERE t.reception_id = r.id)
+			   AND NOT EXISTS (
+				   SELECT 1 FROM product_status_history h
+				   JOIN products p ON p.id = h.product_id
+				   WHERE h.reception_id = r.id AND p.reception_id <> r.id
+			   )
+			   AND NOT EXISTS (
+				   SELECT 1 FROM transfer_items ti
+				   JOIN products p ON p.id = ti.product_id
+				   WHERE p.reception_id = r.id
+			   )
+			 ORDER BY r.date_time
+			 LIMIT $5`,
+			models.ReceptionStatusClose, closedBefore,
+			models.ProductStatusIssued, models.ProductStatusWrittenOff, limit)
+		if err != nil {
+			return err
+		}
+
+		found := []uuid.UUID{}
+		for rows.Next() {
+			var id uuid.UUID
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			found = append(found, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(found) == 0 {
+			return nil
+		}
+
+		ids, err := jsonArray(found)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO receptions_archive (id, date_time, pvz_id, status, version, archived_at)
+			 SELECT id, date_time, pvz_id, status, version, $2
+			 FROM receptions
+			 WHERE id IN (SELECT value FROM json_each($1))`,
+			ids, archivedAt); err != nil {
+			return err
+		}
+
+		// Photos are not archived, the caller removes them once the batch is
+		// committed
+		keys, err := tx.QueryContext(ctx,
+			`SELECT photo_key FROM products WHERE reception_id IN (SELECT value FROM json_each($1)) AND photo_key IS NOT NULL`,
+			ids)
+		if err != nil {
+			return err
+		}
+		for keys.Next() {
+			var key string
+			if err := keys.Scan(&key); err != nil {
+				keys.Close()
+				return err
+			}
+			photoKeys = append(photoKeys, key)
+		}
+		keys.Close()
+		if err := keys.Err(); err != nil {
+			return err
+		}
+
+		res, err := tx.ExecContext(ctx,
+			`INSERT INTO products_archive (id, date_time, type_id, reception_id, status, barcode, condition, condition_note)
+			 SELECT id, date_time, type_id, reception_id, status, barcode, condition, condition_note
+			 FROM products
+			 WHERE reception_id IN (SELECT value FROM json_each($1))`,
+			ids)
+		if err != nil {
+			return err
+		}
+		moved, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO product_status_history_archive (id, product_id, pvz_id, from_status, to_status, comment, changed_at, reception_id)
+			 SELECT h.id, h.product_id, h.pvz_id, h.from_status, h.to_status, h.comment, h.changed_at, h.reception_id
+			 FROM product_status_history h
+			 JOIN products p ON p.id = h.product_id
+			 WHERE p.reception_id IN (SELECT value FROM json_each($1))`,
+			ids); err != nil {
+			return err
+		}
+
+		// Manifests and audit entries stay, keeping the ID of the archived
+		// reception
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE manifests SET archived_reception_id = reception_id, reception_id = NULL
+			 WHERE reception_id IN (SELECT value FROM json_each($1))`,
+			ids); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE reception_audit SET archived_reception_id = reception_id, reception_id = NULL
+			 WHERE reception_id IN (SELECT value FROM json_each($1))`,
+			ids); err != nil {
+			return err
+		}
+
+		// Products and their history go with the receptions
+		if _, err := tx.ExecContext(ctx,
+			`DELETE FROM receptions WHERE id IN (SELECT value FROM json_each($1))`,
+			ids); err != nil {
+			return err
+		}
+
+		receptions, products = len(found), int(moved)
+		return nil
+	})
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	return receptions, products, photoKeys, nil
+}
+
+func (s *SQLite) GetArchivedReceptions(ctx context.Context, pvzID uuid.UUID, from, to time.Time, limit, offset int) ([]models.Reception, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, date_time, pvz_id, status
+		 FROM receptions_archive
+		 WHERE pvz_id = $1 AND date_time BETWEEN $2 AND $3
+		 ORDER BY date_time DESC
+		 LIMIT $4 OFFSET $5`,
+		pvzID, from, to, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	receptions := []models.Reception{}
+	for rows.Next() {
+		var rec models.Reception
+		if err := rows.Scan(&rec.ID, &rec.DateTime, &rec.PVZID, &rec.Status); err != nil {
+			return nil, err
+		}
+		receptions = append(receptions, rec)
+	}
+	return receptions, rows.Err()
+}
+
+func (s *SQLite) GetArchivedProducts(ctx context.Context, receptionIDs []uuid.UUID) ([]models.Product, error) {
+	if len(receptionIDs) == 0 {
+		return []models.Product{}, nil
+	}
+
+	ids, err := jsonArray(receptionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT p.id, p.date_time, p.type_id, pt.name, p.reception_id, p.status,
+		        COALESCE(p.barcode, ''), p.condition, p.condition_note, COALESCE(p.photo_key, '')
+		 FROM products_archive p
+		 JOIN product_types pt ON p.type_id = pt.id
+		 WHERE p.reception_id IN (SELECT value FROM json_each($1))
+		 ORDER BY p.date_time DESC`,
+		ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := []models.Product{}
+	for rows.Next() {
+		var prod models.Product
+		if err := scanProduct(rows, &prod); err != nil {
+			return nil, err
+		}
+		products = append(products, prod)
+	}
+	return products, rows.Err()
+}
@@ -0,0 +1,96 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// database and tx convert time arguments to UTC before passing them on.
+// SQLite keeps timestamps as text, which only orders and compares correctly
+// when every value is written in the same zone.
+type database struct {
+	*sql.DB
+}
+
+type tx struct {
+	*sql.Tx
+}
+
+func (d database) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return d.DB.ExecContext(ctx, query, utc(args)...)
+}
+
+func (d database) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return d.DB.QueryContext(ctx, query, utc(args)...)
+}
+
+func (d database) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return d.DB.QueryRowContext(ctx, query, utc(args)...)
+}
+
+func (t tx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return t.Tx.ExecContext(ctx, query, utc(args)...)
+}
+
+func (t tx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return t.Tx.QueryContext(ctx, query, utc(args)...)
+}
+
+func (t tx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return t.Tx.QueryRowContext(ctx, query, utc(args)...)
+}
+
+// queryer is implemented by both database and tx
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func utc(args []interface{}) []interface{} {
+	converted := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case time.Time:
+			converted[i] = v.UTC()
+		case *time.Time:
+			if v != nil {
+				converted[i] = v.UTC()
+			} else {
+				converted[i] = nil
+			}
+		default:
+			converted[i] = arg
+		}
+	}
+	return converted
+}
+
+// timeLayout is the text format timestamps are stored in
+const timeLayout = "2006-01-02 15:04:05.999999999-07:00"
+
+// parseTime reads a timestamp computed by an expression, which the driver
+// returns as text rather than time.Time
+func parseTime(value string) (time.Time, error) {
+	t, err := time.Parse(timeLayout, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.UTC(), nil
+}
+
+// withTx runs fn inside a transaction and commits it if fn succeeds
+func (s *SQLite) withTx(ctx context.Context, fn func(tx tx) error) error {
+	sqlTx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx{sqlTx}); err != nil {
+		sqlTx.Rollback()
+		return err
+	}
+
+	return sqlTx.Commit()
+}
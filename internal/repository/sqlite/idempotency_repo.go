@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"pvz-service/internal/models"
+	"time"
+)
+
+func (s *SQLite) ReserveIdempotencyKey(ctx context.Context, key, fingerprint string, ttl, lease time.Duration) (*models.IdempotencyRecord, error) {
+	// An expired record is taken over as if the key were new, and so is one
+	// whose request was lost before completing, if the retry is the same
+	var reserved string
+	now := time.Now()
+	err := s.db.QueryRowContext(ctx,
+		`INSERT INTO idempotency_keys (key, fingerprint, created_at, expires_at, locked_until)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (key) DO UPDATE
+		 SET fingerprint = excluded.fingerprint, status_code = NULL, content_type = NULL, response_body = NULL,
+		     created_at = excluded.created_at, expires_at = excluded.expires_at, locked_until = excluded.locked_until
+		 WHERE idempotency_keys.expires_at <= $3
+		 OR (idempotency_keys.status_code IS NULL
+		     AND (idempotency_keys.locked_until IS NULL OR idempotency_keys.locked_until <= $3)
+		     AND idempotency_keys.fingerprint = excluded.fingerprint)
+		 RETURNING key`,
+		key, fingerprint, now, now.Add(ttl), now.Add(lease)).Scan(&reserved)
+	if err == nil {
+		return nil, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	var (
+		record      models.IdempotencyRecord
+		statusCode  sql.NullInt64
+		contentType sql.NullString
+		lockedUntil sql.NullTime
+	)
+	err = s.db.QueryRowContext(ctx,
+		`SELECT key, fingerprint, status_code, content_type, response_body, created_at, expires_at, locked_until
+		 FROM idempotency_keys
+		 WHERE key = $1`,
+		key).Scan(&record.Key, &record.Fingerprint, &statusCode, &contentType, &record.Body, &record.CreatedAt, &record.ExpiresAt, &lockedUntil)
+	if err != nil {
+		return nil, err
+	}
+
+	record.StatusCode = int(statusCode.Int64)
+	record.ContentType = contentType.String
+	record.LockedUntil = lockedUntil.Time
+
+	return &record, nil
+}
+
+func (s *SQLite) SaveIdempotencyResponse(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE idempotency_keys
+		 SET status_code = $1, content_type = $2, response_body = $3
+		 WHERE key = $4`,
+		statusCode, contentType, body, key)
+	return err
+}
+
+func (s *SQLite) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE key = $1", key)
+	return err
+}
+
+func (s *SQLite) DeleteExpiredIdempotencyKeys(ctx context.Context) (int, error) {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE expires_at <= $1", time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := res.RowsAffected()
+	return int(affected), err
+}
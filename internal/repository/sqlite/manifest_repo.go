@@ -0,0 +1,243 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func scanManifest(row rowScanner, manifest *models.Manifest) error {
+	var report sql.NullString
+	err := row.Scan(&manifest.ID, &manifest.PVZID, &manifest.Supplier, &manifest.Status,
+		&manifest.ReceptionID, &report, &manifest.CreatedAt, &manifest.ReconciledAt)
+	if err != nil {
+		return err
+	}
+
+	if report.Valid {
+		manifest.Report = &models.DiscrepancyReport{}
+		if err := json.Unmarshal([]byte(report.String), manifest.Report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SQLite) CreateManifest(ctx context.Context, manifest *models.Manifest) error {
+	return s.withTx(ctx, func(tx tx) error {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO manifests (id, pvz_id, supplier, status, created_at)
+			 VALUES ($1, $2, $3, $4, $5)`,
+			manifest.ID, manifest.PVZID, manifest.Supplier, manifest.Status, manifest.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range manifest.Items {
+			_, err = tx.ExecContext(ctx,
+				"INSERT INTO manifest_items (manifest_id, barcode, type_id) VALUES ($1, $2, $3)",
+				manifest.ID, item.Barcode, item.TypeID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *SQLite) GetManifest(ctx context.Context, manifestID uuid.UUID) (*models.Manifest, error) {
+	var manifest models.Manifest
+	row := s.db.QueryRowContext(ctx,
+		`SELECT id, pvz_id, supplier, status, COALESCE(reception_id, archived_reception_id), report, created_at, reconciled_at
+		 FROM manifests
+		 WHERE id = $1`,
+		manifestID)
+	if err := scanManifest(row, &manifest); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, e.ErrNotFound()
+		}
+		return nil, err
+	}
+
+	items, err := getManifestItems(ctx, s.db, []uuid.UUID{manifest.ID})
+	if err != nil {
+		return nil, err
+	}
+	manifest.Items = items[manifest.ID]
+
+	return &manifest, nil
+}
+
+func (s *SQLite) GetPVZManifests(ctx context.Context, pvzID uuid.UUID, status models.ManifestStatus) ([]models.Manifest, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, pvz_id, supplier, status, COALESCE(reception_id, archived_reception_id), report, created_at, reconciled_at
+		 FROM manifests
+		 WHERE pvz_id = $1 AND ($2 = '' OR status = $2)
+		 ORDER BY created_at DESC`,
+		pvzID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := []models.Manifest{}
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var manifest models.Manifest
+		if err := scanManifest(rows, &manifest); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		manifests = append(manifests, manifest)
+		ids = append(ids, manifest.ID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	items, err := getManifestItems(ctx, s.db, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range manifests {
+		manifests[i].Items = items[manifests[i].ID]
+	}
+
+	return manifests, nil
+}
+
+// linkManifest attaches a pending manifest of the PVZ to a new reception
+func linkManifest(ctx context.Context, tx tx, manifestID, receptionID, pvzID uuid.UUID) error {
+	res, err := tx.ExecContext(ctx,
+		`UPDATE manifests SET status = $1, reception_id = $2
+		 WHERE id = $3 AND pvz_id = $4 AND status = $5`,
+		models.ManifestStatusReceiving, receptionID, manifestID, pvzID, models.ManifestStatusPending)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return e.ErrManifestNotAvailable()
+	}
+
+	return nil
+}
+
+// reconcileManifest compares the products of a closed reception with the
+// manifest it was started with and saves the discrepancy report. It returns
+// nil if the reception has no manifest.
+func reconcileManifest(ctx context.Context, tx tx, receptionID uuid.UUID, reconciledAt time.Time) (*models.DiscrepancyReport, error) {
+	var manifestID uuid.UUID
+	err := tx.QueryRowContext(ctx,
+		"SELECT id FROM manifests WHERE reception_id = $1 AND status = $2",
+		receptionID, models.ManifestStatusReceiving).Scan(&manifestID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := getManifestItems(ctx, tx, []uuid.UUID{manifestID})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT barcode, COUNT(*)
+		 FROM products
+		 WHERE reception_id = $1
+		 GROUP BY barcode`,
+		receptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		scanned   = map[string]int{}
+		unlabeled int
+	)
+	for rows.Next() {
+		var (
+			barcode sql.NullString
+			count   int
+		)
+		if err := rows.Scan(&barcode, &count); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if barcode.Valid {
+			scanned[barcode.String] = count
+		} else {
+			unlabeled = count
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	report := models.Reconcile(items[manifestID], scanned, unlabeled)
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE manifests SET status = $1, report = $2, reconciled_at = $3 WHERE id = $4",
+		models.ManifestStatusReconciled, string(data), reconciledAt, manifestID)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// getManifestItems loads the items of the manifests keyed by manifest ID
+func getManifestItems(ctx context.Context, q queryer, manifestIDs []uuid.UUID) (map[uuid.UUID][]models.ManifestItem, error) {
+	items := make(map[uuid.UUID][]models.ManifestItem, len(manifestIDs))
+	for _, id := range manifestIDs {
+		items[id] = []models.ManifestItem{}
+	}
+	if len(manifestIDs) == 0 {
+		return items, nil
+	}
+
+	ids, err := jsonArray(manifestIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.QueryContext(ctx,
+		`SELECT mi.manifest_id, mi.barcode, mi.type_id, pt.name
+		 FROM manifest_items mi
+		 JOIN product_types pt ON pt.id = mi.type_id
+		 WHERE mi.manifest_id IN (SELECT value FROM json_each($1))
+		 ORDER BY mi.barcode`,
+		ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			manifestID uuid.UUID
+			item       models.ManifestItem
+		)
+		if err := rows.Scan(&manifestID, &item.Barcode, &item.TypeID, &item.TypeName); err != nil {
+			return nil, err
+		}
+		items[manifestID] = append(items[manifestID], item)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS receptions (
+    id TEXT PRIMARY KEY,
+    date_time TIMESTAMP NOT NULL,
+    pvz_id TEXT NOT NULL REFERENCES pvz(id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'in_progress' CHECK (status IN ('in_progress', 'close'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_receptions_in_progress_date_time_desc
+    ON receptions(date_time DESC)
+    WHERE status = 'in_progress';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS receptions;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS products (
+    id TEXT PRIMARY KEY,
+    date_time TIMESTAMP NOT NULL,
+    type_id INTEGER NOT NULL REFERENCES product_types(id) ON DELETE CASCADE,
+    reception_id TEXT NOT NULL REFERENCES receptions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_products_reception_id_date_time_desc
+    ON products(reception_id, date_time DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS products;
+-- +goose StatementEnd
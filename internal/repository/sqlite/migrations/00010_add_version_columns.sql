@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE pvz ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE receptions ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE receptions DROP COLUMN version;
+ALTER TABLE pvz DROP COLUMN version;
+-- +goose StatementEnd
@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE pvz ADD COLUMN status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'suspended', 'closed'));
+ALTER TABLE pvz ADD COLUMN deleted_at TIMESTAMP;
+
+-- PVZs are soft-deleted, so their receptions must never go with them. SQLite
+-- cannot change a foreign key in place, the table is rebuilt instead.
+CREATE TABLE receptions_new (
+    id TEXT PRIMARY KEY,
+    date_time TIMESTAMP NOT NULL,
+    pvz_id TEXT NOT NULL REFERENCES pvz(id) ON DELETE RESTRICT,
+    status TEXT NOT NULL DEFAULT 'in_progress' CHECK (status IN ('in_progress', 'close')),
+    version INTEGER NOT NULL DEFAULT 1
+);
+INSERT INTO receptions_new (id, date_time, pvz_id, status, version)
+SELECT id, date_time, pvz_id, status, version FROM receptions;
+DROP TABLE receptions;
+ALTER TABLE receptions_new RENAME TO receptions;
+
+CREATE INDEX IF NOT EXISTS idx_receptions_in_progress_date_time_desc
+    ON receptions(date_time DESC)
+    WHERE status = 'in_progress';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE TABLE receptions_new (
+    id TEXT PRIMARY KEY,
+    date_time TIMESTAMP NOT NULL,
+    pvz_id TEXT NOT NULL REFERENCES pvz(id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'in_progress' CHECK (status IN ('in_progress', 'close')),
+    version INTEGER NOT NULL DEFAULT 1
+);
+INSERT INTO receptions_new (id, date_time, pvz_id, status, version)
+SELECT id, date_time, pvz_id, status, version FROM receptions;
+DROP TABLE receptions;
+ALTER TABLE receptions_new RENAME TO receptions;
+
+CREATE INDEX IF NOT EXISTS idx_receptions_in_progress_date_time_desc
+    ON receptions(date_time DESC)
+    WHERE status = 'in_progress';
+
+ALTER TABLE pvz DROP COLUMN deleted_at;
+ALTER TABLE pvz DROP COLUMN status;
+-- +goose StatementEnd
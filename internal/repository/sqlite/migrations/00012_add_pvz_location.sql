@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE pvz ADD COLUMN address TEXT NOT NULL DEFAULT '';
+ALTER TABLE pvz ADD COLUMN latitude REAL;
+ALTER TABLE pvz ADD COLUMN longitude REAL CHECK (
+    (latitude IS NULL AND longitude IS NULL)
+    OR (latitude BETWEEN -90 AND 90 AND longitude BETWEEN -180 AND 180)
+);
+ALTER TABLE pvz ADD COLUMN opening_hours TEXT NOT NULL DEFAULT '';
+ALTER TABLE pvz ADD COLUMN capacity INTEGER NOT NULL DEFAULT 0 CHECK (capacity >= 0);
+
+-- Bounding box prefilter for the nearby search
+CREATE INDEX IF NOT EXISTS idx_pvz_latitude_longitude
+    ON pvz(latitude, longitude)
+    WHERE deleted_at IS NULL AND latitude IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_pvz_latitude_longitude;
+
+ALTER TABLE pvz DROP COLUMN capacity;
+ALTER TABLE pvz DROP COLUMN opening_hours;
+ALTER TABLE pvz DROP COLUMN longitude;
+ALTER TABLE pvz DROP COLUMN latitude;
+ALTER TABLE pvz DROP COLUMN address;
+-- +goose StatementEnd
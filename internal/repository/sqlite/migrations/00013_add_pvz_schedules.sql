@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS pvz_schedules (
+    pvz_id TEXT PRIMARY KEY REFERENCES pvz(id) ON DELETE CASCADE,
+    timezone TEXT NOT NULL,
+    weekly TEXT NOT NULL DEFAULT '[]',
+    exceptions TEXT NOT NULL DEFAULT '[]',
+    updated_at TIMESTAMP NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS pvz_schedules;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS pvz_type_capacities (
+    pvz_id TEXT NOT NULL REFERENCES pvz(id) ON DELETE CASCADE,
+    type_id INTEGER NOT NULL REFERENCES product_types(id) ON DELETE CASCADE,
+    capacity INTEGER NOT NULL CHECK (capacity > 0),
+    PRIMARY KEY (pvz_id, type_id)
+);
+
+-- Number of products of each type currently held by a PVZ. Kept up to date
+-- in the same transaction that adds or removes a product.
+CREATE TABLE IF NOT EXISTS pvz_occupancy (
+    pvz_id TEXT NOT NULL REFERENCES pvz(id) ON DELETE CASCADE,
+    type_id INTEGER NOT NULL REFERENCES product_types(id) ON DELETE CASCADE,
+    count INTEGER NOT NULL DEFAULT 0 CHECK (count >= 0),
+    PRIMARY KEY (pvz_id, type_id)
+);
+
+INSERT INTO pvz_occupancy (pvz_id, type_id, count)
+SELECT r.pvz_id, p.type_id, COUNT(*)
+FROM products p
+JOIN receptions r ON p.reception_id = r.id
+GROUP BY r.pvz_id, p.type_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS pvz_occupancy;
+DROP TABLE IF EXISTS pvz_type_capacities;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE products ADD COLUMN status TEXT NOT NULL DEFAULT 'accepted'
+    CHECK (status IN ('accepted', 'stored', 'issued', 'returned', 'written_off'));
+
+-- Products of already closed receptions are on the shelves
+UPDATE products SET status = 'stored'
+WHERE reception_id IN (SELECT id FROM receptions WHERE status = 'close');
+
+CREATE TABLE IF NOT EXISTS product_status_history (
+    id INTEGER PRIMARY KEY,
+    product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    pvz_id TEXT NOT NULL REFERENCES pvz(id),
+    from_status TEXT,
+    to_status TEXT NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    changed_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_status_history_product_id
+    ON product_status_history(product_id, changed_at);
+
+INSERT INTO product_status_history (product_id, pvz_id, from_status, to_status, changed_at)
+SELECT p.id, r.pvz_id, NULL, p.status, p.date_time
+FROM products p
+JOIN receptions r ON r.id = p.reception_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS product_status_history;
+
+ALTER TABLE products DROP COLUMN status;
+-- +goose StatementEnd
@@ -0,0 +1,105 @@
+-- +goose Up
+-- +goose StatementBegin
+-- SQLite cannot change a check constraint or make a column NOT NULL in
+-- place, so products and their history are rebuilt
+CREATE TABLE products_new (
+    id TEXT PRIMARY KEY,
+    date_time TIMESTAMP NOT NULL,
+    type_id INTEGER NOT NULL REFERENCES product_types(id) ON DELETE CASCADE,
+    reception_id TEXT NOT NULL REFERENCES receptions(id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'accepted'
+        CHECK (status IN ('accepted', 'stored', 'issued', 'returned', 'written_off', 'in_transit'))
+);
+INSERT INTO products_new (id, date_time, type_id, reception_id, status)
+SELECT id, date_time, type_id, reception_id, status FROM products;
+DROP TABLE products;
+ALTER TABLE products_new RENAME TO products;
+
+CREATE INDEX IF NOT EXISTS idx_products_reception_id_date_time_desc
+    ON products(reception_id, date_time DESC);
+
+-- A transferred product moves to a reception of the receiving PVZ, so the
+-- history keeps the reception each change was made in
+CREATE TABLE product_status_history_new (
+    id INTEGER PRIMARY KEY,
+    product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    pvz_id TEXT NOT NULL REFERENCES pvz(id),
+    from_status TEXT,
+    to_status TEXT NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    changed_at TIMESTAMP NOT NULL,
+    reception_id TEXT NOT NULL REFERENCES receptions(id)
+);
+INSERT INTO product_status_history_new (id, product_id, pvz_id, from_status, to_status, comment, changed_at, reception_id)
+SELECT h.id, h.product_id, h.pvz_id, h.from_status, h.to_status, h.comment, h.changed_at, p.reception_id
+FROM product_status_history h
+JOIN products p ON p.id = h.product_id;
+DROP TABLE product_status_history;
+ALTER TABLE product_status_history_new RENAME TO product_status_history;
+
+CREATE INDEX IF NOT EXISTS idx_product_status_history_product_id
+    ON product_status_history(product_id, changed_at);
+
+CREATE TABLE IF NOT EXISTS transfers (
+    id TEXT PRIMARY KEY,
+    source_pvz_id TEXT NOT NULL REFERENCES pvz(id),
+    destination_pvz_id TEXT NOT NULL REFERENCES pvz(id),
+    status TEXT NOT NULL CHECK (status IN ('outbound', 'in_transit', 'received', 'cancelled')),
+    reception_id TEXT REFERENCES receptions(id),
+    created_at TIMESTAMP NOT NULL,
+    dispatched_at TIMESTAMP,
+    received_at TIMESTAMP,
+    CHECK (source_pvz_id <> destination_pvz_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transfers_source_pvz_id ON transfers(source_pvz_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_transfers_destination_pvz_id ON transfers(destination_pvz_id, created_at);
+
+CREATE TABLE IF NOT EXISTS transfer_items (
+    transfer_id TEXT NOT NULL REFERENCES transfers(id) ON DELETE CASCADE,
+    product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    PRIMARY KEY (transfer_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transfer_items_product_id ON transfer_items(product_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS transfer_items;
+DROP TABLE IF EXISTS transfers;
+
+CREATE TABLE product_status_history_new (
+    id INTEGER PRIMARY KEY,
+    product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    pvz_id TEXT NOT NULL REFERENCES pvz(id),
+    from_status TEXT,
+    to_status TEXT NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    changed_at TIMESTAMP NOT NULL
+);
+INSERT INTO product_status_history_new (id, product_id, pvz_id, from_status, to_status, comment, changed_at)
+SELECT id, product_id, pvz_id, from_status, to_status, comment, changed_at FROM product_status_history;
+DROP TABLE product_status_history;
+ALTER TABLE product_status_history_new RENAME TO product_status_history;
+
+CREATE INDEX IF NOT EXISTS idx_product_status_history_product_id
+    ON product_status_history(product_id, changed_at);
+
+UPDATE products SET status = 'stored' WHERE status = 'in_transit';
+CREATE TABLE products_new (
+    id TEXT PRIMARY KEY,
+    date_time TIMESTAMP NOT NULL,
+    type_id INTEGER NOT NULL REFERENCES product_types(id) ON DELETE CASCADE,
+    reception_id TEXT NOT NULL REFERENCES receptions(id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'accepted'
+        CHECK (status IN ('accepted', 'stored', 'issued', 'returned', 'written_off'))
+);
+INSERT INTO products_new (id, date_time, type_id, reception_id, status)
+SELECT id, date_time, type_id, reception_id, status FROM products;
+DROP TABLE products;
+ALTER TABLE products_new RENAME TO products;
+
+CREATE INDEX IF NOT EXISTS idx_products_reception_id_date_time_desc
+    ON products(reception_id, date_time DESC);
+-- +goose StatementEnd
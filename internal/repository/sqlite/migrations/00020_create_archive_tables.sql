@@ -0,0 +1,67 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Closed receptions older than the retention period are moved here with
+-- their products and the history of the products. The archive keeps the
+-- columns of the live tables and the time each reception was archived.
+CREATE TABLE IF NOT EXISTS receptions_archive (
+    id TEXT PRIMARY KEY,
+    date_time TIMESTAMP NOT NULL,
+    pvz_id TEXT NOT NULL REFERENCES pvz(id) ON DELETE CASCADE,
+    status TEXT NOT NULL,
+    version INTEGER NOT NULL,
+    archived_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_receptions_archive_pvz_id_date_time
+    ON receptions_archive(pvz_id, date_time DESC);
+
+CREATE TABLE IF NOT EXISTS products_archive (
+    id TEXT PRIMARY KEY,
+    date_time TIMESTAMP NOT NULL,
+    type_id INTEGER NOT NULL REFERENCES product_types(id) ON DELETE CASCADE,
+    reception_id TEXT NOT NULL REFERENCES receptions_archive(id) ON DELETE CASCADE,
+    status TEXT NOT NULL,
+    barcode TEXT,
+    condition TEXT NOT NULL,
+    condition_note TEXT NOT NULL,
+    photo_key TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_products_archive_reception_id_date_time
+    ON products_archive(reception_id, date_time DESC);
+
+CREATE TABLE IF NOT EXISTS product_status_history_archive (
+    id INTEGER PRIMARY KEY,
+    product_id TEXT NOT NULL REFERENCES products_archive(id) ON DELETE CASCADE,
+    pvz_id TEXT NOT NULL REFERENCES pvz(id),
+    from_status TEXT,
+    to_status TEXT NOT NULL,
+    comment TEXT NOT NULL,
+    changed_at TIMESTAMP NOT NULL,
+    reception_id TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_status_history_archive_product_id
+    ON product_status_history_archive(product_id, changed_at);
+
+-- The archiver looks for closed receptions by age and checks that nothing
+-- else still points at them
+CREATE INDEX IF NOT EXISTS idx_receptions_close_date_time
+    ON receptions(date_time)
+    WHERE status = 'close';
+CREATE INDEX IF NOT EXISTS idx_product_status_history_reception_id
+    ON product_status_history(reception_id);
+CREATE INDEX IF NOT EXISTS idx_transfers_reception_id
+    ON transfers(reception_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_transfers_reception_id;
+DROP INDEX IF EXISTS idx_product_status_history_reception_id;
+DROP INDEX IF EXISTS idx_receptions_close_date_time;
+
+DROP TABLE IF EXISTS product_status_history_archive;
+DROP TABLE IF EXISTS products_archive;
+DROP TABLE IF EXISTS receptions_archive;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Keys reserved before the column existed have no lease and may be taken over
+ALTER TABLE idempotency_keys ADD COLUMN locked_until TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE idempotency_keys DROP COLUMN locked_until;
+-- +goose StatementEnd
@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Manifests and audit entries stay in place when their reception moves to
+-- the archive: reception_id is cleared and the ID is kept in
+-- archived_reception_id, which points into receptions_archive
+ALTER TABLE manifests ADD COLUMN archived_reception_id TEXT;
+
+-- SQLite cannot relax a column, so the audit table is rebuilt
+CREATE TABLE reception_audit_detached (
+    id INTEGER PRIMARY KEY,
+    reception_id TEXT REFERENCES receptions(id),
+    pvz_id TEXT NOT NULL REFERENCES pvz(id),
+    action TEXT NOT NULL CHECK (action IN ('auto_closed', 'flagged_stale', 'force_closed')),
+    actor TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    archived_reception_id TEXT,
+    CHECK (reception_id IS NOT NULL OR archived_reception_id IS NOT NULL)
+);
+
+INSERT INTO reception_audit_detached (id, reception_id, pvz_id, action, actor, reason, created_at)
+SELECT id, reception_id, pvz_id, action, actor, reason, created_at
+FROM reception_audit;
+
+DROP TABLE reception_audit;
+ALTER TABLE reception_audit_detached RENAME TO reception_audit;
+
+CREATE INDEX IF NOT EXISTS idx_reception_audit_reception_id ON reception_audit(reception_id, action);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE TABLE reception_audit_attached (
+    id INTEGER PRIMARY KEY,
+    reception_id TEXT NOT NULL REFERENCES receptions(id),
+    pvz_id TEXT NOT NULL REFERENCES pvz(id),
+    action TEXT NOT NULL CHECK (action IN ('auto_closed', 'flagged_stale', 'force_closed')),
+    actor TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL
+);
+
+-- Entries of archived receptions have no live reception to point at
+INSERT INTO reception_audit_attached (id, reception_id, pvz_id, action, actor, reason, created_at)
+SELECT id, reception_id, pvz_id, action, actor, reason, created_at
+FROM reception_audit
+WHERE reception_id IS NOT NULL;
+
+DROP TABLE reception_audit;
+ALTER TABLE reception_audit_attached RENAME TO reception_audit;
+
+CREATE INDEX IF NOT EXISTS idx_reception_audit_reception_id ON reception_audit(reception_id, action);
+
+ALTER TABLE manifests DROP COLUMN archived_reception_id;
+-- +goose StatementEnd
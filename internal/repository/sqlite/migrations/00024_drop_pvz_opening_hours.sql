@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The schedule is the only source of the working hours of a PVZ. Texts like
+-- "09:00-21:00" become a daily schedule in Moscow time, the timezone of all
+-- the allowed cities; free-form texts cannot be enforced and are dropped.
+INSERT INTO pvz_schedules (pvz_id, timezone, weekly, exceptions, updated_at)
+SELECT p.id, 'Europe/Moscow', (
+    SELECT json_group_array(json_object(
+        'weekday', d.weekday,
+        'intervals', json_array(json_object(
+            'opens', substr(p.opening_hours, 1, 5),
+            'closes', substr(p.opening_hours, 7, 5)))))
+    FROM (SELECT 'monday' AS weekday UNION ALL SELECT 'tuesday' UNION ALL SELECT 'wednesday'
+          UNION ALL SELECT 'thursday' UNION ALL SELECT 'friday' UNION ALL SELECT 'saturday'
+          UNION ALL SELECT 'sunday') d
+), '[]', CURRENT_TIMESTAMP
+FROM pvz p
+WHERE p.opening_hours GLOB '[0-2][0-9]:[0-5][0-9]-[0-2][0-9]:[0-5][0-9]'
+  AND substr(p.opening_hours, 1, 5) < '24:00'
+  AND substr(p.opening_hours, 7, 5) <= '24:00'
+  AND substr(p.opening_hours, 1, 5) < substr(p.opening_hours, 7, 5)
+ON CONFLICT (pvz_id) DO NOTHING;
+
+ALTER TABLE pvz DROP COLUMN opening_hours;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- The schedules stay, the texts are not restored
+ALTER TABLE pvz ADD COLUMN opening_hours TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE pvz_schedules ADD COLUMN receptions_allowed_until TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE pvz_schedules DROP COLUMN receptions_allowed_until;
+-- +goose StatementEnd
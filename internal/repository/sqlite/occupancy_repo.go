@@ -0,0 +1,128 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func (s *SQLite) GetPVZOccupancy(ctx context.Context, pvzID uuid.UUID) (*models.PVZOccupancy, error) {
+	occupancy := models.PVZOccupancy{PVZID: pvzID}
+	err := s.db.QueryRowContext(ctx,
+		"SELECT capacity FROM pvz WHERE id = $1 AND deleted_at IS NULL",
+		pvzID).Scan(&occupancy.Capacity)
+	if err == sql.ErrNoRows {
+		return nil, e.ErrNotFound()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT pt.name, COALESCE(o.count, 0), COALESCE(tc.capacity, 0)
+		 FROM product_types pt
+		 LEFT JOIN pvz_occupancy o ON o.type_id = pt.id AND o.pvz_id = $1
+		 LEFT JOIN pvz_type_capacities tc ON tc.type_id = pt.id AND tc.pvz_id = $1
+		 ORDER BY pt.id`,
+		pvzID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t models.TypeOccupancy
+		if err := rows.Scan(&t.TypeName, &t.Count, &t.Capacity); err != nil {
+			return nil, err
+		}
+		occupancy.Count += t.Count
+		occupancy.Types = append(occupancy.Types, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &occupancy, nil
+}
+
+func (s *SQLite) ListPVZOccupancy(ctx context.Context) ([]models.PVZOccupancy, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT p.id, p.capacity, COALESCE(SUM(o.count), 0)
+		 FROM pvz p
+		 LEFT JOIN pvz_occupancy o ON o.pvz_id = p.id
+		 WHERE p.deleted_at IS NULL
+		 GROUP BY p.id, p.capacity`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []models.PVZOccupancy
+	for rows.Next() {
+		var o models.PVZOccupancy
+		if err := rows.Scan(&o.PVZID, &o.Capacity, &o.Count); err != nil {
+			return nil, err
+		}
+		result = append(result, o)
+	}
+
+	return result, rows.Err()
+}
+
+func (s *SQLite) ReplaceTypeCapacities(ctx context.Context, pvzID uuid.UUID, capacities []models.TypeCapacity) error {
+	return s.withTx(ctx, func(tx tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM pvz_type_capacities WHERE pvz_id = $1", pvzID); err != nil {
+			return err
+		}
+
+		for _, c := range capacities {
+			_, err := tx.ExecContext(ctx,
+				"INSERT INTO pvz_type_capacities (pvz_id, type_id, capacity) VALUES ($1, $2, $3)",
+				pvzID, c.TypeID, c.Capacity)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// reserveCapacity counts one more product of the type in the PVZ occupancy.
+// Transactions start immediate, so concurrent receptions wait for the write
+// lock and cannot both take the last free place of a PVZ.
+func reserveCapacity(ctx context.Context, tx tx, pvzID uuid.UUID, typeID int) error {
+	var capacity, typeCapacity, count, typeCount int
+	err := tx.QueryRowContext(ctx,
+		`SELECT p.capacity, COALESCE(tc.capacity, 0),
+		        COALESCE((SELECT SUM(o.count) FROM pvz_occupancy o WHERE o.pvz_id = p.id), 0),
+		        COALESCE((SELECT o.count FROM pvz_occupancy o WHERE o.pvz_id = p.id AND o.type_id = $2), 0)
+		 FROM pvz p
+		 LEFT JOIN pvz_type_capacities tc ON tc.pvz_id = p.id AND tc.type_id = $2
+		 WHERE p.id = $1`,
+		pvzID, typeID).Scan(&capacity, &typeCapacity, &count, &typeCount)
+	if err != nil {
+		return err
+	}
+
+	if (capacity > 0 && count >= capacity) || (typeCapacity > 0 && typeCount >= typeCapacity) {
+		return e.ErrCapacityExceeded()
+	}
+
+	return adjustOccupancy(ctx, tx, pvzID, typeID, 1)
+}
+
+// adjustOccupancy changes the number of products of the type held by the PVZ
+// by delta, never going below zero
+func adjustOccupancy(ctx context.Context, tx tx, pvzID uuid.UUID, typeID int, delta int) error {
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO pvz_occupancy (pvz_id, type_id, count)
+		 VALUES ($1, $2, MAX($3, 0))
+		 ON CONFLICT (pvz_id, type_id) DO UPDATE
+		 SET count = MAX(pvz_occupancy.count + $3, 0)`,
+		pvzID, typeID, delta)
+	return err
+}
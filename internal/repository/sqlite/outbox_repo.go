@@ -0,0 +1,107 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"pvz-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// insertEvent records an event in the outbox as part of tx
+func insertEvent(ctx context.Context, tx tx, eventType models.EventType, pvzID, receptionID uuid.UUID, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event: %w", eventType, err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO outbox (id, event_type, pvz_id, reception_id, payload, created_at) VALUES ($1, $2, $3, $4, $5, $6)",
+		uuid.New(), eventType, pvzID, receptionID, string(data), time.Now())
+	return err
+}
+
+func (s *SQLite) ClaimOutboxEvents(ctx context.Context, limit int, lease time.Duration) ([]models.Event, error) {
+	var events []models.Event
+	err := s.withTx(ctx, func(tx tx) error {
+		now := time.Now()
+		rows, err := tx.QueryContext(ctx,
+			`SELECT id, event_type, pvz_id, reception_id, payload, created_at, attempts
+			 FROM outbox
+			 WHERE published_at IS NULL AND dead_at IS NULL
+			 AND (next_attempt_at IS NULL OR next_attempt_at <= $1)
+			 ORDER BY created_at
+			 LIMIT $2`,
+			now, limit)
+		if err != nil {
+			return err
+		}
+
+		for rows.Next() {
+			var (
+				ev      models.Event
+				payload string
+			)
+			if err := rows.Scan(&ev.ID, &ev.Type, &ev.PVZID, &ev.ReceptionID, &payload, &ev.CreatedAt, &ev.Attempts); err != nil {
+				rows.Close()
+				return err
+			}
+			ev.Payload = json.RawMessage(payload)
+			events = append(events, ev)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		// The lease hides claimed events from other relays until it runs out
+		leasedUntil := now.Add(lease)
+		for _, ev := range events {
+			_, err := tx.ExecContext(ctx,
+				"UPDATE outbox SET next_attempt_at = $1 WHERE id = $2",
+				leasedUntil, ev.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (s *SQLite) MarkEventPublished(ctx context.Context, id uuid.UUID) error {
+	_, err := s.db.ExecContext(ctx,
+		"UPDATE outbox SET attempts = attempts + 1, last_error = NULL, published_at = $1 WHERE id = $2",
+		time.Now(), id)
+	return err
+}
+
+func (s *SQLite) MarkEventFailed(ctx context.Context, id uuid.UUID, lastErr string, nextAttemptAt time.Time, dead bool) error {
+	var deadAt *time.Time
+	if dead {
+		now := time.Now()
+		deadAt = &now
+	}
+
+	_, err := s.db.ExecContext(ctx,
+		"UPDATE outbox SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2, dead_at = $3 WHERE id = $4",
+		lastErr, nextAttemptAt, deadAt, id)
+	return err
+}
+
+func (s *SQLite) PurgePublishedEvents(ctx context.Context, before time.Time) (int, error) {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM outbox WHERE published_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := res.RowsAffected()
+	return int(affected), err
+}
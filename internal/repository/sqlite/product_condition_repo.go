@@ -0,0 +1,97 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// scanProduct reads the columns selected by the product queries into product
+func scanProduct(row rowScanner, product *models.Product) error {
+	err := row.Scan(&product.ID, &product.DateTime, &product.TypeID, &product.TypeName, &product.ReceptionID, &product.Status,
+		&product.Barcode, &product.Condition, &product.ConditionNote, &product.PhotoKey)
+	if err != nil {
+		return err
+	}
+	product.HasPhoto = product.PhotoKey != ""
+	return nil
+}
+
+func (s *SQLite) SetProductCondition(ctx context.Context, productID uuid.UUID, condition models.ProductCondition, note string) (*models.Product, uuid.UUID, error) {
+	var (
+		product models.Product
+		pvzID   uuid.UUID
+	)
+
+	err := s.withTx(ctx, func(tx tx) error {
+		err := tx.QueryRowContext(ctx,
+			`SELECT r.pvz_id
+			 FROM products p
+			 JOIN receptions r ON r.id = p.reception_id
+			 WHERE p.id = $1 AND p.status = $2 AND r.status = $3`,
+			productID, models.ProductStatusAccepted, models.ReceptionStatusInProgress).Scan(&pvzID)
+		if err == sql.ErrNoRows {
+			return e.ErrProductNotInReception()
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"UPDATE products SET condition = $1, condition_note = $2 WHERE id = $3",
+			condition, note, productID)
+		if err != nil {
+			return err
+		}
+
+		err = scanProduct(tx.QueryRowContext(ctx,
+			`SELECT p.id, p.date_time, p.type_id, pt.name, p.reception_id, p.status,
+			        COALESCE(p.barcode, ''), p.condition, p.condition_note, COALESCE(p.photo_key, '')
+			 FROM products p
+			 JOIN product_types pt ON p.type_id = pt.id
+			 WHERE p.id = $1`,
+			productID), &product)
+		if err != nil {
+			return err
+		}
+
+		return insertEvent(ctx, tx, models.EventProductConditionChanged, pvzID, product.ReceptionID, product)
+	})
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+
+	return &product, pvzID, nil
+}
+
+// SetProductPhoto attaches a photo to the condition report of a product in
+// an open reception and returns the key of the photo it replaced, if any
+func (s *SQLite) SetProductPhoto(ctx context.Context, productID uuid.UUID, photoKey string) (string, error) {
+	var previous string
+
+	err := s.withTx(ctx, func(tx tx) error {
+		err := tx.QueryRowContext(ctx,
+			`SELECT COALESCE(p.photo_key, '')
+			 FROM products p
+			 JOIN receptions r ON r.id = p.reception_id
+			 WHERE p.id = $1 AND p.status = $2 AND r.status = $3`,
+			productID, models.ProductStatusAccepted, models.ReceptionStatusInProgress).Scan(&previous)
+		if err == sql.ErrNoRows {
+			return e.ErrProductNotInReception()
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "UPDATE products SET photo_key = $1 WHERE id = $2", photoKey, productID)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return previous, nil
+}
@@ -0,0 +1,118 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *SQLite) GetProduct(ctx context.Context, productID uuid.UUID) (*models.Product, error) {
+	var product models.Product
+	err := scanProduct(s.db.QueryRowContext(ctx,
+		`SELECT p.id, p.date_time, p.type_id, pt.name, p.reception_id, p.status,
+		        COALESCE(p.barcode, ''), p.condition, p.condition_note, COALESCE(p.photo_key, '')
+		 FROM products p
+		 JOIN product_types pt ON p.type_id = pt.id
+		 WHERE p.id = $1`,
+		productID), &product)
+	if err == sql.ErrNoRows {
+		return nil, e.ErrNotFound()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (s *SQLite) ChangeProductStatus(ctx context.Context, change *models.ProductStatusChange) error {
+	return s.withTx(ctx, func(tx tx) error {
+		var typeID int
+		err := tx.QueryRowContext(ctx,
+			`UPDATE products SET status = $1
+			 WHERE id = $2 AND status = $3
+			 RETURNING reception_id, type_id`,
+			change.To, change.ProductID, change.From).Scan(&change.ReceptionID, &typeID)
+		if err == sql.ErrNoRows {
+			return e.ErrInvalidStatusTransition()
+		}
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRowContext(ctx, "SELECT pvz_id FROM receptions WHERE id = $1", change.ReceptionID).Scan(&change.PVZID)
+		if err != nil {
+			return err
+		}
+
+		// Issued and written-off products leave the PVZ, returned ones come back
+		if change.From.InPVZ() != change.To.InPVZ() {
+			delta := 1
+			if !change.To.InPVZ() {
+				delta = -1
+			}
+			if err := adjustOccupancy(ctx, tx, change.PVZID, typeID, delta); err != nil {
+				return err
+			}
+		}
+
+		return recordStatusChange(ctx, tx, change)
+	})
+}
+
+func (s *SQLite) GetProductHistory(ctx context.Context, productID uuid.UUID) ([]models.ProductStatusChange, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT product_id, pvz_id, reception_id, COALESCE(from_status, ''), to_status, comment, changed_at
+		 FROM product_status_history
+		 WHERE product_id = $1
+		 ORDER BY changed_at, id`,
+		productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []models.ProductStatusChange{}
+	for rows.Next() {
+		var change models.ProductStatusChange
+		if err := rows.Scan(&change.ProductID, &change.PVZID, &change.ReceptionID, &change.From, &change.To, &change.Comment, &change.ChangedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, change)
+	}
+
+	return history, rows.Err()
+}
+
+// insertStatusChange appends an entry to the product history
+func insertStatusChange(ctx context.Context, tx tx, change *models.ProductStatusChange) error {
+	from := sql.NullString{String: string(change.From), Valid: change.From != ""}
+
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO product_status_history (product_id, pvz_id, reception_id, from_status, to_status, comment, changed_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		change.ProductID, change.PVZID, change.ReceptionID, from, change.To, change.Comment, change.ChangedAt)
+	return err
+}
+
+// storeAcceptedProducts puts the products of a closed reception on the shelves
+func storeAcceptedProducts(ctx context.Context, tx tx, receptionID, pvzID uuid.UUID) error {
+	// SQLite has no data-modifying CTEs, so the history is written from the
+	// products before their status changes
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO product_status_history (product_id, pvz_id, reception_id, from_status, to_status, changed_at)
+		 SELECT id, $4, $1, $3, $2, $5 FROM products
+		 WHERE reception_id = $1 AND status = $3`,
+		receptionID, models.ProductStatusStored, models.ProductStatusAccepted, pvzID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE products SET status = $2 WHERE reception_id = $1 AND status = $3",
+		receptionID, models.ProductStatusStored, models.ProductStatusAccepted)
+	return err
+}
@@ -0,0 +1,583 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *SQLite) InsertPVZ(ctx context.Context, pvz *models.PVZ) error {
+	lat, lon := locationArgs(pvz.Location)
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO pvz (id, registration_date, city_id, address, latitude, longitude, capacity)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		pvz.ID, pvz.RegistrationDate, pvz.CityID, pvz.Address, lat, lon, pvz.Capacity)
+	return err
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanPVZ reads the columns selected by the PVZ queries into pvz followed by
+// any extra destinations
+func scanPVZ(row rowScanner, pvz *models.PVZ, extra ...interface{}) error {
+	var lat, lon sql.NullFloat64
+	dest := []interface{}{
+		&pvz.ID, &pvz.RegistrationDate, &pvz.CityID, &pvz.CityName, &pvz.Address,
+		&lat, &lon, &pvz.Capacity, &pvz.Status, &pvz.DeletedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+
+	pvz.Location = nil
+	if lat.Valid && lon.Valid {
+		pvz.Location = &models.GeoPoint{Latitude: lat.Float64, Longitude: lon.Float64}
+	}
+	return nil
+}
+
+func locationArgs(location *models.GeoPoint) (sql.NullFloat64, sql.NullFloat64) {
+	if location == nil {
+		return sql.NullFloat64{}, sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: location.Latitude, Valid: true},
+		sql.NullFloat64{Float64: location.Longitude, Valid: true}
+}
+
+// jsonArray encodes values for a json_each($n) lookup, which stands in for
+// the Postgres = ANY($n) array comparison
+func jsonArray[T any](values []T) (string, error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (s *SQLite) GetCityID(ctx context.Context, city string) (int, error) {
+	var cityID int
+
+	err := s.db.QueryRowContext(ctx, "SELECT id FROM cities WHERE name = $1", city).Scan(&cityID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, e.ErrCityNotAllowed()
+		}
+		return 0, err
+	}
+
+	return cityID, nil
+}
+
+func (s *SQLite) CheckPVZ(ctx context.Context, pvzID uuid.UUID) (bool, error) {
+	var exists bool
+	err := s.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM pvz WHERE id = $1 AND deleted_at IS NULL)", pvzID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check PVZ existence: %w", err)
+	}
+	if !exists {
+		return false, e.ErrNotFound()
+	}
+
+	return true, nil
+}
+
+func (s *SQLite) GetPVZ(ctx context.Context, pvzID uuid.UUID) (*models.PVZ, error) {
+	var pvz models.PVZ
+	err := scanPVZ(s.db.QueryRowContext(ctx,
+		`SELECT p.id, p.registration_date, p.city_id, c.name, p.address, p.latitude, p.longitude, p.capacity, p.status, p.deleted_at, p.version
+		 FROM pvz p
+		 JOIN cities c ON p.city_id = c.id
+		 WHERE p.id = $1 AND p.deleted_at IS NULL`,
+		pvzID), &pvz, &pvz.Version)
+	if err == sql.ErrNoRows {
+		return nil, e.ErrNotFound()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pvz, nil
+}
+
+func (s *SQLite) UpdatePVZ(ctx context.Context, pvz *models.PVZ, version int) (int, error) {
+	var newVersion int
+	lat, lon := locationArgs(pvz.Location)
+	err := s.db.QueryRowContext(ctx,
+		`UPDATE pvz SET city_id = $1, address = $2, latitude = $3, longitude = $4,
+		 capacity = $5, status = $6, version = version + 1
+		 WHERE id = $7 AND version = $8 AND deleted_at IS NULL
+		 RETURNING version`,
+		pvz.CityID, pvz.Address, lat, lon, pvz.Capacity, pvz.Status, pvz.ID, version).Scan(&newVersion)
+	if err == sql.ErrNoRows {
+		return 0, e.ErrVersionMismatch()
+	}
+	if err != nil {
+		return 0, err
+	}
+	return newVersion, nil
+}
+
+func (s *SQLite) DeletePVZ(ctx context.Context, pvzID uuid.UUID, version int) error {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE pvz SET status = 'closed', deleted_at = $3, version = version + 1
+		 WHERE id = $1 AND version = $2 AND deleted_at IS NULL`,
+		pvzID, version, time.Now())
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return e.ErrVersionMismatch()
+	}
+
+	return nil
+}
+
+func (s *SQLite) GetNearbyPVZs(ctx context.Context, point models.GeoPoint, radius float64, limit int) ([]models.NearbyPVZ, error) {
+	// The bounding box lets the (latitude, longitude) index discard most rows
+	// before the exact haversine distance is computed
+	minLat, maxLat, minLon, maxLon := point.BoundingBox(radius)
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT p.id, p.registration_date, p.city_id, c.name, p.address, p.latitude, p.longitude, p.capacity, p.status, p.deleted_at,
+		        2 * $1 * ASIN(MIN(1, SQRT(
+		            POWER(SIN(RADIANS(p.latitude - $2) / 2), 2) +
+		            COS(RADIANS($2)) * COS(RADIANS(p.latitude)) * POWER(SIN(RADIANS(p.longitude - $3) / 2), 2)
+		        ))) AS distance
+		 FROM pvz p
+		 JOIN cities c ON p.city_id = c.id
+		 WHERE p.deleted_at IS NULL AND p.status = 'active'
+		 AND p.latitude BETWEEN $4 AND $5
+		 AND p.longitude BETWEEN $6 AND $7
+		 AND distance <= $8
+		 ORDER BY distance
+		 LIMIT $9`,
+		models.EarthRadius, point.Latitude, point.Longitude, minLat, maxLat, minLon, maxLon, radius, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []models.NearbyPVZ
+	for rows.Next() {
+		var nearby models.NearbyPVZ
+		if err := scanPVZ(rows, &nearby.PVZ, &nearby.Distance); err != nil {
+			return nil, err
+		}
+		result = append(result, nearby)
+	}
+	return result, rows.Err()
+}
+
+func (s *SQLite) GetActiveReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
+	var reception models.Reception
+	err := s.db.QueryRowContext(ctx,
+		`SELECT r.id, r.date_time, r.pvz_id, r.status, r.version, m.id
+		 FROM receptions r
+		 LEFT JOIN manifests m ON m.reception_id = r.id
+		 WHERE r.pvz_id = $1 AND r.status = 'in_progress'
+		 ORDER BY r.date_time DESC
+		 LIMIT 1`,
+		pvzID).Scan(&reception.ID, &reception.DateTime, &reception.PVZID, &reception.Status, &reception.Version, &reception.ManifestID)
+	if err == sql.ErrNoRows {
+		return nil, e.ErrNoActiveReception()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &reception, nil
+}
+
+func (s *SQLite) InsertReception(ctx context.Context, reception *models.Reception) error {
+	return s.withTx(ctx, func(tx tx) error {
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO receptions (id, date_time, pvz_id, status) VALUES ($1, $2, $3, $4)",
+			reception.ID, reception.DateTime, reception.PVZID, reception.Status)
+		if err != nil {
+			return err
+		}
+
+		if reception.ManifestID != nil {
+			if err := linkManifest(ctx, tx, *reception.ManifestID, reception.ID, reception.PVZID); err != nil {
+				return err
+			}
+		}
+
+		return insertEvent(ctx, tx, models.EventReceptionOpened, reception.PVZID, reception.ID, reception)
+	})
+}
+
+func (s *SQLite) GetProductTypeID(ctx context.Context, productTypeName string) (int, error) {
+	var productTypeID int
+	err := s.db.QueryRowContext(ctx, "SELECT id FROM product_types WHERE name = $1", productTypeName).Scan(&productTypeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return productTypeID, e.ErrProductTypeNotAllowed()
+		}
+		return productTypeID, err
+	}
+	return productTypeID, nil
+}
+
+func (s *SQLite) InsertProduct(ctx context.Context, product *models.Product, receptionVersion int) (int, error) {
+	var version int
+	err := s.withTx(ctx, func(tx tx) error {
+		pvzID, newVersion, err := bumpReceptionVersion(ctx, tx, product.ReceptionID, receptionVersion)
+		if err != nil {
+			return err
+		}
+		version = newVersion
+
+		if err := reserveCapacity(ctx, tx, pvzID, product.TypeID); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO products (id, date_time, type_id, reception_id, status, barcode) VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))",
+			product.ID, product.DateTime, product.TypeID, product.ReceptionID, product.Status, product.Barcode)
+		if err != nil {
+			return err
+		}
+
+		err = insertStatusChange(ctx, tx, &models.ProductStatusChange{
+			ProductID:   product.ID,
+			PVZID:       pvzID,
+			ReceptionID: product.ReceptionID,
+			To:          product.Status,
+			ChangedAt:   product.DateTime,
+		})
+		if err != nil {
+			return err
+		}
+
+		return insertEvent(ctx, tx, models.EventProductAdded, pvzID, product.ReceptionID, product)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+func (s *SQLite) GetLastProduct(ctx context.Context, receptionID uuid.UUID, since time.Time) (*models.Product, error) {
+	var product models.Product
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id, date_time, type_id, reception_id, status
+		 FROM products
+		 WHERE reception_id = $1 AND date_time >= $2
+		   AND NOT EXISTS (SELECT 1 FROM transfer_items ti WHERE ti.product_id = products.id)
+		 ORDER BY date_time DESC
+		 LIMIT 1`,
+		receptionID, since).Scan(&product.ID, &product.DateTime, &product.TypeID, &product.ReceptionID, &product.Status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, e.ErrNotFound()
+		}
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (s *SQLite) DeleteProduct(ctx context.Context, productID uuid.UUID, receptionVersion int) (int, string, error) {
+	var version int
+	var photoKey string
+	err := s.withTx(ctx, func(tx tx) error {
+		var product models.Product
+		err := tx.QueryRowContext(ctx,
+			`SELECT p.id, p.date_time, p.type_id, pt.name, p.reception_id, p.status, COALESCE(p.photo_key, '')
+			 FROM products p
+			 JOIN product_types pt ON pt.id = p.type_id
+			 WHERE p.id = $1
+			   AND NOT EXISTS (SELECT 1 FROM transfer_items ti WHERE ti.product_id = p.id)`,
+			productID).Scan(&product.ID, &product.DateTime, &product.TypeID, &product.TypeName, &product.ReceptionID, &product.Status, &photoKey)
+		if err == sql.ErrNoRows {
+			return e.ErrNotFound()
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM products WHERE id = $1", productID); err != nil {
+			return err
+		}
+
+		// Rolls the deletion back if the reception has changed meanwhile
+		pvzID, newVersion, err := bumpReceptionVersion(ctx, tx, product.ReceptionID, receptionVersion)
+		if err != nil {
+			return err
+		}
+		version = newVersion
+
+		if err := adjustOccupancy(ctx, tx, pvzID, product.TypeID, -1); err != nil {
+			return err
+		}
+
+		return insertEvent(ctx, tx, models.EventProductDeleted, pvzID, product.ReceptionID, product)
+	})
+	if err != nil {
+		return 0, "", err
+	}
+	return version, photoKey, nil
+}
+
+func (s *SQLite) UpdateReceptionStatus(ctx context.Context, receptionID uuid.UUID, status models.ReceptionStatus, version int) (int, error) {
+	var reception *models.Reception
+	err := s.withTx(ctx, func(tx tx) error {
+		var err error
+		reception, err = setReceptionStatus(ctx, tx, receptionID, status, version)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return reception.Version, nil
+}
+
+// setReceptionStatus moves a reception that still has the expected version
+// to status. Closing a reception also stores its products and reconciles it
+// with the manifest.
+func setReceptionStatus(ctx context.Context, tx tx, receptionID uuid.UUID, status models.ReceptionStatus, version int) (*models.Reception, error) {
+	res, err := tx.ExecContext(ctx,
+		"UPDATE receptions SET status = $1, version = version + 1 WHERE id = $2 AND version = $3",
+		status, receptionID, version)
+	if err != nil {
+		return nil, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, e.ErrVersionMismatch()
+	}
+
+	var reception models.Reception
+	err = tx.QueryRowContext(ctx,
+		"SELECT id, date_time, pvz_id, status, version FROM receptions WHERE id = $1",
+		receptionID).Scan(&reception.ID, &reception.DateTime, &reception.PVZID, &reception.Status, &reception.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	eventType := models.EventReceptionOpened
+	if status == models.ReceptionStatusClose {
+		eventType = models.EventReceptionClosed
+
+		if err := storeAcceptedProducts(ctx, tx, reception.ID, reception.PVZID); err != nil {
+			return nil, err
+		}
+
+		reception.Discrepancies, err = reconcileManifest(ctx, tx, reception.ID, time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := insertEvent(ctx, tx, eventType, reception.PVZID, reception.ID, reception); err != nil {
+		return nil, err
+	}
+	return &reception, nil
+}
+
+// bumpReceptionVersion increments the version of a reception that still has
+// the expected one and returns its PVZ and new version
+func bumpReceptionVersion(ctx context.Context, tx tx, receptionID uuid.UUID, version int) (uuid.UUID, int, error) {
+	var (
+		pvzID      uuid.UUID
+		newVersion int
+	)
+	err := tx.QueryRowContext(ctx,
+		"UPDATE receptions SET version = version + 1 WHERE id = $1 AND version = $2 RETURNING pvz_id, version",
+		receptionID, version).Scan(&pvzID, &newVersion)
+	if err == sql.ErrNoRows {
+		return uuid.Nil, 0, e.ErrVersionMismatch()
+	}
+	if err != nil {
+		return uuid.Nil, 0, err
+	}
+	return pvzID, newVersion, nil
+}
+
+func (s *SQLite) GetPVZs(ctx context.Context, from, to time.Time, limit, offset int) ([]models.PVZ, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT p.id, p.registration_date, p.city_id, c.name, p.address, p.latitude, p.longitude, p.capacity, p.status, p.deleted_at
+		 FROM pvz p
+		 JOIN cities c ON p.city_id = c.id
+		 WHERE EXISTS (
+			 SELECT 1 FROM receptions r
+			 WHERE r.pvz_id = p.id
+			 AND r.date_time BETWEEN $1 AND $2
+		 )
+		 ORDER BY p.registration_date DESC
+		 LIMIT $3 OFFSET $4`,
+		from, to, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pvzs []models.PVZ
+	for rows.Next() {
+		var pvz models.PVZ
+		if err := scanPVZ(rows, &pvz); err != nil {
+			return nil, err
+		}
+		pvzs = append(pvzs, pvz)
+	}
+	return pvzs, rows.Err()
+}
+
+func (s *SQLite) GetPVZsWithNoFilter(ctx context.Context) ([]models.PVZ, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT p.id, p.registration_date, p.city_id, c.name, p.address, p.latitude, p.longitude, p.capacity, p.status, p.deleted_at
+		 FROM pvz p
+		 JOIN cities c ON p.city_id = c.id
+		 WHERE p.deleted_at IS NULL
+		 ORDER BY p.registration_date DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pvzs []models.PVZ
+	for rows.Next() {
+		var pvz models.PVZ
+		if err := scanPVZ(rows, &pvz); err != nil {
+			return nil, err
+		}
+		pvzs = append(pvzs, pvz)
+	}
+	return pvzs, rows.Err()
+}
+
+func (s *SQLite) GetReceptionsForPVZs(ctx context.Context, pvzIDs []uuid.UUID, from, to time.Time) ([]models.Reception, error) {
+	ids, err := jsonArray(pvzIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, date_time, pvz_id, status
+		 FROM receptions
+		 WHERE pvz_id IN (SELECT value FROM json_each($1)) AND date_time BETWEEN $2 AND $3
+		 ORDER BY date_time DESC`,
+		ids, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var receptions []models.Reception
+	for rows.Next() {
+		var rec models.Reception
+		if err := rows.Scan(&rec.ID, &rec.DateTime, &rec.PVZID, &rec.Status); err != nil {
+			return nil, err
+		}
+		receptions = append(receptions, rec)
+	}
+	return receptions, rows.Err()
+}
+
+func (s *SQLite) GetProductsForReceptions(ctx context.Context, receptionIDs []uuid.UUID, since time.Time) ([]models.Product, error) {
+	if len(receptionIDs) == 0 {
+		return []models.Product{}, nil
+	}
+
+	ids, err := jsonArray(receptionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT p.id, p.date_time, p.type_id, pt.name, p.reception_id, p.status,
+		        COALESCE(p.barcode, ''), p.condition, p.condition_note, COALESCE(p.photo_key, '')
+		 FROM products p
+		 JOIN product_types pt ON p.type_id = pt.id
+		 WHERE p.reception_id IN (SELECT value FROM json_each($1)) AND p.date_time >= $2
+		 ORDER BY p.date_time DESC`,
+		ids, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []models.Product
+	for rows.Next() {
+		var prod models.Product
+		if err := scanProduct(rows, &prod); err != nil {
+			return nil, err
+		}
+		products = append(products, prod)
+	}
+	return products, rows.Err()
+}
+
+func (s *SQLite) IteratePVZs(ctx context.Context, filter models.PVZFilter, fn func(models.PVZ) error) error {
+	query := `SELECT p.id, p.registration_date, p.city_id, c.name, p.address, p.latitude, p.longitude, p.capacity, p.status, p.deleted_at
+		 FROM pvz p
+		 JOIN cities c ON p.city_id = c.id`
+
+	var (
+		conds []string
+		args  []interface{}
+	)
+
+	if len(filter.Cities) > 0 {
+		cities, err := jsonArray(filter.Cities)
+		if err != nil {
+			return err
+		}
+		args = append(args, cities)
+		conds = append(conds, fmt.Sprintf("c.name IN (SELECT value FROM json_each($%d))", len(args)))
+	}
+
+	if !filter.From.IsZero() || !filter.To.IsZero() {
+		recConds := []string{"r.pvz_id = p.id"}
+		if !filter.From.IsZero() {
+			args = append(args, filter.From)
+			recConds = append(recConds, fmt.Sprintf("r.date_time >= $%d", len(args)))
+		}
+		if !filter.To.IsZero() {
+			args = append(args, filter.To)
+			recConds = append(recConds, fmt.Sprintf("r.date_time <= $%d", len(args)))
+		}
+		conds = append(conds, "EXISTS (SELECT 1 FROM receptions r WHERE "+strings.Join(recConds, " AND ")+")")
+	} else {
+		// Deleted PVZs stay only in the listings of receptions by date
+		conds = append(conds, "p.deleted_at IS NULL")
+	}
+
+	if len(conds) > 0 {
+		query += "\n\t\t WHERE " + strings.Join(conds, " AND ")
+	}
+	query += "\n\t\t ORDER BY p.registration_date DESC"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var pvz models.PVZ
+		if err := scanPVZ(rows, &pvz); err != nil {
+			return err
+		}
+		if err := fn(pvz); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,152 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRepo(t *testing.T) *SQLite {
+	t.Helper()
+
+	repo, err := Open(filepath.Join(t.TempDir(), "pvz.db"))
+	require.NoError(t, err)
+	t.Cleanup(repo.CloseConnection)
+	return repo
+}
+
+func insertTestPVZ(t *testing.T, repo *SQLite, capacity int) uuid.UUID {
+	t.Helper()
+
+	pvz := &models.PVZ{ID: uuid.New(), RegistrationDate: time.Now(), CityID: 1, Capacity: capacity}
+	require.NoError(t, repo.InsertPVZ(context.Background(), pvz))
+	return pvz.ID
+}
+
+func openTestReception(t *testing.T, repo *SQLite, pvzID uuid.UUID, at time.Time) uuid.UUID {
+	t.Helper()
+
+	reception := &models.Reception{ID: uuid.New(), DateTime: at, PVZID: pvzID, Status: models.ReceptionStatusInProgress}
+	require.NoError(t, repo.InsertReception(context.Background(), reception))
+	return reception.ID
+}
+
+func TestInsertReception_Manifest(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	pvzID := insertTestPVZ(t, repo, 0)
+
+	manifest := &models.Manifest{
+		ID:     uuid.New(),
+		PVZID:  pvzID,
+		Status: models.ManifestStatusPending,
+		Items:  []models.ManifestItem{{Barcode: "B2", TypeID: 2}, {Barcode: "A1", TypeID: 1}},
+	}
+	require.NoError(t, repo.CreateManifest(ctx, manifest))
+
+	reception := &models.Reception{ID: uuid.New(), DateTime: time.Now(), PVZID: pvzID, Status: models.ReceptionStatusInProgress, ManifestID: &manifest.ID}
+	require.NoError(t, repo.InsertReception(ctx, reception))
+
+	other := &models.Reception{ID: uuid.New(), DateTime: time.Now(), PVZID: pvzID, Status: models.ReceptionStatusInProgress, ManifestID: &manifest.ID}
+	assert.Equal(t, e.ErrManifestNotAvailable(), repo.InsertReception(ctx, other))
+
+	version, err := repo.InsertProduct(ctx, &models.Product{ID: uuid.New(), TypeID: 1, ReceptionID: reception.ID, Barcode: "A1", Status: models.ProductStatusAccepted}, 1)
+	require.NoError(t, err)
+	_, err = repo.UpdateReceptionStatus(ctx, reception.ID, models.ReceptionStatusClose, version)
+	require.NoError(t, err)
+
+	reconciled, err := repo.GetManifest(ctx, manifest.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ManifestStatusReconciled, reconciled.Status)
+	assert.Equal(t, "A1", reconciled.Items[0].Barcode)
+	if assert.NotNil(t, reconciled.Report) {
+		assert.Equal(t, 2, reconciled.Report.Expected)
+		assert.Equal(t, "B2", reconciled.Report.Missing[0].Barcode)
+	}
+}
+
+func TestStaleReceptions(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	pvzID := insertTestPVZ(t, repo, 0)
+	openedAt := time.Now().Add(-3 * time.Hour)
+	receptionID := openTestReception(t, repo, pvzID, openedAt)
+
+	// The last product counts as the latest activity
+	product := &models.Product{ID: uuid.New(), DateTime: openedAt.Add(time.Hour), TypeID: 1, ReceptionID: receptionID, Status: models.ProductStatusAccepted}
+	version, err := repo.InsertProduct(ctx, product, 1)
+	require.NoError(t, err)
+
+	stale, err := repo.GetStaleReceptions(ctx, openedAt.Add(30*time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, stale)
+
+	stale, err = repo.GetStaleReceptions(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, stale, 1)
+	assert.Equal(t, receptionID, stale[0].ID)
+	assert.WithinDuration(t, product.DateTime, stale[0].LastActivity, time.Microsecond)
+	assert.False(t, stale[0].Flagged)
+
+	entry := &models.ReceptionAuditEntry{Action: models.ReceptionAuditForceClosed, Actor: "moderator", Reason: "forgotten", CreatedAt: time.Now()}
+	_, err = repo.ForceCloseReception(ctx, receptionID, version-1, entry)
+	assert.Equal(t, e.ErrVersionMismatch(), err)
+
+	version, err = repo.ForceCloseReception(ctx, receptionID, version, entry)
+	require.NoError(t, err)
+	assert.Equal(t, 3, version)
+
+	audit, err := repo.GetPVZReceptionAudit(ctx, pvzID)
+	require.NoError(t, err)
+	require.Len(t, audit, 1)
+	assert.Equal(t, receptionID, audit[0].ReceptionID)
+
+	stale, err = repo.GetStaleReceptions(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, stale)
+}
+
+func TestProductCondition(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	pvzID := insertTestPVZ(t, repo, 0)
+	receptionID := openTestReception(t, repo, pvzID, time.Now())
+
+	product := &models.Product{ID: uuid.New(), DateTime: time.Now(), TypeID: 1, ReceptionID: receptionID, Status: models.ProductStatusAccepted}
+	version, err := repo.InsertProduct(ctx, product, 1)
+	require.NoError(t, err)
+
+	updated, gotPVZ, err := repo.SetProductCondition(ctx, product.ID, models.ProductConditionDamaged, "dented box")
+	require.NoError(t, err)
+	assert.Equal(t, pvzID, gotPVZ)
+	assert.Equal(t, models.ProductConditionDamaged, updated.Condition)
+	assert.Equal(t, "dented box", updated.ConditionNote)
+
+	previous, err := repo.SetProductPhoto(ctx, product.ID, "photos/1.jpg")
+	require.NoError(t, err)
+	assert.Empty(t, previous)
+	previous, err = repo.SetProductPhoto(ctx, product.ID, "photos/2.jpg")
+	require.NoError(t, err)
+	assert.Equal(t, "photos/1.jpg", previous)
+
+	_, err = repo.UpdateReceptionStatus(ctx, receptionID, models.ReceptionStatusClose, version)
+	require.NoError(t, err)
+
+	// Reports are closed together with the reception
+	_, _, err = repo.SetProductCondition(ctx, product.ID, models.ProductConditionOK, "")
+	assert.Equal(t, e.ErrProductNotInReception(), err)
+	_, err = repo.SetProductPhoto(ctx, product.ID, "photos/3.jpg")
+	assert.Equal(t, e.ErrProductNotInReception(), err)
+
+	stored, err := repo.GetProduct(ctx, product.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.HasPhoto)
+}
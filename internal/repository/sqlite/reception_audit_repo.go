@@ -0,0 +1,106 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"pvz-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// execer is implemented by both database and tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func insertReceptionAudit(ctx context.Context, ex execer, entry *models.ReceptionAuditEntry) error {
+	_, err := ex.ExecContext(ctx,
+		`INSERT INTO reception_audit (reception_id, pvz_id, action, actor, reason, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		entry.ReceptionID, entry.PVZID, entry.Action, entry.Actor, entry.Reason, entry.CreatedAt)
+	return err
+}
+
+func (s *SQLite) InsertReceptionAudit(ctx context.Context, entry *models.ReceptionAuditEntry) error {
+	return insertReceptionAudit(ctx, s.db, entry)
+}
+
+func (s *SQLite) ForceCloseReception(ctx context.Context, receptionID uuid.UUID, version int, entry *models.ReceptionAuditEntry) (int, error) {
+	var reception *models.Reception
+	err := s.withTx(ctx, func(tx tx) error {
+		var err error
+		reception, err = setReceptionStatus(ctx, tx, receptionID, models.ReceptionStatusClose, version)
+		if err != nil {
+			return err
+		}
+
+		entry.ReceptionID = reception.ID
+		entry.PVZID = reception.PVZID
+		return insertReceptionAudit(ctx, tx, entry)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return reception.Version, nil
+}
+
+func (s *SQLite) GetStaleReceptions(ctx context.Context, idleSince time.Time) ([]models.StaleReception, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT r.id, r.date_time, r.pvz_id, r.status, r.version, m.id,
+		        MAX(r.date_time, COALESCE(MAX(p.date_time), r.date_time)) AS last_activity,
+		        EXISTS (SELECT 1 FROM reception_audit a WHERE a.reception_id = r.id AND a.action = $2)
+		 FROM receptions r
+		 LEFT JOIN manifests m ON m.reception_id = r.id
+		 LEFT JOIN products p ON p.reception_id = r.id
+		 WHERE r.status = $1
+		 GROUP BY r.id, m.id
+		 HAVING last_activity < $3
+		 ORDER BY last_activity`,
+		models.ReceptionStatusInProgress, models.ReceptionAuditFlaggedStale, idleSince)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stale := []models.StaleReception{}
+	for rows.Next() {
+		var (
+			reception    models.StaleReception
+			lastActivity string
+		)
+		if err := rows.Scan(&reception.ID, &reception.DateTime, &reception.PVZID, &reception.Status,
+			&reception.Version, &reception.ManifestID, &lastActivity, &reception.Flagged); err != nil {
+			return nil, err
+		}
+		var err error
+		if reception.LastActivity, err = parseTime(lastActivity); err != nil {
+			return nil, err
+		}
+		stale = append(stale, reception)
+	}
+	return stale, rows.Err()
+}
+
+func (s *SQLite) GetPVZReceptionAudit(ctx context.Context, pvzID uuid.UUID) ([]models.ReceptionAuditEntry, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT COALESCE(reception_id, archived_reception_id), pvz_id, action, actor, reason, created_at
+		 FROM reception_audit
+		 WHERE pvz_id = $1
+		 ORDER BY created_at DESC, id DESC`,
+		pvzID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.ReceptionAuditEntry{}
+	for rows.Next() {
+		var entry models.ReceptionAuditEntry
+		if err := rows.Scan(&entry.ReceptionID, &entry.PVZID, &entry.Action, &entry.Actor, &entry.Reason, &entry.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
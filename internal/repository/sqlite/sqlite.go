@@ -0,0 +1,115 @@
+// Package sqlite stores the service data in a single SQLite file for
+// deployments that cannot run a Postgres server. It implements the same
+// repository interfaces and error semantics as the postgres package.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"net/url"
+
+	"github.com/pressly/goose/v3"
+	_ "modernc.org/sqlite"
+)
+
+//go:embed migrations/*.sql
+var embedMigrations embed.FS
+
+type SQLite struct {
+	db database
+}
+
+// dsn builds the connection string for the database file. Timestamps are
+// written in the SQLite text format and immediate transactions take the
+// write lock up front, so concurrent writers wait for each other instead of
+// failing on lock upgrade.
+func dsn(path string, foreignKeys bool) string {
+	params := url.Values{}
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", fmt.Sprintf("foreign_keys(%d)", boolToInt(foreignKeys)))
+	params.Set("_time_format", "sqlite")
+	params.Set("_txlock", "immediate")
+
+	return "file:" + path + "?" + params.Encode()
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Open migrates the database file at path and connects to it
+func Open(path string) (*SQLite, error) {
+	const op = "repository.sqlite.Open"
+
+	if err := migrate(path); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	db, err := sql.Open("sqlite", dsn(path, true))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &SQLite{db: database{db}}, nil
+}
+
+func migrate(path string) error {
+	const op = "repository.sqlite.migrate"
+
+	provider, err := NewMigrator(path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer provider.Close()
+
+	if _, err := provider.Up(context.Background()); err != nil {
+		return fmt.Errorf("%s: migration error: %w", op, err)
+	}
+
+	return nil
+}
+
+// NewMigrator opens the database file at path for the migrate command.
+// Closing the returned provider closes the file.
+func NewMigrator(path string) (*goose.Provider, error) {
+	const op = "repository.sqlite.NewMigrator"
+
+	// Migrations rebuild tables that others reference, which SQLite only
+	// allows with foreign key enforcement off
+	db, err := sql.Open("sqlite", dsn(path, false))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	migrations, err := fs.Sub(embedMigrations, "migrations")
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	provider, err := goose.NewProvider(goose.DialectSQLite3, db, migrations)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: migration error: %w", op, err)
+	}
+
+	return provider, nil
+}
+
+func (s *SQLite) CloseConnection() {
+	if s.db.DB != nil {
+		s.db.Close()
+	}
+}
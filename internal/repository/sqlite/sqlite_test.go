@@ -0,0 +1,36 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrations_UpDownUp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pvz.db")
+	db, err := sql.Open("sqlite", dsn(path, false))
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrations, err := fs.Sub(embedMigrations, "migrations")
+	require.NoError(t, err)
+	provider, err := goose.NewProvider(goose.DialectSQLite3, db, migrations)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	_, err = provider.Up(ctx)
+	require.NoError(t, err)
+	_, err = provider.DownTo(ctx, 0)
+	require.NoError(t, err)
+	_, err = provider.Up(ctx)
+	require.NoError(t, err)
+
+	var violations int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM pragma_foreign_key_check").Scan(&violations))
+	require.Zero(t, violations)
+}
@@ -0,0 +1,286 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *SQLite) InsertSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	if sub.PVZID != nil {
+		if _, err := s.CheckPVZ(ctx, *sub.PVZID); err != nil {
+			return err
+		}
+	}
+	if sub.CityName != "" {
+		cityID, err := s.GetCityID(ctx, sub.CityName)
+		if err != nil {
+			return err
+		}
+		sub.CityID = &cityID
+	}
+
+	eventTypes, err := jsonArray(sub.EventTypes)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO webhook_subscriptions (id, url, secret, event_types, pvz_id, city_id, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		sub.ID, sub.URL, sub.Secret, eventTypes, sub.PVZID, sub.CityID, sub.CreatedAt)
+	return err
+}
+
+func (s *SQLite) GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT s.id, s.url, s.secret, s.event_types, s.pvz_id, s.city_id, COALESCE(c.name, ''), s.created_at
+		 FROM webhook_subscriptions s
+		 LEFT JOIN cities c ON s.city_id = c.id
+		 ORDER BY s.created_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []models.WebhookSubscription
+	for rows.Next() {
+		var (
+			sub        models.WebhookSubscription
+			eventTypes string
+			pvzID      uuid.NullUUID
+			cityID     sql.NullInt64
+		)
+		if err := rows.Scan(&sub.ID, &sub.URL, &sub.Secret, &eventTypes, &pvzID, &cityID, &sub.CityName, &sub.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal([]byte(eventTypes), &sub.EventTypes); err != nil {
+			return nil, err
+		}
+		if pvzID.Valid {
+			sub.PVZID = &pvzID.UUID
+		}
+		if cityID.Valid {
+			id := int(cityID.Int64)
+			sub.CityID = &id
+		}
+
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+func (s *SQLite) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM webhook_subscriptions WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return e.ErrNotFound()
+	}
+
+	return nil
+}
+
+func (s *SQLite) EnqueueDeliveries(ctx context.Context, event models.Event) (int, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+
+	// Delivery IDs are generated here, so the matching subscriptions are
+	// read before the deliveries are inserted one by one
+	var enqueued int
+	err = s.withTx(ctx, func(tx tx) error {
+		rows, err := tx.QueryContext(ctx,
+			`SELECT s.id
+			 FROM webhook_subscriptions s
+			 WHERE (json_array_length(s.event_types) = 0
+			        OR EXISTS (SELECT 1 FROM json_each(s.event_types) t WHERE t.value = $1))
+			 AND (s.pvz_id IS NULL OR s.pvz_id = $2)
+			 AND (s.city_id IS NULL OR s.city_id = (SELECT city_id FROM pvz WHERE id = $2))`,
+			string(event.Type), event.PVZID)
+		if err != nil {
+			return err
+		}
+
+		var subscriptionIDs []uuid.UUID
+		for rows.Next() {
+			var id uuid.UUID
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			subscriptionIDs = append(subscriptionIDs, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, subscriptionID := range subscriptionIDs {
+			res, err := tx.ExecContext(ctx,
+				`INSERT INTO webhook_deliveries (id, subscription_id, event_id, event_type, payload, next_attempt_at, created_at)
+				 VALUES ($1, $2, $3, $4, $5, $6, $6)
+				 ON CONFLICT (subscription_id, event_id) DO NOTHING`,
+				uuid.New(), subscriptionID, event.ID, string(event.Type), string(payload), now)
+			if err != nil {
+				return err
+			}
+
+			affected, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			enqueued += int(affected)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return enqueued, nil
+}
+
+func (s *SQLite) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := s.withTx(ctx, func(tx tx) error {
+		now := time.Now()
+		rows, err := tx.QueryContext(ctx,
+			`SELECT d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status,
+			        d.attempts, d.next_attempt_at, d.last_error, d.last_status_code, d.created_at,
+			        s.url, s.secret
+			 FROM webhook_deliveries d
+			 JOIN webhook_subscriptions s ON s.id = d.subscription_id
+			 WHERE d.status = 'pending' AND d.next_attempt_at <= $1
+			 ORDER BY d.next_attempt_at
+			 LIMIT $2`,
+			now, limit)
+		if err != nil {
+			return err
+		}
+
+		for rows.Next() {
+			var (
+				d       models.WebhookDelivery
+				payload string
+			)
+			if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &payload, &d.Status,
+				&d.Attempts, &d.NextAttemptAt, &d.LastError, &d.LastStatusCode, &d.CreatedAt,
+				&d.URL, &d.Secret); err != nil {
+				rows.Close()
+				return err
+			}
+			d.Payload = json.RawMessage(payload)
+			deliveries = append(deliveries, d)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		// The lease hides claimed deliveries from other workers until it runs out
+		leasedUntil := now.Add(lease)
+		for i := range deliveries {
+			_, err := tx.ExecContext(ctx,
+				"UPDATE webhook_deliveries SET next_attempt_at = $1 WHERE id = $2",
+				leasedUntil, deliveries[i].ID)
+			if err != nil {
+				return err
+			}
+			deliveries[i].NextAttemptAt = leasedUntil
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (s *SQLite) MarkDeliverySucceeded(ctx context.Context, id uuid.UUID, statusCode int) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE webhook_deliveries
+		 SET status = 'delivered', attempts = attempts + 1, last_error = '', last_status_code = $1, delivered_at = $3
+		 WHERE id = $2`,
+		statusCode, id, time.Now())
+	return err
+}
+
+func (s *SQLite) MarkDeliveryFailed(ctx context.Context, id uuid.UUID, statusCode int, lastErr string, nextAttemptAt time.Time, dead bool) error {
+	status := models.DeliveryStatusPending
+	if dead {
+		status = models.DeliveryStatusDead
+	}
+
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE webhook_deliveries
+		 SET status = $1, attempts = attempts + 1, last_error = $2, last_status_code = $3, next_attempt_at = $4
+		 WHERE id = $5`,
+		status, lastErr, statusCode, nextAttemptAt, id)
+	return err
+}
+
+func (s *SQLite) GetDeadDeliveries(ctx context.Context, subscriptionID uuid.UUID) ([]models.WebhookDelivery, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, subscription_id, event_id, event_type, payload, status,
+		        attempts, next_attempt_at, last_error, last_status_code, created_at
+		 FROM webhook_deliveries
+		 WHERE subscription_id = $1 AND status = 'dead'
+		 ORDER BY created_at DESC`,
+		subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		var (
+			d       models.WebhookDelivery
+			payload string
+		)
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &payload, &d.Status,
+			&d.Attempts, &d.NextAttemptAt, &d.LastError, &d.LastStatusCode, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		d.Payload = json.RawMessage(payload)
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func (s *SQLite) RedeliverDelivery(ctx context.Context, id uuid.UUID) error {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE webhook_deliveries
+		 SET status = 'pending', attempts = 0, next_attempt_at = $2, delivered_at = NULL
+		 WHERE id = $1 AND status = 'dead'`,
+		id, time.Now())
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return e.ErrNotFound()
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertSubscription_CityNotAllowed(t *testing.T) {
+	sub := &models.WebhookSubscription{ID: uuid.New(), URL: "http://example.com", CityName: "Новосибирск"}
+	assert.Equal(t, e.ErrCityNotAllowed(), newTestRepo(t).InsertSubscription(context.Background(), sub))
+}
+
+func TestInsertSubscription_UnknownPVZ(t *testing.T) {
+	pvzID := uuid.New()
+	sub := &models.WebhookSubscription{ID: uuid.New(), URL: "http://example.com", PVZID: &pvzID}
+	assert.Equal(t, e.ErrNotFound(), newTestRepo(t).InsertSubscription(context.Background(), sub))
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	pvzID := insertTestPVZ(t, repo, 0)
+
+	sub := &models.WebhookSubscription{
+		ID:         uuid.New(),
+		URL:        "http://example.com",
+		EventTypes: []models.EventType{models.EventReceptionOpened},
+		CityName:   "Москва",
+		CreatedAt:  time.Now(),
+	}
+	require.NoError(t, repo.InsertSubscription(ctx, sub))
+	other := &models.WebhookSubscription{ID: uuid.New(), URL: "http://example.org", CityName: "Казань", CreatedAt: time.Now()}
+	require.NoError(t, repo.InsertSubscription(ctx, other))
+
+	event := models.Event{ID: uuid.New(), Type: models.EventReceptionOpened, PVZID: pvzID}
+	enqueued, err := repo.EnqueueDeliveries(ctx, event)
+	require.NoError(t, err)
+	assert.Equal(t, 1, enqueued)
+
+	// The same event is queued once per subscription
+	enqueued, err = repo.EnqueueDeliveries(ctx, event)
+	require.NoError(t, err)
+	assert.Equal(t, 0, enqueued)
+
+	deliveries, err := repo.ClaimDueDeliveries(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, sub.URL, deliveries[0].URL)
+
+	// A claimed delivery is leased
+	claimed, err := repo.ClaimDueDeliveries(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	require.NoError(t, repo.MarkDeliveryFailed(ctx, deliveries[0].ID, 500, "boom", time.Now(), true))
+	dead, err := repo.GetDeadDeliveries(ctx, sub.ID)
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	assert.Equal(t, "boom", dead[0].LastError)
+
+	require.NoError(t, repo.RedeliverDelivery(ctx, deliveries[0].ID))
+	assert.Equal(t, e.ErrNotFound(), repo.RedeliverDelivery(ctx, uuid.New()))
+	// Only dead deliveries are redelivered
+	assert.Equal(t, e.ErrNotFound(), repo.RedeliverDelivery(ctx, deliveries[0].ID))
+
+	require.NoError(t, repo.DeleteSubscription(ctx, sub.ID))
+	assert.Equal(t, e.ErrNotFound(), repo.DeleteSubscription(ctx, sub.ID))
+
+	claimed, err = repo.ClaimDueDeliveries(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+}
+
+func TestOutbox(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	pvzID := insertTestPVZ(t, repo, 0)
+	openTestReception(t, repo, pvzID, time.Now())
+	openTestReception(t, repo, insertTestPVZ(t, repo, 0), time.Now())
+
+	events, err := repo.ClaimOutboxEvents(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, pvzID, events[0].PVZID)
+
+	// Claimed events wait for the lease to run out
+	claimed, err := repo.ClaimOutboxEvents(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	require.NoError(t, repo.MarkEventFailed(ctx, events[0].ID, "broker down", time.Now().Add(-time.Second), false))
+	require.NoError(t, repo.MarkEventFailed(ctx, events[1].ID, "broker down", time.Now().Add(-time.Second), true))
+
+	// The parked event is not claimed again
+	claimed, err = repo.ClaimOutboxEvents(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, events[0].ID, claimed[0].ID)
+	assert.Equal(t, 1, claimed[0].Attempts)
+
+	require.NoError(t, repo.MarkEventPublished(ctx, claimed[0].ID))
+
+	deleted, err := repo.PurgePublishedEvents(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 0, deleted)
+
+	deleted, err = repo.PurgePublishedEvents(ctx, time.Now().Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+}
+
+func TestIdempotencyKeys(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	record, err := repo.ReserveIdempotencyKey(ctx, "key", "fp", time.Hour, time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, record)
+
+	record, err = repo.ReserveIdempotencyKey(ctx, "key", "other", time.Hour, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.False(t, record.Completed())
+
+	require.NoError(t, repo.SaveIdempotencyResponse(ctx, "key", 201, "application/json", []byte(`{}`)))
+	record, err = repo.ReserveIdempotencyKey(ctx, "key", "fp", time.Hour, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 201, record.StatusCode)
+
+	// A request lost past its lease is taken over by its retry only
+	_, err = repo.ReserveIdempotencyKey(ctx, "lost", "fp", time.Hour, -time.Second)
+	require.NoError(t, err)
+	record, err = repo.ReserveIdempotencyKey(ctx, "lost", "other", time.Hour, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, "fp", record.Fingerprint)
+	record, err = repo.ReserveIdempotencyKey(ctx, "lost", "fp", time.Hour, time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, record)
+	record, err = repo.ReserveIdempotencyKey(ctx, "lost", "fp", time.Hour, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.False(t, record.Completed())
+
+	// Expired keys are taken over and cleaned up
+	_, err = repo.ReserveIdempotencyKey(ctx, "expired", "fp", -time.Second, time.Minute)
+	require.NoError(t, err)
+	record, err = repo.ReserveIdempotencyKey(ctx, "expired", "fp", -time.Second, time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, record)
+
+	deleted, err := repo.DeleteExpiredIdempotencyKeys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+}
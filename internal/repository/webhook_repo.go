@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"pvz-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookRepository interface {
+	// Subscription operations. InsertSubscription returns ErrNotFound when the
+	// PVZ to filter by does not exist.
+	InsertSubscription(ctx context.Context, sub *models.WebhookSubscription) error
+	GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+
+	// EnqueueDeliveries creates a pending delivery of event for every matching
+	// subscription. Enqueuing the same event twice is a no-op.
+	EnqueueDeliveries(ctx context.Context, event models.Event) (int, error)
+
+	// ClaimDueDeliveries returns up to limit pending deliveries whose next attempt
+	// is due and postpones them by lease so other workers skip them meanwhile.
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error)
+	MarkDeliverySucceeded(ctx context.Context, id uuid.UUID, statusCode int) error
+	// MarkDeliveryFailed records a failed attempt. The delivery is retried at
+	// nextAttemptAt, or moved to the dead-letter list when dead is set.
+	MarkDeliveryFailed(ctx context.Context, id uuid.UUID, statusCode int, lastErr string, nextAttemptAt time.Time, dead bool) error
+
+	// Dead-letter operations. RedeliverDelivery returns ErrNotFound unless the
+	// delivery is dead.
+	GetDeadDeliveries(ctx context.Context, subscriptionID uuid.UUID) ([]models.WebhookDelivery, error)
+	RedeliverDelivery(ctx context.Context, id uuid.UUID) error
+}
+
+func CreateWebhookRepo(db Database) (WebhookRepository, error) {
+	const op = "repository.webhook_repo.CreateWebhookRepo"
+
+	repo, ok := db.(WebhookRepository)
+	if !ok {
+		return nil, fmt.Errorf("%s: %T does not implement WebhookRepository", op, db)
+	}
+
+	return repo, nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository/memory"
+	"pvz-service/internal/repository/postgres"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWebhookRepository implements WebhookRepository for testing
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) InsertSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	args := m.Called(ctx, sub)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) EnqueueDeliveries(ctx context.Context, event models.Event) (int, error) {
+	args := m.Called(ctx, event)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	args := m.Called(ctx, limit, lease)
+	return args.Get(0).([]models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) MarkDeliverySucceeded(ctx context.Context, id uuid.UUID, statusCode int) error {
+	args := m.Called(ctx, id, statusCode)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) MarkDeliveryFailed(ctx context.Context, id uuid.UUID, statusCode int, lastErr string, nextAttemptAt time.Time, dead bool) error {
+	args := m.Called(ctx, id, statusCode, lastErr, nextAttemptAt, dead)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetDeadDeliveries(ctx context.Context, subscriptionID uuid.UUID) ([]models.WebhookDelivery, error) {
+	args := m.Called(ctx, subscriptionID)
+	return args.Get(0).([]models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) RedeliverDelivery(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestCreateWebhookRepo(t *testing.T) {
+	tests := []struct {
+		name        string
+		db          Database
+		expectError bool
+	}{
+		{
+			name: "Success with postgres",
+			db:   new(postgres.Postgres),
+		},
+		{
+			name: "Success with memory",
+			db:   memory.New(),
+		},
+		{
+			name: "Success with sqlite",
+			db:   openSQLite(t),
+		},
+		{
+			name:        "Not a webhook repository",
+			db:          stubDatabase{},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := CreateWebhookRepo(tt.db)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, repo)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, repo)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package seed
+
+import "pvz-service/internal/models"
+
+// city is a city PVZs are opened in, with the point their locations are
+// scattered around
+type city struct {
+	name   string
+	center models.GeoPoint
+}
+
+// cities are taken in order. They are the cities allowed by the migrations
+// and the API, the seed does not add new ones.
+var cities = []city{
+	{"Москва", models.GeoPoint{Latitude: 55.7558, Longitude: 37.6173}},
+	{"Санкт-Петербург", models.GeoPoint{Latitude: 59.9343, Longitude: 30.3351}},
+	{"Казань", models.GeoPoint{Latitude: 55.7963, Longitude: 49.1088}},
+}
+
+var streets = []string{
+	"ул. Ленина", "ул. Мира", "ул. Гагарина", "ул. Советская", "ул. Садовая",
+	"пр. Победы", "ул. Пушкина", "ул. Молодёжная", "ул. Школьная", "ул. Лесная",
+	"ул. Центральная", "ул. Набережная", "пр. Строителей", "ул. Заводская", "ул. Полевая",
+}
+
+// hours are the daily opening hours of a PVZ. Deliveries arrive between from
+// and to o'clock.
+type hours struct {
+	from, to int
+}
+
+var openingHours = []hours{
+	{9, 21},
+	{8, 22},
+	{10, 20},
+}
+
+// productType is a product type seeded by the migrations and the share of
+// the deliveries it takes
+type productType struct {
+	name   string
+	weight float64
+}
+
+var productTypes = []productType{
+	{"одежда", 0.5},
+	{"электроника", 0.3},
+	{"обувь", 0.2},
+}
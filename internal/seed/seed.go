@@ -0,0 +1,434 @@
+// Package seed fills a database with generated cities, PVZs, users,
+// receptions and products for load tests and demos. The data goes through
+// the repository layer, so it satisfies the same rules as data written by
+// the service, and is the same for the same options.
+package seed
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Options size the generated data. Receptions and products are averages,
+// the actual numbers vary around them.
+type Options struct {
+	// Seed selects the data set, equal options give equal data
+	Seed int64
+
+	Cities               int
+	PVZs                 int
+	Users                int
+	ReceptionsPerPVZ     int
+	ProductsPerReception int
+
+	// Months of history before End. PVZs open over this span and receive
+	// deliveries from their registration on.
+	Months int
+	End    time.Time
+
+	// Password of every generated user
+	Password string
+}
+
+func (o Options) validate() error {
+	switch {
+	case o.Cities < 1 || o.Cities > len(cities):
+		return fmt.Errorf("cities must be between 1 and %d", len(cities))
+	case o.PVZs < 0 || o.Users < 0 || o.ReceptionsPerPVZ < 0 || o.ProductsPerReception < 0:
+		return errors.New("counts must not be negative")
+	case o.Months < 1:
+		return errors.New("months must be positive")
+	case o.End.IsZero():
+		return errors.New("end is not set")
+	case o.Users > 0 && o.Password == "":
+		return errors.New("password is not set")
+	}
+	return nil
+}
+
+// Summary counts what a run has written
+type Summary struct {
+	Cities     int
+	PVZs       int
+	Users      int
+	Receptions int
+	Products   int
+	Issued     int
+	Returned   int
+}
+
+// Seeder writes generated data to the repositories
+type Seeder struct {
+	pvzRepo  repository.PVZRepository
+	authRepo repository.AuthRepository
+	log      *slog.Logger
+}
+
+func New(pvzRepo repository.PVZRepository, authRepo repository.AuthRepository, log *slog.Logger) *Seeder {
+	return &Seeder{
+		pvzRepo:  pvzRepo,
+		authRepo: authRepo,
+		log:      log.With(slog.String("component", "seed")),
+	}
+}
+
+// run is the state of one Run. Every random choice is drawn from src in a
+// fixed order, IDs included, which makes the data deterministic.
+type run struct {
+	*Seeder
+	opts    Options
+	src     *rand.ChaCha8
+	rnd     *rand.Rand
+	typeIDs []int
+	summary Summary
+}
+
+// Run generates the data described by opts. The database should not hold
+// data from an earlier run with the same seed, whose IDs would collide.
+func (s *Seeder) Run(ctx context.Context, opts Options) (*Summary, error) {
+	const op = "seed.Run"
+
+	if err := opts.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var key [32]byte
+	binary.LittleEndian.PutUint64(key[:], uint64(opts.Seed))
+	src := rand.NewChaCha8(key)
+
+	r := &run{Seeder: s, opts: opts, src: src, rnd: rand.New(src)}
+	if err := r.run(ctx); err != nil {
+		return &r.summary, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &r.summary, nil
+}
+
+func (r *run) run(ctx context.Context) error {
+	for _, t := range productTypes {
+		typeID, err := r.pvzRepo.GetProductTypeID(ctx, t.name)
+		if err != nil {
+			return fmt.Errorf("product type %s: %w", t.name, err)
+		}
+		r.typeIDs = append(r.typeIDs, typeID)
+	}
+
+	cityIDs := make([]int, r.opts.Cities)
+	for i, c := range cities[:r.opts.Cities] {
+		cityID, err := r.pvzRepo.GetCityID(ctx, c.name)
+		if err != nil {
+			return fmt.Errorf("city %s: %w", c.name, err)
+		}
+		cityIDs[i] = cityID
+		r.summary.Cities++
+	}
+
+	if err := r.users(ctx); err != nil {
+		return err
+	}
+
+	for i := range r.opts.PVZs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Larger cities have more PVZs: the k-th city gets a share
+		// proportional to 1/k
+		c := r.pickWeighted(r.opts.Cities, func(k int) float64 { return 1 / float64(k+1) })
+		if err := r.pvz(ctx, cityIDs[c], cities[c]); err != nil {
+			return err
+		}
+
+		if (i+1)%10 == 0 {
+			r.log.Info("seeding", slog.Int("pvzs", i+1), slog.Int("products", r.summary.Products))
+		}
+	}
+
+	return nil
+}
+
+// users creates a moderator for every ten employees
+func (r *run) users(ctx context.Context) error {
+	for i := range r.opts.Users {
+		role, email := models.UserRoleEmployee, fmt.Sprintf("employee%d@seed.pvz", i+1)
+		if i%10 == 0 {
+			role, email = models.UserRoleModerator, fmt.Sprintf("moderator%d@seed.pvz", i/10+1)
+		}
+
+		if _, err := r.authRepo.CreateUser(ctx, email, r.opts.Password, role); err != nil {
+			return fmt.Errorf("user %s: %w", email, err)
+		}
+		r.summary.Users++
+	}
+	return nil
+}
+
+// pvz opens a PVZ in the city and generates its deliveries
+func (r *run) pvz(ctx context.Context, cityID int, c city) error {
+	start := r.opts.End.AddDate(0, -r.opts.Months, 0)
+	span := r.opts.End.Sub(start)
+
+	// The network grows, so later registrations are more likely. The last
+	// tenth of the span is left for the newest PVZs to get deliveries.
+	registered := start.Add(time.Duration(float64(span) * 0.9 * math.Sqrt(r.rnd.Float64())))
+	h := openingHours[r.rnd.IntN(len(openingHours))]
+
+	pvz := &models.PVZ{
+		ID:               r.newID(),
+		RegistrationDate: registered,
+		CityID:           cityID,
+		CityName:         c.name,
+		Address:          fmt.Sprintf("%s, д. %d", streets[r.rnd.IntN(len(streets))], 1+r.rnd.IntN(150)),
+		Location: &models.GeoPoint{
+			Latitude:  c.center.Latitude + r.rnd.NormFloat64()*0.05,
+			Longitude: c.center.Longitude + r.rnd.NormFloat64()*0.08,
+		},
+		Status: models.PVZStatusActive,
+	}
+	if err := r.pvzRepo.InsertPVZ(ctx, pvz); err != nil {
+		return fmt.Errorf("pvz: %w", err)
+	}
+	if err := r.pvzRepo.SavePVZSchedule(ctx, h.schedule(pvz.ID, registered)); err != nil {
+		return fmt.Errorf("pvz schedule: %w", err)
+	}
+	r.summary.PVZs++
+
+	// A PVZ open for part of the span gets the same part of the receptions
+	active := r.opts.End.Sub(registered)
+	count := int(math.Round(float64(r.opts.ReceptionsPerPVZ) * float64(active) / float64(span)))
+	if r.opts.ReceptionsPerPVZ > 0 {
+		count = max(count, 1)
+	}
+
+	days := make([]time.Time, count)
+	for i := range days {
+		days[i] = r.deliveryTime(registered, active, h)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	var free time.Time
+	for i, at := range days {
+		// The next delivery waits for the previous reception to close
+		if at.Before(free) {
+			at = free.Add(time.Duration(5+r.rnd.IntN(55)) * time.Minute)
+		}
+		if !at.Before(r.opts.End) {
+			break
+		}
+
+		// The latest delivery of the last day is still being received
+		open := i == len(days)-1 && r.opts.End.Sub(at) < 24*time.Hour
+
+		closedAt, err := r.reception(ctx, pvz.ID, at, open)
+		if err != nil {
+			return err
+		}
+		free = closedAt
+	}
+
+	return nil
+}
+
+// deliveryTime picks a delivery moment within the active span of a PVZ
+// during its opening hours, mostly in the morning and less often on Sundays
+func (r *run) deliveryTime(from time.Time, span time.Duration, h hours) time.Time {
+	day := from.Add(time.Duration(r.rnd.Int64N(int64(span)))).Truncate(24 * time.Hour)
+	if day.Weekday() == time.Sunday && r.rnd.IntN(2) == 0 {
+		day = day.AddDate(0, 0, 1)
+	}
+
+	hour := h.from + int(math.Abs(r.rnd.NormFloat64())*3)
+	hour = min(hour, h.to-1)
+
+	at := day.Add(time.Duration(hour)*time.Hour + time.Duration(r.rnd.IntN(60))*time.Minute)
+	if at.Before(from) {
+		return from
+	}
+	return at
+}
+
+// schedule opens the PVZ daily for the hours. Deliveries are laid out in
+// UTC, so the schedule is too.
+func (h hours) schedule(pvzID uuid.UUID, updatedAt time.Time) *models.PVZSchedule {
+	interval := models.ScheduleInterval{
+		Opens:  fmt.Sprintf("%02d:00", h.from),
+		Closes: fmt.Sprintf("%02d:00", h.to),
+	}
+	weekdays := []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+	schedule := &models.PVZSchedule{PVZID: pvzID, Timezone: "UTC", UpdatedAt: updatedAt}
+	for _, weekday := range weekdays {
+		schedule.Weekly = append(schedule.Weekly, models.ScheduleDay{
+			Weekday:   weekday,
+			Intervals: []models.ScheduleInterval{interval},
+		})
+	}
+	return schedule
+}
+
+// reception accepts one delivery of products starting at at and closes it
+// unless open is set. It returns the moment the reception closed.
+func (r *run) reception(ctx context.Context, pvzID uuid.UUID, at time.Time, open bool) (time.Time, error) {
+	reception := &models.Reception{
+		ID:       r.newID(),
+		DateTime: at,
+		PVZID:    pvzID,
+		Status:   models.ReceptionStatusInProgress,
+	}
+	if err := r.pvzRepo.InsertReception(ctx, reception); err != nil {
+		return time.Time{}, fmt.Errorf("reception: %w", err)
+	}
+	r.summary.Receptions++
+
+	count := 0
+	if r.opts.ProductsPerReception > 0 {
+		count = 1 + r.rnd.IntN(2*r.opts.ProductsPerReception-1)
+	}
+
+	version := 1
+	products := make([]*models.Product, 0, count)
+	for range count {
+		// Scanning a product takes from 20 seconds to 3 minutes
+		at = at.Add(time.Duration(20+r.rnd.IntN(160)) * time.Second)
+		if !at.Before(r.opts.End) {
+			break
+		}
+
+		product := &models.Product{
+			ID:          r.newID(),
+			DateTime:    at,
+			TypeID:      r.typeIDs[r.pickWeighted(len(productTypes), func(k int) float64 { return productTypes[k].weight })],
+			ReceptionID: reception.ID,
+			Status:      models.ProductStatusAccepted,
+			Barcode:     r.barcode(),
+			Condition:   models.ProductConditionOK,
+		}
+		if r.rnd.IntN(50) == 0 {
+			product.Condition = models.ProductConditionDamaged
+		}
+
+		var err error
+		version, err = r.pvzRepo.InsertProduct(ctx, product, version)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("product: %w", err)
+		}
+		products = append(products, product)
+		r.summary.Products++
+	}
+
+	if open {
+		return at, nil
+	}
+
+	if _, err := r.pvzRepo.UpdateReceptionStatus(ctx, reception.ID, models.ReceptionStatusClose, version); err != nil {
+		return time.Time{}, fmt.Errorf("close reception: %w", err)
+	}
+
+	for _, product := range products {
+		if err := r.issue(ctx, product.ID, at); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	return at, nil
+}
+
+// issue hands most stored products to their recipients within days of the
+// delivery. A few of them are returned later.
+func (r *run) issue(ctx context.Context, productID uuid.UUID, storedAt time.Time) error {
+	if r.rnd.Float64() >= 0.8 {
+		return nil
+	}
+
+	issuedAt := storedAt.Add(time.Duration(r.rnd.ExpFloat64() * float64(3*24*time.Hour)))
+	if !issuedAt.Before(r.opts.End) {
+		return nil
+	}
+
+	err := r.pvzRepo.ChangeProductStatus(ctx, &models.ProductStatusChange{
+		ProductID: productID,
+		From:      models.ProductStatusStored,
+		To:        models.ProductStatusIssued,
+		ChangedAt: issuedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("issue product: %w", err)
+	}
+	r.summary.Issued++
+
+	if r.rnd.IntN(30) != 0 {
+		return nil
+	}
+
+	returnedAt := issuedAt.Add(time.Duration(1+r.rnd.IntN(5)) * 24 * time.Hour)
+	if !returnedAt.Before(r.opts.End) {
+		return nil
+	}
+
+	err = r.pvzRepo.ChangeProductStatus(ctx, &models.ProductStatusChange{
+		ProductID: productID,
+		From:      models.ProductStatusIssued,
+		To:        models.ProductStatusReturned,
+		Comment:   "возврат покупателем",
+		ChangedAt: returnedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("return product: %w", err)
+	}
+	r.summary.Returned++
+
+	return nil
+}
+
+func (r *run) newID() uuid.UUID {
+	return uuid.Must(uuid.NewRandomFromReader(r.src))
+}
+
+// barcode returns an EAN-13 with the Russian 460 prefix
+func (r *run) barcode() string {
+	digits := make([]byte, 13)
+	copy(digits, "460")
+	for i := 3; i < 12; i++ {
+		digits[i] = byte('0' + r.rnd.IntN(10))
+	}
+
+	sum := 0
+	for i := range 12 {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(digits[i]-'0') * weight
+	}
+	digits[12] = byte('0' + (10-sum%10)%10)
+
+	return string(digits)
+}
+
+// pickWeighted returns an index below n drawn with the given weights
+func (r *run) pickWeighted(n int, weight func(k int) float64) int {
+	total := 0.0
+	for k := range n {
+		total += weight(k)
+	}
+
+	x := r.rnd.Float64() * total
+	for k := range n {
+		x -= weight(k)
+		if x < 0 {
+			return k
+		}
+	}
+	return n - 1
+}
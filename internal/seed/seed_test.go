@@ -0,0 +1,137 @@
+package seed
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testOptions = Options{
+	Seed:                 42,
+	Cities:               3,
+	PVZs:                 5,
+	Users:                2,
+	ReceptionsPerPVZ:     6,
+	ProductsPerReception: 4,
+	Months:               3,
+	End:                  time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+	Password:             "password",
+}
+
+// snapshot is everything a run wrote that the listing can see
+type snapshot struct {
+	pvzs       []models.PVZ
+	receptions []models.Reception
+	products   []models.Product
+}
+
+func seed(t *testing.T, opts Options) (*Summary, snapshot) {
+	t.Helper()
+
+	repo := memory.New()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	summary, err := New(repo, repo, log).Run(context.Background(), opts)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	from, to := opts.End.AddDate(0, -opts.Months, 0), opts.End
+
+	pvzs, err := repo.GetPVZsWithNoFilter(ctx)
+	require.NoError(t, err)
+
+	ids := make([]uuid.UUID, len(pvzs))
+	for i, pvz := range pvzs {
+		ids[i] = pvz.ID
+	}
+	receptions, err := repo.GetReceptionsForPVZs(ctx, ids, from, to)
+	require.NoError(t, err)
+
+	ids = make([]uuid.UUID, len(receptions))
+	for i, reception := range receptions {
+		ids[i] = reception.ID
+	}
+	products, err := repo.GetProductsForReceptions(ctx, ids, from)
+	require.NoError(t, err)
+
+	return summary, snapshot{pvzs, receptions, products}
+}
+
+func TestRun(t *testing.T) {
+	summary, data := seed(t, testOptions)
+
+	assert.Equal(t, 3, summary.Cities)
+	assert.Equal(t, 5, summary.PVZs)
+	assert.Equal(t, 2, summary.Users)
+	assert.Len(t, data.pvzs, summary.PVZs)
+	assert.Len(t, data.receptions, summary.Receptions)
+	assert.Len(t, data.products, summary.Products)
+	assert.NotZero(t, summary.Issued)
+
+	start := testOptions.End.AddDate(0, -testOptions.Months, 0)
+	for _, pvz := range data.pvzs {
+		assert.False(t, pvz.RegistrationDate.Before(start))
+		assert.True(t, pvz.RegistrationDate.Before(testOptions.End))
+	}
+	for _, reception := range data.receptions {
+		assert.True(t, reception.DateTime.Before(testOptions.End))
+	}
+	for _, product := range data.products {
+		assert.Len(t, product.Barcode, 13)
+	}
+}
+
+func TestRunIsDeterministic(t *testing.T) {
+	first, firstData := seed(t, testOptions)
+	second, secondData := seed(t, testOptions)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, firstData, secondData)
+
+	other := testOptions
+	other.Seed++
+	_, otherData := seed(t, other)
+	assert.NotEqual(t, firstData.pvzs, otherData.pvzs)
+}
+
+func TestOptionsValidate(t *testing.T) {
+	assert.NoError(t, testOptions.validate())
+
+	tooManyCities := testOptions
+	tooManyCities.Cities = len(cities) + 1
+	assert.Error(t, tooManyCities.validate())
+
+	noEnd := testOptions
+	noEnd.End = time.Time{}
+	assert.Error(t, noEnd.validate())
+}
+
+func TestBarcode(t *testing.T) {
+	r := &run{rnd: rand.New(rand.NewPCG(1, 2))}
+
+	for range 100 {
+		code := r.barcode()
+		require.Len(t, code, 13)
+		assert.Equal(t, "460", code[:3])
+
+		// The check digit makes the weighted sum of all digits a multiple of 10
+		sum := 0
+		for i, digit := range code {
+			weight := 1
+			if i%2 == 1 {
+				weight = 3
+			}
+			sum += int(digit-'0') * weight
+		}
+		assert.Zero(t, sum%10, code)
+	}
+}
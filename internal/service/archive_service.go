@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/logger/sl"
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/storage"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ArchiveService struct {
+	repo   repository.PVZRepository
+	photos storage.Storage
+	log    *slog.Logger
+}
+
+func NewArchiveService(repo repository.PVZRepository, photos storage.Storage, log *slog.Logger) *ArchiveService {
+	return &ArchiveService{repo: repo, photos: photos, log: log}
+}
+
+type ArchiveServiceInterface interface {
+	ArchiveReceptions(ctx context.Context, retentionMonths, batchSize int) (int, int, error)
+	GetArchivedReceptions(ctx context.Context, pvzID uuid.UUID, from, to time.Time, page, limit int) ([]models.ReceptionInfo, error)
+}
+
+// ArchiveReceptions moves the closed receptions started more than
+// retentionMonths ago to the archive in batches of batchSize, until no
+// archivable reception is left. It returns how many receptions and products
+// were moved, including those of the batches done before an error.
+func (s *ArchiveService) ArchiveReceptions(ctx context.Context, retentionMonths, batchSize int) (int, int, error) {
+	const op = "service.archive_service.ArchiveReceptions"
+
+	now := time.Now()
+	closedBefore := now.AddDate(0, -retentionMonths, 0)
+
+	receptions, products := 0, 0
+	for {
+		movedReceptions, movedProducts, photoKeys, err := s.repo.ArchiveReceptions(ctx, closedBefore, now, batchSize)
+		if err != nil {
+			s.log.Error(fmt.Sprintf("%s: failed to archive receptions", op), sl.Err(err))
+			return receptions, products, fmt.Errorf("failed to archive receptions: %w", err)
+		}
+		for _, key := range photoKeys {
+			deletePhoto(ctx, s.photos, s.log, op, key)
+		}
+
+		receptions += movedReceptions
+		products += movedProducts
+		if movedReceptions == 0 || movedReceptions < batchSize {
+			break
+		}
+	}
+
+	return receptions, products, nil
+}
+
+// GetArchivedReceptions lists the archived receptions of the PVZ started
+// between from and to, newest first, with their products
+func (s *ArchiveService) GetArchivedReceptions(ctx context.Context, pvzID uuid.UUID, from, to time.Time, page, limit int) ([]models.ReceptionInfo, error) {
+	const op = "service.archive_service.GetArchivedReceptions"
+
+	if _, err := s.repo.CheckPVZ(ctx, pvzID); err != nil {
+		if err == e.ErrNotFound() {
+			s.log.Info(fmt.Sprintf("%s: pvz not found", op), "pvzID", pvzID)
+			return nil, e.ErrNotFound()
+		}
+		s.log.Error(fmt.Sprintf("%s: failed to check PVZ", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to check PVZ: %w", err)
+	}
+
+	offset := (page - 1) * limit
+
+	receptions, err := s.repo.GetArchivedReceptions(ctx, pvzID, from, to, limit, offset)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to get archived receptions", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to get archived receptions: %w", err)
+	}
+
+	receptionIDs := make([]uuid.UUID, len(receptions))
+	for i, rec := range receptions {
+		receptionIDs[i] = rec.ID
+	}
+
+	products, err := s.repo.GetArchivedProducts(ctx, receptionIDs)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to get archived products", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to get archived products: %w", err)
+	}
+
+	productsByReception := make(map[uuid.UUID][]models.Product)
+	for _, prod := range products {
+		productsByReception[prod.ReceptionID] = append(productsByReception[prod.ReceptionID], prod)
+	}
+
+	archived := make([]models.ReceptionInfo, len(receptions))
+	for i, rec := range receptions {
+		archived[i] = models.ReceptionInfo{
+			Reception: rec,
+			Products:  productsByReception[rec.ID],
+		}
+		if archived[i].Products == nil {
+			archived[i].Products = []models.Product{}
+		}
+	}
+	return archived, nil
+}
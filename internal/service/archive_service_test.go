@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"pvz-service/internal/storage"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestArchiveService_ArchiveReceptions(t *testing.T) {
+	closedBefore := mock.MatchedBy(func(before time.Time) bool {
+		cutoff := time.Now().AddDate(0, -12, 0)
+		return !before.After(cutoff) && cutoff.Sub(before) < time.Minute
+	})
+
+	t.Run("Archives in batches until a short one", func(t *testing.T) {
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("ArchiveReceptions", mock.Anything, closedBefore, mock.Anything, 2).Return(2, 5, nil, nil).Twice()
+		mockRepo.On("ArchiveReceptions", mock.Anything, closedBefore, mock.Anything, 2).Return(1, 0, nil, nil).Once()
+
+		service := NewArchiveService(mockRepo, nil, slog.Default())
+		receptions, products, err := service.ArchiveReceptions(context.Background(), 12, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, receptions)
+		assert.Equal(t, 10, products)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Removes the photos of archived products", func(t *testing.T) {
+		photos := storage.NewLocalStorage(t.TempDir())
+		ctx := context.Background()
+		assert.NoError(t, photos.Put(ctx, "products/archived.jpg", []byte("jpeg"), "image/jpeg"))
+
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("ArchiveReceptions", mock.Anything, closedBefore, mock.Anything, 2).
+			Return(1, 1, []string{"products/archived.jpg"}, nil).Once()
+
+		service := NewArchiveService(mockRepo, photos, slog.Default())
+		_, _, err := service.ArchiveReceptions(ctx, 12, 2)
+		assert.NoError(t, err)
+
+		_, _, err = photos.Get(ctx, "products/archived.jpg")
+		assert.Equal(t, e.ErrNotFound(), err)
+	})
+
+	t.Run("Reports the batches done before an error", func(t *testing.T) {
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("ArchiveReceptions", mock.Anything, closedBefore, mock.Anything, 2).Return(2, 3, nil, nil).Once()
+		mockRepo.On("ArchiveReceptions", mock.Anything, closedBefore, mock.Anything, 2).Return(0, 0, nil, errors.New("db error")).Once()
+
+		service := NewArchiveService(mockRepo, nil, slog.Default())
+		receptions, products, err := service.ArchiveReceptions(context.Background(), 12, 2)
+		assert.EqualError(t, err, "failed to archive receptions: db error")
+		assert.Equal(t, 2, receptions)
+		assert.Equal(t, 3, products)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestArchiveService_GetArchivedReceptions(t *testing.T) {
+	pvzID := uuid.New()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	withProducts := models.Reception{ID: uuid.New(), DateTime: from.Add(48 * time.Hour), PVZID: pvzID, Status: models.ReceptionStatusClose}
+	empty := models.Reception{ID: uuid.New(), DateTime: from.Add(24 * time.Hour), PVZID: pvzID, Status: models.ReceptionStatusClose}
+	product := models.Product{ID: uuid.New(), ReceptionID: withProducts.ID, Status: models.ProductStatusIssued}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("CheckPVZ", mock.Anything, pvzID).Return(true, nil)
+		mockRepo.On("GetArchivedReceptions", mock.Anything, pvzID, from, to, 10, 10).
+			Return([]models.Reception{withProducts, empty}, nil)
+		mockRepo.On("GetArchivedProducts", mock.Anything, []uuid.UUID{withProducts.ID, empty.ID}).
+			Return([]models.Product{product}, nil)
+
+		service := NewArchiveService(mockRepo, nil, slog.Default())
+		result, err := service.GetArchivedReceptions(context.Background(), pvzID, from, to, 2, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []models.ReceptionInfo{
+			{Reception: withProducts, Products: []models.Product{product}},
+			{Reception: empty, Products: []models.Product{}},
+		}, result)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("PVZ not found", func(t *testing.T) {
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("CheckPVZ", mock.Anything, pvzID).Return(false, e.ErrNotFound())
+
+		service := NewArchiveService(mockRepo, nil, slog.Default())
+		_, err := service.GetArchivedReceptions(context.Background(), pvzID, from, to, 1, 10)
+		assert.Equal(t, e.ErrNotFound(), err)
+		mockRepo.AssertNotCalled(t, "GetArchivedReceptions", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
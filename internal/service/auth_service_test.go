@@ -20,10 +20,6 @@ type MockAuthRepository struct {
 	mock.Mock
 }
 
-func (m *MockAuthRepository) CloseConnection() {
-	m.Called()
-}
-
 func (m *MockAuthRepository) CreateUser(ctx context.Context, email, password string, role models.UserRole) (*models.User, error) {
 	args := m.Called(ctx, email, password, role)
 	return args.Get(0).(*models.User), args.Error(1)
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"pvz-service/internal/config"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/logger/sl"
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository"
+	"time"
+)
+
+type IdempotencyService struct {
+	repo  repository.IdempotencyRepository
+	log   *slog.Logger
+	ttl   time.Duration
+	lease time.Duration
+}
+
+func NewIdempotencyService(repo repository.IdempotencyRepository, cfg *config.Config, log *slog.Logger) *IdempotencyService {
+	return &IdempotencyService{
+		repo:  repo,
+		log:   log,
+		ttl:   cfg.Idempotency.TTL,
+		lease: cfg.Idempotency.Lease,
+	}
+}
+
+type IdempotencyServiceInterface interface {
+	Begin(ctx context.Context, key, fingerprint string) (*models.IdempotencyRecord, error)
+	Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error
+	Release(ctx context.Context, key string) error
+	PurgeExpired(ctx context.Context) (int, error)
+}
+
+// Begin reserves key for a request with the given fingerprint. It returns the
+// stored response to replay, or nil when the caller should process the request.
+func (s *IdempotencyService) Begin(ctx context.Context, key, fingerprint string) (*models.IdempotencyRecord, error) {
+	const op = "service.idempotency_service.Begin"
+
+	record, err := s.repo.ReserveIdempotencyKey(ctx, key, fingerprint, s.ttl, s.lease)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to reserve idempotency key", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	if record == nil {
+		return nil, nil
+	}
+
+	if record.Fingerprint != fingerprint {
+		s.log.Info(fmt.Sprintf("%s: idempotency key reused with different request", op), "key", key)
+		return nil, e.ErrIdempotencyKeyMismatch()
+	}
+	if !record.Completed() {
+		s.log.Info(fmt.Sprintf("%s: idempotency key is in progress", op), "key", key)
+		return nil, e.ErrIdempotencyKeyInProgress()
+	}
+
+	return record, nil
+}
+
+// Complete stores the response of a request started with Begin
+func (s *IdempotencyService) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	const op = "service.idempotency_service.Complete"
+
+	if err := s.repo.SaveIdempotencyResponse(ctx, key, statusCode, contentType, body); err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to save response", op), sl.Err(err))
+		return fmt.Errorf("failed to save response: %w", err)
+	}
+
+	return nil
+}
+
+// Release frees key so that the request may be retried, e.g. after a server error
+func (s *IdempotencyService) Release(ctx context.Context, key string) error {
+	const op = "service.idempotency_service.Release"
+
+	if err := s.repo.DeleteIdempotencyKey(ctx, key); err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to release idempotency key", op), sl.Err(err))
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+func (s *IdempotencyService) PurgeExpired(ctx context.Context) (int, error) {
+	const op = "service.idempotency_service.PurgeExpired"
+
+	deleted, err := s.repo.DeleteExpiredIdempotencyKeys(ctx)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to purge expired keys", op), sl.Err(err))
+		return 0, fmt.Errorf("failed to purge expired keys: %w", err)
+	}
+
+	return deleted, nil
+}
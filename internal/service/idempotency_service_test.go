@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"pvz-service/internal/config"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIdempotencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyRepository) ReserveIdempotencyKey(ctx context.Context, key, fingerprint string, ttl, lease time.Duration) (*models.IdempotencyRecord, error) {
+	args := m.Called(ctx, key, fingerprint, ttl, lease)
+	record, _ := args.Get(0).(*models.IdempotencyRecord)
+	return record, args.Error(1)
+}
+
+func (m *MockIdempotencyRepository) SaveIdempotencyResponse(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	args := m.Called(ctx, key, statusCode, contentType, body)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) DeleteExpiredIdempotencyKeys(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func newTestIdempotencyService(repo *MockIdempotencyRepository) *IdempotencyService {
+	cfg := &config.Config{Idempotency: config.Idempotency{TTL: time.Hour, Lease: time.Minute}}
+	return NewIdempotencyService(repo, cfg, slog.Default())
+}
+
+func TestIdempotencyService_Begin(t *testing.T) {
+	completed := &models.IdempotencyRecord{Key: "key", Fingerprint: "fp", StatusCode: 201, Body: []byte(`{}`)}
+
+	tests := []struct {
+		name         string
+		record       *models.IdempotencyRecord
+		repoErr      error
+		expectRecord *models.IdempotencyRecord
+		expectError  error
+	}{
+		{name: "Key reserved"},
+		{name: "Replay", record: completed, expectRecord: completed},
+		{
+			name:        "Different request",
+			record:      &models.IdempotencyRecord{Key: "key", Fingerprint: "other", StatusCode: 201},
+			expectError: e.ErrIdempotencyKeyMismatch(),
+		},
+		{
+			name:        "In progress",
+			record:      &models.IdempotencyRecord{Key: "key", Fingerprint: "fp"},
+			expectError: e.ErrIdempotencyKeyInProgress(),
+		},
+		{
+			name:        "Repository error",
+			repoErr:     errors.New("db error"),
+			expectError: errors.New("failed to reserve idempotency key: db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockIdempotencyRepository)
+			mockRepo.On("ReserveIdempotencyKey", mock.Anything, "key", "fp", time.Hour, time.Minute).Return(tt.record, tt.repoErr)
+
+			record, err := newTestIdempotencyService(mockRepo).Begin(context.Background(), "key", "fp")
+
+			if tt.expectError != nil {
+				assert.Equal(t, tt.expectError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectRecord, record)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIdempotencyService_CompleteAndRelease(t *testing.T) {
+	mockRepo := new(MockIdempotencyRepository)
+	mockRepo.On("SaveIdempotencyResponse", mock.Anything, "key", 201, "application/json", []byte(`{}`)).Return(nil)
+	mockRepo.On("DeleteIdempotencyKey", mock.Anything, "other").Return(errors.New("db error"))
+
+	service := newTestIdempotencyService(mockRepo)
+
+	assert.NoError(t, service.Complete(context.Background(), "key", 201, "application/json", []byte(`{}`)))
+	assert.EqualError(t, service.Release(context.Background(), "other"), "failed to release idempotency key: db error")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIdempotencyService_PurgeExpired(t *testing.T) {
+	mockRepo := new(MockIdempotencyRepository)
+	mockRepo.On("DeleteExpiredIdempotencyKeys", mock.Anything).Return(3, nil)
+
+	deleted, err := newTestIdempotencyService(mockRepo).PurgeExpired(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, deleted)
+}
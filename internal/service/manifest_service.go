@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/logger/sl"
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ManifestService struct {
+	repo repository.PVZRepository
+	log  *slog.Logger
+}
+
+func NewManifestService(repo repository.PVZRepository, log *slog.Logger) *ManifestService {
+	return &ManifestService{repo: repo, log: log}
+}
+
+type ManifestServiceInterface interface {
+	CreateManifest(ctx context.Context, pvzID uuid.UUID, supplier string, items []models.ManifestItem) (*models.Manifest, error)
+	GetManifest(ctx context.Context, manifestID uuid.UUID) (*models.Manifest, error)
+	GetPVZManifests(ctx context.Context, pvzID uuid.UUID, status models.ManifestStatus) ([]models.Manifest, error)
+}
+
+// CreateManifest saves the list of items a supplier is going to deliver to a
+// PVZ. Items of the same type share one type lookup.
+func (s *ManifestService) CreateManifest(ctx context.Context, pvzID uuid.UUID, supplier string, items []models.ManifestItem) (*models.Manifest, error) {
+	const op = "service.manifest_service.CreateManifest"
+
+	if _, err := s.repo.CheckPVZ(ctx, pvzID); err != nil {
+		if err == e.ErrNotFound() {
+			s.log.Info(fmt.Sprintf("%s: pvz not found", op), "pvzID", pvzID)
+			return nil, e.ErrNotFound()
+		}
+		s.log.Error(fmt.Sprintf("%s: failed to check PVZ", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to check PVZ: %w", err)
+	}
+
+	typeIDs := map[string]int{}
+	for i := range items {
+		typeID, ok := typeIDs[items[i].TypeName]
+		if !ok {
+			var err error
+			typeID, err = s.repo.GetProductTypeID(ctx, items[i].TypeName)
+			if err == e.ErrProductTypeNotAllowed() {
+				s.log.Info(fmt.Sprintf("%s: product type not allowed", op), "type", items[i].TypeName)
+				return nil, e.ErrProductTypeNotAllowed()
+			}
+			if err != nil {
+				s.log.Error(fmt.Sprintf("%s: failed to get product type", op), sl.Err(err))
+				return nil, fmt.Errorf("failed to get product type: %w", err)
+			}
+			typeIDs[items[i].TypeName] = typeID
+		}
+		items[i].TypeID = typeID
+	}
+
+	manifest := &models.Manifest{
+		ID:        uuid.New(),
+		PVZID:     pvzID,
+		Supplier:  supplier,
+		Status:    models.ManifestStatusPending,
+		Items:     items,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.repo.CreateManifest(ctx, manifest); err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to create manifest", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to create manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+func (s *ManifestService) GetManifest(ctx context.Context, manifestID uuid.UUID) (*models.Manifest, error) {
+	const op = "service.manifest_service.GetManifest"
+
+	manifest, err := s.repo.GetManifest(ctx, manifestID)
+	if err == e.ErrNotFound() {
+		s.log.Info(fmt.Sprintf("%s: manifest not found", op), "manifestID", manifestID)
+		return nil, e.ErrNotFound()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to get manifest", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to get manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// GetPVZManifests returns the manifests of a PVZ, newest first. An empty
+// status matches any.
+func (s *ManifestService) GetPVZManifests(ctx context.Context, pvzID uuid.UUID, status models.ManifestStatus) ([]models.Manifest, error) {
+	const op = "service.manifest_service.GetPVZManifests"
+
+	if _, err := s.repo.CheckPVZ(ctx, pvzID); err != nil {
+		if err == e.ErrNotFound() {
+			s.log.Info(fmt.Sprintf("%s: pvz not found", op), "pvzID", pvzID)
+			return nil, e.ErrNotFound()
+		}
+		s.log.Error(fmt.Sprintf("%s: failed to check PVZ", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to check PVZ: %w", err)
+	}
+
+	manifests, err := s.repo.GetPVZManifests(ctx, pvzID, status)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to get manifests", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to get manifests: %w", err)
+	}
+
+	return manifests, nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestManifestService_CreateManifest(t *testing.T) {
+	pvzID := uuid.New()
+
+	tests := []struct {
+		name        string
+		setupMock   func(m *MockPVZRepository)
+		expectError error
+	}{
+		{
+			name: "Success",
+			setupMock: func(m *MockPVZRepository) {
+				m.On("CheckPVZ", mock.Anything, pvzID).Return(true, nil)
+				m.On("GetProductTypeID", mock.Anything, "электроника").Return(1, nil).Once()
+				m.On("GetProductTypeID", mock.Anything, "обувь").Return(3, nil).Once()
+				m.On("CreateManifest", mock.Anything, mock.MatchedBy(func(mf *models.Manifest) bool {
+					return mf.PVZID == pvzID && mf.Status == models.ManifestStatusPending &&
+						mf.Items[0].TypeID == 1 && mf.Items[1].TypeID == 3 && mf.Items[2].TypeID == 1
+				})).Return(nil)
+			},
+		},
+		{
+			name: "PVZ not found",
+			setupMock: func(m *MockPVZRepository) {
+				m.On("CheckPVZ", mock.Anything, pvzID).Return(false, e.ErrNotFound())
+			},
+			expectError: e.ErrNotFound(),
+		},
+		{
+			name: "Unknown product type",
+			setupMock: func(m *MockPVZRepository) {
+				m.On("CheckPVZ", mock.Anything, pvzID).Return(true, nil)
+				m.On("GetProductTypeID", mock.Anything, "электроника").Return(0, e.ErrProductTypeNotAllowed())
+			},
+			expectError: e.ErrProductTypeNotAllowed(),
+		},
+		{
+			name: "Insert error",
+			setupMock: func(m *MockPVZRepository) {
+				m.On("CheckPVZ", mock.Anything, pvzID).Return(true, nil)
+				m.On("GetProductTypeID", mock.Anything, mock.Anything).Return(1, nil)
+				m.On("CreateManifest", mock.Anything, mock.Anything).Return(errors.New("insert error"))
+			},
+			expectError: errors.New("failed to create manifest: insert error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockPVZRepository)
+			tt.setupMock(mockRepo)
+
+			items := []models.ManifestItem{
+				{Barcode: "A1", TypeName: "электроника"},
+				{Barcode: "B2", TypeName: "обувь"},
+				{Barcode: "C3", TypeName: "электроника"},
+			}
+
+			service := NewManifestService(mockRepo, slog.Default())
+			manifest, err := service.CreateManifest(context.Background(), pvzID, "ООО Поставщик", items)
+			if tt.expectError != nil {
+				assert.Equal(t, tt.expectError.Error(), err.Error())
+				assert.Nil(t, manifest)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, models.ManifestStatusPending, manifest.Status)
+				assert.Len(t, manifest.Items, 3)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/eventbus"
+	"pvz-service/internal/logger/sl"
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+type ProductConditionService struct {
+	repo   repository.PVZRepository
+	photos storage.Storage
+	bus    *eventbus.Bus
+	log    *slog.Logger
+}
+
+func NewProductConditionService(repo repository.PVZRepository, photos storage.Storage, bus *eventbus.Bus, log *slog.Logger) *ProductConditionService {
+	return &ProductConditionService{repo: repo, photos: photos, bus: bus, log: log}
+}
+
+type ProductConditionServiceInterface interface {
+	SetProductCondition(ctx context.Context, productID uuid.UUID, condition models.ProductCondition, note string) (*models.Product, error)
+	UploadProductPhoto(ctx context.Context, productID uuid.UUID, contentType string, data []byte) (*models.Product, error)
+	GetProductPhoto(ctx context.Context, productID uuid.UUID) (io.ReadCloser, string, error)
+}
+
+// SetProductCondition marks a product of an open reception as damaged or a
+// wrong item, or clears the mark
+func (s *ProductConditionService) SetProductCondition(ctx context.Context, productID uuid.UUID, condition models.ProductCondition, note string) (*models.Product, error) {
+	const op = "service.product_condition_service.SetProductCondition"
+
+	if _, err := s.repo.GetProduct(ctx, productID); err != nil {
+		if err == e.ErrNotFound() {
+			s.log.Info(fmt.Sprintf("%s: product not found", op), "productID", productID)
+			return nil, e.ErrNotFound()
+		}
+		s.log.Error(fmt.Sprintf("%s: failed to get product", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	product, pvzID, err := s.repo.SetProductCondition(ctx, productID, condition, note)
+	if err == e.ErrProductNotInReception() {
+		s.log.Info(fmt.Sprintf("%s: product is not in an open reception", op), "productID", productID)
+		return nil, e.ErrProductNotInReception()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to set product condition", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to set product condition: %w", err)
+	}
+
+	s.log.Info(fmt.Sprintf("%s: product condition set", op), "productID", productID, "condition", condition)
+	publish(s.bus, s.log, models.EventProductConditionChanged, pvzID, product.ReceptionID, product)
+
+	return product, nil
+}
+
+// UploadProductPhoto attaches a photo to the condition report of a product in
+// an open reception, replacing the previous one
+func (s *ProductConditionService) UploadProductPhoto(ctx context.Context, productID uuid.UUID, contentType string, data []byte) (*models.Product, error) {
+	const op = "service.product_condition_service.UploadProductPhoto"
+
+	product, err := s.repo.GetProduct(ctx, productID)
+	if err == e.ErrNotFound() {
+		s.log.Info(fmt.Sprintf("%s: product not found", op), "productID", productID)
+		return nil, e.ErrNotFound()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to get product", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	if product.Status != models.ProductStatusAccepted {
+		s.log.Info(fmt.Sprintf("%s: product is not in an open reception", op), "productID", productID)
+		return nil, e.ErrProductNotInReception()
+	}
+
+	key := fmt.Sprintf("products/%s/%s%s", productID, uuid.New(), models.PhotoExtensions[contentType])
+	if err := s.photos.Put(ctx, key, data, contentType); err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to store photo", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to store photo: %w", err)
+	}
+
+	previous, err := s.repo.SetProductPhoto(ctx, productID, key)
+	if err != nil {
+		deletePhoto(ctx, s.photos, s.log, op, key)
+
+		if err == e.ErrProductNotInReception() {
+			s.log.Info(fmt.Sprintf("%s: product is not in an open reception", op), "productID", productID)
+			return nil, e.ErrProductNotInReception()
+		}
+		s.log.Error(fmt.Sprintf("%s: failed to set product photo", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to set product photo: %w", err)
+	}
+	if previous != "" {
+		deletePhoto(ctx, s.photos, s.log, op, previous)
+	}
+
+	product.PhotoKey = key
+	product.HasPhoto = true
+
+	return product, nil
+}
+
+// GetProductPhoto returns the photo of the product condition report and its
+// content type. The caller closes the returned reader.
+func (s *ProductConditionService) GetProductPhoto(ctx context.Context, productID uuid.UUID) (io.ReadCloser, string, error) {
+	const op = "service.product_condition_service.GetProductPhoto"
+
+	product, err := s.repo.GetProduct(ctx, productID)
+	if err == e.ErrNotFound() {
+		s.log.Info(fmt.Sprintf("%s: product not found", op), "productID", productID)
+		return nil, "", e.ErrNotFound()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to get product", op), sl.Err(err))
+		return nil, "", fmt.Errorf("failed to get product: %w", err)
+	}
+
+	if !product.HasPhoto {
+		s.log.Info(fmt.Sprintf("%s: product has no photo", op), "productID", productID)
+		return nil, "", e.ErrNotFound()
+	}
+
+	photo, contentType, err := s.photos.Get(ctx, product.PhotoKey)
+	if err == e.ErrNotFound() {
+		s.log.Error(fmt.Sprintf("%s: photo is missing from storage", op), "key", product.PhotoKey)
+		return nil, "", e.ErrNotFound()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to get photo", op), sl.Err(err))
+		return nil, "", fmt.Errorf("failed to get photo: %w", err)
+	}
+
+	return photo, contentType, nil
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/eventbus"
+	"pvz-service/internal/models"
+	"pvz-service/internal/storage"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProductConditionService_SetProductCondition(t *testing.T) {
+	productID := uuid.New()
+	receptionID := uuid.New()
+	pvzID := uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("GetProduct", mock.Anything, productID).Return(&models.Product{ID: productID, Status: models.ProductStatusAccepted}, nil)
+		mockRepo.On("SetProductCondition", mock.Anything, productID, models.ProductConditionWrongItem, "прислали синий вместо красного").
+			Return(&models.Product{
+				ID:            productID,
+				ReceptionID:   receptionID,
+				Status:        models.ProductStatusAccepted,
+				Condition:     models.ProductConditionWrongItem,
+				ConditionNote: "прислали синий вместо красного",
+			}, pvzID, nil)
+
+		bus := eventbus.NewBus()
+		events, unsubscribe := bus.Subscribe(pvzID)
+		defer unsubscribe()
+
+		service := NewProductConditionService(mockRepo, nil, bus, slog.Default())
+		product, err := service.SetProductCondition(context.Background(), productID, models.ProductConditionWrongItem, "прислали синий вместо красного")
+		assert.NoError(t, err)
+		assert.Equal(t, models.ProductConditionWrongItem, product.Condition)
+
+		event := <-events
+		assert.Equal(t, models.EventProductConditionChanged, event.Type)
+		assert.Equal(t, receptionID, event.ReceptionID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Product not found", func(t *testing.T) {
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("GetProduct", mock.Anything, productID).Return(nil, e.ErrNotFound())
+
+		service := NewProductConditionService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+		_, err := service.SetProductCondition(context.Background(), productID, models.ProductConditionDamaged, "")
+		assert.Equal(t, e.ErrNotFound(), err)
+	})
+
+	t.Run("Reception closed", func(t *testing.T) {
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("GetProduct", mock.Anything, productID).Return(&models.Product{ID: productID, Status: models.ProductStatusStored}, nil)
+		mockRepo.On("SetProductCondition", mock.Anything, productID, models.ProductConditionDamaged, "").Return(nil, uuid.Nil, e.ErrProductNotInReception())
+
+		service := NewProductConditionService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+		_, err := service.SetProductCondition(context.Background(), productID, models.ProductConditionDamaged, "")
+		assert.Equal(t, e.ErrProductNotInReception(), err)
+	})
+}
+
+func TestProductConditionService_ProductPhoto(t *testing.T) {
+	productID := uuid.New()
+	accepted := func(photoKey string) *models.Product {
+		return &models.Product{ID: productID, Status: models.ProductStatusAccepted, PhotoKey: photoKey, HasPhoto: photoKey != ""}
+	}
+
+	t.Run("Upload replaces previous photo", func(t *testing.T) {
+		photos := storage.NewLocalStorage(t.TempDir())
+		ctx := context.Background()
+		assert.NoError(t, photos.Put(ctx, "products/old.png", []byte("old"), "image/png"))
+
+		var stored string
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("GetProduct", mock.Anything, productID).Return(accepted(""), nil).Once()
+		mockRepo.On("SetProductPhoto", mock.Anything, productID, mock.MatchedBy(func(key string) bool {
+			stored = key
+			return strings.HasPrefix(key, "products/"+productID.String()+"/") && strings.HasSuffix(key, ".jpg")
+		})).Return("products/old.png", nil)
+
+		service := NewProductConditionService(mockRepo, photos, eventbus.NewBus(), slog.Default())
+		product, err := service.UploadProductPhoto(ctx, productID, "image/jpeg", []byte("jpeg"))
+		assert.NoError(t, err)
+		assert.True(t, product.HasPhoto)
+		assert.Equal(t, stored, product.PhotoKey)
+
+		_, _, err = photos.Get(ctx, "products/old.png")
+		assert.Equal(t, e.ErrNotFound(), err)
+
+		mockRepo.On("GetProduct", mock.Anything, productID).Return(accepted(stored), nil)
+		body, contentType, err := service.GetProductPhoto(ctx, productID)
+		assert.NoError(t, err)
+		data, _ := io.ReadAll(body)
+		body.Close()
+		assert.Equal(t, "jpeg", string(data))
+		assert.Equal(t, "image/jpeg", contentType)
+	})
+
+	t.Run("Upload after reception closed", func(t *testing.T) {
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("GetProduct", mock.Anything, productID).Return(&models.Product{ID: productID, Status: models.ProductStatusStored}, nil)
+
+		service := NewProductConditionService(mockRepo, storage.NewLocalStorage(t.TempDir()), eventbus.NewBus(), slog.Default())
+		_, err := service.UploadProductPhoto(context.Background(), productID, "image/jpeg", []byte("jpeg"))
+		assert.Equal(t, e.ErrProductNotInReception(), err)
+		mockRepo.AssertNotCalled(t, "SetProductPhoto", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Upload removes the photo when the reception closed meanwhile", func(t *testing.T) {
+		dir := t.TempDir()
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("GetProduct", mock.Anything, productID).Return(accepted(""), nil)
+		mockRepo.On("SetProductPhoto", mock.Anything, productID, mock.Anything).Return("", e.ErrProductNotInReception())
+
+		service := NewProductConditionService(mockRepo, storage.NewLocalStorage(dir), eventbus.NewBus(), slog.Default())
+		_, err := service.UploadProductPhoto(context.Background(), productID, "image/png", []byte("png"))
+		assert.Equal(t, e.ErrProductNotInReception(), err)
+
+		entries, _ := os.ReadDir(filepath.Join(dir, "products", productID.String()))
+		assert.Empty(t, entries)
+	})
+
+	t.Run("Get without photo", func(t *testing.T) {
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("GetProduct", mock.Anything, productID).Return(accepted(""), nil)
+
+		service := NewProductConditionService(mockRepo, storage.NewLocalStorage(t.TempDir()), eventbus.NewBus(), slog.Default())
+		_, _, err := service.GetProductPhoto(context.Background(), productID)
+		assert.Equal(t, e.ErrNotFound(), err)
+	})
+}
@@ -3,36 +3,61 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	e "pvz-service/internal/errors"
+	"pvz-service/internal/eventbus"
 	"pvz-service/internal/logger/sl"
 	"pvz-service/internal/models"
 	"pvz-service/internal/repository"
+	"pvz-service/internal/storage"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type PVZService struct {
-	repo repository.PVZRepository
-	log  *slog.Logger
+	repo   repository.PVZRepository
+	photos storage.Storage
+	bus    *eventbus.Bus
+	log    *slog.Logger
 }
 
-func NewPVZService(repo repository.PVZRepository, log *slog.Logger) *PVZService {
-	return &PVZService{repo: repo, log: log}
+func NewPVZService(repo repository.PVZRepository, photos storage.Storage, bus *eventbus.Bus, log *slog.Logger) *PVZService {
+	return &PVZService{repo: repo, photos: photos, bus: bus, log: log}
 }
 
 type PVZServiceInterface interface {
 	CreatePVZ(ctx context.Context, pvz *models.PVZ) (*models.PVZ, error)
-	StartReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error)
-	AddProduct(ctx context.Context, pvzID uuid.UUID, productTypeName string) (*models.Product, error)
-	DeleteLastProduct(ctx context.Context, pvzID uuid.UUID) error
-	CloseReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error)
+	UpdatePVZ(ctx context.Context, pvzID uuid.UUID, update models.PVZUpdate, ifMatch *models.ETag) (*models.PVZ, error)
+	DeletePVZ(ctx context.Context, pvzID uuid.UUID, ifMatch *models.ETag) error
+	GetNearbyPVZs(ctx context.Context, point models.GeoPoint, radius float64, limit int) ([]models.NearbyPVZ, error)
+	GetPVZSchedule(ctx context.Context, pvzID uuid.UUID) (*models.PVZSchedule, error)
+	SetPVZSchedule(ctx context.Context, schedule *models.PVZSchedule) (*models.PVZSchedule, error)
+	AllowReceptions(ctx context.Context, pvzID uuid.UUID, until time.Time) (*models.PVZSchedule, error)
+	DeletePVZSchedule(ctx context.Context, pvzID uuid.UUID) error
+	GetPVZOccupancy(ctx context.Context, pvzID uuid.UUID) (*models.PVZOccupancy, error)
+	ListPVZOccupancy(ctx context.Context) ([]models.PVZOccupancy, error)
+	SetTypeCapacities(ctx context.Context, pvzID uuid.UUID, capacities []models.TypeCapacity) error
+	StartReception(ctx context.Context, pvzID uuid.UUID, manifestID *uuid.UUID) (*models.Reception, error)
+	AddProduct(ctx context.Context, pvzID uuid.UUID, productTypeName, barcode string, ifMatch *models.ETag) (*models.Product, models.ETag, error)
+	DeleteLastProduct(ctx context.Context, pvzID uuid.UUID, ifMatch *models.ETag) (models.ETag, error)
+	CloseReception(ctx context.Context, pvzID uuid.UUID, ifMatch *models.ETag) (*models.Reception, error)
+	IssueProduct(ctx context.Context, productID uuid.UUID, comment string) (*models.Product, error)
+	ReturnProduct(ctx context.Context, productID uuid.UUID, comment string) (*models.Product, error)
+	WriteOffProduct(ctx context.Context, productID uuid.UUID, comment string) (*models.Product, error)
+	GetProductHistory(ctx context.Context, productID uuid.UUID) ([]models.ProductStatusChange, error)
 	GetPVZsWithReceptions(ctx context.Context, from, to time.Time, page, limit int) ([]models.PVZInfo, error)
 	GetPVZs(ctx context.Context) ([]models.PVZ, error)
+	StreamPVZs(ctx context.Context, filter models.PVZFilter, withReceptions, withProducts bool, send func(models.PVZInfo) error) error
+	SubscribeEvents(ctx context.Context, pvzID uuid.UUID) (<-chan models.Event, func(), error)
 }
 
+// pvzStreamBatchSize is how many PVZs StreamPVZs buffers before loading
+// their receptions and products in a single round trip.
+const pvzStreamBatchSize = 100
+
 func (s *PVZService) CreatePVZ(ctx context.Context, pvz *models.PVZ) (*models.PVZ, error) {
 	const op = "service.pvz_service.CreatePVZ"
 
@@ -51,14 +76,327 @@ func (s *PVZService) CreatePVZ(ctx context.Context, pvz *models.PVZ) (*models.PV
 		s.log.Error(fmt.Sprintf("%s: failed to create PVZ", op), sl.Err(err))
 		return nil, fmt.Errorf("failed to create PVZ: %w", err)
 	}
+	pvz.Status = models.PVZStatusActive
+	pvz.Version = 1
+
+	return pvz, nil
+}
+
+func (s *PVZService) UpdatePVZ(ctx context.Context, pvzID uuid.UUID, update models.PVZUpdate, ifMatch *models.ETag) (*models.PVZ, error) {
+	for attempt := 1; ; attempt++ {
+		pvz, err := s.updatePVZ(ctx, pvzID, update, ifMatch)
+		if !retryUnconditional(err, ifMatch, attempt) {
+			return pvz, err
+		}
+	}
+}
+
+// updatePVZ is a single attempt of UpdatePVZ
+func (s *PVZService) updatePVZ(ctx context.Context, pvzID uuid.UUID, update models.PVZUpdate, ifMatch *models.ETag) (*models.PVZ, error) {
+	const op = "service.pvz_service.UpdatePVZ"
+
+	pvz, err := s.repo.GetPVZ(ctx, pvzID)
+	if err == e.ErrNotFound() {
+		s.log.Info(fmt.Sprintf("%s: pvz not found", op), "pvzID", pvzID)
+		return nil, e.ErrNotFound()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to get PVZ", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to get PVZ: %w", err)
+	}
+
+	version, err := expectedVersion(pvz.ID, pvz.Version, ifMatch)
+	if err != nil {
+		s.log.Info(fmt.Sprintf("%s: pvz version mismatch", op), "pvzID", pvzID)
+		return nil, err
+	}
+
+	if update.CityName != nil && *update.CityName != pvz.CityName {
+		cityID, err := s.repo.GetCityID(ctx, *update.CityName)
+		if err == e.ErrCityNotAllowed() {
+			s.log.Info(fmt.Sprintf("%s: city not allowed", op), "city", *update.CityName)
+			return nil, e.ErrCityNotAllowed()
+		}
+		if err != nil {
+			s.log.Error(fmt.Sprintf("%s: failed to get city ID", op), sl.Err(err))
+			return nil, fmt.Errorf("failed to get city ID: %w", err)
+		}
+		pvz.CityID = cityID
+		pvz.CityName = *update.CityName
+	}
+
+	if update.Address != nil {
+		pvz.Address = *update.Address
+	}
+	if update.Location != nil {
+		pvz.Location = update.Location
+	}
+	if update.ClearLocation {
+		pvz.Location = nil
+	}
+	if update.Capacity != nil {
+		pvz.Capacity = *update.Capacity
+	}
+
+	if update.Status != nil && *update.Status != pvz.Status {
+		if *update.Status == models.PVZStatusClosed {
+			err := s.ensureNoActiveReception(ctx, pvzID)
+			if err == e.ErrActiveReceptionExists() {
+				s.log.Info(fmt.Sprintf("%s: pvz has active reception", op), "pvzID", pvzID)
+				return nil, e.ErrActiveReceptionExists()
+			}
+			if err != nil {
+				s.log.Error(fmt.Sprintf("%s: failed to check active receptions", op), sl.Err(err))
+				return nil, fmt.Errorf("failed to check active receptions: %w", err)
+			}
+		}
+		pvz.Status = *update.Status
+	}
+
+	newVersion, err := s.repo.UpdatePVZ(ctx, pvz, version)
+	if err == e.ErrVersionMismatch() {
+		s.log.Info(fmt.Sprintf("%s: pvz was modified concurrently", op), "pvzID", pvzID)
+		return nil, e.ErrVersionMismatch()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to update PVZ", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to update PVZ: %w", err)
+	}
+	pvz.Version = newVersion
 
 	return pvz, nil
 }
 
-func (s *PVZService) StartReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
+// DeletePVZ decommissions a PVZ. It is closed and hidden from the PVZ list,
+// while its receptions stay available for historic queries.
+func (s *PVZService) DeletePVZ(ctx context.Context, pvzID uuid.UUID, ifMatch *models.ETag) error {
+	for attempt := 1; ; attempt++ {
+		err := s.deletePVZ(ctx, pvzID, ifMatch)
+		if !retryUnconditional(err, ifMatch, attempt) {
+			return err
+		}
+	}
+}
+
+// deletePVZ is a single attempt of DeletePVZ
+func (s *PVZService) deletePVZ(ctx context.Context, pvzID uuid.UUID, ifMatch *models.ETag) error {
+	const op = "service.pvz_service.DeletePVZ"
+
+	pvz, err := s.repo.GetPVZ(ctx, pvzID)
+	if err == e.ErrNotFound() {
+		s.log.Info(fmt.Sprintf("%s: pvz not found", op), "pvzID", pvzID)
+		return e.ErrNotFound()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to get PVZ", op), sl.Err(err))
+		return fmt.Errorf("failed to get PVZ: %w", err)
+	}
+
+	version, err := expectedVersion(pvz.ID, pvz.Version, ifMatch)
+	if err != nil {
+		s.log.Info(fmt.Sprintf("%s: pvz version mismatch", op), "pvzID", pvzID)
+		return err
+	}
+
+	err = s.ensureNoActiveReception(ctx, pvzID)
+	if err == e.ErrActiveReceptionExists() {
+		s.log.Info(fmt.Sprintf("%s: pvz has active reception", op), "pvzID", pvzID)
+		return e.ErrActiveReceptionExists()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to check active receptions", op), sl.Err(err))
+		return fmt.Errorf("failed to check active receptions: %w", err)
+	}
+
+	err = s.repo.DeletePVZ(ctx, pvzID, version)
+	if err == e.ErrVersionMismatch() {
+		s.log.Info(fmt.Sprintf("%s: pvz was modified concurrently", op), "pvzID", pvzID)
+		return e.ErrVersionMismatch()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to delete PVZ", op), sl.Err(err))
+		return fmt.Errorf("failed to delete PVZ: %w", err)
+	}
+
+	return nil
+}
+
+func (s *PVZService) GetNearbyPVZs(ctx context.Context, point models.GeoPoint, radius float64, limit int) ([]models.NearbyPVZ, error) {
+	const op = "service.pvz_service.GetNearbyPVZs"
+
+	nearby, err := s.repo.GetNearbyPVZs(ctx, point, radius, limit)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to get nearby PVZs", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to get nearby PVZs: %w", err)
+	}
+
+	return nearby, nil
+}
+
+func (s *PVZService) GetPVZSchedule(ctx context.Context, pvzID uuid.UUID) (*models.PVZSchedule, error) {
+	const op = "service.pvz_service.GetPVZSchedule"
+
+	if _, err := s.repo.CheckPVZ(ctx, pvzID); err != nil {
+		if err == e.ErrNotFound() {
+			s.log.Info(fmt.Sprintf("%s: pvz not found", op), "pvzID", pvzID)
+			return nil, e.ErrNotFound()
+		}
+		s.log.Error(fmt.Sprintf("%s: failed to check PVZ", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to check PVZ: %w", err)
+	}
+
+	schedule, err := s.repo.GetPVZSchedule(ctx, pvzID)
+	if err == e.ErrNotFound() {
+		s.log.Info(fmt.Sprintf("%s: pvz has no schedule", op), "pvzID", pvzID)
+		return nil, e.ErrNotFound()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to get schedule", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to get schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// SetPVZSchedule replaces the working hours of a PVZ. The schedule is
+// expected to be validated by the caller.
+func (s *PVZService) SetPVZSchedule(ctx context.Context, schedule *models.PVZSchedule) (*models.PVZSchedule, error) {
+	const op = "service.pvz_service.SetPVZSchedule"
+
+	if _, err := s.repo.CheckPVZ(ctx, schedule.PVZID); err != nil {
+		if err == e.ErrNotFound() {
+			s.log.Info(fmt.Sprintf("%s: pvz not found", op), "pvzID", schedule.PVZID)
+			return nil, e.ErrNotFound()
+		}
+		s.log.Error(fmt.Sprintf("%s: failed to check PVZ", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to check PVZ: %w", err)
+	}
+
+	schedule.UpdatedAt = time.Now()
+	if err := s.repo.SavePVZSchedule(ctx, schedule); err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to save schedule", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to save schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// AllowReceptions lets receptions be started in a PVZ outside its working
+// hours until the given time. It returns ErrNotFound when the PVZ has no
+// schedule, since receptions may then be started at any time.
+func (s *PVZService) AllowReceptions(ctx context.Context, pvzID uuid.UUID, until time.Time) (*models.PVZSchedule, error) {
+	const op = "service.pvz_service.AllowReceptions"
+
+	err := s.repo.AllowReceptionsUntil(ctx, pvzID, until)
+	if err == e.ErrNotFound() {
+		s.log.Info(fmt.Sprintf("%s: pvz has no schedule", op), "pvzID", pvzID)
+		return nil, e.ErrNotFound()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to allow receptions", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to allow receptions: %w", err)
+	}
+	s.log.Info(fmt.Sprintf("%s: receptions allowed outside working hours", op), "pvzID", pvzID, "until", until)
+
+	schedule, err := s.repo.GetPVZSchedule(ctx, pvzID)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to get schedule", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to get schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// DeletePVZSchedule removes the working hours of a PVZ, so receptions can be
+// started there at any time
+func (s *PVZService) DeletePVZSchedule(ctx context.Context, pvzID uuid.UUID) error {
+	const op = "service.pvz_service.DeletePVZSchedule"
+
+	err := s.repo.DeletePVZSchedule(ctx, pvzID)
+	if err == e.ErrNotFound() {
+		s.log.Info(fmt.Sprintf("%s: pvz has no schedule", op), "pvzID", pvzID)
+		return e.ErrNotFound()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to delete schedule", op), sl.Err(err))
+		return fmt.Errorf("failed to delete schedule: %w", err)
+	}
+
+	return nil
+}
+
+func (s *PVZService) GetPVZOccupancy(ctx context.Context, pvzID uuid.UUID) (*models.PVZOccupancy, error) {
+	const op = "service.pvz_service.GetPVZOccupancy"
+
+	occupancy, err := s.repo.GetPVZOccupancy(ctx, pvzID)
+	if err == e.ErrNotFound() {
+		s.log.Info(fmt.Sprintf("%s: pvz not found", op), "pvzID", pvzID)
+		return nil, e.ErrNotFound()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to get occupancy", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to get occupancy: %w", err)
+	}
+
+	return occupancy, nil
+}
+
+// ListPVZOccupancy returns the total occupancy of every active PVZ
+func (s *PVZService) ListPVZOccupancy(ctx context.Context) ([]models.PVZOccupancy, error) {
+	const op = "service.pvz_service.ListPVZOccupancy"
+
+	occupancy, err := s.repo.ListPVZOccupancy(ctx)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to list occupancy", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to list occupancy: %w", err)
+	}
+
+	return occupancy, nil
+}
+
+// SetTypeCapacities replaces the per product type limits of a PVZ. Types
+// left out of capacities are no longer limited on their own.
+func (s *PVZService) SetTypeCapacities(ctx context.Context, pvzID uuid.UUID, capacities []models.TypeCapacity) error {
+	const op = "service.pvz_service.SetTypeCapacities"
+
+	if _, err := s.repo.CheckPVZ(ctx, pvzID); err != nil {
+		if err == e.ErrNotFound() {
+			s.log.Info(fmt.Sprintf("%s: pvz not found", op), "pvzID", pvzID)
+			return e.ErrNotFound()
+		}
+		s.log.Error(fmt.Sprintf("%s: failed to check PVZ", op), sl.Err(err))
+		return fmt.Errorf("failed to check PVZ: %w", err)
+	}
+
+	for i := range capacities {
+		typeID, err := s.repo.GetProductTypeID(ctx, capacities[i].TypeName)
+		if err == e.ErrProductTypeNotAllowed() {
+			s.log.Info(fmt.Sprintf("%s: product type not allowed", op), "type", capacities[i].TypeName)
+			return e.ErrProductTypeNotAllowed()
+		}
+		if err != nil {
+			s.log.Error(fmt.Sprintf("%s: failed to get product type", op), sl.Err(err))
+			return fmt.Errorf("failed to get product type: %w", err)
+		}
+		capacities[i].TypeID = typeID
+	}
+
+	if err := s.repo.ReplaceTypeCapacities(ctx, pvzID, capacities); err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to save type capacities", op), sl.Err(err))
+		return fmt.Errorf("failed to save type capacities: %w", err)
+	}
+
+	return nil
+}
+
+// StartReception opens a reception in an active PVZ. Outside the PVZ working
+// hours it fails with ErrOutsideWorkingHours unless a moderator has allowed
+// receptions with AllowReceptions. A pending manifest of the PVZ may be given
+// to reconcile the delivery against.
+func (s *PVZService) StartReception(ctx context.Context, pvzID uuid.UUID, manifestID *uuid.UUID) (*models.Reception, error) {
 	const op = "service.pvz_service.StartReception"
 
-	_, err := s.repo.CheckPVZ(ctx, pvzID)
+	pvz, err := s.repo.GetPVZ(ctx, pvzID)
 	if err == e.ErrNotFound() {
 		s.log.Info(fmt.Sprintf("%s: city not found", op), sl.Err(err))
 		return nil, e.ErrCityNotAllowed()
@@ -67,6 +405,21 @@ func (s *PVZService) StartReception(ctx context.Context, pvzID uuid.UUID) (*mode
 		s.log.Error(fmt.Sprintf("%s: failed to get city ID", op), sl.Err(err))
 		return nil, fmt.Errorf("failed to get city ID: %w", err)
 	}
+	if pvz.Status != models.PVZStatusActive {
+		s.log.Info(fmt.Sprintf("%s: pvz is not active", op), "pvzID", pvzID, "status", pvz.Status)
+		return nil, e.ErrPVZNotActive()
+	}
+
+	now := time.Now()
+	schedule, err := s.repo.GetPVZSchedule(ctx, pvzID)
+	if err != nil && err != e.ErrNotFound() {
+		s.log.Error(fmt.Sprintf("%s: failed to get schedule", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to get schedule: %w", err)
+	}
+	if schedule != nil && !schedule.AcceptsReceptions(now) {
+		s.log.Info(fmt.Sprintf("%s: pvz is closed", op), "pvzID", pvzID, "timezone", schedule.Timezone)
+		return nil, e.ErrOutsideWorkingHours()
+	}
 
 	// Check for existing active reception
 	activeReception, err := s.repo.GetActiveReception(ctx, pvzID)
@@ -80,22 +433,40 @@ func (s *PVZService) StartReception(ctx context.Context, pvzID uuid.UUID) (*mode
 	}
 
 	reception := &models.Reception{
-		ID:       uuid.New(),
-		DateTime: time.Now(),
-		PVZID:    pvzID,
-		Status:   models.ReceptionStatusInProgress,
+		ID:         uuid.New(),
+		DateTime:   now,
+		PVZID:      pvzID,
+		Status:     models.ReceptionStatusInProgress,
+		Version:    1,
+		ManifestID: manifestID,
 	}
 
-	// Simple repository call
-	if err := s.repo.InsertReception(ctx, reception); err != nil {
+	err = s.repo.InsertReception(ctx, reception)
+	if err == e.ErrManifestNotAvailable() {
+		s.log.Info(fmt.Sprintf("%s: manifest not available", op), "pvzID", pvzID, "manifestID", manifestID)
+		return nil, e.ErrManifestNotAvailable()
+	}
+	if err != nil {
 		s.log.Error(fmt.Sprintf("%s: failed to create reception", op), sl.Err(err))
 		return nil, fmt.Errorf("failed to create reception: %w", err)
 	}
 
+	publish(s.bus, s.log, models.EventReceptionOpened, pvzID, reception.ID, reception)
+
 	return reception, nil
 }
 
-func (s *PVZService) AddProduct(ctx context.Context, pvzID uuid.UUID, productTypeName string) (*models.Product, error) {
+func (s *PVZService) AddProduct(ctx context.Context, pvzID uuid.UUID, productTypeName, barcode string, ifMatch *models.ETag) (*models.Product, models.ETag, error) {
+	for attempt := 1; ; attempt++ {
+		product, etag, err := s.addProduct(ctx, pvzID, productTypeName, barcode, ifMatch)
+		if !retryUnconditional(err, ifMatch, attempt) {
+			return product, etag, err
+		}
+	}
+}
+
+// addProduct is a single attempt of AddProduct
+func (s *PVZService) addProduct(ctx context.Context, pvzID uuid.UUID, productTypeName, barcode string, ifMatch *models.ETag) (*models.Product, models.ETag, error) {
 	const op = "service.pvz_service.AddProduct"
 
 	// Check for active reception
@@ -103,10 +474,16 @@ func (s *PVZService) AddProduct(ctx context.Context, pvzID uuid.UUID, productTyp
 	if err != nil {
 		if err == e.ErrNoActiveReception() {
 			s.log.Info(fmt.Sprintf("%s: no active reception", op), "pvzID", pvzID)
-			return nil, e.ErrNoActiveReception()
+			return nil, models.ETag{}, e.ErrNoActiveReception()
 		}
 		s.log.Error(fmt.Sprintf("%s: failed to get active reception", op), sl.Err(err))
-		return nil, fmt.Errorf("failed to get active reception: %w", err)
+		return nil, models.ETag{}, fmt.Errorf("failed to get active reception: %w", err)
+	}
+
+	version, err := expectedVersion(reception.ID, reception.Version, ifMatch)
+	if err != nil {
+		s.log.Info(fmt.Sprintf("%s: reception version mismatch", op), "receptionID", reception.ID)
+		return nil, models.ETag{}, err
 	}
 
 	// Check product type
@@ -114,10 +491,10 @@ func (s *PVZService) AddProduct(ctx context.Context, pvzID uuid.UUID, productTyp
 	if err != nil {
 		if err == e.ErrProductTypeNotAllowed() {
 			s.log.Info(fmt.Sprintf("%s: product type not allowed", op), "type", productTypeName)
-			return nil, e.ErrProductTypeNotAllowed()
+			return nil, models.ETag{}, e.ErrProductTypeNotAllowed()
 		}
 		s.log.Error(fmt.Sprintf("%s: failed to get product type", op), sl.Err(err))
-		return nil, fmt.Errorf("failed to get product type: %w", err)
+		return nil, models.ETag{}, fmt.Errorf("failed to get product type: %w", err)
 	}
 
 	product := &models.Product{
@@ -126,18 +503,40 @@ func (s *PVZService) AddProduct(ctx context.Context, pvzID uuid.UUID, productTyp
 		TypeID:      productTypeID,
 		TypeName:    productTypeName,
 		ReceptionID: reception.ID,
+		Status:      models.ProductStatusAccepted,
+		Barcode:     barcode,
 	}
 
-	// Simple repository call
-	if err := s.repo.InsertProduct(ctx, product); err != nil {
+	newVersion, err := s.repo.InsertProduct(ctx, product, version)
+	if err == e.ErrVersionMismatch() {
+		s.log.Info(fmt.Sprintf("%s: reception was modified concurrently", op), "receptionID", reception.ID)
+		return nil, models.ETag{}, e.ErrVersionMismatch()
+	}
+	if err == e.ErrCapacityExceeded() {
+		s.log.Info(fmt.Sprintf("%s: pvz capacity exceeded", op), "pvzID", pvzID, "type", productTypeName)
+		return nil, models.ETag{}, e.ErrCapacityExceeded()
+	}
+	if err != nil {
 		s.log.Error(fmt.Sprintf("%s: failed to add product", op), sl.Err(err))
-		return nil, fmt.Errorf("failed to add product: %w", err)
+		return nil, models.ETag{}, fmt.Errorf("failed to add product: %w", err)
 	}
 
-	return product, nil
+	publish(s.bus, s.log, models.EventProductAdded, pvzID, reception.ID, product)
+
+	return product, models.ETag{ID: reception.ID, Version: newVersion}, nil
 }
 
-func (s *PVZService) DeleteLastProduct(ctx context.Context, pvzID uuid.UUID) error {
+func (s *PVZService) DeleteLastProduct(ctx context.Context, pvzID uuid.UUID, ifMatch *models.ETag) (models.ETag, error) {
+	for attempt := 1; ; attempt++ {
+		etag, err := s.deleteLastProduct(ctx, pvzID, ifMatch)
+		if !retryUnconditional(err, ifMatch, attempt) {
+			return etag, err
+		}
+	}
+}
+
+// deleteLastProduct is a single attempt of DeleteLastProduct
+func (s *PVZService) deleteLastProduct(ctx context.Context, pvzID uuid.UUID, ifMatch *models.ETag) (models.ETag, error) {
 	const op = "service.pvz_service.DeleteLastProduct"
 
 	// Check for active reception
@@ -145,33 +544,62 @@ func (s *PVZService) DeleteLastProduct(ctx context.Context, pvzID uuid.UUID) err
 	if err != nil {
 		if err == e.ErrNoActiveReception() {
 			s.log.Info(fmt.Sprintf("%s: no active reception", op), "pvzID", pvzID)
-			return e.ErrNoActiveReception()
+			return models.ETag{}, e.ErrNoActiveReception()
 		}
 		s.log.Error(fmt.Sprintf("%s: failed to get active reception", op), sl.Err(err))
-		return fmt.Errorf("failed to get active reception: %w", err)
+		return models.ETag{}, fmt.Errorf("failed to get active reception: %w", err)
+	}
+
+	version, err := expectedVersion(reception.ID, reception.Version, ifMatch)
+	if err != nil {
+		s.log.Info(fmt.Sprintf("%s: reception version mismatch", op), "receptionID", reception.ID)
+		return models.ETag{}, err
 	}
 
 	// Get last product
-	product, err := s.repo.GetLastProduct(ctx, reception.ID)
+	product, err := s.repo.GetLastProduct(ctx, reception.ID, reception.DateTime)
 	if err != nil {
 		if err == e.ErrNotFound() {
 			s.log.Info(fmt.Sprintf("%s: no products to delete", op), "receptionID", reception.ID)
-			return e.ErrNoProduct()
+			return models.ETag{}, e.ErrNoProduct()
 		}
 		s.log.Error(fmt.Sprintf("%s: failed to get last product", op), sl.Err(err))
-		return fmt.Errorf("failed to get last product: %w", err)
+		return models.ETag{}, fmt.Errorf("failed to get last product: %w", err)
 	}
 
-	// Simple repository call
-	if err := s.repo.DeleteProduct(ctx, product.ID); err != nil {
+	newVersion, photoKey, err := s.repo.DeleteProduct(ctx, product.ID, version)
+	if err == e.ErrVersionMismatch() {
+		s.log.Info(fmt.Sprintf("%s: reception was modified concurrently", op), "receptionID", reception.ID)
+		return models.ETag{}, e.ErrVersionMismatch()
+	}
+	if err == e.ErrNotFound() {
+		s.log.Info(fmt.Sprintf("%s: product already deleted", op), "productID", product.ID)
+		return models.ETag{}, e.ErrNoProduct()
+	}
+	if err != nil {
 		s.log.Error(fmt.Sprintf("%s: failed to delete product", op), sl.Err(err))
-		return fmt.Errorf("failed to delete product: %w", err)
+		return models.ETag{}, fmt.Errorf("failed to delete product: %w", err)
+	}
+	if photoKey != "" {
+		deletePhoto(ctx, s.photos, s.log, op, photoKey)
 	}
 
-	return nil
+	publish(s.bus, s.log, models.EventProductDeleted, pvzID, reception.ID, product)
+
+	return models.ETag{ID: reception.ID, Version: newVersion}, nil
+}
+
+func (s *PVZService) CloseReception(ctx context.Context, pvzID uuid.UUID, ifMatch *models.ETag) (*models.Reception, error) {
+	for attempt := 1; ; attempt++ {
+		reception, err := s.closeReception(ctx, pvzID, ifMatch)
+		if !retryUnconditional(err, ifMatch, attempt) {
+			return reception, err
+		}
+	}
 }
 
-func (s *PVZService) CloseReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
+// closeReception is a single attempt of CloseReception
+func (s *PVZService) closeReception(ctx context.Context, pvzID uuid.UUID, ifMatch *models.ETag) (*models.Reception, error) {
 	const op = "service.pvz_service.CloseReception"
 
 	// Check for active reception
@@ -185,16 +613,132 @@ func (s *PVZService) CloseReception(ctx context.Context, pvzID uuid.UUID) (*mode
 		return nil, fmt.Errorf("failed to get active reception: %w", err)
 	}
 
-	// Simple repository call
-	if err := s.repo.UpdateReceptionStatus(ctx, reception.ID, models.ReceptionStatusClose); err != nil {
+	version, err := expectedVersion(reception.ID, reception.Version, ifMatch)
+	if err != nil {
+		s.log.Info(fmt.Sprintf("%s: reception version mismatch", op), "receptionID", reception.ID)
+		return nil, err
+	}
+
+	newVersion, err := s.repo.UpdateReceptionStatus(ctx, reception.ID, models.ReceptionStatusClose, version)
+	if err == e.ErrVersionMismatch() {
+		s.log.Info(fmt.Sprintf("%s: reception was modified concurrently", op), "receptionID", reception.ID)
+		return nil, e.ErrVersionMismatch()
+	}
+	if err != nil {
 		s.log.Error(fmt.Sprintf("%s: failed to close reception", op), sl.Err(err))
 		return nil, fmt.Errorf("failed to close reception: %w", err)
 	}
 
-	reception.Status = models.ReceptionStatusClose
+	receptionClosed(ctx, s.repo, s.bus, s.log, op, reception, newVersion)
+
 	return reception, nil
 }
 
+// receptionClosed updates reception after it was closed with newVersion,
+// attaches the manifest report and publishes the closed event. It is shared
+// by the services that close receptions.
+func receptionClosed(ctx context.Context, repo repository.PVZRepository, bus *eventbus.Bus, log *slog.Logger, op string, reception *models.Reception, newVersion int) {
+	reception.Status = models.ReceptionStatusClose
+	reception.Version = newVersion
+
+	// The reception is closed at this point, so a failure to load the report
+	// is not reported to the caller. It stays available with the manifest.
+	if reception.ManifestID != nil {
+		manifest, err := repo.GetManifest(ctx, *reception.ManifestID)
+		if err != nil {
+			log.Error(fmt.Sprintf("%s: failed to get manifest", op), sl.Err(err))
+		} else {
+			reception.Discrepancies = manifest.Report
+		}
+	}
+
+	publish(bus, log, models.EventReceptionClosed, reception.PVZID, reception.ID, reception)
+}
+
+// IssueProduct hands a stored product over to the customer
+func (s *PVZService) IssueProduct(ctx context.Context, productID uuid.UUID, comment string) (*models.Product, error) {
+	return s.changeProductStatus(ctx, "service.pvz_service.IssueProduct", productID, models.ProductStatusIssued, comment)
+}
+
+// ReturnProduct takes an issued product back from the customer
+func (s *PVZService) ReturnProduct(ctx context.Context, productID uuid.UUID, comment string) (*models.Product, error) {
+	return s.changeProductStatus(ctx, "service.pvz_service.ReturnProduct", productID, models.ProductStatusReturned, comment)
+}
+
+// WriteOffProduct removes a lost or damaged product from the PVZ stock
+func (s *PVZService) WriteOffProduct(ctx context.Context, productID uuid.UUID, comment string) (*models.Product, error) {
+	return s.changeProductStatus(ctx, "service.pvz_service.WriteOffProduct", productID, models.ProductStatusWrittenOff, comment)
+}
+
+func (s *PVZService) changeProductStatus(ctx context.Context, op string, productID uuid.UUID, to models.ProductStatus, comment string) (*models.Product, error) {
+	product, err := s.repo.GetProduct(ctx, productID)
+	if err == e.ErrNotFound() {
+		s.log.Info(fmt.Sprintf("%s: product not found", op), "productID", productID)
+		return nil, e.ErrNotFound()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to get product", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	if !product.Status.CanTransitionTo(to) {
+		s.log.Info(fmt.Sprintf("%s: invalid status transition", op), "productID", productID, "from", product.Status, "to", to)
+		return nil, e.ErrInvalidStatusTransition()
+	}
+
+	change := &models.ProductStatusChange{
+		ProductID: productID,
+		From:      product.Status,
+		To:        to,
+		Comment:   comment,
+		ChangedAt: time.Now(),
+	}
+
+	err = s.repo.ChangeProductStatus(ctx, change)
+	if err == e.ErrInvalidStatusTransition() {
+		s.log.Info(fmt.Sprintf("%s: product was modified concurrently", op), "productID", productID)
+		return nil, e.ErrInvalidStatusTransition()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to change product status", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to change product status: %w", err)
+	}
+
+	product.Status = to
+	publish(s.bus, s.log, models.EventProductStatusChanged, change.PVZID, change.ReceptionID, change)
+
+	return product, nil
+}
+
+func (s *PVZService) GetProductHistory(ctx context.Context, productID uuid.UUID) ([]models.ProductStatusChange, error) {
+	const op = "service.pvz_service.GetProductHistory"
+
+	if _, err := s.repo.GetProduct(ctx, productID); err != nil {
+		if err == e.ErrNotFound() {
+			s.log.Info(fmt.Sprintf("%s: product not found", op), "productID", productID)
+			return nil, e.ErrNotFound()
+		}
+		s.log.Error(fmt.Sprintf("%s: failed to get product", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	history, err := s.repo.GetProductHistory(ctx, productID)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to get product history", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to get product history: %w", err)
+	}
+
+	return history, nil
+}
+
+// deletePhoto removes a photo that is no longer referenced. A failure only
+// leaves an orphaned object behind, so it is logged and not returned.
+func deletePhoto(ctx context.Context, photos storage.Storage, log *slog.Logger, op, key string) {
+	if err := photos.Delete(ctx, key); err != nil {
+		log.Error(fmt.Sprintf("%s: failed to delete photo", op), "key", key, sl.Err(err))
+	}
+}
+
 func (s *PVZService) GetPVZsWithReceptions(ctx context.Context, from, to time.Time, page, limit int) ([]models.PVZInfo, error) {
 	const op = "service.pvz_service.GetPVZsWithReceptions"
 
@@ -222,12 +766,8 @@ func (s *PVZService) GetPVZsWithReceptions(ctx context.Context, from, to time.Ti
 		return nil, fmt.Errorf("failed to get receptions: %w", err)
 	}
 
-	receptionIDs := make([]uuid.UUID, len(receptions))
-	for i, rec := range receptions {
-		receptionIDs[i] = rec.ID
-	}
-
-	products, err := s.repo.GetProductsForReceptions(ctx, receptionIDs)
+	receptionIDs, since := receptionBounds(receptions)
+	products, err := s.repo.GetProductsForReceptions(ctx, receptionIDs, since)
 	if err != nil {
 		s.log.Error(fmt.Sprintf("%s: failed to get products", op), sl.Err(err))
 		return nil, fmt.Errorf("failed to get products: %w", err)
@@ -237,6 +777,21 @@ func (s *PVZService) GetPVZsWithReceptions(ctx context.Context, from, to time.Ti
 	return s.buildPVZResponse(pvzs, receptions, products), nil
 }
 
+// receptionBounds returns the IDs of the receptions and the earliest of
+// their dates. Products are never dated before their reception, so the date
+// bounds the products to read.
+func receptionBounds(receptions []models.Reception) ([]uuid.UUID, time.Time) {
+	ids := make([]uuid.UUID, len(receptions))
+	var since time.Time
+	for i, rec := range receptions {
+		ids[i] = rec.ID
+		if i == 0 || rec.DateTime.Before(since) {
+			since = rec.DateTime
+		}
+	}
+	return ids, since
+}
+
 // Helper function: Build hierarchical response
 func (s *PVZService) buildPVZResponse(pvzs []models.PVZ, receptions []models.Reception, products []models.Product) []models.PVZInfo {
 	// Create maps for quick lookup
@@ -258,6 +813,11 @@ func (s *PVZService) buildPVZResponse(pvzs []models.PVZ, receptions []models.Rec
 				ID:               pvz.ID,
 				RegistrationDate: pvz.RegistrationDate,
 				CityName:         pvz.CityName,
+				Address:          pvz.Address,
+				Location:         pvz.Location,
+				Capacity:         pvz.Capacity,
+				Status:           pvz.Status,
+				DeletedAt:        pvz.DeletedAt,
 			},
 			Receptions: []models.ReceptionInfo{},
 		}
@@ -271,6 +831,7 @@ func (s *PVZService) buildPVZResponse(pvzs []models.PVZ, receptions []models.Rec
 					DateTime:    prod.DateTime,
 					TypeName:    prod.TypeName,
 					ReceptionID: prod.ReceptionID,
+					Status:      prod.Status,
 				}
 			}
 
@@ -303,3 +864,155 @@ func (s *PVZService) GetPVZs(ctx context.Context) ([]models.PVZ, error) {
 
 	return pvzs, nil
 }
+
+func (s *PVZService) StreamPVZs(ctx context.Context, filter models.PVZFilter, withReceptions, withProducts bool, send func(models.PVZInfo) error) error {
+	const op = "service.pvz_service.StreamPVZs"
+
+	batch := make([]models.PVZ, 0, pvzStreamBatchSize)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		infos, err := s.loadPVZBatch(ctx, batch, filter, withReceptions, withProducts)
+		if err != nil {
+			return err
+		}
+
+		for _, info := range infos {
+			if err := send(info); err != nil {
+				return err
+			}
+		}
+
+		batch = batch[:0]
+		return nil
+	}
+
+	err := s.repo.IteratePVZs(ctx, filter, func(pvz models.PVZ) error {
+		batch = append(batch, pvz)
+		if len(batch) < pvzStreamBatchSize {
+			return nil
+		}
+		return flush()
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to stream PVZs", op), sl.Err(err))
+		return fmt.Errorf("failed to stream PVZs: %w", err)
+	}
+
+	return nil
+}
+
+// Helper function: Load receptions and products for one batch of streamed PVZs
+func (s *PVZService) loadPVZBatch(ctx context.Context, pvzs []models.PVZ, filter models.PVZFilter, withReceptions, withProducts bool) ([]models.PVZInfo, error) {
+	if !withReceptions {
+		return s.buildPVZResponse(pvzs, nil, nil), nil
+	}
+
+	pvzIDs := make([]uuid.UUID, len(pvzs))
+	for i, pvz := range pvzs {
+		pvzIDs[i] = pvz.ID
+	}
+
+	to := filter.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+
+	receptions, err := s.repo.GetReceptionsForPVZs(ctx, pvzIDs, filter.From, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receptions: %w", err)
+	}
+
+	if !withProducts || len(receptions) == 0 {
+		return s.buildPVZResponse(pvzs, receptions, nil), nil
+	}
+
+	receptionIDs, since := receptionBounds(receptions)
+	products, err := s.repo.GetProductsForReceptions(ctx, receptionIDs, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+
+	return s.buildPVZResponse(pvzs, receptions, products), nil
+}
+
+// SubscribeEvents returns live events of an existing PVZ. The caller must
+// call the returned function once it stops reading.
+func (s *PVZService) SubscribeEvents(ctx context.Context, pvzID uuid.UUID) (<-chan models.Event, func(), error) {
+	const op = "service.pvz_service.SubscribeEvents"
+
+	_, err := s.repo.CheckPVZ(ctx, pvzID)
+	if err == e.ErrNotFound() {
+		s.log.Info(fmt.Sprintf("%s: pvz not found", op), "pvzID", pvzID)
+		return nil, nil, e.ErrNotFound()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to check PVZ", op), sl.Err(err))
+		return nil, nil, fmt.Errorf("failed to check PVZ: %w", err)
+	}
+
+	events, unsubscribe := s.bus.Subscribe(pvzID)
+	return events, unsubscribe, nil
+}
+
+// Helper function: Fail with ErrActiveReceptionExists while a reception is in progress
+func (s *PVZService) ensureNoActiveReception(ctx context.Context, pvzID uuid.UUID) error {
+	_, err := s.repo.GetActiveReception(ctx, pvzID)
+	if err == e.ErrNoActiveReception() {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return e.ErrActiveReceptionExists()
+}
+
+// unconditionalAttempts bounds how often a mutation sent without If-Match is
+// repeated after losing the race to a concurrent one
+const unconditionalAttempts = 5
+
+// Helper function: Pick the version a mutation of a reception or PVZ must match.
+// Without If-Match it is the version just read, so a concurrent change shows
+// up as a mismatch the caller retries on fresh state.
+func expectedVersion(id uuid.UUID, version int, ifMatch *models.ETag) (int, error) {
+	if ifMatch == nil {
+		return version, nil
+	}
+	if ifMatch.ID != id {
+		return 0, e.ErrVersionMismatch()
+	}
+	return ifMatch.Version, nil
+}
+
+// Helper function: Tell whether a mutation lost the race to a concurrent one
+// without the client asking for a precondition, so it is repeated instead of
+// failing with a version mismatch
+func retryUnconditional(err error, ifMatch *models.ETag, attempt int) bool {
+	return err == e.ErrVersionMismatch() && ifMatch == nil && attempt < unconditionalAttempts
+}
+
+// Helper function: Publish live event after successful change
+func publish(bus *eventbus.Bus, log *slog.Logger, eventType models.EventType, pvzID, receptionID uuid.UUID, payload any) {
+	const op = "service.pvz_service.publish"
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Error(fmt.Sprintf("%s: failed to marshal event payload", op), sl.Err(err))
+		return
+	}
+
+	bus.Publish(models.Event{
+		ID:          uuid.New(),
+		Type:        eventType,
+		PVZID:       pvzID,
+		ReceptionID: receptionID,
+		Payload:     data,
+		CreatedAt:   time.Now(),
+	})
+}
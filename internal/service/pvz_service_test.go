@@ -5,7 +5,9 @@ import (
 	"errors"
 	"log/slog"
 	e "pvz-service/internal/errors"
+	"pvz-service/internal/eventbus"
 	"pvz-service/internal/models"
+	"pvz-service/internal/storage"
 	"testing"
 	"time"
 
@@ -18,10 +20,6 @@ type MockPVZRepository struct {
 	mock.Mock
 }
 
-func (m *MockPVZRepository) CloseConnection() {
-	m.Called()
-}
-
 func (m *MockPVZRepository) InsertPVZ(ctx context.Context, pvz *models.PVZ) error {
 	args := m.Called(ctx, pvz)
 	return args.Error(0)
@@ -51,18 +49,18 @@ func (m *MockPVZRepository) GetActiveReception(ctx context.Context, pvzID uuid.U
 	return args.Get(0).(*models.Reception), args.Error(1)
 }
 
-func (m *MockPVZRepository) UpdateReceptionStatus(ctx context.Context, receptionID uuid.UUID, status models.ReceptionStatus) error {
-	args := m.Called(ctx, receptionID, status)
-	return args.Error(0)
+func (m *MockPVZRepository) UpdateReceptionStatus(ctx context.Context, receptionID uuid.UUID, status models.ReceptionStatus, version int) (int, error) {
+	args := m.Called(ctx, receptionID, status, version)
+	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPVZRepository) InsertProduct(ctx context.Context, product *models.Product) error {
-	args := m.Called(ctx, product)
-	return args.Error(0)
+func (m *MockPVZRepository) InsertProduct(ctx context.Context, product *models.Product, receptionVersion int) (int, error) {
+	args := m.Called(ctx, product, receptionVersion)
+	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPVZRepository) GetLastProduct(ctx context.Context, receptionID uuid.UUID) (*models.Product, error) {
-	args := m.Called(ctx, receptionID)
+func (m *MockPVZRepository) GetLastProduct(ctx context.Context, receptionID uuid.UUID, since time.Time) (*models.Product, error) {
+	args := m.Called(ctx, receptionID, since)
 	prd := args.Get(0)
 	if prd == nil {
 		return nil, args.Error(1)
@@ -70,9 +68,9 @@ func (m *MockPVZRepository) GetLastProduct(ctx context.Context, receptionID uuid
 	return args.Get(0).(*models.Product), args.Error(1)
 }
 
-func (m *MockPVZRepository) DeleteProduct(ctx context.Context, productID uuid.UUID) error {
-	args := m.Called(ctx, productID)
-	return args.Error(0)
+func (m *MockPVZRepository) DeleteProduct(ctx context.Context, productID uuid.UUID, receptionVersion int) (int, string, error) {
+	args := m.Called(ctx, productID, receptionVersion)
+	return args.Int(0), args.String(1), args.Error(2)
 }
 
 func (m *MockPVZRepository) GetProductTypeID(ctx context.Context, productTypeName string) (int, error) {
@@ -98,16 +96,243 @@ func (m *MockPVZRepository) GetPVZsWithNoFilter(ctx context.Context) ([]models.P
 	return args.Get(0).([]models.PVZ), args.Error(1)
 }
 
+func (m *MockPVZRepository) GetPVZ(ctx context.Context, pvzID uuid.UUID) (*models.PVZ, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PVZ), args.Error(1)
+}
+
+func (m *MockPVZRepository) UpdatePVZ(ctx context.Context, pvz *models.PVZ, version int) (int, error) {
+	args := m.Called(ctx, pvz, version)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPVZRepository) DeletePVZ(ctx context.Context, pvzID uuid.UUID, version int) error {
+	args := m.Called(ctx, pvzID, version)
+	return args.Error(0)
+}
+
+func (m *MockPVZRepository) GetPVZSchedule(ctx context.Context, pvzID uuid.UUID) (*models.PVZSchedule, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PVZSchedule), args.Error(1)
+}
+
+func (m *MockPVZRepository) SavePVZSchedule(ctx context.Context, schedule *models.PVZSchedule) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (m *MockPVZRepository) AllowReceptionsUntil(ctx context.Context, pvzID uuid.UUID, until time.Time) error {
+	args := m.Called(ctx, pvzID, until)
+	return args.Error(0)
+}
+
+func (m *MockPVZRepository) DeletePVZSchedule(ctx context.Context, pvzID uuid.UUID) error {
+	args := m.Called(ctx, pvzID)
+	return args.Error(0)
+}
+
+func (m *MockPVZRepository) GetPVZOccupancy(ctx context.Context, pvzID uuid.UUID) (*models.PVZOccupancy, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PVZOccupancy), args.Error(1)
+}
+
+func (m *MockPVZRepository) ListPVZOccupancy(ctx context.Context) ([]models.PVZOccupancy, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PVZOccupancy), args.Error(1)
+}
+
+func (m *MockPVZRepository) ReplaceTypeCapacities(ctx context.Context, pvzID uuid.UUID, capacities []models.TypeCapacity) error {
+	args := m.Called(ctx, pvzID, capacities)
+	return args.Error(0)
+}
+
+func (m *MockPVZRepository) GetProduct(ctx context.Context, productID uuid.UUID) (*models.Product, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockPVZRepository) ChangeProductStatus(ctx context.Context, change *models.ProductStatusChange) error {
+	args := m.Called(ctx, change)
+	return args.Error(0)
+}
+
+func (m *MockPVZRepository) SetProductCondition(ctx context.Context, productID uuid.UUID, condition models.ProductCondition, note string) (*models.Product, uuid.UUID, error) {
+	args := m.Called(ctx, productID, condition, note)
+	if args.Get(0) == nil {
+		return nil, uuid.Nil, args.Error(2)
+	}
+	return args.Get(0).(*models.Product), args.Get(1).(uuid.UUID), args.Error(2)
+}
+
+func (m *MockPVZRepository) SetProductPhoto(ctx context.Context, productID uuid.UUID, photoKey string) (string, error) {
+	args := m.Called(ctx, productID, photoKey)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockPVZRepository) GetStaleReceptions(ctx context.Context, idleSince time.Time) ([]models.StaleReception, error) {
+	args := m.Called(ctx, idleSince)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.StaleReception), args.Error(1)
+}
+
+func (m *MockPVZRepository) ForceCloseReception(ctx context.Context, receptionID uuid.UUID, version int, entry *models.ReceptionAuditEntry) (int, error) {
+	args := m.Called(ctx, receptionID, version, entry)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPVZRepository) InsertReceptionAudit(ctx context.Context, entry *models.ReceptionAuditEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockPVZRepository) GetPVZReceptionAudit(ctx context.Context, pvzID uuid.UUID) ([]models.ReceptionAuditEntry, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ReceptionAuditEntry), args.Error(1)
+}
+
+func (m *MockPVZRepository) ArchiveReceptions(ctx context.Context, closedBefore, archivedAt time.Time, limit int) (int, int, []string, error) {
+	args := m.Called(ctx, closedBefore, archivedAt, limit)
+	photoKeys, _ := args.Get(2).([]string)
+	return args.Int(0), args.Int(1), photoKeys, args.Error(3)
+}
+
+func (m *MockPVZRepository) GetArchivedReceptions(ctx context.Context, pvzID uuid.UUID, from, to time.Time, limit, offset int) ([]models.Reception, error) {
+	args := m.Called(ctx, pvzID, from, to, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Reception), args.Error(1)
+}
+
+func (m *MockPVZRepository) GetArchivedProducts(ctx context.Context, receptionIDs []uuid.UUID) ([]models.Product, error) {
+	args := m.Called(ctx, receptionIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Product), args.Error(1)
+}
+
+func (m *MockPVZRepository) GetProductHistory(ctx context.Context, productID uuid.UUID) ([]models.ProductStatusChange, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ProductStatusChange), args.Error(1)
+}
+
+func (m *MockPVZRepository) CreateTransfer(ctx context.Context, transfer *models.Transfer) error {
+	args := m.Called(ctx, transfer)
+	return args.Error(0)
+}
+
+func (m *MockPVZRepository) GetTransfer(ctx context.Context, transferID uuid.UUID) (*models.Transfer, error) {
+	args := m.Called(ctx, transferID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transfer), args.Error(1)
+}
+
+func (m *MockPVZRepository) GetPVZTransfers(ctx context.Context, pvzID uuid.UUID, status models.TransferStatus) ([]models.Transfer, error) {
+	args := m.Called(ctx, pvzID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Transfer), args.Error(1)
+}
+
+func (m *MockPVZRepository) DispatchTransfer(ctx context.Context, transferID uuid.UUID, dispatchedAt time.Time) ([]models.ProductStatusChange, error) {
+	args := m.Called(ctx, transferID, dispatchedAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ProductStatusChange), args.Error(1)
+}
+
+func (m *MockPVZRepository) ReceiveTransfer(ctx context.Context, transferID, receptionID uuid.UUID, receptionVersion int, receivedAt time.Time) (int, []models.ProductStatusChange, error) {
+	args := m.Called(ctx, transferID, receptionID, receptionVersion, receivedAt)
+	if args.Get(1) == nil {
+		return args.Int(0), nil, args.Error(2)
+	}
+	return args.Int(0), args.Get(1).([]models.ProductStatusChange), args.Error(2)
+}
+
+func (m *MockPVZRepository) CancelTransfer(ctx context.Context, transferID uuid.UUID) error {
+	args := m.Called(ctx, transferID)
+	return args.Error(0)
+}
+
+func (m *MockPVZRepository) CreateManifest(ctx context.Context, manifest *models.Manifest) error {
+	args := m.Called(ctx, manifest)
+	return args.Error(0)
+}
+
+func (m *MockPVZRepository) GetManifest(ctx context.Context, manifestID uuid.UUID) (*models.Manifest, error) {
+	args := m.Called(ctx, manifestID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Manifest), args.Error(1)
+}
+
+func (m *MockPVZRepository) GetPVZManifests(ctx context.Context, pvzID uuid.UUID, status models.ManifestStatus) ([]models.Manifest, error) {
+	args := m.Called(ctx, pvzID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Manifest), args.Error(1)
+}
+
+func (m *MockPVZRepository) GetNearbyPVZs(ctx context.Context, point models.GeoPoint, radius float64, limit int) ([]models.NearbyPVZ, error) {
+	args := m.Called(ctx, point, radius, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.NearbyPVZ), args.Error(1)
+}
+
 func (m *MockPVZRepository) GetReceptionsForPVZs(ctx context.Context, pvzIDs []uuid.UUID, from, to time.Time) ([]models.Reception, error) {
 	args := m.Called(ctx, pvzIDs, from, to)
 	return args.Get(0).([]models.Reception), args.Error(1)
 }
 
-func (m *MockPVZRepository) GetProductsForReceptions(ctx context.Context, receptionIDs []uuid.UUID) ([]models.Product, error) {
-	args := m.Called(ctx, receptionIDs)
+func (m *MockPVZRepository) GetProductsForReceptions(ctx context.Context, receptionIDs []uuid.UUID, since time.Time) ([]models.Product, error) {
+	args := m.Called(ctx, receptionIDs, since)
 	return args.Get(0).([]models.Product), args.Error(1)
 }
 
+func (m *MockPVZRepository) IteratePVZs(ctx context.Context, filter models.PVZFilter, fn func(models.PVZ) error) error {
+	args := m.Called(ctx, filter)
+	if list, ok := args.Get(0).([]models.PVZ); ok {
+		for _, pvz := range list {
+			if err := fn(pvz); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
 func TestPVZService_CreatePVZ(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -156,7 +381,7 @@ func TestPVZService_CreatePVZ(t *testing.T) {
 			mockRepo := new(MockPVZRepository)
 			tt.mockSetup(mockRepo)
 
-			service := NewPVZService(mockRepo, slog.Default())
+			service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
 			result, err := service.CreatePVZ(context.Background(), tt.pvz)
 
 			if tt.expectError != nil {
@@ -175,14 +400,411 @@ func TestPVZService_CreatePVZ(t *testing.T) {
 	}
 }
 
+func TestPVZService_UpdatePVZ(t *testing.T) {
+	testPVZID := uuid.New()
+	newPVZ := func() *models.PVZ {
+		return &models.PVZ{ID: testPVZID, CityID: 1, CityName: "Москва", Status: models.PVZStatusActive, Version: 2}
+	}
+	city := "Казань"
+	suspended := models.PVZStatusSuspended
+	closed := models.PVZStatusClosed
+	address := "ул. Тверская, 1"
+	location := models.GeoPoint{Latitude: 55.7575, Longitude: 37.6132}
+	capacity := 500
+
+	tests := []struct {
+		name        string
+		update      models.PVZUpdate
+		ifMatch     *models.ETag
+		mockSetup   func(*MockPVZRepository)
+		expectError error
+		expectPVZ   *models.PVZ
+	}{
+		{
+			name:   "Change city and suspend",
+			update: models.PVZUpdate{CityName: &city, Status: &suspended},
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("GetPVZ", mock.Anything, testPVZID).Return(newPVZ(), nil)
+				m.On("GetCityID", mock.Anything, city).Return(3, nil)
+				m.On("UpdatePVZ", mock.Anything, mock.MatchedBy(func(p *models.PVZ) bool {
+					return p.CityID == 3 && p.Status == models.PVZStatusSuspended
+				}), 2).Return(3, nil)
+			},
+			expectPVZ: &models.PVZ{ID: testPVZID, CityID: 3, CityName: city, Status: models.PVZStatusSuspended, Version: 3},
+		},
+		{
+			name:   "Set address, location and capacity",
+			update: models.PVZUpdate{Address: &address, Location: &location, Capacity: &capacity},
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("GetPVZ", mock.Anything, testPVZID).Return(newPVZ(), nil)
+				m.On("UpdatePVZ", mock.Anything, mock.Anything, 2).Return(3, nil)
+			},
+			expectPVZ: &models.PVZ{
+				ID:       testPVZID,
+				CityID:   1,
+				CityName: "Москва",
+				Address:  address,
+				Location: &location,
+				Capacity: capacity,
+				Status:   models.PVZStatusActive,
+				Version:  3,
+			},
+		},
+		{
+			name:   "Clear location",
+			update: models.PVZUpdate{ClearLocation: true},
+			mockSetup: func(m *MockPVZRepository) {
+				located := newPVZ()
+				located.Location = &location
+				m.On("GetPVZ", mock.Anything, testPVZID).Return(located, nil)
+				m.On("UpdatePVZ", mock.Anything, mock.MatchedBy(func(p *models.PVZ) bool {
+					return p.Location == nil
+				}), 2).Return(3, nil)
+			},
+			expectPVZ: &models.PVZ{ID: testPVZID, CityID: 1, CityName: "Москва", Status: models.PVZStatusActive, Version: 3},
+		},
+		{
+			name:    "Stale If-Match",
+			update:  models.PVZUpdate{Status: &suspended},
+			ifMatch: &models.ETag{ID: testPVZID, Version: 1},
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("GetPVZ", mock.Anything, testPVZID).Return(newPVZ(), nil)
+				m.On("UpdatePVZ", mock.Anything, mock.Anything, 1).Return(0, e.ErrVersionMismatch())
+			},
+			expectError: e.ErrVersionMismatch(),
+		},
+		{
+			name:   "Close with active reception",
+			update: models.PVZUpdate{Status: &closed},
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("GetPVZ", mock.Anything, testPVZID).Return(newPVZ(), nil)
+				m.On("GetActiveReception", mock.Anything, testPVZID).Return(&models.Reception{ID: uuid.New()}, nil)
+			},
+			expectError: e.ErrActiveReceptionExists(),
+		},
+		{
+			name:   "City not allowed",
+			update: models.PVZUpdate{CityName: &city},
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("GetPVZ", mock.Anything, testPVZID).Return(newPVZ(), nil)
+				m.On("GetCityID", mock.Anything, city).Return(0, e.ErrCityNotAllowed())
+			},
+			expectError: e.ErrCityNotAllowed(),
+		},
+		{
+			name:   "PVZ not found",
+			update: models.PVZUpdate{Status: &suspended},
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("GetPVZ", mock.Anything, testPVZID).Return(nil, e.ErrNotFound())
+			},
+			expectError: e.ErrNotFound(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockPVZRepository)
+			tt.mockSetup(mockRepo)
+
+			service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+			result, err := service.UpdatePVZ(context.Background(), testPVZID, tt.update, tt.ifMatch)
+
+			if tt.expectError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectError.Error(), err.Error())
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectPVZ, result)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPVZService_DeletePVZ(t *testing.T) {
+	testPVZID := uuid.New()
+	testPVZ := &models.PVZ{ID: testPVZID, Status: models.PVZStatusActive, Version: 5}
+
+	tests := []struct {
+		name        string
+		ifMatch     *models.ETag
+		mockSetup   func(*MockPVZRepository)
+		expectError error
+	}{
+		{
+			name: "Success",
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("GetPVZ", mock.Anything, testPVZID).Return(testPVZ, nil)
+				m.On("GetActiveReception", mock.Anything, testPVZID).Return(nil, e.ErrNoActiveReception())
+				m.On("DeletePVZ", mock.Anything, testPVZID, 5).Return(nil)
+			},
+		},
+		{
+			name:    "If-Match for another PVZ",
+			ifMatch: &models.ETag{ID: uuid.New(), Version: 5},
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("GetPVZ", mock.Anything, testPVZID).Return(testPVZ, nil)
+			},
+			expectError: e.ErrVersionMismatch(),
+		},
+		{
+			name: "Active reception exists",
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("GetPVZ", mock.Anything, testPVZID).Return(testPVZ, nil)
+				m.On("GetActiveReception", mock.Anything, testPVZID).Return(&models.Reception{ID: uuid.New()}, nil)
+			},
+			expectError: e.ErrActiveReceptionExists(),
+		},
+		{
+			name: "Delete error",
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("GetPVZ", mock.Anything, testPVZID).Return(testPVZ, nil)
+				m.On("GetActiveReception", mock.Anything, testPVZID).Return(nil, e.ErrNoActiveReception())
+				m.On("DeletePVZ", mock.Anything, testPVZID, 5).Return(errors.New("db error"))
+			},
+			expectError: errors.New("failed to delete PVZ: db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockPVZRepository)
+			tt.mockSetup(mockRepo)
+
+			service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+			err := service.DeletePVZ(context.Background(), testPVZID, tt.ifMatch)
+
+			if tt.expectError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPVZService_SetPVZSchedule(t *testing.T) {
+	testPVZID := uuid.New()
+	newSchedule := func() *models.PVZSchedule {
+		return &models.PVZSchedule{
+			PVZID:    testPVZID,
+			Timezone: "Asia/Yekaterinburg",
+			Weekly: []models.ScheduleDay{
+				{Weekday: "monday", Intervals: []models.ScheduleInterval{{Opens: "09:00", Closes: "21:00"}}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		mockSetup   func(*MockPVZRepository)
+		expectError error
+	}{
+		{
+			name: "Success",
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("CheckPVZ", mock.Anything, testPVZID).Return(true, nil)
+				m.On("SavePVZSchedule", mock.Anything, mock.MatchedBy(func(s *models.PVZSchedule) bool {
+					return s.PVZID == testPVZID && !s.UpdatedAt.IsZero()
+				})).Return(nil)
+			},
+		},
+		{
+			name: "PVZ not found",
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("CheckPVZ", mock.Anything, testPVZID).Return(false, e.ErrNotFound())
+			},
+			expectError: e.ErrNotFound(),
+		},
+		{
+			name: "Save error",
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("CheckPVZ", mock.Anything, testPVZID).Return(true, nil)
+				m.On("SavePVZSchedule", mock.Anything, mock.Anything).Return(errors.New("db error"))
+			},
+			expectError: errors.New("failed to save schedule: db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockPVZRepository)
+			tt.mockSetup(mockRepo)
+
+			service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+			result, err := service.SetPVZSchedule(context.Background(), newSchedule())
+
+			if tt.expectError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectError.Error(), err.Error())
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "Asia/Yekaterinburg", result.Timezone)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPVZService_GetPVZSchedule(t *testing.T) {
+	testPVZID := uuid.New()
+	schedule := &models.PVZSchedule{PVZID: testPVZID, Timezone: "Europe/Moscow"}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("CheckPVZ", mock.Anything, testPVZID).Return(true, nil)
+		mockRepo.On("GetPVZSchedule", mock.Anything, testPVZID).Return(schedule, nil)
+
+		service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+		result, err := service.GetPVZSchedule(context.Background(), testPVZID)
+		assert.NoError(t, err)
+		assert.Equal(t, schedule, result)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("No schedule", func(t *testing.T) {
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("CheckPVZ", mock.Anything, testPVZID).Return(true, nil)
+		mockRepo.On("GetPVZSchedule", mock.Anything, testPVZID).Return(nil, e.ErrNotFound())
+
+		service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+		_, err := service.GetPVZSchedule(context.Background(), testPVZID)
+		assert.Equal(t, e.ErrNotFound(), err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestPVZService_AllowReceptions(t *testing.T) {
+	testPVZID := uuid.New()
+	until := time.Now().Add(time.Hour)
+
+	t.Run("Success", func(t *testing.T) {
+		schedule := &models.PVZSchedule{PVZID: testPVZID, Timezone: "Europe/Moscow", ReceptionsAllowedUntil: &until}
+
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("AllowReceptionsUntil", mock.Anything, testPVZID, until).Return(nil)
+		mockRepo.On("GetPVZSchedule", mock.Anything, testPVZID).Return(schedule, nil)
+
+		service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+		result, err := service.AllowReceptions(context.Background(), testPVZID, until)
+		assert.NoError(t, err)
+		assert.Equal(t, schedule, result)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("No schedule", func(t *testing.T) {
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("AllowReceptionsUntil", mock.Anything, testPVZID, until).Return(e.ErrNotFound())
+
+		service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+		_, err := service.AllowReceptions(context.Background(), testPVZID, until)
+		assert.Equal(t, e.ErrNotFound(), err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestPVZService_DeletePVZSchedule(t *testing.T) {
+	testPVZID := uuid.New()
+
+	mockRepo := new(MockPVZRepository)
+	mockRepo.On("DeletePVZSchedule", mock.Anything, testPVZID).Return(e.ErrNotFound()).Once()
+	mockRepo.On("DeletePVZSchedule", mock.Anything, testPVZID).Return(nil).Once()
+
+	service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+	assert.Equal(t, e.ErrNotFound(), service.DeletePVZSchedule(context.Background(), testPVZID))
+	assert.NoError(t, service.DeletePVZSchedule(context.Background(), testPVZID))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPVZService_GetPVZOccupancy(t *testing.T) {
+	testPVZID := uuid.New()
+	occupancy := &models.PVZOccupancy{PVZID: testPVZID, Count: 3, Capacity: 10}
+
+	mockRepo := new(MockPVZRepository)
+	mockRepo.On("GetPVZOccupancy", mock.Anything, testPVZID).Return(nil, e.ErrNotFound()).Once()
+	mockRepo.On("GetPVZOccupancy", mock.Anything, testPVZID).Return(occupancy, nil).Once()
+
+	service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+
+	_, err := service.GetPVZOccupancy(context.Background(), testPVZID)
+	assert.Equal(t, e.ErrNotFound(), err)
+
+	result, err := service.GetPVZOccupancy(context.Background(), testPVZID)
+	assert.NoError(t, err)
+	assert.Equal(t, occupancy, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPVZService_SetTypeCapacities(t *testing.T) {
+	testPVZID := uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("CheckPVZ", mock.Anything, testPVZID).Return(true, nil)
+		mockRepo.On("GetProductTypeID", mock.Anything, "обувь").Return(3, nil)
+		mockRepo.On("ReplaceTypeCapacities", mock.Anything, testPVZID, []models.TypeCapacity{
+			{TypeID: 3, TypeName: "обувь", Capacity: 20},
+		}).Return(nil)
+
+		service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+		err := service.SetTypeCapacities(context.Background(), testPVZID, []models.TypeCapacity{
+			{TypeName: "обувь", Capacity: 20},
+		})
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("PVZ not found", func(t *testing.T) {
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("CheckPVZ", mock.Anything, testPVZID).Return(false, e.ErrNotFound())
+
+		service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+		err := service.SetTypeCapacities(context.Background(), testPVZID, nil)
+		assert.Equal(t, e.ErrNotFound(), err)
+		mockRepo.AssertNotCalled(t, "ReplaceTypeCapacities", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown product type", func(t *testing.T) {
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("CheckPVZ", mock.Anything, testPVZID).Return(true, nil)
+		mockRepo.On("GetProductTypeID", mock.Anything, "мебель").Return(0, e.ErrProductTypeNotAllowed())
+
+		service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+		err := service.SetTypeCapacities(context.Background(), testPVZID, []models.TypeCapacity{
+			{TypeName: "мебель", Capacity: 5},
+		})
+		assert.Equal(t, e.ErrProductTypeNotAllowed(), err)
+		mockRepo.AssertNotCalled(t, "ReplaceTypeCapacities", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
 func TestPVZService_StartReception(t *testing.T) {
 	testPVZID := uuid.New()
+	testPVZ := &models.PVZ{ID: testPVZID, CityName: "Москва", Status: models.PVZStatusActive, Version: 1}
 	testReception := &models.Reception{
 		ID:       uuid.New(),
 		PVZID:    testPVZID,
 		DateTime: time.Now(),
 		Status:   models.ReceptionStatusInProgress,
+		Version:  1,
+	}
+
+	alwaysOpen := &models.PVZSchedule{PVZID: testPVZID, Timezone: "Europe/Moscow"}
+	for _, weekday := range []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"} {
+		alwaysOpen.Weekly = append(alwaysOpen.Weekly, models.ScheduleDay{
+			Weekday:   weekday,
+			Intervals: []models.ScheduleInterval{{Opens: "00:00", Closes: "24:00"}},
+		})
 	}
+	// No working days at all
+	alwaysClosed := &models.PVZSchedule{PVZID: testPVZID, Timezone: "Europe/Moscow"}
 
 	tests := []struct {
 		name        string
@@ -194,7 +816,8 @@ func TestPVZService_StartReception(t *testing.T) {
 			name:  "Success",
 			pvzID: testPVZID,
 			mockSetup: func(m *MockPVZRepository) {
-				m.On("CheckPVZ", mock.Anything, testPVZID).Return(true, nil)
+				m.On("GetPVZ", mock.Anything, testPVZID).Return(testPVZ, nil)
+				m.On("GetPVZSchedule", mock.Anything, testPVZID).Return(nil, e.ErrNotFound())
 				m.On("GetActiveReception", mock.Anything, testPVZID).Return(nil, e.ErrNoActiveReception())
 				m.On("InsertReception", mock.Anything, mock.MatchedBy(func(r *models.Reception) bool {
 					return r.PVZID == testPVZID && r.Status == models.ReceptionStatusInProgress
@@ -202,19 +825,97 @@ func TestPVZService_StartReception(t *testing.T) {
 			},
 			expectError: nil,
 		},
+		{
+			name:  "Within working hours",
+			pvzID: testPVZID,
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("GetPVZ", mock.Anything, testPVZID).Return(testPVZ, nil)
+				m.On("GetPVZSchedule", mock.Anything, testPVZID).Return(alwaysOpen, nil)
+				m.On("GetActiveReception", mock.Anything, testPVZID).Return(nil, e.ErrNoActiveReception())
+				m.On("InsertReception", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectError: nil,
+		},
+		{
+			name:  "Outside working hours",
+			pvzID: testPVZID,
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("GetPVZ", mock.Anything, testPVZID).Return(testPVZ, nil)
+				m.On("GetPVZSchedule", mock.Anything, testPVZID).Return(alwaysClosed, nil)
+			},
+			expectError: e.ErrOutsideWorkingHours(),
+		},
+		{
+			name:  "Holiday exception overrides weekly hours",
+			pvzID: testPVZID,
+			mockSetup: func(m *MockPVZRepository) {
+				moscow, _ := time.LoadLocation("Europe/Moscow")
+				holiday := *alwaysOpen
+				holiday.Exceptions = []models.ScheduleException{
+					{Date: time.Now().In(moscow).Format(time.DateOnly), Note: "праздник"},
+				}
+				m.On("GetPVZ", mock.Anything, testPVZID).Return(testPVZ, nil)
+				m.On("GetPVZSchedule", mock.Anything, testPVZID).Return(&holiday, nil)
+			},
+			expectError: e.ErrOutsideWorkingHours(),
+		},
+		{
+			name:  "Allowed outside working hours",
+			pvzID: testPVZID,
+			mockSetup: func(m *MockPVZRepository) {
+				allowed := *alwaysClosed
+				until := time.Now().Add(time.Hour)
+				allowed.ReceptionsAllowedUntil = &until
+				m.On("GetPVZ", mock.Anything, testPVZID).Return(testPVZ, nil)
+				m.On("GetPVZSchedule", mock.Anything, testPVZID).Return(&allowed, nil)
+				m.On("GetActiveReception", mock.Anything, testPVZID).Return(nil, e.ErrNoActiveReception())
+				m.On("InsertReception", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectError: nil,
+		},
+		{
+			name:  "Allowance expired",
+			pvzID: testPVZID,
+			mockSetup: func(m *MockPVZRepository) {
+				allowed := *alwaysClosed
+				until := time.Now().Add(-time.Minute)
+				allowed.ReceptionsAllowedUntil = &until
+				m.On("GetPVZ", mock.Anything, testPVZID).Return(testPVZ, nil)
+				m.On("GetPVZSchedule", mock.Anything, testPVZID).Return(&allowed, nil)
+			},
+			expectError: e.ErrOutsideWorkingHours(),
+		},
+		{
+			name:  "Schedule lookup error",
+			pvzID: testPVZID,
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("GetPVZ", mock.Anything, testPVZID).Return(testPVZ, nil)
+				m.On("GetPVZSchedule", mock.Anything, testPVZID).Return(nil, errors.New("db error"))
+			},
+			expectError: errors.New("failed to get schedule: db error"),
+		},
 		{
 			name:  "PVZ not found",
 			pvzID: testPVZID,
 			mockSetup: func(m *MockPVZRepository) {
-				m.On("CheckPVZ", mock.Anything, testPVZID).Return(false, e.ErrNotFound())
+				m.On("GetPVZ", mock.Anything, testPVZID).Return(nil, e.ErrNotFound())
 			},
 			expectError: e.ErrCityNotAllowed(),
 		},
+		{
+			name:  "PVZ suspended",
+			pvzID: testPVZID,
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("GetPVZ", mock.Anything, testPVZID).Return(&models.PVZ{ID: testPVZID, Status: models.PVZStatusSuspended}, nil)
+			},
+			expectError: e.ErrPVZNotActive(),
+		},
 		{
 			name:  "Active reception exists",
 			pvzID: testPVZID,
 			mockSetup: func(m *MockPVZRepository) {
-				m.On("CheckPVZ", mock.Anything, testPVZID).Return(true, nil)
+				m.On("GetPVZ", mock.Anything, testPVZID).Return(testPVZ, nil)
+				m.On("GetPVZSchedule", mock.Anything, testPVZID).Return(nil, e.ErrNotFound())
 				m.On("GetActiveReception", mock.Anything, testPVZID).Return(testReception, nil)
 			},
 			expectError: e.ErrActiveReceptionExists(),
@@ -223,7 +924,8 @@ func TestPVZService_StartReception(t *testing.T) {
 			name:  "Insert error",
 			pvzID: testPVZID,
 			mockSetup: func(m *MockPVZRepository) {
-				m.On("CheckPVZ", mock.Anything, testPVZID).Return(true, nil)
+				m.On("GetPVZ", mock.Anything, testPVZID).Return(testPVZ, nil)
+				m.On("GetPVZSchedule", mock.Anything, testPVZID).Return(nil, e.ErrNotFound())
 				m.On("GetActiveReception", mock.Anything, testPVZID).Return(nil, e.ErrNoActiveReception())
 				m.On("InsertReception", mock.Anything, mock.Anything).Return(errors.New("insert error"))
 			},
@@ -236,8 +938,8 @@ func TestPVZService_StartReception(t *testing.T) {
 			mockRepo := new(MockPVZRepository)
 			tt.mockSetup(mockRepo)
 
-			service := NewPVZService(mockRepo, slog.Default())
-			result, err := service.StartReception(context.Background(), tt.pvzID)
+			service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+			result, err := service.StartReception(context.Background(), tt.pvzID, nil)
 
 			if tt.expectError != nil {
 				assert.Error(t, err)
@@ -262,6 +964,7 @@ func TestPVZService_AddProduct(t *testing.T) {
 		PVZID:    testPVZID,
 		DateTime: time.Now(),
 		Status:   models.ReceptionStatusInProgress,
+		Version:  1,
 	}
 	testProductType := "electronics"
 
@@ -282,7 +985,7 @@ func TestPVZService_AddProduct(t *testing.T) {
 				m.On("GetProductTypeID", mock.Anything, testProductType).Return(1, nil)
 				m.On("InsertProduct", mock.Anything, mock.MatchedBy(func(p *models.Product) bool {
 					return p.ReceptionID == testReception.ID && p.TypeName == testProductType && p.TypeID == 1
-				})).Return(nil)
+				}), 1).Return(2, nil)
 			},
 			expectError:   nil,
 			expectProduct: true,
@@ -308,6 +1011,46 @@ func TestPVZService_AddProduct(t *testing.T) {
 			expectError:   e.ErrProductTypeNotAllowed(),
 			expectProduct: false,
 		},
+		{
+			name:        "Concurrent change is retried without If-Match",
+			pvzID:       testPVZID,
+			productType: testProductType,
+			mockSetup: func(m *MockPVZRepository) {
+				changed := *testReception
+				changed.Version = 2
+				m.On("GetActiveReception", mock.Anything, testPVZID).Return(testReception, nil).Once()
+				m.On("GetActiveReception", mock.Anything, testPVZID).Return(&changed, nil).Once()
+				m.On("GetProductTypeID", mock.Anything, testProductType).Return(1, nil)
+				m.On("InsertProduct", mock.Anything, mock.Anything, 1).Return(0, e.ErrVersionMismatch()).Once()
+				m.On("InsertProduct", mock.Anything, mock.Anything, 2).Return(3, nil).Once()
+			},
+			expectError:   nil,
+			expectProduct: true,
+		},
+		{
+			name:        "Reception keeps changing concurrently",
+			pvzID:       testPVZID,
+			productType: testProductType,
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("GetActiveReception", mock.Anything, testPVZID).Return(testReception, nil).Times(unconditionalAttempts)
+				m.On("GetProductTypeID", mock.Anything, testProductType).Return(1, nil)
+				m.On("InsertProduct", mock.Anything, mock.Anything, 1).Return(0, e.ErrVersionMismatch()).Times(unconditionalAttempts)
+			},
+			expectError:   e.ErrVersionMismatch(),
+			expectProduct: false,
+		},
+		{
+			name:        "PVZ is full",
+			pvzID:       testPVZID,
+			productType: testProductType,
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("GetActiveReception", mock.Anything, testPVZID).Return(testReception, nil)
+				m.On("GetProductTypeID", mock.Anything, testProductType).Return(1, nil)
+				m.On("InsertProduct", mock.Anything, mock.Anything, 1).Return(0, e.ErrCapacityExceeded())
+			},
+			expectError:   e.ErrCapacityExceeded(),
+			expectProduct: false,
+		},
 		{
 			name:        "Insert error",
 			pvzID:       testPVZID,
@@ -315,7 +1058,7 @@ func TestPVZService_AddProduct(t *testing.T) {
 			mockSetup: func(m *MockPVZRepository) {
 				m.On("GetActiveReception", mock.Anything, testPVZID).Return(testReception, nil)
 				m.On("GetProductTypeID", mock.Anything, testProductType).Return(1, nil)
-				m.On("InsertProduct", mock.Anything, mock.Anything).Return(errors.New("insert error"))
+				m.On("InsertProduct", mock.Anything, mock.Anything, 1).Return(0, errors.New("insert error"))
 			},
 			expectError:   errors.New("failed to add product: insert error"),
 			expectProduct: false,
@@ -327,8 +1070,8 @@ func TestPVZService_AddProduct(t *testing.T) {
 			mockRepo := new(MockPVZRepository)
 			tt.mockSetup(mockRepo)
 
-			service := NewPVZService(mockRepo, slog.Default())
-			product, err := service.AddProduct(context.Background(), tt.pvzID, tt.productType)
+			service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+			product, _, err := service.AddProduct(context.Background(), tt.pvzID, tt.productType, "", nil)
 
 			if tt.expectError != nil {
 				assert.Error(t, err)
@@ -354,6 +1097,7 @@ func TestPVZService_DeleteLastProduct(t *testing.T) {
 		PVZID:    testPVZID,
 		DateTime: time.Now(),
 		Status:   models.ReceptionStatusInProgress,
+		Version:  1,
 	}
 	testProduct := &models.Product{
 		ID:          uuid.New(),
@@ -371,8 +1115,8 @@ func TestPVZService_DeleteLastProduct(t *testing.T) {
 			pvzID: testPVZID,
 			mockSetup: func(m *MockPVZRepository) {
 				m.On("GetActiveReception", mock.Anything, testPVZID).Return(testReception, nil)
-				m.On("GetLastProduct", mock.Anything, testReception.ID).Return(testProduct, nil)
-				m.On("DeleteProduct", mock.Anything, testProduct.ID).Return(nil)
+				m.On("GetLastProduct", mock.Anything, testReception.ID, testReception.DateTime).Return(testProduct, nil)
+				m.On("DeleteProduct", mock.Anything, testProduct.ID, 1).Return(2, "", nil)
 			},
 			expectError: nil,
 		},
@@ -389,7 +1133,7 @@ func TestPVZService_DeleteLastProduct(t *testing.T) {
 			pvzID: testPVZID,
 			mockSetup: func(m *MockPVZRepository) {
 				m.On("GetActiveReception", mock.Anything, testPVZID).Return(testReception, nil)
-				m.On("GetLastProduct", mock.Anything, testReception.ID).Return(nil, e.ErrNotFound())
+				m.On("GetLastProduct", mock.Anything, testReception.ID, testReception.DateTime).Return(nil, e.ErrNotFound())
 			},
 			expectError: e.ErrNoProduct(),
 		},
@@ -398,8 +1142,8 @@ func TestPVZService_DeleteLastProduct(t *testing.T) {
 			pvzID: testPVZID,
 			mockSetup: func(m *MockPVZRepository) {
 				m.On("GetActiveReception", mock.Anything, testPVZID).Return(testReception, nil)
-				m.On("GetLastProduct", mock.Anything, testReception.ID).Return(testProduct, nil)
-				m.On("DeleteProduct", mock.Anything, testProduct.ID).Return(errors.New("delete error"))
+				m.On("GetLastProduct", mock.Anything, testReception.ID, testReception.DateTime).Return(testProduct, nil)
+				m.On("DeleteProduct", mock.Anything, testProduct.ID, 1).Return(0, "", errors.New("delete error"))
 			},
 			expectError: errors.New("failed to delete product: delete error"),
 		},
@@ -410,8 +1154,8 @@ func TestPVZService_DeleteLastProduct(t *testing.T) {
 			mockRepo := new(MockPVZRepository)
 			tt.mockSetup(mockRepo)
 
-			service := NewPVZService(mockRepo, slog.Default())
-			err := service.DeleteLastProduct(context.Background(), tt.pvzID)
+			service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+			_, err := service.DeleteLastProduct(context.Background(), tt.pvzID, nil)
 
 			if tt.expectError != nil {
 				assert.Error(t, err)
@@ -425,29 +1169,87 @@ func TestPVZService_DeleteLastProduct(t *testing.T) {
 	}
 }
 
+func TestPVZService_DeleteLastProductRemovesPhoto(t *testing.T) {
+	productID := uuid.New()
+	photos := storage.NewLocalStorage(t.TempDir())
+	ctx := context.Background()
+	assert.NoError(t, photos.Put(ctx, "products/deleted.jpg", []byte("jpeg"), "image/jpeg"))
+
+	pvzID := uuid.New()
+	reception := &models.Reception{ID: uuid.New(), PVZID: pvzID, Status: models.ReceptionStatusInProgress, Version: 2}
+	mockRepo := new(MockPVZRepository)
+	mockRepo.On("GetActiveReception", mock.Anything, pvzID).Return(reception, nil)
+	mockRepo.On("GetLastProduct", mock.Anything, reception.ID, reception.DateTime).Return(&models.Product{ID: productID, Status: models.ProductStatusAccepted}, nil)
+	mockRepo.On("DeleteProduct", mock.Anything, productID, 2).Return(3, "products/deleted.jpg", nil)
+
+	service := NewPVZService(mockRepo, photos, eventbus.NewBus(), slog.Default())
+	_, err := service.DeleteLastProduct(ctx, pvzID, nil)
+	assert.NoError(t, err)
+
+	_, _, err = photos.Get(ctx, "products/deleted.jpg")
+	assert.Equal(t, e.ErrNotFound(), err)
+}
+
 func TestPVZService_CloseReception(t *testing.T) {
 	testPVZID := uuid.New()
-	testReception := &models.Reception{
-		ID:       uuid.New(),
-		PVZID:    testPVZID,
-		DateTime: time.Now(),
-		Status:   models.ReceptionStatusInProgress,
+	receptionID := uuid.New()
+	newReception := func() *models.Reception {
+		return &models.Reception{
+			ID:       receptionID,
+			PVZID:    testPVZID,
+			DateTime: time.Now(),
+			Status:   models.ReceptionStatusInProgress,
+			Version:  1,
+		}
 	}
 
 	tests := []struct {
-		name        string
-		pvzID       uuid.UUID
-		mockSetup   func(*MockPVZRepository)
-		expectError error
+		name          string
+		pvzID         uuid.UUID
+		ifMatch       *models.ETag
+		mockSetup     func(*MockPVZRepository)
+		expectError   error
+		expectVersion int
 	}{
 		{
 			name:  "Success",
 			pvzID: testPVZID,
 			mockSetup: func(m *MockPVZRepository) {
-				m.On("GetActiveReception", mock.Anything, testPVZID).Return(testReception, nil)
-				m.On("UpdateReceptionStatus", mock.Anything, testReception.ID, models.ReceptionStatusClose).Return(nil)
+				m.On("GetActiveReception", mock.Anything, testPVZID).Return(newReception(), nil)
+				m.On("UpdateReceptionStatus", mock.Anything, receptionID, models.ReceptionStatusClose, 1).Return(2, nil)
 			},
-			expectError: nil,
+			expectError:   nil,
+			expectVersion: 2,
+		},
+		{
+			name:    "Success with If-Match",
+			pvzID:   testPVZID,
+			ifMatch: &models.ETag{ID: receptionID, Version: 1},
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("GetActiveReception", mock.Anything, testPVZID).Return(newReception(), nil)
+				m.On("UpdateReceptionStatus", mock.Anything, receptionID, models.ReceptionStatusClose, 1).Return(2, nil)
+			},
+			expectError:   nil,
+			expectVersion: 2,
+		},
+		{
+			name:    "Stale If-Match",
+			pvzID:   testPVZID,
+			ifMatch: &models.ETag{ID: receptionID, Version: 5},
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("GetActiveReception", mock.Anything, testPVZID).Return(newReception(), nil)
+				m.On("UpdateReceptionStatus", mock.Anything, receptionID, models.ReceptionStatusClose, 5).Return(0, e.ErrVersionMismatch())
+			},
+			expectError: e.ErrVersionMismatch(),
+		},
+		{
+			name:    "If-Match for another reception",
+			pvzID:   testPVZID,
+			ifMatch: &models.ETag{ID: uuid.New(), Version: 1},
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("GetActiveReception", mock.Anything, testPVZID).Return(newReception(), nil)
+			},
+			expectError: e.ErrVersionMismatch(),
 		},
 		{
 			name:  "No active reception",
@@ -461,8 +1263,8 @@ func TestPVZService_CloseReception(t *testing.T) {
 			name:  "Update error",
 			pvzID: testPVZID,
 			mockSetup: func(m *MockPVZRepository) {
-				m.On("GetActiveReception", mock.Anything, testPVZID).Return(testReception, nil)
-				m.On("UpdateReceptionStatus", mock.Anything, testReception.ID, models.ReceptionStatusClose).Return(errors.New("update error"))
+				m.On("GetActiveReception", mock.Anything, testPVZID).Return(newReception(), nil)
+				m.On("UpdateReceptionStatus", mock.Anything, receptionID, models.ReceptionStatusClose, 1).Return(0, errors.New("update error"))
 			},
 			expectError: errors.New("failed to close reception: update error"),
 		},
@@ -473,8 +1275,8 @@ func TestPVZService_CloseReception(t *testing.T) {
 			mockRepo := new(MockPVZRepository)
 			tt.mockSetup(mockRepo)
 
-			service := NewPVZService(mockRepo, slog.Default())
-			result, err := service.CloseReception(context.Background(), tt.pvzID)
+			service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+			result, err := service.CloseReception(context.Background(), tt.pvzID, tt.ifMatch)
 
 			if tt.expectError != nil {
 				assert.Error(t, err)
@@ -484,6 +1286,7 @@ func TestPVZService_CloseReception(t *testing.T) {
 				assert.NoError(t, err)
 				assert.NotNil(t, result)
 				assert.Equal(t, models.ReceptionStatusClose, result.Status)
+				assert.Equal(t, tt.expectVersion, result.Version)
 			}
 
 			mockRepo.AssertExpectations(t)
@@ -532,7 +1335,7 @@ func TestPVZService_GetPVZsWithReceptions(t *testing.T) {
 			mockSetup: func(m *MockPVZRepository) {
 				m.On("GetPVZs", mock.Anything, mock.Anything, mock.Anything, 10, 0).Return([]models.PVZ{testPVZ}, nil)
 				m.On("GetReceptionsForPVZs", mock.Anything, []uuid.UUID{testPVZ.ID}, mock.Anything, mock.Anything).Return([]models.Reception{testReception}, nil)
-				m.On("GetProductsForReceptions", mock.Anything, []uuid.UUID{testReception.ID}).Return([]models.Product{testProduct}, nil)
+				m.On("GetProductsForReceptions", mock.Anything, []uuid.UUID{testReception.ID}, testReception.DateTime).Return([]models.Product{testProduct}, nil)
 			},
 			expectError:   nil,
 			expectResults: 1,
@@ -568,7 +1371,7 @@ func TestPVZService_GetPVZsWithReceptions(t *testing.T) {
 			mockRepo := new(MockPVZRepository)
 			tt.mockSetup(mockRepo)
 
-			service := NewPVZService(mockRepo, slog.Default())
+			service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
 			result, err := service.GetPVZsWithReceptions(context.Background(), tt.from, tt.to, tt.page, tt.limit)
 
 			if tt.expectError != nil {
@@ -651,7 +1454,7 @@ func TestPVZService_GetPVZs(t *testing.T) {
 			mockRepo := new(MockPVZRepository)
 			tt.mockSetup(mockRepo)
 
-			service := NewPVZService(mockRepo, slog.Default())
+			service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
 			result, err := service.GetPVZs(context.Background())
 
 			if tt.expectError != nil {
@@ -674,3 +1477,486 @@ func TestPVZService_GetPVZs(t *testing.T) {
 		})
 	}
 }
+
+func TestPVZService_GetNearbyPVZs(t *testing.T) {
+	point := models.GeoPoint{Latitude: 55.7558, Longitude: 37.6173}
+	nearby := []models.NearbyPVZ{
+		{PVZ: models.PVZ{ID: uuid.New(), CityName: "Москва"}, Distance: 120},
+		{PVZ: models.PVZ{ID: uuid.New(), CityName: "Москва"}, Distance: 840},
+	}
+
+	tests := []struct {
+		name         string
+		mockSetup    func(*MockPVZRepository)
+		expectError  error
+		expectResult []models.NearbyPVZ
+	}{
+		{
+			name: "Success",
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("GetNearbyPVZs", mock.Anything, point, 1000.0, 20).Return(nearby, nil)
+			},
+			expectResult: nearby,
+		},
+		{
+			name: "Repository error",
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("GetNearbyPVZs", mock.Anything, point, 1000.0, 20).Return(nil, errors.New("repository error"))
+			},
+			expectError: errors.New("failed to get nearby PVZs: repository error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockPVZRepository)
+			tt.mockSetup(mockRepo)
+
+			service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+			result, err := service.GetNearbyPVZs(context.Background(), point, 1000, 20)
+
+			if tt.expectError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectError.Error(), err.Error())
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectResult, result)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPVZService_StreamPVZs(t *testing.T) {
+	now := time.Now()
+	filter := models.PVZFilter{
+		Cities: []string{"Москва"},
+		From:   now.Add(-24 * time.Hour),
+		To:     now.Add(24 * time.Hour),
+	}
+	testPVZ := models.PVZ{
+		ID:               uuid.New(),
+		RegistrationDate: now,
+		CityName:         "Москва",
+		CityID:           1,
+	}
+	testReception := models.Reception{
+		ID:       uuid.New(),
+		PVZID:    testPVZ.ID,
+		DateTime: now,
+		Status:   models.ReceptionStatusInProgress,
+		Version:  1,
+	}
+	testProduct := models.Product{
+		ID:          uuid.New(),
+		ReceptionID: testReception.ID,
+		TypeName:    "обувь",
+		DateTime:    now,
+		TypeID:      3,
+	}
+
+	tests := []struct {
+		name             string
+		withReceptions   bool
+		withProducts     bool
+		mockSetup        func(*MockPVZRepository)
+		expectError      error
+		expectResults    int
+		expectReceptions int
+		expectProducts   int
+	}{
+		{
+			name:           "PVZs only",
+			withReceptions: false,
+			withProducts:   true,
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("IteratePVZs", mock.Anything, filter).Return([]models.PVZ{testPVZ}, nil)
+			},
+			expectResults: 1,
+		},
+		{
+			name:           "With receptions",
+			withReceptions: true,
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("IteratePVZs", mock.Anything, filter).Return([]models.PVZ{testPVZ}, nil)
+				m.On("GetReceptionsForPVZs", mock.Anything, []uuid.UUID{testPVZ.ID}, filter.From, filter.To).Return([]models.Reception{testReception}, nil)
+			},
+			expectResults:    1,
+			expectReceptions: 1,
+		},
+		{
+			name:           "With receptions and products",
+			withReceptions: true,
+			withProducts:   true,
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("IteratePVZs", mock.Anything, filter).Return([]models.PVZ{testPVZ}, nil)
+				m.On("GetReceptionsForPVZs", mock.Anything, []uuid.UUID{testPVZ.ID}, filter.From, filter.To).Return([]models.Reception{testReception}, nil)
+				m.On("GetProductsForReceptions", mock.Anything, []uuid.UUID{testReception.ID}, testReception.DateTime).Return([]models.Product{testProduct}, nil)
+			},
+			expectResults:    1,
+			expectReceptions: 1,
+			expectProducts:   1,
+		},
+		{
+			name:           "Iterate error",
+			withReceptions: true,
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("IteratePVZs", mock.Anything, filter).Return(nil, errors.New("iterate error"))
+			},
+			expectError: errors.New("failed to stream PVZs: iterate error"),
+		},
+		{
+			name:           "Receptions error",
+			withReceptions: true,
+			mockSetup: func(m *MockPVZRepository) {
+				m.On("IteratePVZs", mock.Anything, filter).Return([]models.PVZ{testPVZ}, nil)
+				m.On("GetReceptionsForPVZs", mock.Anything, []uuid.UUID{testPVZ.ID}, filter.From, filter.To).Return([]models.Reception(nil), errors.New("get error"))
+			},
+			expectError: errors.New("failed to stream PVZs: failed to get receptions: get error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockPVZRepository)
+			tt.mockSetup(mockRepo)
+
+			service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+
+			var result []models.PVZInfo
+			err := service.StreamPVZs(context.Background(), filter, tt.withReceptions, tt.withProducts, func(info models.PVZInfo) error {
+				result = append(result, info)
+				return nil
+			})
+
+			if tt.expectError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectResults, len(result))
+
+				if tt.expectResults > 0 {
+					assert.Equal(t, testPVZ.ID, result[0].PVZ.ID)
+					assert.Equal(t, tt.expectReceptions, len(result[0].Receptions))
+					if tt.expectReceptions > 0 {
+						assert.Equal(t, tt.expectProducts, len(result[0].Receptions[0].Products))
+					}
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPVZService_StreamPVZs_Batches(t *testing.T) {
+	pvzs := make([]models.PVZ, pvzStreamBatchSize+1)
+	for i := range pvzs {
+		pvzs[i] = models.PVZ{ID: uuid.New(), CityName: "Казань"}
+	}
+
+	mockRepo := new(MockPVZRepository)
+	mockRepo.On("IteratePVZs", mock.Anything, models.PVZFilter{}).Return(pvzs, nil)
+	mockRepo.On("GetReceptionsForPVZs", mock.Anything, mock.Anything, time.Time{}, mock.AnythingOfType("time.Time")).Return([]models.Reception{}, nil).Twice()
+
+	service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+
+	sent := 0
+	err := service.StreamPVZs(context.Background(), models.PVZFilter{}, true, true, func(models.PVZInfo) error {
+		sent++
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, len(pvzs), sent)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPVZService_PublishesEvents(t *testing.T) {
+	pvzID := uuid.New()
+	reception := &models.Reception{ID: uuid.New(), PVZID: pvzID, Status: models.ReceptionStatusInProgress}
+	product := &models.Product{ID: uuid.New(), ReceptionID: reception.ID, TypeName: "обувь"}
+
+	mockRepo := new(MockPVZRepository)
+	mockRepo.On("CheckPVZ", mock.Anything, pvzID).Return(true, nil)
+	mockRepo.On("GetPVZ", mock.Anything, pvzID).Return(&models.PVZ{ID: pvzID, Status: models.PVZStatusActive}, nil)
+	mockRepo.On("GetPVZSchedule", mock.Anything, pvzID).Return(nil, e.ErrNotFound())
+	mockRepo.On("GetActiveReception", mock.Anything, pvzID).Return(nil, e.ErrNoActiveReception()).Once()
+	mockRepo.On("InsertReception", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("GetActiveReception", mock.Anything, pvzID).Return(reception, nil)
+	mockRepo.On("GetProductTypeID", mock.Anything, "обувь").Return(1, nil)
+	mockRepo.On("InsertProduct", mock.Anything, mock.Anything, mock.Anything).Return(2, nil)
+	mockRepo.On("GetLastProduct", mock.Anything, reception.ID, reception.DateTime).Return(product, nil)
+	mockRepo.On("DeleteProduct", mock.Anything, product.ID, mock.Anything).Return(3, "", nil)
+	mockRepo.On("UpdateReceptionStatus", mock.Anything, reception.ID, models.ReceptionStatusClose, mock.Anything).Return(4, nil)
+
+	service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+
+	events, unsubscribe, err := service.SubscribeEvents(context.Background(), pvzID)
+	assert.NoError(t, err)
+	defer unsubscribe()
+
+	_, err = service.StartReception(context.Background(), pvzID, nil)
+	assert.NoError(t, err)
+	_, _, err = service.AddProduct(context.Background(), pvzID, "обувь", "", nil)
+	assert.NoError(t, err)
+	_, err = service.DeleteLastProduct(context.Background(), pvzID, nil)
+	assert.NoError(t, err)
+	_, err = service.CloseReception(context.Background(), pvzID, nil)
+	assert.NoError(t, err)
+
+	expected := []models.EventType{
+		models.EventReceptionOpened,
+		models.EventProductAdded,
+		models.EventProductDeleted,
+		models.EventReceptionClosed,
+	}
+	for _, eventType := range expected {
+		event := <-events
+		assert.Equal(t, eventType, event.Type)
+		assert.Equal(t, pvzID, event.PVZID)
+		assert.NotEmpty(t, event.Payload)
+	}
+	assert.Len(t, events, 0)
+}
+
+func TestPVZService_SubscribeEvents(t *testing.T) {
+	tests := []struct {
+		name        string
+		checkErr    error
+		expectError error
+	}{
+		{name: "Success"},
+		{name: "PVZ not found", checkErr: e.ErrNotFound(), expectError: e.ErrNotFound()},
+		{name: "Check error", checkErr: errors.New("db error"), expectError: errors.New("failed to check PVZ: db error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pvzID := uuid.New()
+			mockRepo := new(MockPVZRepository)
+			mockRepo.On("CheckPVZ", mock.Anything, pvzID).Return(tt.checkErr == nil, tt.checkErr)
+
+			service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+			events, unsubscribe, err := service.SubscribeEvents(context.Background(), pvzID)
+
+			if tt.expectError != nil {
+				assert.Equal(t, tt.expectError.Error(), err.Error())
+				assert.Nil(t, events)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, events)
+				unsubscribe()
+			}
+		})
+	}
+}
+
+func TestPVZService_ChangeProductStatus(t *testing.T) {
+	productID := uuid.New()
+	receptionID := uuid.New()
+	pvzID := uuid.New()
+
+	product := func(status models.ProductStatus) *models.Product {
+		return &models.Product{ID: productID, TypeName: "одежда", ReceptionID: receptionID, Status: status}
+	}
+
+	type changeFunc func(s *PVZService) (*models.Product, error)
+	issue := func(s *PVZService) (*models.Product, error) {
+		return s.IssueProduct(context.Background(), productID, "")
+	}
+	giveBack := func(s *PVZService) (*models.Product, error) {
+		return s.ReturnProduct(context.Background(), productID, "брак")
+	}
+	writeOff := func(s *PVZService) (*models.Product, error) {
+		return s.WriteOffProduct(context.Background(), productID, "")
+	}
+
+	tests := []struct {
+		name        string
+		current     models.ProductStatus
+		change      changeFunc
+		expected    models.ProductStatus
+		expectError error
+	}{
+		{name: "Issue stored product", current: models.ProductStatusStored, change: issue, expected: models.ProductStatusIssued},
+		{name: "Return issued product", current: models.ProductStatusIssued, change: giveBack, expected: models.ProductStatusReturned},
+		{name: "Write off returned product", current: models.ProductStatusReturned, change: writeOff, expected: models.ProductStatusWrittenOff},
+		{name: "Write off accepted product", current: models.ProductStatusAccepted, change: writeOff, expected: models.ProductStatusWrittenOff},
+		{name: "Issue product of open reception", current: models.ProductStatusAccepted, change: issue, expectError: e.ErrInvalidStatusTransition()},
+		{name: "Issue product twice", current: models.ProductStatusIssued, change: issue, expectError: e.ErrInvalidStatusTransition()},
+		{name: "Return stored product", current: models.ProductStatusStored, change: giveBack, expectError: e.ErrInvalidStatusTransition()},
+		{name: "Write off issued product", current: models.ProductStatusIssued, change: writeOff, expectError: e.ErrInvalidStatusTransition()},
+		{name: "Issue written off product", current: models.ProductStatusWrittenOff, change: issue, expectError: e.ErrInvalidStatusTransition()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockPVZRepository)
+			mockRepo.On("GetProduct", mock.Anything, productID).Return(product(tt.current), nil)
+			if tt.expectError == nil {
+				mockRepo.On("ChangeProductStatus", mock.Anything, mock.MatchedBy(func(c *models.ProductStatusChange) bool {
+					return c.ProductID == productID && c.From == tt.current && c.To == tt.expected
+				})).Run(func(args mock.Arguments) {
+					change := args.Get(1).(*models.ProductStatusChange)
+					change.PVZID = pvzID
+					change.ReceptionID = receptionID
+				}).Return(nil)
+			}
+
+			bus := eventbus.NewBus()
+			events, unsubscribe := bus.Subscribe(pvzID)
+			defer unsubscribe()
+
+			result, err := tt.change(NewPVZService(mockRepo, nil, bus, slog.Default()))
+			if tt.expectError != nil {
+				assert.Equal(t, tt.expectError, err)
+				assert.Nil(t, result)
+				mockRepo.AssertNotCalled(t, "ChangeProductStatus", mock.Anything, mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Status)
+
+			select {
+			case event := <-events:
+				assert.Equal(t, models.EventProductStatusChanged, event.Type)
+				assert.Equal(t, receptionID, event.ReceptionID)
+			case <-time.After(time.Second):
+				t.Fatal("status change event was not published")
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Product not found", func(t *testing.T) {
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("GetProduct", mock.Anything, productID).Return(nil, e.ErrNotFound())
+
+		_, err := issue(NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default()))
+		assert.Equal(t, e.ErrNotFound(), err)
+	})
+
+	t.Run("Status changed concurrently", func(t *testing.T) {
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("GetProduct", mock.Anything, productID).Return(product(models.ProductStatusStored), nil)
+		mockRepo.On("ChangeProductStatus", mock.Anything, mock.Anything).Return(e.ErrInvalidStatusTransition())
+
+		_, err := issue(NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default()))
+		assert.Equal(t, e.ErrInvalidStatusTransition(), err)
+	})
+}
+
+func TestPVZService_GetProductHistory(t *testing.T) {
+	productID := uuid.New()
+	history := []models.ProductStatusChange{
+		{ProductID: productID, To: models.ProductStatusAccepted},
+		{ProductID: productID, From: models.ProductStatusAccepted, To: models.ProductStatusStored},
+	}
+
+	mockRepo := new(MockPVZRepository)
+	mockRepo.On("GetProduct", mock.Anything, productID).Return(nil, e.ErrNotFound()).Once()
+	mockRepo.On("GetProduct", mock.Anything, productID).Return(&models.Product{ID: productID}, nil).Once()
+	mockRepo.On("GetProductHistory", mock.Anything, productID).Return(history, nil).Once()
+
+	service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+
+	_, err := service.GetProductHistory(context.Background(), productID)
+	assert.Equal(t, e.ErrNotFound(), err)
+
+	result, err := service.GetProductHistory(context.Background(), productID)
+	assert.NoError(t, err)
+	assert.Equal(t, history, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPVZService_ReceptionWithManifest(t *testing.T) {
+	testPVZID := uuid.New()
+	manifestID := uuid.New()
+	receptionID := uuid.New()
+	testPVZ := &models.PVZ{ID: testPVZID, Status: models.PVZStatusActive, Version: 1}
+
+	t.Run("Start links manifest", func(t *testing.T) {
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("GetPVZ", mock.Anything, testPVZID).Return(testPVZ, nil)
+		mockRepo.On("GetPVZSchedule", mock.Anything, testPVZID).Return(nil, e.ErrNotFound())
+		mockRepo.On("GetActiveReception", mock.Anything, testPVZID).Return(nil, e.ErrNoActiveReception())
+		mockRepo.On("InsertReception", mock.Anything, mock.MatchedBy(func(r *models.Reception) bool {
+			return r.ManifestID != nil && *r.ManifestID == manifestID
+		})).Return(nil)
+
+		service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+		reception, err := service.StartReception(context.Background(), testPVZID, &manifestID)
+		assert.NoError(t, err)
+		assert.Equal(t, manifestID, *reception.ManifestID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Start with unavailable manifest", func(t *testing.T) {
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("GetPVZ", mock.Anything, testPVZID).Return(testPVZ, nil)
+		mockRepo.On("GetPVZSchedule", mock.Anything, testPVZID).Return(nil, e.ErrNotFound())
+		mockRepo.On("GetActiveReception", mock.Anything, testPVZID).Return(nil, e.ErrNoActiveReception())
+		mockRepo.On("InsertReception", mock.Anything, mock.Anything).Return(e.ErrManifestNotAvailable())
+
+		service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+		reception, err := service.StartReception(context.Background(), testPVZID, &manifestID)
+		assert.Equal(t, e.ErrManifestNotAvailable(), err)
+		assert.Nil(t, reception)
+	})
+
+	t.Run("Close returns discrepancies", func(t *testing.T) {
+		report := &models.DiscrepancyReport{
+			Expected:   2,
+			Received:   1,
+			Missing:    []models.ManifestItem{{Barcode: "4600000000024", TypeName: "одежда"}},
+			Unexpected: []string{},
+			Duplicates: []models.DuplicateItem{},
+		}
+
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("GetActiveReception", mock.Anything, testPVZID).Return(&models.Reception{
+			ID:         receptionID,
+			PVZID:      testPVZID,
+			Status:     models.ReceptionStatusInProgress,
+			Version:    3,
+			ManifestID: &manifestID,
+		}, nil)
+		mockRepo.On("UpdateReceptionStatus", mock.Anything, receptionID, models.ReceptionStatusClose, 3).Return(4, nil)
+		mockRepo.On("GetManifest", mock.Anything, manifestID).Return(&models.Manifest{
+			ID:     manifestID,
+			Status: models.ManifestStatusReconciled,
+			Report: report,
+		}, nil)
+
+		service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+		reception, err := service.CloseReception(context.Background(), testPVZID, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, report, reception.Discrepancies)
+		assert.True(t, reception.Discrepancies.HasDiscrepancies())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Close succeeds when report cannot be loaded", func(t *testing.T) {
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("GetActiveReception", mock.Anything, testPVZID).Return(&models.Reception{
+			ID:         receptionID,
+			PVZID:      testPVZID,
+			Status:     models.ReceptionStatusInProgress,
+			Version:    1,
+			ManifestID: &manifestID,
+		}, nil)
+		mockRepo.On("UpdateReceptionStatus", mock.Anything, receptionID, models.ReceptionStatusClose, 1).Return(2, nil)
+		mockRepo.On("GetManifest", mock.Anything, manifestID).Return(nil, errors.New("db error"))
+
+		service := NewPVZService(mockRepo, nil, eventbus.NewBus(), slog.Default())
+		reception, err := service.CloseReception(context.Background(), testPVZID, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, models.ReceptionStatusClose, reception.Status)
+		assert.Nil(t, reception.Discrepancies)
+	})
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/eventbus"
+	"pvz-service/internal/logger/sl"
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StaleReceptionService struct {
+	repo repository.PVZRepository
+	bus  *eventbus.Bus
+	log  *slog.Logger
+}
+
+func NewStaleReceptionService(repo repository.PVZRepository, bus *eventbus.Bus, log *slog.Logger) *StaleReceptionService {
+	return &StaleReceptionService{repo: repo, bus: bus, log: log}
+}
+
+type StaleReceptionServiceInterface interface {
+	ForceCloseReception(ctx context.Context, pvzID uuid.UUID, actor, reason string) (*models.Reception, error)
+	CloseStaleReceptions(ctx context.Context, idleTimeout time.Duration, flagOnly bool) (int, error)
+	GetPVZReceptionAudit(ctx context.Context, pvzID uuid.UUID) ([]models.ReceptionAuditEntry, error)
+}
+
+// ForceCloseReception closes the active reception of the PVZ on behalf of a
+// moderator regardless of the employee's version, recording actor and reason
+// in the reception audit
+func (s *StaleReceptionService) ForceCloseReception(ctx context.Context, pvzID uuid.UUID, actor, reason string) (*models.Reception, error) {
+	for attempt := 1; ; attempt++ {
+		reception, err := s.forceCloseReception(ctx, pvzID, actor, reason)
+		if !retryUnconditional(err, nil, attempt) {
+			return reception, err
+		}
+	}
+}
+
+// forceCloseReception is a single attempt of ForceCloseReception
+func (s *StaleReceptionService) forceCloseReception(ctx context.Context, pvzID uuid.UUID, actor, reason string) (*models.Reception, error) {
+	const op = "service.stale_reception_service.ForceCloseReception"
+
+	reception, err := s.repo.GetActiveReception(ctx, pvzID)
+	if err != nil {
+		if err == e.ErrNoActiveReception() {
+			s.log.Info(fmt.Sprintf("%s: no active reception", op), "pvzID", pvzID)
+			return nil, e.ErrNoActiveReception()
+		}
+		s.log.Error(fmt.Sprintf("%s: failed to get active reception", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to get active reception: %w", err)
+	}
+
+	newVersion, err := s.repo.ForceCloseReception(ctx, reception.ID, reception.Version, &models.ReceptionAuditEntry{
+		Action:    models.ReceptionAuditForceClosed,
+		Actor:     actor,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	})
+	if err == e.ErrVersionMismatch() {
+		s.log.Info(fmt.Sprintf("%s: reception was modified concurrently", op), "receptionID", reception.ID)
+		return nil, e.ErrVersionMismatch()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to close reception", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to close reception: %w", err)
+	}
+
+	s.log.Info(fmt.Sprintf("%s: reception force closed", op), "receptionID", reception.ID, "actor", actor)
+	receptionClosed(ctx, s.repo, s.bus, s.log, op, reception, newVersion)
+
+	return reception, nil
+}
+
+// CloseStaleReceptions closes the receptions that got no new products for
+// idleTimeout and returns how many were closed. With flagOnly it leaves them
+// open and records each one in the audit once instead, returning how many
+// were flagged. Receptions that change meanwhile are skipped until the next
+// run.
+func (s *StaleReceptionService) CloseStaleReceptions(ctx context.Context, idleTimeout time.Duration, flagOnly bool) (int, error) {
+	const op = "service.stale_reception_service.CloseStaleReceptions"
+
+	now := time.Now()
+	stale, err := s.repo.GetStaleReceptions(ctx, now.Add(-idleTimeout))
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to get stale receptions", op), sl.Err(err))
+		return 0, fmt.Errorf("failed to get stale receptions: %w", err)
+	}
+
+	handled := 0
+	for _, reception := range stale {
+		entry := &models.ReceptionAuditEntry{
+			ReceptionID: reception.ID,
+			PVZID:       reception.PVZID,
+			Actor:       models.ReceptionAuditActorSystem,
+			Reason:      fmt.Sprintf("no activity since %s", reception.LastActivity.UTC().Format(time.RFC3339)),
+			CreatedAt:   now,
+		}
+
+		if flagOnly {
+			if reception.Flagged {
+				continue
+			}
+
+			entry.Action = models.ReceptionAuditFlaggedStale
+			if err := s.repo.InsertReceptionAudit(ctx, entry); err != nil {
+				s.log.Error(fmt.Sprintf("%s: failed to flag reception", op), "receptionID", reception.ID, sl.Err(err))
+				continue
+			}
+			s.log.Info(fmt.Sprintf("%s: reception flagged as stale", op), "receptionID", reception.ID, "pvzID", reception.PVZID)
+			handled++
+			continue
+		}
+
+		entry.Action = models.ReceptionAuditAutoClosed
+		newVersion, err := s.repo.ForceCloseReception(ctx, reception.ID, reception.Version, entry)
+		if err == e.ErrVersionMismatch() {
+			s.log.Info(fmt.Sprintf("%s: reception was modified concurrently", op), "receptionID", reception.ID)
+			continue
+		}
+		if err != nil {
+			s.log.Error(fmt.Sprintf("%s: failed to close reception", op), "receptionID", reception.ID, sl.Err(err))
+			continue
+		}
+
+		s.log.Info(fmt.Sprintf("%s: stale reception closed", op), "receptionID", reception.ID, "pvzID", reception.PVZID)
+		receptionClosed(ctx, s.repo, s.bus, s.log, op, &reception.Reception, newVersion)
+		handled++
+	}
+
+	return handled, nil
+}
+
+func (s *StaleReceptionService) GetPVZReceptionAudit(ctx context.Context, pvzID uuid.UUID) ([]models.ReceptionAuditEntry, error) {
+	const op = "service.stale_reception_service.GetPVZReceptionAudit"
+
+	if _, err := s.repo.CheckPVZ(ctx, pvzID); err != nil {
+		if err == e.ErrNotFound() {
+			s.log.Info(fmt.Sprintf("%s: pvz not found", op), "pvzID", pvzID)
+			return nil, e.ErrNotFound()
+		}
+		s.log.Error(fmt.Sprintf("%s: failed to check PVZ", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to check PVZ: %w", err)
+	}
+
+	entries, err := s.repo.GetPVZReceptionAudit(ctx, pvzID)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to get reception audit", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to get reception audit: %w", err)
+	}
+
+	return entries, nil
+}
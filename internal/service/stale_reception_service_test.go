@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/eventbus"
+	"pvz-service/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStaleReceptionService_ForceCloseReception(t *testing.T) {
+	pvzID := uuid.New()
+	receptionID := uuid.New()
+	manifestID := uuid.New()
+
+	tests := []struct {
+		name        string
+		setupMock   func(m *MockPVZRepository)
+		expectError error
+	}{
+		{
+			name: "Success",
+			setupMock: func(m *MockPVZRepository) {
+				m.On("GetActiveReception", mock.Anything, pvzID).Return(&models.Reception{
+					ID: receptionID, PVZID: pvzID, Status: models.ReceptionStatusInProgress, Version: 5, ManifestID: &manifestID,
+				}, nil)
+				m.On("ForceCloseReception", mock.Anything, receptionID, 5, mock.MatchedBy(func(entry *models.ReceptionAuditEntry) bool {
+					return entry.Action == models.ReceptionAuditForceClosed &&
+						entry.Actor == "moderator@example.com" && entry.Reason == "scanner crashed"
+				})).Return(6, nil)
+				m.On("GetManifest", mock.Anything, manifestID).Return(&models.Manifest{
+					ID: manifestID, Report: &models.DiscrepancyReport{Expected: 3, Received: 2},
+				}, nil)
+			},
+		},
+		{
+			name: "No active reception",
+			setupMock: func(m *MockPVZRepository) {
+				m.On("GetActiveReception", mock.Anything, pvzID).Return(nil, e.ErrNoActiveReception())
+			},
+			expectError: e.ErrNoActiveReception(),
+		},
+		{
+			name: "Reception modified",
+			setupMock: func(m *MockPVZRepository) {
+				m.On("GetActiveReception", mock.Anything, pvzID).Return(&models.Reception{ID: receptionID, PVZID: pvzID, Version: 5}, nil)
+				m.On("ForceCloseReception", mock.Anything, receptionID, 5, mock.Anything).Return(0, e.ErrVersionMismatch())
+			},
+			expectError: e.ErrVersionMismatch(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockPVZRepository)
+			tt.setupMock(mockRepo)
+
+			service := NewStaleReceptionService(mockRepo, eventbus.NewBus(), slog.Default())
+			reception, err := service.ForceCloseReception(context.Background(), pvzID, "moderator@example.com", "scanner crashed")
+			if tt.expectError != nil {
+				assert.Equal(t, tt.expectError, err)
+				assert.Nil(t, reception)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, models.ReceptionStatusClose, reception.Status)
+				assert.Equal(t, 6, reception.Version)
+				assert.Equal(t, 3, reception.Discrepancies.Expected)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStaleReceptionService_CloseStaleReceptions(t *testing.T) {
+	lastActivity := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	stale := func(flagged bool) models.StaleReception {
+		return models.StaleReception{
+			Reception: models.Reception{
+				ID:      uuid.New(),
+				PVZID:   uuid.New(),
+				Status:  models.ReceptionStatusInProgress,
+				Version: 2,
+			},
+			LastActivity: lastActivity,
+			Flagged:      flagged,
+		}
+	}
+	idleSince := mock.MatchedBy(func(since time.Time) bool {
+		return time.Since(since) >= 12*time.Hour && time.Since(since) < 13*time.Hour
+	})
+
+	t.Run("Closes stale receptions and skips modified ones", func(t *testing.T) {
+		closed, modified, failed := stale(false), stale(false), stale(false)
+
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("GetStaleReceptions", mock.Anything, idleSince).
+			Return([]models.StaleReception{closed, modified, failed}, nil)
+		mockRepo.On("ForceCloseReception", mock.Anything, closed.ID, 2, mock.MatchedBy(func(entry *models.ReceptionAuditEntry) bool {
+			return entry.Action == models.ReceptionAuditAutoClosed && entry.Actor == models.ReceptionAuditActorSystem &&
+				entry.Reason == "no activity since 2026-01-01T08:00:00Z"
+		})).Return(3, nil)
+		mockRepo.On("ForceCloseReception", mock.Anything, modified.ID, 2, mock.Anything).Return(0, e.ErrVersionMismatch())
+		mockRepo.On("ForceCloseReception", mock.Anything, failed.ID, 2, mock.Anything).Return(0, errors.New("db error"))
+
+		bus := eventbus.NewBus()
+		events, unsubscribe := bus.Subscribe(closed.PVZID)
+		defer unsubscribe()
+
+		service := NewStaleReceptionService(mockRepo, bus, slog.Default())
+		handled, err := service.CloseStaleReceptions(context.Background(), 12*time.Hour, false)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, handled)
+		mockRepo.AssertNotCalled(t, "InsertReceptionAudit", mock.Anything, mock.Anything)
+
+		select {
+		case event := <-events:
+			assert.Equal(t, models.EventReceptionClosed, event.Type)
+			assert.Equal(t, closed.ID, event.ReceptionID)
+		case <-time.After(time.Second):
+			t.Fatal("reception closed event was not published")
+		}
+	})
+
+	t.Run("Flags stale receptions once", func(t *testing.T) {
+		fresh, flagged := stale(false), stale(true)
+
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("GetStaleReceptions", mock.Anything, idleSince).
+			Return([]models.StaleReception{fresh, flagged}, nil)
+		mockRepo.On("InsertReceptionAudit", mock.Anything, mock.MatchedBy(func(entry *models.ReceptionAuditEntry) bool {
+			return entry.ReceptionID == fresh.ID && entry.PVZID == fresh.PVZID &&
+				entry.Action == models.ReceptionAuditFlaggedStale
+		})).Return(nil).Once()
+
+		service := NewStaleReceptionService(mockRepo, eventbus.NewBus(), slog.Default())
+		handled, err := service.CloseStaleReceptions(context.Background(), 12*time.Hour, true)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, handled)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "ForceCloseReception", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Query error", func(t *testing.T) {
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("GetStaleReceptions", mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+
+		service := NewStaleReceptionService(mockRepo, eventbus.NewBus(), slog.Default())
+		_, err := service.CloseStaleReceptions(context.Background(), 12*time.Hour, false)
+		assert.EqualError(t, err, "failed to get stale receptions: db error")
+	})
+}
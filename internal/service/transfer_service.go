@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/eventbus"
+	"pvz-service/internal/logger/sl"
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TransferService struct {
+	repo repository.PVZRepository
+	bus  *eventbus.Bus
+	log  *slog.Logger
+}
+
+func NewTransferService(repo repository.PVZRepository, bus *eventbus.Bus, log *slog.Logger) *TransferService {
+	return &TransferService{repo: repo, bus: bus, log: log}
+}
+
+type TransferServiceInterface interface {
+	CreateTransfer(ctx context.Context, sourcePVZID, destinationPVZID uuid.UUID, productIDs []uuid.UUID) (*models.Transfer, error)
+	GetTransfer(ctx context.Context, transferID uuid.UUID) (*models.Transfer, error)
+	GetPVZTransfers(ctx context.Context, pvzID uuid.UUID, status models.TransferStatus) ([]models.Transfer, error)
+	DispatchTransfer(ctx context.Context, transferID uuid.UUID) (*models.Transfer, error)
+	ReceiveTransfer(ctx context.Context, transferID uuid.UUID) (*models.Transfer, error)
+	CancelTransfer(ctx context.Context, transferID uuid.UUID) (*models.Transfer, error)
+}
+
+// CreateTransfer assembles stored products of the source PVZ into a transfer
+// to an active destination PVZ. The products stay in the source PVZ until the
+// transfer is dispatched.
+func (s *TransferService) CreateTransfer(ctx context.Context, sourcePVZID, destinationPVZID uuid.UUID, productIDs []uuid.UUID) (*models.Transfer, error) {
+	const op = "service.transfer_service.CreateTransfer"
+
+	if _, err := s.repo.CheckPVZ(ctx, sourcePVZID); err != nil {
+		if err == e.ErrNotFound() {
+			s.log.Info(fmt.Sprintf("%s: source pvz not found", op), "pvzID", sourcePVZID)
+			return nil, e.ErrNotFound()
+		}
+		s.log.Error(fmt.Sprintf("%s: failed to check PVZ", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to check PVZ: %w", err)
+	}
+
+	destination, err := s.repo.GetPVZ(ctx, destinationPVZID)
+	if err == e.ErrNotFound() {
+		s.log.Info(fmt.Sprintf("%s: destination pvz not found", op), "pvzID", destinationPVZID)
+		return nil, e.ErrNotFound()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to get PVZ", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to get PVZ: %w", err)
+	}
+	if destination.Status != models.PVZStatusActive {
+		s.log.Info(fmt.Sprintf("%s: destination pvz is not active", op), "pvzID", destinationPVZID, "status", destination.Status)
+		return nil, e.ErrPVZNotActive()
+	}
+
+	transfer := &models.Transfer{
+		ID:               uuid.New(),
+		SourcePVZID:      sourcePVZID,
+		DestinationPVZID: destinationPVZID,
+		Status:           models.TransferStatusOutbound,
+		ProductIDs:       productIDs,
+		CreatedAt:        time.Now(),
+	}
+
+	err = s.repo.CreateTransfer(ctx, transfer)
+	if err == e.ErrProductNotTransferable() {
+		s.log.Info(fmt.Sprintf("%s: product cannot be transferred", op), "pvzID", sourcePVZID)
+		return nil, e.ErrProductNotTransferable()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to create transfer", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to create transfer: %w", err)
+	}
+
+	return transfer, nil
+}
+
+func (s *TransferService) GetTransfer(ctx context.Context, transferID uuid.UUID) (*models.Transfer, error) {
+	const op = "service.transfer_service.GetTransfer"
+
+	transfer, err := s.repo.GetTransfer(ctx, transferID)
+	if err == e.ErrNotFound() {
+		s.log.Info(fmt.Sprintf("%s: transfer not found", op), "transferID", transferID)
+		return nil, e.ErrNotFound()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to get transfer", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to get transfer: %w", err)
+	}
+
+	return transfer, nil
+}
+
+// GetPVZTransfers returns the transfers from and to a PVZ, newest first. An
+// empty status matches any.
+func (s *TransferService) GetPVZTransfers(ctx context.Context, pvzID uuid.UUID, status models.TransferStatus) ([]models.Transfer, error) {
+	const op = "service.transfer_service.GetPVZTransfers"
+
+	if _, err := s.repo.CheckPVZ(ctx, pvzID); err != nil {
+		if err == e.ErrNotFound() {
+			s.log.Info(fmt.Sprintf("%s: pvz not found", op), "pvzID", pvzID)
+			return nil, e.ErrNotFound()
+		}
+		s.log.Error(fmt.Sprintf("%s: failed to check PVZ", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to check PVZ: %w", err)
+	}
+
+	transfers, err := s.repo.GetPVZTransfers(ctx, pvzID, status)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to get transfers", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to get transfers: %w", err)
+	}
+
+	return transfers, nil
+}
+
+// DispatchTransfer ships an outbound transfer. Its products leave the source
+// PVZ, so the whole transfer fails if any of them is no longer stored there.
+func (s *TransferService) DispatchTransfer(ctx context.Context, transferID uuid.UUID) (*models.Transfer, error) {
+	const op = "service.transfer_service.DispatchTransfer"
+
+	transfer, err := s.GetTransfer(ctx, transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	changes, err := s.repo.DispatchTransfer(ctx, transferID, now)
+	if err == e.ErrInvalidTransferState() || err == e.ErrProductNotTransferable() {
+		s.log.Info(fmt.Sprintf("%s: transfer cannot be dispatched", op), "transferID", transferID, "status", transfer.Status, sl.Err(err))
+		return nil, err
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to dispatch transfer", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to dispatch transfer: %w", err)
+	}
+
+	for _, change := range changes {
+		publish(s.bus, s.log, models.EventProductStatusChanged, change.PVZID, change.ReceptionID, change)
+	}
+
+	transfer.Status = models.TransferStatusInTransit
+	transfer.DispatchedAt = &now
+
+	return transfer, nil
+}
+
+// ReceiveTransfer confirms the arrival of a transfer in the destination PVZ.
+// The products are added to the active reception there and count towards the
+// PVZ capacity.
+func (s *TransferService) ReceiveTransfer(ctx context.Context, transferID uuid.UUID) (*models.Transfer, error) {
+	for attempt := 1; ; attempt++ {
+		transfer, err := s.receiveTransfer(ctx, transferID)
+		if !retryUnconditional(err, nil, attempt) {
+			return transfer, err
+		}
+	}
+}
+
+// receiveTransfer is a single attempt of ReceiveTransfer
+func (s *TransferService) receiveTransfer(ctx context.Context, transferID uuid.UUID) (*models.Transfer, error) {
+	const op = "service.transfer_service.ReceiveTransfer"
+
+	transfer, err := s.GetTransfer(ctx, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.Status != models.TransferStatusInTransit {
+		s.log.Info(fmt.Sprintf("%s: transfer is not in transit", op), "transferID", transferID, "status", transfer.Status)
+		return nil, e.ErrInvalidTransferState()
+	}
+
+	reception, err := s.repo.GetActiveReception(ctx, transfer.DestinationPVZID)
+	if err == e.ErrNoActiveReception() {
+		s.log.Info(fmt.Sprintf("%s: no active reception", op), "pvzID", transfer.DestinationPVZID)
+		return nil, e.ErrNoActiveReception()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to get active reception", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to get active reception: %w", err)
+	}
+
+	now := time.Now()
+	_, changes, err := s.repo.ReceiveTransfer(ctx, transferID, reception.ID, reception.Version, now)
+	if err == e.ErrInvalidTransferState() || err == e.ErrVersionMismatch() ||
+		err == e.ErrNoActiveReception() || err == e.ErrCapacityExceeded() {
+		s.log.Info(fmt.Sprintf("%s: transfer cannot be received", op), "transferID", transferID, sl.Err(err))
+		return nil, err
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to receive transfer", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to receive transfer: %w", err)
+	}
+
+	for _, change := range changes {
+		publish(s.bus, s.log, models.EventProductStatusChanged, change.PVZID, change.ReceptionID, change)
+	}
+
+	transfer.Status = models.TransferStatusReceived
+	transfer.ReceptionID = &reception.ID
+	transfer.ReceivedAt = &now
+
+	return transfer, nil
+}
+
+func (s *TransferService) CancelTransfer(ctx context.Context, transferID uuid.UUID) (*models.Transfer, error) {
+	const op = "service.transfer_service.CancelTransfer"
+
+	transfer, err := s.GetTransfer(ctx, transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.CancelTransfer(ctx, transferID)
+	if err == e.ErrInvalidTransferState() {
+		s.log.Info(fmt.Sprintf("%s: transfer is not outbound", op), "transferID", transferID, "status", transfer.Status)
+		return nil, e.ErrInvalidTransferState()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to cancel transfer", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to cancel transfer: %w", err)
+	}
+
+	transfer.Status = models.TransferStatusCancelled
+
+	return transfer, nil
+}
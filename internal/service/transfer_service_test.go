@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/eventbus"
+	"pvz-service/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTransferService_CreateTransfer(t *testing.T) {
+	sourceID := uuid.New()
+	destinationID := uuid.New()
+	productIDs := []uuid.UUID{uuid.New(), uuid.New()}
+
+	tests := []struct {
+		name        string
+		setupMock   func(m *MockPVZRepository)
+		expectError error
+	}{
+		{
+			name: "Success",
+			setupMock: func(m *MockPVZRepository) {
+				m.On("CheckPVZ", mock.Anything, sourceID).Return(true, nil)
+				m.On("GetPVZ", mock.Anything, destinationID).Return(&models.PVZ{ID: destinationID, Status: models.PVZStatusActive}, nil)
+				m.On("CreateTransfer", mock.Anything, mock.MatchedBy(func(tr *models.Transfer) bool {
+					return tr.SourcePVZID == sourceID && tr.DestinationPVZID == destinationID &&
+						tr.Status == models.TransferStatusOutbound && len(tr.ProductIDs) == 2
+				})).Return(nil)
+			},
+		},
+		{
+			name: "Source not found",
+			setupMock: func(m *MockPVZRepository) {
+				m.On("CheckPVZ", mock.Anything, sourceID).Return(false, e.ErrNotFound())
+			},
+			expectError: e.ErrNotFound(),
+		},
+		{
+			name: "Destination suspended",
+			setupMock: func(m *MockPVZRepository) {
+				m.On("CheckPVZ", mock.Anything, sourceID).Return(true, nil)
+				m.On("GetPVZ", mock.Anything, destinationID).Return(&models.PVZ{ID: destinationID, Status: models.PVZStatusSuspended}, nil)
+			},
+			expectError: e.ErrPVZNotActive(),
+		},
+		{
+			name: "Product not stored",
+			setupMock: func(m *MockPVZRepository) {
+				m.On("CheckPVZ", mock.Anything, sourceID).Return(true, nil)
+				m.On("GetPVZ", mock.Anything, destinationID).Return(&models.PVZ{ID: destinationID, Status: models.PVZStatusActive}, nil)
+				m.On("CreateTransfer", mock.Anything, mock.Anything).Return(e.ErrProductNotTransferable())
+			},
+			expectError: e.ErrProductNotTransferable(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockPVZRepository)
+			tt.setupMock(mockRepo)
+
+			service := NewTransferService(mockRepo, eventbus.NewBus(), slog.Default())
+			transfer, err := service.CreateTransfer(context.Background(), sourceID, destinationID, productIDs)
+			if tt.expectError != nil {
+				assert.Equal(t, tt.expectError, err)
+				assert.Nil(t, transfer)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, models.TransferStatusOutbound, transfer.Status)
+				assert.Equal(t, productIDs, transfer.ProductIDs)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTransferService_DispatchTransfer(t *testing.T) {
+	transferID := uuid.New()
+	sourceID := uuid.New()
+	receptionID := uuid.New()
+	transfer := func() *models.Transfer {
+		return &models.Transfer{ID: transferID, SourcePVZID: sourceID, Status: models.TransferStatusOutbound}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("GetTransfer", mock.Anything, transferID).Return(transfer(), nil)
+		mockRepo.On("DispatchTransfer", mock.Anything, transferID, mock.Anything).Return([]models.ProductStatusChange{
+			{ProductID: uuid.New(), PVZID: sourceID, ReceptionID: receptionID, From: models.ProductStatusStored, To: models.ProductStatusInTransit},
+		}, nil)
+
+		bus := eventbus.NewBus()
+		events, unsubscribe := bus.Subscribe(sourceID)
+		defer unsubscribe()
+
+		result, err := NewTransferService(mockRepo, bus, slog.Default()).DispatchTransfer(context.Background(), transferID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.TransferStatusInTransit, result.Status)
+		assert.NotNil(t, result.DispatchedAt)
+
+		select {
+		case event := <-events:
+			assert.Equal(t, models.EventProductStatusChanged, event.Type)
+		case <-time.After(time.Second):
+			t.Fatal("status change event was not published")
+		}
+	})
+
+	t.Run("Already dispatched", func(t *testing.T) {
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("GetTransfer", mock.Anything, transferID).Return(transfer(), nil)
+		mockRepo.On("DispatchTransfer", mock.Anything, transferID, mock.Anything).Return(nil, e.ErrInvalidTransferState())
+
+		_, err := NewTransferService(mockRepo, eventbus.NewBus(), slog.Default()).DispatchTransfer(context.Background(), transferID)
+		assert.Equal(t, e.ErrInvalidTransferState(), err)
+	})
+
+	t.Run("Transfer not found", func(t *testing.T) {
+		mockRepo := new(MockPVZRepository)
+		mockRepo.On("GetTransfer", mock.Anything, transferID).Return(nil, e.ErrNotFound())
+
+		_, err := NewTransferService(mockRepo, eventbus.NewBus(), slog.Default()).DispatchTransfer(context.Background(), transferID)
+		assert.Equal(t, e.ErrNotFound(), err)
+	})
+}
+
+func TestTransferService_ReceiveTransfer(t *testing.T) {
+	transferID := uuid.New()
+	destinationID := uuid.New()
+	reception := &models.Reception{ID: uuid.New(), PVZID: destinationID, Status: models.ReceptionStatusInProgress, Version: 3}
+	transfer := func(status models.TransferStatus) *models.Transfer {
+		return &models.Transfer{ID: transferID, SourcePVZID: uuid.New(), DestinationPVZID: destinationID, Status: status}
+	}
+
+	tests := []struct {
+		name        string
+		setupMock   func(m *MockPVZRepository)
+		expectError error
+	}{
+		{
+			name: "Success",
+			setupMock: func(m *MockPVZRepository) {
+				m.On("GetTransfer", mock.Anything, transferID).Return(transfer(models.TransferStatusInTransit), nil)
+				m.On("GetActiveReception", mock.Anything, destinationID).Return(reception, nil)
+				m.On("ReceiveTransfer", mock.Anything, transferID, reception.ID, 3, mock.Anything).Return(4, []models.ProductStatusChange{}, nil)
+			},
+		},
+		{
+			name: "Not dispatched",
+			setupMock: func(m *MockPVZRepository) {
+				m.On("GetTransfer", mock.Anything, transferID).Return(transfer(models.TransferStatusOutbound), nil)
+			},
+			expectError: e.ErrInvalidTransferState(),
+		},
+		{
+			name: "No active reception",
+			setupMock: func(m *MockPVZRepository) {
+				m.On("GetTransfer", mock.Anything, transferID).Return(transfer(models.TransferStatusInTransit), nil)
+				m.On("GetActiveReception", mock.Anything, destinationID).Return(nil, e.ErrNoActiveReception())
+			},
+			expectError: e.ErrNoActiveReception(),
+		},
+		{
+			name: "Capacity exceeded",
+			setupMock: func(m *MockPVZRepository) {
+				m.On("GetTransfer", mock.Anything, transferID).Return(transfer(models.TransferStatusInTransit), nil)
+				m.On("GetActiveReception", mock.Anything, destinationID).Return(reception, nil)
+				m.On("ReceiveTransfer", mock.Anything, transferID, reception.ID, 3, mock.Anything).Return(0, nil, e.ErrCapacityExceeded())
+			},
+			expectError: e.ErrCapacityExceeded(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockPVZRepository)
+			tt.setupMock(mockRepo)
+
+			service := NewTransferService(mockRepo, eventbus.NewBus(), slog.Default())
+			result, err := service.ReceiveTransfer(context.Background(), transferID)
+			if tt.expectError != nil {
+				assert.Equal(t, tt.expectError, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, models.TransferStatusReceived, result.Status)
+				assert.Equal(t, reception.ID, *result.ReceptionID)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTransferService_CancelTransfer(t *testing.T) {
+	transferID := uuid.New()
+
+	mockRepo := new(MockPVZRepository)
+	mockRepo.On("GetTransfer", mock.Anything, transferID).
+		Return(&models.Transfer{ID: transferID, Status: models.TransferStatusOutbound}, nil)
+	mockRepo.On("CancelTransfer", mock.Anything, transferID).Return(nil).Once()
+	mockRepo.On("CancelTransfer", mock.Anything, transferID).Return(e.ErrInvalidTransferState()).Once()
+
+	service := NewTransferService(mockRepo, eventbus.NewBus(), slog.Default())
+
+	result, err := service.CancelTransfer(context.Background(), transferID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.TransferStatusCancelled, result.Status)
+
+	_, err = service.CancelTransfer(context.Background(), transferID)
+	assert.Equal(t, e.ErrInvalidTransferState(), err)
+}
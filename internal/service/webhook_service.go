@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/logger/sl"
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookService struct {
+	repo repository.WebhookRepository
+	log  *slog.Logger
+}
+
+func NewWebhookService(repo repository.WebhookRepository, log *slog.Logger) *WebhookService {
+	return &WebhookService{repo: repo, log: log}
+}
+
+type WebhookServiceInterface interface {
+	CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) (*models.WebhookSubscription, error)
+	GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+	GetDeadLetters(ctx context.Context, subscriptionID uuid.UUID) ([]models.WebhookDelivery, error)
+	Redeliver(ctx context.Context, deliveryID uuid.UUID) error
+}
+
+func (s *WebhookService) CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	const op = "service.webhook_service.CreateSubscription"
+
+	if sub.ID == uuid.Nil {
+		sub.ID = uuid.New()
+	}
+	if sub.Secret == "" {
+		secret, err := generateSecret()
+		if err != nil {
+			s.log.Error(fmt.Sprintf("%s: failed to generate secret", op), sl.Err(err))
+			return nil, fmt.Errorf("failed to generate secret: %w", err)
+		}
+		sub.Secret = secret
+	}
+	if sub.EventTypes == nil {
+		sub.EventTypes = []models.EventType{}
+	}
+	sub.CreatedAt = time.Now()
+
+	err := s.repo.InsertSubscription(ctx, sub)
+	if err == e.ErrNotFound() {
+		s.log.Info(fmt.Sprintf("%s: pvz not found", op), "pvzID", sub.PVZID)
+		return nil, e.ErrNotFound()
+	}
+	if err == e.ErrCityNotAllowed() {
+		s.log.Info(fmt.Sprintf("%s: city not allowed", op), "city", sub.CityName)
+		return nil, e.ErrCityNotAllowed()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to create subscription", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to create subscription: %w", err)
+	}
+
+	return sub, nil
+}
+
+func (s *WebhookService) GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	const op = "service.webhook_service.GetSubscriptions"
+
+	subs, err := s.repo.GetSubscriptions(ctx)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to get subscriptions", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to get subscriptions: %w", err)
+	}
+
+	// Secrets are shown only once, on creation
+	for i := range subs {
+		subs[i].Secret = ""
+	}
+
+	return subs, nil
+}
+
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	const op = "service.webhook_service.DeleteSubscription"
+
+	err := s.repo.DeleteSubscription(ctx, id)
+	if err == e.ErrNotFound() {
+		s.log.Info(fmt.Sprintf("%s: subscription not found", op), "id", id)
+		return e.ErrNotFound()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to delete subscription", op), sl.Err(err))
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
+
+	return nil
+}
+
+func (s *WebhookService) GetDeadLetters(ctx context.Context, subscriptionID uuid.UUID) ([]models.WebhookDelivery, error) {
+	const op = "service.webhook_service.GetDeadLetters"
+
+	deliveries, err := s.repo.GetDeadDeliveries(ctx, subscriptionID)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to get dead letters", op), sl.Err(err))
+		return nil, fmt.Errorf("failed to get dead letters: %w", err)
+	}
+
+	if deliveries == nil {
+		deliveries = []models.WebhookDelivery{}
+	}
+
+	return deliveries, nil
+}
+
+func (s *WebhookService) Redeliver(ctx context.Context, deliveryID uuid.UUID) error {
+	const op = "service.webhook_service.Redeliver"
+
+	err := s.repo.RedeliverDelivery(ctx, deliveryID)
+	if err == e.ErrNotFound() {
+		s.log.Info(fmt.Sprintf("%s: dead delivery not found", op), "id", deliveryID)
+		return e.ErrNotFound()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("%s: failed to redeliver", op), sl.Err(err))
+		return fmt.Errorf("failed to redeliver: %w", err)
+	}
+
+	return nil
+}
+
+// Helper function: Generate random signing secret
+func generateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
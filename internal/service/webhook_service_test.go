@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	e "pvz-service/internal/errors"
+	"pvz-service/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) InsertSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	args := m.Called(ctx, sub)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) EnqueueDeliveries(ctx context.Context, event models.Event) (int, error) {
+	args := m.Called(ctx, event)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	args := m.Called(ctx, limit, lease)
+	return args.Get(0).([]models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) MarkDeliverySucceeded(ctx context.Context, id uuid.UUID, statusCode int) error {
+	args := m.Called(ctx, id, statusCode)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) MarkDeliveryFailed(ctx context.Context, id uuid.UUID, statusCode int, lastErr string, nextAttemptAt time.Time, dead bool) error {
+	args := m.Called(ctx, id, statusCode, lastErr, nextAttemptAt, dead)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetDeadDeliveries(ctx context.Context, subscriptionID uuid.UUID) ([]models.WebhookDelivery, error) {
+	args := m.Called(ctx, subscriptionID)
+	return args.Get(0).([]models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) RedeliverDelivery(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestWebhookService_CreateSubscription(t *testing.T) {
+	pvzID := uuid.New()
+
+	tests := []struct {
+		name        string
+		sub         *models.WebhookSubscription
+		mockSetup   func(*MockWebhookRepository)
+		expectError error
+	}{
+		{
+			name: "Success with generated secret",
+			sub:  &models.WebhookSubscription{URL: "http://example.com/hook"},
+			mockSetup: func(m *MockWebhookRepository) {
+				m.On("InsertSubscription", mock.Anything, mock.MatchedBy(func(s *models.WebhookSubscription) bool {
+					return s.ID != uuid.Nil && len(s.Secret) == 64 && s.EventTypes != nil && !s.CreatedAt.IsZero()
+				})).Return(nil)
+			},
+		},
+		{
+			name: "Success with own secret",
+			sub:  &models.WebhookSubscription{URL: "http://example.com/hook", Secret: "my-own-secret-value"},
+			mockSetup: func(m *MockWebhookRepository) {
+				m.On("InsertSubscription", mock.Anything, mock.MatchedBy(func(s *models.WebhookSubscription) bool {
+					return s.Secret == "my-own-secret-value"
+				})).Return(nil)
+			},
+		},
+		{
+			name: "City not allowed",
+			sub:  &models.WebhookSubscription{URL: "http://example.com/hook", CityName: "Unknown"},
+			mockSetup: func(m *MockWebhookRepository) {
+				m.On("InsertSubscription", mock.Anything, mock.Anything).Return(e.ErrCityNotAllowed())
+			},
+			expectError: e.ErrCityNotAllowed(),
+		},
+		{
+			name: "PVZ not found",
+			sub:  &models.WebhookSubscription{URL: "http://example.com/hook", PVZID: &pvzID},
+			mockSetup: func(m *MockWebhookRepository) {
+				m.On("InsertSubscription", mock.Anything, mock.Anything).Return(e.ErrNotFound())
+			},
+			expectError: e.ErrNotFound(),
+		},
+		{
+			name: "Insert error",
+			sub:  &models.WebhookSubscription{URL: "http://example.com/hook"},
+			mockSetup: func(m *MockWebhookRepository) {
+				m.On("InsertSubscription", mock.Anything, mock.Anything).Return(errors.New("insert error"))
+			},
+			expectError: errors.New("failed to create subscription: insert error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockWebhookRepository)
+			tt.mockSetup(mockRepo)
+
+			service := NewWebhookService(mockRepo, slog.Default())
+			result, err := service.CreateSubscription(context.Background(), tt.sub)
+
+			if tt.expectError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectError.Error(), err.Error())
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, result)
+				assert.NotEmpty(t, result.Secret)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookService_GetSubscriptions(t *testing.T) {
+	mockRepo := new(MockWebhookRepository)
+	mockRepo.On("GetSubscriptions", mock.Anything).Return([]models.WebhookSubscription{
+		{ID: uuid.New(), URL: "http://example.com/hook", Secret: "secret-secret-secret"},
+	}, nil)
+
+	service := NewWebhookService(mockRepo, slog.Default())
+	subs, err := service.GetSubscriptions(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, subs, 1)
+	assert.Empty(t, subs[0].Secret)
+}
+
+func TestWebhookService_DeleteSubscription(t *testing.T) {
+	tests := []struct {
+		name        string
+		repoErr     error
+		expectError error
+	}{
+		{name: "Success"},
+		{name: "Not found", repoErr: e.ErrNotFound(), expectError: e.ErrNotFound()},
+		{name: "Delete error", repoErr: errors.New("delete error"), expectError: errors.New("failed to delete subscription: delete error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := uuid.New()
+			mockRepo := new(MockWebhookRepository)
+			mockRepo.On("DeleteSubscription", mock.Anything, id).Return(tt.repoErr)
+
+			service := NewWebhookService(mockRepo, slog.Default())
+			err := service.DeleteSubscription(context.Background(), id)
+
+			if tt.expectError != nil {
+				assert.Equal(t, tt.expectError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWebhookService_GetDeadLetters(t *testing.T) {
+	id := uuid.New()
+	mockRepo := new(MockWebhookRepository)
+	mockRepo.On("GetDeadDeliveries", mock.Anything, id).Return([]models.WebhookDelivery(nil), nil)
+
+	service := NewWebhookService(mockRepo, slog.Default())
+	deliveries, err := service.GetDeadLetters(context.Background(), id)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, deliveries)
+	assert.Empty(t, deliveries)
+}
+
+func TestWebhookService_Redeliver(t *testing.T) {
+	tests := []struct {
+		name        string
+		repoErr     error
+		expectError error
+	}{
+		{name: "Success"},
+		{name: "Not found", repoErr: e.ErrNotFound(), expectError: e.ErrNotFound()},
+		{name: "Update error", repoErr: errors.New("update error"), expectError: errors.New("failed to redeliver: update error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := uuid.New()
+			mockRepo := new(MockWebhookRepository)
+			mockRepo.On("RedeliverDelivery", mock.Anything, id).Return(tt.repoErr)
+
+			service := NewWebhookService(mockRepo, slog.Default())
+			err := service.Redeliver(context.Background(), id)
+
+			if tt.expectError != nil {
+				assert.Equal(t, tt.expectError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
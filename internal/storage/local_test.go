@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	e "pvz-service/internal/errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	storage := NewLocalStorage(dir)
+	ctx := context.Background()
+
+	require.NoError(t, storage.Put(ctx, "products/1/photo.png", []byte("png data"), "image/png"))
+	assert.FileExists(t, filepath.Join(dir, "products", "1", "photo.png"))
+
+	body, contentType, err := storage.Get(ctx, "products/1/photo.png")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(body)
+	body.Close()
+	assert.Equal(t, "png data", string(data))
+	assert.Equal(t, "image/png", contentType)
+
+	require.NoError(t, storage.Delete(ctx, "products/1/photo.png"))
+	_, _, err = storage.Get(ctx, "products/1/photo.png")
+	assert.Equal(t, e.ErrNotFound(), err)
+
+	// Deleting a missing object is not an error
+	assert.NoError(t, storage.Delete(ctx, "products/1/photo.png"))
+
+	entries, _ := os.ReadDir(filepath.Join(dir, "products", "1"))
+	assert.Empty(t, entries)
+}
+
+func TestLocalStorage_RejectsKeysOutsideDir(t *testing.T) {
+	storage := NewLocalStorage(t.TempDir())
+
+	for _, key := range []string{"../escape.jpg", "/etc/passwd", ""} {
+		assert.Error(t, storage.Put(context.Background(), key, []byte("x"), "image/jpeg"), key)
+	}
+}
@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"pvz-service/internal/config"
+)
+
+// Storage keeps binary objects such as product photos under slash-separated
+// keys. Get returns errors.ErrNotFound for a missing key.
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, string, error)
+	Delete(ctx context.Context, key string) error
+}
+
+func CreateStorage(cfg *config.Config, log *slog.Logger) (Storage, error) {
+	const op = "storage.CreateStorage"
+
+	switch cfg.Storage.Type {
+
+	case "local":
+		log.Info("storage is local", slog.String("dir", cfg.Storage.LocalDir))
+		return NewLocalStorage(cfg.Storage.LocalDir), nil
+
+	case "s3":
+		if cfg.Storage.S3.Endpoint == "" || cfg.Storage.S3.Bucket == "" {
+			return nil, fmt.Errorf("%s: endpoint and bucket are required for s3 storage", op)
+		}
+		log.Info("storage is s3", slog.String("endpoint", cfg.Storage.S3.Endpoint), slog.String("bucket", cfg.Storage.S3.Bucket))
+		return NewS3Storage(cfg.Storage.S3)
+
+	default:
+		return nil, fmt.Errorf("%s: unknown storage type (%s)", op, cfg.Storage.Type)
+	}
+}
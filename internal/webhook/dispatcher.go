@@ -0,0 +1,152 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"pvz-service/internal/config"
+	"pvz-service/internal/logger/sl"
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository"
+	"time"
+)
+
+// Dispatcher sends due webhook deliveries, retrying failures with
+// exponential backoff until MaxAttempts is reached.
+type Dispatcher struct {
+	repo   repository.WebhookRepository
+	client *http.Client
+	log    *slog.Logger
+	now    func() time.Time
+
+	interval       time.Duration
+	lease          time.Duration
+	batchSize      int
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+func NewDispatcher(repo repository.WebhookRepository, cfg *config.Config, log *slog.Logger) *Dispatcher {
+	return &Dispatcher{
+		repo:   repo,
+		client: &http.Client{Timeout: cfg.Webhooks.Timeout},
+		log:    log.With(slog.String("component", "webhook/dispatcher")),
+		now:    time.Now,
+
+		interval: cfg.Webhooks.Interval,
+		// A claimed delivery must not become due again while its batch is being
+		// sent
+		lease:          Lease(cfg.Webhooks.BatchSize, cfg.Webhooks.Timeout),
+		batchSize:      cfg.Webhooks.BatchSize,
+		maxAttempts:    cfg.Webhooks.MaxAttempts,
+		initialBackoff: cfg.Webhooks.InitialBackoff,
+		maxBackoff:     cfg.Webhooks.MaxBackoff,
+	}
+}
+
+// Run dispatches due deliveries until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := d.DispatchDue(ctx); err != nil {
+				d.log.Error("failed to dispatch webhooks", sl.Err(err))
+			}
+		}
+	}
+}
+
+// DispatchDue sends one batch of due deliveries and returns how many succeeded
+func (d *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	deliveries, err := d.repo.ClaimDueDeliveries(ctx, d.batchSize, d.lease)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim deliveries: %w", err)
+	}
+
+	delivered := 0
+	for _, delivery := range deliveries {
+		statusCode, sendErr := d.send(ctx, delivery)
+		if sendErr == nil {
+			if err := d.repo.MarkDeliverySucceeded(ctx, delivery.ID, statusCode); err != nil {
+				return delivered, fmt.Errorf("failed to mark delivery %s: %w", delivery.ID, err)
+			}
+			delivered++
+			continue
+		}
+
+		attempt := delivery.Attempts + 1
+		dead := attempt >= d.maxAttempts
+		nextAttemptAt := d.now().Add(Backoff(attempt, d.initialBackoff, d.maxBackoff))
+
+		d.log.Warn("webhook delivery failed",
+			slog.String("delivery_id", delivery.ID.String()),
+			slog.Int("attempt", attempt),
+			slog.Bool("dead", dead),
+			sl.Err(sendErr),
+		)
+
+		if err := d.repo.MarkDeliveryFailed(ctx, delivery.ID, statusCode, sendErr.Error(), nextAttemptAt, dead); err != nil {
+			return delivered, fmt.Errorf("failed to mark delivery %s: %w", delivery.ID, err)
+		}
+	}
+
+	return delivered, nil
+}
+
+// send POSTs the signed payload and returns the response status code, if any
+func (d *Dispatcher) send(ctx context.Context, delivery models.WebhookDelivery) (int, error) {
+	timestamp := d.now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, delivery.EventID.String())
+	req.Header.Set(EventTypeHeader, string(delivery.EventType))
+	req.Header.Set(SignatureTimestampHeader, fmt.Sprint(timestamp))
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Backoff returns the delay before retry number attempt: initial, 2*initial,
+// 4*initial and so on, capped at max.
+func Backoff(attempt int, initial, max time.Duration) time.Duration {
+	delay := initial
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	return delay
+}
+
+// Lease returns how long a batch of batchSize items, sent one after another
+// within timeout each, stays claimed. It covers the whole batch and one more
+// timeout for recording the outcomes, so the tail of a slow batch does not
+// become due again while it is still being sent.
+func Lease(batchSize int, timeout time.Duration) time.Duration {
+	return time.Duration(batchSize+1) * timeout
+}
@@ -0,0 +1,259 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"pvz-service/internal/config"
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) InsertSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	args := m.Called(ctx, sub)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) EnqueueDeliveries(ctx context.Context, event models.Event) (int, error) {
+	args := m.Called(ctx, event)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	args := m.Called(ctx, limit, lease)
+	return args.Get(0).([]models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) MarkDeliverySucceeded(ctx context.Context, id uuid.UUID, statusCode int) error {
+	args := m.Called(ctx, id, statusCode)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) MarkDeliveryFailed(ctx context.Context, id uuid.UUID, statusCode int, lastErr string, nextAttemptAt time.Time, dead bool) error {
+	args := m.Called(ctx, id, statusCode, lastErr, nextAttemptAt, dead)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetDeadDeliveries(ctx context.Context, subscriptionID uuid.UUID) ([]models.WebhookDelivery, error) {
+	args := m.Called(ctx, subscriptionID)
+	return args.Get(0).([]models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) RedeliverDelivery(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// leasingWebhookRepository keeps claimed deliveries leased like the real
+// repositories do, on a clock the test moves
+type leasingWebhookRepository struct {
+	MockWebhookRepository
+	mu      sync.Mutex
+	now     time.Time
+	pending []models.WebhookDelivery
+	due     map[uuid.UUID]time.Time
+}
+
+func (m *leasingWebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var deliveries []models.WebhookDelivery
+	for _, delivery := range m.pending {
+		if len(deliveries) == limit {
+			break
+		}
+		if m.due[delivery.ID].After(m.now) {
+			continue
+		}
+		m.due[delivery.ID] = m.now.Add(lease)
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+var testNow = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestDispatcher(repo repository.WebhookRepository) *Dispatcher {
+	cfg := &config.Config{Webhooks: config.Webhooks{
+		Timeout:        time.Second,
+		Interval:       10 * time.Millisecond,
+		BatchSize:      10,
+		MaxAttempts:    3,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+	}}
+	d := NewDispatcher(repo, cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	d.now = func() time.Time { return testNow }
+	return d
+}
+
+func testDelivery(url string, attempts int) models.WebhookDelivery {
+	return models.WebhookDelivery{
+		ID:        uuid.New(),
+		EventID:   uuid.New(),
+		EventType: models.EventReceptionClosed,
+		Payload:   []byte(`{"status":"close"}`),
+		Attempts:  attempts,
+		URL:       url,
+		Secret:    "secret-secret-secret",
+	}
+}
+
+func TestDispatcher_DispatchDue_Success(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(SignatureTimestampHeader), 10, 64)
+
+		if !Verify("secret-secret-secret", timestamp, body, r.Header.Get(SignatureHeader)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		received <- r
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	repo := new(MockWebhookRepository)
+	delivery := testDelivery(server.URL, 0)
+
+	repo.On("ClaimDueDeliveries", mock.Anything, 10, 11*time.Second).Return([]models.WebhookDelivery{delivery}, nil)
+	repo.On("MarkDeliverySucceeded", mock.Anything, delivery.ID, http.StatusNoContent).Return(nil)
+
+	delivered, err := newTestDispatcher(repo).DispatchDue(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+
+	r := <-received
+	assert.Equal(t, delivery.EventID.String(), r.Header.Get(EventIDHeader))
+	assert.Equal(t, string(models.EventReceptionClosed), r.Header.Get(EventTypeHeader))
+	assert.Equal(t, strconv.FormatInt(testNow.Unix(), 10), r.Header.Get(SignatureTimestampHeader))
+	repo.AssertExpectations(t)
+}
+
+func TestDispatcher_DispatchDue_Failure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		attempts      int
+		nextAttemptAt time.Time
+		dead          bool
+	}{
+		{name: "First failure is retried", attempts: 0, nextAttemptAt: testNow.Add(time.Second), dead: false},
+		{name: "Backoff grows", attempts: 1, nextAttemptAt: testNow.Add(2 * time.Second), dead: false},
+		{name: "Last attempt goes to dead letters", attempts: 2, nextAttemptAt: testNow.Add(4 * time.Second), dead: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockWebhookRepository)
+			delivery := testDelivery(server.URL, tt.attempts)
+
+			repo.On("ClaimDueDeliveries", mock.Anything, 10, 11*time.Second).Return([]models.WebhookDelivery{delivery}, nil)
+			repo.On("MarkDeliveryFailed", mock.Anything, delivery.ID, http.StatusServiceUnavailable,
+				"receiver responded with status 503", tt.nextAttemptAt, tt.dead).Return(nil)
+
+			delivered, err := newTestDispatcher(repo).DispatchDue(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, 0, delivered)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDispatcher_DispatchDue_KeepsBatchLeased(t *testing.T) {
+	repo := &leasingWebhookRepository{now: testNow, due: map[uuid.UUID]time.Time{}}
+
+	var d *Dispatcher
+	var mu sync.Mutex
+	var resent []models.WebhookDelivery
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Every request takes the whole timeout, meanwhile another dispatcher
+		// instance polls the deliveries
+		repo.mu.Lock()
+		repo.now = repo.now.Add(time.Second)
+		repo.mu.Unlock()
+
+		deliveries, _ := repo.ClaimDueDeliveries(context.Background(), 10, d.lease)
+		mu.Lock()
+		resent = append(resent, deliveries...)
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	for range 10 {
+		repo.pending = append(repo.pending, testDelivery(server.URL, 0))
+	}
+	repo.On("MarkDeliverySucceeded", mock.Anything, mock.Anything, http.StatusNoContent).Return(nil)
+
+	d = newTestDispatcher(repo)
+	delivered, err := d.DispatchDue(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 10, delivered)
+	assert.Empty(t, resent)
+}
+
+func TestDispatcher_DispatchDue_ClaimError(t *testing.T) {
+	repo := new(MockWebhookRepository)
+	repo.On("ClaimDueDeliveries", mock.Anything, 10, 11*time.Second).
+		Return([]models.WebhookDelivery(nil), errors.New("db error"))
+
+	_, err := newTestDispatcher(repo).DispatchDue(context.Background())
+
+	assert.Error(t, err)
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{attempt: 1, expected: 5 * time.Second},
+		{attempt: 2, expected: 10 * time.Second},
+		{attempt: 3, expected: 20 * time.Second},
+		{attempt: 12, expected: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.attempt), func(t *testing.T) {
+			assert.Equal(t, tt.expected, Backoff(tt.attempt, 5*time.Second, time.Hour))
+		})
+	}
+}
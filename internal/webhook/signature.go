@@ -0,0 +1,33 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+const (
+	SignatureHeader          = "X-Webhook-Signature"
+	SignatureTimestampHeader = "X-Webhook-Timestamp"
+	EventIDHeader            = "X-Event-Id"
+	EventTypeHeader          = "X-Event-Type"
+
+	signaturePrefix = "sha256="
+)
+
+// Sign returns the value of SignatureHeader for body sent at timestamp (unix
+// seconds). The timestamp is part of the signed message so receivers can
+// reject replays.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature matches body and timestamp for secret
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
@@ -0,0 +1,32 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	signature := Sign("secret-secret-secret", 1700000000, body)
+
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, signature)
+	assert.True(t, Verify("secret-secret-secret", 1700000000, body, signature))
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      []byte
+	}{
+		{name: "Wrong secret", secret: "another-secret-value", timestamp: 1700000000, body: body},
+		{name: "Wrong timestamp", secret: "secret-secret-secret", timestamp: 1700000001, body: body},
+		{name: "Tampered body", secret: "secret-secret-secret", timestamp: 1700000000, body: []byte(`{"id":"2"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.False(t, Verify(tt.secret, tt.timestamp, tt.body, signature))
+		})
+	}
+}
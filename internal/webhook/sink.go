@@ -0,0 +1,22 @@
+package webhook
+
+import (
+	"context"
+	"pvz-service/internal/models"
+	"pvz-service/internal/repository"
+)
+
+// SubscriptionSink fans outbox events out into per-subscription deliveries.
+// It implements outbox.Sink; actual HTTP calls are made by the Dispatcher.
+type SubscriptionSink struct {
+	repo repository.WebhookRepository
+}
+
+func NewSubscriptionSink(repo repository.WebhookRepository) *SubscriptionSink {
+	return &SubscriptionSink{repo: repo}
+}
+
+func (s *SubscriptionSink) Publish(ctx context.Context, event models.Event) error {
+	_, err := s.repo.EnqueueDeliveries(ctx, event)
+	return err
+}